@@ -1,324 +0,0 @@
-// cmd/mysql-mcp-server/connection.go
-package main
-
-import (
-	"context"
-	"database/sql"
-	"fmt"
-	"strings"
-	"sync"
-	"time"
-
-	"github.com/askdba/mysql-mcp-server/internal/config"
-	"github.com/askdba/mysql-mcp-server/internal/sshtunnel"
-	"github.com/askdba/mysql-mcp-server/internal/util"
-	"github.com/go-sql-driver/mysql"
-)
-
-// ServerType represents the type of the database server.
-type ServerType string
-
-const (
-	ServerTypeMySQL   ServerType = "mysql"
-	ServerTypeMariaDB ServerType = "mariadb"
-	ServerTypeUnknown ServerType = "unknown"
-)
-
-// ===== Multi-DSN Connection Manager =====
-
-// ConnectionManager manages multiple MySQL connections.
-type ConnectionManager struct {
-	connections   map[string]*sql.DB
-	configs       map[string]config.ConnectionConfig
-	serverTypes   map[string]ServerType
-	activeConn    string
-	tunnelClosers map[string]func() // per-connection SSH tunnel close functions
-	mu            sync.RWMutex
-}
-
-// NewConnectionManager creates a new connection manager.
-func NewConnectionManager() *ConnectionManager {
-	return &ConnectionManager{
-		connections:   make(map[string]*sql.DB),
-		configs:       make(map[string]config.ConnectionConfig),
-		serverTypes:   make(map[string]ServerType),
-		tunnelClosers: make(map[string]func()),
-	}
-}
-
-// applyDefaultIOTimeouts ensures the MySQL driver read/write deadlines are set when the DSN
-// omits them. Without this, a query can block indefinitely at the TCP layer if context
-// cancellation does not unblock the driver (metadata locks, proxy stalls, etc.).
-func applyDefaultIOTimeouts(dsn string, queryTimeout time.Duration) (string, error) {
-	mysqlCfg, err := mysql.ParseDSN(dsn)
-	if err != nil {
-		return "", err
-	}
-	deadline := queryTimeout
-	if deadline <= 0 {
-		deadline = time.Duration(config.DefaultQueryTimeoutSecs) * time.Second
-	}
-	// Small margin after the logical query timeout for the server to flush result packets.
-	const margin = 2 * time.Second
-	if mysqlCfg.ReadTimeout == 0 {
-		mysqlCfg.ReadTimeout = deadline + margin
-	}
-	if mysqlCfg.WriteTimeout == 0 {
-		mysqlCfg.WriteTimeout = deadline + margin
-	}
-	return mysqlCfg.FormatDSN(), nil
-}
-
-// applyStrictReadOnlyDSN sets session parameter transaction_read_only=ON so each new
-// physical connection executes SET via the driver (see go-sql-driver/mysql handleParams).
-// Any existing DSN value for this param is replaced so strict mode cannot be bypassed.
-func applyStrictReadOnlyDSN(dsn string, strict bool) (string, error) {
-	if !strict {
-		return dsn, nil
-	}
-	mysqlCfg, err := mysql.ParseDSN(dsn)
-	if err != nil {
-		return "", err
-	}
-	if mysqlCfg.Params == nil {
-		mysqlCfg.Params = make(map[string]string)
-	}
-	// Always force ON so a DSN cannot override strict mode with OFF (or other values).
-	mysqlCfg.Params["transaction_read_only"] = "ON"
-	return mysqlCfg.FormatDSN(), nil
-}
-
-// AddConnectionWithPoolConfig adds a new connection with pool configuration.
-// If a connection with the same name already exists, it and its SSH tunnel (if any) are closed and replaced.
-func (cm *ConnectionManager) AddConnectionWithPoolConfig(connCfg config.ConnectionConfig, cfg *config.Config) error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-
-	// If replacing an existing connection, close it and its tunnel first to avoid leaks
-	if existing, ok := cm.connections[connCfg.Name]; ok {
-		existing.Close()
-		delete(cm.connections, connCfg.Name)
-		delete(cm.configs, connCfg.Name)
-		delete(cm.serverTypes, connCfg.Name)
-		if closeTunnel := cm.tunnelClosers[connCfg.Name]; closeTunnel != nil {
-			closeTunnel()
-			delete(cm.tunnelClosers, connCfg.Name)
-		}
-		if cm.activeConn == connCfg.Name {
-			cm.activeConn = ""
-		}
-	}
-
-	dsn := config.ApplySSLToDSN(connCfg.DSN, connCfg.SSL)
-	var err error
-	dsn, err = applyDefaultIOTimeouts(dsn, cfg.QueryTimeout)
-	if err != nil {
-		return fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
-	}
-	dsn, err = applyStrictReadOnlyDSN(dsn, cfg.StrictReadOnly)
-	if err != nil {
-		return fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
-	}
-
-	// If SSH tunnel is configured, start tunnel and rewrite DSN to use local listener
-	if connCfg.SSH != nil && connCfg.SSH.Host != "" && connCfg.SSH.User != "" && connCfg.SSH.KeyPath != "" {
-		mysqlCfg, err := mysql.ParseDSN(dsn)
-		if err != nil {
-			return fmt.Errorf("failed to parse DSN for SSH tunnel %s: %w", connCfg.Name, err)
-		}
-		remoteAddr := mysqlCfg.Addr
-		if remoteAddr == "" {
-			remoteAddr = "127.0.0.1:3306"
-		}
-		strict := config.EffectiveStrictSSHHostKeyChecking(connCfg.SSH)
-		tunnelCfg := sshtunnel.Config{
-			Host:                  connCfg.SSH.Host,
-			User:                  connCfg.SSH.User,
-			KeyPath:               connCfg.SSH.KeyPath,
-			Port:                  connCfg.SSH.Port,
-			InsecureIgnoreHostKey: !strict,
-			KnownHostsPath:        connCfg.SSH.KnownHostsPath,
-			HostKeyFingerprint:    connCfg.SSH.HostKeyFingerprint,
-		}
-		localAddr, closeTunnel, err := sshtunnel.Tunnel(tunnelCfg, remoteAddr)
-		if err != nil {
-			return fmt.Errorf("failed to start SSH tunnel for %s: %w", connCfg.Name, err)
-		}
-		cm.tunnelClosers[connCfg.Name] = closeTunnel
-		mysqlCfg.Addr = localAddr
-		dsn = mysqlCfg.FormatDSN()
-	}
-
-	conn, err := sql.Open("mysql", dsn)
-	if err != nil {
-		if closer := cm.tunnelClosers[connCfg.Name]; closer != nil {
-			closer()
-			delete(cm.tunnelClosers, connCfg.Name)
-		}
-		return fmt.Errorf("failed to open connection %s: %w", connCfg.Name, err)
-	}
-
-	// Apply pool settings with sensible defaults (defensive against zero values)
-	maxOpen := cfg.MaxOpenConns
-	if maxOpen <= 0 {
-		maxOpen = config.DefaultMaxOpenConns
-	}
-	maxIdle := cfg.MaxIdleConns
-	if maxIdle <= 0 {
-		maxIdle = config.DefaultMaxIdleConns
-	}
-	lifetime := cfg.ConnMaxLifetime
-	if lifetime <= 0 {
-		lifetime = time.Duration(config.DefaultConnMaxLifetimeMins) * time.Minute
-	}
-	idleTime := cfg.ConnMaxIdleTime
-	if idleTime <= 0 {
-		idleTime = time.Duration(config.DefaultConnMaxIdleTimeMins) * time.Minute
-	}
-	pingTimeout := cfg.PingTimeout
-	if pingTimeout <= 0 {
-		pingTimeout = time.Duration(config.DefaultPingTimeoutSecs) * time.Second
-	}
-
-	conn.SetMaxOpenConns(maxOpen)
-	conn.SetMaxIdleConns(maxIdle)
-	conn.SetConnMaxLifetime(lifetime)
-	conn.SetConnMaxIdleTime(idleTime)
-
-	// Test connection with configurable timeout
-	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
-	defer cancel()
-	if err := conn.PingContext(ctx); err != nil {
-		conn.Close()
-		if closer := cm.tunnelClosers[connCfg.Name]; closer != nil {
-			closer()
-			delete(cm.tunnelClosers, connCfg.Name)
-		}
-		return fmt.Errorf("failed to ping connection %s: %w", connCfg.Name, err)
-	}
-
-	cm.connections[connCfg.Name] = conn
-	cm.configs[connCfg.Name] = connCfg
-
-	// Detect server type with a dedicated context to avoid sharing timeout with PingContext
-	ctxDetect, cancelDetect := context.WithTimeout(context.Background(), pingTimeout)
-	defer cancelDetect()
-	cm.serverTypes[connCfg.Name] = cm.detectServerType(ctxDetect, conn)
-
-	// Set as active if it's the first connection
-	if cm.activeConn == "" {
-		cm.activeConn = connCfg.Name
-	}
-
-	return nil
-}
-
-// GetActive returns the active database connection and its name.
-func (cm *ConnectionManager) GetActive() (*sql.DB, string) {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.connections[cm.activeConn], cm.activeConn
-}
-
-// SetActive sets the active connection by name.
-func (cm *ConnectionManager) SetActive(name string) error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-
-	if _, exists := cm.connections[name]; !exists {
-		return fmt.Errorf("connection '%s' not found", name)
-	}
-	cm.activeConn = name
-	return nil
-}
-
-// List returns a list of all connection configurations with masked DSNs.
-func (cm *ConnectionManager) List() []config.ConnectionConfig {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-
-	var list []config.ConnectionConfig
-	for _, cfg := range cm.configs {
-		// Mask DSN for security
-		maskedCfg := cfg
-		maskedCfg.DSN = util.MaskDSN(cfg.DSN)
-		list = append(list, maskedCfg)
-	}
-	return list
-}
-
-// GetActiveDB returns the active database connection.
-func (cm *ConnectionManager) GetActiveDB() *sql.DB {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	return cm.connections[cm.activeConn]
-}
-
-// Close closes all connections and SSH tunnels managed by the manager.
-func (cm *ConnectionManager) Close() {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-	for _, conn := range cm.connections {
-		conn.Close()
-	}
-	for _, closeFn := range cm.tunnelClosers {
-		closeFn()
-	}
-	cm.tunnelClosers = make(map[string]func())
-}
-
-// getDB returns the active database connection in a thread-safe manner.
-// All database access should go through this function to ensure proper
-// connection management and avoid data races when connections are switched.
-func getDB() *sql.DB {
-	if connManager == nil {
-		panic("getDB called before connManager initialized")
-	}
-	return connManager.GetActiveDB()
-}
-
-// GetServerType returns the server type of the active connection.
-func (cm *ConnectionManager) GetServerType() ServerType {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-	if st, exists := cm.serverTypes[cm.activeConn]; exists {
-		return st
-	}
-	return ServerTypeUnknown
-}
-
-// detectServerType queries the server to determine if it's MySQL or MariaDB.
-func (cm *ConnectionManager) detectServerType(ctx context.Context, db *sql.DB) ServerType {
-	var version, versionComment string
-	// Try VERSION() and @@version_comment first
-	err := db.QueryRowContext(ctx, "SELECT VERSION(), @@version_comment").Scan(&version, &versionComment)
-	if err != nil {
-		// Fallback to just VERSION() if the combined query fails
-		err = db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
-		if err != nil {
-			return ServerTypeUnknown
-		}
-	}
-
-	version = strings.TrimSpace(strings.ToLower(version))
-	versionComment = strings.TrimSpace(strings.ToLower(versionComment))
-
-	// If we got nothing back, we can't reliably identify the server
-	if version == "" && versionComment == "" {
-		return ServerTypeUnknown
-	}
-
-	if strings.Contains(version, "mariadb") || strings.Contains(versionComment, "mariadb") {
-		return ServerTypeMariaDB
-	}
-
-	return ServerTypeMySQL
-}
-
-// getServerType is a helper to get the active server type.
-func getServerType() ServerType {
-	if connManager == nil {
-		return ServerTypeUnknown
-	}
-	return connManager.GetServerType()
-}
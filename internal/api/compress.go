@@ -0,0 +1,239 @@
+// internal/api/compress.go
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content codings WithCompression can apply, in order of preference when a
+// client accepts several with the same weight.
+const (
+	EncodingZstd = "zstd"
+	EncodingGzip = "gzip"
+)
+
+var supportedEncodings = []string{EncodingZstd, EncodingGzip}
+
+// encoder is the part of gzip.Writer and zstd.Encoder the compressing
+// writer uses.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var encoderPools = map[string]*sync.Pool{
+	EncodingGzip: {New: func() interface{} { return gzip.NewWriter(io.Discard) }},
+	EncodingZstd: {New: func() interface{} {
+		// Only the options checked by NewWriter can fail, and these are valid.
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		return enc
+	}},
+}
+
+// WithCompression returns middleware that compresses response bodies with
+// zstd or gzip, whichever the request's Accept-Encoding prefers. Bodies
+// shorter than minSize bytes are sent as they are, since compressing them
+// costs more than it saves; a handler that flushes is treated as streaming
+// and compressed from the first flush on. Responses the handler encoded
+// itself, HEAD requests and bodiless statuses pass through untouched.
+func WithCompression(minSize int) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := NegotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, status: http.StatusOK}
+			defer cw.close()
+			next(cw, r)
+		}
+	}
+}
+
+// NegotiateEncoding returns the supported content coding an Accept-Encoding
+// header value ranks highest, or "" when it accepts none of them. Weights
+// follow RFC 9110: a coding absent from the header takes the weight of "*",
+// and q=0 rules a coding out.
+func NegotiateEncoding(header string) string {
+	if strings.TrimSpace(header) == "" {
+		return ""
+	}
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "q") {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if name == "x-gzip" {
+			name = EncodingGzip
+		}
+		weights[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, enc := range supportedEncodings {
+		q, ok := weights[enc]
+		if !ok {
+			q = weights["*"]
+		}
+		if q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+// compressWriter holds the status and the first minSize bytes of a response
+// back until it knows whether the body is worth compressing.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool // the handler called WriteHeader or Write
+	started     bool // headers went out; enc is set when compressing
+	buf         []byte
+	enc         encoder
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	if code >= 100 && code < 200 {
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.status = code
+	cw.wroteHeader = true
+	if !bodyAllowed(code) {
+		cw.start(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.started {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= cw.minSize {
+		if err := cw.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends what the handler wrote so far, compressing from here on when
+// the response may still carry a body.
+func (cw *compressWriter) Flush() {
+	_ = cw.FlushError()
+}
+
+// FlushError is Flush for http.ResponseController, reporting write errors.
+func (cw *compressWriter) FlushError() error {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.started {
+		if err := cw.start(bodyAllowed(cw.status)); err != nil {
+			return err
+		}
+	}
+	if cw.enc != nil {
+		if err := cw.enc.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// start sends the headers, switching to compression when compress is set and
+// the handler did not choose a Content-Encoding itself, then writes the
+// buffered bytes.
+func (cw *compressWriter) start(compress bool) error {
+	cw.started = true
+	h := cw.Header()
+	if compress && h.Get("Content-Encoding") == "" {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		// The compressed bytes differ from the ones a strong ETag was
+		// computed for; the weak form still revalidates, since
+		// If-None-Match uses weak comparison.
+		if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/"+etag)
+		}
+		cw.enc = encoderPools[cw.encoding].Get().(encoder)
+		cw.enc.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close sends a response that stayed under minSize as it is, or finishes the
+// compressed stream.
+func (cw *compressWriter) close() {
+	if !cw.started {
+		if !cw.wroteHeader {
+			return
+		}
+		_ = cw.start(false)
+	}
+	if cw.enc != nil {
+		_ = cw.enc.Close()
+		cw.enc.Reset(io.Discard)
+		encoderPools[cw.encoding].Put(cw.enc)
+		cw.enc = nil
+	}
+}
+
+// bodyAllowed reports whether a response with status code may have a body.
+func bodyAllowed(code int) bool {
+	return code != http.StatusNoContent && code != http.StatusNotModified
+}
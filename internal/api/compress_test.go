@@ -0,0 +1,192 @@
+// internal/api/compress_test.go
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", EncodingGzip},
+		{"gzip, deflate, br", EncodingGzip},
+		{"gzip, zstd", EncodingZstd},
+		{"zstd;q=0.5, gzip", EncodingGzip},
+		{"GZIP;Q=0.8, zstd;q=0.2", EncodingGzip},
+		{"x-gzip", EncodingGzip},
+		{"*", EncodingZstd},
+		{"*;q=0.5, zstd;q=0", EncodingGzip},
+		{"gzip;q=0", ""},
+		{"br", ""},
+	}
+	for _, tt := range tests {
+		if got := NegotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("NegotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func decodeBody(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case EncodingGzip:
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		r = gz
+	case EncodingZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("zstd reader: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return string(body)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decode %s: %v", encoding, err)
+	}
+	return string(out)
+}
+
+func TestWithCompression(t *testing.T) {
+	large := strings.Repeat(`{"id":1,"name":"row"},`, 200)
+	handler := WithCompression(1024)(func(w http.ResponseWriter, r *http.Request) {
+		body := large
+		if r.URL.Query().Get("small") != "" {
+			body = "ok"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, body)
+	})
+
+	for _, enc := range []string{EncodingGzip, EncodingZstd} {
+		req := httptest.NewRequest("GET", "/api/test", nil)
+		req.Header.Set("Accept-Encoding", enc)
+		w := httptest.NewRecorder()
+		handler(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("%s: expected status 201, got %d", enc, w.Code)
+		}
+		if got := w.Header().Get("Content-Encoding"); got != enc {
+			t.Errorf("expected Content-Encoding %s, got %q", enc, got)
+		}
+		if w.Body.Len() >= len(large) {
+			t.Errorf("%s: body was not compressed (%d bytes)", enc, w.Body.Len())
+		}
+		if got := decodeBody(t, enc, w.Body.Bytes()); got != large {
+			t.Errorf("%s: body did not round-trip", enc)
+		}
+	}
+
+	// Small bodies and clients without Accept-Encoding get the body as is.
+	for _, target := range []string{"/api/test?small=1", "/api/test"} {
+		req := httptest.NewRequest("GET", target, nil)
+		if strings.Contains(target, "small") {
+			req.Header.Set("Accept-Encoding", "gzip")
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Header().Get("Content-Encoding") != "" || w.Code != http.StatusCreated {
+			t.Errorf("%s: expected an uncompressed 201, got %d %q", target, w.Code, w.Header().Get("Content-Encoding"))
+		}
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: missing Vary header", target)
+		}
+	}
+}
+
+func TestWithCompressionPassThrough(t *testing.T) {
+	// A handler that encodes the body itself is left alone.
+	handler := WithCompression(0)(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = io.WriteString(w, "already encoded")
+	})
+	req := httptest.NewRequest("GET", "/api/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Header().Get("Content-Encoding") != "br" || w.Body.String() != "already encoded" {
+		t.Errorf("expected the handler's encoding to be kept, got %q %q", w.Header().Get("Content-Encoding"), w.Body.String())
+	}
+
+	// HEAD requests are not compressed.
+	req = httptest.NewRequest("HEAD", "/api/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	WithCompression(0)(func(w http.ResponseWriter, r *http.Request) {
+		WriteSuccess(w, "ok")
+	})(w, req)
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Error("expected HEAD response to pass through")
+	}
+}
+
+func TestWithCompressionETag(t *testing.T) {
+	data := strings.Repeat("x", 4096)
+	handler := WithCompression(1024)(func(w http.ResponseWriter, r *http.Request) {
+		WriteSuccessETag(w, r, data)
+	})
+
+	req := httptest.NewRequest("GET", "/api/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler(w, req)
+	etag := w.Header().Get("ETag")
+	if w.Header().Get("Content-Encoding") != EncodingGzip || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("expected a gzip response with a weak ETag, got %q %q", w.Header().Get("Content-Encoding"), etag)
+	}
+
+	// The weak ETag still revalidates, and the 304 has no encoded body.
+	req = httptest.NewRequest("GET", "/api/test", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected an empty 304, got %d with %d bytes", w.Code, w.Body.Len())
+	}
+}
+
+func TestWithCompressionFlush(t *testing.T) {
+	handler := WithCompression(1 << 20)(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		_, _ = io.WriteString(w, "line 1\n")
+		if err := rc.Flush(); err != nil {
+			t.Errorf("flush: %v", err)
+		}
+		_, _ = io.WriteString(w, "line 2\n")
+	})
+	req := httptest.NewRequest("POST", "/api/query/stream", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if !w.Flushed {
+		t.Error("expected the flush to reach the client")
+	}
+	if w.Header().Get("Content-Encoding") != EncodingGzip {
+		t.Fatalf("expected a flushed stream to be compressed, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if got := decodeBody(t, EncodingGzip, w.Body.Bytes()); got != "line 1\nline 2\n" {
+		t.Errorf("unexpected body %q", got)
+	}
+}
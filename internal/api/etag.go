@@ -0,0 +1,62 @@
+// internal/api/etag.go
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// WriteSuccessETag writes data like WriteSuccess, with an ETag derived from
+// the response body. When the request's If-None-Match lists that ETag it
+// answers 304 Not Modified without a body, so clients polling an unchanged
+// resource do not transfer it again.
+func WriteSuccessETag(w http.ResponseWriter, r *http.Request, data interface{}) {
+	writeETag(w, r, Response{Success: true, Data: data})
+}
+
+// WriteSuccessPageETag is WriteSuccessPage with the ETag handling of
+// WriteSuccessETag.
+func WriteSuccessPageETag(w http.ResponseWriter, r *http.Request, data interface{}, page Page) {
+	writeETag(w, r, Response{Success: true, Data: data, Page: &page})
+}
+
+func writeETag(w http.ResponseWriter, r *http.Request, resp Response) {
+	body, err := json.Marshal(resp)
+	if err != nil {
+		WriteInternalError(w, "failed to encode response: "+err.Error())
+		return
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	// Caches may keep the response but must revalidate it on every use.
+	w.Header().Set("Cache-Control", "no-cache")
+	setCORSDefaults(w)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// etagMatches reports whether an If-None-Match header value lists etag,
+// using the weak comparison RFC 9110 prescribes for If-None-Match.
+func etagMatches(header, etag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+// internal/api/etag_test.go
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteSuccessETag(t *testing.T) {
+	data := map[string]string{"table": "users"}
+
+	w := httptest.NewRecorder()
+	WriteSuccessETag(w, httptest.NewRequest("GET", "/api/describe", nil), data)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || etag[0] != '"' {
+		t.Fatalf("expected a quoted ETag, got %q", etag)
+	}
+	var resp Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || !resp.Success {
+		t.Fatalf("expected a success body, got %+v (%v)", resp, err)
+	}
+
+	// A matching If-None-Match, also in a list or as a weak tag, gets 304.
+	for _, header := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		req := httptest.NewRequest("GET", "/api/describe", nil)
+		req.Header.Set("If-None-Match", header)
+		w = httptest.NewRecorder()
+		WriteSuccessETag(w, req, data)
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: expected an empty 304, got %d with %q", header, w.Code, w.Body.String())
+		}
+		if w.Header().Get("ETag") != etag {
+			t.Errorf("If-None-Match %s: 304 should repeat the ETag", header)
+		}
+	}
+
+	// Changed data gets a new ETag and a full response.
+	req := httptest.NewRequest("GET", "/api/describe", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	WriteSuccessETag(w, req, map[string]string{"table": "orders"})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("expected a 200 with a new ETag, got %d %s", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestWriteSuccessPageETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteSuccessPageETag(w, httptest.NewRequest("GET", "/api/tables", nil), []string{"a"}, Page{Total: 1, Limit: 10})
+	first := w.Header().Get("ETag")
+
+	w = httptest.NewRecorder()
+	WriteSuccessPageETag(w, httptest.NewRequest("GET", "/api/tables", nil), []string{"a"}, Page{Total: 2, Limit: 10})
+	if w.Header().Get("ETag") == first {
+		t.Error("expected the page to be part of the ETag")
+	}
+}
@@ -17,8 +17,9 @@ type HandlerFunc func(ctx context.Context, r *http.Request) (interface{}, error)
 func WithCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag, X-Quota-Queries-Remaining, X-Quota-Queries-Reset, X-Quota-Rows-Remaining, X-Quota-Rows-Reset")
 
 		if r.Method == "OPTIONS" {
 			WriteJSON(w, http.StatusOK, nil)
@@ -158,6 +159,12 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming handlers can flush through the logging middleware.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // WithLogging returns middleware that logs HTTP requests using the provided logger.
 func WithLogging(logger Logger) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
@@ -268,3 +268,18 @@ func TestResponseWriterCapture(t *testing.T) {
 		t.Errorf("expected status 404, got %d", capturedStatus)
 	}
 }
+
+func TestWithLoggingFlush(t *testing.T) {
+	handler := WithLogging(func(string, string, int, time.Duration) {})(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("flush through logging middleware failed: %v", err)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/test", nil))
+	if !w.Flushed {
+		t.Error("expected the underlying writer to be flushed")
+	}
+}
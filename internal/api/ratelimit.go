@@ -125,10 +125,10 @@ func (rl *RateLimiter) Stats() map[string]interface{} {
 	}
 }
 
-// getClientIP extracts the client IP from the request.
+// ClientIP extracts the client IP from the request.
 // It checks X-Forwarded-For and X-Real-IP headers first (for reverse proxies),
 // then falls back to RemoteAddr.
-func getClientIP(r *http.Request) string {
+func ClientIP(r *http.Request) string {
 	remoteIPStr := strings.TrimSpace(r.RemoteAddr)
 	remoteIP := net.ParseIP(remoteIPStr)
 	if remoteIP == nil {
@@ -183,7 +183,7 @@ func WithRateLimit(rl *RateLimiter) func(http.HandlerFunc) http.HandlerFunc {
 				return
 			}
 
-			ip := getClientIP(r)
+			ip := ClientIP(r)
 			if !rl.Allow(ip) {
 				w.Header().Set("Retry-After", "1")
 				WriteError(w, http.StatusTooManyRequests, "rate limit exceeded")
@@ -80,7 +80,7 @@ func TestRateLimiter_Stats(t *testing.T) {
 	}
 }
 
-func TestGetClientIP(t *testing.T) {
+func TestClientIP(t *testing.T) {
 	tests := []struct {
 		name       string
 		remoteAddr string
@@ -154,9 +154,9 @@ func TestGetClientIP(t *testing.T) {
 				req.Header.Set("X-Real-IP", tt.xRealIP)
 			}
 
-			got := getClientIP(req)
+			got := ClientIP(req)
 			if got != tt.expected {
-				t.Errorf("getClientIP() = %v, want %v", got, tt.expected)
+				t.Errorf("ClientIP() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
@@ -10,29 +10,65 @@ import (
 type Response struct {
 	Success bool        `json:"success"`
 	Data    interface{} `json:"data,omitempty"`
+	Page    *Page       `json:"page,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Page describes the slice of a list endpoint returned in Data.
+type Page struct {
+	Total      int  `json:"total"`
+	Offset     int  `json:"offset"`
+	Limit      int  `json:"limit"`
+	HasMore    bool `json:"has_more"`
+	NextOffset *int `json:"next_offset,omitempty"`
 }
 
 // WriteJSON writes a JSON response with the given status code.
+// CORS headers already set by WithCORS are kept.
 func WriteJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	setCORSDefaults(w)
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
 
+// setCORSDefaults sets the CORS headers of a response that has none.
+func setCORSDefaults(w http.ResponseWriter) {
+	h := w.Header()
+	if h.Get("Access-Control-Allow-Origin") == "" {
+		h.Set("Access-Control-Allow-Origin", "*")
+	}
+	if h.Get("Access-Control-Allow-Methods") == "" {
+		h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	}
+	if h.Get("Access-Control-Allow-Headers") == "" {
+		h.Set("Access-Control-Allow-Headers", "Content-Type")
+	}
+}
+
 // WriteSuccess writes a successful JSON response with status 200.
 func WriteSuccess(w http.ResponseWriter, data interface{}) {
 	WriteJSON(w, http.StatusOK, Response{Success: true, Data: data})
 }
 
+// WriteSuccessPage writes a successful JSON response with status 200 for one
+// page of a list endpoint.
+func WriteSuccessPage(w http.ResponseWriter, data interface{}, page Page) {
+	WriteJSON(w, http.StatusOK, Response{Success: true, Data: data, Page: &page})
+}
+
 // WriteError writes an error JSON response with the given status code.
 func WriteError(w http.ResponseWriter, status int, message string) {
 	WriteJSON(w, status, Response{Success: false, Error: message})
 }
 
+// WriteErrorDetails writes an error JSON response with the given status code
+// and machine-readable details about the error.
+func WriteErrorDetails(w http.ResponseWriter, status int, message string, details interface{}) {
+	WriteJSON(w, status, Response{Success: false, Error: message, Details: details})
+}
+
 // WriteBadRequest writes a 400 Bad Request error response.
 func WriteBadRequest(w http.ResponseWriter, message string) {
 	WriteError(w, http.StatusBadRequest, message)
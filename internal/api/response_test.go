@@ -35,6 +35,24 @@ func TestWriteSuccess(t *testing.T) {
 	}
 }
 
+func TestWriteSuccessPage(t *testing.T) {
+	w := httptest.NewRecorder()
+	next := 20
+
+	WriteSuccessPage(w, []string{"a"}, Page{Total: 45, Offset: 10, Limit: 10, HasMore: true, NextOffset: &next})
+
+	var resp Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Success || resp.Page == nil {
+		t.Fatalf("expected a successful response with a page, got %+v", resp)
+	}
+	if resp.Page.Total != 45 || !resp.Page.HasMore || resp.Page.NextOffset == nil || *resp.Page.NextOffset != 20 {
+		t.Errorf("unexpected page: %+v", resp.Page)
+	}
+}
+
 func TestWriteError(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -121,3 +139,21 @@ func TestCORSHeaders(t *testing.T) {
 		t.Error("expected CORS headers header")
 	}
 }
+
+func TestWriteErrorDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	WriteErrorDetails(w, http.StatusBadRequest, "unknown column", map[string]string{"name": "custmer_id"})
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	var resp Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	details, ok := resp.Details.(map[string]interface{})
+	if resp.Success || resp.Error != "unknown column" || !ok || details["name"] != "custmer_id" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+}
@@ -4,7 +4,9 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -21,10 +23,240 @@ const (
 	DefaultPingTimeoutSecs     = 5
 	DefaultHTTPPort            = 9306
 	DefaultHTTPRequestTimeoutS = 60
-	DefaultRateLimitRPS        = 100 // requests per second
-	DefaultRateLimitBurst      = 200 // burst size
+	DefaultStreamMaxRows       = 100000 // row cap of /api/query/stream
+	DefaultCompressionMinBytes = 1024   // smallest HTTP response body that is compressed
+	DefaultRateLimitRPS        = 100    // requests per second
+	DefaultRateLimitBurst      = 200    // burst size
+	DefaultMetricsHistorySize  = 720    // samples kept by the metrics sampler (1h at 5s)
+	DefaultFreshnessCacheS     = 60     // seconds data_freshness reuses a table's answer
+	DefaultQueryQueueTimeoutS  = 10
+	DefaultCircuitThreshold    = 5       // consecutive connection failures that open a circuit
+	DefaultCircuitCooldownS    = 30      // seconds an open circuit fails fast
+	DefaultMaxResultBytes      = 8 << 20 // 8 MiB of cell data per query result
+	DefaultBinaryOutput        = BinaryOutputHex
+	DefaultIdentifierCase      = IdentifierCasePreserve
+	DefaultProxyCompat         = ProxyCompatAuto
+
+	// Async query jobs (/api/jobs)
+	DefaultJobTimeoutS   = 1800     // run time limit of a job's query
+	DefaultJobRetentionS = 3600     // seconds a finished job's result is kept
+	DefaultJobStoreBytes = 64 << 20 // result bytes kept across all jobs
+
+	// Object storage exports of async query jobs (export_targets)
+	DefaultExportURLExpiryS = 3600   // lifetime of a signed download URL
+	MaxExportURLExpiryS     = 604800 // longest lifetime S3 and GCS accept for signed URLs
+
+	// Schema change watcher (schema_watch)
+	DefaultSchemaWatchS = 60 // seconds between metadata snapshots of each watched database
+)
+
+// Binary output modes for BLOB, BINARY and VARBINARY cells (Config.BinaryOutput).
+const (
+	BinaryOutputHex    = "hex"    // 0x hex preview of the first bytes, with the length when cut
+	BinaryOutputBase64 = "base64" // whole value, base64-encoded
+	BinaryOutputLength = "length" // only the size, e.g. <binary 2048 bytes>
+	BinaryOutputSkip   = "skip"   // binary columns are left out of the result
+	BinaryOutputRaw    = "raw"    // bytes passed through as a string
+)
+
+// ValidBinaryOutput reports whether mode is one of the BinaryOutput* modes.
+func ValidBinaryOutput(mode string) bool {
+	switch mode {
+	case BinaryOutputHex, BinaryOutputBase64, BinaryOutputLength, BinaryOutputSkip, BinaryOutputRaw:
+		return true
+	}
+	return false
+}
+
+// Log levels (Config.LogLevel and Config.ComponentLogLevels).
+const (
+	LogLevelDebug   = "debug"
+	LogLevelInfo    = "info"
+	LogLevelWarn    = "warn"
+	LogLevelError   = "error"
+	DefaultLogLevel = LogLevelInfo
+)
+
+// LogComponents are the components whose log level can be set on their own.
+var LogComponents = []string{"http", "pool", "validator", "audit"}
+
+// ValidLogLevel reports whether level is one of the LogLevel* values.
+func ValidLogLevel(level string) bool {
+	switch level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return true
+	}
+	return false
+}
+
+// CheckLogLevels validates a global log level and per-component levels.
+func CheckLogLevels(level string, components map[string]string) error {
+	if !ValidLogLevel(level) {
+		return fmt.Errorf("log level '%s' must be one of debug, info, warn or error", level)
+	}
+	for name, l := range components {
+		known := false
+		for _, c := range LogComponents {
+			known = known || c == name
+		}
+		if !known {
+			return fmt.Errorf("unknown log component '%s'; components: %s", name, strings.Join(LogComponents, ", "))
+		}
+		if !ValidLogLevel(l) {
+			return fmt.Errorf("log level '%s' of component '%s' must be one of debug, info, warn or error", l, name)
+		}
+	}
+	return nil
+}
+
+// Audit log formats (Config.AuditFormat).
+const (
+	AuditFormatJSON    = "json" // one JSON object per line
+	AuditFormatCEF     = "cef"  // ArcSight Common Event Format
+	AuditFormatLEEF    = "leef" // IBM QRadar Log Event Extended Format
+	DefaultAuditFormat = AuditFormatJSON
 )
 
+// ValidAuditFormat reports whether format is one of the AuditFormat* values.
+func ValidAuditFormat(format string) bool {
+	switch format {
+	case AuditFormatJSON, AuditFormatCEF, AuditFormatLEEF:
+		return true
+	}
+	return false
+}
+
+// Identifier case modes for database and table names passed to tools
+// (Config.IdentifierCase). They only matter on servers with
+// lower_case_table_names=0, where those names are case-sensitive.
+const (
+	IdentifierCasePreserve = "preserve" // pass names as given; hint at the catalog spelling when one is not found
+	IdentifierCaseLower    = "lower"    // fold names to lower case (schemas created with lower_case_table_names=1)
+	IdentifierCaseCatalog  = "catalog"  // use the catalog's spelling when a name matches one case-insensitively
+)
+
+// ValidIdentifierCase reports whether mode is one of the IdentifierCase* modes.
+func ValidIdentifierCase(mode string) bool {
+	switch mode {
+	case IdentifierCasePreserve, IdentifierCaseLower, IdentifierCaseCatalog:
+		return true
+	}
+	return false
+}
+
+// Proxy compatibility modes (Config.ProxyCompat). Behind ProxySQL or Vitess
+// a session change made on one statement may not reach the backend
+// connection of the next, so compatible tools qualify table names instead of
+// running USE and refuse per-call session variables.
+const (
+	ProxyCompatAuto = "auto" // on for connections whose probe found ProxySQL or Vitess
+	ProxyCompatOn   = "on"   // on for every connection
+	ProxyCompatOff  = "off"  // never
+)
+
+// ValidProxyCompat reports whether mode is one of the ProxyCompat* modes.
+func ValidProxyCompat(mode string) bool {
+	switch mode {
+	case ProxyCompatAuto, ProxyCompatOn, ProxyCompatOff:
+		return true
+	}
+	return false
+}
+
+// timeZoneOffsetRe matches a MySQL time zone offset such as +05:30 or -08:00.
+var timeZoneOffsetRe = regexp.MustCompile(`^[+-](\d{1,2}):(\d{2})$`)
+
+// timeZoneNameRe matches a named zone from the server's time zone tables,
+// e.g. UTC or America/New_York.
+var timeZoneNameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9_+-]+)*$`)
+
+// ValidTimeZone reports whether tz is a value MySQL accepts for time_zone:
+// SYSTEM, an offset from -13:59 to +14:00, or a zone name. Named zones also
+// need the server's time zone tables to be loaded.
+func ValidTimeZone(tz string) bool {
+	if m := timeZoneOffsetRe.FindStringSubmatch(tz); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		mins, _ := strconv.Atoi(m[2])
+		if mins > 59 {
+			return false
+		}
+		if tz[0] == '+' {
+			return h < 14 || (h == 14 && mins == 0)
+		}
+		return h < 14
+	}
+	return len(tz) <= 64 && timeZoneNameRe.MatchString(tz)
+}
+
+// Locales of user-facing error messages (Config.Locale).
+const (
+	LocaleEnglish  = "en"
+	LocaleGerman   = "de"
+	LocaleJapanese = "ja"
+	LocaleThai     = "th"
+	DefaultLocale  = LocaleEnglish
+)
+
+// ValidLocale reports whether locale is one of the Locale* values.
+func ValidLocale(locale string) bool {
+	switch locale {
+	case LocaleEnglish, LocaleGerman, LocaleJapanese, LocaleThai:
+		return true
+	}
+	return false
+}
+
+// Replica routing strategies (ConnectionConfig.ReplicaRouting).
+const (
+	ReplicaRoutingRoundRobin = "round_robin" // rotate through the replicas
+	ReplicaRoutingLeastLag   = "least_lag"   // the replica furthest ahead in replication
+	DefaultReplicaRouting    = ReplicaRoutingRoundRobin
+)
+
+// CheckSessionReaper rejects a reap threshold the server's own idle pooled
+// connections could reach: it must exceed the pool idle time and, when
+// keepalive is on, the keepalive interval.
+func CheckSessionReaper(cfg *Config) error {
+	after := cfg.ReapIdleSessionsAfter
+	if after <= 0 {
+		return nil
+	}
+	idle := cfg.ConnMaxIdleTime
+	if idle <= 0 {
+		idle = time.Duration(DefaultConnMaxIdleTimeMins) * time.Minute
+	}
+	if after <= idle || (cfg.KeepaliveInterval > 0 && after <= cfg.KeepaliveInterval) {
+		return fmt.Errorf("MYSQL_MCP_REAP_IDLE_SESSIONS_SECONDS / pool.reap_idle_sessions_seconds (%s) must be longer than the pool idle time (%s) and the keepalive interval", after, idle)
+	}
+	return nil
+}
+
+// ValidReplicaRouting reports whether routing is empty or a ReplicaRouting* value.
+func ValidReplicaRouting(routing string) bool {
+	switch routing {
+	case "", ReplicaRoutingRoundRobin, ReplicaRoutingLeastLag:
+		return true
+	}
+	return false
+}
+
+// CheckReplicas validates the replica settings of a connection.
+func CheckReplicas(c ConnectionConfig) error {
+	for i, dsn := range c.Replicas {
+		if strings.TrimSpace(dsn) == "" {
+			return fmt.Errorf("connection '%s': replica %d has empty DSN", c.Name, i+1)
+		}
+	}
+	if !ValidReplicaRouting(c.ReplicaRouting) {
+		return fmt.Errorf("connection '%s': replica_routing '%s' must be round_robin or least_lag", c.Name, c.ReplicaRouting)
+	}
+	return nil
+}
+
+// DemoDSN is the DSN of the built-in demo connection (MYSQL_MCP_DEMO). It is
+// served by the in-process sample schema instead of a MySQL server.
+const DemoDSN = "demo://sample"
+
 // SSHConfig holds SSH bastion settings for tunneling (optional).
 type SSHConfig struct {
 	Host    string `json:"ssh_host,omitempty"`
@@ -46,8 +278,36 @@ type ConnectionConfig struct {
 	DSN         string     `json:"dsn"`
 	Description string     `json:"description,omitempty"`
 	ReadOnly    bool       `json:"read_only,omitempty"`
-	SSL         string     `json:"ssl,omitempty"` // "true", "false", "skip-verify", or empty (use DSN as-is)
-	SSH         *SSHConfig `json:"ssh,omitempty"` // optional SSH tunnel (bastion)
+	SSL         string     `json:"ssl,omitempty"`         // "true", "false", "skip-verify", or empty (use DSN as-is)
+	SSH         *SSHConfig `json:"ssh,omitempty"`         // optional SSH tunnel (bastion)
+	Environment string     `json:"environment,omitempty"` // e.g. prod, staging, dev; shown to agents by list_connections
+	Tags        []string   `json:"tags,omitempty"`        // free-form labels, matched by ConfirmRequired
+
+	MaxConcurrentQueries int `json:"max_concurrent_queries,omitempty"` // 0 = no per-connection limit
+
+	// ConnectOnDemand defers opening the pool until the connection is first
+	// used; it is not pinged at startup.
+	ConnectOnDemand bool `json:"connect_on_demand,omitempty"`
+
+	// Driver options applied on top of the DSN (see ApplyConnectionOptionsToDSN).
+	Socket         string        `json:"socket,omitempty"`    // unix socket path; replaces the DSN address
+	Compress       bool          `json:"compress,omitempty"`  // zlib protocol compression
+	Collation      string        `json:"collation,omitempty"` // connection collation (SET NAMES ... COLLATE)
+	ConnectTimeout time.Duration `json:"connect_timeout,omitempty"`
+	ReadTimeout    time.Duration `json:"read_timeout,omitempty"`
+	WriteTimeout   time.Duration `json:"write_timeout,omitempty"`
+
+	// Read replicas: a connection with replicas is a replica group whose query
+	// tools read from a replica. Replicas share every other setting of the
+	// connection.
+	Replicas       []string `json:"replicas,omitempty"`        // replica DSNs
+	ReplicaRouting string   `json:"replica_routing,omitempty"` // ReplicaRouting* value (default round_robin)
+	PinReplica     bool     `json:"pin_replica,omitempty"`     // keep an MCP session on the replica of its first read
+	ReplicaOf      string   `json:"replica_of,omitempty"`      // set on replica connections to the group's name
+
+	// RowPolicies maps a table ("table" or "database.table") to a predicate
+	// every query reading it must satisfy, e.g. tenant_id = {session.tenant}.
+	RowPolicies map[string]string `json:"row_policies,omitempty"`
 }
 
 // Config holds all configuration for the MySQL MCP server.
@@ -56,8 +316,27 @@ type Config struct {
 	Connections []ConnectionConfig
 
 	// Query limits
-	MaxRows      int
-	QueryTimeout time.Duration
+	MaxRows         int
+	MaxResultBytes  int    // Cap on cell bytes per result; the crossing row's largest cells are cut (0 = unlimited)
+	BinaryOutput    string // How binary cells are rendered (BinaryOutput* modes)
+	IdentifierCase  string // How database and table names are matched (IdentifierCase* modes)
+	ProxyCompat     string // Avoid session state behind ProxySQL / Vitess (ProxyCompat* modes)
+	TimeZone        string // Session time_zone for query tools ("" = server default)
+	QueryTimeout    time.Duration
+	InjectLimit     bool           // Append LIMIT to SELECTs without one so MySQL stops early (default on)
+	SuggestNames    bool           // Add the closest catalog names to unknown table/column errors of run_query (default on)
+	MaxExecTimeHint bool           // Add a MAX_EXECUTION_TIME hint of QueryTimeout to SELECTs on MySQL (default on)
+	KillOnCancel    bool           // Send KILL QUERY when a run_query call is canceled or times out
+	DatabaseMaxRows map[string]int // Per-database default row cap for run_query; overrides MaxRows
+
+	// Tool calls allowed to run queries at once across all connections (0 = unlimited)
+	MaxConcurrentQueries int
+	QueryQueueDepth      int           // Calls that may wait for a slot when saturated (0 = fail fast)
+	QueryQueueTimeout    time.Duration // Longest wait in the queue before "server busy"
+
+	// Identical run_query calls of one MCP session within DuplicateWindow get
+	// the earlier result back instead of querying MySQL again (0 = off)
+	DuplicateWindow time.Duration
 
 	// Connection pool settings
 	MaxOpenConns    int
@@ -66,14 +345,40 @@ type Config struct {
 	ConnMaxIdleTime time.Duration
 	PingTimeout     time.Duration
 
+	// Reuse prepared statements for fixed metadata queries and prepare them at startup (default on)
+	PreparedStatements bool
+
+	// Per-connection circuit breaker: after CircuitThreshold consecutive
+	// connection failures (0 disables), calls fail fast for CircuitCooldown.
+	CircuitThreshold int
+	CircuitCooldown  time.Duration
+
+	// Pool maintenance (0 disables): ping idle pooled connections every
+	// KeepaliveInterval, and kill this server's sessions left in Sleep for
+	// ReapIdleSessionsAfter (e.g. by a process that exited without closing them).
+	KeepaliveInterval     time.Duration
+	ReapIdleSessionsAfter time.Duration
+
 	// Feature flags
+	DemoMode     bool // Serve the built-in sample schema instead of MySQL (MYSQL_MCP_DEMO)
 	ExtendedMode bool
 	VectorMode   bool
+	VectorWrite  bool // Enable vector_insert and vector_delete (requires VectorMode; MYSQL_MCP_VECTOR_WRITE)
 	HTTPMode     bool
 	MetricsHTTP  bool // Serve /status + /api/metrics/tokens on HTTP while MCP uses stdio (Claude Desktop)
 	JSONLogging  bool
 	TokenCard    bool // Enable live monitoring UI at /status
 
+	// Per-tool or per-group switches (features.tools, MYSQL_MCP_TOOLS), keyed by
+	// tool or group name. A tool's entry wins over its group's, and both win
+	// over ExtendedMode / VectorMode.
+	ToolFlags map[string]bool
+
+	// Logging
+	LogLevel           string            // Global log level (LogLevel* values)
+	ComponentLogLevels map[string]string // Per-component log levels, keyed by LogComponents
+	LogRedactSQL       bool              // Replace literals in logged and audited SQL with ?
+
 	// Token estimation (optional, disabled by default)
 	TokenTracking bool
 	TokenModel    string
@@ -81,6 +386,19 @@ type Config struct {
 	// HTTP settings
 	HTTPPort           int
 	HTTPRequestTimeout time.Duration
+	StreamMaxRows      int    // Row cap of /api/query/stream (0 = unlimited)
+	BookmarksFile      string // JSON file behind /api/bookmarks ("" = bookmarks disabled)
+
+	// Response compression (zstd or gzip, as the client accepts). Bodies
+	// under CompressionMinBytes are sent uncompressed.
+	Compression         bool
+	CompressionMinBytes int
+
+	// Async query jobs (/api/jobs). Finished results are kept for JobRetention
+	// within a JobStoreBytes budget shared by all jobs.
+	JobTimeout    time.Duration
+	JobRetention  time.Duration
+	JobStoreBytes int
 
 	// Rate limiting (HTTP mode only)
 	RateLimitEnabled bool
@@ -89,25 +407,362 @@ type Config struct {
 
 	// Audit logging
 	AuditLogPath string
+	AuditFormat  string // AuditFormat* value
+
+	// Language of user-facing error messages (Locale* value); callers can
+	// override it per request
+	Locale string
 
 	// Transient DB error retries (MCP tools / shared pool)
-	DBRetryMaxRetries  int
-	DBRetryMaxInterval time.Duration
+	DBRetryMaxRetries      int
+	DBRetryInitialInterval time.Duration // first backoff delay (0 = 500ms)
+	DBRetryMaxInterval     time.Duration
 
 	// Masking
 	MaskColumns []string
 
+	// Pseudonymization: values of matching columns are replaced by keyed
+	// hashes, consistent within an MCP session (or across sessions with a key).
+	PseudonymizeColumns []string
+	PseudonymKey        string
+
+	// Columns flagged as PII in run_query results (pii_columns + warning)
+	PIIColumns []string
+
+	// Background status sampling for metrics_history (0 interval = disabled)
+	MetricsSampleInterval time.Duration
+	MetricsHistorySize    int
+
 	// Security / access (optional)
 	AllowedDatabases []string // Empty = all databases allowed (subject to MySQL grants)
 	StrictReadOnly   bool     // SET transaction_read_only=ON on each driver connection (DSN param)
 	ProcessAdmin     bool     // Enable process_list and kill_query (extended tools)
 	ReadAuditTool    bool     // Enable read_audit_log when AuditLogPath is set (extended)
 	SlowQueryTool    bool     // Enable slow_query_log tool (extended)
+	SessionsTool     bool     // Enable read-only list_sessions (sanitized processlist, extended)
+	SaveQueryTool    bool     // Enable save_query (register saved queries at runtime)
+	ConfirmRequired  []string // Environments/tags whose connections need confirm=true for run_query
+
+	// Tenant scopes on shared connections (virtual_connections). VirtualConnection
+	// names the one every call is locked to ("" = none until use_connection picks one).
+	VirtualConnections []VirtualConnection
+	VirtualConnection  string
+
+	// Named, parameterized read-only queries from the config file (saved_queries)
+	SavedQueries []SavedQuery
+
+	// JSON file where check_saved_queries keeps its plan baselines ("" = in memory only)
+	PlanBaselineFile string
+
+	// Multi-query report templates from the config file (reports)
+	Reports []Report
+
+	// Saved queries run periodically in the background (schedules)
+	Schedules []Schedule
+
+	// Rollup tables declared in the config file (summary_tables), reported by
+	// list_summary_tables in addition to the ones found by name
+	SummaryTables []SummaryTable
+
+	// Timestamp column per database.table whose MAX() data_freshness reports
+	// (freshness.tables); other tables fall back to UPDATE_TIME. Answers are
+	// reused for FreshnessCacheTTL.
+	FreshnessColumns  map[string]string
+	FreshnessCacheTTL time.Duration
+
+	// Databases whose tables and columns are snapshotted every
+	// SchemaWatchInterval (schema_watch); a change is listed by schema_changes
+	// and announced to MCP clients and schema_changed webhooks. Empty = off.
+	SchemaWatchDatabases []string
+	SchemaWatchInterval  time.Duration
+
+	// Role-based tool access (rbac). Empty Roles = every registered tool is callable.
+	Roles       map[string][]string // role -> tool names or groups (core, extended, vector, *)
+	APIKeys     map[string]string   // HTTP API key -> role
+	ClientRoles map[string]string   // MCP client name (initialize clientInfo.name) -> role
+	DefaultRole string              // role for callers without a mapping; empty denies them
+
+	// Per-identity usage quotas (quotas). Every caller (API key, MCP client or
+	// remote address) has its own counters; a role listed in QuotaRoles gets
+	// that quota instead of Quota. Counters live in memory and, with
+	// QuotaFile set, in a JSON file that survives restarts.
+	Quota      Quota
+	QuotaRoles map[string]Quota
+	QuotaFile  string
+
+	// Outbound webhooks for operational events (webhooks). A query tool call
+	// taking longer than WebhookSlowQuery sends query_slow (0 = never).
+	Webhooks         []Webhook
+	WebhookSlowQuery time.Duration
+
+	// Object storage buckets async query jobs export to (export_targets)
+	ExportTargets []ExportTarget
+}
+
+// Quota limits one caller's tool calls that reach MySQL per clock hour and
+// the result rows they return per UTC day. Zero is unlimited.
+type Quota struct {
+	MaxQueriesPerHour int
+	MaxRowsPerDay     int
+}
+
+// Enabled reports whether q limits anything.
+func (q Quota) Enabled() bool {
+	return q.MaxQueriesPerHour > 0 || q.MaxRowsPerDay > 0
+}
+
+// SavedQuery is a named, parameterized read-only query exposed through
+// run_saved_query. Parameters appear in SQL as :name.
+type SavedQuery struct {
+	Name        string
+	Description string
+	SQL         string
+	Database    string
+	Params      []SavedQueryParam
+}
+
+// SavedQueryParam describes one SavedQuery parameter.
+type SavedQueryParam struct {
+	Name        string
+	Type        string // string (default), int, number, bool, date; reports also allow identifier
+	Description string
+	Required    bool
+	Default     interface{}
+}
+
+// Report is a multi-query template run by run_report. Each section is either
+// inline SQL or a reference to a saved query.
+type Report struct {
+	Name        string
+	Description string
+	Database    string
+	Variables   []SavedQueryParam
+	Sections    []ReportSection
+}
+
+// ReportSection is one named result of a Report.
+type ReportSection struct {
+	Name       string
+	SQL        string
+	SavedQuery string
+	MaxRows    int
+}
+
+// Schedule runs a saved query on a cron expression; scheduled_results returns
+// its latest result and what changed since the run before.
+type Schedule struct {
+	Name       string
+	SavedQuery string
+	Cron       string // five-field cron expression, @hourly-style macro or @every <duration>
+	Params     map[string]interface{}
+	Database   string
+	Jitter     time.Duration // random delay of up to this much added to each run
+}
+
+// SummaryTable declares a rollup or materialized table and the fact table it
+// summarizes. Table and Source are "database.table"; Source may omit the
+// database when it is the same.
+type SummaryTable struct {
+	Table           string
+	Source          string
+	Grain           string // e.g. hourly, daily, monthly
+	FreshnessColumn string // column whose MAX() tells when the table was last refreshed
+	Description     string
+}
+
+// VirtualConnection scopes a caller to one tenant's schemas on a shared
+// connection.
+type VirtualConnection struct {
+	Name        string
+	Connection  string   // physical connection ("" = the active one)
+	Database    string   // default schema for tools called without a database
+	Schemas     []string // schema patterns; % matches any characters, everything else itself
+	Description string
+
+	// Session holds the values row policy predicates read as {session.name}.
+	Session map[string]string
+}
+
+// Webhook is an endpoint events are POSTed to as JSON.
+type Webhook struct {
+	URL    string
+	Secret string   // key of the X-MCP-Signature HMAC-SHA256 header ("" = unsigned)
+	Events []string // WebhookEvent* names to send (empty = all)
+}
+
+// Webhook events (Webhook.Events).
+const (
+	WebhookEventQueryBlocked        = "query_blocked"        // the SQL validator rejected a query
+	WebhookEventQuerySlow           = "query_slow"           // a query tool call ran longer than WebhookSlowQuery
+	WebhookEventConnectionUnhealthy = "connection_unhealthy" // a circuit opened or keepalive pings failed
+	WebhookEventSchemaChanged       = "schema_changed"       // the schema watcher saw tables or columns change
+)
+
+// ValidWebhookEvent reports whether event is one of the WebhookEvent* values.
+func ValidWebhookEvent(event string) bool {
+	switch event {
+	case WebhookEventQueryBlocked, WebhookEventQuerySlow, WebhookEventConnectionUnhealthy, WebhookEventSchemaChanged:
+		return true
+	}
+	return false
+}
+
+// CheckWebhooks validates the URLs and event names of webhooks.
+func CheckWebhooks(webhooks []Webhook) error {
+	for _, w := range webhooks {
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook url '%s' must be an absolute http or https URL", MaskWebhookURL(w.URL))
+		}
+		for _, e := range w.Events {
+			if !ValidWebhookEvent(e) {
+				return fmt.Errorf("webhook event '%s' must be one of query_blocked, query_slow, connection_unhealthy or schema_changed", e)
+			}
+		}
+	}
+	return nil
+}
+
+// ExportTarget is an object storage bucket the results of /api/jobs exports
+// are uploaded to. Empty credentials are read from the environment when the
+// export runs.
+type ExportTarget struct {
+	Name     string
+	Type     string // ExportTarget* provider
+	Bucket   string // bucket, or container for azure
+	Prefix   string // prepended to object names, e.g. exports/
+	Region   string // s3 only; default us-east-1
+	Endpoint string // base URL replacing the provider's, e.g. for MinIO or Azurite
+
+	AccessKeyID     string // s3, or the HMAC key of gcs
+	SecretAccessKey string
+	Account         string // azure storage account
+	AccountKey      string // azure, base64
+
+	URLExpiry time.Duration // lifetime of the signed download URL
+}
+
+// Object storage providers (ExportTarget.Type). gcs uses its
+// S3-compatible XML API with HMAC keys.
+const (
+	ExportTargetS3    = "s3"
+	ExportTargetGCS   = "gcs"
+	ExportTargetAzure = "azure"
+)
+
+// CheckExportTargets validates the providers, buckets, endpoints and URL
+// lifetimes of targets.
+func CheckExportTargets(targets []ExportTarget) error {
+	for _, t := range targets {
+		switch t.Type {
+		case ExportTargetS3, ExportTargetGCS:
+		case ExportTargetAzure:
+			if t.Account == "" {
+				return fmt.Errorf("export target '%s' needs the azure storage account", t.Name)
+			}
+		default:
+			return fmt.Errorf("export target '%s' type must be one of s3, gcs or azure", t.Name)
+		}
+		if t.Bucket == "" {
+			return fmt.Errorf("export target '%s' needs a bucket", t.Name)
+		}
+		if t.Endpoint != "" {
+			u, err := url.Parse(t.Endpoint)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("export target '%s' endpoint must be an absolute http or https URL", t.Name)
+			}
+		}
+		if t.URLExpiry <= 0 || t.URLExpiry > MaxExportURLExpiryS*time.Second {
+			return fmt.Errorf("export target '%s' url_expiry_seconds must be between 1 and %d", t.Name, MaxExportURLExpiryS)
+		}
+	}
+	return nil
+}
+
+// MaskWebhookURL hides the path and query of a webhook URL, which often carry
+// the token (Slack, PagerDuty), for logs and --print-config.
+func MaskWebhookURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	if u.Path == "" && u.RawQuery == "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return u.Scheme + "://" + u.Host + "/***"
+}
+
+// MatchSchemaPattern reports whether schema matches a virtual connection
+// schema pattern, ignoring case. Only % is a wildcard: unlike in LIKE, _
+// matches itself, so tenant_1_% does not match tenantX1_a.
+func MatchSchemaPattern(pattern, schema string) bool {
+	parts := strings.Split(strings.ToLower(pattern), "%")
+	schema = strings.ToLower(schema)
+	if len(parts) == 1 {
+		return schema == parts[0]
+	}
+	if !strings.HasPrefix(schema, parts[0]) {
+		return false
+	}
+	schema = schema[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(schema, part)
+		if i < 0 {
+			return false
+		}
+		schema = schema[i+len(part):]
+	}
+	return len(schema) >= len(last) && strings.HasSuffix(schema, last)
+}
+
+// CheckRowPolicies validates the table names and predicates of a
+// connection's row_policies; the predicate syntax is checked at startup.
+func CheckRowPolicies(c ConnectionConfig) error {
+	for table, pred := range c.RowPolicies {
+		parts := strings.Split(strings.TrimSpace(table), ".")
+		if len(parts) > 2 || parts[0] == "" || parts[len(parts)-1] == "" {
+			return fmt.Errorf("connection '%s': row policy table '%s' must be table or database.table", c.Name, table)
+		}
+		if strings.TrimSpace(pred) == "" {
+			return fmt.Errorf("connection '%s': row policy on '%s' has an empty predicate", c.Name, table)
+		}
+	}
+	return nil
+}
+
+// schemaPatternRe restricts virtual connection schema patterns to plain
+// schema names plus %.
+var schemaPatternRe = regexp.MustCompile(`^[A-Za-z0-9_$%-]+$`)
+
+func matchAnySchemaPattern(patterns []string, schema string) bool {
+	for _, p := range patterns {
+		if MatchSchemaPattern(p, schema) {
+			return true
+		}
+	}
+	return false
 }
 
 // Load reads configuration from config file (if present) and environment variables.
 // Priority: Environment variables > Config file > Defaults
 func Load() (*Config, error) {
+	cfg, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure we have at least one connection
+	if len(cfg.Connections) == 0 {
+		return nil, fmt.Errorf("no MySQL connections configured. Set MYSQL_DSN, MYSQL_CONNECTIONS, or use a config file")
+	}
+
+	return cfg, nil
+}
+
+// LoadSettings is Load without the check for at least one connection, for
+// programs that supply their own connection pools.
+func LoadSettings() (*Config, error) {
 	var cfg *Config
 
 	// Try to load config file first
@@ -120,26 +775,56 @@ func Load() (*Config, error) {
 	} else {
 		// No config file, start with defaults
 		cfg = &Config{
-			MaxRows:            DefaultMaxRows,
-			QueryTimeout:       time.Duration(DefaultQueryTimeoutSecs) * time.Second,
-			MaxOpenConns:       DefaultMaxOpenConns,
-			MaxIdleConns:       DefaultMaxIdleConns,
-			ConnMaxLifetime:    time.Duration(DefaultConnMaxLifetimeMins) * time.Minute,
-			ConnMaxIdleTime:    time.Duration(DefaultConnMaxIdleTimeMins) * time.Minute,
-			PingTimeout:        time.Duration(DefaultPingTimeoutSecs) * time.Second,
-			HTTPPort:           DefaultHTTPPort,
-			HTTPRequestTimeout: time.Duration(DefaultHTTPRequestTimeoutS) * time.Second,
-			RateLimitRPS:       float64(DefaultRateLimitRPS),
-			RateLimitBurst:     DefaultRateLimitBurst,
-			TokenModel:         "cl100k_base",
-			DBRetryMaxRetries:  3,
-			DBRetryMaxInterval: 10 * time.Second,
+			MaxRows:             DefaultMaxRows,
+			QueryTimeout:        time.Duration(DefaultQueryTimeoutSecs) * time.Second,
+			InjectLimit:         true,
+			SuggestNames:        true,
+			MaxExecTimeHint:     true,
+			PreparedStatements:  true,
+			MaxOpenConns:        DefaultMaxOpenConns,
+			MaxIdleConns:        DefaultMaxIdleConns,
+			ConnMaxLifetime:     time.Duration(DefaultConnMaxLifetimeMins) * time.Minute,
+			ConnMaxIdleTime:     time.Duration(DefaultConnMaxIdleTimeMins) * time.Minute,
+			PingTimeout:         time.Duration(DefaultPingTimeoutSecs) * time.Second,
+			HTTPPort:            DefaultHTTPPort,
+			HTTPRequestTimeout:  time.Duration(DefaultHTTPRequestTimeoutS) * time.Second,
+			StreamMaxRows:       DefaultStreamMaxRows,
+			Compression:         true,
+			CompressionMinBytes: DefaultCompressionMinBytes,
+			JobTimeout:          time.Duration(DefaultJobTimeoutS) * time.Second,
+			JobRetention:        time.Duration(DefaultJobRetentionS) * time.Second,
+			JobStoreBytes:       DefaultJobStoreBytes,
+			RateLimitRPS:        float64(DefaultRateLimitRPS),
+			RateLimitBurst:      DefaultRateLimitBurst,
+			TokenModel:          "cl100k_base",
+			DBRetryMaxRetries:   3,
+			DBRetryMaxInterval:  10 * time.Second,
+			MetricsHistorySize:  DefaultMetricsHistorySize,
+			FreshnessCacheTTL:   time.Duration(DefaultFreshnessCacheS) * time.Second,
+			SchemaWatchInterval: time.Duration(DefaultSchemaWatchS) * time.Second,
+			QueryQueueTimeout:   time.Duration(DefaultQueryQueueTimeoutS) * time.Second,
+			CircuitThreshold:    DefaultCircuitThreshold,
+			CircuitCooldown:     time.Duration(DefaultCircuitCooldownS) * time.Second,
+			MaxResultBytes:      DefaultMaxResultBytes,
+			BinaryOutput:        DefaultBinaryOutput,
+			IdentifierCase:      DefaultIdentifierCase,
+			ProxyCompat:         DefaultProxyCompat,
+			LogLevel:            DefaultLogLevel,
+			AuditFormat:         DefaultAuditFormat,
+			Locale:              DefaultLocale,
 		}
 	}
 
 	// Apply environment variable overrides (env vars take precedence)
 	applyEnvOverrides(cfg)
 
+	if cfg.DemoMode {
+		// Demo mode replaces every configured connection so that a stray
+		// MYSQL_DSN cannot point the demo at a real server.
+		cfg.Connections = []ConnectionConfig{DemoConnection()}
+		return cfg, nil
+	}
+
 	// Load connections from environment (if any defined, they override file config)
 	envConns, err := loadConnections()
 	if err != nil {
@@ -149,11 +834,6 @@ func Load() (*Config, error) {
 		cfg.Connections = envConns
 	}
 
-	// Ensure we have at least one connection
-	if len(cfg.Connections) == 0 {
-		return nil, fmt.Errorf("no MySQL connections configured. Set MYSQL_DSN, MYSQL_CONNECTIONS, or use a config file")
-	}
-
 	return cfg, nil
 }
 
@@ -186,9 +866,27 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("MYSQL_CONN_MAX_IDLE_TIME_MINUTES"); v != "" {
 		cfg.ConnMaxIdleTime = time.Duration(getEnvInt("MYSQL_CONN_MAX_IDLE_TIME_MINUTES", int(cfg.ConnMaxIdleTime.Minutes()))) * time.Minute
 	}
+	if v := os.Getenv("MYSQL_MCP_PREPARED_STATEMENTS"); v != "" {
+		cfg.PreparedStatements = getEnvBool("MYSQL_MCP_PREPARED_STATEMENTS")
+	}
 	if v := os.Getenv("MYSQL_PING_TIMEOUT_SECONDS"); v != "" {
 		cfg.PingTimeout = time.Duration(getEnvInt("MYSQL_PING_TIMEOUT_SECONDS", int(cfg.PingTimeout.Seconds()))) * time.Second
 	}
+	if v := strings.TrimSpace(os.Getenv("MYSQL_MCP_CIRCUIT_THRESHOLD")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err == nil && n >= 0 {
+			cfg.CircuitThreshold = n
+		}
+	}
+	if v := os.Getenv("MYSQL_MCP_KEEPALIVE_SECONDS"); v != "" {
+		cfg.KeepaliveInterval = time.Duration(getEnvInt("MYSQL_MCP_KEEPALIVE_SECONDS", int(cfg.KeepaliveInterval.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_MCP_REAP_IDLE_SESSIONS_SECONDS"); v != "" {
+		cfg.ReapIdleSessionsAfter = time.Duration(getEnvInt("MYSQL_MCP_REAP_IDLE_SESSIONS_SECONDS", int(cfg.ReapIdleSessionsAfter.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_MCP_CIRCUIT_COOLDOWN"); v != "" {
+		cfg.CircuitCooldown = time.Duration(getEnvInt("MYSQL_MCP_CIRCUIT_COOLDOWN", int(cfg.CircuitCooldown.Seconds()))) * time.Second
+	}
 	if v := strings.TrimSpace(os.Getenv("MYSQL_MCP_DB_RETRY_MAX")); v != "" {
 		n, err := strconv.Atoi(v)
 		if err == nil && n >= 0 && n <= 20 {
@@ -201,12 +899,27 @@ func applyEnvOverrides(cfg *Config) {
 			cfg.DBRetryMaxInterval = time.Duration(n) * time.Millisecond
 		}
 	}
+	if v := strings.TrimSpace(os.Getenv("MYSQL_MCP_DB_RETRY_INITIAL_INTERVAL_MS")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err == nil && n > 0 {
+			cfg.DBRetryInitialInterval = time.Duration(n) * time.Millisecond
+		}
+	}
+	if v := os.Getenv("MYSQL_MCP_DEMO"); v != "" {
+		cfg.DemoMode = getEnvBool("MYSQL_MCP_DEMO")
+	}
 	if v := os.Getenv("MYSQL_MCP_EXTENDED"); v != "" {
 		cfg.ExtendedMode = getEnvBool("MYSQL_MCP_EXTENDED")
 	}
 	if v := os.Getenv("MYSQL_MCP_VECTOR"); v != "" {
 		cfg.VectorMode = getEnvBool("MYSQL_MCP_VECTOR")
 	}
+	if v := os.Getenv("MYSQL_MCP_TOOLS"); v != "" {
+		cfg.ToolFlags = ParseToolFlags(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_VECTOR_WRITE"); v != "" {
+		cfg.VectorWrite = getEnvBool("MYSQL_MCP_VECTOR_WRITE")
+	}
 	if v := os.Getenv("MYSQL_MCP_HTTP"); v != "" {
 		cfg.HTTPMode = getEnvBool("MYSQL_MCP_HTTP")
 	}
@@ -216,6 +929,15 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("MYSQL_MCP_JSON_LOGS"); v != "" {
 		cfg.JSONLogging = getEnvBool("MYSQL_MCP_JSON_LOGS")
 	}
+	if v := os.Getenv("MYSQL_MCP_LOG_LEVEL"); v != "" {
+		cfg.LogLevel = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("MYSQL_MCP_LOG_COMPONENTS"); v != "" {
+		cfg.ComponentLogLevels = ParseComponentLogLevels(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_LOG_REDACT_SQL"); v != "" {
+		cfg.LogRedactSQL = getEnvBool("MYSQL_MCP_LOG_REDACT_SQL")
+	}
 	if v := os.Getenv("MYSQL_MCP_TOKEN_TRACKING"); v != "" {
 		cfg.TokenTracking = getEnvBool("MYSQL_MCP_TOKEN_TRACKING")
 	}
@@ -239,6 +961,27 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("MYSQL_HTTP_REQUEST_TIMEOUT_SECONDS"); v != "" {
 		cfg.HTTPRequestTimeout = time.Duration(getEnvInt("MYSQL_HTTP_REQUEST_TIMEOUT_SECONDS", int(cfg.HTTPRequestTimeout.Seconds()))) * time.Second
 	}
+	if v := os.Getenv("MYSQL_HTTP_STREAM_MAX_ROWS"); v != "" {
+		cfg.StreamMaxRows = getEnvInt("MYSQL_HTTP_STREAM_MAX_ROWS", cfg.StreamMaxRows)
+	}
+	if v := os.Getenv("MYSQL_HTTP_COMPRESSION"); v != "" {
+		cfg.Compression = getEnvBool("MYSQL_HTTP_COMPRESSION")
+	}
+	if v := os.Getenv("MYSQL_HTTP_COMPRESSION_MIN_BYTES"); v != "" {
+		cfg.CompressionMinBytes = getEnvInt("MYSQL_HTTP_COMPRESSION_MIN_BYTES", cfg.CompressionMinBytes)
+	}
+	if v := os.Getenv("MYSQL_HTTP_BOOKMARKS_FILE"); v != "" {
+		cfg.BookmarksFile = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("MYSQL_HTTP_JOB_TIMEOUT_SECONDS"); v != "" {
+		cfg.JobTimeout = time.Duration(getEnvInt("MYSQL_HTTP_JOB_TIMEOUT_SECONDS", int(cfg.JobTimeout.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_HTTP_JOB_RETENTION_SECONDS"); v != "" {
+		cfg.JobRetention = time.Duration(getEnvInt("MYSQL_HTTP_JOB_RETENTION_SECONDS", int(cfg.JobRetention.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_HTTP_JOB_STORE_BYTES"); v != "" {
+		cfg.JobStoreBytes = getEnvInt("MYSQL_HTTP_JOB_STORE_BYTES", cfg.JobStoreBytes)
+	}
 	if v := os.Getenv("MYSQL_HTTP_RATE_LIMIT"); v != "" {
 		cfg.RateLimitEnabled = getEnvBool("MYSQL_HTTP_RATE_LIMIT")
 	}
@@ -251,15 +994,106 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("MYSQL_MCP_AUDIT_LOG"); v != "" {
 		cfg.AuditLogPath = strings.TrimSpace(v)
 	}
+	if v := os.Getenv("MYSQL_MCP_AUDIT_FORMAT"); v != "" {
+		cfg.AuditFormat = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("MYSQL_MCP_LOCALE"); v != "" {
+		cfg.Locale = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("MYSQL_MCP_METRICS_SAMPLE_SECONDS"); v != "" {
+		cfg.MetricsSampleInterval = time.Duration(getEnvInt("MYSQL_MCP_METRICS_SAMPLE_SECONDS", int(cfg.MetricsSampleInterval.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_MCP_METRICS_HISTORY_SIZE"); v != "" {
+		cfg.MetricsHistorySize = getEnvInt("MYSQL_MCP_METRICS_HISTORY_SIZE", cfg.MetricsHistorySize)
+	}
 	if v := os.Getenv("MYSQL_MCP_MASK_COLUMNS"); v != "" {
 		cfg.MaskColumns = parseCSVList(v)
 	}
+	if v := os.Getenv("MYSQL_MCP_PSEUDONYMIZE_COLUMNS"); v != "" {
+		cfg.PseudonymizeColumns = parseCSVList(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_PII_COLUMNS"); v != "" {
+		cfg.PIIColumns = parseCSVList(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_PSEUDONYM_KEY"); v != "" {
+		cfg.PseudonymKey = v
+	}
+	if v := os.Getenv("MYSQL_MCP_KILL_ON_CANCEL"); v != "" {
+		cfg.KillOnCancel = getEnvBool("MYSQL_MCP_KILL_ON_CANCEL")
+	}
+	if v := os.Getenv("MYSQL_MCP_INJECT_LIMIT"); v != "" {
+		cfg.InjectLimit = getEnvBool("MYSQL_MCP_INJECT_LIMIT")
+	}
+	if v := os.Getenv("MYSQL_MCP_SUGGEST_IDENTIFIERS"); v != "" {
+		cfg.SuggestNames = getEnvBool("MYSQL_MCP_SUGGEST_IDENTIFIERS")
+	}
+	if v := os.Getenv("MYSQL_MCP_MAX_EXECUTION_TIME_HINT"); v != "" {
+		cfg.MaxExecTimeHint = getEnvBool("MYSQL_MCP_MAX_EXECUTION_TIME_HINT")
+	}
+	if v := os.Getenv("MYSQL_MCP_MAX_RESULT_BYTES"); v != "" {
+		cfg.MaxResultBytes = getEnvInt("MYSQL_MCP_MAX_RESULT_BYTES", cfg.MaxResultBytes)
+	}
+	if v := os.Getenv("MYSQL_MCP_BINARY_OUTPUT"); v != "" {
+		cfg.BinaryOutput = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("MYSQL_MCP_IDENTIFIER_CASE"); v != "" {
+		cfg.IdentifierCase = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("MYSQL_MCP_PROXY_COMPAT"); v != "" {
+		cfg.ProxyCompat = strings.ToLower(strings.TrimSpace(v))
+	}
+	if v := os.Getenv("MYSQL_MCP_TIME_ZONE"); v != "" {
+		cfg.TimeZone = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_PLAN_BASELINE_FILE"); v != "" {
+		cfg.PlanBaselineFile = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_MAX_CONCURRENT_QUERIES"); v != "" {
+		cfg.MaxConcurrentQueries = getEnvInt("MYSQL_MCP_MAX_CONCURRENT_QUERIES", cfg.MaxConcurrentQueries)
+	}
+	if v := os.Getenv("MYSQL_MCP_QUERY_QUEUE_DEPTH"); v != "" {
+		cfg.QueryQueueDepth = getEnvInt("MYSQL_MCP_QUERY_QUEUE_DEPTH", cfg.QueryQueueDepth)
+	}
+	if v := os.Getenv("MYSQL_MCP_QUERY_QUEUE_TIMEOUT"); v != "" {
+		cfg.QueryQueueTimeout = time.Duration(getEnvInt("MYSQL_MCP_QUERY_QUEUE_TIMEOUT", int(cfg.QueryQueueTimeout.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_MCP_DUPLICATE_WINDOW_SECONDS"); v != "" {
+		cfg.DuplicateWindow = time.Duration(max(0, getEnvInt("MYSQL_MCP_DUPLICATE_WINDOW_SECONDS", 0))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_MCP_DATABASE_MAX_ROWS"); v != "" {
+		cfg.DatabaseMaxRows = ParseDatabaseMaxRows(v)
+	}
 	if cfg.HTTPMode {
 		cfg.MetricsHTTP = false // full REST API replaces metrics-only sidecar
 	}
 	if v := os.Getenv("MYSQL_MCP_ALLOWED_DATABASES"); v != "" {
 		cfg.AllowedDatabases = parseCSVList(v)
 	}
+	// MYSQL_MCP_WEBHOOK_URL replaces the webhooks of the config file with one.
+	if v := strings.TrimSpace(os.Getenv("MYSQL_MCP_WEBHOOK_URL")); v != "" {
+		cfg.Webhooks = []Webhook{{
+			URL:    v,
+			Secret: os.Getenv("MYSQL_MCP_WEBHOOK_SECRET"),
+			Events: parseCSVList(strings.ToLower(os.Getenv("MYSQL_MCP_WEBHOOK_EVENTS"))),
+		}}
+	}
+	if v := os.Getenv("MYSQL_MCP_SCHEMA_WATCH"); v != "" {
+		cfg.SchemaWatchDatabases = parseCSVList(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_SCHEMA_WATCH_SECONDS"); v != "" {
+		if n := getEnvInt("MYSQL_MCP_SCHEMA_WATCH_SECONDS", 0); n > 0 {
+			cfg.SchemaWatchInterval = time.Duration(n) * time.Second
+		}
+	}
+	if v := os.Getenv("MYSQL_MCP_WEBHOOK_SLOW_QUERY_SECONDS"); v != "" {
+		cfg.WebhookSlowQuery = time.Duration(getEnvInt("MYSQL_MCP_WEBHOOK_SLOW_QUERY_SECONDS", int(cfg.WebhookSlowQuery.Seconds()))) * time.Second
+	}
+	if v := os.Getenv("MYSQL_MCP_CONFIRM_REQUIRED"); v != "" {
+		cfg.ConfirmRequired = parseCSVList(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_VIRTUAL_CONNECTION"); v != "" {
+		cfg.VirtualConnection = strings.TrimSpace(v)
+	}
 	if v := os.Getenv("MYSQL_MCP_STRICT_READ_ONLY"); v != "" {
 		cfg.StrictReadOnly = getEnvBool("MYSQL_MCP_STRICT_READ_ONLY")
 	}
@@ -272,6 +1106,27 @@ func applyEnvOverrides(cfg *Config) {
 	if v := os.Getenv("MYSQL_MCP_SLOW_QUERY_TOOL"); v != "" {
 		cfg.SlowQueryTool = getEnvBool("MYSQL_MCP_SLOW_QUERY_TOOL")
 	}
+	if v := os.Getenv("MYSQL_MCP_SESSIONS_TOOL"); v != "" {
+		cfg.SessionsTool = getEnvBool("MYSQL_MCP_SESSIONS_TOOL")
+	}
+	if v := os.Getenv("MYSQL_MCP_SAVE_QUERY_TOOL"); v != "" {
+		cfg.SaveQueryTool = getEnvBool("MYSQL_MCP_SAVE_QUERY_TOOL")
+	}
+	if v := os.Getenv("MYSQL_MCP_API_KEYS"); v != "" {
+		cfg.APIKeys = ParseAPIKeys(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_DEFAULT_ROLE"); v != "" {
+		cfg.DefaultRole = strings.TrimSpace(v)
+	}
+	if v := os.Getenv("MYSQL_MCP_QUOTA_QUERIES_PER_HOUR"); v != "" {
+		cfg.Quota.MaxQueriesPerHour = max(0, getEnvInt("MYSQL_MCP_QUOTA_QUERIES_PER_HOUR", cfg.Quota.MaxQueriesPerHour))
+	}
+	if v := os.Getenv("MYSQL_MCP_QUOTA_ROWS_PER_DAY"); v != "" {
+		cfg.Quota.MaxRowsPerDay = max(0, getEnvInt("MYSQL_MCP_QUOTA_ROWS_PER_DAY", cfg.Quota.MaxRowsPerDay))
+	}
+	if v := os.Getenv("MYSQL_MCP_QUOTA_FILE"); v != "" {
+		cfg.QuotaFile = strings.TrimSpace(v)
+	}
 }
 
 // parseCSVList splits comma-separated values, trims space, drops empties.
@@ -287,6 +1142,99 @@ func parseCSVList(s string) []string {
 	return out
 }
 
+// ParseDatabaseMaxRows parses "db=rows" pairs separated by commas (e.g.
+// "analytics=1000,logs=50"). Entries without a positive integer are ignored.
+func ParseDatabaseMaxRows(s string) map[string]int {
+	out := map[string]int{}
+	for _, pair := range parseCSVList(s) {
+		name, rows, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		n, err := strconv.Atoi(strings.TrimSpace(rows))
+		if name == "" || err != nil || n <= 0 {
+			continue
+		}
+		out[name] = n
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// ParseToolFlags parses "name=on|off" pairs separated by commas (e.g.
+// "explain_query=1,list_variables=0"), where name is a tool or tool group.
+// Names are lowercased; pairs without a boolean value are ignored.
+func ParseToolFlags(s string) map[string]bool {
+	out := map[string]bool{}
+	for _, pair := range parseCSVList(s) {
+		name, v, ok := strings.Cut(pair, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || name == "" {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "1", "true", "yes", "on", "y":
+			out[name] = true
+		case "0", "false", "no", "off", "n":
+			out[name] = false
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// ParseComponentLogLevels parses "component=level" pairs separated by commas
+// (e.g. "validator=debug,http=warn"), lowercased. Pairs without a level are
+// skipped; CheckLogLevels reports unknown components and levels.
+func ParseComponentLogLevels(s string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range parseCSVList(s) {
+		name, level, ok := strings.Cut(pair, "=")
+		name, level = strings.ToLower(strings.TrimSpace(name)), strings.ToLower(strings.TrimSpace(level))
+		if !ok || name == "" || level == "" {
+			continue
+		}
+		out[name] = level
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// ParseAPIKeys parses "key=role,key2=role2" (MYSQL_MCP_API_KEYS).
+// Malformed pairs are skipped.
+func ParseAPIKeys(s string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range parseCSVList(s) {
+		key, role, ok := strings.Cut(pair, "=")
+		key, role = strings.TrimSpace(key), strings.TrimSpace(role)
+		if !ok || key == "" || role == "" {
+			continue
+		}
+		out[key] = role
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// DemoConnection returns the read-only connection served in demo mode.
+func DemoConnection() ConnectionConfig {
+	return ConnectionConfig{
+		Name:        "demo",
+		DSN:         DemoDSN,
+		Description: "Built-in sample schema (demo mode)",
+		ReadOnly:    true,
+	}
+}
+
 // EffectiveStrictSSHHostKeyChecking returns whether SSH host keys must be verified.
 // Nil StrictHostKeyChecking means strict (verify).
 func EffectiveStrictSSHHostKeyChecking(s *SSHConfig) bool {
@@ -347,6 +1295,7 @@ func loadConnections() ([]ConnectionConfig, error) {
 			DSN:         dsn,
 			Description: "Default connection",
 			SSL:         globalSSL,
+			Environment: strings.TrimSpace(os.Getenv("MYSQL_DSN_ENV")),
 		}
 		if globalSSH != nil {
 			c.SSH = globalSSH
@@ -359,6 +1308,7 @@ func loadConnections() ([]ConnectionConfig, error) {
 		nameKey := fmt.Sprintf("MYSQL_DSN_%d_NAME", i)
 		descKey := fmt.Sprintf("MYSQL_DSN_%d_DESC", i)
 		sslKey := fmt.Sprintf("MYSQL_DSN_%d_SSL", i)
+		envKey := fmt.Sprintf("MYSQL_DSN_%d_ENV", i)
 
 		dsn := os.Getenv(dsnKey)
 		if dsn == "" {
@@ -381,6 +1331,7 @@ func loadConnections() ([]ConnectionConfig, error) {
 			DSN:         dsn,
 			Description: os.Getenv(descKey),
 			SSL:         ssl,
+			Environment: strings.TrimSpace(os.Getenv(envKey)),
 		}
 		if globalSSH != nil {
 			c.SSH = globalSSH
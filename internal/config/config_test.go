@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,21 +21,63 @@ func clearEnv() {
 		"MYSQL_CONN_MAX_LIFETIME_MINUTES",
 		"MYSQL_CONN_MAX_IDLE_TIME_MINUTES",
 		"MYSQL_PING_TIMEOUT_SECONDS",
+		"MYSQL_MCP_DEMO",
 		"MYSQL_MCP_EXTENDED",
 		"MYSQL_MCP_VECTOR",
+		"MYSQL_MCP_TOOLS",
+		"MYSQL_MCP_VECTOR_WRITE",
 		"MYSQL_MCP_HTTP",
 		"MYSQL_MCP_METRICS_HTTP",
 		"MYSQL_MCP_JSON_LOGS",
+		"MYSQL_MCP_LOG_LEVEL",
+		"MYSQL_MCP_LOG_COMPONENTS",
+		"MYSQL_MCP_LOG_REDACT_SQL",
+		"MYSQL_MCP_AUDIT_FORMAT",
+		"MYSQL_MCP_LOCALE",
+		"MYSQL_MCP_DB_RETRY_INITIAL_INTERVAL_MS",
+		"MYSQL_MCP_CIRCUIT_THRESHOLD",
+		"MYSQL_MCP_CIRCUIT_COOLDOWN",
+		"MYSQL_MCP_KEEPALIVE_SECONDS",
+		"MYSQL_MCP_REAP_IDLE_SESSIONS_SECONDS",
+		"MYSQL_MCP_PSEUDONYMIZE_COLUMNS",
+		"MYSQL_MCP_PSEUDONYM_KEY",
+		"MYSQL_MCP_PII_COLUMNS",
+		"MYSQL_MCP_MAX_EXECUTION_TIME_HINT",
+		"MYSQL_MCP_SUGGEST_IDENTIFIERS",
 		"MYSQL_MCP_TOKEN_TRACKING",
 		"MYSQL_MCP_TOKEN_MODEL",
 		"MYSQL_MCP_TOKEN_CARD",
 		"MYSQL_HTTP_PORT",
+		"MYSQL_HTTP_STREAM_MAX_ROWS",
+		"MYSQL_HTTP_COMPRESSION",
+		"MYSQL_HTTP_COMPRESSION_MIN_BYTES",
+		"MYSQL_HTTP_BOOKMARKS_FILE",
 		"MYSQL_MCP_AUDIT_LOG",
 		"MYSQL_MCP_ALLOWED_DATABASES",
+		"MYSQL_MCP_VIRTUAL_CONNECTION",
 		"MYSQL_MCP_STRICT_READ_ONLY",
 		"MYSQL_MCP_PROCESS_ADMIN",
 		"MYSQL_MCP_READ_AUDIT_TOOL",
 		"MYSQL_MCP_SLOW_QUERY_TOOL",
+		"MYSQL_MCP_SESSIONS_TOOL",
+		"MYSQL_MCP_METRICS_SAMPLE_SECONDS",
+		"MYSQL_MCP_METRICS_HISTORY_SIZE",
+		"MYSQL_MCP_INJECT_LIMIT",
+		"MYSQL_MCP_BINARY_OUTPUT",
+		"MYSQL_MCP_IDENTIFIER_CASE",
+		"MYSQL_MCP_PROXY_COMPAT",
+		"MYSQL_MCP_TIME_ZONE",
+		"MYSQL_MCP_PLAN_BASELINE_FILE",
+		"MYSQL_MCP_WEBHOOK_URL",
+		"MYSQL_MCP_WEBHOOK_SECRET",
+		"MYSQL_MCP_WEBHOOK_EVENTS",
+		"MYSQL_MCP_WEBHOOK_SLOW_QUERY_SECONDS",
+		"MYSQL_MCP_DATABASE_MAX_ROWS",
+		"MYSQL_MCP_PREPARED_STATEMENTS",
+		"MYSQL_MCP_KILL_ON_CANCEL",
+		"MYSQL_MCP_SAVE_QUERY_TOOL",
+		"MYSQL_MCP_API_KEYS",
+		"MYSQL_MCP_DEFAULT_ROLE",
 		"MYSQL_SSL",
 	}
 	for _, v := range envVars {
@@ -120,6 +164,25 @@ func TestLoadWithDefaults(t *testing.T) {
 	}
 }
 
+func TestLoadDemoMode(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+
+	os.Setenv("MYSQL_MCP_DEMO", "1")
+	os.Setenv("MYSQL_DSN", "user:pass@tcp(prod:3306)/db")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if !cfg.DemoMode {
+		t.Fatal("expected DemoMode to be enabled")
+	}
+	if len(cfg.Connections) != 1 || cfg.Connections[0].DSN != DemoDSN || !cfg.Connections[0].ReadOnly {
+		t.Fatalf("expected only the demo connection, got %+v", cfg.Connections)
+	}
+}
+
 func TestLoadOverridesFromEnv(t *testing.T) {
 	clearEnv()
 
@@ -132,6 +195,7 @@ func TestLoadOverridesFromEnv(t *testing.T) {
 	os.Setenv("MYSQL_PING_TIMEOUT_SECONDS", "15")
 	os.Setenv("MYSQL_MCP_EXTENDED", "1")
 	os.Setenv("MYSQL_MCP_VECTOR", "1")
+	os.Setenv("MYSQL_MCP_VECTOR_WRITE", "1")
 	os.Setenv("MYSQL_MCP_HTTP", "1")
 	os.Setenv("MYSQL_MCP_JSON_LOGS", "1")
 	os.Setenv("MYSQL_MCP_TOKEN_TRACKING", "1")
@@ -171,6 +235,9 @@ func TestLoadOverridesFromEnv(t *testing.T) {
 	if !cfg.VectorMode {
 		t.Fatal("expected VectorMode to be true")
 	}
+	if !cfg.VectorWrite {
+		t.Fatal("expected VectorWrite to be true")
+	}
 	if !cfg.HTTPMode {
 		t.Fatal("expected HTTPMode to be true")
 	}
@@ -253,6 +320,19 @@ func TestLoadMissingDSN(t *testing.T) {
 	}
 }
 
+func TestLoadSettingsWithoutDSN(t *testing.T) {
+	clearEnv()
+	os.Setenv("MYSQL_MAX_ROWS", "50")
+
+	cfg, err := LoadSettings()
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if len(cfg.Connections) != 0 || cfg.MaxRows != 50 {
+		t.Errorf("unexpected settings: connections=%d max_rows=%d", len(cfg.Connections), cfg.MaxRows)
+	}
+}
+
 func TestLoadJSONConnections(t *testing.T) {
 	clearEnv()
 
@@ -524,6 +604,31 @@ func TestMySQLQueryTimeoutSecondsTakesPrecedenceOverMs(t *testing.T) {
 	}
 }
 
+func TestMatchSchemaPattern(t *testing.T) {
+	tests := []struct {
+		pattern, schema string
+		want            bool
+	}{
+		{"tenant_1234_%", "tenant_1234_orders", true},
+		{"tenant_1234_%", "TENANT_1234_Orders", true},
+		{"tenant_1234_%", "tenant_1234_", true},
+		{"tenant_1234_%", "tenant_12345_orders", false},
+		{"tenant_1234_%", "tenantX1234_orders", false},
+		{"tenant_1_%", "tenant_1x_a", false},
+		{"%_archive", "shop_archive", true},
+		{"a%b%c", "axxbyyc", true},
+		{"a%b%c", "acb", false},
+		{"a%a", "a", false},
+		{"shared", "shared", true},
+		{"shared", "shared2", false},
+	}
+	for _, tt := range tests {
+		if got := MatchSchemaPattern(tt.pattern, tt.schema); got != tt.want {
+			t.Errorf("MatchSchemaPattern(%q, %q) = %v, want %v", tt.pattern, tt.schema, got, tt.want)
+		}
+	}
+}
+
 func TestSecurityEnvOverrides(t *testing.T) {
 	clearEnv()
 	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
@@ -532,6 +637,8 @@ func TestSecurityEnvOverrides(t *testing.T) {
 	_ = os.Setenv("MYSQL_MCP_PROCESS_ADMIN", "1")
 	_ = os.Setenv("MYSQL_MCP_READ_AUDIT_TOOL", "true")
 	_ = os.Setenv("MYSQL_MCP_SLOW_QUERY_TOOL", "y")
+	_ = os.Setenv("MYSQL_MCP_SESSIONS_TOOL", "on")
+	_ = os.Setenv("MYSQL_MCP_VIRTUAL_CONNECTION", " tenant_1234 ")
 	cfg, err := Load()
 	if err != nil {
 		t.Fatal(err)
@@ -539,11 +646,420 @@ func TestSecurityEnvOverrides(t *testing.T) {
 	if len(cfg.AllowedDatabases) != 3 || cfg.AllowedDatabases[0] != "a" {
 		t.Fatalf("allowed db list: %#v", cfg.AllowedDatabases)
 	}
-	if !cfg.StrictReadOnly || !cfg.ProcessAdmin || !cfg.ReadAuditTool || !cfg.SlowQueryTool {
-		t.Fatalf("flags: strict=%v admin=%v audit=%v slow=%v", cfg.StrictReadOnly, cfg.ProcessAdmin, cfg.ReadAuditTool, cfg.SlowQueryTool)
+	if !cfg.StrictReadOnly || !cfg.ProcessAdmin || !cfg.ReadAuditTool || !cfg.SlowQueryTool || !cfg.SessionsTool {
+		t.Fatalf("flags: strict=%v admin=%v audit=%v slow=%v sessions=%v", cfg.StrictReadOnly, cfg.ProcessAdmin, cfg.ReadAuditTool, cfg.SlowQueryTool, cfg.SessionsTool)
+	}
+	if cfg.VirtualConnection != "tenant_1234" {
+		t.Errorf("virtual connection: %q", cfg.VirtualConnection)
 	}
 	set := AllowedDatabaseSet(cfg.AllowedDatabases)
 	if len(set) != 3 {
 		t.Fatalf("set len %d", len(set))
 	}
 }
+
+func TestMetricsSamplerEnvOverrides(t *testing.T) {
+	clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MetricsSampleInterval != 0 || cfg.MetricsHistorySize != DefaultMetricsHistorySize {
+		t.Fatalf("defaults: interval=%v size=%d", cfg.MetricsSampleInterval, cfg.MetricsHistorySize)
+	}
+
+	_ = os.Setenv("MYSQL_MCP_METRICS_SAMPLE_SECONDS", "15")
+	_ = os.Setenv("MYSQL_MCP_METRICS_HISTORY_SIZE", "100")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MetricsSampleInterval != 15*time.Second {
+		t.Errorf("expected 15s interval, got %v", cfg.MetricsSampleInterval)
+	}
+	if cfg.MetricsHistorySize != 100 {
+		t.Errorf("expected history size 100, got %d", cfg.MetricsHistorySize)
+	}
+}
+
+func TestPreparedStatementsEnvOverride(t *testing.T) {
+	clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.PreparedStatements {
+		t.Fatal("expected PreparedStatements to default to true")
+	}
+
+	_ = os.Setenv("MYSQL_MCP_PREPARED_STATEMENTS", "false")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PreparedStatements {
+		t.Error("expected PreparedStatements=false")
+	}
+}
+
+func TestCompressionEnvOverrides(t *testing.T) {
+	clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Compression || cfg.CompressionMinBytes != DefaultCompressionMinBytes {
+		t.Fatalf("defaults: compression=%v min=%d", cfg.Compression, cfg.CompressionMinBytes)
+	}
+
+	_ = os.Setenv("MYSQL_HTTP_COMPRESSION", "0")
+	_ = os.Setenv("MYSQL_HTTP_COMPRESSION_MIN_BYTES", "8192")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Compression || cfg.CompressionMinBytes != 8192 {
+		t.Errorf("expected compression off with min 8192, got %v %d", cfg.Compression, cfg.CompressionMinBytes)
+	}
+}
+
+func TestCircuitBreakerEnvOverrides(t *testing.T) {
+	clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CircuitThreshold != DefaultCircuitThreshold || cfg.CircuitCooldown != 30*time.Second {
+		t.Fatalf("unexpected defaults: threshold=%d cooldown=%v", cfg.CircuitThreshold, cfg.CircuitCooldown)
+	}
+
+	_ = os.Setenv("MYSQL_MCP_CIRCUIT_THRESHOLD", "0")
+	_ = os.Setenv("MYSQL_MCP_CIRCUIT_COOLDOWN", "5")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CircuitThreshold != 0 || cfg.CircuitCooldown != 5*time.Second {
+		t.Errorf("unexpected overrides: threshold=%d cooldown=%v", cfg.CircuitThreshold, cfg.CircuitCooldown)
+	}
+}
+
+func TestLimitInjectionEnvOverrides(t *testing.T) {
+	clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.InjectLimit || cfg.DatabaseMaxRows != nil || cfg.KillOnCancel || !cfg.MaxExecTimeHint || !cfg.SuggestNames {
+		t.Fatalf("defaults: inject=%v per-db=%v kill=%v hint=%v suggest=%v", cfg.InjectLimit, cfg.DatabaseMaxRows, cfg.KillOnCancel, cfg.MaxExecTimeHint, cfg.SuggestNames)
+	}
+
+	_ = os.Setenv("MYSQL_MCP_INJECT_LIMIT", "0")
+	_ = os.Setenv("MYSQL_MCP_SUGGEST_IDENTIFIERS", "0")
+	_ = os.Setenv("MYSQL_MCP_MAX_EXECUTION_TIME_HINT", "0")
+	_ = os.Setenv("MYSQL_MCP_KILL_ON_CANCEL", "1")
+	_ = os.Setenv("MYSQL_MCP_DATABASE_MAX_ROWS", "analytics=1000, logs = 50,bad,zero=0,neg=-1,nan=x")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.InjectLimit {
+		t.Error("expected InjectLimit=false")
+	}
+	if cfg.SuggestNames {
+		t.Error("expected SuggestNames=false")
+	}
+	if cfg.MaxExecTimeHint {
+		t.Error("expected MaxExecTimeHint=false")
+	}
+	if !cfg.KillOnCancel {
+		t.Error("expected KillOnCancel=true")
+	}
+	if len(cfg.DatabaseMaxRows) != 2 || cfg.DatabaseMaxRows["analytics"] != 1000 || cfg.DatabaseMaxRows["logs"] != 50 {
+		t.Errorf("unexpected DatabaseMaxRows: %v", cfg.DatabaseMaxRows)
+	}
+}
+
+func TestBinaryOutputEnvOverride(t *testing.T) {
+	clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BinaryOutput != BinaryOutputHex {
+		t.Errorf("default BinaryOutput = %q, want hex", cfg.BinaryOutput)
+	}
+
+	_ = os.Setenv("MYSQL_MCP_BINARY_OUTPUT", " Base64 ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BinaryOutput != BinaryOutputBase64 {
+		t.Errorf("BinaryOutput = %q, want base64", cfg.BinaryOutput)
+	}
+	if ValidBinaryOutput("garbage") || !ValidBinaryOutput(BinaryOutputSkip) {
+		t.Error("ValidBinaryOutput accepted or rejected the wrong mode")
+	}
+}
+
+func TestLoadIdentifierCase(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.IdentifierCase != IdentifierCasePreserve {
+		t.Errorf("default IdentifierCase = %q, want preserve", cfg.IdentifierCase)
+	}
+
+	_ = os.Setenv("MYSQL_MCP_IDENTIFIER_CASE", " Catalog ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.IdentifierCase != IdentifierCaseCatalog {
+		t.Errorf("IdentifierCase = %q, want catalog", cfg.IdentifierCase)
+	}
+	if ValidIdentifierCase("upper") || !ValidIdentifierCase(IdentifierCaseLower) {
+		t.Error("ValidIdentifierCase accepted or rejected the wrong mode")
+	}
+}
+
+func TestProxyCompatEnvOverride(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ProxyCompat != ProxyCompatAuto {
+		t.Errorf("default ProxyCompat = %q, want auto", cfg.ProxyCompat)
+	}
+
+	_ = os.Setenv("MYSQL_MCP_PROXY_COMPAT", " ON ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.ProxyCompat != ProxyCompatOn {
+		t.Errorf("ProxyCompat = %q, want on", cfg.ProxyCompat)
+	}
+	if ValidProxyCompat("vitess") || !ValidProxyCompat(ProxyCompatOff) {
+		t.Error("ValidProxyCompat accepted or rejected the wrong mode")
+	}
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	got := ParseAPIKeys(" k1=analyst, k2 = dba ,broken,=x,k3=")
+	if len(got) != 2 || got["k1"] != "analyst" || got["k2"] != "dba" {
+		t.Fatalf("unexpected keys: %#v", got)
+	}
+	if ParseAPIKeys("nope") != nil {
+		t.Error("expected nil for no valid pairs")
+	}
+
+	clearEnv()
+	defer clearEnv()
+	os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	os.Setenv("MYSQL_MCP_API_KEYS", "secret=analyst")
+	os.Setenv("MYSQL_MCP_DEFAULT_ROLE", "analyst")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKeys["secret"] != "analyst" || cfg.DefaultRole != "analyst" {
+		t.Errorf("unexpected rbac env config: keys=%#v default=%q", cfg.APIKeys, cfg.DefaultRole)
+	}
+}
+
+func TestComponentLogLevels(t *testing.T) {
+	levels := ParseComponentLogLevels(" Validator=DEBUG, http=warn, pool, =info")
+	if len(levels) != 2 || levels["validator"] != "debug" || levels["http"] != "warn" {
+		t.Errorf("unexpected levels: %v", levels)
+	}
+	if err := CheckLogLevels("info", levels); err != nil {
+		t.Errorf("expected valid levels, got %v", err)
+	}
+	if err := CheckLogLevels("verbose", nil); err == nil {
+		t.Error("expected an unknown global level to be rejected")
+	}
+	if err := CheckLogLevels("info", map[string]string{"parser": "debug"}); err == nil {
+		t.Error("expected an unknown component to be rejected")
+	}
+	if err := CheckLogLevels("info", map[string]string{"http": "trace"}); err == nil {
+		t.Error("expected an unknown component level to be rejected")
+	}
+}
+
+func TestLoadLogLevelsFromEnv(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/testdb")
+	os.Setenv("MYSQL_MCP_LOG_LEVEL", "WARN")
+	os.Setenv("MYSQL_MCP_LOG_COMPONENTS", "validator=debug")
+	os.Setenv("MYSQL_MCP_LOG_REDACT_SQL", "1")
+	os.Setenv("MYSQL_MCP_AUDIT_FORMAT", "CEF")
+	os.Setenv("MYSQL_MCP_LOCALE", "JA")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.LogLevel != "warn" || cfg.ComponentLogLevels["validator"] != "debug" || !cfg.LogRedactSQL {
+		t.Errorf("unexpected logging config: level=%q components=%v redact=%v", cfg.LogLevel, cfg.ComponentLogLevels, cfg.LogRedactSQL)
+	}
+	if cfg.AuditFormat != AuditFormatCEF {
+		t.Errorf("expected audit format cef, got %q", cfg.AuditFormat)
+	}
+	if cfg.Locale != LocaleJapanese {
+		t.Errorf("expected locale ja, got %q", cfg.Locale)
+	}
+}
+
+func TestCheckReplicas(t *testing.T) {
+	ok := ConnectionConfig{Name: "prod", Replicas: []string{"u:p@tcp(r1:3306)/db"}, ReplicaRouting: ReplicaRoutingLeastLag}
+	if err := CheckReplicas(ok); err != nil {
+		t.Errorf("expected valid replicas, got %v", err)
+	}
+	if err := CheckReplicas(ConnectionConfig{Name: "prod", Replicas: []string{" "}}); err == nil {
+		t.Error("expected an empty replica DSN to be rejected")
+	}
+	if err := CheckReplicas(ConnectionConfig{Name: "prod", ReplicaRouting: "random"}); err == nil {
+		t.Error("expected an unknown replica_routing to be rejected")
+	}
+}
+
+func TestCheckSessionReaper(t *testing.T) {
+	for _, tc := range []struct {
+		cfg   Config
+		valid bool
+	}{
+		{Config{}, true},
+		{Config{ReapIdleSessionsAfter: 10 * time.Minute}, true},
+		{Config{ReapIdleSessionsAfter: 2 * time.Minute}, false}, // below the default 5m idle time
+		{Config{ReapIdleSessionsAfter: 10 * time.Minute, ConnMaxIdleTime: 15 * time.Minute}, false},
+		{Config{ReapIdleSessionsAfter: 10 * time.Minute, KeepaliveInterval: 10 * time.Minute}, false},
+	} {
+		if err := CheckSessionReaper(&tc.cfg); (err == nil) != tc.valid {
+			t.Errorf("CheckSessionReaper(reap=%s idle=%s keepalive=%s) = %v, want valid=%v",
+				tc.cfg.ReapIdleSessionsAfter, tc.cfg.ConnMaxIdleTime, tc.cfg.KeepaliveInterval, err, tc.valid)
+		}
+	}
+}
+
+func TestLoadTimeZone(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	_ = os.Setenv("MYSQL_MCP_TIME_ZONE", " +00:00 ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TimeZone != "+00:00" {
+		t.Errorf("TimeZone = %q, want +00:00", cfg.TimeZone)
+	}
+
+	for _, tz := range []string{"SYSTEM", "UTC", "Europe/Berlin", "America/Argentina/Buenos_Aires", "+14:00", "-13:59", "+5:30"} {
+		if !ValidTimeZone(tz) {
+			t.Errorf("ValidTimeZone(%q) = false, want true", tz)
+		}
+	}
+	for _, tz := range []string{"", "+14:01", "-14:00", "+02:60", "'UTC'", "Europe/", "UTC; SET x=1"} {
+		if ValidTimeZone(tz) {
+			t.Errorf("ValidTimeZone(%q) = true, want false", tz)
+		}
+	}
+}
+
+func TestLoadBookmarksFile(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BookmarksFile != "" {
+		t.Errorf("bookmarks should be disabled by default, got %q", cfg.BookmarksFile)
+	}
+
+	_ = os.Setenv("MYSQL_HTTP_BOOKMARKS_FILE", " /var/lib/mysql-mcp/bookmarks.json ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BookmarksFile != "/var/lib/mysql-mcp/bookmarks.json" {
+		t.Errorf("BookmarksFile = %q", cfg.BookmarksFile)
+	}
+}
+
+func TestLoadPlanBaselineFile(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	_ = os.Setenv("MYSQL_MCP_PLAN_BASELINE_FILE", " /var/lib/mysql-mcp/plans.json ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PlanBaselineFile != "/var/lib/mysql-mcp/plans.json" {
+		t.Errorf("PlanBaselineFile = %q", cfg.PlanBaselineFile)
+	}
+}
+
+func TestLoadWebhookFromEnv(t *testing.T) {
+	clearEnv()
+	defer clearEnv()
+	_ = os.Setenv("MYSQL_DSN", "user:pass@tcp(localhost:3306)/db")
+	_ = os.Setenv("MYSQL_MCP_WEBHOOK_URL", "https://hooks.example.com/T000/B000/token")
+	_ = os.Setenv("MYSQL_MCP_WEBHOOK_SECRET", "s3cret")
+	_ = os.Setenv("MYSQL_MCP_WEBHOOK_EVENTS", "Query_Blocked, query_slow")
+	_ = os.Setenv("MYSQL_MCP_WEBHOOK_SLOW_QUERY_SECONDS", "5")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Webhook{{URL: "https://hooks.example.com/T000/B000/token", Secret: "s3cret", Events: []string{"query_blocked", "query_slow"}}}
+	if !reflect.DeepEqual(cfg.Webhooks, want) || cfg.WebhookSlowQuery != 5*time.Second {
+		t.Errorf("webhooks = %+v, slow = %s", cfg.Webhooks, cfg.WebhookSlowQuery)
+	}
+}
+
+func TestCheckWebhooks(t *testing.T) {
+	if err := CheckWebhooks([]Webhook{{URL: "https://hooks.example.com/x", Events: []string{WebhookEventQuerySlow}}}); err != nil {
+		t.Errorf("valid webhook rejected: %v", err)
+	}
+	if err := CheckWebhooks([]Webhook{{URL: "hooks.example.com/x"}}); err == nil {
+		t.Error("expected a relative URL to be rejected")
+	}
+	err := CheckWebhooks([]Webhook{{URL: "https://hooks.example.com/token", Events: []string{"query_failed"}}})
+	if err == nil || !strings.Contains(err.Error(), "query_failed") {
+		t.Errorf("expected an unknown event error, got %v", err)
+	}
+	if got := MaskWebhookURL("https://hooks.example.com/T000/B000/token?x=1"); got != "https://hooks.example.com/***" {
+		t.Errorf("MaskWebhookURL = %q", got)
+	}
+}
+
+func TestParseToolFlags(t *testing.T) {
+	got := ParseToolFlags("Explain_Query=1, list_variables=off, extended=yes, bad, other=maybe")
+	want := map[string]bool{"explain_query": true, "list_variables": false, "extended": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseToolFlags = %v, want %v", got, want)
+	}
+	if ParseToolFlags("bad") != nil {
+		t.Error("expected nil for no valid pairs")
+	}
+}
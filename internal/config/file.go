@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/askdba/mysql-mcp-server/internal/cron"
+	"github.com/go-sql-driver/mysql"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,6 +21,9 @@ type FileConfig struct {
 	// Database connections
 	Connections map[string]FileConnectionConfig `yaml:"connections" json:"connections"`
 
+	// Tenant scopes on shared connections: connection, default schema, schema patterns
+	VirtualConnections map[string]FileVirtualConnection `yaml:"virtual_connections,omitempty" json:"virtual_connections,omitempty"`
+
 	// Query settings
 	Query FileQueryConfig `yaml:"query" json:"query"`
 
@@ -36,6 +41,155 @@ type FileConfig struct {
 
 	// HTTP/REST API settings
 	HTTP FileHTTPConfig `yaml:"http" json:"http"`
+
+	// Background status sampling for metrics_history
+	MetricsHistory FileMetricsHistoryConfig `yaml:"metrics_history" json:"metrics_history"`
+
+	// Named, parameterized read-only queries (run_saved_query)
+	SavedQueries map[string]FileSavedQuery `yaml:"saved_queries,omitempty" json:"saved_queries,omitempty"`
+
+	// Multi-query report templates (run_report)
+	Reports map[string]FileReport `yaml:"reports,omitempty" json:"reports,omitempty"`
+
+	// Saved queries run on a cron schedule (scheduled_results)
+	Schedules map[string]FileSchedule `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+
+	// Rollup / materialized tables keyed by database.table (list_summary_tables)
+	SummaryTables map[string]FileSummaryTable `yaml:"summary_tables,omitempty" json:"summary_tables,omitempty"`
+
+	// Timestamp columns checked by data_freshness
+	Freshness FileFreshnessConfig `yaml:"freshness,omitempty" json:"freshness,omitempty"`
+
+	// Databases watched for table and column changes (schema_changes)
+	SchemaWatch FileSchemaWatchConfig `yaml:"schema_watch,omitempty" json:"schema_watch,omitempty"`
+
+	// Role-based tool access
+	RBAC FileRBACConfig `yaml:"rbac,omitempty" json:"rbac,omitempty"`
+
+	// Per-identity query and row quotas
+	Quotas FileQuotasConfig `yaml:"quotas,omitempty" json:"quotas,omitempty"`
+
+	// Outbound webhooks for blocked queries, slow queries and unhealthy connections
+	Webhooks FileWebhooksConfig `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+
+	// Object storage buckets /api/jobs exports are uploaded to
+	ExportTargets map[string]FileExportTarget `yaml:"export_targets,omitempty" json:"export_targets,omitempty"`
+
+	// Language of user-facing error messages: en (default), de, ja or th
+	Locale string `yaml:"locale,omitempty" json:"locale,omitempty"`
+}
+
+// FileWebhooksConfig represents the webhooks section of the config file.
+type FileWebhooksConfig struct {
+	SlowQuerySeconds int                   `yaml:"slow_query_seconds,omitempty" json:"slow_query_seconds,omitempty"` // 0 = no query_slow events
+	Endpoints        []FileWebhookEndpoint `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
+}
+
+// FileWebhookEndpoint is one webhook URL in the config file.
+type FileWebhookEndpoint struct {
+	URL    string   `yaml:"url" json:"url"`
+	Secret string   `yaml:"secret,omitempty" json:"secret,omitempty"` // HMAC-SHA256 signing key
+	Events []string `yaml:"events,omitempty" json:"events,omitempty"` // empty = all events
+}
+
+// FileExportTarget is one object storage bucket in the config file.
+type FileExportTarget struct {
+	Type             string `yaml:"type" json:"type"` // s3, gcs or azure
+	Bucket           string `yaml:"bucket" json:"bucket"`
+	Prefix           string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	Region           string `yaml:"region,omitempty" json:"region,omitempty"`
+	Endpoint         string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	AccessKeyID      string `yaml:"access_key_id,omitempty" json:"access_key_id,omitempty"`
+	SecretAccessKey  string `yaml:"secret_access_key,omitempty" json:"secret_access_key,omitempty"`
+	Account          string `yaml:"account,omitempty" json:"account,omitempty"`
+	AccountKey       string `yaml:"account_key,omitempty" json:"account_key,omitempty"`
+	URLExpirySeconds int    `yaml:"url_expiry_seconds,omitempty" json:"url_expiry_seconds,omitempty"` // default 3600
+}
+
+// FileRBACConfig maps callers to roles and roles to permitted tools.
+type FileRBACConfig struct {
+	DefaultRole string              `yaml:"default_role,omitempty" json:"default_role,omitempty"`
+	Roles       map[string][]string `yaml:"roles,omitempty" json:"roles,omitempty"`
+	APIKeys     map[string]string   `yaml:"api_keys,omitempty" json:"api_keys,omitempty"`
+	Clients     map[string]string   `yaml:"clients,omitempty" json:"clients,omitempty"`
+}
+
+// FileQuotasConfig represents the quotas section of the config file. The
+// top-level limits apply to every caller; roles replaces them per rbac role.
+type FileQuotasConfig struct {
+	MaxQueriesPerHour int                  `yaml:"max_queries_per_hour,omitempty" json:"max_queries_per_hour,omitempty"`
+	MaxRowsPerDay     int                  `yaml:"max_rows_per_day,omitempty" json:"max_rows_per_day,omitempty"`
+	StateFile         string               `yaml:"state_file,omitempty" json:"state_file,omitempty"` // keeps counters across restarts
+	Roles             map[string]FileQuota `yaml:"roles,omitempty" json:"roles,omitempty"`
+}
+
+// FileQuota is one set of quota limits; 0 = unlimited.
+type FileQuota struct {
+	MaxQueriesPerHour int `yaml:"max_queries_per_hour,omitempty" json:"max_queries_per_hour,omitempty"`
+	MaxRowsPerDay     int `yaml:"max_rows_per_day,omitempty" json:"max_rows_per_day,omitempty"`
+}
+
+// FileReport represents a report template in the config file.
+type FileReport struct {
+	Description string                `yaml:"description" json:"description"`
+	Database    string                `yaml:"database,omitempty" json:"database,omitempty"`
+	Variables   []FileSavedQueryParam `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Sections    []FileReportSection   `yaml:"sections" json:"sections"`
+}
+
+// FileReportSection represents one report section: inline sql or a saved query name.
+type FileReportSection struct {
+	Name       string `yaml:"name" json:"name"`
+	SQL        string `yaml:"sql,omitempty" json:"sql,omitempty"`
+	SavedQuery string `yaml:"saved_query,omitempty" json:"saved_query,omitempty"`
+	MaxRows    int    `yaml:"max_rows,omitempty" json:"max_rows,omitempty"`
+}
+
+// FileSchedule represents a scheduled saved query in the config file.
+type FileSchedule struct {
+	SavedQuery    string                 `yaml:"saved_query" json:"saved_query"`
+	Cron          string                 `yaml:"cron" json:"cron"` // e.g. "*/15 * * * *", @hourly, "@every 10m"
+	Params        map[string]interface{} `yaml:"params,omitempty" json:"params,omitempty"`
+	Database      string                 `yaml:"database,omitempty" json:"database,omitempty"`
+	JitterSeconds int                    `yaml:"jitter_seconds,omitempty" json:"jitter_seconds,omitempty"`
+}
+
+// FileFreshnessConfig represents the freshness section of the config file.
+type FileFreshnessConfig struct {
+	CacheSeconds int               `yaml:"cache_seconds,omitempty" json:"cache_seconds,omitempty"` // default 60
+	Tables       map[string]string `yaml:"tables,omitempty" json:"tables,omitempty"`               // database.table -> timestamp column
+}
+
+// FileSchemaWatchConfig represents the schema_watch section of the config file.
+type FileSchemaWatchConfig struct {
+	Databases       []string `yaml:"databases,omitempty" json:"databases,omitempty"`
+	IntervalSeconds int      `yaml:"interval_seconds,omitempty" json:"interval_seconds,omitempty"` // default 60
+}
+
+// FileSummaryTable represents a rollup table in the config file.
+type FileSummaryTable struct {
+	Source          string `yaml:"source,omitempty" json:"source,omitempty"` // fact table it summarizes
+	Grain           string `yaml:"grain,omitempty" json:"grain,omitempty"`
+	FreshnessColumn string `yaml:"freshness_column,omitempty" json:"freshness_column,omitempty"`
+	Description     string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// FileSavedQuery represents a saved query in the config file. Parameters are
+// referenced in sql as :name and always bound, never interpolated.
+type FileSavedQuery struct {
+	Description string                `yaml:"description" json:"description"`
+	SQL         string                `yaml:"sql" json:"sql"`
+	Database    string                `yaml:"database,omitempty" json:"database,omitempty"`
+	Params      []FileSavedQueryParam `yaml:"params,omitempty" json:"params,omitempty"`
+}
+
+// FileSavedQueryParam describes one saved query parameter.
+type FileSavedQueryParam struct {
+	Name        string      `yaml:"name" json:"name"`
+	Type        string      `yaml:"type,omitempty" json:"type,omitempty"` // string (default), int, number, bool, date, identifier (reports)
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     interface{} `yaml:"default,omitempty" json:"default,omitempty"`
 }
 
 // FileConnectionConfig represents a connection in the config file.
@@ -43,8 +197,36 @@ type FileConnectionConfig struct {
 	DSN         string         `yaml:"dsn" json:"dsn"`
 	Description string         `yaml:"description" json:"description"`
 	ReadOnly    bool           `yaml:"read_only" json:"read_only"`
-	SSL         string         `yaml:"ssl" json:"ssl"` // "true", "false", "skip-verify", or empty
-	SSH         *FileSSHConfig `yaml:"ssh" json:"ssh"` // optional SSH tunnel (bastion)
+	SSL         string         `yaml:"ssl" json:"ssl"`                                     // "true", "false", "skip-verify", or empty
+	SSH         *FileSSHConfig `yaml:"ssh" json:"ssh"`                                     // optional SSH tunnel (bastion)
+	Environment string         `yaml:"environment,omitempty" json:"environment,omitempty"` // prod, staging, dev, ...
+	Tags        []string       `yaml:"tags,omitempty" json:"tags,omitempty"`
+
+	MaxConcurrentQueries int  `yaml:"max_concurrent_queries,omitempty" json:"max_concurrent_queries,omitempty"` // 0 = no per-connection limit
+	ConnectOnDemand      bool `yaml:"connect_on_demand,omitempty" json:"connect_on_demand,omitempty"`           // open on first use, not at startup
+
+	Socket                string `yaml:"socket,omitempty" json:"socket,omitempty"`       // unix socket path instead of the DSN host
+	Compress              bool   `yaml:"compress,omitempty" json:"compress,omitempty"`   // zlib protocol compression
+	Collation             string `yaml:"collation,omitempty" json:"collation,omitempty"` // e.g. utf8mb4_0900_ai_ci
+	ConnectTimeoutSeconds int    `yaml:"connect_timeout_seconds,omitempty" json:"connect_timeout_seconds,omitempty"`
+	ReadTimeoutSeconds    int    `yaml:"read_timeout_seconds,omitempty" json:"read_timeout_seconds,omitempty"`
+	WriteTimeoutSeconds   int    `yaml:"write_timeout_seconds,omitempty" json:"write_timeout_seconds,omitempty"`
+
+	Replicas       []string `yaml:"replicas,omitempty" json:"replicas,omitempty"`               // read replica DSNs
+	ReplicaRouting string   `yaml:"replica_routing,omitempty" json:"replica_routing,omitempty"` // round_robin (default) or least_lag
+	PinReplica     bool     `yaml:"pin_replica,omitempty" json:"pin_replica,omitempty"`         // keep an MCP session on one replica
+
+	RowPolicies map[string]string `yaml:"row_policies,omitempty" json:"row_policies,omitempty"` // table -> mandatory predicate
+}
+
+// FileVirtualConnection represents a tenant scope in the config file.
+type FileVirtualConnection struct {
+	Connection  string   `yaml:"connection,omitempty" json:"connection,omitempty"` // physical connection name
+	Database    string   `yaml:"database,omitempty" json:"database,omitempty"`     // default schema
+	Schemas     []string `yaml:"schemas" json:"schemas"`                           // e.g. tenant_1234_%
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+
+	Session map[string]string `yaml:"session,omitempty" json:"session,omitempty"` // {session.name} values for row policies
 }
 
 // FileSSHConfig represents SSH tunnel settings in the config file.
@@ -60,25 +242,67 @@ type FileSSHConfig struct {
 
 // FileQueryConfig represents query settings in the config file.
 type FileQueryConfig struct {
-	MaxRows        int      `yaml:"max_rows" json:"max_rows"`
-	TimeoutSeconds int      `yaml:"timeout_seconds" json:"timeout_seconds"`
-	MaskColumns    []string `yaml:"mask_columns" json:"mask_columns"`
+	MaxRows         int            `yaml:"max_rows" json:"max_rows"`
+	MaxResultBytes  int            `yaml:"max_result_bytes,omitempty" json:"max_result_bytes,omitempty"` // 0 = default (8 MiB)
+	BinaryOutput    string         `yaml:"binary_output,omitempty" json:"binary_output,omitempty"`       // hex (default), base64, length, skip or raw
+	IdentifierCase  string         `yaml:"identifier_case,omitempty" json:"identifier_case,omitempty"`   // preserve (default), lower or catalog
+	ProxyCompat     string         `yaml:"proxy_compat,omitempty" json:"proxy_compat,omitempty"`         // auto (default), on or off
+	TimeZone        string         `yaml:"time_zone,omitempty" json:"time_zone,omitempty"`               // session time_zone, e.g. +00:00 or Europe/Berlin
+	TimeoutSeconds  int            `yaml:"timeout_seconds" json:"timeout_seconds"`
+	MaskColumns     []string       `yaml:"mask_columns" json:"mask_columns"`
+	InjectLimit     *bool          `yaml:"inject_limit,omitempty" json:"inject_limit,omitempty"`                       // nil = default (on)
+	SuggestNames    *bool          `yaml:"suggest_identifiers,omitempty" json:"suggest_identifiers,omitempty"`         // nil = default (on)
+	MaxExecTimeHint *bool          `yaml:"max_execution_time_hint,omitempty" json:"max_execution_time_hint,omitempty"` // nil = default (on)
+	KillOnCancel    bool           `yaml:"kill_on_cancel,omitempty" json:"kill_on_cancel,omitempty"`
+	DatabaseMaxRows map[string]int `yaml:"database_max_rows,omitempty" json:"database_max_rows,omitempty"`
+
+	PseudonymizeColumns []string `yaml:"pseudonymize_columns,omitempty" json:"pseudonymize_columns,omitempty"`
+	PseudonymKey        string   `yaml:"pseudonym_key,omitempty" json:"pseudonym_key,omitempty"` // empty = a random key per session
+	PIIColumns          []string `yaml:"pii_columns,omitempty" json:"pii_columns,omitempty"`     // flagged in run_query results
+
+	MaxConcurrentQueries int `yaml:"max_concurrent_queries,omitempty" json:"max_concurrent_queries,omitempty"` // 0 = unlimited
+	QueueDepth           int `yaml:"queue_depth,omitempty" json:"queue_depth,omitempty"`                       // 0 = fail fast when saturated
+	QueueTimeoutSeconds  int `yaml:"queue_timeout_seconds,omitempty" json:"queue_timeout_seconds,omitempty"`
+
+	DuplicateWindowSeconds int `yaml:"duplicate_window_seconds,omitempty" json:"duplicate_window_seconds,omitempty"` // 0 = off
+
+	Retry *FileRetryConfig `yaml:"retry,omitempty" json:"retry,omitempty"` // transient error retries
+
+	PlanBaselineFile string `yaml:"plan_baseline_file,omitempty" json:"plan_baseline_file,omitempty"` // check_saved_queries baselines
+}
+
+// FileRetryConfig represents transient error retry settings in the config file.
+type FileRetryConfig struct {
+	MaxRetries        *int `yaml:"max_retries,omitempty" json:"max_retries,omitempty"` // nil = default (3), 0 disables
+	InitialIntervalMs int  `yaml:"initial_interval_ms,omitempty" json:"initial_interval_ms,omitempty"`
+	MaxIntervalMs     int  `yaml:"max_interval_ms,omitempty" json:"max_interval_ms,omitempty"`
 }
 
 // FilePoolConfig represents connection pool settings in the config file.
 type FilePoolConfig struct {
-	MaxOpenConns           int `yaml:"max_open_conns" json:"max_open_conns"`
-	MaxIdleConns           int `yaml:"max_idle_conns" json:"max_idle_conns"`
-	ConnMaxLifetimeMinutes int `yaml:"conn_max_lifetime_minutes" json:"conn_max_lifetime_minutes"`
-	ConnMaxIdleTimeMinutes int `yaml:"conn_max_idle_time_minutes" json:"conn_max_idle_time_minutes"`
-	PingTimeoutSeconds     int `yaml:"ping_timeout_seconds" json:"ping_timeout_seconds"`
+	MaxOpenConns           int   `yaml:"max_open_conns" json:"max_open_conns"`
+	MaxIdleConns           int   `yaml:"max_idle_conns" json:"max_idle_conns"`
+	ConnMaxLifetimeMinutes int   `yaml:"conn_max_lifetime_minutes" json:"conn_max_lifetime_minutes"`
+	ConnMaxIdleTimeMinutes int   `yaml:"conn_max_idle_time_minutes" json:"conn_max_idle_time_minutes"`
+	PingTimeoutSeconds     int   `yaml:"ping_timeout_seconds" json:"ping_timeout_seconds"`
+	PreparedStatements     *bool `yaml:"prepared_statements,omitempty" json:"prepared_statements,omitempty"` // nil = default (on)
+
+	CircuitThreshold       *int `yaml:"circuit_threshold,omitempty" json:"circuit_threshold,omitempty"` // nil = default (5), 0 disables
+	CircuitCooldownSeconds int  `yaml:"circuit_cooldown_seconds,omitempty" json:"circuit_cooldown_seconds,omitempty"`
+
+	KeepaliveSeconds        int `yaml:"keepalive_seconds,omitempty" json:"keepalive_seconds,omitempty"`                   // 0 = off
+	ReapIdleSessionsSeconds int `yaml:"reap_idle_sessions_seconds,omitempty" json:"reap_idle_sessions_seconds,omitempty"` // 0 = off
 }
 
 // FileFeatureConfig represents feature flags in the config file.
 type FileFeatureConfig struct {
 	ExtendedTools bool `yaml:"extended_tools" json:"extended_tools"`
 	VectorTools   bool `yaml:"vector_tools" json:"vector_tools"`
+	VectorWrite   bool `yaml:"vector_write" json:"vector_write"` // vector_insert / vector_delete
 	TokenCard     bool `yaml:"token_card" json:"token_card"`
+
+	// Per-tool or per-group switches, e.g. {explain_query: true, list_variables: false}
+	Tools map[string]bool `yaml:"tools,omitempty" json:"tools,omitempty"`
 }
 
 // FileSecurityConfig represents access-control and privileged tool flags.
@@ -88,22 +312,54 @@ type FileSecurityConfig struct {
 	ProcessAdmin     bool     `yaml:"process_admin" json:"process_admin"`
 	ReadAuditTool    bool     `yaml:"read_audit_tool" json:"read_audit_tool"`
 	SlowQueryTool    bool     `yaml:"slow_query_tool" json:"slow_query_tool"`
+	SessionsTool     bool     `yaml:"sessions_tool" json:"sessions_tool"`
+	SaveQueryTool    bool     `yaml:"save_query_tool" json:"save_query_tool"`
+	ConfirmRequired  []string `yaml:"confirm_required,omitempty" json:"confirm_required,omitempty"` // environments/tags needing confirm=true
+
+	VirtualConnection string `yaml:"virtual_connection,omitempty" json:"virtual_connection,omitempty"` // lock every call to this virtual connection
 }
 
 // FileLoggingConfig represents logging settings in the config file.
 type FileLoggingConfig struct {
-	JSONFormat    bool   `yaml:"json_format" json:"json_format"`
-	AuditLogPath  string `yaml:"audit_log_path" json:"audit_log_path"`
-	TokenTracking bool   `yaml:"token_tracking" json:"token_tracking"`
-	TokenModel    string `yaml:"token_model" json:"token_model"`
+	JSONFormat    bool              `yaml:"json_format" json:"json_format"`
+	Level         string            `yaml:"level,omitempty" json:"level,omitempty"`           // debug, info (default), warn or error
+	Components    map[string]string `yaml:"components,omitempty" json:"components,omitempty"` // per-component levels: http, pool, validator, audit
+	RedactSQL     bool              `yaml:"redact_sql,omitempty" json:"redact_sql,omitempty"` // replace literals in logged and audited SQL with ?
+	AuditLogPath  string            `yaml:"audit_log_path" json:"audit_log_path"`
+	Audit         *FileAuditConfig  `yaml:"audit,omitempty" json:"audit,omitempty"`
+	TokenTracking bool              `yaml:"token_tracking" json:"token_tracking"`
+	TokenModel    string            `yaml:"token_model" json:"token_model"`
+}
+
+// FileAuditConfig represents audit log settings in the config file.
+type FileAuditConfig struct {
+	Format string `yaml:"format" json:"format"` // json (default), cef or leef
 }
 
 // FileHTTPConfig represents HTTP settings in the config file.
 type FileHTTPConfig struct {
-	Enabled               bool                 `yaml:"enabled" json:"enabled"`
-	Port                  int                  `yaml:"port" json:"port"`
-	RequestTimeoutSeconds int                  `yaml:"request_timeout_seconds" json:"request_timeout_seconds"`
-	RateLimit             *FileRateLimitConfig `yaml:"rate_limit" json:"rate_limit"`
+	Enabled               bool                   `yaml:"enabled" json:"enabled"`
+	Port                  int                    `yaml:"port" json:"port"`
+	RequestTimeoutSeconds int                    `yaml:"request_timeout_seconds" json:"request_timeout_seconds"`
+	StreamMaxRows         *int                   `yaml:"stream_max_rows,omitempty" json:"stream_max_rows,omitempty"` // nil = default, 0 = unlimited
+	BookmarksFile         string                 `yaml:"bookmarks_file,omitempty" json:"bookmarks_file,omitempty"`   // enables /api/bookmarks
+	JobTimeoutSeconds     int                    `yaml:"job_timeout_seconds,omitempty" json:"job_timeout_seconds,omitempty"`
+	JobRetentionSeconds   int                    `yaml:"job_retention_seconds,omitempty" json:"job_retention_seconds,omitempty"`
+	JobStoreBytes         int                    `yaml:"job_store_bytes,omitempty" json:"job_store_bytes,omitempty"` // result bytes kept across /api/jobs
+	Compression           *FileCompressionConfig `yaml:"compression,omitempty" json:"compression,omitempty"`
+	RateLimit             *FileRateLimitConfig   `yaml:"rate_limit" json:"rate_limit"`
+}
+
+// FileCompressionConfig represents response compression settings in the config file.
+type FileCompressionConfig struct {
+	Enabled  *bool `yaml:"enabled" json:"enabled"`
+	MinBytes *int  `yaml:"min_bytes" json:"min_bytes"` // smallest body that is compressed
+}
+
+// FileMetricsHistoryConfig represents the background status sampler in the config file.
+type FileMetricsHistoryConfig struct {
+	SampleSeconds int `yaml:"sample_seconds" json:"sample_seconds"` // 0 = sampler disabled
+	HistorySize   int `yaml:"history_size" json:"history_size"`
 }
 
 // FileRateLimitConfig represents rate limiting settings in the config file.
@@ -170,13 +426,20 @@ func FindConfigFile() string {
 	return ""
 }
 
-// LoadConfigFile loads configuration from a file (YAML or JSON).
+// LoadConfigFile loads configuration from a file (YAML or JSON). Unknown keys,
+// mistyped values and out-of-range numbers are rejected with a *SchemaErrors
+// listing each problem and its line.
 func LoadConfigFile(path string) (*FileConfig, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	// Syntax errors are left to the decoders below, which report them per format.
+	if errs, err := CheckConfigSchema(data); err == nil && len(errs) > 0 {
+		return nil, &SchemaErrors{File: path, Errors: errs}
+	}
+
 	var cfg FileConfig
 
 	// Determine format by extension
@@ -225,39 +488,361 @@ func ValidateConfigFile(path string) error {
 		if conn.DSN == "" {
 			return fmt.Errorf("connection '%s' has empty DSN", name)
 		}
+		if conn.Socket != "" && conn.SSH != nil && conn.SSH.Host != "" {
+			return fmt.Errorf("connection '%s': socket cannot be combined with an SSH tunnel", name)
+		}
+		if err := CheckReplicas(ConnectionConfig{Name: name, Replicas: conn.Replicas, ReplicaRouting: strings.ToLower(strings.TrimSpace(conn.ReplicaRouting))}); err != nil {
+			return err
+		}
+		if conn.Collation != "" && !validCollationName(conn.Collation) {
+			return fmt.Errorf("connection '%s': invalid collation '%s'", name, conn.Collation)
+		}
+		if conn.ConnectTimeoutSeconds < 0 || conn.ReadTimeoutSeconds < 0 || conn.WriteTimeoutSeconds < 0 {
+			return fmt.Errorf("connection '%s': timeouts must not be negative", name)
+		}
+		if err := CheckRowPolicies(ConnectionConfig{Name: name, RowPolicies: conn.RowPolicies}); err != nil {
+			return err
+		}
+	}
+
+	if v := strings.ToLower(strings.TrimSpace(cfg.Query.BinaryOutput)); v != "" && !ValidBinaryOutput(v) {
+		return fmt.Errorf("query.binary_output '%s' must be one of hex, base64, length, skip or raw", cfg.Query.BinaryOutput)
+	}
+	if v := strings.ToLower(strings.TrimSpace(cfg.Query.IdentifierCase)); v != "" && !ValidIdentifierCase(v) {
+		return fmt.Errorf("query.identifier_case '%s' must be one of preserve, lower or catalog", cfg.Query.IdentifierCase)
+	}
+	if v := strings.ToLower(strings.TrimSpace(cfg.Query.ProxyCompat)); v != "" && !ValidProxyCompat(v) {
+		return fmt.Errorf("query.proxy_compat '%s' must be one of auto, on or off", cfg.Query.ProxyCompat)
+	}
+	if v := strings.TrimSpace(cfg.Query.TimeZone); v != "" && !ValidTimeZone(v) {
+		return fmt.Errorf("query.time_zone '%s' must be SYSTEM, an offset such as +00:00 or a zone name such as Europe/Berlin", cfg.Query.TimeZone)
+	}
+
+	if level := strings.ToLower(strings.TrimSpace(cfg.Logging.Level)); level != "" || len(cfg.Logging.Components) > 0 {
+		if level == "" {
+			level = DefaultLogLevel
+		}
+		components := map[string]string{}
+		for name, l := range cfg.Logging.Components {
+			components[strings.ToLower(strings.TrimSpace(name))] = strings.ToLower(strings.TrimSpace(l))
+		}
+		if err := CheckLogLevels(level, components); err != nil {
+			return fmt.Errorf("logging: %w", err)
+		}
+	}
+
+	if (cfg.Pool.CircuitThreshold != nil && *cfg.Pool.CircuitThreshold < 0) || cfg.Pool.CircuitCooldownSeconds < 0 {
+		return fmt.Errorf("pool.circuit_threshold and pool.circuit_cooldown_seconds must not be negative")
+	}
+	if cfg.Pool.KeepaliveSeconds < 0 || cfg.Pool.ReapIdleSessionsSeconds < 0 {
+		return fmt.Errorf("pool.keepalive_seconds and pool.reap_idle_sessions_seconds must not be negative")
+	}
+
+	if cfg.Query.DuplicateWindowSeconds < 0 {
+		return fmt.Errorf("query.duplicate_window_seconds must not be negative")
+	}
+	if r := cfg.Query.Retry; r != nil {
+		if r.MaxRetries != nil && (*r.MaxRetries < 0 || *r.MaxRetries > 20) {
+			return fmt.Errorf("query.retry.max_retries must be between 0 and 20")
+		}
+		if r.InitialIntervalMs < 0 || r.MaxIntervalMs < 0 {
+			return fmt.Errorf("query.retry intervals must not be negative")
+		}
+	}
+
+	if cfg.Logging.Audit != nil {
+		if v := strings.ToLower(strings.TrimSpace(cfg.Logging.Audit.Format)); v != "" && !ValidAuditFormat(v) {
+			return fmt.Errorf("logging.audit.format '%s' must be one of json, cef or leef", cfg.Logging.Audit.Format)
+		}
+	}
+	if v := strings.ToLower(strings.TrimSpace(cfg.Locale)); v != "" && !ValidLocale(v) {
+		return fmt.Errorf("locale '%s' must be one of en, de, ja or th", cfg.Locale)
+	}
+
+	for name, q := range cfg.SavedQueries {
+		if strings.TrimSpace(q.SQL) == "" {
+			return fmt.Errorf("saved query '%s' has empty sql", name)
+		}
+		for _, p := range q.Params {
+			if strings.TrimSpace(p.Name) == "" {
+				return fmt.Errorf("saved query '%s' has a parameter without a name", name)
+			}
+		}
+	}
+
+	for name, r := range cfg.Reports {
+		if len(r.Sections) == 0 {
+			return fmt.Errorf("report '%s' has no sections", name)
+		}
+		for _, s := range r.Sections {
+			if (strings.TrimSpace(s.SQL) == "") == (strings.TrimSpace(s.SavedQuery) == "") {
+				return fmt.Errorf("report '%s' section '%s' needs exactly one of sql or saved_query", name, s.Name)
+			}
+		}
+	}
+
+	for name, sc := range cfg.Schedules {
+		if strings.TrimSpace(sc.SavedQuery) == "" {
+			return fmt.Errorf("schedule '%s' needs a saved_query", name)
+		}
+		if _, err := cron.Parse(sc.Cron); err != nil {
+			return fmt.Errorf("schedule '%s': %w", name, err)
+		}
+		if sc.JitterSeconds < 0 {
+			return fmt.Errorf("schedule '%s' jitter_seconds must not be negative", name)
+		}
+	}
+
+	if cfg.SchemaWatch.IntervalSeconds < 0 {
+		return fmt.Errorf("schema_watch.interval_seconds must not be negative")
+	}
+	for _, db := range cfg.SchemaWatch.Databases {
+		if strings.TrimSpace(db) == "" {
+			return fmt.Errorf("schema_watch.databases must not contain empty names")
+		}
+	}
+
+	for name := range cfg.SummaryTables {
+		if db, table, ok := strings.Cut(strings.TrimSpace(name), "."); !ok || db == "" || table == "" {
+			return fmt.Errorf("summary table '%s' must be named database.table", name)
+		}
+	}
+
+	if cfg.Freshness.CacheSeconds < 0 {
+		return fmt.Errorf("freshness.cache_seconds must not be negative")
+	}
+	for name, column := range cfg.Freshness.Tables {
+		if db, table, ok := strings.Cut(strings.TrimSpace(name), "."); !ok || db == "" || table == "" {
+			return fmt.Errorf("freshness table '%s' must be named database.table", name)
+		}
+		if strings.TrimSpace(column) == "" {
+			return fmt.Errorf("freshness table '%s' needs a column", name)
+		}
+	}
+
+	for name, vc := range cfg.VirtualConnections {
+		if _, ok := cfg.Connections[name]; ok {
+			return fmt.Errorf("virtual connection '%s' has the name of a connection", name)
+		}
+		if len(vc.Schemas) == 0 {
+			return fmt.Errorf("virtual connection '%s' needs at least one schema pattern", name)
+		}
+		for _, p := range vc.Schemas {
+			if !schemaPatternRe.MatchString(strings.TrimSpace(p)) {
+				return fmt.Errorf("virtual connection '%s' schema pattern '%s' may only contain letters, digits, _, $, - and %%", name, p)
+			}
+		}
+		if db := strings.TrimSpace(vc.Database); db != "" && !matchAnySchemaPattern(vc.Schemas, db) {
+			return fmt.Errorf("virtual connection '%s' database '%s' does not match its schemas", name, db)
+		}
+	}
+	if name := strings.TrimSpace(cfg.Security.VirtualConnection); name != "" {
+		if _, ok := cfg.VirtualConnections[name]; !ok {
+			return fmt.Errorf("security.virtual_connection references unknown virtual connection '%s'", name)
+		}
+	}
+
+	for _, role := range cfg.RBAC.APIKeys {
+		if _, ok := cfg.RBAC.Roles[role]; !ok {
+			return fmt.Errorf("rbac api key references unknown role '%s'", role)
+		}
+	}
+	for client, role := range cfg.RBAC.Clients {
+		if _, ok := cfg.RBAC.Roles[role]; !ok {
+			return fmt.Errorf("rbac client '%s' references unknown role '%s'", client, role)
+		}
+	}
+	if r := cfg.RBAC.DefaultRole; r != "" {
+		if _, ok := cfg.RBAC.Roles[r]; !ok {
+			return fmt.Errorf("rbac default_role references unknown role '%s'", r)
+		}
+	}
+
+	if cfg.Quotas.MaxQueriesPerHour < 0 || cfg.Quotas.MaxRowsPerDay < 0 {
+		return fmt.Errorf("quotas limits must not be negative")
+	}
+	for role, q := range cfg.Quotas.Roles {
+		if _, ok := cfg.RBAC.Roles[role]; !ok {
+			return fmt.Errorf("quotas role '%s' is not an rbac role", role)
+		}
+		if q.MaxQueriesPerHour < 0 || q.MaxRowsPerDay < 0 {
+			return fmt.Errorf("quotas role '%s' limits must not be negative", role)
+		}
+	}
+
+	if cfg.Webhooks.SlowQuerySeconds < 0 {
+		return fmt.Errorf("webhooks.slow_query_seconds must not be negative")
+	}
+	if err := CheckWebhooks(fileWebhooks(cfg.Webhooks.Endpoints)); err != nil {
+		return fmt.Errorf("webhooks: %w", err)
+	}
+
+	for name, t := range cfg.ExportTargets {
+		if t.URLExpirySeconds < 0 {
+			return fmt.Errorf("export target '%s' url_expiry_seconds must not be negative", name)
+		}
+	}
+	if err := CheckExportTargets(fileExportTargets(cfg.ExportTargets)); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// fileWebhooks converts the webhook endpoints of the config file.
+func fileWebhooks(endpoints []FileWebhookEndpoint) []Webhook {
+	var out []Webhook
+	for _, e := range endpoints {
+		w := Webhook{URL: strings.TrimSpace(e.URL), Secret: e.Secret}
+		for _, ev := range e.Events {
+			w.Events = append(w.Events, strings.ToLower(strings.TrimSpace(ev)))
+		}
+		out = append(out, w)
+	}
+	return out
+}
+
+// fileExportTargets converts the export targets of the config file, sorted by name.
+func fileExportTargets(targets map[string]FileExportTarget) []ExportTarget {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out []ExportTarget
+	for _, name := range names {
+		ft := targets[name]
+		t := ExportTarget{
+			Name:            strings.TrimSpace(name),
+			Type:            strings.ToLower(strings.TrimSpace(ft.Type)),
+			Bucket:          strings.TrimSpace(ft.Bucket),
+			Prefix:          strings.TrimLeft(strings.TrimSpace(ft.Prefix), "/"),
+			Region:          strings.TrimSpace(ft.Region),
+			Endpoint:        strings.TrimRight(strings.TrimSpace(ft.Endpoint), "/"),
+			AccessKeyID:     strings.TrimSpace(ft.AccessKeyID),
+			SecretAccessKey: ft.SecretAccessKey,
+			Account:         strings.TrimSpace(ft.Account),
+			AccountKey:      ft.AccountKey,
+			URLExpiry:       time.Duration(DefaultExportURLExpiryS) * time.Second,
+		}
+		if ft.URLExpirySeconds > 0 {
+			t.URLExpiry = secondsToDuration(ft.URLExpirySeconds)
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
 // ToConfig converts a FileConfig to the runtime Config struct.
 // Values from FileConfig are used as base, can be overridden by env vars.
 func (fc *FileConfig) ToConfig() *Config {
 	cfg := &Config{
 		// Set defaults first (must include all fields to avoid zero-value issues)
-		MaxRows:            DefaultMaxRows,
-		QueryTimeout:       time.Duration(DefaultQueryTimeoutSecs) * time.Second,
-		MaxOpenConns:       DefaultMaxOpenConns,
-		MaxIdleConns:       DefaultMaxIdleConns,
-		ConnMaxLifetime:    time.Duration(DefaultConnMaxLifetimeMins) * time.Minute,
-		ConnMaxIdleTime:    time.Duration(DefaultConnMaxIdleTimeMins) * time.Minute,
-		PingTimeout:        time.Duration(DefaultPingTimeoutSecs) * time.Second,
-		HTTPPort:           DefaultHTTPPort,
-		HTTPRequestTimeout: time.Duration(DefaultHTTPRequestTimeoutS) * time.Second,
-		RateLimitRPS:       float64(DefaultRateLimitRPS),
-		RateLimitBurst:     DefaultRateLimitBurst,
-		TokenModel:         "cl100k_base",
-		DBRetryMaxRetries:  3,
-		DBRetryMaxInterval: 10 * time.Second,
+		MaxRows:             DefaultMaxRows,
+		QueryTimeout:        time.Duration(DefaultQueryTimeoutSecs) * time.Second,
+		InjectLimit:         true,
+		SuggestNames:        true,
+		MaxExecTimeHint:     true,
+		PreparedStatements:  true,
+		MaxOpenConns:        DefaultMaxOpenConns,
+		MaxIdleConns:        DefaultMaxIdleConns,
+		ConnMaxLifetime:     time.Duration(DefaultConnMaxLifetimeMins) * time.Minute,
+		ConnMaxIdleTime:     time.Duration(DefaultConnMaxIdleTimeMins) * time.Minute,
+		PingTimeout:         time.Duration(DefaultPingTimeoutSecs) * time.Second,
+		HTTPPort:            DefaultHTTPPort,
+		HTTPRequestTimeout:  time.Duration(DefaultHTTPRequestTimeoutS) * time.Second,
+		StreamMaxRows:       DefaultStreamMaxRows,
+		Compression:         true,
+		CompressionMinBytes: DefaultCompressionMinBytes,
+		JobTimeout:          time.Duration(DefaultJobTimeoutS) * time.Second,
+		JobRetention:        time.Duration(DefaultJobRetentionS) * time.Second,
+		JobStoreBytes:       DefaultJobStoreBytes,
+		RateLimitRPS:        float64(DefaultRateLimitRPS),
+		RateLimitBurst:      DefaultRateLimitBurst,
+		TokenModel:          "cl100k_base",
+		DBRetryMaxRetries:   3,
+		DBRetryMaxInterval:  10 * time.Second,
+		MetricsHistorySize:  DefaultMetricsHistorySize,
+		FreshnessCacheTTL:   time.Duration(DefaultFreshnessCacheS) * time.Second,
+		SchemaWatchInterval: time.Duration(DefaultSchemaWatchS) * time.Second,
+		QueryQueueTimeout:   time.Duration(DefaultQueryQueueTimeoutS) * time.Second,
+		CircuitThreshold:    DefaultCircuitThreshold,
+		CircuitCooldown:     time.Duration(DefaultCircuitCooldownS) * time.Second,
+		MaxResultBytes:      DefaultMaxResultBytes,
+		BinaryOutput:        DefaultBinaryOutput,
+		IdentifierCase:      DefaultIdentifierCase,
+		ProxyCompat:         DefaultProxyCompat,
+		LogLevel:            DefaultLogLevel,
+		AuditFormat:         DefaultAuditFormat,
+		Locale:              DefaultLocale,
 	}
 
 	// Apply file config values (if set)
 	if fc.Query.MaxRows > 0 {
 		cfg.MaxRows = fc.Query.MaxRows
 	}
+	if fc.Query.MaxResultBytes > 0 {
+		cfg.MaxResultBytes = fc.Query.MaxResultBytes
+	}
+	if v := strings.TrimSpace(fc.Query.BinaryOutput); v != "" {
+		cfg.BinaryOutput = strings.ToLower(v)
+	}
+	if v := strings.TrimSpace(fc.Query.IdentifierCase); v != "" {
+		cfg.IdentifierCase = strings.ToLower(v)
+	}
+	if v := strings.TrimSpace(fc.Query.ProxyCompat); v != "" {
+		cfg.ProxyCompat = strings.ToLower(v)
+	}
+	if v := strings.TrimSpace(fc.Query.TimeZone); v != "" {
+		cfg.TimeZone = v
+	}
+	cfg.PlanBaselineFile = strings.TrimSpace(fc.Query.PlanBaselineFile)
 	if fc.Query.TimeoutSeconds > 0 {
 		cfg.QueryTimeout = secondsToDuration(fc.Query.TimeoutSeconds)
 	}
+	if fc.Query.InjectLimit != nil {
+		cfg.InjectLimit = *fc.Query.InjectLimit
+	}
+	if fc.Query.SuggestNames != nil {
+		cfg.SuggestNames = *fc.Query.SuggestNames
+	}
+	if fc.Query.MaxExecTimeHint != nil {
+		cfg.MaxExecTimeHint = *fc.Query.MaxExecTimeHint
+	}
+	if fc.Query.KillOnCancel {
+		cfg.KillOnCancel = true
+	}
+	if fc.Query.MaxConcurrentQueries > 0 {
+		cfg.MaxConcurrentQueries = fc.Query.MaxConcurrentQueries
+	}
+	if fc.Query.QueueDepth > 0 {
+		cfg.QueryQueueDepth = fc.Query.QueueDepth
+	}
+	if fc.Query.QueueTimeoutSeconds > 0 {
+		cfg.QueryQueueTimeout = secondsToDuration(fc.Query.QueueTimeoutSeconds)
+	}
+	if fc.Query.DuplicateWindowSeconds > 0 {
+		cfg.DuplicateWindow = secondsToDuration(fc.Query.DuplicateWindowSeconds)
+	}
+	if r := fc.Query.Retry; r != nil {
+		if r.MaxRetries != nil && *r.MaxRetries >= 0 && *r.MaxRetries <= 20 {
+			cfg.DBRetryMaxRetries = *r.MaxRetries
+		}
+		if r.InitialIntervalMs > 0 {
+			cfg.DBRetryInitialInterval = time.Duration(r.InitialIntervalMs) * time.Millisecond
+		}
+		if r.MaxIntervalMs > 0 {
+			cfg.DBRetryMaxInterval = time.Duration(r.MaxIntervalMs) * time.Millisecond
+		}
+	}
+	for name, rows := range fc.Query.DatabaseMaxRows {
+		if name = strings.TrimSpace(name); name != "" && rows > 0 {
+			if cfg.DatabaseMaxRows == nil {
+				cfg.DatabaseMaxRows = map[string]int{}
+			}
+			cfg.DatabaseMaxRows[name] = rows
+		}
+	}
 	if len(fc.Query.MaskColumns) > 0 {
 		var mask []string
 		for _, c := range fc.Query.MaskColumns {
@@ -269,6 +854,31 @@ func (fc *FileConfig) ToConfig() *Config {
 			cfg.MaskColumns = mask
 		}
 	}
+	if len(fc.Query.PseudonymizeColumns) > 0 {
+		var cols []string
+		for _, c := range fc.Query.PseudonymizeColumns {
+			if t := strings.TrimSpace(c); t != "" {
+				cols = append(cols, t)
+			}
+		}
+		if len(cols) > 0 {
+			cfg.PseudonymizeColumns = cols
+		}
+	}
+	if len(fc.Query.PIIColumns) > 0 {
+		var cols []string
+		for _, c := range fc.Query.PIIColumns {
+			if t := strings.TrimSpace(c); t != "" {
+				cols = append(cols, t)
+			}
+		}
+		if len(cols) > 0 {
+			cfg.PIIColumns = cols
+		}
+	}
+	if fc.Query.PseudonymKey != "" {
+		cfg.PseudonymKey = fc.Query.PseudonymKey
+	}
 
 	if fc.Pool.MaxOpenConns > 0 {
 		cfg.MaxOpenConns = fc.Pool.MaxOpenConns
@@ -285,10 +895,34 @@ func (fc *FileConfig) ToConfig() *Config {
 	if fc.Pool.PingTimeoutSeconds > 0 {
 		cfg.PingTimeout = secondsToDuration(fc.Pool.PingTimeoutSeconds)
 	}
+	if fc.Pool.PreparedStatements != nil {
+		cfg.PreparedStatements = *fc.Pool.PreparedStatements
+	}
+	if fc.Pool.CircuitThreshold != nil && *fc.Pool.CircuitThreshold >= 0 {
+		cfg.CircuitThreshold = *fc.Pool.CircuitThreshold
+	}
+	if fc.Pool.CircuitCooldownSeconds > 0 {
+		cfg.CircuitCooldown = secondsToDuration(fc.Pool.CircuitCooldownSeconds)
+	}
+	if fc.Pool.KeepaliveSeconds > 0 {
+		cfg.KeepaliveInterval = secondsToDuration(fc.Pool.KeepaliveSeconds)
+	}
+	if fc.Pool.ReapIdleSessionsSeconds > 0 {
+		cfg.ReapIdleSessionsAfter = secondsToDuration(fc.Pool.ReapIdleSessionsSeconds)
+	}
 
 	cfg.ExtendedMode = fc.Features.ExtendedTools
 	cfg.VectorMode = fc.Features.VectorTools
+	cfg.VectorWrite = fc.Features.VectorWrite
 	cfg.TokenCard = fc.Features.TokenCard
+	for name, on := range fc.Features.Tools {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			if cfg.ToolFlags == nil {
+				cfg.ToolFlags = map[string]bool{}
+			}
+			cfg.ToolFlags[name] = on
+		}
+	}
 
 	if len(fc.Security.AllowedDatabases) > 0 {
 		cfg.AllowedDatabases = append([]string(nil), fc.Security.AllowedDatabases...)
@@ -305,9 +939,178 @@ func (fc *FileConfig) ToConfig() *Config {
 	if fc.Security.SlowQueryTool {
 		cfg.SlowQueryTool = true
 	}
+	if fc.Security.SessionsTool {
+		cfg.SessionsTool = true
+	}
+	if len(fc.Security.ConfirmRequired) > 0 {
+		cfg.ConfirmRequired = append([]string(nil), fc.Security.ConfirmRequired...)
+	}
+	cfg.VirtualConnection = strings.TrimSpace(fc.Security.VirtualConnection)
+	if fc.Security.SaveQueryTool {
+		cfg.SaveQueryTool = true
+	}
+
+	queryNames := make([]string, 0, len(fc.SavedQueries))
+	for name := range fc.SavedQueries {
+		queryNames = append(queryNames, name)
+	}
+	sort.Strings(queryNames)
+	for _, name := range queryNames {
+		fq := fc.SavedQueries[name]
+		q := SavedQuery{
+			Name:        strings.TrimSpace(name),
+			Description: fq.Description,
+			SQL:         strings.TrimSpace(fq.SQL),
+			Database:    strings.TrimSpace(fq.Database),
+		}
+		for _, p := range fq.Params {
+			q.Params = append(q.Params, SavedQueryParam(p))
+		}
+		cfg.SavedQueries = append(cfg.SavedQueries, q)
+	}
+
+	reportNames := make([]string, 0, len(fc.Reports))
+	for name := range fc.Reports {
+		reportNames = append(reportNames, name)
+	}
+	sort.Strings(reportNames)
+	for _, name := range reportNames {
+		fr := fc.Reports[name]
+		r := Report{
+			Name:        strings.TrimSpace(name),
+			Description: fr.Description,
+			Database:    strings.TrimSpace(fr.Database),
+		}
+		for _, v := range fr.Variables {
+			r.Variables = append(r.Variables, SavedQueryParam(v))
+		}
+		for _, s := range fr.Sections {
+			r.Sections = append(r.Sections, ReportSection(s))
+		}
+		cfg.Reports = append(cfg.Reports, r)
+	}
+
+	scheduleNames := make([]string, 0, len(fc.Schedules))
+	for name := range fc.Schedules {
+		scheduleNames = append(scheduleNames, name)
+	}
+	sort.Strings(scheduleNames)
+	for _, name := range scheduleNames {
+		fs := fc.Schedules[name]
+		cfg.Schedules = append(cfg.Schedules, Schedule{
+			Name:       strings.TrimSpace(name),
+			SavedQuery: strings.TrimSpace(fs.SavedQuery),
+			Cron:       strings.TrimSpace(fs.Cron),
+			Params:     fs.Params,
+			Database:   strings.TrimSpace(fs.Database),
+			Jitter:     secondsToDuration(fs.JitterSeconds),
+		})
+	}
+
+	virtualNames := make([]string, 0, len(fc.VirtualConnections))
+	for name := range fc.VirtualConnections {
+		virtualNames = append(virtualNames, name)
+	}
+	sort.Strings(virtualNames)
+	for _, name := range virtualNames {
+		fv := fc.VirtualConnections[name]
+		vc := VirtualConnection{
+			Name:        strings.TrimSpace(name),
+			Connection:  strings.TrimSpace(fv.Connection),
+			Database:    strings.TrimSpace(fv.Database),
+			Description: fv.Description,
+			Session:     fv.Session,
+		}
+		for _, p := range fv.Schemas {
+			vc.Schemas = append(vc.Schemas, strings.TrimSpace(p))
+		}
+		cfg.VirtualConnections = append(cfg.VirtualConnections, vc)
+	}
+
+	summaryNames := make([]string, 0, len(fc.SummaryTables))
+	for name := range fc.SummaryTables {
+		summaryNames = append(summaryNames, name)
+	}
+	sort.Strings(summaryNames)
+	for _, name := range summaryNames {
+		ft := fc.SummaryTables[name]
+		cfg.SummaryTables = append(cfg.SummaryTables, SummaryTable{
+			Table:           strings.TrimSpace(name),
+			Source:          strings.TrimSpace(ft.Source),
+			Grain:           strings.TrimSpace(ft.Grain),
+			FreshnessColumn: strings.TrimSpace(ft.FreshnessColumn),
+			Description:     ft.Description,
+		})
+	}
+
+	if len(fc.Freshness.Tables) > 0 {
+		cfg.FreshnessColumns = make(map[string]string, len(fc.Freshness.Tables))
+		for name, column := range fc.Freshness.Tables {
+			cfg.FreshnessColumns[strings.TrimSpace(name)] = strings.TrimSpace(column)
+		}
+	}
+	if fc.Freshness.CacheSeconds > 0 {
+		cfg.FreshnessCacheTTL = secondsToDuration(fc.Freshness.CacheSeconds)
+	}
+
+	for _, db := range fc.SchemaWatch.Databases {
+		cfg.SchemaWatchDatabases = append(cfg.SchemaWatchDatabases, strings.TrimSpace(db))
+	}
+	if fc.SchemaWatch.IntervalSeconds > 0 {
+		cfg.SchemaWatchInterval = secondsToDuration(fc.SchemaWatch.IntervalSeconds)
+	}
+
+	if len(fc.RBAC.Roles) > 0 {
+		cfg.Roles = make(map[string][]string, len(fc.RBAC.Roles))
+		for role, tools := range fc.RBAC.Roles {
+			cfg.Roles[strings.TrimSpace(role)] = append([]string(nil), tools...)
+		}
+	}
+	for key, role := range fc.RBAC.APIKeys {
+		if cfg.APIKeys == nil {
+			cfg.APIKeys = map[string]string{}
+		}
+		cfg.APIKeys[strings.TrimSpace(key)] = strings.TrimSpace(role)
+	}
+	for client, role := range fc.RBAC.Clients {
+		if cfg.ClientRoles == nil {
+			cfg.ClientRoles = map[string]string{}
+		}
+		cfg.ClientRoles[strings.TrimSpace(client)] = strings.TrimSpace(role)
+	}
+	cfg.DefaultRole = strings.TrimSpace(fc.RBAC.DefaultRole)
+
+	cfg.Quota = Quota{MaxQueriesPerHour: fc.Quotas.MaxQueriesPerHour, MaxRowsPerDay: fc.Quotas.MaxRowsPerDay}
+	for role, q := range fc.Quotas.Roles {
+		if cfg.QuotaRoles == nil {
+			cfg.QuotaRoles = map[string]Quota{}
+		}
+		cfg.QuotaRoles[strings.TrimSpace(role)] = Quota(q)
+	}
+	cfg.QuotaFile = strings.TrimSpace(fc.Quotas.StateFile)
+
+	cfg.Webhooks = fileWebhooks(fc.Webhooks.Endpoints)
+	cfg.WebhookSlowQuery = secondsToDuration(fc.Webhooks.SlowQuerySeconds)
+	cfg.ExportTargets = fileExportTargets(fc.ExportTargets)
 
 	cfg.JSONLogging = fc.Logging.JSONFormat
+	if v := strings.TrimSpace(fc.Logging.Level); v != "" {
+		cfg.LogLevel = strings.ToLower(v)
+	}
+	for name, level := range fc.Logging.Components {
+		if cfg.ComponentLogLevels == nil {
+			cfg.ComponentLogLevels = map[string]string{}
+		}
+		cfg.ComponentLogLevels[strings.ToLower(strings.TrimSpace(name))] = strings.ToLower(strings.TrimSpace(level))
+	}
+	cfg.LogRedactSQL = fc.Logging.RedactSQL
 	cfg.AuditLogPath = fc.Logging.AuditLogPath
+	if fc.Logging.Audit != nil && strings.TrimSpace(fc.Logging.Audit.Format) != "" {
+		cfg.AuditFormat = strings.ToLower(strings.TrimSpace(fc.Logging.Audit.Format))
+	}
+	if v := strings.TrimSpace(fc.Locale); v != "" {
+		cfg.Locale = strings.ToLower(v)
+	}
 	cfg.TokenTracking = fc.Logging.TokenTracking
 	if strings.TrimSpace(fc.Logging.TokenModel) != "" {
 		cfg.TokenModel = strings.TrimSpace(fc.Logging.TokenModel)
@@ -320,6 +1123,34 @@ func (fc *FileConfig) ToConfig() *Config {
 	if fc.HTTP.RequestTimeoutSeconds > 0 {
 		cfg.HTTPRequestTimeout = secondsToDuration(fc.HTTP.RequestTimeoutSeconds)
 	}
+	if fc.HTTP.StreamMaxRows != nil {
+		cfg.StreamMaxRows = *fc.HTTP.StreamMaxRows
+	}
+	cfg.BookmarksFile = strings.TrimSpace(fc.HTTP.BookmarksFile)
+	if fc.HTTP.JobTimeoutSeconds > 0 {
+		cfg.JobTimeout = secondsToDuration(fc.HTTP.JobTimeoutSeconds)
+	}
+	if fc.HTTP.JobRetentionSeconds > 0 {
+		cfg.JobRetention = secondsToDuration(fc.HTTP.JobRetentionSeconds)
+	}
+	if fc.HTTP.JobStoreBytes > 0 {
+		cfg.JobStoreBytes = fc.HTTP.JobStoreBytes
+	}
+	if fc.HTTP.Compression != nil {
+		if fc.HTTP.Compression.Enabled != nil {
+			cfg.Compression = *fc.HTTP.Compression.Enabled
+		}
+		if fc.HTTP.Compression.MinBytes != nil {
+			cfg.CompressionMinBytes = *fc.HTTP.Compression.MinBytes
+		}
+	}
+
+	if fc.MetricsHistory.SampleSeconds > 0 {
+		cfg.MetricsSampleInterval = secondsToDuration(fc.MetricsHistory.SampleSeconds)
+	}
+	if fc.MetricsHistory.HistorySize > 0 {
+		cfg.MetricsHistorySize = fc.MetricsHistory.HistorySize
+	}
 
 	// Only apply rate limit settings from file if the section is present.
 	if fc.HTTP.RateLimit != nil {
@@ -358,6 +1189,24 @@ func (fc *FileConfig) ToConfig() *Config {
 			Description: conn.Description,
 			ReadOnly:    conn.ReadOnly,
 			SSL:         conn.SSL,
+			Environment: strings.TrimSpace(conn.Environment),
+			Tags:        conn.Tags,
+
+			MaxConcurrentQueries: conn.MaxConcurrentQueries,
+			ConnectOnDemand:      conn.ConnectOnDemand,
+
+			Socket:         conn.Socket,
+			Compress:       conn.Compress,
+			Collation:      conn.Collation,
+			ConnectTimeout: secondsToDuration(conn.ConnectTimeoutSeconds),
+			ReadTimeout:    secondsToDuration(conn.ReadTimeoutSeconds),
+			WriteTimeout:   secondsToDuration(conn.WriteTimeoutSeconds),
+
+			Replicas:       conn.Replicas,
+			ReplicaRouting: strings.ToLower(strings.TrimSpace(conn.ReplicaRouting)),
+			PinReplica:     conn.PinReplica,
+
+			RowPolicies: conn.RowPolicies,
 		}
 		if conn.SSH != nil && (conn.SSH.Host != "" || conn.SSH.User != "" || conn.SSH.KeyPath != "") {
 			cc.SSH = &SSHConfig{
@@ -381,9 +1230,35 @@ func PrintConfig(cfg *Config) string {
 	fc := &FileConfig{
 		Connections: make(map[string]FileConnectionConfig),
 		Query: FileQueryConfig{
-			MaxRows:        cfg.MaxRows,
-			TimeoutSeconds: int(cfg.QueryTimeout.Seconds()),
-			MaskColumns:    cfg.MaskColumns,
+			MaxRows:         cfg.MaxRows,
+			MaxResultBytes:  cfg.MaxResultBytes,
+			BinaryOutput:    cfg.BinaryOutput,
+			IdentifierCase:  cfg.IdentifierCase,
+			ProxyCompat:     cfg.ProxyCompat,
+			TimeZone:        cfg.TimeZone,
+			TimeoutSeconds:  int(cfg.QueryTimeout.Seconds()),
+			MaskColumns:     cfg.MaskColumns,
+			InjectLimit:     &cfg.InjectLimit,
+			SuggestNames:    &cfg.SuggestNames,
+			MaxExecTimeHint: &cfg.MaxExecTimeHint,
+
+			PseudonymizeColumns: cfg.PseudonymizeColumns,
+			PIIColumns:          cfg.PIIColumns,
+			KillOnCancel:        cfg.KillOnCancel,
+			DatabaseMaxRows:     cfg.DatabaseMaxRows,
+
+			MaxConcurrentQueries: cfg.MaxConcurrentQueries,
+			QueueDepth:           cfg.QueryQueueDepth,
+			QueueTimeoutSeconds:  int(cfg.QueryQueueTimeout.Seconds()),
+
+			DuplicateWindowSeconds: int(cfg.DuplicateWindow.Seconds()),
+
+			Retry: &FileRetryConfig{
+				MaxRetries:        &cfg.DBRetryMaxRetries,
+				InitialIntervalMs: int(cfg.DBRetryInitialInterval.Milliseconds()),
+				MaxIntervalMs:     int(cfg.DBRetryMaxInterval.Milliseconds()),
+			},
+			PlanBaselineFile: cfg.PlanBaselineFile,
 		},
 		Pool: FilePoolConfig{
 			MaxOpenConns:           cfg.MaxOpenConns,
@@ -391,11 +1266,19 @@ func PrintConfig(cfg *Config) string {
 			ConnMaxLifetimeMinutes: int(cfg.ConnMaxLifetime.Minutes()),
 			ConnMaxIdleTimeMinutes: int(cfg.ConnMaxIdleTime.Minutes()),
 			PingTimeoutSeconds:     int(cfg.PingTimeout.Seconds()),
+			PreparedStatements:     &cfg.PreparedStatements,
+			CircuitThreshold:       &cfg.CircuitThreshold,
+			CircuitCooldownSeconds: int(cfg.CircuitCooldown.Seconds()),
+
+			KeepaliveSeconds:        int(cfg.KeepaliveInterval.Seconds()),
+			ReapIdleSessionsSeconds: int(cfg.ReapIdleSessionsAfter.Seconds()),
 		},
 		Features: FileFeatureConfig{
 			ExtendedTools: cfg.ExtendedMode,
 			VectorTools:   cfg.VectorMode,
+			VectorWrite:   cfg.VectorWrite,
 			TokenCard:     cfg.TokenCard,
+			Tools:         cfg.ToolFlags,
 		},
 		Security: FileSecurityConfig{
 			AllowedDatabases: cfg.AllowedDatabases,
@@ -403,10 +1286,19 @@ func PrintConfig(cfg *Config) string {
 			ProcessAdmin:     cfg.ProcessAdmin,
 			ReadAuditTool:    cfg.ReadAuditTool,
 			SlowQueryTool:    cfg.SlowQueryTool,
+			SessionsTool:     cfg.SessionsTool,
+			SaveQueryTool:    cfg.SaveQueryTool,
+			ConfirmRequired:  cfg.ConfirmRequired,
+
+			VirtualConnection: cfg.VirtualConnection,
 		},
 		Logging: FileLoggingConfig{
 			JSONFormat:    cfg.JSONLogging,
+			Level:         cfg.LogLevel,
+			Components:    cfg.ComponentLogLevels,
+			RedactSQL:     cfg.LogRedactSQL,
 			AuditLogPath:  cfg.AuditLogPath,
+			Audit:         &FileAuditConfig{Format: cfg.AuditFormat},
 			TokenTracking: cfg.TokenTracking,
 			TokenModel:    cfg.TokenModel,
 		},
@@ -414,12 +1306,81 @@ func PrintConfig(cfg *Config) string {
 			Enabled:               cfg.HTTPMode,
 			Port:                  cfg.HTTPPort,
 			RequestTimeoutSeconds: int(cfg.HTTPRequestTimeout.Seconds()),
+			StreamMaxRows:         &cfg.StreamMaxRows,
+			BookmarksFile:         cfg.BookmarksFile,
+			JobTimeoutSeconds:     int(cfg.JobTimeout.Seconds()),
+			JobRetentionSeconds:   int(cfg.JobRetention.Seconds()),
+			JobStoreBytes:         cfg.JobStoreBytes,
+			Compression: &FileCompressionConfig{
+				Enabled:  &cfg.Compression,
+				MinBytes: &cfg.CompressionMinBytes,
+			},
 			RateLimit: &FileRateLimitConfig{
 				Enabled: &cfg.RateLimitEnabled,
 				RPS:     &cfg.RateLimitRPS,
 				Burst:   &cfg.RateLimitBurst,
 			},
 		},
+		MetricsHistory: FileMetricsHistoryConfig{
+			SampleSeconds: int(cfg.MetricsSampleInterval.Seconds()),
+			HistorySize:   cfg.MetricsHistorySize,
+		},
+		RBAC: FileRBACConfig{
+			DefaultRole: cfg.DefaultRole,
+			Roles:       cfg.Roles,
+			Clients:     cfg.ClientRoles,
+		},
+		Locale: cfg.Locale,
+	}
+	if cfg.PseudonymKey != "" {
+		fc.Query.PseudonymKey = "***"
+	}
+	fc.Quotas = FileQuotasConfig{
+		MaxQueriesPerHour: cfg.Quota.MaxQueriesPerHour,
+		MaxRowsPerDay:     cfg.Quota.MaxRowsPerDay,
+		StateFile:         cfg.QuotaFile,
+	}
+	for role, q := range cfg.QuotaRoles {
+		if fc.Quotas.Roles == nil {
+			fc.Quotas.Roles = make(map[string]FileQuota)
+		}
+		fc.Quotas.Roles[role] = FileQuota(q)
+	}
+	fc.Webhooks.SlowQuerySeconds = int(cfg.WebhookSlowQuery.Seconds())
+	for _, w := range cfg.Webhooks {
+		e := FileWebhookEndpoint{URL: MaskWebhookURL(w.URL), Events: w.Events}
+		if w.Secret != "" {
+			e.Secret = "***"
+		}
+		fc.Webhooks.Endpoints = append(fc.Webhooks.Endpoints, e)
+	}
+	for _, t := range cfg.ExportTargets {
+		if fc.ExportTargets == nil {
+			fc.ExportTargets = make(map[string]FileExportTarget)
+		}
+		ft := FileExportTarget{
+			Type:             t.Type,
+			Bucket:           t.Bucket,
+			Prefix:           t.Prefix,
+			Region:           t.Region,
+			Endpoint:         t.Endpoint,
+			AccessKeyID:      t.AccessKeyID,
+			Account:          t.Account,
+			URLExpirySeconds: int(t.URLExpiry.Seconds()),
+		}
+		if t.SecretAccessKey != "" {
+			ft.SecretAccessKey = "***"
+		}
+		if t.AccountKey != "" {
+			ft.AccountKey = "***"
+		}
+		fc.ExportTargets[t.Name] = ft
+	}
+	for key, role := range cfg.APIKeys {
+		if fc.RBAC.APIKeys == nil {
+			fc.RBAC.APIKeys = make(map[string]string)
+		}
+		fc.RBAC.APIKeys[MaskAPIKey(key)] = role
 	}
 
 	for _, conn := range cfg.Connections {
@@ -428,6 +1389,26 @@ func PrintConfig(cfg *Config) string {
 			Description: conn.Description,
 			ReadOnly:    conn.ReadOnly,
 			SSL:         conn.SSL,
+			Environment: conn.Environment,
+			Tags:        conn.Tags,
+
+			MaxConcurrentQueries: conn.MaxConcurrentQueries,
+			ConnectOnDemand:      conn.ConnectOnDemand,
+
+			Socket:                conn.Socket,
+			Compress:              conn.Compress,
+			Collation:             conn.Collation,
+			ConnectTimeoutSeconds: int(conn.ConnectTimeout.Seconds()),
+			ReadTimeoutSeconds:    int(conn.ReadTimeout.Seconds()),
+			WriteTimeoutSeconds:   int(conn.WriteTimeout.Seconds()),
+
+			ReplicaRouting: conn.ReplicaRouting,
+			PinReplica:     conn.PinReplica,
+
+			RowPolicies: conn.RowPolicies,
+		}
+		for _, dsn := range conn.Replicas {
+			fcc.Replicas = append(fcc.Replicas, maskDSN(dsn))
 		}
 		if conn.SSH != nil {
 			fcc.SSH = &FileSSHConfig{
@@ -443,10 +1424,82 @@ func PrintConfig(cfg *Config) string {
 		fc.Connections[conn.Name] = fcc
 	}
 
+	for _, q := range cfg.SavedQueries {
+		if fc.SavedQueries == nil {
+			fc.SavedQueries = make(map[string]FileSavedQuery)
+		}
+		fq := FileSavedQuery{Description: q.Description, SQL: q.SQL, Database: q.Database}
+		for _, p := range q.Params {
+			fq.Params = append(fq.Params, FileSavedQueryParam(p))
+		}
+		fc.SavedQueries[q.Name] = fq
+	}
+	for _, r := range cfg.Reports {
+		if fc.Reports == nil {
+			fc.Reports = make(map[string]FileReport)
+		}
+		fr := FileReport{Description: r.Description, Database: r.Database}
+		for _, v := range r.Variables {
+			fr.Variables = append(fr.Variables, FileSavedQueryParam(v))
+		}
+		for _, s := range r.Sections {
+			fr.Sections = append(fr.Sections, FileReportSection(s))
+		}
+		fc.Reports[r.Name] = fr
+	}
+	for _, sc := range cfg.Schedules {
+		if fc.Schedules == nil {
+			fc.Schedules = make(map[string]FileSchedule)
+		}
+		fc.Schedules[sc.Name] = FileSchedule{
+			SavedQuery:    sc.SavedQuery,
+			Cron:          sc.Cron,
+			Params:        sc.Params,
+			Database:      sc.Database,
+			JitterSeconds: int(sc.Jitter.Seconds()),
+		}
+	}
+	for _, vc := range cfg.VirtualConnections {
+		if fc.VirtualConnections == nil {
+			fc.VirtualConnections = make(map[string]FileVirtualConnection)
+		}
+		fc.VirtualConnections[vc.Name] = FileVirtualConnection{
+			Connection:  vc.Connection,
+			Database:    vc.Database,
+			Schemas:     vc.Schemas,
+			Description: vc.Description,
+			Session:     vc.Session,
+		}
+	}
+	for _, st := range cfg.SummaryTables {
+		if fc.SummaryTables == nil {
+			fc.SummaryTables = make(map[string]FileSummaryTable)
+		}
+		fc.SummaryTables[st.Table] = FileSummaryTable{
+			Source:          st.Source,
+			Grain:           st.Grain,
+			FreshnessColumn: st.FreshnessColumn,
+			Description:     st.Description,
+		}
+	}
+
+	fc.Freshness = FileFreshnessConfig{CacheSeconds: int(cfg.FreshnessCacheTTL.Seconds()), Tables: cfg.FreshnessColumns}
+	if len(cfg.SchemaWatchDatabases) > 0 {
+		fc.SchemaWatch = FileSchemaWatchConfig{Databases: cfg.SchemaWatchDatabases, IntervalSeconds: int(cfg.SchemaWatchInterval.Seconds())}
+	}
+
 	data, _ := yaml.Marshal(fc)
 	return string(data)
 }
 
+// MaskAPIKey hides all but the last four characters of an API key.
+func MaskAPIKey(key string) string {
+	if len(key) <= 4 {
+		return "***"
+	}
+	return "***" + key[len(key)-4:]
+}
+
 // maskDSN masks the password in a DSN for safe printing.
 func maskDSN(dsn string) string {
 	// Simple masking: replace password with ***
@@ -515,6 +1568,53 @@ func ApplySSLToDSN(dsn, ssl string) string {
 	return dsn + "?tls=" + tlsValue
 }
 
+// ApplyConnectionOptionsToDSN applies the structured driver options of conn
+// (unix socket, compression, collation and dial/read/write timeouts) to dsn.
+// Options left unset keep the DSN's own values; a DSN without any options set
+// is returned unchanged.
+func ApplyConnectionOptionsToDSN(dsn string, conn ConnectionConfig) (string, error) {
+	if conn.Socket == "" && !conn.Compress && conn.Collation == "" &&
+		conn.ConnectTimeout == 0 && conn.ReadTimeout == 0 && conn.WriteTimeout == 0 {
+		return dsn, nil
+	}
+	mysqlCfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	if conn.Socket != "" {
+		mysqlCfg.Net = "unix"
+		mysqlCfg.Addr = conn.Socket
+	}
+	if conn.Compress {
+		if err := mysqlCfg.Apply(mysql.EnableCompression(true)); err != nil {
+			return "", err
+		}
+	}
+	if conn.Collation != "" {
+		mysqlCfg.Collation = conn.Collation
+	}
+	if conn.ConnectTimeout > 0 {
+		mysqlCfg.Timeout = conn.ConnectTimeout
+	}
+	if conn.ReadTimeout > 0 {
+		mysqlCfg.ReadTimeout = conn.ReadTimeout
+	}
+	if conn.WriteTimeout > 0 {
+		mysqlCfg.WriteTimeout = conn.WriteTimeout
+	}
+	return mysqlCfg.FormatDSN(), nil
+}
+
+// validCollationName accepts collation names such as utf8mb4_0900_ai_ci.
+func validCollationName(name string) bool {
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return name != ""
+}
+
 func secondsToDuration(s int) time.Duration {
 	return time.Duration(s) * time.Second
 }
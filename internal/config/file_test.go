@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -30,6 +31,7 @@ pool:
 features:
   extended_tools: true
   vector_tools: true
+  vector_write: true
 
 logging:
   json_format: true
@@ -104,6 +106,9 @@ http:
 	if !cfg.Features.VectorTools {
 		t.Error("expected vector_tools true")
 	}
+	if !cfg.Features.VectorWrite {
+		t.Error("expected vector_write true")
+	}
 
 	// Verify logging
 	if !cfg.Logging.JSONFormat {
@@ -209,6 +214,7 @@ func TestFileConfigToConfig(t *testing.T) {
 		Features: FileFeatureConfig{
 			ExtendedTools: true,
 			VectorTools:   false,
+			Tools:         map[string]bool{" List_Variables ": false, "vector_search": true},
 		},
 		Logging: FileLoggingConfig{
 			JSONFormat:    true,
@@ -220,6 +226,10 @@ func TestFileConfigToConfig(t *testing.T) {
 			Enabled:               true,
 			Port:                  9000,
 			RequestTimeoutSeconds: 90,
+			Compression: &FileCompressionConfig{
+				Enabled:  func(b bool) *bool { return &b }(false),
+				MinBytes: func(i int) *int { return &i }(4096),
+			},
 			RateLimit: &FileRateLimitConfig{
 				Enabled: func(b bool) *bool { return &b }(true),
 				RPS:     func(f float64) *float64 { return &f }(75),
@@ -270,6 +280,9 @@ func TestFileConfigToConfig(t *testing.T) {
 	if cfg.VectorMode {
 		t.Error("expected VectorMode false")
 	}
+	if on, ok := cfg.ToolFlags["list_variables"]; !ok || on || !cfg.ToolFlags["vector_search"] {
+		t.Errorf("unexpected ToolFlags: %v", cfg.ToolFlags)
+	}
 
 	// Verify logging
 	if !cfg.JSONLogging {
@@ -298,6 +311,9 @@ func TestFileConfigToConfig(t *testing.T) {
 	if cfg.RateLimitRPS != 75 {
 		t.Errorf("expected RateLimitRPS 75, got %f", cfg.RateLimitRPS)
 	}
+	if cfg.Compression || cfg.CompressionMinBytes != 4096 {
+		t.Errorf("expected compression off with min 4096, got %v %d", cfg.Compression, cfg.CompressionMinBytes)
+	}
 }
 
 // TestMinimalConfigDefaults verifies that a minimal config file (connections only)
@@ -373,6 +389,560 @@ func TestMinimalConfigDefaults(t *testing.T) {
 	if cfg.RateLimitBurst != DefaultRateLimitBurst {
 		t.Errorf("expected RateLimitBurst %d, got %d", DefaultRateLimitBurst, cfg.RateLimitBurst)
 	}
+	if !cfg.Compression || cfg.CompressionMinBytes != DefaultCompressionMinBytes {
+		t.Errorf("expected compression on above %d bytes, got %v %d", DefaultCompressionMinBytes, cfg.Compression, cfg.CompressionMinBytes)
+	}
+	if !cfg.InjectLimit {
+		t.Error("expected InjectLimit to default to true")
+	}
+	if !cfg.PreparedStatements {
+		t.Error("expected PreparedStatements to default to true")
+	}
+}
+
+func TestFileConfigLimitInjection(t *testing.T) {
+	off := false
+	fc := &FileConfig{
+		Connections: map[string]FileConnectionConfig{"default": {DSN: "user:pass@tcp(localhost:3306)/db"}},
+		Query: FileQueryConfig{
+			InjectLimit:     &off,
+			DatabaseMaxRows: map[string]int{"analytics": 1000, " ": 5, "logs": 0},
+		},
+	}
+
+	cfg := fc.ToConfig()
+	if cfg.InjectLimit {
+		t.Error("expected inject_limit: false to disable injection")
+	}
+	if len(cfg.DatabaseMaxRows) != 1 || cfg.DatabaseMaxRows["analytics"] != 1000 {
+		t.Errorf("unexpected DatabaseMaxRows: %v", cfg.DatabaseMaxRows)
+	}
+}
+
+func TestFileConfigSavedQueries(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+security:
+  save_query_tool: true
+saved_queries:
+  orders_by_customer:
+    description: "Recent orders for one customer"
+    database: shop
+    sql: "SELECT id, total FROM orders WHERE customer_id = :customer_id ORDER BY id DESC LIMIT :limit"
+    params:
+      - name: customer_id
+        type: int
+        required: true
+      - name: limit
+        type: int
+        default: 20
+  active_users:
+    sql: "SELECT COUNT(*) FROM users WHERE active = 1"
+`
+	path := filepath.Join(t.TempDir(), "saved.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fc.ToConfig()
+	if !cfg.SaveQueryTool || len(cfg.SavedQueries) != 2 {
+		t.Fatalf("unexpected saved query config: tool=%v queries=%+v", cfg.SaveQueryTool, cfg.SavedQueries)
+	}
+	q := cfg.SavedQueries[1]
+	if q.Name != "orders_by_customer" || q.Database != "shop" || len(q.Params) != 2 {
+		t.Fatalf("unexpected saved query: %+v", q)
+	}
+	if !q.Params[0].Required || q.Params[1].Type != "int" || q.Params[1].Default != 20 {
+		t.Errorf("unexpected params: %+v", q.Params)
+	}
+
+	if !strings.Contains(PrintConfig(cfg), "orders_by_customer") {
+		t.Error("expected PrintConfig to include saved queries")
+	}
+
+	emptySQL := filepath.Join(t.TempDir(), "empty_sql.yaml")
+	if err := os.WriteFile(emptySQL, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nsaved_queries:\n  broken:\n    sql: \"\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(emptySQL); err == nil {
+		t.Error("expected error for saved query with empty sql")
+	}
+}
+
+func TestFileConfigReports(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+reports:
+  daily_sales:
+    description: "Sales summary for one day"
+    database: shop
+    variables:
+      - name: day
+        type: date
+        required: true
+    sections:
+      - name: totals
+        sql: "SELECT COUNT(*), SUM(total) FROM orders WHERE DATE(created_at) = :day"
+      - name: top_customers
+        saved_query: top_customers
+        max_rows: 10
+`
+	path := filepath.Join(t.TempDir(), "reports.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fc.ToConfig()
+	if len(cfg.Reports) != 1 {
+		t.Fatalf("unexpected reports: %+v", cfg.Reports)
+	}
+	r := cfg.Reports[0]
+	if r.Name != "daily_sales" || r.Database != "shop" || len(r.Variables) != 1 || r.Variables[0].Type != "date" {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+	if len(r.Sections) != 2 || r.Sections[1].SavedQuery != "top_customers" || r.Sections[1].MaxRows != 10 {
+		t.Errorf("unexpected sections: %+v", r.Sections)
+	}
+	if !strings.Contains(PrintConfig(cfg), "daily_sales") {
+		t.Error("expected PrintConfig to include reports")
+	}
+
+	both := filepath.Join(t.TempDir(), "both.yaml")
+	if err := os.WriteFile(both, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nreports:\n  r:\n    sections:\n      - name: a\n        sql: \"SELECT 1\"\n        saved_query: q\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(both); err == nil {
+		t.Error("expected error for section with both sql and saved_query")
+	}
+}
+
+func TestFileConfigSummaryTables(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+summary_tables:
+  analytics.sales_by_store:
+    source: sales
+    grain: daily
+    freshness_column: refreshed_at
+    description: "Revenue per store and day"
+`
+	path := filepath.Join(t.TempDir(), "summary.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if len(cfg.SummaryTables) != 1 {
+		t.Fatalf("unexpected summary tables: %+v", cfg.SummaryTables)
+	}
+	st := cfg.SummaryTables[0]
+	if st.Table != "analytics.sales_by_store" || st.Source != "sales" || st.Grain != "daily" || st.FreshnessColumn != "refreshed_at" {
+		t.Errorf("unexpected summary table: %+v", st)
+	}
+	if !strings.Contains(PrintConfig(cfg), "analytics.sales_by_store") {
+		t.Error("expected PrintConfig to include summary tables")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(bad, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nsummary_tables:\n  sales_by_store:\n    source: sales\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(bad); err == nil || !strings.Contains(err.Error(), "database.table") {
+		t.Errorf("expected database.table error, got %v", err)
+	}
+}
+
+func TestFileConfigVirtualConnections(t *testing.T) {
+	content := `
+connections:
+  shared:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+virtual_connections:
+  tenant_1234:
+    connection: shared
+    database: tenant_1234_app
+    schemas: [tenant_1234_%]
+    description: "Acme Corp"
+security:
+  virtual_connection: tenant_1234
+`
+	path := filepath.Join(t.TempDir(), "virtual.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if len(cfg.VirtualConnections) != 1 || cfg.VirtualConnection != "tenant_1234" {
+		t.Fatalf("unexpected virtual connections: %+v (lock %q)", cfg.VirtualConnections, cfg.VirtualConnection)
+	}
+	vc := cfg.VirtualConnections[0]
+	if vc.Name != "tenant_1234" || vc.Connection != "shared" || vc.Database != "tenant_1234_app" || len(vc.Schemas) != 1 {
+		t.Errorf("unexpected virtual connection: %+v", vc)
+	}
+	out := PrintConfig(cfg)
+	if !strings.Contains(out, "tenant_1234_%") || !strings.Contains(out, "virtual_connection: tenant_1234") {
+		t.Errorf("PrintConfig missing virtual connections:\n%s", out)
+	}
+
+	bad := map[string]string{
+		"no schemas":       "virtual_connections:\n  t1:\n    database: t1_app\n",
+		"database outside": "virtual_connections:\n  t1:\n    database: other\n    schemas: [t1_%]\n",
+		"bad pattern":      "virtual_connections:\n  t1:\n    schemas: [\"t1 %\"]\n",
+		"name clash":       "virtual_connections:\n  shared:\n    schemas: [t1_%]\n",
+		"unknown lock":     "virtual_connections:\n  t1:\n    schemas: [t1_%]\nsecurity:\n  virtual_connection: t2\n",
+	}
+	for name, body := range bad {
+		path := filepath.Join(t.TempDir(), "bad.yaml")
+		if err := os.WriteFile(path, []byte("connections:\n  shared:\n    dsn: \"u:p@tcp(h:3306)/db\"\n"+body), 0644); err != nil {
+			t.Fatalf("failed to write temp file: %v", err)
+		}
+		if err := ValidateConfigFile(path); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestFileConfigRowPolicies(t *testing.T) {
+	content := `
+connections:
+  shared:
+    dsn: "user:pass@tcp(localhost:3306)/shop"
+    row_policies:
+      orders: "tenant_id = {session.tenant}"
+      shop.invoices: "tenant_id = {session.tenant} AND deleted = 0"
+virtual_connections:
+  acme:
+    connection: shared
+    schemas: [shop]
+    session:
+      tenant: "1234"
+`
+	path := filepath.Join(t.TempDir(), "rls.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if got := cfg.Connections[0].RowPolicies["orders"]; got != "tenant_id = {session.tenant}" {
+		t.Errorf("unexpected row policy: %q", got)
+	}
+	if cfg.VirtualConnections[0].Session["tenant"] != "1234" {
+		t.Errorf("unexpected session: %+v", cfg.VirtualConnections[0].Session)
+	}
+	if out := PrintConfig(cfg); !strings.Contains(out, "row_policies:") || !strings.Contains(out, "tenant: \"1234\"") {
+		t.Errorf("PrintConfig missing row policies:\n%s", out)
+	}
+
+	for name, policy := range map[string]string{
+		"too many dots":   "a.b.c: \"x = 1\"",
+		"empty predicate": "orders: \"\"",
+	} {
+		path := filepath.Join(t.TempDir(), "bad.yaml")
+		body := "connections:\n  shared:\n    dsn: \"u:p@tcp(h:3306)/db\"\n    row_policies:\n      " + policy + "\n"
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatalf("failed to write temp file: %v", err)
+		}
+		if err := ValidateConfigFile(path); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestFileConfigRBAC(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+rbac:
+  default_role: analyst
+  roles:
+    analyst: [core]
+    dba: [core, extended]
+  api_keys:
+    s3cr3t-dba-key: dba
+  clients:
+    claude-desktop: dba
+`
+	path := filepath.Join(t.TempDir(), "rbac.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fc.ToConfig()
+	if cfg.DefaultRole != "analyst" || len(cfg.Roles["dba"]) != 2 || cfg.APIKeys["s3cr3t-dba-key"] != "dba" || cfg.ClientRoles["claude-desktop"] != "dba" {
+		t.Fatalf("unexpected rbac config: %+v %+v %+v %q", cfg.Roles, cfg.APIKeys, cfg.ClientRoles, cfg.DefaultRole)
+	}
+	printed := PrintConfig(cfg)
+	if strings.Contains(printed, "s3cr3t-dba-key") || !strings.Contains(printed, "***-key") {
+		t.Errorf("expected PrintConfig to mask API keys:\n%s", printed)
+	}
+
+	unknown := filepath.Join(t.TempDir(), "unknown_role.yaml")
+	if err := os.WriteFile(unknown, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nrbac:\n  roles:\n    analyst: [core]\n  api_keys:\n    k: admin\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(unknown); err == nil {
+		t.Error("expected error for api key with unknown role")
+	}
+}
+
+func TestFileConfigWebhooks(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+webhooks:
+  slow_query_seconds: 10
+  endpoints:
+    - url: https://hooks.example.com/services/T000/B000/token
+      secret: s3cret
+      events: [Query_Blocked, connection_unhealthy]
+    - url: https://pager.example.com/events
+`
+	path := filepath.Join(t.TempDir(), "webhooks.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fc.ToConfig()
+	if cfg.WebhookSlowQuery != 10*time.Second || len(cfg.Webhooks) != 2 {
+		t.Fatalf("unexpected webhooks: %+v, slow = %s", cfg.Webhooks, cfg.WebhookSlowQuery)
+	}
+	if w := cfg.Webhooks[0]; w.Secret != "s3cret" || len(w.Events) != 2 || w.Events[0] != WebhookEventQueryBlocked {
+		t.Errorf("unexpected first webhook: %+v", w)
+	}
+	printed := PrintConfig(cfg)
+	if strings.Contains(printed, "s3cret") || strings.Contains(printed, "B000") {
+		t.Errorf("expected PrintConfig to mask webhook secrets and paths:\n%s", printed)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad_event.yaml")
+	if err := os.WriteFile(bad, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nwebhooks:\n  endpoints:\n    - url: https://h.example.com/x\n      events: [everything]\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(bad); err == nil {
+		t.Error("expected error for an unknown webhook event")
+	}
+}
+
+func TestFileConfigSchedules(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+schedules:
+  stuck_orders:
+    saved_query: orders_by_status
+    cron: "*/15 * * * *"
+    params:
+      status: pending
+    jitter_seconds: 30
+  nightly:
+    saved_query: daily_totals
+    cron: "@daily"
+`
+	path := filepath.Join(t.TempDir(), "schedules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := fc.ToConfig()
+	if len(cfg.Schedules) != 2 || cfg.Schedules[0].Name != "nightly" {
+		t.Fatalf("unexpected schedules: %+v", cfg.Schedules)
+	}
+	if s := cfg.Schedules[1]; s.SavedQuery != "orders_by_status" || s.Cron != "*/15 * * * *" || s.Jitter != 30*time.Second || s.Params["status"] != "pending" {
+		t.Errorf("unexpected schedule: %+v", s)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad_cron.yaml")
+	if err := os.WriteFile(bad, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nschedules:\n  x:\n    saved_query: q\n    cron: \"every minute\"\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(bad); err == nil {
+		t.Error("expected error for an invalid cron expression")
+	}
+}
+
+func TestFileConfigFreshness(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+freshness:
+  cache_seconds: 300
+  tables:
+    shop.orders: created_at
+`
+	path := filepath.Join(t.TempDir(), "freshness.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if cfg.FreshnessColumns["shop.orders"] != "created_at" || cfg.FreshnessCacheTTL != 5*time.Minute {
+		t.Errorf("unexpected freshness settings: %v, ttl = %s", cfg.FreshnessColumns, cfg.FreshnessCacheTTL)
+	}
+	if ttl := (&FileConfig{}).ToConfig().FreshnessCacheTTL; ttl != DefaultFreshnessCacheS*time.Second {
+		t.Errorf("expected the default cache TTL, got %s", ttl)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad_freshness.yaml")
+	if err := os.WriteFile(bad, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nfreshness:\n  tables:\n    orders: created_at\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(bad); err == nil {
+		t.Error("expected error for a table without a database")
+	}
+}
+
+func TestFileConfigSchemaWatch(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+schema_watch:
+  databases: [shop, " billing "]
+  interval_seconds: 30
+webhooks:
+  endpoints:
+    - url: https://hooks.example.com/x
+      events: [schema_changed]
+`
+	path := filepath.Join(t.TempDir(), "schema_watch.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if len(cfg.SchemaWatchDatabases) != 2 || cfg.SchemaWatchDatabases[1] != "billing" || cfg.SchemaWatchInterval != 30*time.Second {
+		t.Errorf("unexpected schema watch settings: %v every %s", cfg.SchemaWatchDatabases, cfg.SchemaWatchInterval)
+	}
+	if d := (&FileConfig{}).ToConfig().SchemaWatchInterval; d != DefaultSchemaWatchS*time.Second {
+		t.Errorf("expected the default interval, got %s", d)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad_schema_watch.yaml")
+	if err := os.WriteFile(bad, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nschema_watch:\n  interval_seconds: -1\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(bad); err == nil {
+		t.Error("expected error for a negative interval")
+	}
+}
+
+func TestFileConfigQuotas(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+rbac:
+  roles:
+    analyst: [core]
+quotas:
+  max_queries_per_hour: 500
+  max_rows_per_day: 100000
+  state_file: /var/lib/mysql-mcp/quotas.json
+  roles:
+    analyst:
+      max_queries_per_hour: 50
+`
+	path := filepath.Join(t.TempDir(), "quotas.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if cfg.Quota != (Quota{MaxQueriesPerHour: 500, MaxRowsPerDay: 100000}) || cfg.QuotaFile != "/var/lib/mysql-mcp/quotas.json" {
+		t.Errorf("unexpected quota settings: %+v, file = %q", cfg.Quota, cfg.QuotaFile)
+	}
+	if q := cfg.QuotaRoles["analyst"]; q != (Quota{MaxQueriesPerHour: 50}) {
+		t.Errorf("unexpected analyst quota: %+v", q)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad_quotas.yaml")
+	if err := os.WriteFile(bad, []byte("connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nquotas:\n  roles:\n    nobody:\n      max_rows_per_day: 10\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(bad); err == nil {
+		t.Error("expected error for a quota of an unknown role")
+	}
 }
 
 func TestValidateConfigFile(t *testing.T) {
@@ -420,6 +990,142 @@ connections:
 	if err := ValidateConfigFile(emptyDSNFile); err == nil {
 		t.Error("expected error for config with empty DSN")
 	}
+
+	// Invalid config - unknown binary output mode
+	binaryContent := validContent + `
+query:
+  binary_output: bytes
+`
+	binaryFile := filepath.Join(t.TempDir(), "binary.yaml")
+	if err := os.WriteFile(binaryFile, []byte(binaryContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(binaryFile); err == nil || !strings.Contains(err.Error(), "binary_output") {
+		t.Errorf("expected binary_output error, got %v", err)
+	}
+
+	// Invalid config - unknown identifier case mode
+	identContent := validContent + `
+query:
+  identifier_case: upper
+`
+	identFile := filepath.Join(t.TempDir(), "ident.yaml")
+	if err := os.WriteFile(identFile, []byte(identContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(identFile); err == nil || !strings.Contains(err.Error(), "identifier_case") {
+		t.Errorf("expected identifier_case error, got %v", err)
+	}
+
+	// Invalid config - malformed time zone
+	tzContent := validContent + `
+query:
+  time_zone: "+25:00"
+`
+	tzFile := filepath.Join(t.TempDir(), "tz.yaml")
+	if err := os.WriteFile(tzFile, []byte(tzContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(tzFile); err == nil || !strings.Contains(err.Error(), "time_zone") {
+		t.Errorf("expected time_zone error, got %v", err)
+	}
+
+	// Invalid config - unknown log component
+	logContent := validContent + `
+logging:
+  level: debug
+  components:
+    parser: debug
+`
+	logFile := filepath.Join(t.TempDir(), "logging.yaml")
+	if err := os.WriteFile(logFile, []byte(logContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(logFile); err == nil || !strings.Contains(err.Error(), "parser") {
+		t.Errorf("expected log component error, got %v", err)
+	}
+
+	// Invalid config - unknown replica routing
+	replicaContent := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+    replicas:
+      - "user:pass@tcp(replica:3306)/db"
+    replica_routing: fastest
+`
+	replicaFile := filepath.Join(t.TempDir(), "replica.yaml")
+	if err := os.WriteFile(replicaFile, []byte(replicaContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(replicaFile); err == nil || !strings.Contains(err.Error(), "replica_routing") {
+		t.Errorf("expected replica_routing error, got %v", err)
+	}
+
+	// Invalid config - unknown audit format
+	auditContent := validContent + `
+logging:
+  audit:
+    format: syslog
+`
+	auditFile := filepath.Join(t.TempDir(), "audit.yaml")
+	if err := os.WriteFile(auditFile, []byte(auditContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(auditFile); err == nil || !strings.Contains(err.Error(), "logging.audit.format") {
+		t.Errorf("expected audit format error, got %v", err)
+	}
+
+	// Invalid config - unsupported locale
+	localeContent := validContent + `
+locale: fr
+`
+	localeFile := filepath.Join(t.TempDir(), "locale.yaml")
+	if err := os.WriteFile(localeFile, []byte(localeContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(localeFile); err == nil || !strings.Contains(err.Error(), "locale") {
+		t.Errorf("expected locale error, got %v", err)
+	}
+
+	// Invalid config - retry count out of range
+	retryContent := validContent + `
+query:
+  retry:
+    max_retries: 50
+`
+	retryFile := filepath.Join(t.TempDir(), "retry.yaml")
+	if err := os.WriteFile(retryFile, []byte(retryContent), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(retryFile); err == nil || !strings.Contains(err.Error(), "query.retry.max_retries") {
+		t.Errorf("expected retry error, got %v", err)
+	}
+}
+
+func TestFileConfigToConfigRetry(t *testing.T) {
+	zero := 0
+	fc := &FileConfig{Query: FileQueryConfig{Retry: &FileRetryConfig{MaxRetries: &zero, InitialIntervalMs: 200, MaxIntervalMs: 3000}}}
+	cfg := fc.ToConfig()
+	if cfg.DBRetryMaxRetries != 0 || cfg.DBRetryInitialInterval != 200*time.Millisecond || cfg.DBRetryMaxInterval != 3*time.Second {
+		t.Errorf("unexpected retry settings: max=%d initial=%v maxInterval=%v",
+			cfg.DBRetryMaxRetries, cfg.DBRetryInitialInterval, cfg.DBRetryMaxInterval)
+	}
+	if def := (&FileConfig{}).ToConfig(); def.DBRetryMaxRetries != 3 {
+		t.Errorf("expected 3 retries by default, got %d", def.DBRetryMaxRetries)
+	}
+}
+
+func TestFileConfigPseudonymization(t *testing.T) {
+	fc := &FileConfig{Query: FileQueryConfig{PseudonymizeColumns: []string{" email ", "", "customer_name"}, PseudonymKey: "s3cret", PIIColumns: []string{"phone"}}}
+	cfg := fc.ToConfig()
+	if len(cfg.PseudonymizeColumns) != 2 || cfg.PseudonymizeColumns[0] != "email" || cfg.PseudonymKey != "s3cret" || len(cfg.PIIColumns) != 1 {
+		t.Errorf("unexpected pseudonymization settings: %q key=%q", cfg.PseudonymizeColumns, cfg.PseudonymKey)
+	}
+	out := PrintConfig(cfg)
+	if contains(out, "s3cret") || !contains(out, "pseudonym_key: '***'") {
+		t.Errorf("expected the pseudonym key to be masked:\n%s", out)
+	}
 }
 
 func TestFindConfigFile(t *testing.T) {
@@ -816,3 +1522,181 @@ connections:
 		t.Error("expected 'secure' connection")
 	}
 }
+
+func TestApplyConnectionOptionsToDSN(t *testing.T) {
+	dsn := "user:pass@tcp(localhost:3306)/db?tls=true"
+	got, err := ApplyConnectionOptionsToDSN(dsn, ConnectionConfig{})
+	if err != nil || got != dsn {
+		t.Errorf("expected DSN unchanged without options, got %q (%v)", got, err)
+	}
+
+	got, err = ApplyConnectionOptionsToDSN(dsn, ConnectionConfig{
+		Socket:         "/var/run/mysqld/mysqld.sock",
+		Compress:       true,
+		Collation:      "utf8mb4_0900_ai_ci",
+		ConnectTimeout: 5 * time.Second,
+		ReadTimeout:    30 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("ApplyConnectionOptionsToDSN failed: %v", err)
+	}
+	for _, want := range []string{
+		"@unix(/var/run/mysqld/mysqld.sock)/db", "compress=true", "collation=utf8mb4_0900_ai_ci",
+		"timeout=5s", "readTimeout=30s", "tls=true",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %q", want, got)
+		}
+	}
+	if strings.Contains(got, "writeTimeout") {
+		t.Errorf("unset write timeout should not be added: %q", got)
+	}
+
+	if _, err := ApplyConnectionOptionsToDSN("not a dsn", ConnectionConfig{Compress: true}); err == nil {
+		t.Error("expected error for an invalid DSN")
+	}
+}
+
+func TestLoadConfigFileConnectionOptions(t *testing.T) {
+	content := `
+connections:
+  local:
+    dsn: "user:pass@/db"
+    socket: "/tmp/mysql.sock"
+    compress: true
+    collation: "utf8mb4_unicode_ci"
+    connect_timeout_seconds: 3
+    read_timeout_seconds: 20
+    write_timeout_seconds: 10
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile failed: %v", err)
+	}
+	conn := fc.ToConfig().Connections[0]
+	if conn.Socket != "/tmp/mysql.sock" || !conn.Compress || conn.Collation != "utf8mb4_unicode_ci" {
+		t.Errorf("unexpected connection options: %+v", conn)
+	}
+	if conn.ConnectTimeout != 3*time.Second || conn.ReadTimeout != 20*time.Second || conn.WriteTimeout != 10*time.Second {
+		t.Errorf("unexpected timeouts: %+v", conn)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	for name, bad := range map[string]string{
+		"collation": "    collation: \"utf8mb4; DROP\"\n",
+		"timeout":   "    read_timeout_seconds: -1\n",
+		"ssh":       "    ssh:\n      host: bastion\n      user: u\n      key_path: /k\n",
+	} {
+		badPath := filepath.Join(t.TempDir(), name+".yaml")
+		body := "connections:\n  local:\n    dsn: \"user:pass@/db\"\n    socket: \"/tmp/mysql.sock\"\n" + bad
+		if err := os.WriteFile(badPath, []byte(body), 0644); err != nil {
+			t.Fatalf("failed to write temp file: %v", err)
+		}
+		if err := ValidateConfigFile(badPath); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestLoadConfigFileEnvironmentLabels(t *testing.T) {
+	content := `
+connections:
+  prod:
+    dsn: "user:pass@tcp(prod:3306)/app"
+    environment: prod
+    tags: [pci, eu]
+    connect_on_demand: true
+security:
+  confirm_required: [prod]
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile failed: %v", err)
+	}
+	cfg := fc.ToConfig()
+	conn := cfg.Connections[0]
+	if conn.Environment != "prod" || len(conn.Tags) != 2 || conn.Tags[0] != "pci" {
+		t.Errorf("unexpected labels: %+v", conn)
+	}
+	if !conn.ConnectOnDemand {
+		t.Error("expected connect_on_demand to be set")
+	}
+	if len(cfg.ConfirmRequired) != 1 || cfg.ConfirmRequired[0] != "prod" {
+		t.Errorf("unexpected confirm_required: %v", cfg.ConfirmRequired)
+	}
+	out := PrintConfig(cfg)
+	if !strings.Contains(out, "environment: prod") || !strings.Contains(out, "confirm_required:") || !strings.Contains(out, "connect_on_demand: true") {
+		t.Errorf("PrintConfig missing labels:\n%s", out)
+	}
+}
+
+func TestFileConfigExportTargets(t *testing.T) {
+	content := `
+connections:
+  default:
+    dsn: "user:pass@tcp(localhost:3306)/db"
+export_targets:
+  lake:
+    type: S3
+    bucket: analytics-exports
+    prefix: /mysql/
+    region: eu-central-1
+    secret_access_key: hunter2
+  blob:
+    type: azure
+    bucket: exports
+    account: acme
+    url_expiry_seconds: 600
+`
+	path := filepath.Join(t.TempDir(), "exports.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	if err := ValidateConfigFile(path); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := fc.ToConfig()
+	if len(cfg.ExportTargets) != 2 {
+		t.Fatalf("expected two export targets, got %+v", cfg.ExportTargets)
+	}
+	blob, lake := cfg.ExportTargets[0], cfg.ExportTargets[1]
+	if blob.Name != "blob" || blob.Account != "acme" || blob.URLExpiry != 10*time.Minute {
+		t.Errorf("unexpected azure target: %+v", blob)
+	}
+	if lake.Type != ExportTargetS3 || lake.Prefix != "mysql/" || lake.URLExpiry != DefaultExportURLExpiryS*time.Second {
+		t.Errorf("unexpected s3 target: %+v", lake)
+	}
+	if out := PrintConfig(cfg); strings.Contains(out, "hunter2") {
+		t.Errorf("expected the secret key to be masked:\n%s", out)
+	}
+
+	for name, target := range map[string]string{
+		"unknown type":  "    type: ftp\n    bucket: b\n",
+		"no bucket":     "    type: gcs\n",
+		"azure account": "    type: azure\n    bucket: b\n",
+		"long expiry":   "    type: s3\n    bucket: b\n    url_expiry_seconds: 999999\n",
+	} {
+		bad := filepath.Join(t.TempDir(), "bad_exports.yaml")
+		yaml := "connections:\n  default:\n    dsn: \"u:p@tcp(h:3306)/db\"\nexport_targets:\n  t:\n" + target
+		if err := os.WriteFile(bad, []byte(yaml), 0644); err != nil {
+			t.Fatalf("failed to write temp file: %v", err)
+		}
+		if err := ValidateConfigFile(bad); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
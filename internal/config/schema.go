@@ -0,0 +1,276 @@
+// internal/config/schema.go
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaError is one problem found by CheckConfigSchema, with the position of
+// the offending key or value in the config file.
+type SchemaError struct {
+	Line    int
+	Column  int
+	Path    string // dotted key path, e.g. query.max_rows or connections.prod.ssh.port
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// SchemaErrors collects every problem found in one config file.
+type SchemaErrors struct {
+	File   string
+	Errors []SchemaError
+}
+
+func (e *SchemaErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problem(s) in %s:", len(e.Errors), e.File)
+	for _, se := range e.Errors {
+		fmt.Fprintf(&b, "\n  %s:%d:%d: %s: %s", e.File, se.Line, se.Column, se.Path, se.Message)
+	}
+	return b.String()
+}
+
+// numericRange bounds a numeric setting. Numeric settings without an entry in
+// schemaRanges must be non-negative.
+type numericRange struct {
+	min, max float64
+}
+
+// schemaRanges holds bounds keyed by path, with * standing for a user-chosen
+// map key (connection, saved query or report name) or a list index.
+var schemaRanges = map[string]numericRange{
+	"http.port":              {0, 65535},
+	"connections.*.ssh.port": {0, 65535},
+}
+
+// yaml11Bools lists the YAML 1.1 boolean spellings yaml.v3 accepts for bool fields.
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true, "off": true, "Off": true, "OFF": true,
+}
+
+// CheckConfigSchema checks a YAML or JSON config document against FileConfig:
+// unknown keys, values of the wrong type and out-of-range numbers. It returns
+// every problem found, in file order, or nil when the document conforms.
+func CheckConfigSchema(data []byte) ([]SchemaError, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	var errs []SchemaError
+	checkNode(doc.Content[0], reflect.TypeOf(FileConfig{}), "", "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs, nil
+}
+
+// checkNode validates n against t. path is the dotted path used in messages;
+// pattern is the same path with user-chosen keys replaced by * for
+// schemaRanges lookups.
+func checkNode(n *yaml.Node, t reflect.Type, path, pattern string, errs *[]SchemaError) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	report := func(format string, args ...interface{}) {
+		*errs = append(*errs, SchemaError{Line: n.Line, Column: n.Column, Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			report("expected a mapping, got %s", describeNode(n))
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				checkMerge(val, t, path, pattern, errs)
+				continue
+			}
+			if path == "" && strings.HasPrefix(key.Value, "x-") {
+				// Extension keys, e.g. for YAML anchors shared between connections.
+				continue
+			}
+			sub := joinPath(path, key.Value)
+			ft, ok := fields[key.Value]
+			if !ok {
+				msg := "unknown key"
+				if s := closestKey(key.Value, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				*errs = append(*errs, SchemaError{Line: key.Line, Column: key.Column, Path: sub, Message: msg})
+				continue
+			}
+			checkNode(val, ft, sub, joinPath(pattern, key.Value), errs)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			report("expected a mapping, got %s", describeNode(n))
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			checkNode(val, t.Elem(), joinPath(path, key.Value), joinPath(pattern, "*"), errs)
+		}
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			report("expected a list, got %s", describeNode(n))
+			return
+		}
+		for i, item := range n.Content {
+			checkNode(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), pattern+"[*]", errs)
+		}
+	case reflect.Bool:
+		// yaml.v3 still decodes YAML 1.1 spellings (yes/no, on/off) into bools.
+		if n.Kind != yaml.ScalarNode || (n.Tag != "!!bool" && !yaml11Bools[n.Value]) {
+			report("expected true or false, got %s", describeNode(n))
+		}
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		if n.Kind != yaml.ScalarNode || (n.Tag != "!!int" && n.Tag != "!!float") {
+			if t.Kind() == reflect.Float64 {
+				report("expected a number, got %s", describeNode(n))
+			} else {
+				report("expected an integer, got %s", describeNode(n))
+			}
+			return
+		}
+		v, err := strconv.ParseFloat(strings.ReplaceAll(n.Value, "_", ""), 64)
+		if err != nil {
+			// Hex and octal literals; yaml.v3 decodes them, so leave them be.
+			return
+		}
+		if t.Kind() != reflect.Float64 && v != math.Trunc(v) {
+			report("expected an integer, got %s", describeNode(n))
+			return
+		}
+		r, ok := schemaRanges[pattern]
+		if !ok {
+			r = numericRange{min: 0, max: -1}
+		}
+		if v < r.min || (r.max >= r.min && v > r.max) {
+			if r.max >= r.min {
+				report("value %s out of range (%g to %g)", n.Value, r.min, r.max)
+			} else {
+				report("value %s must not be negative", n.Value)
+			}
+		}
+	case reflect.String:
+		if n.Kind != yaml.ScalarNode {
+			report("expected a string, got %s", describeNode(n))
+		}
+	}
+}
+
+// checkMerge validates the value of a YAML merge key (<<) against the
+// enclosing struct type.
+func checkMerge(n *yaml.Node, t reflect.Type, path, pattern string, errs *[]SchemaError) {
+	if n.Kind == yaml.SequenceNode {
+		for _, item := range n.Content {
+			checkNode(item, t, path, pattern, errs)
+		}
+		return
+	}
+	checkNode(n, t, path, pattern, errs)
+}
+
+// yamlFields maps the yaml key of each field of struct type t to its type.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// closestKey returns the known key within edit distance 2 of key, if any.
+func closestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func describeNode(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch n.Tag {
+	case "!!str":
+		return fmt.Sprintf("string %q", n.Value)
+	case "!!int":
+		return "integer " + n.Value
+	case "!!float":
+		return "number " + n.Value
+	case "!!bool":
+		return "boolean " + n.Value
+	}
+	return n.Value
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckConfigSchema(t *testing.T) {
+	content := `connections:
+  prod:
+    dsn: "user:pass@tcp(db:3306)/app"
+    ssh:
+      host: bastion
+      port: 70000
+query:
+  max_rowss: 500
+  timeout_seconds: "30"
+  inject_limit: yes
+pool:
+  max_open_conns: -1
+security:
+  allowed_databases: app
+http:
+  rate_limit:
+    rps: 2.5
+`
+	errs, err := CheckConfigSchema([]byte(content))
+	if err != nil {
+		t.Fatalf("CheckConfigSchema failed: %v", err)
+	}
+	want := []SchemaError{
+		{Line: 6, Column: 13, Path: "connections.prod.ssh.port", Message: "value 70000 out of range (0 to 65535)"},
+		{Line: 8, Column: 3, Path: "query.max_rowss", Message: `unknown key (did you mean "max_rows"?)`},
+		{Line: 9, Column: 20, Path: "query.timeout_seconds", Message: `expected an integer, got string "30"`},
+		{Line: 12, Column: 19, Path: "pool.max_open_conns", Message: "value -1 must not be negative"},
+		{Line: 14, Column: 22, Path: "security.allowed_databases", Message: `expected a list, got string "app"`},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("got %d errors, want %d: %v", len(errs), len(want), errs)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("error %d = %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+}
+
+func TestCheckConfigSchemaJSON(t *testing.T) {
+	content := `{
+  "connections": {"prod": {"dsn": "user:pass@/app", "read_only": "true"}},
+  "loging": {"json_format": true}
+}`
+	errs, err := CheckConfigSchema([]byte(content))
+	if err != nil {
+		t.Fatalf("CheckConfigSchema failed: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Path != "connections.prod.read_only" || errs[0].Line != 2 {
+		t.Errorf("unexpected first error: %+v", errs[0])
+	}
+	if errs[1].Path != "loging" || !strings.Contains(errs[1].Message, `"logging"`) {
+		t.Errorf("unexpected second error: %+v", errs[1])
+	}
+}
+
+func TestCheckConfigSchemaAcceptsValidConfig(t *testing.T) {
+	content := `x-defaults: &defaults
+  read_only: true
+connections:
+  prod:
+    <<: *defaults
+    dsn: "user:pass@/app"
+    ssl: true
+saved_queries:
+  top:
+    sql: "SELECT 1"
+    params:
+      - name: n
+        type: int
+        default: 5
+query:
+  max_rows: 1e3
+`
+	errs, err := CheckConfigSchema([]byte(content))
+	if err != nil {
+		t.Fatalf("CheckConfigSchema failed: %v", err)
+	}
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestLoadConfigFileRejectsSchemaErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("connections:\n  a:\n    dsn: x\nquery:\n  max_rowss: 10\n"), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	_, err := LoadConfigFile(path)
+	var schemaErr *SchemaErrors
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected *SchemaErrors, got %v", err)
+	}
+	if !strings.Contains(err.Error(), path+":5:3: query.max_rowss: unknown key") {
+		t.Errorf("unexpected message: %v", err)
+	}
+	if err := ValidateConfigFile(path); err == nil {
+		t.Error("expected ValidateConfigFile to fail")
+	}
+}
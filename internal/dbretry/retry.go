@@ -6,17 +6,39 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"net"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/go-sql-driver/mysql"
 )
 
-// Config controls retry behavior for pool operations.
+// Config controls retry behavior for pool operations. Delays grow
+// exponentially from InitialInterval up to MaxInterval, each randomized by
+// ±RandomizationFactor so concurrent callers do not retry in lockstep.
 type Config struct {
-	MaxRetries  int
-	MaxInterval time.Duration
+	MaxRetries      int
+	InitialInterval time.Duration // 0 = 500ms
+	MaxInterval     time.Duration
+}
+
+// RandomizationFactor is the jitter applied to every backoff delay.
+const RandomizationFactor = 0.5
+
+// Stats describes the attempts Do made.
+type Stats struct {
+	Attempts  int   // calls of op, including the first
+	LastRetry error // transient error that caused the last retry, if any
+}
+
+// Retries returns how many times op was retried.
+func (s Stats) Retries() int {
+	if s.Attempts <= 1 {
+		return 0
+	}
+	return s.Attempts - 1
 }
 
 // DefaultConfig matches historical internal/mysql Client retry defaults.
@@ -46,6 +68,9 @@ func IsTransientError(err error) bool {
 	if errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) {
 		return true
 	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
 
 	var mysqlErr *mysql.MySQLError
 	if errors.As(err, &mysqlErr) {
@@ -62,6 +87,66 @@ func IsTransientError(err error) bool {
 	return false
 }
 
+// IsConnectionError reports whether err means the server could not be used
+// at all: a timeout, a network or bad-connection error, or a login refusal
+// (access denied, host blocked, too many connections). SQL errors such as a
+// syntax error or deadlock show a working server and are not connection errors.
+func IsConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1040, // ER_CON_COUNT_ERROR
+			1045, // ER_ACCESS_DENIED_ERROR
+			1129, // ER_HOST_IS_BLOCKED
+			1130: // ER_HOST_NOT_PRIVILEGED
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// Reason returns a short description of a transient error for result
+// metadata, e.g. "deadlock (1213)" or "connection reset".
+func Reason(err error) string {
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &mysqlErr):
+		switch mysqlErr.Number {
+		case 1040:
+			return "too many connections (1040)"
+		case 1205:
+			return "lock wait timeout (1205)"
+		case 1213:
+			return "deadlock (1213)"
+		}
+		return fmt.Sprintf("mysql error %d", mysqlErr.Number)
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNABORTED), errors.Is(err, syscall.EPIPE):
+		return "connection reset"
+	case errors.Is(err, mysql.ErrInvalidConn), errors.Is(err, driver.ErrBadConn):
+		return "bad connection"
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return "network error"
+	}
+	return err.Error()
+}
+
 // ShouldWarmPool reports whether a ping may help refresh the pool after this error.
 func ShouldWarmPool(err error) bool {
 	return errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn)
@@ -72,21 +157,35 @@ func ShouldWarmPool(err error) bool {
 // returning a retryable error when the failure looks like a bad pooled connection,
 // helping recovery after MySQL restarts (issue #121).
 func Do(ctx context.Context, db *sql.DB, cfg Config, pingTimeout time.Duration, op func() error) error {
+	_, err := DoWithStats(ctx, db, cfg, pingTimeout, op)
+	return err
+}
+
+// DoWithStats is Do that also reports how many attempts were made.
+func DoWithStats(ctx context.Context, db *sql.DB, cfg Config, pingTimeout time.Duration, op func() error) (Stats, error) {
+	var stats Stats
 	if cfg.MaxRetries <= 0 {
-		return op()
+		stats.Attempts = 1
+		return stats, op()
 	}
-
 	ebo := backoff.NewExponentialBackOff()
+	if cfg.InitialInterval > 0 {
+		ebo.InitialInterval = cfg.InitialInterval
+	}
+	ebo.RandomizationFactor = RandomizationFactor
 	ebo.MaxInterval = cfg.MaxInterval
 	if ebo.MaxInterval <= 0 {
 		ebo.MaxInterval = 10 * time.Second
 	}
 	ebo.MaxElapsedTime = 0
-
 	bo := backoff.WithContext(ebo, ctx)
 	b := backoff.WithMaxRetries(bo, uint64(cfg.MaxRetries))
-
-	return backoff.Retry(func() error {
+	var lastErr error
+	err := backoff.Retry(func() error {
+		if stats.Attempts > 0 {
+			stats.LastRetry = lastErr
+		}
+		stats.Attempts++
 		err := op()
 		if err == nil {
 			return nil
@@ -94,6 +193,7 @@ func Do(ctx context.Context, db *sql.DB, cfg Config, pingTimeout time.Duration,
 		if !IsTransientError(err) {
 			return backoff.Permanent(err)
 		}
+		lastErr = err
 		if db != nil && pingTimeout > 0 && ShouldWarmPool(err) {
 			pctx, cancel := context.WithTimeout(ctx, pingTimeout)
 			_ = db.PingContext(pctx)
@@ -101,4 +201,5 @@ func Do(ctx context.Context, db *sql.DB, cfg Config, pingTimeout time.Duration,
 		}
 		return err
 	}, b)
+	return stats, err
 }
@@ -4,8 +4,14 @@ import (
 	"context"
 	"database/sql/driver"
 	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 func TestDoRetriesDriverBadConn(t *testing.T) {
@@ -56,3 +62,76 @@ func TestDoPermanentError(t *testing.T) {
 		t.Fatalf("expected 1 attempt, got %d", attempts)
 	}
 }
+
+func TestDoWithStatsReportsRetries(t *testing.T) {
+	ctx := context.Background()
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}
+	var attempts int
+	stats, err := DoWithStats(ctx, nil, Config{MaxRetries: 3, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}, 0, func() error {
+		attempts++
+		switch attempts {
+		case 1:
+			return driver.ErrBadConn
+		case 2:
+			return deadlock
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DoWithStats: %v", err)
+	}
+	if stats.Attempts != 3 || stats.Retries() != 2 || !errors.Is(stats.LastRetry, deadlock) {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	stats, err = DoWithStats(ctx, nil, Config{MaxRetries: 3, MaxInterval: time.Millisecond}, 0, func() error { return nil })
+	if err != nil || stats.Retries() != 0 || stats.LastRetry != nil {
+		t.Fatalf("expected no retries, got %+v, %v", stats, err)
+	}
+}
+
+func TestIsTransientConnectionReset(t *testing.T) {
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	if !IsTransientError(reset) {
+		t.Error("expected a connection reset to be transient")
+	}
+	if !IsTransientError(fmt.Errorf("query: %w", syscall.ECONNRESET)) {
+		t.Error("expected a wrapped ECONNRESET to be transient")
+	}
+	if IsTransientError(&mysql.MySQLError{Number: 1064, Message: "syntax error"}) {
+		t.Error("expected a syntax error not to be transient")
+	}
+	for err, want := range map[error]string{
+		reset:                           "connection reset",
+		driver.ErrBadConn:               "bad connection",
+		&mysql.MySQLError{Number: 1205}: "lock wait timeout (1205)",
+	} {
+		if got := Reason(err); got != want {
+			t.Errorf("Reason(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	for _, err := range []error{
+		context.DeadlineExceeded,
+		driver.ErrBadConn,
+		&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+		&mysql.MySQLError{Number: 1045, Message: "Access denied"},
+	} {
+		if !IsConnectionError(err) {
+			t.Errorf("expected %v to be a connection error", err)
+		}
+	}
+	for _, err := range []error{
+		nil,
+		context.Canceled,
+		&mysql.MySQLError{Number: 1213, Message: "Deadlock"},
+		&mysql.MySQLError{Number: 1064, Message: "syntax error"},
+		errors.New("sql is required"),
+	} {
+		if IsConnectionError(err) {
+			t.Errorf("expected %v not to be a connection error", err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// internal/demo/catalog.go
+package demo
+
+// Schema is the name of the sample database served in demo mode.
+const Schema = "demo"
+
+// Server identity reported by VERSION(), @@version_comment and CURRENT_USER().
+const (
+	ServerVersion  = "8.4.0-demo"
+	VersionComment = "mysql-mcp-server built-in demo dataset"
+	CurrentUser    = "demo@localhost"
+)
+
+// systemSchema is the subset of performance_schema the diagnostics tools
+// read. Its tables are filled from SHOW GLOBAL STATUS and SHOW GLOBAL
+// VARIABLES when the engine starts.
+const systemSchema = "performance_schema"
+
+// sampleSchema creates and fills the demo tables. It is run once, before the
+// connection is handed out and statements pass through the read-only check.
+var sampleSchema = []string{
+	`CREATE TABLE performance_schema.global_status (
+		VARIABLE_NAME varchar(64) NOT NULL PRIMARY KEY,
+		VARIABLE_VALUE varchar(1024)
+	)`,
+	`CREATE TABLE performance_schema.global_variables (
+		VARIABLE_NAME varchar(64) NOT NULL PRIMARY KEY,
+		VARIABLE_VALUE varchar(1024)
+	)`,
+
+	"USE `demo`",
+
+	`CREATE TABLE customers (
+		id int unsigned NOT NULL AUTO_INCREMENT,
+		name varchar(100) NOT NULL,
+		email varchar(255) NOT NULL COMMENT 'login and contact address',
+		country char(2) NOT NULL COMMENT 'ISO 3166-1 alpha-2',
+		created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (id),
+		UNIQUE KEY uk_customers_email (email),
+		KEY idx_customers_country (country)
+	) COMMENT 'Registered shop customers'`,
+
+	`CREATE TABLE products (
+		id int unsigned NOT NULL AUTO_INCREMENT,
+		sku varchar(32) NOT NULL,
+		name varchar(200) NOT NULL,
+		category varchar(50) NOT NULL,
+		price decimal(10,2) NOT NULL COMMENT 'unit price in EUR',
+		stock int NOT NULL DEFAULT 0,
+		PRIMARY KEY (id),
+		UNIQUE KEY uk_products_sku (sku),
+		KEY idx_products_category (category)
+	) COMMENT 'Product catalog'`,
+
+	`CREATE TABLE orders (
+		id int unsigned NOT NULL AUTO_INCREMENT,
+		customer_id int unsigned NOT NULL,
+		status varchar(20) NOT NULL DEFAULT 'pending' COMMENT 'pending, paid, shipped or cancelled',
+		total decimal(10,2) NOT NULL,
+		created_at datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		shipped_at datetime NULL,
+		PRIMARY KEY (id),
+		KEY idx_orders_customer (customer_id),
+		KEY idx_orders_status_created (status, created_at),
+		CONSTRAINT fk_orders_customer FOREIGN KEY (customer_id) REFERENCES customers (id) ON DELETE RESTRICT ON UPDATE CASCADE
+	) COMMENT 'Customer orders'`,
+
+	`CREATE TABLE order_items (
+		order_id int unsigned NOT NULL,
+		product_id int unsigned NOT NULL,
+		quantity int NOT NULL DEFAULT 1,
+		unit_price decimal(10,2) NOT NULL COMMENT 'price at time of order',
+		PRIMARY KEY (order_id, product_id),
+		KEY idx_order_items_product (product_id),
+		CONSTRAINT fk_order_items_order FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE ON UPDATE CASCADE,
+		CONSTRAINT fk_order_items_product FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE RESTRICT ON UPDATE CASCADE
+	) COMMENT 'Order lines'`,
+
+	`INSERT INTO customers (id, name, email, country, created_at) VALUES
+		(1, 'Ada Lovelace', 'ada@example.com', 'GB', '2024-01-03 09:12:00'),
+		(2, 'Alan Turing', 'alan@example.com', 'GB', '2024-01-05 14:30:00'),
+		(3, 'Grace Hopper', 'grace@example.com', 'US', '2024-01-09 11:00:00'),
+		(4, 'Edsger Dijkstra', 'edsger@example.com', 'NL', '2024-02-01 08:45:00'),
+		(5, 'Barbara Liskov', 'barbara@example.com', 'US', '2024-02-14 16:20:00'),
+		(6, 'Donald Knuth', 'don@example.com', 'US', '2024-03-02 10:05:00'),
+		(7, 'Margaret Hamilton', 'margaret@example.com', 'US', '2024-03-18 13:40:00'),
+		(8, 'Linus Torvalds', 'linus@example.com', 'FI', '2024-04-07 19:25:00')`,
+
+	`INSERT INTO products (id, sku, name, category, price, stock) VALUES
+		(1, 'BK-001', 'Structure and Interpretation of Computer Programs', 'books', 45.00, 12),
+		(2, 'BK-002', 'The Art of Computer Programming', 'books', 189.99, 3),
+		(3, 'KB-100', 'Mechanical Keyboard', 'hardware', 129.50, 25),
+		(4, 'MS-200', 'Ergonomic Mouse', 'hardware', 59.90, 40),
+		(5, 'MG-010', 'Coffee Mug', 'accessories', 12.00, 150),
+		(6, 'ST-500', 'Laptop Stand', 'accessories', 39.00, 0)`,
+
+	`INSERT INTO orders (id, customer_id, status, total, created_at, shipped_at) VALUES
+		(1, 1, 'shipped', 234.99, '2024-04-01 10:00:00', '2024-04-02 09:00:00'),
+		(2, 2, 'shipped', 129.50, '2024-04-03 12:15:00', '2024-04-04 15:30:00'),
+		(3, 3, 'paid', 71.90, '2024-04-10 08:20:00', NULL),
+		(4, 1, 'shipped', 24.00, '2024-04-12 17:45:00', '2024-04-13 11:10:00'),
+		(5, 5, 'pending', 189.99, '2024-04-20 21:05:00', NULL),
+		(6, 6, 'cancelled', 39.00, '2024-04-22 07:50:00', NULL),
+		(7, 4, 'shipped', 188.50, '2024-05-01 13:00:00', '2024-05-03 10:20:00'),
+		(8, 7, 'paid', 45.00, '2024-05-06 18:30:00', NULL),
+		(9, 3, 'pending', 59.90, '2024-05-09 09:55:00', NULL),
+		(10, 8, 'shipped', 141.50, '2024-05-11 22:10:00', '2024-05-13 08:00:00')`,
+
+	`INSERT INTO order_items (order_id, product_id, quantity, unit_price) VALUES
+		(1, 1, 1, 45.00),
+		(1, 2, 1, 189.99),
+		(2, 3, 1, 129.50),
+		(3, 5, 1, 12.00),
+		(3, 4, 1, 59.90),
+		(4, 5, 2, 12.00),
+		(5, 2, 1, 189.99),
+		(6, 6, 1, 39.00),
+		(7, 3, 1, 129.50),
+		(7, 4, 1, 59.00),
+		(8, 1, 1, 45.00),
+		(9, 4, 1, 59.90),
+		(10, 3, 1, 129.50),
+		(10, 5, 1, 12.00)`,
+}
@@ -0,0 +1,237 @@
+// internal/demo/demo_test.go
+package demo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open()
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func queryAll(t *testing.T, db *sql.DB, query string, args ...interface{}) ([]string, [][]interface{}) {
+	t.Helper()
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		t.Fatalf("query %q failed: %v", query, err)
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("columns: %v", err)
+	}
+	var out [][]interface{}
+	for rows.Next() {
+		vals := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		out = append(out, vals)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+	return cols, out
+}
+
+func TestServerIdentity(t *testing.T) {
+	db := openTestDB(t)
+
+	var version, comment string
+	if err := db.QueryRow("SELECT VERSION(), @@version_comment").Scan(&version, &comment); err != nil {
+		t.Fatalf("version query failed: %v", err)
+	}
+	if version != ServerVersion || comment != VersionComment {
+		t.Errorf("unexpected identity %q / %q", version, comment)
+	}
+	if err := db.PingContext(context.Background()); err != nil {
+		t.Errorf("ping failed: %v", err)
+	}
+}
+
+func TestSelectJoinGroupAndPlaceholders(t *testing.T) {
+	db := openTestDB(t)
+
+	cols, rows := queryAll(t, db, `
+		SELECT c.country, COUNT(*) AS orders, SUM(o.total) AS revenue
+		FROM demo.orders o
+		JOIN demo.customers c ON c.id = o.customer_id
+		WHERE o.status <> ?
+		GROUP BY c.country
+		HAVING orders >= ?
+		ORDER BY orders DESC, c.country
+		LIMIT 2`, "cancelled", 1)
+	if strings.Join(cols, ",") != "country,orders,revenue" {
+		t.Fatalf("unexpected columns %v", cols)
+	}
+	if len(rows) == 0 || len(rows) > 2 {
+		t.Fatalf("expected 1-2 rows, got %v", rows)
+	}
+	if n, ok := rows[0][1].(int64); !ok || n < 1 {
+		t.Errorf("expected positive order count, got %#v", rows[0][1])
+	}
+
+	var name string
+	if err := db.QueryRow("SELECT name FROM demo.customers WHERE id = ?", 1).Scan(&name); err != nil || name == "" {
+		t.Errorf("lookup by id failed: %q %v", name, err)
+	}
+
+	_, rows = queryAll(t, db, `SELECT p.sku FROM demo.products p
+		WHERE NOT EXISTS (SELECT 1 FROM demo.order_items i WHERE i.product_id = p.id)
+		ORDER BY 1`)
+	for _, r := range rows {
+		if r[0] == nil {
+			t.Errorf("unexpected NULL sku")
+		}
+	}
+}
+
+func TestInformationSchemaAndShow(t *testing.T) {
+	db := openTestDB(t)
+
+	_, rows := queryAll(t, db, "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME", Schema)
+	var names []string
+	for _, r := range rows {
+		names = append(names, r[0].(string))
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "customers,order_items,orders,products" {
+		t.Errorf("unexpected tables %v", names)
+	}
+
+	cols, rows := queryAll(t, db, "SHOW TABLES FROM demo LIKE 'order%'")
+	if cols[0] != "Tables_in_demo" || len(rows) != 2 {
+		t.Errorf("unexpected SHOW TABLES result %v %v", cols, rows)
+	}
+
+	cols, rows = queryAll(t, db, "SHOW INDEX FROM demo.orders")
+	if len(cols) < 11 || cols[10] != "Index_type" || len(rows) == 0 {
+		t.Errorf("unexpected SHOW INDEX result %v", cols)
+	}
+
+	_, rows = queryAll(t, db, "SHOW GLOBAL STATUS LIKE 'Threads_%'")
+	if len(rows) == 0 {
+		t.Errorf("expected Threads_ status rows, got none")
+	}
+
+	_, rows = queryAll(t, db, "SHOW CREATE TABLE demo.orders")
+	if ddl, _ := rows[0][1].(string); !strings.Contains(ddl, "FOREIGN KEY (`customer_id`) REFERENCES `customers`") {
+		t.Errorf("unexpected DDL:\n%s", ddl)
+	}
+
+	cols, rows = queryAll(t, db, "EXPLAIN SELECT * FROM demo.orders o JOIN demo.customers c ON c.id = o.customer_id")
+	if len(cols) < 10 || cols[1] != "select_type" || len(rows) == 0 {
+		t.Errorf("unexpected plan %v %v", cols, rows)
+	}
+}
+
+func TestCTEAndWindowFunctions(t *testing.T) {
+	db := openTestDB(t)
+
+	_, rows := queryAll(t, db, `
+		WITH spend AS (
+			SELECT customer_id, SUM(total) AS total FROM demo.orders GROUP BY customer_id
+		)
+		SELECT customer_id, RANK() OVER (ORDER BY total DESC) AS rnk
+		FROM spend ORDER BY rnk, customer_id`)
+	if len(rows) != 8 {
+		t.Fatalf("expected one row per customer with orders, got %v", rows)
+	}
+	if fmt.Sprint(rows[0][0]) != "1" {
+		t.Errorf("expected customer 1 to rank first, got %v", rows[0])
+	}
+}
+
+func TestSessionDatabaseAndReadOnly(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.QueryContext(ctx, "SELECT * FROM orders"); err == nil || !strings.Contains(strings.ToLower(err.Error()), "no database selected") {
+		t.Errorf("expected No database selected, got %v", err)
+	}
+	if _, err := conn.ExecContext(ctx, "USE `demo`"); err != nil {
+		t.Fatalf("USE failed: %v", err)
+	}
+	var current string
+	if err := conn.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&current); err != nil || current != Schema {
+		t.Errorf("expected current database %q, got %q (%v)", Schema, current, err)
+	}
+	if _, err := conn.ExecContext(ctx, "USE missing"); err == nil {
+		t.Error("expected error for unknown database")
+	}
+	for _, stmt := range []string{
+		"DELETE FROM orders",
+		"UPDATE products SET stock = 0",
+		"DROP TABLE order_items",
+		"SELECT * FROM orders INTO OUTFILE '/tmp/orders.csv'",
+		"EXPLAIN DELETE FROM orders",
+		"SET GLOBAL max_connections = 1",
+		"SET @@global.sql_mode = ''",
+		"SET PERSIST max_connections = 1",
+		"SET @x = 1, GLOBAL max_connections = 1",
+	} {
+		if _, err := conn.ExecContext(ctx, stmt); err == nil || !strings.Contains(err.Error(), "read-only") {
+			t.Errorf("%s: expected read-only error, got %v", stmt, err)
+		}
+	}
+	for _, stmt := range []string{"SET @x = 1", "SET SESSION sql_mode = ''", "SET @@session.max_execution_time = 1000"} {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			t.Errorf("%s: %v", stmt, err)
+		}
+	}
+	var orders int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM orders").Scan(&orders); err != nil || orders != 10 {
+		t.Errorf("expected the sample orders to be intact, got %d (%v)", orders, err)
+	}
+	if _, err := conn.QueryContext(ctx, "SELECT * FROM nope"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected missing table error, got %v", err)
+	}
+}
+
+func TestValuesRenderAsText(t *testing.T) {
+	db := openTestDB(t)
+
+	var price, created string
+	if err := db.QueryRow("SELECT price, (SELECT created_at FROM demo.customers WHERE id = 1) FROM demo.products WHERE id = ?", 1).Scan(&price, &created); err != nil {
+		t.Fatal(err)
+	}
+	if price != "45.00" || created != "2024-01-03 09:12:00" {
+		t.Errorf("unexpected values %q %q", price, created)
+	}
+
+	stmt, err := db.Prepare("SELECT total FROM demo.orders WHERE id = ?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	var total string
+	if err := stmt.QueryRow(4).Scan(&total); err != nil || total != "24.00" {
+		t.Errorf("prepared query: %q %v", total, err)
+	}
+
+	var status string
+	if err := db.QueryRow("SELECT VARIABLE_VALUE FROM performance_schema.global_variables WHERE VARIABLE_NAME = 'version'").Scan(&status); err != nil || status != ServerVersion {
+		t.Errorf("performance_schema.global_variables: %q %v", status, err)
+	}
+}
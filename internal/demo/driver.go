@@ -0,0 +1,292 @@
+// internal/demo/driver.go
+package demo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	gmsdriver "github.com/dolthub/go-mysql-server/driver"
+	"github.com/dolthub/go-mysql-server/memory"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/shopspring/decimal"
+)
+
+// DriverName is the database/sql driver name registered by this package.
+const DriverName = "mysql-mcp-demo"
+
+var (
+	sharedConnector     driver.Connector
+	sharedConnectorErr  error
+	sharedConnectorOnce sync.Once
+)
+
+func init() {
+	sql.Register(DriverName, demoDriver{})
+}
+
+// Open returns a *sql.DB backed by the built-in sample schema. The data is
+// held in memory by go-mysql-server and is read-only; the DSN is ignored.
+func Open() (*sql.DB, error) {
+	return sql.Open(DriverName, "")
+}
+
+type demoDriver struct{}
+
+var _ driver.DriverContext = demoDriver{}
+
+func (d demoDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.OpenConnector(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.Connect(context.Background())
+}
+
+func (demoDriver) OpenConnector(string) (driver.Connector, error) {
+	sharedConnectorOnce.Do(func() { sharedConnector, sharedConnectorErr = newConnector() })
+	if sharedConnectorErr != nil {
+		return nil, sharedConnectorErr
+	}
+	return connector{sharedConnector}, nil
+}
+
+// newConnector sets up the in-memory engine and loads the sample schema.
+// Every connection shares the same engine and data.
+func newConnector() (driver.Connector, error) {
+	if err := gmssql.SystemVariables.AssignValues(map[string]interface{}{
+		"version":               ServerVersion,
+		"version_comment":       VersionComment,
+		"read_only":             1,
+		"transaction_read_only": 1,
+	}); err != nil {
+		return nil, fmt.Errorf("demo: server variables: %w", err)
+	}
+
+	dbs := make([]gmssql.Database, 0, 2)
+	for _, name := range []string{Schema, systemSchema} {
+		db := memory.NewDatabase(name)
+		db.EnablePrimaryKeyIndexes()
+		dbs = append(dbs, db)
+	}
+	p := provider{memory.NewDBProvider(dbs...)}
+	c, err := gmsdriver.New(p, nil).OpenConnector("")
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.Connect(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	load := conn.(*gmsdriver.Conn)
+	for _, stmt := range sampleSchema {
+		if _, err := load.ExecContext(context.Background(), stmt, nil); err != nil {
+			return nil, fmt.Errorf("demo: loading sample schema: %w", err)
+		}
+	}
+	for show, table := range map[string]string{
+		"SHOW GLOBAL STATUS":    "global_status",
+		"SHOW GLOBAL VARIABLES": "global_variables",
+	} {
+		if err := copyShow(load, show, systemSchema+"."+table); err != nil {
+			return nil, fmt.Errorf("demo: loading %s.%s: %w", systemSchema, table, err)
+		}
+	}
+	return c, nil
+}
+
+// copyShow inserts the name/value rows of a SHOW statement into table.
+func copyShow(conn *gmsdriver.Conn, show, table string) error {
+	ctx := context.Background()
+	rows, err := conn.QueryContext(ctx, show, nil)
+	if err != nil {
+		return err
+	}
+	var (
+		tuples []string
+		args   []driver.NamedValue
+	)
+	row := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err := rows.Next(row); err == io.EOF {
+			break
+		} else if err != nil {
+			rows.Close()
+			return err
+		}
+		value := row[1]
+		if value != nil {
+			value = fmt.Sprint(value)
+		}
+		tuples = append(tuples, "(?, ?)")
+		args = append(args,
+			driver.NamedValue{Ordinal: len(args) + 1, Value: fmt.Sprint(row[0])},
+			driver.NamedValue{Ordinal: len(args) + 2, Value: value})
+	}
+	if err := rows.Close(); err != nil || len(tuples) == 0 {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, "INSERT INTO "+table+" VALUES "+strings.Join(tuples, ", "), args)
+	return err
+}
+
+// provider resolves every DSN to the single in-memory catalog and opens
+// sessions as CurrentUser.
+type provider struct {
+	dbs *memory.DbProvider
+}
+
+func (p provider) Resolve(string, *gmsdriver.Options) (string, gmssql.DatabaseProvider, error) {
+	return Schema, p.dbs, nil
+}
+
+func (p provider) NewSession(_ context.Context, id uint32, _ *gmsdriver.Connector) (gmssql.Session, error) {
+	user, host, _ := strings.Cut(CurrentUser, "@")
+	client := gmssql.Client{User: user, Address: host}
+	return memory.NewSession(gmssql.NewBaseSessionWithClientServer("", client, id), p.dbs), nil
+}
+
+type connector struct {
+	driver.Connector
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{inner.(*gmsdriver.Conn)}, nil
+}
+
+func (connector) Driver() driver.Driver { return demoDriver{} }
+
+// conn passes statements to go-mysql-server after rejecting anything that
+// would modify the sample data.
+type conn struct {
+	*gmsdriver.Conn
+}
+
+var (
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+)
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := checkReadOnly(query); err != nil {
+		return nil, err
+	}
+	st, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt{st.(*gmsdriver.Stmt)}, nil
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if err := checkReadOnly(query); err != nil {
+		return nil, err
+	}
+	r, err := c.Conn.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return textRows{r}, nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := checkReadOnly(query); err != nil {
+		return nil, err
+	}
+	return c.Conn.ExecContext(ctx, query, args)
+}
+
+// checkReadOnly accepts queries, SHOW/DESCRIBE/EXPLAIN, USE, session and
+// user variable SET and transaction control; the demo dataset is read-only. Statements that do not
+// parse are left to the engine, which reports the syntax error.
+func checkReadOnly(query string) error {
+	stmt, err := sqlparser.Parse(query)
+	if err != nil || readOnlyStatement(stmt) {
+		return nil
+	}
+	verb := strings.ToUpper(strings.Fields(sqlparser.String(stmt))[0])
+	return fmt.Errorf("the demo connection is read-only: %s statements are not supported", verb)
+}
+
+func readOnlyStatement(stmt sqlparser.Statement) bool {
+	switch s := stmt.(type) {
+	case *sqlparser.Select:
+		return s.Into == nil
+	case *sqlparser.SetOp:
+		return s.Into == nil && readOnlyStatement(s.Left) && readOnlyStatement(s.Right)
+	case *sqlparser.ParenSelect:
+		return readOnlyStatement(s.Select)
+	case *sqlparser.Explain:
+		return readOnlyStatement(s.Statement)
+	case *sqlparser.Set:
+		for _, e := range s.Exprs {
+			switch e.Scope {
+			case sqlparser.SetScope_Global, sqlparser.SetScope_Persist, sqlparser.SetScope_PersistOnly:
+				return false
+			}
+		}
+		return true
+	case *sqlparser.Show, *sqlparser.OtherRead, *sqlparser.Use,
+		*sqlparser.Begin, *sqlparser.Commit, *sqlparser.Rollback:
+		return true
+	}
+	return false
+}
+
+type stmt struct {
+	*gmsdriver.Stmt
+}
+
+func (s stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	r, err := s.Stmt.QueryContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return textRows{r}, nil
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	r, err := s.Stmt.Query(args)
+	if err != nil {
+		return nil, err
+	}
+	return textRows{r}, nil
+}
+
+// textRows renders temporal and DECIMAL values as text, the way the MySQL
+// text protocol returns them, so demo results look like those of a real
+// server without parseTime.
+type textRows struct {
+	driver.Rows
+}
+
+func (r textRows) Next(dest []driver.Value) error {
+	if err := r.Rows.Next(dest); err != nil {
+		return err
+	}
+	for i, v := range dest {
+		switch x := v.(type) {
+		case time.Time:
+			dest[i] = x.Format("2006-01-02 15:04:05")
+		case decimal.Decimal:
+			dest[i] = x.StringFixed(max(-x.Exponent(), 0))
+		}
+	}
+	return nil
+}
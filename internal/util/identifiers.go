@@ -4,21 +4,51 @@ package util
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
-// QuoteIdent safely quotes a MySQL identifier, returning an error if the name
-// contains potentially dangerous characters.
-func QuoteIdent(name string) (string, error) {
+// MaxIdentifierLength is MySQL's limit for database, table and column names,
+// counted in characters rather than bytes.
+const MaxIdentifierLength = 64
+
+// ValidateIdent checks name against MySQL's rules for quoted identifiers.
+// Identifiers are stored as utf8mb3, so any character from U+0001 to U+FFFF
+// is legal (letters in any script, $, spaces, punctuation) while NUL and
+// supplementary characters such as emoji are not; names may not end with a
+// space and are at most 64 characters long. Backticks and ASCII control
+// characters are rejected as well: MySQL accepts them, but no real schema
+// needs them and they are the usual ingredients of injection attempts.
+func ValidateIdent(name string) error {
 	if name == "" {
-		return "", fmt.Errorf("identifier cannot be empty")
+		return fmt.Errorf("identifier cannot be empty")
+	}
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("identifier is not valid UTF-8: %q", name)
 	}
-	// Reject identifiers with dangerous characters that could enable SQL injection
-	if strings.ContainsAny(name, " \t\n\r;`\\") {
-		return "", fmt.Errorf("identifier contains invalid characters: %q", name)
+	n := 0
+	for _, r := range name {
+		switch {
+		case r == 0 || r > 0xFFFF:
+			return fmt.Errorf("identifier contains %U, outside the U+0001..U+FFFF range MySQL allows: %q", r, name)
+		case r < 0x20 || r == 0x7F || r == '`':
+			return fmt.Errorf("identifier contains invalid characters: %q", name)
+		}
+		n++
 	}
-	// Additional check: reject identifiers that are too long (MySQL limit is 64)
-	if len(name) > 64 {
-		return "", fmt.Errorf("identifier too long: %d characters (max 64)", len(name))
+	if n > MaxIdentifierLength {
+		return fmt.Errorf("identifier too long: %d characters (max %d)", n, MaxIdentifierLength)
+	}
+	if strings.HasSuffix(name, " ") {
+		return fmt.Errorf("identifier cannot end with a space: %q", name)
+	}
+	return nil
+}
+
+// QuoteIdent validates a MySQL identifier with ValidateIdent and returns it
+// in backticks.
+func QuoteIdent(name string) (string, error) {
+	if err := ValidateIdent(name); err != nil {
+		return "", err
 	}
 	return "`" + name + "`", nil
 }
@@ -56,3 +86,27 @@ func TruncateQuery(query string, maxLen int) string {
 	}
 	return query[:maxLen] + "..."
 }
+
+// IdentifierDistance is the Levenshtein distance between two identifiers,
+// counted in characters and ignoring case the way MySQL compares column
+// names.
+func IdentifierDistance(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
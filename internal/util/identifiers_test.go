@@ -2,6 +2,7 @@
 package util
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -15,15 +16,25 @@ func TestQuoteIdent(t *testing.T) {
 		{"valid simple", "users", "`users`", false},
 		{"valid with underscore", "user_accounts", "`user_accounts`", false},
 		{"valid with numbers", "table123", "`table123`", false},
+		{"dollar sign", "order$items", "`order$items`", false},
+		{"unicode letters", "クライアント", "`クライアント`", false},
+		{"accented latin", "café_crème", "`café_crème`", false},
+		{"top of BMP", "a\uFFFF", "`a\uFFFF`", false},
+		{"inner space", "user accounts", "`user accounts`", false},
+		{"semicolon", "users;", "`users;`", false},
+		{"backslash", "users\\table", "`users\\table`", false},
 		{"empty string", "", "", true},
-		{"contains space", "user accounts", "", true},
-		{"contains semicolon", "users;", "", true},
+		{"trailing space", "users ", "", true},
 		{"contains backtick", "users`drop", "", true},
 		{"contains tab", "users\ttable", "", true},
 		{"contains newline", "users\ntable", "", true},
-		{"contains backslash", "users\\table", "", true},
-		{"too long", string(make([]byte, 65)), "", true},
-		{"max length (64)", string(make([]byte, 64)), "`" + string(make([]byte, 64)) + "`", false},
+		{"contains NUL", "users\x00", "", true},
+		{"supplementary character", "users😀", "", true},
+		{"invalid UTF-8", "users\xff", "", true},
+		{"too long", strings.Repeat("a", 65), "", true},
+		{"max length (64)", strings.Repeat("a", 64), "`" + strings.Repeat("a", 64) + "`", false},
+		{"64 multibyte characters", strings.Repeat("é", 64), "`" + strings.Repeat("é", 64) + "`", false},
+		{"65 multibyte characters", strings.Repeat("é", 65), "", true},
 	}
 
 	for _, tt := range tests {
@@ -128,3 +139,24 @@ func TestTruncateQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentifierDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"orders", "orders", 0},
+		{"Orders", "ORDERS", 0},
+		{"ordres", "orders", 2},
+		{"custmer", "customer", 1},
+		{"user_id", "userid", 1},
+		{"", "abc", 3},
+		{"größe", "grösse", 2},
+	}
+	for _, tt := range tests {
+		if got := IdentifierDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("IdentifierDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
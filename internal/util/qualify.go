@@ -0,0 +1,131 @@
+// internal/util/qualify.go
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// TableRef names a table in a specific database.
+type TableRef struct {
+	Database string
+	Table    string
+}
+
+func (t TableRef) String() string {
+	return t.Database + "." + t.Table
+}
+
+// ParseTableRef splits "database.table" into a TableRef.
+func ParseTableRef(s string) (TableRef, error) {
+	db, table, ok := strings.Cut(strings.TrimSpace(s), ".")
+	db, table = strings.Trim(strings.TrimSpace(db), "`"), strings.Trim(strings.TrimSpace(table), "`")
+	if !ok || db == "" || table == "" || strings.Contains(table, ".") {
+		return TableRef{}, fmt.Errorf("table %q must be written as database.table", s)
+	}
+	return TableRef{Database: db, Table: table}, nil
+}
+
+// QualifyTables rewrites the unqualified table references of a SELECT or
+// UNION to database.table using tables, so a join across databases runs
+// without a default database. A qualified reference is left as written. An
+// unqualified name missing from tables, or present in more than one of its
+// databases, is an error rather than a guess. qualified lists the references
+// rewritten, as database.table.
+func QualifyTables(sqlText string, tables []TableRef) (out string, qualified []string, err error) {
+	stmt, targets, err := unqualifiedTables(sqlText)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not parse the query (WITH clauses and window functions are not supported here; qualify their tables as database.table and use run_query): %w", err)
+	}
+	if stmt == nil {
+		return "", nil, fmt.Errorf("only SELECT statements can be qualified")
+	}
+	if len(targets) == 0 {
+		return sqlText, nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, ate := range targets {
+		tn := ate.Expr.(sqlparser.TableName)
+		var match *TableRef
+		for i, t := range tables {
+			if !strings.EqualFold(t.Table, tn.Name.String()) {
+				continue
+			}
+			if match != nil && !strings.EqualFold(match.Database, t.Database) {
+				return "", nil, fmt.Errorf("table %s exists in both %s and %s; qualify it in the query", tn.Name.String(), match.Database, t.Database)
+			}
+			match = &tables[i]
+		}
+		if match == nil {
+			return "", nil, fmt.Errorf("table %s is not in tables; list it as database.table or qualify it in the query", tn.Name.String())
+		}
+		tn.Qualifier = sqlparser.NewTableIdent(match.Database)
+		ate.Expr = tn
+		if key := strings.ToLower(match.String()); !seen[key] {
+			seen[key] = true
+			qualified = append(qualified, match.String())
+		}
+	}
+	return sqlparser.String(stmt), qualified, nil
+}
+
+// QualifyDefaultDatabase rewrites the unqualified table references of a
+// SELECT or UNION to database.table, so the statement reads the same tables
+// without database being selected with USE. Qualified references and DUAL
+// are left as written.
+func QualifyDefaultDatabase(sqlText, database string) (string, error) {
+	stmt, targets, err := unqualifiedTables(sqlText)
+	if err != nil {
+		return "", fmt.Errorf("could not parse the query: %w", err)
+	}
+	if stmt == nil {
+		return "", fmt.Errorf("only SELECT statements can be qualified")
+	}
+	if len(targets) == 0 {
+		return sqlText, nil
+	}
+	for _, ate := range targets {
+		tn := ate.Expr.(sqlparser.TableName)
+		if strings.EqualFold(tn.Name.String(), "dual") {
+			continue
+		}
+		tn.Qualifier = sqlparser.NewTableIdent(database)
+		ate.Expr = tn
+	}
+	return sqlparser.String(stmt), nil
+}
+
+// unqualifiedTables parses a SELECT or UNION and returns it with its table
+// references that name no database. stmt is nil for other statements.
+func unqualifiedTables(sqlText string) (stmt sqlparser.Statement, targets []*sqlparser.AliasedTableExpr, err error) {
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlText), ";"))
+	stmt, err = sqlparser.Parse(trimmed)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch stmt.(type) {
+	case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+	default:
+		return nil, nil, nil
+	}
+
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			if tn, ok := n.Expr.(sqlparser.TableName); ok && tn.Qualifier.IsEmpty() {
+				targets = append(targets, n)
+			}
+		case *sqlparser.SQLVal:
+			// The parser numbers ? placeholders as :v1, :v2, ...; MySQL
+			// only knows ?, and their order does not change.
+			if n.Type == sqlparser.ValArg {
+				n.Val = []byte("?")
+			}
+		}
+		return true, nil
+	}, stmt)
+	return stmt, targets, nil
+}
@@ -0,0 +1,92 @@
+// internal/util/qualify_test.go
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQualifyTables(t *testing.T) {
+	tables := []TableRef{{Database: "shop", Table: "orders"}, {Database: "crm", Table: "customers"}}
+	tests := []struct {
+		name, sql, want string
+		qualified       int
+	}{
+		{
+			name:      "join across databases",
+			sql:       "SELECT o.id, c.name FROM orders o JOIN customers c ON c.id = o.customer_id WHERE o.total > ?;",
+			want:      "select o.id, c.name from shop.orders as o join crm.customers as c on c.id = o.customer_id where o.total > ?",
+			qualified: 2,
+		},
+		{
+			name:      "qualified references are kept",
+			sql:       "SELECT customers.name FROM crm.customers JOIN orders ON orders.customer_id = customers.id",
+			want:      "select customers.name from crm.customers join shop.orders on orders.customer_id = customers.id",
+			qualified: 1,
+		},
+		{
+			name:      "subquery",
+			sql:       "SELECT name FROM customers WHERE id IN (SELECT customer_id FROM ORDERS)",
+			want:      "select name from crm.customers where id in (select customer_id from shop.ORDERS)",
+			qualified: 2,
+		},
+		{
+			name: "nothing to qualify",
+			sql:  "SELECT 1 FROM shop.orders",
+			want: "SELECT 1 FROM shop.orders",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, qualified, err := QualifyTables(tt.sql, tables)
+			if err != nil {
+				t.Fatalf("QualifyTables: %v", err)
+			}
+			if got != tt.want || len(qualified) != tt.qualified {
+				t.Errorf("got %q (%v), want %q", got, qualified, tt.want)
+			}
+		})
+	}
+}
+
+func TestQualifyTablesRefuses(t *testing.T) {
+	tables := []TableRef{{Database: "shop", Table: "users"}, {Database: "crm", Table: "users"}, {Database: "shop", Table: "orders"}}
+	for sql, want := range map[string]string{
+		"SELECT * FROM users":                          "exists in both",
+		"SELECT * FROM orders JOIN refunds USING (id)": "not in tables",
+		"DELETE FROM orders":                           "only SELECT",
+		"WITH x AS (SELECT 1) SELECT * FROM x":         "could not parse",
+	} {
+		if _, _, err := QualifyTables(sql, tables); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("QualifyTables(%q) = %v, want an error containing %q", sql, err, want)
+		}
+	}
+}
+
+func TestQualifyDefaultDatabase(t *testing.T) {
+	got, err := QualifyDefaultDatabase("SELECT o.id, c.name FROM orders o JOIN crm.customers c ON c.id = o.customer_id WHERE o.id IN (SELECT order_id FROM refunds) AND o.id > ?", "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "select o.id, c.name from shop.orders as o join crm.customers as c on c.id = o.customer_id where o.id in (select order_id from shop.refunds) and o.id > ?"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, err := QualifyDefaultDatabase("SELECT 1 FROM dual", "shop"); err != nil || got != "select 1 from dual" {
+		t.Errorf("DUAL should stay unqualified, got %q (%v)", got, err)
+	}
+	if _, err := QualifyDefaultDatabase("SHOW TABLES", "shop"); err == nil || !strings.Contains(err.Error(), "only SELECT") {
+		t.Errorf("expected SHOW to be refused, got %v", err)
+	}
+}
+
+func TestParseTableRef(t *testing.T) {
+	if ref, err := ParseTableRef(" `shop`.`orders` "); err != nil || ref != (TableRef{Database: "shop", Table: "orders"}) {
+		t.Errorf("ParseTableRef = %+v, %v", ref, err)
+	}
+	for _, s := range []string{"orders", "shop.", ".orders", "a.b.c"} {
+		if _, err := ParseTableRef(s); err == nil {
+			t.Errorf("ParseTableRef(%q) succeeded, want an error", s)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+// internal/util/query_digest.go
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// NormalizedQuery is the literal-free form of a statement plus the objects it references.
+type NormalizedQuery struct {
+	Fingerprint   string   // statement with literals replaced by ?, IN lists collapsed to (?+)
+	Digest        string   // hex SHA-256 of Fingerprint
+	StatementType string   // select, union, show, explain, other
+	Tables        []string // referenced tables (schema-qualified when written that way), sorted
+	Columns       []string // referenced columns (table.column when qualified; aliases resolved), sorted
+	Parsed        bool     // false when the parser could not handle the statement and a lexical fallback was used
+}
+
+var (
+	fingerprintQuoted     = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"`)
+	fingerprintNumber     = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9]+(?:\.[0-9]+)?(?:[eE][-+]?[0-9]+)?)\b`)
+	fingerprintInList     = regexp.MustCompile(`(?i)\bin\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	fingerprintWhitespace = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery returns the fingerprint, digest and referenced objects for sqlText.
+// Two statements that differ only in literal values share the same digest, so
+// callers can group and deduplicate queries (audit log, caching, usage stats).
+func NormalizeQuery(sqlText string) NormalizedQuery {
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlText), ";"))
+
+	stmt, err := sqlparser.Parse(trimmed)
+	if err != nil {
+		return lexicalNormalize(trimmed)
+	}
+	switch stmt.(type) {
+	case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+	default:
+		// SHOW / EXPLAIN / DESCRIBE are only partially represented in the AST;
+		// formatting them back would lose text, so use the lexical form.
+		return lexicalNormalize(trimmed)
+	}
+
+	tables, aliases := collectNormalizedTables(stmt)
+
+	columns := map[string]struct{}{}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.SQLVal:
+			*n = sqlparser.SQLVal{Type: sqlparser.ValArg, Val: []byte("?")}
+		case *sqlparser.ComparisonExpr:
+			if n.Operator == sqlparser.InStr || n.Operator == sqlparser.NotInStr {
+				if tuple, ok := n.Right.(sqlparser.ValTuple); ok && tupleIsLiteral(tuple) {
+					n.Right = sqlparser.ListArg("(?+)")
+				}
+			}
+		case *sqlparser.ColName:
+			name := n.Name.String()
+			if q := n.Qualifier.Name.String(); q != "" {
+				if real, ok := aliases[strings.ToLower(q)]; ok {
+					q = real
+				} else if s := n.Qualifier.Qualifier.String(); s != "" {
+					q = s + "." + q
+				}
+				name = q + "." + name
+			}
+			columns[name] = struct{}{}
+		}
+		return true, nil
+	}, stmt)
+
+	fp := sqlparser.String(stmt)
+	return NormalizedQuery{
+		Fingerprint:   fp,
+		Digest:        digestOf(fp),
+		StatementType: statementTypeOf(stmt),
+		Tables:        sortedKeys(tables),
+		Columns:       sortedKeys(columns),
+		Parsed:        true,
+	}
+}
+
+// QueryDigest is shorthand for NormalizeQuery(sqlText).Digest.
+func QueryDigest(sqlText string) string {
+	return NormalizeQuery(sqlText).Digest
+}
+
+// ExactQueryDigest returns the hex SHA-256 of sqlText as the parser formats
+// it. Unlike QueryDigest it keeps literal values, so statements share it only
+// when they differ in nothing but whitespace, keyword case and comments.
+// Statements the parser cannot format use their trimmed text.
+func ExactQueryDigest(sqlText string) string {
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlText), ";"))
+	if stmt, err := sqlparser.Parse(trimmed); err == nil {
+		switch stmt.(type) {
+		case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+			trimmed = sqlparser.String(stmt)
+		}
+	}
+	return digestOf(trimmed)
+}
+
+// collectNormalizedTables returns referenced tables and a lowercased alias -> table map.
+func collectNormalizedTables(stmt sqlparser.Statement) (map[string]struct{}, map[string]string) {
+	tables := map[string]struct{}{}
+	aliases := map[string]string{}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		ate, ok := node.(*sqlparser.AliasedTableExpr)
+		if !ok {
+			return true, nil
+		}
+		tn, ok := ate.Expr.(sqlparser.TableName)
+		if !ok {
+			// Derived tables: keep walking into the subquery.
+			return true, nil
+		}
+		name := tn.Name.String()
+		if q := tn.Qualifier.String(); q != "" {
+			name = q + "." + name
+		}
+		tables[name] = struct{}{}
+		if !ate.As.IsEmpty() {
+			aliases[strings.ToLower(ate.As.String())] = name
+		}
+		return true, nil
+	}, stmt)
+	return tables, aliases
+}
+
+// TableAliases maps the lowercased names a statement uses for its tables (the
+// alias, or the bare table name when unaliased) to the table as written,
+// schema-qualified when it was. It returns nil if the statement cannot be parsed.
+func TableAliases(sqlText string) map[string]string {
+	stmt, err := sqlparser.Parse(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlText), ";")))
+	if err != nil {
+		return nil
+	}
+	tables, aliases := collectNormalizedTables(stmt)
+	for name := range tables {
+		bare := name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			bare = name[i+1:]
+		}
+		if _, ok := aliases[strings.ToLower(bare)]; !ok {
+			aliases[strings.ToLower(bare)] = name
+		}
+	}
+	return aliases
+}
+
+func tupleIsLiteral(t sqlparser.ValTuple) bool {
+	for _, e := range t {
+		switch e.(type) {
+		case *sqlparser.SQLVal, *sqlparser.NullVal, sqlparser.BoolVal:
+		default:
+			return false
+		}
+	}
+	return len(t) > 0
+}
+
+func statementTypeOf(stmt sqlparser.Statement) string {
+	switch stmt.(type) {
+	case *sqlparser.Select, *sqlparser.ParenSelect:
+		return "select"
+	case *sqlparser.Union:
+		return "union"
+	default:
+		return "other"
+	}
+}
+
+// lexicalNormalize fingerprints statements the parser does not support.
+func lexicalNormalize(sqlText string) NormalizedQuery {
+	fp := fingerprintQuoted.ReplaceAllString(sqlText, "?")
+	fp = fingerprintNumber.ReplaceAllString(fp, "?")
+	fp = fingerprintWhitespace.ReplaceAllString(strings.TrimSpace(fp), " ")
+	fp = strings.ToLower(fp)
+	fp = fingerprintInList.ReplaceAllString(fp, "in (?+)")
+
+	typ := "other"
+	if fields := strings.Fields(fp); len(fields) > 0 {
+		switch fields[0] {
+		case "select", "show", "explain":
+			typ = fields[0]
+		case "describe", "desc":
+			typ = "explain"
+		}
+	}
+	return NormalizedQuery{
+		Fingerprint:   fp,
+		Digest:        digestOf(fp),
+		StatementType: typ,
+		Tables:        []string{},
+		Columns:       []string{},
+	}
+}
+
+func digestOf(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
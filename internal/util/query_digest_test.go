@@ -0,0 +1,99 @@
+// internal/util/query_digest_test.go
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeQueryGroupsByShape(t *testing.T) {
+	a := NormalizeQuery("SELECT id FROM users WHERE email = 'a@example.com' AND age > 30 LIMIT 5")
+	b := NormalizeQuery("select id   from users where email = 'b@example.com' and age > 41 limit 10;")
+	if a.Digest != b.Digest {
+		t.Errorf("expected equal digests:\n  %s\n  %s", a.Fingerprint, b.Fingerprint)
+	}
+	if a.Fingerprint != "select id from users where email = ? and age > ? limit ?" {
+		t.Errorf("unexpected fingerprint: %q", a.Fingerprint)
+	}
+	if len(a.Digest) != 64 {
+		t.Errorf("expected 64-char hex digest, got %q", a.Digest)
+	}
+
+	c := NormalizeQuery("SELECT id FROM users WHERE email = 'x' AND age > 1 AND active = 1")
+	if c.Digest == a.Digest {
+		t.Error("expected different digest for different statement shape")
+	}
+}
+
+func TestNormalizeQueryCollapsesInLists(t *testing.T) {
+	a := NormalizeQuery("SELECT * FROM t WHERE id IN (1, 2, 3)")
+	b := NormalizeQuery("SELECT * FROM t WHERE id IN (7)")
+	if a.Digest != b.Digest {
+		t.Errorf("expected IN lists of different length to share a digest: %q vs %q", a.Fingerprint, b.Fingerprint)
+	}
+}
+
+func TestNormalizeQueryTablesAndColumns(t *testing.T) {
+	n := NormalizeQuery("SELECT u.id, name FROM shop.users u JOIN orders o ON o.user_id = u.id WHERE o.total > 100")
+	if !n.Parsed || n.StatementType != "select" {
+		t.Fatalf("expected parsed select, got %+v", n)
+	}
+	if want := []string{"orders", "shop.users"}; !reflect.DeepEqual(n.Tables, want) {
+		t.Errorf("tables = %v, want %v", n.Tables, want)
+	}
+	if want := []string{"name", "orders.total", "orders.user_id", "shop.users.id"}; !reflect.DeepEqual(n.Columns, want) {
+		t.Errorf("columns = %v, want %v", n.Columns, want)
+	}
+
+	u := NormalizeQuery("SELECT a FROM t1 UNION SELECT b FROM (SELECT b FROM t2) d")
+	if u.StatementType != "union" || !reflect.DeepEqual(u.Tables, []string{"t1", "t2"}) {
+		t.Errorf("unexpected union result: %+v", u)
+	}
+}
+
+func TestNormalizeQueryLexicalFallback(t *testing.T) {
+	a := NormalizeQuery("SHOW TABLES LIKE 'order%'")
+	b := NormalizeQuery("show   tables like \"user%\"")
+	if a.Parsed {
+		t.Error("expected lexical fallback for SHOW")
+	}
+	if a.StatementType != "show" || a.Digest != b.Digest {
+		t.Errorf("unexpected fallback: %+v / %+v", a, b)
+	}
+	if a.Fingerprint != "show tables like ?" {
+		t.Errorf("unexpected fingerprint: %q", a.Fingerprint)
+	}
+	if got := NormalizeQuery("DESCRIBE users").StatementType; got != "explain" {
+		t.Errorf("expected explain type for DESCRIBE, got %q", got)
+	}
+}
+
+func TestQueryDigest(t *testing.T) {
+	if QueryDigest("SELECT 1") != QueryDigest("select 2") {
+		t.Error("expected literal-only difference to share a digest")
+	}
+}
+
+func TestExactQueryDigest(t *testing.T) {
+	base := ExactQueryDigest("SELECT id FROM users WHERE id = 1")
+	if ExactQueryDigest("select  id\nfrom users where id=1 /* again */;") != base {
+		t.Error("expected whitespace, keyword case and comments to be ignored")
+	}
+	if ExactQueryDigest("SELECT id FROM users WHERE id = 2") == base {
+		t.Error("expected different literals to get different digests")
+	}
+	if ExactQueryDigest("SHOW TABLES;") != ExactQueryDigest(" SHOW TABLES ") || ExactQueryDigest("SHOW TABLES") == ExactQueryDigest("SHOW DATABASES") {
+		t.Error("unexpected digests of unparsed statements")
+	}
+}
+
+func TestTableAliases(t *testing.T) {
+	got := TableAliases("SELECT * FROM shop.orders o JOIN customers ON customers.id = o.customer_id;")
+	want := map[string]string{"o": "shop.orders", "orders": "shop.orders", "customers": "customers"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TableAliases = %v, want %v", got, want)
+	}
+	if TableAliases("SELECT FROM WHERE (") != nil {
+		t.Error("expected nil for unparsable statement")
+	}
+}
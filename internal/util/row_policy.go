@@ -0,0 +1,234 @@
+// internal/util/row_policy.go
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// Row policies make a predicate mandatory for every read of a table: each
+// reference to the table in a SELECT is replaced by a derived table that
+// applies the predicate, so
+//
+//	SELECT o.id FROM orders o LEFT JOIN refunds r ON r.order_id = o.id
+//
+// becomes
+//
+//	select o.id from (select * from orders where tenant_id = 1234) as o left join refunds as r on r.order_id = o.id
+//
+// The derived table keeps outer and anti-join semantics intact, which adding
+// the predicate to WHERE would not, and MySQL merges it into the outer query
+// so indexes on the predicate columns are still used.
+
+// RowPolicy is a mandatory predicate on a table.
+type RowPolicy struct {
+	Database  string // "" = the table in any database
+	Table     string
+	Predicate string // boolean SQL expression; {session.name} is replaced by a session value
+}
+
+// sessionRefRe matches {session.name} in a predicate.
+var sessionRefRe = regexp.MustCompile(`\{session\.([A-Za-z0-9_]+)\}`)
+
+// RowPolicySessionRefs returns the session values a predicate uses.
+func RowPolicySessionRefs(predicate string) []string {
+	var out []string
+	for _, m := range sessionRefRe.FindAllStringSubmatch(predicate, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+// CheckRowPolicyPredicate reports whether predicate parses as a boolean
+// expression once its session references are filled in.
+func CheckRowPolicyPredicate(predicate string) error {
+	_, err := parsePredicate(sessionRefRe.ReplaceAllString(predicate, "0"))
+	return err
+}
+
+// ApplyRowPolicies rewrites sqlText so every read of a table with a policy
+// goes through its predicate. defaultDB resolves unqualified table names;
+// when it is empty, a policy on database.table also applies to unqualified
+// references to table. Statements other than SELECT and UNION that name a
+// policy table, statements the parser can only read with MySQL 8 constructs
+// lifted out (WITH, window functions), and predicates whose session values
+// are missing are refused rather than run unfiltered. changed reports whether
+// the statement was rewritten.
+func ApplyRowPolicies(sqlText, defaultDB string, policies []RowPolicy, session map[string]string) (out string, changed bool, err error) {
+	if len(policies) == 0 {
+		return sqlText, false, nil
+	}
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlText), ";"))
+	match := func(tn sqlparser.TableName) *RowPolicy {
+		db := tn.Qualifier.String()
+		if db == "" {
+			db = defaultDB
+		}
+		for i, p := range policies {
+			if !strings.EqualFold(p.Table, tn.Name.String()) {
+				continue
+			}
+			if p.Database == "" || db == "" || strings.EqualFold(p.Database, db) {
+				return &policies[i]
+			}
+		}
+		return nil
+	}
+
+	stmt, perr := sqlparser.Parse(trimmed)
+	if perr != nil {
+		q, err := parseQuery(trimmed)
+		if err != nil {
+			return "", false, err
+		}
+		for _, s := range q.statements() {
+			if p := firstPolicyTable(s, match); p != nil {
+				return "", false, fmt.Errorf("table %s has a row policy, which cannot be applied to WITH clauses or window functions; rewrite the query without them", policyName(p))
+			}
+		}
+		return sqlText, false, nil
+	}
+
+	switch stmt.(type) {
+	case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+	case *sqlparser.Show, *sqlparser.OtherRead, *sqlparser.Use, *sqlparser.Set:
+		// Metadata only: no table rows are returned.
+		return sqlText, false, nil
+	default:
+		if p := firstPolicyTable(stmt, match); p != nil {
+			return "", false, fmt.Errorf("table %s has a row policy; only SELECT statements may use it", policyName(p))
+		}
+		return sqlText, false, nil
+	}
+
+	// Collect first: the derived tables added below must not be rewritten again.
+	var targets []*sqlparser.AliasedTableExpr
+	var columns []*sqlparser.ColName
+	var stars []*sqlparser.StarExpr
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			if tn, ok := n.Expr.(sqlparser.TableName); ok && match(tn) != nil {
+				targets = append(targets, n)
+			}
+		case *sqlparser.ColName:
+			columns = append(columns, n)
+		case *sqlparser.StarExpr:
+			stars = append(stars, n)
+		case *sqlparser.SQLVal:
+			// The parser numbers ? placeholders as :v1, :v2, ...; MySQL
+			// only knows ?, and their order does not change.
+			if n.Type == sqlparser.ValArg {
+				n.Val = []byte("?")
+			}
+		}
+		return true, nil
+	}, stmt)
+	if len(targets) == 0 {
+		return sqlText, false, nil
+	}
+
+	for _, ate := range targets {
+		tn := ate.Expr.(sqlparser.TableName)
+		p := match(tn)
+		pred, err := fillSessionRefs(p, session)
+		if err != nil {
+			return "", false, err
+		}
+		where, err := parsePredicate(pred)
+		if err != nil {
+			return "", false, fmt.Errorf("row policy on %s: %w", policyName(p), err)
+		}
+		if ate.As.IsEmpty() {
+			ate.As = tn.Name
+			// db.table.column would no longer resolve against the derived table.
+			for _, c := range columns {
+				if sameTable(c.Qualifier, tn) {
+					c.Qualifier.Qualifier = sqlparser.NewTableIdent("")
+				}
+			}
+			for _, s := range stars {
+				if sameTable(s.TableName, tn) {
+					s.TableName.Qualifier = sqlparser.NewTableIdent("")
+				}
+			}
+		}
+		ate.Expr = &sqlparser.Subquery{Select: &sqlparser.Select{
+			SelectExprs: sqlparser.SelectExprs{&sqlparser.StarExpr{}},
+			From:        sqlparser.TableExprs{&sqlparser.AliasedTableExpr{Expr: tn, Partitions: ate.Partitions, Hints: ate.Hints}},
+			Where:       sqlparser.NewWhere(sqlparser.WhereStr, where),
+		}}
+		ate.Partitions, ate.Hints = nil, nil
+	}
+	return sqlparser.String(stmt), true, nil
+}
+
+// firstPolicyTable returns the policy of the first table in stmt that has one.
+func firstPolicyTable(stmt sqlparser.SQLNode, match func(sqlparser.TableName) *RowPolicy) *RowPolicy {
+	var found *RowPolicy
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if tn, ok := node.(sqlparser.TableName); ok && !tn.IsEmpty() {
+			if p := match(tn); p != nil {
+				found = p
+				return false, nil
+			}
+		}
+		return found == nil, nil
+	}, stmt)
+	return found
+}
+
+// sameTable reports whether qualifier is tn written with its database.
+func sameTable(qualifier, tn sqlparser.TableName) bool {
+	return !qualifier.Qualifier.IsEmpty() &&
+		strings.EqualFold(qualifier.Qualifier.String(), tn.Qualifier.String()) &&
+		strings.EqualFold(qualifier.Name.String(), tn.Name.String())
+}
+
+// fillSessionRefs replaces {session.name} in p's predicate with SQL literals.
+func fillSessionRefs(p *RowPolicy, session map[string]string) (string, error) {
+	var missing string
+	pred := sessionRefRe.ReplaceAllStringFunc(p.Predicate, func(ref string) string {
+		name := sessionRefRe.FindStringSubmatch(ref)[1]
+		v, ok := session[name]
+		if !ok {
+			if missing == "" {
+				missing = name
+			}
+			return ref
+		}
+		if intLiteralRe.MatchString(v) {
+			return v
+		}
+		return sqlparser.String(sqlparser.NewStrVal([]byte(v)))
+	})
+	if missing != "" {
+		return "", fmt.Errorf("row policy on %s needs session value %q, which is not set", policyName(p), missing)
+	}
+	return pred, nil
+}
+
+var intLiteralRe = regexp.MustCompile(`^-?[0-9]{1,18}$`)
+
+// parsePredicate parses a boolean expression.
+func parsePredicate(pred string) (sqlparser.Expr, error) {
+	stmt, err := sqlparser.Parse("select 1 from t where " + pred)
+	if err != nil {
+		return nil, fmt.Errorf("invalid predicate %q: %w", pred, err)
+	}
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok || sel.Where == nil || sel.Limit != nil || sel.OrderBy != nil || sel.GroupBy != nil || sel.Lock != "" {
+		return nil, fmt.Errorf("invalid predicate %q: must be a single boolean expression", pred)
+	}
+	return sel.Where.Expr, nil
+}
+
+func policyName(p *RowPolicy) string {
+	if p.Database == "" {
+		return p.Table
+	}
+	return p.Database + "." + p.Table
+}
@@ -0,0 +1,102 @@
+// internal/util/row_policy_test.go
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+var testRowPolicies = []RowPolicy{
+	{Table: "orders", Predicate: "tenant_id = {session.tenant}"},
+	{Database: "shop", Table: "invoices", Predicate: "tenant_id = {session.tenant} AND deleted = 0"},
+}
+
+func TestApplyRowPolicies(t *testing.T) {
+	session := map[string]string{"tenant": "1234"}
+	tests := []struct {
+		name, sql, want string
+	}{
+		{
+			name: "left join keeps its alias",
+			sql:  "SELECT o.id FROM orders o LEFT JOIN refunds r ON r.order_id = o.id",
+			want: "select o.id from (select * from orders where tenant_id = 1234) as o left join refunds as r on r.order_id = o.id",
+		},
+		{
+			name: "qualified references and index hints",
+			sql:  "SELECT shop.orders.id FROM shop.orders USE INDEX (ix_tenant) WHERE shop.orders.total > 5;",
+			want: "select orders.id from (select * from shop.orders use index (ix_tenant) where tenant_id = 1234) as orders where orders.total > 5",
+		},
+		{
+			name: "default database",
+			sql:  "SELECT * FROM invoices",
+			want: "select * from (select * from invoices where tenant_id = 1234 and deleted = 0) as invoices",
+		},
+		{
+			name: "placeholders",
+			sql:  "SELECT * FROM orders WHERE id = ? AND status IN (?, ?)",
+			want: "select * from (select * from orders where tenant_id = 1234) as orders where id = ? and `status` in (?, ?)",
+		},
+		{
+			name: "subquery inside a union",
+			sql:  "SELECT id FROM users WHERE id IN (SELECT user_id FROM orders) UNION SELECT 1 FROM dual",
+			want: "select id from users where id in (select user_id from (select * from orders where tenant_id = 1234) as orders) union select 1 from dual",
+		},
+	}
+	for _, tt := range tests {
+		got, changed, err := ApplyRowPolicies(tt.sql, "shop", testRowPolicies, session)
+		if err != nil || !changed || got != tt.want {
+			t.Errorf("%s:\n got %q (changed=%v, err=%v)\nwant %q", tt.name, got, changed, err, tt.want)
+		}
+	}
+
+	for _, sql := range []string{"SELECT * FROM other.invoices", "SHOW TABLES", "DESCRIBE orders", "SELECT 1"} {
+		got, changed, err := ApplyRowPolicies(sql, "shop", testRowPolicies, session)
+		if err != nil || changed || got != sql {
+			t.Errorf("%q should be left alone, got %q (changed=%v, err=%v)", sql, got, changed, err)
+		}
+	}
+}
+
+func TestApplyRowPoliciesSessionValues(t *testing.T) {
+	got, _, err := ApplyRowPolicies("SELECT * FROM orders", "", testRowPolicies, map[string]string{"tenant": "acme's"})
+	if err != nil || !strings.Contains(got, `tenant_id = 'acme\'s'`) {
+		t.Errorf("string session value not quoted: %q, %v", got, err)
+	}
+	if _, _, err := ApplyRowPolicies("SELECT * FROM orders", "", testRowPolicies, nil); err == nil || !strings.Contains(err.Error(), `"tenant"`) {
+		t.Errorf("expected missing session value error, got %v", err)
+	}
+}
+
+func TestApplyRowPoliciesRefuses(t *testing.T) {
+	session := map[string]string{"tenant": "1"}
+	for _, sql := range []string{
+		"WITH x AS (SELECT * FROM orders) SELECT * FROM x",
+		"SELECT id, ROW_NUMBER() OVER (ORDER BY id) FROM orders",
+		"DELETE FROM orders WHERE id = 1",
+	} {
+		if _, _, err := ApplyRowPolicies(sql, "", testRowPolicies, session); err == nil {
+			t.Errorf("%q: expected the query to be refused", sql)
+		}
+	}
+	// MySQL 8 constructs are fine when no policy table is involved.
+	sql := "WITH x AS (SELECT * FROM users) SELECT * FROM x"
+	if got, changed, err := ApplyRowPolicies(sql, "", testRowPolicies, session); err != nil || changed || got != sql {
+		t.Errorf("%q: got %q (changed=%v, err=%v)", sql, got, changed, err)
+	}
+}
+
+func TestCheckRowPolicyPredicate(t *testing.T) {
+	for _, ok := range []string{"tenant_id = {session.tenant}", "region IN ('eu', 'uk') AND NOT archived"} {
+		if err := CheckRowPolicyPredicate(ok); err != nil {
+			t.Errorf("%q: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "tenant_id =", "1 = 1 LIMIT 1", "1 = 1 UNION SELECT 1"} {
+		if err := CheckRowPolicyPredicate(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+	if refs := RowPolicySessionRefs("a = {session.tenant} AND b = {session.region}"); len(refs) != 2 || refs[1] != "region" {
+		t.Errorf("RowPolicySessionRefs = %v", refs)
+	}
+}
@@ -99,18 +99,14 @@ func ValidateSQLWithParser(sqlText string) error {
 	}
 	sqlText = statements[0]
 
-	// Parse the SQL statement
-	stmt, err := sqlparser.Parse(sqlText)
+	// Parse the SQL statement; if parsing fails, reject the query for safety
+	q, err := parseQuery(sqlText)
 	if err != nil {
-		// If parsing fails, reject the query for safety
-		return &ParserValidationError{
-			Reason:    "failed to parse SQL statement",
-			Statement: err.Error(),
-		}
+		return err
 	}
 
-	// Validate the parsed statement
-	return validateStatement(stmt)
+	// Validate the parsed statement, its CTEs and window specifications
+	return validateParsedQuery(q)
 }
 
 // validateStatement checks if a parsed SQL statement is allowed.
@@ -468,14 +464,16 @@ func collectFromOtherRead(sqlText string, out map[string]struct{}) (handled bool
 			if inner == "" {
 				return true, nil
 			}
-			st, perr := sqlparser.Parse(inner)
+			q, perr := parseQuery(inner)
 			if perr != nil {
 				return true, &ParserValidationError{
 					Reason:    "failed to parse SQL after EXPLAIN",
-					Statement: perr.Error(),
+					Statement: perr.(*ParserValidationError).Statement,
 				}
 			}
-			collectStmtReferencedSchemas(st, out)
+			for _, st := range q.statements() {
+				collectStmtReferencedSchemas(st, out)
+			}
 			return true, nil
 		}
 	}
@@ -586,16 +584,13 @@ func ReferencedSchemaQualifiers(sqlText string) (map[string]struct{}, error) {
 	}
 	sqlText = statements[0]
 
-	stmt, err := sqlparser.Parse(sqlText)
+	q, err := parseQuery(sqlText)
 	if err != nil {
-		return nil, &ParserValidationError{
-			Reason:    "failed to parse SQL statement",
-			Statement: err.Error(),
-		}
+		return nil, err
 	}
 
 	out := make(map[string]struct{})
-	switch stmt.(type) {
+	switch q.stmt.(type) {
 	case *sqlparser.OtherRead:
 		ok, err := collectFromOtherRead(sqlText, out)
 		if err != nil {
@@ -608,7 +603,9 @@ func ReferencedSchemaQualifiers(sqlText string) (map[string]struct{}, error) {
 			}
 		}
 	default:
-		collectStmtReferencedSchemas(stmt, out)
+		for _, st := range q.statements() {
+			collectStmtReferencedSchemas(st, out)
+		}
 	}
 	return out, nil
 }
@@ -642,13 +639,13 @@ func InjectLimit(sqlText string, limit int) string {
 	}
 
 	trimmed := strings.TrimSpace(sqlText)
-	stmt, err := sqlparser.Parse(trimmed)
+	q, err := parseQuery(trimmed)
 	if err != nil {
 		return sqlText
 	}
 
 	var hasLimit bool
-	switch s := stmt.(type) {
+	switch s := q.stmt.(type) {
 	case *sqlparser.Select:
 		hasLimit = s.Limit != nil
 	case *sqlparser.Union:
@@ -681,13 +678,13 @@ func InjectLimitWithOffset(sqlText string, fetchLimit, offset int) (string, erro
 	}
 
 	trimmed := strings.TrimSpace(sqlText)
-	stmt, err := sqlparser.Parse(trimmed)
+	q, err := parseQuery(trimmed)
 	if err != nil {
 		return "", fmt.Errorf("cannot paginate unparsable SQL: %w", err)
 	}
 
 	var hasLimit bool
-	switch s := stmt.(type) {
+	switch s := q.stmt.(type) {
 	case *sqlparser.Select:
 		hasLimit = s.Limit != nil
 	case *sqlparser.Union:
@@ -704,15 +701,67 @@ func InjectLimitWithOffset(sqlText string, fetchLimit, offset int) (string, erro
 	return fmt.Sprintf("%s LIMIT %d OFFSET %d", base, fetchLimit, offset), nil
 }
 
+// InjectMaxExecutionTime adds the optimizer hint MAX_EXECUTION_TIME(ms) after
+// the first SELECT keyword of a SELECT or UNION statement, so MySQL aborts the
+// query itself after ms milliseconds. MySQL reads a single hint comment per
+// query block, so an existing /*+ ... */ right after SELECT is extended rather
+// than followed by a second one. WITH queries (whose first SELECT belongs to a
+// CTE, where the hint is ignored), other statements and SQL that already sets
+// MAX_EXECUTION_TIME are returned unchanged.
+func InjectMaxExecutionTime(sqlText string, ms int64) string {
+	if ms <= 0 || strings.Contains(strings.ToUpper(sqlText), "MAX_EXECUTION_TIME") {
+		return sqlText
+	}
+	at := len(sqlText) - len(strings.TrimLeft(sqlText, "( \t\r\n"))
+	rest := sqlText[at:]
+	if len(rest) < 6 || !strings.EqualFold(rest[:6], "SELECT") {
+		return sqlText
+	}
+	if len(rest) > 6 && isIdentByte(rest[6]) {
+		return sqlText
+	}
+	at += 6
+	hint := fmt.Sprintf("MAX_EXECUTION_TIME(%d)", ms)
+	after := strings.TrimLeft(sqlText[at:], " \t\r\n")
+	if strings.HasPrefix(after, "/*+") {
+		pos := len(sqlText) - len(after) + 3
+		return sqlText[:pos] + " " + hint + sqlText[pos:]
+	}
+	return sqlText[:at] + " /*+ " + hint + " */" + sqlText[at:]
+}
+
 // HasSelectStar reports whether the SQL statement selects all columns with a
 // bare "*" wildcard (e.g. SELECT * or SELECT t.*).  Non-SELECT statements and
 // statements that cannot be parsed always return false.
 func HasSelectStar(sqlText string) bool {
-	stmt, err := sqlparser.Parse(strings.TrimSpace(sqlText))
+	q, err := parseQuery(strings.TrimSpace(sqlText))
 	if err != nil {
 		return false
 	}
-	return selectHasStar(stmt)
+	return selectHasStar(q.stmt)
+}
+
+// HasOrderBy reports whether the SQL statement is a SELECT (or UNION) whose
+// result rows are sorted by a top-level ORDER BY. Non-SELECT statements and
+// statements that cannot be parsed return false.
+func HasOrderBy(sqlText string) bool {
+	q, err := parseQuery(strings.TrimSpace(sqlText))
+	if err != nil {
+		return false
+	}
+	stmt := q.stmt
+	for {
+		switch s := stmt.(type) {
+		case *sqlparser.Select:
+			return len(s.OrderBy) > 0
+		case *sqlparser.Union:
+			return len(s.OrderBy) > 0
+		case *sqlparser.ParenSelect:
+			stmt = s.Select
+		default:
+			return false
+		}
+	}
 }
 
 // selectHasStar recursively checks parsed statements for star expressions.
@@ -731,3 +780,64 @@ func selectHasStar(stmt sqlparser.Statement) bool {
 	}
 	return false
 }
+
+// BindNamedParams rewrites :name placeholders to ? and returns the parameter
+// names in placeholder order (a name used twice appears twice). Text inside
+// quotes, backticks and comments is left alone, as are :: and := operators.
+func BindNamedParams(sqlText string) (string, []string) {
+	var b strings.Builder
+	var names []string
+	isIdent := func(c byte, first bool) bool {
+		return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+	}
+
+	for i := 0; i < len(sqlText); i++ {
+		c := sqlText[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(sqlText) {
+				if sqlText[j] == '\\' && c != '`' {
+					j += 2
+					continue
+				}
+				if sqlText[j] == c {
+					break
+				}
+				j++
+			}
+			if j >= len(sqlText) {
+				j = len(sqlText) - 1
+			}
+			b.WriteString(sqlText[i : j+1])
+			i = j
+		case c == '-' && strings.HasPrefix(sqlText[i:], "-- "), c == '#':
+			end := strings.IndexByte(sqlText[i:], '\n')
+			if end < 0 {
+				end = len(sqlText) - i - 1
+			}
+			b.WriteString(sqlText[i : i+end+1])
+			i += end
+		case c == '/' && strings.HasPrefix(sqlText[i:], "/*"):
+			end := strings.Index(sqlText[i+2:], "*/")
+			if end < 0 {
+				b.WriteString(sqlText[i:])
+				i = len(sqlText)
+				continue
+			}
+			b.WriteString(sqlText[i : i+end+4])
+			i += end + 3
+		case c == ':' && i+1 < len(sqlText) && isIdent(sqlText[i+1], true) && (i == 0 || sqlText[i-1] != ':'):
+			j := i + 1
+			for j < len(sqlText) && isIdent(sqlText[j], false) {
+				j++
+			}
+			names = append(names, sqlText[i+1:j])
+			b.WriteByte('?')
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), names
+}
@@ -321,6 +321,75 @@ func TestValidateSQLCombined(t *testing.T) {
 	}
 }
 
+func TestValidateSQLCombined_SetOperations(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		expectErr bool
+	}{
+		{"union", "SELECT id FROM a UNION SELECT id FROM b", false},
+		{"union all", "SELECT id FROM a UNION ALL SELECT id FROM b", false},
+		{"union distinct", "SELECT id FROM a UNION DISTINCT SELECT id FROM b", false},
+		{"three-way union", "SELECT id FROM a UNION SELECT id FROM b UNION ALL SELECT id FROM c", false},
+		{"intersect", "SELECT id FROM a INTERSECT SELECT id FROM b", false},
+		{"intersect all", "SELECT id FROM a INTERSECT ALL SELECT id FROM b", false},
+		{"except", "SELECT id FROM a EXCEPT SELECT id FROM b", false},
+		{"except distinct", "select id from a except distinct select id from b", false},
+		{"mixed operators", "SELECT id FROM a UNION SELECT id FROM b EXCEPT SELECT id FROM c INTERSECT SELECT id FROM d", false},
+		{"parenthesized operands", "(SELECT id FROM a) UNION (SELECT id FROM b)", false},
+		{"parenthesized with order and limit", "(SELECT id FROM a ORDER BY id LIMIT 5) UNION ALL (SELECT id FROM b LIMIT 5) ORDER BY id LIMIT 3", false},
+		{"nested parentheses", "((SELECT id FROM a) UNION (SELECT id FROM b)) UNION SELECT id FROM c", false},
+		{"parenthesized intersect", "(SELECT id FROM a) INTERSECT (SELECT id FROM b)", false},
+		{"lone parenthesized select", "(SELECT id FROM a)", false},
+		{"doubly parenthesized select", "((SELECT id FROM a))", false},
+		{"union in derived table", "SELECT * FROM (SELECT id FROM a UNION SELECT id FROM b) AS u", false},
+		{"except in subquery", "SELECT * FROM t WHERE id IN (SELECT id FROM a EXCEPT SELECT id FROM b)", false},
+		{"union of ctes", "WITH x AS (SELECT 1 AS id) SELECT id FROM x UNION SELECT id FROM b", false},
+
+		{"dangerous function in second operand", "SELECT id FROM a UNION SELECT SLEEP(5)", true},
+		{"dangerous function after intersect", "SELECT id FROM a INTERSECT SELECT BENCHMARK(1, 1)", true},
+		{"system schema after except", "SELECT user FROM a EXCEPT SELECT user FROM mysql.user", true},
+		{"system schema in parenthesized operand", "(SELECT id FROM a) UNION (SELECT id FROM information_schema.tables)", true},
+		{"stacked statement", "(SELECT id FROM a) UNION (SELECT id FROM b); DROP TABLE a", true},
+		{"dml in parentheses", "(DELETE FROM a)", true},
+		{"union into outfile", "SELECT id FROM a UNION SELECT id FROM b INTO OUTFILE '/tmp/x'", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSQLCombined(tc.query)
+			if tc.expectErr && err == nil {
+				t.Errorf("expected error for query: %s", tc.query)
+			}
+			if !tc.expectErr && err != nil {
+				t.Errorf("unexpected error: %v\nQuery: %s", err, tc.query)
+			}
+		})
+	}
+}
+
+func TestSetOperationSchemasAndLimit(t *testing.T) {
+	refs, err := ReferencedSchemaQualifiers("(SELECT id FROM shop.a) INTERSECT (SELECT id FROM crm.b EXCEPT SELECT id FROM hr.c)")
+	if err != nil {
+		t.Fatalf("ReferencedSchemaQualifiers: %v", err)
+	}
+	for _, db := range []string{"shop", "crm", "hr"} {
+		if _, ok := refs[db]; !ok {
+			t.Errorf("expected %s among %v", db, refs)
+		}
+	}
+
+	if got := InjectLimit("SELECT id FROM a EXCEPT SELECT id FROM b", 10); got != "SELECT id FROM a EXCEPT SELECT id FROM b LIMIT 10" {
+		t.Errorf("InjectLimit(EXCEPT) = %q", got)
+	}
+	if got := InjectLimit("(SELECT id FROM a)", 10); got != "(SELECT id FROM a) LIMIT 10" {
+		t.Errorf("InjectLimit(parenthesized) = %q", got)
+	}
+	if got := InjectLimit("(SELECT id FROM a) UNION (SELECT id FROM b) LIMIT 5", 10); got != "(SELECT id FROM a) UNION (SELECT id FROM b) LIMIT 5" {
+		t.Errorf("InjectLimit kept an existing LIMIT: %q", got)
+	}
+}
+
 func TestReferencedSchemaQualifiers(t *testing.T) {
 	tests := []struct {
 		query string
@@ -591,3 +660,79 @@ func TestHasSelectStar(t *testing.T) {
 		})
 	}
 }
+
+func TestHasOrderBy(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT id, body FROM docs ORDER BY id LIMIT 10", true},
+		{"SELECT id, body FROM docs LIMIT 10", false},
+		{"SELECT id FROM (SELECT id FROM docs ORDER BY id) d", false},
+		{"SELECT id FROM a UNION SELECT id FROM b ORDER BY id", true},
+		{"(SELECT id FROM docs ORDER BY id)", true},
+		{"SHOW TABLES", false},
+	}
+	for _, tc := range tests {
+		if got := HasOrderBy(tc.sql); got != tc.want {
+			t.Errorf("HasOrderBy(%q) = %v, want %v", tc.sql, got, tc.want)
+		}
+	}
+}
+
+func TestBindNamedParams(t *testing.T) {
+	tests := []struct {
+		sql       string
+		wantSQL   string
+		wantNames []string
+	}{
+		{
+			"SELECT * FROM orders WHERE customer_id = :customer_id AND status = :status LIMIT :limit",
+			"SELECT * FROM orders WHERE customer_id = ? AND status = ? LIMIT ?",
+			[]string{"customer_id", "status", "limit"},
+		},
+		{
+			"SELECT ':not_a_param', `a:b` FROM t WHERE x = :x OR y = :x",
+			"SELECT ':not_a_param', `a:b` FROM t WHERE x = ? OR y = ?",
+			[]string{"x", "x"},
+		},
+		{
+			"SELECT 'it\\'s :quoted' /* :comment */ FROM t -- :line\nWHERE a = :a",
+			"SELECT 'it\\'s :quoted' /* :comment */ FROM t -- :line\nWHERE a = ?",
+			[]string{"a"},
+		},
+		{"SELECT @v := 1, '10:30'", "SELECT @v := 1, '10:30'", nil},
+	}
+	for _, tt := range tests {
+		gotSQL, gotNames := BindNamedParams(tt.sql)
+		if gotSQL != tt.wantSQL {
+			t.Errorf("BindNamedParams(%q) sql = %q, want %q", tt.sql, gotSQL, tt.wantSQL)
+		}
+		if strings.Join(gotNames, ",") != strings.Join(tt.wantNames, ",") {
+			t.Errorf("BindNamedParams(%q) names = %v, want %v", tt.sql, gotNames, tt.wantNames)
+		}
+	}
+}
+
+func TestInjectMaxExecutionTime(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"select", "SELECT id FROM t", "SELECT /*+ MAX_EXECUTION_TIME(30000) */ id FROM t"},
+		{"lowercase", "select\nid from t", "select /*+ MAX_EXECUTION_TIME(30000) */\nid from t"},
+		{"union", "(SELECT a FROM t) UNION (SELECT b FROM u)", "(SELECT /*+ MAX_EXECUTION_TIME(30000) */ a FROM t) UNION (SELECT b FROM u)"},
+		{"existing hint", "SELECT /*+ NO_INDEX(t) */ * FROM t", "SELECT /*+ MAX_EXECUTION_TIME(30000) NO_INDEX(t) */ * FROM t"},
+		{"own timeout", "SELECT /*+ MAX_EXECUTION_TIME(5) */ 1", "SELECT /*+ MAX_EXECUTION_TIME(5) */ 1"},
+		{"cte", "WITH c AS (SELECT 1) SELECT * FROM c", "WITH c AS (SELECT 1) SELECT * FROM c"},
+		{"show", "SHOW TABLES", "SHOW TABLES"},
+		{"identifier", "SELECTED", "SELECTED"},
+	}
+	for _, tt := range tests {
+		if got := InjectMaxExecutionTime(tt.in, 30000); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := InjectMaxExecutionTime("SELECT 1", 0); got != "SELECT 1" {
+		t.Errorf("expected no hint without a timeout, got %q", got)
+	}
+}
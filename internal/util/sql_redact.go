@@ -0,0 +1,97 @@
+// internal/util/sql_redact.go
+package util
+
+import (
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// RedactLiterals replaces the string, number and hex literals of sqlText with
+// ?, keeping everything else, so logs show the shape of a statement without
+// the values in it. Unlike NormalizeQuery it does not collapse IN lists.
+// SELECT statements go through the parser; other statements, and ones it
+// cannot parse, are redacted lexically.
+func RedactLiterals(sqlText string) string {
+	trimmed := strings.TrimSpace(sqlText)
+	stmt, err := sqlparser.Parse(strings.TrimRight(trimmed, "; \t\r\n"))
+	if err != nil {
+		return redactLexical(trimmed)
+	}
+	switch stmt.(type) {
+	case *sqlparser.Select, *sqlparser.Union, *sqlparser.ParenSelect:
+	default:
+		return redactLexical(trimmed)
+	}
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if n, ok := node.(*sqlparser.SQLVal); ok && n.Type != sqlparser.ValArg {
+			*n = sqlparser.SQLVal{Type: sqlparser.ValArg, Val: []byte("?")}
+		}
+		return true, nil
+	}, stmt)
+	return sqlparser.String(stmt)
+}
+
+// redactLexical replaces quoted strings (also unterminated ones, as left by
+// truncation), x'..'/b'..' literals and numbers with ?. Backquoted identifiers
+// and digits inside identifiers are kept.
+func redactLexical(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '`':
+			j := i + 1
+			for j < len(s) && s[j] != '`' {
+				j++
+			}
+			if j < len(s) {
+				j++
+			}
+			b.WriteString(s[i:j])
+			i = j
+		case (c == 'x' || c == 'X' || c == 'b' || c == 'B') && i+1 < len(s) && s[i+1] == '\'' && (i == 0 || !isIdentByte(s[i-1])):
+			b.WriteByte('?')
+			i = skipQuoted(s, i+1)
+		case c == '\'' || c == '"':
+			b.WriteByte('?')
+			i = skipQuoted(s, i)
+		case c >= '0' && c <= '9' && (i == 0 || !isIdentByte(s[i-1])):
+			j := i
+			for j < len(s) && (isIdentByte(s[j]) || s[j] == '.' ||
+				((s[j] == '+' || s[j] == '-') && (s[j-1] == 'e' || s[j-1] == 'E'))) {
+				j++
+			}
+			b.WriteByte('?')
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skipQuoted returns the index after the string literal starting with the
+// quote at s[start], or len(s) when it is not terminated.
+func skipQuoted(s string, start int) int {
+	quote := s[start]
+	for j := start + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
@@ -0,0 +1,66 @@
+// internal/util/sql_redact_test.go
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactLiterals(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{
+			name: "select through the parser",
+			sql:  "SELECT id FROM users WHERE email = 'alice@example.com' AND age > 30 AND id IN (1, 2) LIMIT 5;",
+			want: "select id from users where email = ? and age > ? and id in (?, ?) limit ?",
+		},
+		{
+			name: "hex and placeholders",
+			sql:  "SELECT * FROM t WHERE h = 0xFF AND x = :x",
+			want: "select * from t where h = ? and x = :x",
+		},
+		{
+			name: "show statement, lexical",
+			sql:  "SHOW TABLES LIKE 'cust%'",
+			want: "SHOW TABLES LIKE ?",
+		},
+		{
+			name: "identifiers with digits are kept",
+			sql:  "EXPLAIN SELECT col1 FROM `t2` WHERE v = -1.5e3 AND s = \"it\"\"s\"",
+			want: "EXPLAIN SELECT col1 FROM `t2` WHERE v = -? AND s = ?",
+		},
+		{
+			name: "truncated string",
+			sql:  "SELECT name FROM users WHERE ssn = '123-45-67...",
+			want: "SELECT name FROM users WHERE ssn = ?",
+		},
+		{
+			name: "escaped quote and typed literal",
+			sql:  "SHOW WARNINGS WHERE x = 'a\\'b' OR y = x'4142'",
+			want: "SHOW WARNINGS WHERE x = ? OR y = ?",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactLiterals(tt.sql); got != tt.want {
+				t.Errorf("RedactLiterals(%q) = %q, want %q", tt.sql, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactLiteralsRemovesValues(t *testing.T) {
+	for _, sql := range []string{
+		"SELECT * FROM users WHERE email = 'bob@example.com'",
+		"SELECT * FROM users WHERE email = 'bob@example.com' UNION SELECT * FROM admins WHERE phone = '5551234'",
+		"SELECT * FROM users WHERE (email = 'bob@example.com'",
+	} {
+		got := RedactLiterals(sql)
+		if strings.Contains(got, "bob@example.com") || strings.Contains(got, "5551234") {
+			t.Errorf("RedactLiterals(%q) kept a literal: %q", sql, got)
+		}
+	}
+}
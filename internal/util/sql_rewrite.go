@@ -0,0 +1,530 @@
+// internal/util/sql_rewrite.go
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// The Vitess parser predates MySQL 8 and cannot parse WITH clauses, window
+// functions (OVER, WINDOW), derived table column lists, INTERSECT / EXCEPT or
+// a lone parenthesized SELECT. parseQuery rewrites those constructs so the
+// remaining statement parses: CTE bodies and window specifications are lifted
+// out and parsed on their own, so validation and schema collection still see
+// everything the query references, and INTERSECT / EXCEPT are read as UNION,
+// which has the same shape.
+
+// parsedCTE is one common table expression of a WITH clause.
+type parsedCTE struct {
+	name string
+	stmt sqlparser.Statement
+}
+
+// parsedQuery is a statement together with the constructs lifted out of it.
+type parsedQuery struct {
+	stmt sqlparser.Statement
+	// with is set when the statement starts with a WITH clause.
+	with bool
+	ctes []parsedCTE
+	// windows holds each window specification as SELECT 1 GROUP BY <partition>
+	// ORDER BY <order>, so its expressions can be walked like a query's.
+	windows []sqlparser.Statement
+}
+
+// statements returns the statement and every lifted CTE body and window.
+func (q *parsedQuery) statements() []sqlparser.Statement {
+	out := []sqlparser.Statement{q.stmt}
+	for _, c := range q.ctes {
+		out = append(out, c.stmt)
+	}
+	return append(out, q.windows...)
+}
+
+// parseQuery parses a single statement, falling back to lifting MySQL 8
+// constructs out of the text when the parser rejects it.
+func parseQuery(sqlText string) (*parsedQuery, error) {
+	stmt, err := sqlparser.Parse(sqlText)
+	if err == nil {
+		return &parsedQuery{stmt: stmt}, nil
+	}
+	parseErr := &ParserValidationError{Reason: "failed to parse SQL statement", Statement: err.Error()}
+
+	r := &queryRewriter{}
+	toks := tokenizeSQL(sqlText)
+	first := nextSignificant(toks, 0)
+	with := first < len(toks) && isWord(toks[first], "WITH")
+	rewritten, rerr := r.level(toks)
+	if rerr != nil {
+		return nil, rerr
+	}
+	if !r.changed {
+		return nil, parseErr
+	}
+	stmt, err = sqlparser.Parse(rewritten)
+	if err != nil {
+		return nil, parseErr
+	}
+
+	q := &parsedQuery{stmt: stmt, with: with}
+	for _, c := range r.ctes {
+		cs, err := sqlparser.Parse(c.sql)
+		if err != nil {
+			return nil, &ParserValidationError{
+				Reason:    fmt.Sprintf("failed to parse common table expression '%s'", c.name),
+				Statement: err.Error(),
+			}
+		}
+		q.ctes = append(q.ctes, parsedCTE{name: c.name, stmt: cs})
+	}
+	for _, w := range r.windows {
+		ws, err := sqlparser.Parse(w)
+		if err != nil {
+			return nil, &ParserValidationError{Reason: "failed to parse window specification", Statement: err.Error()}
+		}
+		q.windows = append(q.windows, ws)
+	}
+	return q, nil
+}
+
+// validateParsedQuery applies validateStatement to the statement and every
+// lifted CTE body and window specification.
+func validateParsedQuery(q *parsedQuery) error {
+	if q.with {
+		if _, ok := q.stmt.(sqlparser.SelectStatement); !ok {
+			if err := validateStatement(q.stmt); err != nil {
+				return err
+			}
+			return &ParserValidationError{Reason: "WITH must be followed by a SELECT statement"}
+		}
+	}
+	if err := validateStatement(q.stmt); err != nil {
+		return err
+	}
+	for _, c := range q.ctes {
+		sel, ok := c.stmt.(sqlparser.SelectStatement)
+		if !ok {
+			return &ParserValidationError{
+				Reason:    "data-modifying common table expressions are not allowed",
+				Statement: c.name,
+			}
+		}
+		if err := validateSelectStatement(sel); err != nil {
+			return err
+		}
+	}
+	for _, w := range q.windows {
+		if err := checkExprForDangerousFunctions(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Token kinds produced by tokenizeSQL.
+const (
+	tokSpace = iota
+	tokWord
+	tokQuoted
+	tokPunct
+)
+
+type sqlToken struct {
+	kind int
+	text string
+}
+
+// tokenizeSQL splits sqlText into words, quoted strings and identifiers,
+// punctuation and runs of whitespace or comments. Joining the token texts
+// gives back sqlText.
+func tokenizeSQL(sqlText string) []sqlToken {
+	var toks []sqlToken
+	isWordByte := func(c byte) bool {
+		return c == '_' || c == '$' || c == '.' || c >= 0x80 ||
+			(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+	}
+	for i := 0; i < len(sqlText); {
+		c := sqlText[i]
+		j := i + 1
+		kind := tokPunct
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			kind = tokSpace
+			for j < len(sqlText) && strings.IndexByte(" \t\n\r", sqlText[j]) >= 0 {
+				j++
+			}
+		case c == '#' || (c == '-' && strings.HasPrefix(sqlText[i:], "-- ")):
+			kind = tokSpace
+			if end := strings.IndexByte(sqlText[i:], '\n'); end >= 0 {
+				j = i + end + 1
+			} else {
+				j = len(sqlText)
+			}
+		case c == '/' && strings.HasPrefix(sqlText[i:], "/*"):
+			kind = tokSpace
+			if end := strings.Index(sqlText[i+2:], "*/"); end >= 0 {
+				j = i + end + 4
+			} else {
+				j = len(sqlText)
+			}
+		case c == '\'' || c == '"' || c == '`':
+			kind = tokQuoted
+			for j < len(sqlText) {
+				if sqlText[j] == '\\' && c != '`' {
+					j += 2
+					continue
+				}
+				if sqlText[j] == c {
+					if j+1 < len(sqlText) && sqlText[j+1] == c {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+			j = min(j, len(sqlText))
+		case isWordByte(c):
+			kind = tokWord
+			for j < len(sqlText) && isWordByte(sqlText[j]) {
+				j++
+			}
+		}
+		toks = append(toks, sqlToken{kind: kind, text: sqlText[i:j]})
+		i = j
+	}
+	return toks
+}
+
+func isWord(t sqlToken, word string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+func isPunct(t sqlToken, p string) bool {
+	return t.kind == tokPunct && t.text == p
+}
+
+// isName reports whether t can name a CTE, window or derived column.
+func isName(t sqlToken) bool {
+	return t.kind == tokWord || (t.kind == tokQuoted && strings.HasPrefix(t.text, "`"))
+}
+
+// nextSignificant returns the index of the first non-space token at or after
+// i, or len(toks).
+func nextSignificant(toks []sqlToken, i int) int {
+	for i < len(toks) && toks[i].kind == tokSpace {
+		i++
+	}
+	return i
+}
+
+// matchParen returns the index of the ")" closing the "(" at toks[open].
+func matchParen(toks []sqlToken, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(toks); i++ {
+		switch {
+		case isPunct(toks[i], "("):
+			depth++
+		case isPunct(toks[i], ")"):
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, &ParserValidationError{Reason: "failed to parse SQL statement", Statement: "unbalanced parentheses"}
+}
+
+// queryRewriter collects what it lifts out of a statement.
+type queryRewriter struct {
+	ctes    []struct{ name, sql string }
+	windows []string
+	changed bool
+}
+
+// level rewrites one nesting level of a statement: a leading WITH clause is
+// lifted out, window specifications and derived table column lists are
+// dropped, INTERSECT and EXCEPT become UNION, and parenthesized groups are
+// rewritten recursively.
+func (r *queryRewriter) level(toks []sqlToken) (string, error) {
+	toks, err := r.unwrap(toks)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	i := nextSignificant(toks, 0)
+	b.WriteString(joinTokens(toks[:i]))
+	if i < len(toks) && isWord(toks[i], "WITH") {
+		next, err := r.withClause(toks, i+1)
+		if err != nil {
+			return "", err
+		}
+		i = nextSignificant(toks, next)
+		r.changed = true
+	}
+
+	prev := -1 // index of the previous significant token
+	for i < len(toks) {
+		t := toks[i]
+		switch {
+		case isPunct(t, "("):
+			end, err := matchParen(toks, i)
+			if err != nil {
+				return "", err
+			}
+			inner, err := r.level(toks[i+1 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString("(" + inner + ")")
+			subquery := startsQuery(toks[i+1 : end])
+			i, prev = end+1, end
+			if subquery {
+				if alias, skip := derivedColumnList(toks, i); skip > i {
+					b.WriteString(joinTokens(toks[i:alias]))
+					i = skip
+					r.changed = true
+				}
+			}
+			continue
+
+		case isWord(t, "OVER") && prev >= 0 && isPunct(toks[prev], ")"):
+			j := nextSignificant(toks, i+1)
+			if j < len(toks) && isPunct(toks[j], "(") {
+				end, err := matchParen(toks, j)
+				if err != nil {
+					return "", err
+				}
+				if err := r.window(toks[j+1 : end]); err != nil {
+					return "", err
+				}
+				j = end
+			} else if j >= len(toks) || !isName(toks[j]) {
+				break
+			}
+			b.WriteByte(' ')
+			i, prev = j+1, j
+			r.changed = true
+			continue
+
+		case isWord(t, "INTERSECT") || isWord(t, "EXCEPT"):
+			b.WriteString("UNION")
+			i, prev = i+1, i
+			r.changed = true
+			continue
+
+		case isWord(t, "WINDOW"):
+			next, ok, err := r.windowClause(toks, i+1)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				break
+			}
+			b.WriteByte(' ')
+			i, prev = next, -1
+			r.changed = true
+			continue
+		}
+		b.WriteString(t.text)
+		if t.kind != tokSpace {
+			prev = i
+		}
+		i++
+	}
+	return b.String(), nil
+}
+
+// unwrap strips parentheses that enclose a whole query, as in (SELECT 1).
+func (r *queryRewriter) unwrap(toks []sqlToken) ([]sqlToken, error) {
+	for {
+		open := nextSignificant(toks, 0)
+		if open >= len(toks) || !isPunct(toks[open], "(") || !startsQuery(toks[open+1:]) {
+			return toks, nil
+		}
+		end, err := matchParen(toks, open)
+		if err != nil {
+			return nil, err
+		}
+		if nextSignificant(toks, end+1) < len(toks) {
+			return toks, nil
+		}
+		toks = toks[open+1 : end]
+		r.changed = true
+	}
+}
+
+// withClause parses [RECURSIVE] name [(columns)] AS (body) [, ...] starting
+// at toks[i] and records each body. It returns the index after the clause.
+func (r *queryRewriter) withClause(toks []sqlToken, i int) (int, error) {
+	fail := func(msg string) (int, error) {
+		return 0, &ParserValidationError{Reason: "failed to parse WITH clause", Statement: msg}
+	}
+	i = nextSignificant(toks, i)
+	if i < len(toks) && isWord(toks[i], "RECURSIVE") {
+		i = nextSignificant(toks, i+1)
+	}
+	for {
+		if i >= len(toks) || !isName(toks[i]) {
+			return fail("expected a common table expression name")
+		}
+		name := strings.Trim(toks[i].text, "`")
+		i = nextSignificant(toks, i+1)
+		if i < len(toks) && isPunct(toks[i], "(") {
+			end, err := matchParen(toks, i)
+			if err != nil {
+				return 0, err
+			}
+			i = nextSignificant(toks, end+1)
+		}
+		if i >= len(toks) || !isWord(toks[i], "AS") {
+			return fail(fmt.Sprintf("expected AS after '%s'", name))
+		}
+		i = nextSignificant(toks, i+1)
+		if i >= len(toks) || !isPunct(toks[i], "(") {
+			return fail(fmt.Sprintf("expected ( after '%s' AS", name))
+		}
+		end, err := matchParen(toks, i)
+		if err != nil {
+			return 0, err
+		}
+		body, err := r.level(toks[i+1 : end])
+		if err != nil {
+			return 0, err
+		}
+		r.ctes = append(r.ctes, struct{ name, sql string }{name, strings.TrimSpace(body)})
+		i = nextSignificant(toks, end+1)
+		if i < len(toks) && isPunct(toks[i], ",") {
+			i = nextSignificant(toks, i+1)
+			continue
+		}
+		return i, nil
+	}
+}
+
+// windowClause parses name AS (spec) [, ...] starting at toks[i]. ok is false
+// when the tokens are not a WINDOW clause.
+func (r *queryRewriter) windowClause(toks []sqlToken, i int) (next int, ok bool, err error) {
+	for {
+		i = nextSignificant(toks, i)
+		if i >= len(toks) || !isName(toks[i]) {
+			return 0, false, nil
+		}
+		as := nextSignificant(toks, i+1)
+		if as >= len(toks) || !isWord(toks[as], "AS") {
+			return 0, false, nil
+		}
+		open := nextSignificant(toks, as+1)
+		if open >= len(toks) || !isPunct(toks[open], "(") {
+			return 0, false, nil
+		}
+		end, err := matchParen(toks, open)
+		if err != nil {
+			return 0, false, err
+		}
+		if err := r.window(toks[open+1 : end]); err != nil {
+			return 0, false, err
+		}
+		i = nextSignificant(toks, end+1)
+		if i < len(toks) && isPunct(toks[i], ",") {
+			i++
+			continue
+		}
+		return i, true, nil
+	}
+}
+
+// window records a window specification ([name] [PARTITION BY ...]
+// [ORDER BY ...] [frame]) as SELECT 1 GROUP BY ... ORDER BY .... The frame
+// clause holds only bounds and is dropped.
+func (r *queryRewriter) window(spec []sqlToken) error {
+	var kept []sqlToken
+	for i := 0; i < len(spec); i++ {
+		t := spec[i]
+		if isPunct(t, "(") {
+			end, err := matchParen(spec, i)
+			if err != nil {
+				return err
+			}
+			kept = append(kept, spec[i:end+1]...)
+			i = end
+			continue
+		}
+		if isWord(t, "ROWS") || isWord(t, "RANGE") || isWord(t, "GROUPS") {
+			break
+		}
+		if len(kept) == 0 && t.kind != tokSpace && !isWord(t, "PARTITION") && !isWord(t, "ORDER") {
+			continue // name of the window this one extends
+		}
+		if isWord(t, "PARTITION") {
+			t = sqlToken{kind: tokWord, text: "GROUP"}
+		}
+		kept = append(kept, t)
+	}
+	text, err := r.level(kept)
+	if err != nil {
+		return err
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		r.windows = append(r.windows, "SELECT 1 "+text)
+	}
+	return nil
+}
+
+// startsQuery reports whether toks begin with SELECT or WITH, possibly inside
+// further parentheses.
+func startsQuery(toks []sqlToken) bool {
+	for i := nextSignificant(toks, 0); i < len(toks); i = nextSignificant(toks, i+1) {
+		if !isPunct(toks[i], "(") {
+			return isWord(toks[i], "SELECT") || isWord(toks[i], "WITH")
+		}
+	}
+	return false
+}
+
+// clauseKeywords can follow a parenthesized subquery and are never its alias.
+var clauseKeywords = map[string]bool{
+	"AND": true, "OR": true, "XOR": true, "NOT": true, "IS": true, "IN": true, "LIKE": true, "BETWEEN": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "SELECT": true, "FROM": true, "WHERE": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "CROSS": true, "NATURAL": true, "STRAIGHT_JOIN": true,
+	"ON": true, "USING": true, "GROUP": true, "HAVING": true, "ORDER": true, "LIMIT": true, "WINDOW": true,
+	"FOR": true, "LOCK": true, "INTO": true, "AS": true,
+}
+
+// derivedColumnList looks for [AS] alias (col, ...) after a derived table
+// ending at toks[i]. It returns the index just after the alias and the index
+// after the column list, or skip == i when there is no column list.
+func derivedColumnList(toks []sqlToken, i int) (alias, skip int) {
+	j := nextSignificant(toks, i)
+	if j < len(toks) && isWord(toks[j], "AS") {
+		j = nextSignificant(toks, j+1)
+	}
+	if j >= len(toks) || !isName(toks[j]) || clauseKeywords[strings.ToUpper(toks[j].text)] {
+		return i, i
+	}
+	alias = j + 1
+	open := nextSignificant(toks, alias)
+	if open >= len(toks) || !isPunct(toks[open], "(") {
+		return i, i
+	}
+	for k := open + 1; k < len(toks); k++ {
+		switch {
+		case isPunct(toks[k], ")"):
+			return alias, k + 1
+		case toks[k].kind == tokSpace || isPunct(toks[k], ",") || isName(toks[k]):
+		default:
+			return i, i
+		}
+	}
+	return i, i
+}
+
+func joinTokens(toks []sqlToken) string {
+	var b strings.Builder
+	for _, t := range toks {
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+// internal/util/sql_rewrite_test.go
+package util
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidateSQLCombined_CTEsAndWindows(t *testing.T) {
+	allowed := []string{
+		"WITH t AS (SELECT id FROM users) SELECT * FROM t",
+		"with t (id) as (select id from users) select * from t",
+		"WITH a AS (SELECT 1 AS x), b AS (SELECT x FROM a) SELECT * FROM b",
+		"WITH RECURSIVE seq (n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM seq WHERE n < 10) SELECT n FROM seq",
+		"WITH RECURSIVE tree AS (SELECT id, parent_id FROM nodes WHERE parent_id IS NULL UNION ALL SELECT n.id, n.parent_id FROM nodes n JOIN tree ON n.parent_id = tree.id) SELECT * FROM tree",
+		"WITH `my cte` AS (SELECT 1 AS x) SELECT x FROM `my cte`",
+		"SELECT * FROM (WITH t AS (SELECT id FROM users) SELECT id FROM t) AS d",
+		"SELECT id, ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC) AS rn FROM emp",
+		"SELECT id, SUM(total) OVER (ORDER BY day ROWS BETWEEN 6 PRECEDING AND CURRENT ROW) FROM sales",
+		"SELECT id, RANK() OVER w, LAG(total, 1) OVER w FROM sales WINDOW w AS (PARTITION BY region ORDER BY day)",
+		"SELECT id, AVG(total) OVER () FROM sales",
+		"SELECT id, FIRST_VALUE(total) OVER (w ORDER BY day) FROM sales WINDOW w AS (PARTITION BY region)",
+		"WITH ranked AS (SELECT id, ROW_NUMBER() OVER (PARTITION BY dept ORDER BY id) AS rn FROM emp) SELECT * FROM ranked WHERE rn = 1",
+		"SELECT * FROM (SELECT 1, 2) AS d (a, b)",
+		"SELECT 'WITH x AS (DELETE' AS label FROM t",
+	}
+	for _, q := range allowed {
+		if err := ValidateSQLCombined(q); err != nil {
+			t.Errorf("ValidateSQLCombined(%q) = %v, want nil", q, err)
+		}
+	}
+
+	blocked := []string{
+		"WITH t AS (DELETE FROM users) SELECT * FROM t",
+		"WITH t AS (SELECT id FROM users) DELETE FROM users WHERE id IN (SELECT id FROM t)",
+		"WITH t AS (SELECT id FROM users) UPDATE users SET name = 'x'",
+		"WITH t AS (SELECT id FROM users) INSERT INTO a SELECT * FROM t",
+		"WITH t AS (SELECT SLEEP(5)) SELECT * FROM t",
+		"WITH t AS (SELECT * FROM mysql.user) SELECT * FROM t",
+		"SELECT ROW_NUMBER() OVER (ORDER BY SLEEP(1)) FROM t",
+		"SELECT id FROM (SELECT id FROM t WHERE BENCHMARK(1, 1)) AS d (id)",
+		"WITH t AS (SELECT 1) SELECT * FROM t; DROP TABLE users",
+		"WITH t AS SELECT 1 SELECT * FROM t",
+	}
+	for _, q := range blocked {
+		if err := ValidateSQLCombined(q); err == nil {
+			t.Errorf("ValidateSQLCombined(%q) = nil, want error", q)
+		}
+	}
+}
+
+func TestValidateSQLWithParser_DataModifyingCTE(t *testing.T) {
+	err := ValidateSQLWithParser("WITH t AS (DELETE FROM users) SELECT * FROM t")
+	if err == nil || !strings.Contains(err.Error(), "data-modifying common table expressions") {
+		t.Errorf("expected data-modifying CTE error, got %v", err)
+	}
+	err = ValidateSQLWithParser("WITH t AS (SELECT 1) SHOW TABLES")
+	if err == nil || !strings.Contains(err.Error(), "WITH must be followed by a SELECT") {
+		t.Errorf("expected WITH ... SHOW to be rejected, got %v", err)
+	}
+}
+
+func TestReferencedSchemaQualifiers_CTEsAndWindows(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{"WITH t AS (SELECT id FROM shop.orders) SELECT * FROM t JOIN crm.users u ON u.id = t.id", []string{"crm", "shop"}},
+		{"WITH RECURSIVE r AS (SELECT 1 AS n UNION ALL SELECT n + 1 FROM r WHERE n < (SELECT MAX(id) FROM hr.emp)) SELECT * FROM r", []string{"hr"}},
+		{"SELECT ROW_NUMBER() OVER (PARTITION BY (SELECT MAX(id) FROM ops.x) ORDER BY id) FROM t", []string{"ops"}},
+		{"EXPLAIN WITH t AS (SELECT * FROM sales.orders) SELECT * FROM t", []string{"sales"}},
+	}
+	for _, tt := range tests {
+		refs, err := ReferencedSchemaQualifiers(tt.sql)
+		if err != nil {
+			t.Fatalf("ReferencedSchemaQualifiers(%q): %v", tt.sql, err)
+		}
+		var got []string
+		for k := range refs {
+			got = append(got, k)
+		}
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ReferencedSchemaQualifiers(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestInjectLimit_CTEsAndWindows(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"WITH t AS (SELECT id FROM users) SELECT * FROM t", "WITH t AS (SELECT id FROM users) SELECT * FROM t LIMIT 10"},
+		{"WITH t AS (SELECT id FROM users LIMIT 5) SELECT * FROM t", "WITH t AS (SELECT id FROM users LIMIT 5) SELECT * FROM t LIMIT 10"},
+		{"WITH t AS (SELECT id FROM users) SELECT * FROM t LIMIT 3", "WITH t AS (SELECT id FROM users) SELECT * FROM t LIMIT 3"},
+		{"SELECT id, RANK() OVER (ORDER BY score) FROM t", "SELECT id, RANK() OVER (ORDER BY score) FROM t LIMIT 10"},
+	}
+	for _, tt := range tests {
+		if got := InjectLimit(tt.sql, 10); got != tt.want {
+			t.Errorf("InjectLimit(%q) = %q, want %q", tt.sql, got, tt.want)
+		}
+	}
+	if !HasSelectStar("WITH t AS (SELECT id FROM users) SELECT * FROM t") {
+		t.Error("HasSelectStar should see the * of the outer SELECT")
+	}
+}
@@ -178,9 +178,14 @@ var blockedPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)\bSYS\s*\.\b`),
 }
 
+// dataModifyingWith matches DML used as a CTE body or after a WITH clause
+// (WITH t AS (...) DELETE ...). Applied to queries that start with WITH.
+var dataModifyingWith = regexp.MustCompile(`(?is)(\)|\bAS\s*\()\s*(INSERT|UPDATE|DELETE|REPLACE)\b`)
+
 // Allowed query prefixes (read-only operations).
 var allowedPrefixes = []string{
 	"SELECT",
+	"WITH",
 	"SHOW",
 	"DESCRIBE",
 	"DESC",
@@ -189,9 +194,24 @@ var allowedPrefixes = []string{
 
 // ValidateSQL performs comprehensive SQL safety validation.
 func ValidateSQL(sqlText string) error {
+	if violations := sqlViolations(sqlText, false); len(violations) > 0 {
+		return violations[0]
+	}
+	return nil
+}
+
+// sqlViolations runs the checks of ValidateSQL in order. It stops at the
+// first violation unless all is set.
+func sqlViolations(sqlText string, all bool) []*SQLValidationError {
 	s := strings.TrimSpace(sqlText)
 	if s == "" {
-		return &SQLValidationError{Reason: "empty query"}
+		return []*SQLValidationError{{Reason: "empty query"}}
+	}
+
+	var violations []*SQLValidationError
+	add := func(v *SQLValidationError) bool {
+		violations = append(violations, v)
+		return !all
 	}
 
 	scan := stripSQLLiterals(s)
@@ -200,9 +220,11 @@ func ValidateSQL(sqlText string) error {
 	// Allow semicolon only at the very end (single statement)
 	cleaned := strings.TrimRight(scan, "; \t\n\r")
 	if strings.Contains(cleaned, ";") {
-		return &SQLValidationError{
+		if add(&SQLValidationError{
 			Reason:  "multi-statement queries are not allowed",
 			Pattern: ";",
+		}) {
+			return violations
 		}
 	}
 
@@ -215,15 +237,18 @@ func ValidateSQL(sqlText string) error {
 			target = scan
 		}
 		if pattern.MatchString(target) {
-			return &SQLValidationError{
+			if add(&SQLValidationError{
 				Reason:  "query contains blocked pattern",
 				Pattern: pattern.String(),
+			}) {
+				return violations
 			}
 		}
 	}
 
-	// Verify query starts with an allowed prefix
-	upper := strings.ToUpper(s)
+	// Verify query starts with an allowed prefix; a set operation may start
+	// with a parenthesized SELECT, as in (SELECT ...) UNION (SELECT ...)
+	upper := strings.ToUpper(strings.TrimLeft(s, "( \t\n\r"))
 	allowed := false
 	for _, prefix := range allowedPrefixes {
 		if strings.HasPrefix(upper, prefix) {
@@ -233,12 +258,21 @@ func ValidateSQL(sqlText string) error {
 	}
 
 	if !allowed {
-		return &SQLValidationError{
-			Reason: "only SELECT, SHOW, DESCRIBE, and EXPLAIN queries are allowed",
+		if add(&SQLValidationError{
+			Reason: "only SELECT, WITH, SHOW, DESCRIBE, and EXPLAIN queries are allowed",
+		}) {
+			return violations
 		}
 	}
 
-	return nil
+	if strings.HasPrefix(upper, "WITH") && dataModifyingWith.MatchString(scan) {
+		add(&SQLValidationError{
+			Reason:  "data-modifying WITH queries are not allowed",
+			Pattern: dataModifyingWith.String(),
+		})
+	}
+
+	return violations
 }
 
 // IsReadOnlySQL is a convenience wrapper for ValidateSQL.
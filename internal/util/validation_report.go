@@ -0,0 +1,133 @@
+// internal/util/validation_report.go
+package util
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// Layers of ValidateSQLCombined reported by ExplainValidation.
+const (
+	ValidationLayerInput          = "input"
+	ValidationLayerMultiStatement = "multi_statement"
+	ValidationLayerParser         = "parser"
+	ValidationLayerRegex          = "regex"
+)
+
+// ValidationViolation is one rule of ValidateSQLCombined that rejects a query.
+type ValidationViolation struct {
+	Layer   string
+	Rule    string
+	Message string
+	// Pattern is the regular expression that matched (regex layer only).
+	Pattern string
+}
+
+// ExplainValidation runs every check of ValidateSQLCombined and returns all
+// the rules the query breaks, not only the first. Each statement of a
+// multi-statement query is also checked on its own. An empty result means
+// ValidateSQLCombined accepts the query.
+func ExplainValidation(sqlText string) []ValidationViolation {
+	sqlText = strings.TrimSpace(sqlText)
+	if sqlText == "" {
+		return []ValidationViolation{{Layer: ValidationLayerInput, Rule: "empty", Message: "empty query"}}
+	}
+
+	var out []ValidationViolation
+	seen := map[string]bool{}
+	add := func(v ValidationViolation) {
+		key := v.Layer + "\x00" + v.Rule + "\x00" + v.Message + "\x00" + v.Pattern
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, v)
+		}
+	}
+
+	statements, err := sqlparser.SplitStatementToPieces(sqlText)
+	switch {
+	case err != nil:
+		add(ValidationViolation{Layer: ValidationLayerParser, Rule: "syntax", Message: "failed to parse SQL statement: " + err.Error()})
+	case len(statements) > 1:
+		add(ValidationViolation{
+			Layer:   ValidationLayerMultiStatement,
+			Rule:    "single_statement",
+			Message: "multi-statement queries are not allowed",
+		})
+		fallthrough
+	default:
+		for _, stmt := range statements {
+			if err := ValidateSQLWithParser(stmt); err != nil {
+				add(parserViolation(err))
+			}
+		}
+	}
+
+	for _, v := range sqlViolations(sqlText, true) {
+		add(regexViolation(v))
+	}
+	return out
+}
+
+// parserViolation classifies an error from ValidateSQLWithParser.
+func parserViolation(err error) ValidationViolation {
+	v := ValidationViolation{Layer: ValidationLayerParser, Rule: "statement_type", Message: err.Error()}
+	var pe *ParserValidationError
+	if !errors.As(err, &pe) {
+		return v
+	}
+	switch {
+	case pe.Reason == "empty query":
+		v.Layer, v.Rule = ValidationLayerInput, "empty"
+	case strings.HasPrefix(pe.Reason, "multi-statement"):
+		v.Layer, v.Rule = ValidationLayerMultiStatement, "single_statement"
+	case strings.HasPrefix(pe.Reason, "failed to parse"):
+		v.Rule = "syntax"
+	case pe.Reason == "dangerous function not allowed":
+		v.Rule = "dangerous_function"
+	case pe.Reason == "access to system schema is not allowed":
+		v.Rule = "system_schema"
+	case strings.HasPrefix(pe.Reason, "data-modifying"), strings.HasPrefix(pe.Reason, "WITH must be followed"):
+		v.Rule = "data_modifying_cte"
+	}
+	return v
+}
+
+// regexViolation classifies a violation from sqlViolations.
+func regexViolation(e *SQLValidationError) ValidationViolation {
+	v := ValidationViolation{Layer: ValidationLayerRegex, Message: e.Reason, Pattern: e.Pattern}
+	switch {
+	case e.Reason == "empty query":
+		v.Layer, v.Rule, v.Pattern = ValidationLayerInput, "empty", ""
+	case strings.HasPrefix(e.Reason, "multi-statement"):
+		v.Layer, v.Rule = ValidationLayerMultiStatement, "single_statement"
+		v.Message = "query contains a ';' before its end"
+	case strings.HasPrefix(e.Reason, "only "):
+		v.Rule = "allowed_prefix"
+	case strings.HasPrefix(e.Reason, "data-modifying"):
+		v.Rule = "data_modifying_cte"
+	default:
+		v.Rule = blockedPatternRule(e.Pattern)
+	}
+	return v
+}
+
+// blockedPatternRule names the group a pattern of blockedPatterns belongs to.
+func blockedPatternRule(pattern string) string {
+	upper := strings.ToUpper(pattern)
+	switch {
+	case pattern == "--" || pattern == `/\*`:
+		return "comment"
+	case strings.Contains(upper, "LOAD_FILE") || strings.Contains(upper, "OUTFILE") ||
+		strings.Contains(upper, "DUMPFILE") || strings.Contains(upper, "LOAD\\S+DATA"):
+		return "file_access"
+	case strings.Contains(pattern, `\s*\.`):
+		return "system_schema"
+	case strings.Contains(pattern, `\s*\(`):
+		return "dangerous_function"
+	case strings.HasPrefix(pattern, `(?i)^`):
+		return "statement_type"
+	}
+	return "blocked_pattern"
+}
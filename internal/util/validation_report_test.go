@@ -0,0 +1,89 @@
+// internal/util/validation_report_test.go
+package util
+
+import (
+	"testing"
+)
+
+func TestExplainValidationMatchesValidateSQLCombined(t *testing.T) {
+	queries := []string{
+		"SELECT * FROM users",
+		"SHOW TABLES",
+		"EXPLAIN SELECT 1",
+		"WITH t AS (SELECT 1) SELECT * FROM t",
+		"(SELECT 1) UNION (SELECT 2)",
+		"",
+		"DELETE FROM users",
+		"SELECT SLEEP(5)",
+		"SELECT * FROM mysql.user",
+		"SELECT 1; DROP TABLE users",
+		"SELECT 1 -- comment",
+		"SELECT * FROM users INTO OUTFILE '/tmp/x'",
+		"SELEC * FROM users",
+		"WITH t AS (DELETE FROM users) SELECT * FROM t",
+		"CALL proc()",
+	}
+	for _, q := range queries {
+		violations := ExplainValidation(q)
+		err := ValidateSQLCombined(q)
+		if (err == nil) != (len(violations) == 0) {
+			t.Errorf("%q: ValidateSQLCombined = %v but ExplainValidation = %+v", q, err, violations)
+		}
+	}
+}
+
+func TestExplainValidationRules(t *testing.T) {
+	tests := []struct {
+		sql   string
+		layer string
+		rule  string
+	}{
+		{"", ValidationLayerInput, "empty"},
+		{"SELECT 1; SELECT 2", ValidationLayerMultiStatement, "single_statement"},
+		{"SELEC * FROM users", ValidationLayerParser, "syntax"},
+		{"DELETE FROM users", ValidationLayerParser, "statement_type"},
+		{"DELETE FROM users", ValidationLayerRegex, "statement_type"},
+		{"SELECT SLEEP(5)", ValidationLayerParser, "dangerous_function"},
+		{"SELECT SLEEP(5)", ValidationLayerRegex, "dangerous_function"},
+		{"SELECT * FROM mysql.user", ValidationLayerParser, "system_schema"},
+		{"SELECT * FROM mysql.user", ValidationLayerRegex, "system_schema"},
+		{"SELECT 1 /* hi */", ValidationLayerRegex, "comment"},
+		{"SELECT LOAD_FILE('/etc/passwd')", ValidationLayerRegex, "file_access"},
+		{"SELECT * FROM t INTO OUTFILE '/tmp/x'", ValidationLayerRegex, "file_access"},
+		{"HANDLER t READ FIRST", ValidationLayerRegex, "allowed_prefix"},
+		{"WITH t AS (DELETE FROM users) SELECT * FROM t", ValidationLayerParser, "data_modifying_cte"},
+		{"WITH t AS (DELETE FROM users) SELECT * FROM t", ValidationLayerRegex, "data_modifying_cte"},
+	}
+	for _, tt := range tests {
+		found := false
+		for _, v := range ExplainValidation(tt.sql) {
+			if v.Layer == tt.layer && v.Rule == tt.rule {
+				found = true
+				if v.Message == "" {
+					t.Errorf("%q: %s/%s has no message", tt.sql, tt.layer, tt.rule)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%q: expected %s/%s among %+v", tt.sql, tt.layer, tt.rule, ExplainValidation(tt.sql))
+		}
+	}
+}
+
+func TestExplainValidationReportsEveryRule(t *testing.T) {
+	// A stacked statement with a comment and a system schema breaks several
+	// rules at once; all of them are reported, each once.
+	violations := ExplainValidation("SELECT * FROM mysql.user; DROP TABLE t -- x")
+	rules := map[string]int{}
+	for _, v := range violations {
+		rules[v.Layer+"/"+v.Rule]++
+	}
+	for _, want := range []string{"multi_statement/single_statement", "parser/system_schema", "parser/statement_type", "regex/comment", "regex/system_schema"} {
+		if rules[want] == 0 {
+			t.Errorf("expected %s among %+v", want, violations)
+		}
+	}
+	if rules["parser/system_schema"] > 1 {
+		t.Errorf("duplicate violations: %+v", violations)
+	}
+}
@@ -0,0 +1,305 @@
+// internal/util/view_lineage.go
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// ColumnRef names a column of a table. Database is empty when the statement
+// did not qualify the table, and Table is empty when an unqualified column
+// could belong to more than one table of the FROM clause.
+type ColumnRef struct {
+	Database string
+	Table    string
+	Column   string
+}
+
+// ColumnLineage is where one result column of a SELECT comes from.
+type ColumnLineage struct {
+	Name       string      // result column name
+	Expression string      // select expression as the parser formats it
+	Sources    []ColumnRef // columns the value is read from, in order of appearance
+	Derived    bool        // computed from its sources rather than copied from one column
+}
+
+// Lineage is the provenance of the result columns of a SELECT.
+type Lineage struct {
+	Columns []ColumnLineage
+	Tables  []TableRef // every table the statement reads, sorted
+}
+
+// lineageSource is one entry of a FROM clause: a table, or a derived table
+// whose columns were traced already.
+type lineageSource struct {
+	table   TableRef
+	derived []ColumnLineage // nil for a table
+}
+
+type lineageScope struct {
+	byName map[string]*lineageSource // lowercased alias, or table name when unaliased
+	order  []*lineageSource
+}
+
+// SelectLineage traces each result column of a SELECT or UNION, such as a
+// view definition, back to the table columns it is read from. Derived tables
+// are followed to their own sources; columns of a UNION collect the sources
+// of every branch. Table names are returned as written, so unqualified names
+// belong to the default database of the statement.
+func SelectLineage(sqlText string) (*Lineage, error) {
+	trimmed := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(sqlText), ";"))
+	stmt, err := sqlparser.Parse(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse: %w", err)
+	}
+	sel, ok := stmt.(sqlparser.SelectStatement)
+	if !ok {
+		return nil, fmt.Errorf("not a SELECT statement")
+	}
+	columns, err := selectStatementLineage(sel)
+	if err != nil {
+		return nil, err
+	}
+
+	tables, _ := collectNormalizedTables(stmt)
+	out := &Lineage{Columns: columns}
+	for _, name := range sortedKeys(tables) {
+		ref := TableRef{Table: name}
+		if db, table, ok := strings.Cut(name, "."); ok {
+			ref = TableRef{Database: db, Table: table}
+		}
+		out.Tables = append(out.Tables, ref)
+	}
+	return out, nil
+}
+
+func selectStatementLineage(stmt sqlparser.SelectStatement) ([]ColumnLineage, error) {
+	switch s := stmt.(type) {
+	case *sqlparser.Select:
+		return selectLineage(s)
+	case *sqlparser.ParenSelect:
+		return selectStatementLineage(s.Select)
+	case *sqlparser.Union:
+		left, err := selectStatementLineage(s.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := selectStatementLineage(s.Right)
+		if err != nil {
+			return nil, err
+		}
+		if len(left) != len(right) {
+			return nil, fmt.Errorf("UNION branches have %d and %d columns", len(left), len(right))
+		}
+		for i := range left {
+			left[i].Sources = appendColumnRefs(left[i].Sources, right[i].Sources...)
+			left[i].Derived = left[i].Derived || right[i].Derived
+		}
+		return left, nil
+	default:
+		return nil, fmt.Errorf("unsupported statement %T", stmt)
+	}
+}
+
+func selectLineage(sel *sqlparser.Select) ([]ColumnLineage, error) {
+	scope := &lineageScope{byName: map[string]*lineageSource{}}
+	for _, te := range sel.From {
+		if err := scope.add(te); err != nil {
+			return nil, err
+		}
+	}
+
+	var columns []ColumnLineage
+	for _, se := range sel.SelectExprs {
+		switch e := se.(type) {
+		case *sqlparser.StarExpr:
+			columns = append(columns, scope.star(e)...)
+		case *sqlparser.AliasedExpr:
+			c := ColumnLineage{Expression: sqlparser.String(e.Expr)}
+			if col, ok := e.Expr.(*sqlparser.ColName); ok {
+				c.Name = col.Name.String()
+				c.Sources, c.Derived = scope.resolve(col)
+			} else {
+				c.Name = c.Expression
+				c.Sources = scope.exprSources(e.Expr)
+				c.Derived = true
+			}
+			if !e.As.IsEmpty() {
+				c.Name = e.As.String()
+			}
+			columns = append(columns, c)
+		default:
+			return nil, fmt.Errorf("unsupported select expression %s", sqlparser.String(se))
+		}
+	}
+	return columns, nil
+}
+
+// add registers the tables and derived tables of one FROM entry.
+func (s *lineageScope) add(te sqlparser.TableExpr) error {
+	switch t := te.(type) {
+	case *sqlparser.AliasedTableExpr:
+		var src *lineageSource
+		name := t.As.String()
+		switch expr := t.Expr.(type) {
+		case sqlparser.TableName:
+			src = &lineageSource{table: TableRef{Database: expr.Qualifier.String(), Table: expr.Name.String()}}
+			if name == "" {
+				name = expr.Name.String()
+			}
+		case *sqlparser.Subquery:
+			columns, err := selectStatementLineage(expr.Select)
+			if err != nil {
+				return err
+			}
+			src = &lineageSource{derived: columns}
+		default:
+			return fmt.Errorf("unsupported table expression %s", sqlparser.String(te))
+		}
+		s.byName[strings.ToLower(name)] = src
+		s.order = append(s.order, src)
+	case *sqlparser.JoinTableExpr:
+		if err := s.add(t.LeftExpr); err != nil {
+			return err
+		}
+		return s.add(t.RightExpr)
+	case *sqlparser.ParenTableExpr:
+		for _, e := range t.Exprs {
+			if err := s.add(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the sources of a column reference and whether its value is
+// computed, which it is when it names a computed column of a derived table.
+func (s *lineageScope) resolve(col *sqlparser.ColName) ([]ColumnRef, bool) {
+	name := col.Name.String()
+	table, db := col.Qualifier.Name.String(), col.Qualifier.Qualifier.String()
+	switch {
+	case table == "":
+		if len(s.order) == 1 {
+			return s.order[0].column(name)
+		}
+		// Only a derived table tells which columns it has.
+		var found *lineageSource
+		for _, src := range s.order {
+			if src.derived == nil {
+				continue
+			}
+			if _, ok := src.derivedColumn(name); ok {
+				if found != nil {
+					found = nil
+					break
+				}
+				found = src
+			}
+		}
+		if found != nil {
+			return found.column(name)
+		}
+		return []ColumnRef{{Column: name}}, false
+	case db != "":
+		for _, src := range s.order {
+			if src.derived == nil && strings.EqualFold(src.table.Table, table) && strings.EqualFold(src.table.Database, db) {
+				return src.column(name)
+			}
+		}
+		return []ColumnRef{{Database: db, Table: table, Column: name}}, false
+	default:
+		if src, ok := s.byName[strings.ToLower(table)]; ok {
+			return src.column(name)
+		}
+		return []ColumnRef{{Table: table, Column: name}}, false
+	}
+}
+
+func (src *lineageSource) column(name string) ([]ColumnRef, bool) {
+	if src.derived == nil {
+		return []ColumnRef{{Database: src.table.Database, Table: src.table.Table, Column: name}}, false
+	}
+	if c, ok := src.derivedColumn(name); ok {
+		return append([]ColumnRef(nil), c.Sources...), c.Derived
+	}
+	return nil, false
+}
+
+func (src *lineageSource) derivedColumn(name string) (ColumnLineage, bool) {
+	for _, c := range src.derived {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return ColumnLineage{}, false
+}
+
+// star expands * or t.*: derived tables into their columns, tables into a
+// single "*" entry since their columns are not known here.
+func (s *lineageScope) star(e *sqlparser.StarExpr) []ColumnLineage {
+	sources := s.order
+	if !e.TableName.IsEmpty() {
+		src, ok := s.byName[strings.ToLower(e.TableName.Name.String())]
+		if !ok {
+			ref := TableRef{Database: e.TableName.Qualifier.String(), Table: e.TableName.Name.String()}
+			src = &lineageSource{table: ref}
+		}
+		sources = []*lineageSource{src}
+	}
+	var out []ColumnLineage
+	for _, src := range sources {
+		if src.derived != nil {
+			out = append(out, src.derived...)
+			continue
+		}
+		out = append(out, ColumnLineage{
+			Name:       "*",
+			Expression: sqlparser.String(e),
+			Sources:    []ColumnRef{{Database: src.table.Database, Table: src.table.Table, Column: "*"}},
+		})
+	}
+	return out
+}
+
+// exprSources collects the columns an expression reads, including the
+// result columns of its subqueries.
+func (s *lineageScope) exprSources(expr sqlparser.Expr) []ColumnRef {
+	var refs []ColumnRef
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.ColName:
+			sources, _ := s.resolve(n)
+			refs = appendColumnRefs(refs, sources...)
+		case *sqlparser.Subquery:
+			if columns, err := selectStatementLineage(n.Select); err == nil {
+				for _, c := range columns {
+					refs = appendColumnRefs(refs, c.Sources...)
+				}
+			}
+			return false, nil
+		}
+		return true, nil
+	}, expr)
+	return refs
+}
+
+// appendColumnRefs appends the refs not yet in list, comparing names
+// case-insensitively.
+func appendColumnRefs(list []ColumnRef, refs ...ColumnRef) []ColumnRef {
+	for _, r := range refs {
+		dup := false
+		for _, l := range list {
+			if strings.EqualFold(l.Database, r.Database) && strings.EqualFold(l.Table, r.Table) && strings.EqualFold(l.Column, r.Column) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, r)
+		}
+	}
+	return list
+}
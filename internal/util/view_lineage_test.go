@@ -0,0 +1,60 @@
+// internal/util/view_lineage_test.go
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectLineageViewDefinition(t *testing.T) {
+	// information_schema.VIEWS stores definitions with every column
+	// qualified: by alias for aliased tables, by database.table otherwise.
+	l, err := SelectLineage("select `o`.`id` AS `order_id`,`shop`.`customers`.`name` AS `customer`," +
+		"sum((`i`.`qty` * `i`.`price`)) AS `total` " +
+		"from ((`shop`.`orders` `o` join `shop`.`customers` on((`shop`.`customers`.`id` = `o`.`customer_id`))) " +
+		"join `shop`.`order_items` `i` on((`i`.`order_id` = `o`.`id`))) group by `o`.`id`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnLineage{
+		{Name: "order_id", Expression: "o.id", Sources: []ColumnRef{{"shop", "orders", "id"}}},
+		{Name: "customer", Expression: "shop.customers.name", Sources: []ColumnRef{{"shop", "customers", "name"}}},
+		{Name: "total", Expression: "sum((i.qty * i.price))", Derived: true,
+			Sources: []ColumnRef{{"shop", "order_items", "qty"}, {"shop", "order_items", "price"}}},
+	}
+	if !reflect.DeepEqual(l.Columns, want) {
+		t.Errorf("unexpected columns:\n got %+v\nwant %+v", l.Columns, want)
+	}
+	wantTables := []TableRef{{"shop", "customers"}, {"shop", "order_items"}, {"shop", "orders"}}
+	if !reflect.DeepEqual(l.Tables, wantTables) {
+		t.Errorf("unexpected tables %+v", l.Tables)
+	}
+}
+
+func TestSelectLineageDerivedAndUnion(t *testing.T) {
+	l, err := SelectLineage("SELECT d.region, d.n + 1 AS n1 FROM (SELECT region, COUNT(*) AS n FROM stores GROUP BY region) d " +
+		"UNION ALL SELECT w.region, (SELECT MAX(qty) FROM stock) FROM warehouses w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ColumnLineage{
+		{Name: "region", Expression: "d.region", Sources: []ColumnRef{{"", "stores", "region"}, {"", "warehouses", "region"}}},
+		{Name: "n1", Expression: "d.n + 1", Derived: true, Sources: []ColumnRef{{"", "stock", "qty"}}},
+	}
+	if !reflect.DeepEqual(l.Columns, want) {
+		t.Errorf("unexpected columns:\n got %+v\nwant %+v", l.Columns, want)
+	}
+
+	// An unqualified column of a join cannot be placed without the catalog.
+	l, err = SelectLineage("SELECT name FROM a JOIN b ON a.id = b.id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Columns[0].Sources; !reflect.DeepEqual(got, []ColumnRef{{Column: "name"}}) {
+		t.Errorf("expected an unresolved source, got %+v", got)
+	}
+
+	if _, err := SelectLineage("DELETE FROM a"); err == nil {
+		t.Error("expected a non-SELECT to fail")
+	}
+}
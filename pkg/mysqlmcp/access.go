@@ -0,0 +1,177 @@
+package mysqlmcp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+var allowedDatabaseSet map[string]struct{}
+
+func initAccessControl(allowed []string) {
+	allowedDatabaseSet = config.AllowedDatabaseSet(allowed)
+}
+
+// accessControlEnabled reports whether schemas are restricted, by
+// MYSQL_MCP_ALLOWED_DATABASES or by the active virtual connection.
+func accessControlEnabled() bool {
+	return len(allowedDatabaseSet) > 0 || activeVirtualConnection() != nil
+}
+
+// allowedDatabasesLower returns allowlist entries as lowercase strings, sorted.
+// Used for SQL filters (e.g. mysql.slow_log.db) when accessControlEnabled().
+func allowedDatabasesLower() []string {
+	if !accessControlEnabled() {
+		return nil
+	}
+	out := make([]string, 0, len(allowedDatabaseSet))
+	for name := range allowedDatabaseSet {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func databaseAllowed(name string) bool {
+	if !accessControlEnabled() {
+		return true
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+	if len(allowedDatabaseSet) > 0 {
+		if _, ok := allowedDatabaseSet[strings.ToLower(name)]; !ok {
+			return false
+		}
+	}
+	if vc := activeVirtualConnection(); vc != nil && !virtualSchemaAllowed(vc, name) {
+		return false
+	}
+	return true
+}
+
+// allowedSchemaFilter returns a SQL condition keeping only the values of
+// column that databaseAllowed accepts, with its arguments. Callers use it
+// when accessControlEnabled().
+func allowedSchemaFilter(column string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if allowed := allowedDatabasesLower(); len(allowed) > 0 {
+		conds = append(conds, "LOWER("+column+") IN ("+placeholders(len(allowed))+")")
+		args = append(args, stringsToArgs(allowed)...)
+	}
+	if vc := activeVirtualConnection(); vc != nil {
+		likes := make([]string, 0, len(vc.Schemas))
+		for _, p := range vc.Schemas {
+			likes = append(likes, "LOWER("+column+") LIKE ? ESCAPE '!'")
+			args = append(args, schemaPatternLike(p))
+		}
+		conds = append(conds, "("+strings.Join(likes, " OR ")+")")
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func requireAllowedDatabase(db string) error {
+	if !accessControlEnabled() {
+		return nil
+	}
+	if vc := activeVirtualConnection(); vc != nil {
+		if strings.TrimSpace(db) == "" {
+			return i18nErrorf("database is required on virtual connection %q", vc.Name)
+		}
+		if !virtualSchemaAllowed(vc, db) {
+			return i18nErrorf("database %q is outside virtual connection %q", db, vc.Name)
+		}
+	}
+	if strings.TrimSpace(db) == "" {
+		return i18nErrorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is configured")
+	}
+	if !databaseAllowed(db) {
+		return i18nErrorf("database %q is not in MYSQL_MCP_ALLOWED_DATABASES", db)
+	}
+	return nil
+}
+
+// requireReferencedSchemasInQuery ensures every explicitly schema-qualified
+// reference in sqlText (and USE / SHOW / EXPLAIN targets) is allowed when an
+// allowlist is configured.
+func requireReferencedSchemasInQuery(sqlText string) error {
+	if !accessControlEnabled() {
+		return nil
+	}
+	if util.ShowEnumeratesAllSchemasInQuery(sqlText) {
+		return i18nErrorf("SHOW DATABASES is not allowed when MYSQL_MCP_ALLOWED_DATABASES is set; use the list_databases tool instead")
+	}
+	refs, err := util.ReferencedSchemaQualifiers(sqlText)
+	if err != nil {
+		return i18nErrorf("query validation failed: %w", err)
+	}
+	vc := activeVirtualConnection()
+	for name := range refs {
+		if vc != nil && !virtualSchemaAllowed(vc, name) {
+			return i18nErrorf("query references database %q which is outside virtual connection %q", name, vc.Name)
+		}
+		if !databaseAllowed(name) {
+			return i18nErrorf("query references database %q which is not in MYSQL_MCP_ALLOWED_DATABASES", name)
+		}
+	}
+	return nil
+}
+
+// confirmRequiredSet holds the lowercase environments and tags whose
+// connections need confirm=true before run_query executes.
+var confirmRequiredSet map[string]struct{}
+
+func initConfirmPolicy(labels []string) {
+	confirmRequiredSet = nil
+	for _, l := range labels {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" {
+			if confirmRequiredSet == nil {
+				confirmRequiredSet = make(map[string]struct{})
+			}
+			confirmRequiredSet[l] = struct{}{}
+		}
+	}
+}
+
+// confirmationLabel returns the environment or tag of c that requires
+// confirmation, or "" when c is not covered by the policy.
+func confirmationLabel(c config.ConnectionConfig) string {
+	if len(confirmRequiredSet) == 0 {
+		return ""
+	}
+	for _, l := range append([]string{c.Environment}, c.Tags...) {
+		if _, ok := confirmRequiredSet[strings.ToLower(strings.TrimSpace(l))]; ok {
+			return l
+		}
+	}
+	return ""
+}
+
+// activeConnectionConfig returns the configuration of the active connection.
+func activeConnectionConfig() (config.ConnectionConfig, bool) {
+	if connManager == nil {
+		return config.ConnectionConfig{}, false
+	}
+	_, name := connManager.GetActive()
+	return connManager.Config(name)
+}
+
+// requireConfirmation rejects a query on a connection covered by the confirm
+// policy (MYSQL_MCP_CONFIRM_REQUIRED) unless the caller set confirm=true.
+func requireConfirmation(confirm bool) error {
+	if confirm {
+		return nil
+	}
+	c, ok := activeConnectionConfig()
+	if !ok {
+		return nil
+	}
+	if label := confirmationLabel(c); label != "" {
+		return i18nErrorf("connection %q is labeled %q and requires confirmation: check that this query should run there, then retry with confirm=true", c.Name, label)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package mysqlmcp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRequireReferencedSchemasBlocksShowDatabases(t *testing.T) {
+	t.Cleanup(func() { initAccessControl(nil) })
+	initAccessControl([]string{"app"})
+	if err := requireReferencedSchemasInQuery("SHOW DATABASES"); err == nil {
+		t.Fatal("expected SHOW DATABASES to be rejected with allowlist")
+	}
+	if err := requireReferencedSchemasInQuery("SHOW DATABASES LIKE 'x%'"); err == nil {
+		t.Fatal("expected SHOW DATABASES LIKE to be rejected with allowlist")
+	}
+	if err := requireReferencedSchemasInQuery("SELECT 1 FROM app.t"); err != nil {
+		t.Fatalf("allowed schema should pass: %v", err)
+	}
+}
+
+func TestAllowedDatabasesLower(t *testing.T) {
+	t.Cleanup(func() { initAccessControl(nil) })
+
+	initAccessControl([]string{"zebra", "App", "  middle  "})
+	got := allowedDatabasesLower()
+	want := []string{"app", "middle", "zebra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("allowedDatabasesLower() = %v, want %v", got, want)
+	}
+
+	initAccessControl(nil)
+	if got := allowedDatabasesLower(); got != nil {
+		t.Fatalf("with nil allowlist expected nil slice, got %#v", got)
+	}
+}
+
+func TestRunQueryRequiresConfirmationOnLabeledConnection(t *testing.T) {
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+	t.Cleanup(func() { initConfirmPolicy(nil) })
+	initConfirmPolicy([]string{" PROD ", "pci"})
+
+	connManager.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: mockDSN, Environment: "prod"}
+	ctx := context.Background()
+
+	_, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1"})
+	if err == nil || !strings.Contains(err.Error(), "confirm=true") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+
+	result.mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	_, out, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1", Confirm: true})
+	if err != nil {
+		t.Fatalf("confirmed query failed: %v", err)
+	}
+	if out.Connection != "mock" || out.Environment != "prod" {
+		t.Errorf("expected routing hint for prod, got connection=%q environment=%q", out.Connection, out.Environment)
+	}
+
+	_, list, err := toolListConnections(ctx, &mcp.CallToolRequest{}, ListConnectionsInput{})
+	if err != nil {
+		t.Fatalf("toolListConnections failed: %v", err)
+	}
+	if len(list.Connections) != 1 || !list.Connections[0].RequiresConfirm || list.Connections[0].Environment != "prod" {
+		t.Errorf("unexpected connection info: %+v", list.Connections)
+	}
+
+	// Tags match as well; unlabeled connections are unaffected.
+	connManager.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: mockDSN, Tags: []string{"PCI"}}
+	if err := requireConfirmation(false); err == nil {
+		t.Error("expected tag match to require confirmation")
+	}
+	connManager.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: mockDSN, Environment: "dev"}
+	if err := requireConfirmation(false); err != nil {
+		t.Errorf("dev connection should not need confirmation: %v", err)
+	}
+
+	if err := result.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
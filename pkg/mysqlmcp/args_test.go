@@ -1,4 +1,4 @@
-package main
+package mysqlmcp
 
 import (
 	"bytes"
@@ -16,6 +16,8 @@ func TestParseArgs(t *testing.T) {
 		wantSilent        bool
 		wantDaemon        bool
 		wantTokenCardFlag bool
+		wantSubject       string
+		wantInput         string
 		wantErr           bool
 		errContains       string
 	}{
@@ -30,6 +32,11 @@ func TestParseArgs(t *testing.T) {
 			args:       []string{"--version"},
 			wantAction: "version",
 		},
+		{
+			name:       "healthcheck flag",
+			args:       []string{"--healthcheck"},
+			wantAction: "healthcheck",
+		},
 		{
 			name:       "version short flag",
 			args:       []string{"-v"},
@@ -95,6 +102,62 @@ func TestParseArgs(t *testing.T) {
 			errContains: "--validate-config requires a path argument",
 		},
 
+		// Subcommands
+		{
+			name:       "validate-config subcommand without path",
+			args:       []string{"validate-config"},
+			wantAction: "validate-config",
+		},
+		{
+			name:          "validate-config subcommand with path",
+			args:          []string{"validate-config", "/path/to/config.yaml"},
+			wantAction:    "validate-config",
+			wantValidPath: "/path/to/config.yaml",
+		},
+		{
+			name:       "test-connection all",
+			args:       []string{"test-connection"},
+			wantAction: "test-connection",
+		},
+		{
+			name:           "test-connection named with config",
+			args:           []string{"test-connection", "prod", "--config", "/path/to/config.yaml"},
+			wantAction:     "test-connection",
+			wantSubject:    "prod",
+			wantConfigPath: "/path/to/config.yaml",
+		},
+		{
+			name:       "list-tools",
+			args:       []string{"list-tools"},
+			wantAction: "list-tools",
+		},
+		{
+			name:        "run-tool with input",
+			args:        []string{"run-tool", "list_tables", "--input", `{"database":"app"}`},
+			wantAction:  "run-tool",
+			wantSubject: "list_tables",
+			wantInput:   `{"database":"app"}`,
+		},
+		{
+			name:        "run-tool input from stdin",
+			args:        []string{"run-tool", "list_databases", "--input", "-"},
+			wantAction:  "run-tool",
+			wantSubject: "list_databases",
+			wantInput:   "-",
+		},
+		{
+			name:        "run-tool missing name",
+			args:        []string{"run-tool", "--input", "{}"},
+			wantErr:     true,
+			errContains: "run-tool requires a tool name",
+		},
+		{
+			name:        "input missing value",
+			args:        []string{"run-tool", "ping", "--input"},
+			wantErr:     true,
+			errContains: "--input requires a JSON argument",
+		},
+
 		// Silent and daemon flags
 		{
 			name:       "silent flag",
@@ -232,6 +295,12 @@ func TestParseArgs(t *testing.T) {
 			if result.tokenCardFlag != tt.wantTokenCardFlag {
 				t.Errorf("parseArgs() tokenCardFlag = %v, want %v", result.tokenCardFlag, tt.wantTokenCardFlag)
 			}
+			if result.subjectName != tt.wantSubject {
+				t.Errorf("parseArgs() subjectName = %q, want %q", result.subjectName, tt.wantSubject)
+			}
+			if result.toolInput != tt.wantInput {
+				t.Errorf("parseArgs() toolInput = %q, want %q", result.toolInput, tt.wantInput)
+			}
 		})
 	}
 }
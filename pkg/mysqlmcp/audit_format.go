@@ -0,0 +1,173 @@
+// pkg/mysqlmcp/audit_format.go
+package mysqlmcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Vendor and product reported in the CEF and LEEF headers.
+const (
+	auditVendor  = "askdba"
+	auditProduct = "mysql-mcp-server"
+)
+
+// formatAuditEntry renders entry as one audit log line in format. Unknown or
+// empty formats write JSON.
+func formatAuditEntry(format string, entry *AuditEntry, at time.Time) string {
+	switch format {
+	case config.AuditFormatCEF:
+		return formatAuditCEF(entry, at)
+	case config.AuditFormatLEEF:
+		return formatAuditLEEF(entry, at)
+	}
+	data, _ := json.Marshal(entry)
+	return string(data)
+}
+
+// auditOutcome returns the event name and outcome of entry.
+func auditOutcome(entry *AuditEntry) (name, outcome string) {
+	if entry.Success {
+		return "Query executed", "success"
+	}
+	return "Query failed", "failure"
+}
+
+// auditSeverity maps entry to a 0-10 severity: failed calls are 7, others 3.
+func auditSeverity(entry *AuditEntry) int {
+	if entry.Success {
+		return 3
+	}
+	return 7
+}
+
+// formatAuditCEF renders entry in the ArcSight Common Event Format:
+// a pipe-separated header followed by space-separated key=value extensions.
+func formatAuditCEF(entry *AuditEntry, at time.Time) string {
+	name, outcome := auditOutcome(entry)
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|",
+		cefHeader(auditVendor), cefHeader(auditProduct), cefHeader(Version),
+		cefHeader(entry.Tool), cefHeader(name), auditSeverity(entry))
+
+	ext := []string{
+		"rt=" + strconv.FormatInt(at.UnixMilli(), 10),
+		"act=" + cefValue(entry.Tool),
+		"outcome=" + outcome,
+	}
+	add := func(key, value string) {
+		if value != "" {
+			ext = append(ext, key+"="+cefValue(value))
+		}
+	}
+	add("app", entry.Source)
+	add("src", entry.RemoteIP)
+	add("suser", entry.APIKey)
+	add("requestClientApplication", strings.TrimSpace(entry.Client+" "+entry.ClientVersion))
+	add("duser", entry.DBUser)
+	add("cs1Label", "connection")
+	add("cs1", entry.Connection)
+	add("cs2Label", "database")
+	add("cs2", entry.Database)
+	add("cs3Label", "requestId")
+	add("cs3", entry.RequestID)
+	add("cs4Label", "queryDigest")
+	add("cs4", entry.QueryDigest)
+	add("cs5Label", "query")
+	add("cs5", entry.Query)
+	if entry.Duplicate {
+		add("cs6Label", "duplicate")
+		add("cs6", "true")
+	}
+	ext = append(ext, "cn1Label=durationMs", "cn1="+strconv.FormatInt(entry.DurationMs, 10))
+	ext = append(ext, "cnt="+strconv.Itoa(entry.RowCount))
+	add("reason", entry.Error)
+	b.WriteString(strings.Join(ext, " "))
+	return b.String()
+}
+
+// cefHeader escapes a CEF header field.
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(s)
+}
+
+// cefValue escapes a CEF extension value.
+func cefValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// leefTimeFormat is the default LEEF devTimeFormat, MMM dd yyyy HH:mm:ss.SSS zzz.
+const leefTimeFormat = "Jan 02 2006 15:04:05.000 MST"
+
+// formatAuditLEEF renders entry in the QRadar Log Event Extended Format 1.0:
+// a pipe-separated header followed by tab-separated key=value attributes.
+func formatAuditLEEF(entry *AuditEntry, at time.Time) string {
+	_, outcome := auditOutcome(entry)
+	var b strings.Builder
+	fmt.Fprintf(&b, "LEEF:1.0|%s|%s|%s|%s|",
+		leefHeader(auditVendor), leefHeader(auditProduct), leefHeader(Version), leefHeader(entry.Tool))
+
+	attrs := []string{
+		"devTime=" + at.UTC().Format(leefTimeFormat),
+		"cat=" + leefValue(entry.Tool),
+		"sev=" + strconv.Itoa(auditSeverity(entry)),
+		"outcome=" + outcome,
+	}
+	add := func(key, value string) {
+		if value != "" {
+			attrs = append(attrs, key+"="+leefValue(value))
+		}
+	}
+	add("source", entry.Source)
+	add("src", entry.RemoteIP)
+	add("apiKey", entry.APIKey)
+	add("client", entry.Client)
+	add("clientVersion", entry.ClientVersion)
+	add("usrName", entry.DBUser)
+	add("connection", entry.Connection)
+	add("database", entry.Database)
+	add("requestId", entry.RequestID)
+	add("queryDigest", entry.QueryDigest)
+	add("query", entry.Query)
+	if entry.Duplicate {
+		add("duplicate", "true")
+	}
+	attrs = append(attrs, "durationMs="+strconv.FormatInt(entry.DurationMs, 10))
+	attrs = append(attrs, "rowCount="+strconv.Itoa(entry.RowCount))
+	add("error", entry.Error)
+	b.WriteString(strings.Join(attrs, "\t"))
+	return b.String()
+}
+
+// leefHeader makes s safe for a LEEF header field, which has no escaping.
+func leefHeader(s string) string {
+	return strings.NewReplacer("|", "/", "\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+// leefValue makes s safe for a tab-delimited LEEF attribute value.
+func leefValue(s string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+// activeConnectionIdentity returns the name of the active connection and the
+// MySQL user of its DSN, for audit entries.
+func activeConnectionIdentity() (name, user string) {
+	if connManager == nil {
+		return "", ""
+	}
+	_, name = connManager.GetActive()
+	c, ok := connManager.Config(name)
+	if !ok {
+		return name, ""
+	}
+	if parsed, err := mysql.ParseDSN(c.DSN); err == nil {
+		user = parsed.User
+	}
+	return name, user
+}
@@ -0,0 +1,135 @@
+// pkg/mysqlmcp/audit_format_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+func testAuditEntry() *AuditEntry {
+	return &AuditEntry{
+		RequestID:   "req-1",
+		Source:      "http",
+		RemoteIP:    "10.0.0.7",
+		Client:      "cursor",
+		Connection:  "prod",
+		DBUser:      "reader",
+		Tool:        "run_query",
+		Database:    "shop",
+		Query:       "SELECT a=1 FROM t|x\nWHERE\tb = ?",
+		QueryDigest: "abc123",
+		DurationMs:  12,
+		RowCount:    3,
+		Success:     false,
+		Error:       `bad \ thing`,
+	}
+}
+
+func TestFormatAuditCEF(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	line := formatAuditEntry(config.AuditFormatCEF, testAuditEntry(), at)
+
+	wantHeader := "CEF:0|askdba|mysql-mcp-server|" + Version + "|run_query|Query failed|7|"
+	if !strings.HasPrefix(line, wantHeader) {
+		t.Fatalf("unexpected CEF header: %s", line)
+	}
+	for _, want := range []string{
+		"rt=1767323045000",
+		"outcome=failure",
+		"app=http",
+		"duser=reader",
+		"cs1Label=connection cs1=prod",
+		"cs2=shop",
+		"cs3=req-1",
+		`cs5=SELECT a\=1 FROM t|x\nWHERE` + "\tb \\= ?",
+		"cn1=12",
+		"cnt=3",
+		`reason=bad \\ thing`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("CEF line missing %q:\n%s", want, line)
+		}
+	}
+	if strings.Contains(line, "\n") {
+		t.Errorf("CEF line must be a single line: %q", line)
+	}
+}
+
+func TestFormatAuditLEEF(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	entry := testAuditEntry()
+	entry.Tool = "run|query"
+	line := formatAuditEntry(config.AuditFormatLEEF, entry, at)
+
+	wantHeader := "LEEF:1.0|askdba|mysql-mcp-server|" + Version + "|run/query|"
+	if !strings.HasPrefix(line, wantHeader) {
+		t.Fatalf("unexpected LEEF header: %s", line)
+	}
+	attrs := map[string]string{}
+	for _, kv := range strings.Split(strings.TrimPrefix(line, wantHeader), "\t") {
+		k, v, _ := strings.Cut(kv, "=")
+		attrs[k] = v
+	}
+	want := map[string]string{
+		"devTime":    "Jan 02 2026 03:04:05.000 UTC",
+		"sev":        "7",
+		"usrName":    "reader",
+		"connection": "prod",
+		"source":     "http",
+		"src":        "10.0.0.7",
+		"client":     "cursor",
+		"query":      "SELECT a=1 FROM t|x WHERE b = ?",
+		"durationMs": "12",
+		"rowCount":   "3",
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("LEEF %s = %q, want %q", k, attrs[k], v)
+		}
+	}
+}
+
+func TestAuditLoggerFormatAndIdentity(t *testing.T) {
+	cm := NewConnectionManager()
+	cm.configs["reporting"] = config.ConnectionConfig{Name: "reporting", DSN: "auditor:secret@tcp(db:3306)/shop"}
+	cm.activeConn = "reporting"
+	oldCM := connManager
+	connManager = cm
+	defer func() { connManager = oldCM }()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := NewAuditLogger(path)
+	if err != nil {
+		t.Fatalf("NewAuditLogger failed: %v", err)
+	}
+	logger.format = config.AuditFormatCEF
+	ctx := withRequestSource(context.Background(), requestSourceHTTP)
+	ctx = withClientIdentity(ctx, clientIdentity{APIKey: "***9f3a", RemoteIP: "10.0.0.7", Client: "claude-desktop", ClientVersion: "1.2"})
+	logger.LogContext(withRequestID(ctx, "req-9"), &AuditEntry{Tool: "list_tables", Success: true})
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	line := string(data)
+	for _, want := range []string{"CEF:0|", "|Query executed|3|", "cs1=reporting", "duser=auditor", "app=http",
+		"src=10.0.0.7", "suser=***9f3a", "requestClientApplication=claude-desktop 1.2", "cs3=req-9",
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("audit line missing %q: %s", want, line)
+		}
+	}
+	if strings.Contains(line, "secret") {
+		t.Errorf("audit line leaked the DSN password: %s", line)
+	}
+	if got := requestSourceFrom(context.Background()); got != requestSourceMCP {
+		t.Errorf("expected default source mcp, got %q", got)
+	}
+}
@@ -0,0 +1,174 @@
+// pkg/mysqlmcp/binary_output.go
+package mysqlmcp
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// binaryHexPreviewBytes is how many leading bytes the hex mode shows.
+const binaryHexPreviewBytes = 32
+
+// binaryOutput is the default rendering of binary cells (MYSQL_MCP_BINARY_OUTPUT).
+var binaryOutput = config.DefaultBinaryOutput
+
+// resolveBinaryOutput returns the binary rendering for one call: mode when
+// set, otherwise the configured default.
+func resolveBinaryOutput(mode string) (string, error) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return binaryOutput, nil
+	}
+	if !config.ValidBinaryOutput(mode) {
+		return "", fmt.Errorf("binary_output must be one of hex, base64, length, skip or raw")
+	}
+	return mode, nil
+}
+
+// isBinaryColumnType reports whether a driver type name holds raw bytes.
+// The MySQL driver reports TEXT columns as TEXT, so only binary strings match.
+func isBinaryColumnType(name string) bool {
+	switch strings.ToUpper(name) {
+	case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB":
+		return true
+	}
+	return false
+}
+
+// isGeometryColumnType reports whether a driver type name is a spatial type.
+// The MySQL driver reports every spatial column as GEOMETRY.
+func isGeometryColumnType(name string) bool {
+	switch strings.ToUpper(name) {
+	case "GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING",
+		"MULTIPOLYGON", "GEOMETRYCOLLECTION", "GEOMCOLLECTION":
+		return true
+	}
+	return false
+}
+
+// Cell kinds a cellRenderer rewrites.
+const (
+	cellPlain = iota
+	cellBinary
+	cellGeometry
+)
+
+// cellRenderer rewrites the binary and spatial cells of each row for one
+// result. A nil renderer leaves rows unchanged.
+type cellRenderer struct {
+	mode string // binary_output mode
+	kind []int
+	keep []int // source column of each output column (skip mode)
+}
+
+// newCellRenderer inspects the column types of rows; it returns nil when
+// there is nothing to rewrite.
+func newCellRenderer(rows *sql.Rows, mode string) (*cellRenderer, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column types: %w", err)
+	}
+	r := &cellRenderer{mode: mode, kind: make([]int, len(types))}
+	found := false
+	for i, t := range types {
+		switch name := t.DatabaseTypeName(); {
+		case isGeometryColumnType(name):
+			r.kind[i] = cellGeometry
+			found = true
+		case isBinaryColumnType(name) && mode != config.BinaryOutputRaw:
+			r.kind[i] = cellBinary
+			found = true
+			if mode == config.BinaryOutputSkip {
+				continue
+			}
+		}
+		r.keep = append(r.keep, i)
+	}
+	if !found {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// columns returns the output column names and, in skip mode, the names left out.
+func (r *cellRenderer) columns(cols []string) (out, skipped []string) {
+	if r == nil || len(r.keep) == len(cols) {
+		return cols, nil
+	}
+	out = make([]string, 0, len(r.keep))
+	for _, i := range r.keep {
+		out = append(out, cols[i])
+	}
+	for i, k := range r.kind {
+		if k == cellBinary {
+			skipped = append(skipped, cols[i])
+		}
+	}
+	return out, skipped
+}
+
+// cell renders the non-NULL value s of source column i.
+func (r *cellRenderer) cell(i int, s string) interface{} {
+	if r == nil {
+		return s
+	}
+	switch r.kind[i] {
+	case cellGeometry:
+		if wkt, err := geometryToWKT([]byte(s)); err == nil {
+			return wkt
+		}
+		return renderBinaryCell(s, config.BinaryOutputHex)
+	case cellBinary:
+		if r.mode != config.BinaryOutputSkip {
+			return renderBinaryCell(s, r.mode)
+		}
+	}
+	return s
+}
+
+// row renders the binary and spatial cells of a normalized row.
+func (r *cellRenderer) row(values []interface{}) []interface{} {
+	if r == nil {
+		return values
+	}
+	for i := range r.kind {
+		if s, ok := values[i].(string); ok {
+			values[i] = r.cell(i, s)
+		}
+	}
+	if len(r.keep) == len(values) {
+		return values
+	}
+	out := make([]interface{}, 0, len(r.keep))
+	for _, i := range r.keep {
+		out = append(out, values[i])
+	}
+	return out
+}
+
+// sourceColumn maps an output column index back to the query's column index.
+func (r *cellRenderer) sourceColumn(i int) int {
+	if r == nil {
+		return i
+	}
+	return r.keep[i]
+}
+
+// renderBinaryCell formats the bytes of one binary cell for mode.
+func renderBinaryCell(b string, mode string) string {
+	switch mode {
+	case config.BinaryOutputBase64:
+		return base64.StdEncoding.EncodeToString([]byte(b))
+	case config.BinaryOutputLength:
+		return fmt.Sprintf("<binary %d bytes>", len(b))
+	}
+	if len(b) <= binaryHexPreviewBytes {
+		return "0x" + hex.EncodeToString([]byte(b))
+	}
+	return fmt.Sprintf("0x%s… (%d bytes)", hex.EncodeToString([]byte(b[:binaryHexPreviewBytes])), len(b))
+}
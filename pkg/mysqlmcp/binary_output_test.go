@@ -0,0 +1,66 @@
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRenderBinaryCell(t *testing.T) {
+	short := "\x00\x01\xff"
+	long := strings.Repeat("\xab", 40)
+	tests := []struct {
+		value, mode, want string
+	}{
+		{short, "hex", "0x0001ff"},
+		{long, "hex", "0x" + strings.Repeat("ab", binaryHexPreviewBytes) + "… (40 bytes)"},
+		{short, "base64", "AAH/"},
+		{long, "length", "<binary 40 bytes>"},
+	}
+	for _, tt := range tests {
+		if got := renderBinaryCell(tt.value, tt.mode); got != tt.want {
+			t.Errorf("renderBinaryCell(%q, %s) = %q, want %q", tt.value, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func binaryRows() *sqlmock.Rows {
+	return sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("INT", int64(0)),
+		sqlmock.NewColumn("note").OfType("TEXT", ""),
+		sqlmock.NewColumn("payload").OfType("BLOB", []byte(nil)),
+	).AddRow(int64(1), "hello", []byte{0xde, 0xad}).AddRow(int64(2), "world", nil)
+}
+
+func TestRunQueryBinaryOutput(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT id, note, payload FROM files").WillReturnRows(binaryRows())
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, note, payload FROM files"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if out.Rows[0][2] != "0xdead" || out.Rows[0][1] != "hello" || out.Rows[1][2] != nil {
+		t.Errorf("default hex rendering: got %v", out.Rows)
+	}
+
+	mock.ExpectQuery("SELECT id, note, payload FROM files").WillReturnRows(binaryRows())
+	_, out, err = toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, note, payload FROM files", BinaryOutput: "skip"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if len(out.Columns) != 2 || len(out.Rows[0]) != 2 || len(out.SkippedColumns) != 1 || out.SkippedColumns[0] != "payload" {
+		t.Errorf("skip: columns=%v skipped=%v rows=%v", out.Columns, out.SkippedColumns, out.Rows)
+	}
+
+	if _, _, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1", BinaryOutput: "bytes"}); err == nil {
+		t.Error("expected error for unknown binary_output")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
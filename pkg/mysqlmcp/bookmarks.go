@@ -0,0 +1,283 @@
+// pkg/mysqlmcp/bookmarks.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Bookmarks are saved queries shared over the HTTP API (/api/bookmarks) and
+// kept in a JSON file (MYSQL_HTTP_BOOKMARKS_FILE), so they survive restarts
+// and every user of the server sees them. They join the saved query library
+// with source "bookmark": MCP clients find them with list_saved_queries and
+// run them with run_saved_query, like any other saved query.
+
+const savedQuerySourceBookmark = "bookmark"
+
+// bookmarkRecord is one entry of the bookmarks file.
+type bookmarkRecord struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	SQL         string                `json:"sql"`
+	Database    string                `json:"database,omitempty"`
+	Params      []SavedQueryParamInfo `json:"params,omitempty"`
+	CreatedBy   string                `json:"created_by,omitempty"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+func (r bookmarkRecord) savedQuery() config.SavedQuery {
+	q := config.SavedQuery{Name: r.Name, Description: r.Description, SQL: r.SQL, Database: r.Database}
+	for _, p := range r.Params {
+		q.Params = append(q.Params, config.SavedQueryParam(p))
+	}
+	return q
+}
+
+// bookmarkFile is the layout of the bookmarks file.
+type bookmarkFile struct {
+	Bookmarks []bookmarkRecord `json:"bookmarks"`
+}
+
+// bookmarkStore persists bookmarks and mirrors them into savedQueries.
+type bookmarkStore struct {
+	mu      sync.Mutex
+	path    string
+	records map[string]bookmarkRecord
+}
+
+// bookmarks is nil when MYSQL_HTTP_BOOKMARKS_FILE is not set.
+var bookmarks *bookmarkStore
+
+// openBookmarkStore reads path, which may not exist yet, and registers its
+// bookmarks as saved queries. Bookmarks that no longer validate, or whose
+// name is taken by a config file query, are logged and stay in the file.
+func openBookmarkStore(path string) (*bookmarkStore, error) {
+	s := &bookmarkStore{path: path, records: make(map[string]bookmarkRecord)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks file: %w", err)
+	}
+	var f bookmarkFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid bookmarks file %s: %w", path, err)
+	}
+	for _, r := range f.Bookmarks {
+		s.records[r.Name] = r
+		if _, err := savedQueries.add(r.savedQuery(), savedQuerySourceBookmark); err != nil {
+			serverLog.Warn("bookmark skipped", map[string]interface{}{"name": r.Name, "error": err.Error()})
+		}
+	}
+	return s, nil
+}
+
+// save validates r, stores it as a saved query and writes the file. It
+// returns the stored record and whether an earlier bookmark or runtime query
+// was replaced.
+func (s *bookmarkStore) save(r bookmarkRecord) (bookmarkRecord, bool, error) {
+	// A rejected bookmark is a problem with the request, not the server.
+	compiled, err := compileSavedQuery(r.savedQuery(), savedQuerySourceBookmark)
+	if err != nil {
+		return bookmarkRecord{}, false, &InputError{Problems: []error{err}}
+	}
+	info := compiled.info()
+	r.Name, r.Description, r.SQL, r.Database, r.Params = info.Name, info.Description, info.SQL, info.Database, info.Params
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, _ := savedQueries.get(r.Name)
+	replaced, err := savedQueries.add(r.savedQuery(), savedQuerySourceBookmark)
+	if err != nil {
+		return bookmarkRecord{}, false, &InputError{Problems: []error{err}}
+	}
+	old, hadRecord := s.records[r.Name]
+	s.records[r.Name] = r
+	if err := s.writeLocked(); err != nil {
+		if hadRecord {
+			s.records[r.Name] = old
+		} else {
+			delete(s.records, r.Name)
+		}
+		savedQueries.set(r.Name, previous)
+		return bookmarkRecord{}, false, err
+	}
+	return r, replaced, nil
+}
+
+// delete removes the named bookmark, reporting whether it existed.
+func (s *bookmarkStore) delete(name string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.records[name]
+	if !ok {
+		return false, nil
+	}
+	delete(s.records, name)
+	if err := s.writeLocked(); err != nil {
+		s.records[name] = old
+		return false, err
+	}
+	if q, ok := savedQueries.get(name); ok && q.source == savedQuerySourceBookmark {
+		savedQueries.set(name, nil)
+	}
+	return true, nil
+}
+
+func (s *bookmarkStore) list() []bookmarkRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]bookmarkRecord, 0, len(s.records))
+	for _, r := range s.records {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// writeLocked rewrites the bookmarks file. Callers must hold s.mu.
+func (s *bookmarkStore) writeLocked() error {
+	f := bookmarkFile{Bookmarks: make([]bookmarkRecord, 0, len(s.records))}
+	for _, r := range s.records {
+		f.Bookmarks = append(f.Bookmarks, r)
+	}
+	sort.Slice(f.Bookmarks, func(i, j int) bool { return f.Bookmarks[i].Name < f.Bookmarks[j].Name })
+	if err := writeJSONFile(s.path, f); err != nil {
+		return fmt.Errorf("failed to write bookmarks file: %w", err)
+	}
+	return nil
+}
+
+// writeJSONFile replaces path with v as indented JSON through a temporary
+// file in the same directory, so a crash never leaves it half written.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// bookmarkAuthor names the caller in ctx for a bookmark's created_by.
+func bookmarkAuthor(ctx context.Context) string {
+	id := clientIdentityFrom(ctx)
+	switch {
+	case id.APIKey != "":
+		return "api_key " + id.APIKey
+	case id.Client != "":
+		return id.Client
+	default:
+		return id.RemoteIP
+	}
+}
+
+func (r bookmarkRecord) info() BookmarkInfo {
+	info := BookmarkInfo{
+		SavedQueryInfo: SavedQueryInfo{
+			Name:        r.Name,
+			Description: r.Description,
+			Database:    r.Database,
+			SQL:         r.SQL,
+			Params:      r.Params,
+			Source:      savedQuerySourceBookmark,
+		},
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if info.Params == nil {
+		info.Params = []SavedQueryParamInfo{}
+	}
+	return info
+}
+
+func toolListBookmarks(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListBookmarksInput,
+) (*mcp.CallToolResult, ListBookmarksOutput, error) {
+	out := ListBookmarksOutput{Bookmarks: []BookmarkInfo{}}
+	if bookmarks == nil {
+		return nil, out, nil
+	}
+	for _, r := range bookmarks.list() {
+		if r.Database != "" && accessControlEnabled() && !databaseAllowed(r.Database) {
+			continue
+		}
+		out.Bookmarks = append(out.Bookmarks, r.info())
+	}
+	return nil, out, nil
+}
+
+func toolSaveBookmark(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SaveQueryInput,
+) (*mcp.CallToolResult, SaveBookmarkOutput, error) {
+	if bookmarks == nil {
+		return nil, SaveBookmarkOutput{}, fmt.Errorf("bookmarks are not enabled (set MYSQL_HTTP_BOOKMARKS_FILE)")
+	}
+	if input.Database != "" {
+		if err := requireAllowedDatabase(strings.TrimSpace(input.Database)); err != nil {
+			return nil, SaveBookmarkOutput{}, err
+		}
+	}
+	r := bookmarkRecord{
+		Name:        input.Name,
+		Description: input.Description,
+		SQL:         input.SQL,
+		Database:    input.Database,
+		Params:      input.Params,
+		CreatedBy:   bookmarkAuthor(ctx),
+		CreatedAt:   time.Now(),
+	}
+	r, replaced, err := bookmarks.save(r)
+	if err != nil {
+		return nil, SaveBookmarkOutput{}, err
+	}
+	serverLog.Info("bookmark saved", map[string]interface{}{"name": r.Name, "created_by": r.CreatedBy, "replaced": replaced})
+	return nil, SaveBookmarkOutput{Bookmark: r.info(), Replaced: replaced}, nil
+}
+
+func toolDeleteBookmark(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input DeleteBookmarkInput,
+) (*mcp.CallToolResult, DeleteBookmarkOutput, error) {
+	if bookmarks == nil {
+		return nil, DeleteBookmarkOutput{}, fmt.Errorf("bookmarks are not enabled (set MYSQL_HTTP_BOOKMARKS_FILE)")
+	}
+	name := strings.TrimSpace(input.Name)
+	deleted, err := bookmarks.delete(name)
+	if err != nil {
+		return nil, DeleteBookmarkOutput{}, err
+	}
+	if !deleted {
+		return nil, DeleteBookmarkOutput{}, fmt.Errorf("bookmark not found: %s", name)
+	}
+	serverLog.Info("bookmark deleted", map[string]interface{}{"name": name})
+	return nil, DeleteBookmarkOutput{Name: name, Deleted: true}, nil
+}
@@ -0,0 +1,147 @@
+// pkg/mysqlmcp/bookmarks_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withBookmarks opens a bookmark store on path with a fresh saved query library.
+func withBookmarks(t *testing.T, path string, queries ...config.SavedQuery) func() {
+	t.Helper()
+	restoreQueries := withSavedQueries(t, queries...)
+	old := bookmarks
+	store, err := openBookmarkStore(path)
+	if err != nil {
+		t.Fatalf("openBookmarkStore failed: %v", err)
+	}
+	bookmarks = store
+	return func() {
+		bookmarks = old
+		restoreQueries()
+	}
+}
+
+func TestBookmarkStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	restore := withBookmarks(t, path, config.SavedQuery{Name: "reviewed", SQL: "SELECT 1"})
+
+	_, out, err := toolSaveBookmark(context.Background(), &mcp.CallToolRequest{}, SaveQueryInput{
+		Name:        " by_email ",
+		Description: "user by email",
+		SQL:         "SELECT id FROM users WHERE email = :email",
+	})
+	if err != nil {
+		t.Fatalf("save_bookmark failed: %v", err)
+	}
+	if out.Bookmark.Name != "by_email" || out.Bookmark.Source != savedQuerySourceBookmark || len(out.Bookmark.Params) != 1 || out.Replaced {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if q, ok := savedQueries.get("by_email"); !ok || q.source != savedQuerySourceBookmark {
+		t.Errorf("bookmark missing from the saved query library: %+v", q)
+	}
+	if _, _, err := toolSaveBookmark(context.Background(), &mcp.CallToolRequest{}, SaveQueryInput{Name: "reviewed", SQL: "SELECT 2"}); err == nil {
+		t.Error("expected config query to be protected")
+	}
+	if _, _, err := toolSaveBookmark(context.Background(), &mcp.CallToolRequest{}, SaveQueryInput{Name: "drop", SQL: "DROP TABLE users"}); err == nil {
+		t.Error("expected invalid SQL to be rejected")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("bookmarks file not written: %v", err)
+	}
+	var f bookmarkFile
+	if err := json.Unmarshal(data, &f); err != nil || len(f.Bookmarks) != 1 || f.Bookmarks[0].Name != "by_email" {
+		t.Fatalf("unexpected bookmarks file: %s", data)
+	}
+	restore()
+
+	// A restart loads the file back into the library.
+	defer withBookmarks(t, path)()
+	_, list, _ := toolListBookmarks(context.Background(), &mcp.CallToolRequest{}, ListBookmarksInput{})
+	if len(list.Bookmarks) != 1 || list.Bookmarks[0].Description != "user by email" || list.Bookmarks[0].CreatedAt == "" {
+		t.Fatalf("unexpected list: %+v", list.Bookmarks)
+	}
+	if _, ok := savedQueries.get("by_email"); !ok {
+		t.Error("bookmark not registered after reload")
+	}
+
+	if _, out, err := toolDeleteBookmark(context.Background(), &mcp.CallToolRequest{}, DeleteBookmarkInput{Name: "by_email"}); err != nil || !out.Deleted {
+		t.Fatalf("delete_bookmark failed: %+v, %v", out, err)
+	}
+	if _, ok := savedQueries.get("by_email"); ok {
+		t.Error("deleted bookmark still in the saved query library")
+	}
+	if _, _, err := toolDeleteBookmark(context.Background(), &mcp.CallToolRequest{}, DeleteBookmarkInput{Name: "by_email"}); err == nil {
+		t.Error("expected error for unknown bookmark")
+	}
+}
+
+func TestHTTPBookmarks(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	cfg.BookmarksFile = path
+	defer withBookmarks(t, path, config.SavedQuery{Name: "reviewed", SQL: "SELECT 1"})()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/bookmarks",
+		strings.NewReader(`{"name": "open_orders", "sql": "SELECT id FROM orders WHERE customer_id = :customer_id", "params": [{"name": "customer_id", "type": "int"}]}`))
+	w := httptest.NewRecorder()
+	httpBookmarks(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST /api/bookmarks: %d %s", w.Code, w.Body.String())
+	}
+
+	// Rejected bookmarks answer 400, not 500.
+	for _, body := range []string{
+		`{"name": "wipe", "sql": "DELETE FROM orders"}`,
+		`{"name": "reviewed", "sql": "SELECT 2"}`,
+	} {
+		w = httptest.NewRecorder()
+		httpBookmarks(w, httptest.NewRequest(http.MethodPost, "/api/bookmarks", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: expected 400, got %d %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	mock.ExpectQuery("SELECT id FROM orders WHERE customer_id = \\?").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	w = httptest.NewRecorder()
+	httpRunBookmark(w, httptest.NewRequest(http.MethodGet, "/api/bookmarks/run?name=open_orders&customer_id=7&max_rows=5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/bookmarks/run: %d %s", w.Code, w.Body.String())
+	}
+
+	// Config queries are not bookmarks.
+	w = httptest.NewRecorder()
+	httpRunBookmark(w, httptest.NewRequest(http.MethodGet, "/api/bookmarks/run?name=reviewed", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a config query, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	httpBookmarks(w, httptest.NewRequest(http.MethodDelete, "/api/bookmarks?name=missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown bookmark, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	httpBookmarks(w, httptest.NewRequest(http.MethodDelete, "/api/bookmarks?name=open_orders", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("DELETE /api/bookmarks: %d %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
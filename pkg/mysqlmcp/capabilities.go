@@ -0,0 +1,235 @@
+// pkg/mysqlmcp/capabilities.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// The same tools run against MySQL 5.7 and 9.x, MariaDB, managed services
+// with performance_schema turned off and accounts without PROCESS. Instead
+// of letting a tool fail halfway through with a raw MySQL error, each
+// connection is probed once when it opens: its version features, whether
+// performance_schema is on and readable, and which optional system tables the
+// account may read. Tools whose capability is known to be missing answer
+// "not supported on this connection" with what to use instead before they
+// touch the server, and server_info reports the whole matrix. A capability
+// that could not be probed (a timeout, an unexpected error) is unknown and
+// never blocks a tool.
+
+// MySQL error numbers that settle a probe: the account lacks the privilege
+// or the server lacks the object.
+const (
+	errTableAccessDenied    = 1142 // ER_TABLEACCESS_DENIED_ERROR
+	errDBAccessDenied       = 1044 // ER_DBACCESS_DENIED_ERROR
+	errSpecificAccessDenied = 1227 // ER_SPECIFIC_ACCESS_DENIED_ERROR
+	errUnknownSystemVar     = 1193 // ER_UNKNOWN_SYSTEM_VARIABLE
+)
+
+// errToolUnsupported is wrapped by UnsupportedToolError so HTTP handlers can
+// answer 501.
+var errToolUnsupported = errors.New("tool not supported on this connection")
+
+// UnsupportedToolError reports that the active connection lacks a capability
+// the tool needs.
+type UnsupportedToolError struct {
+	Tool        string
+	Connection  string
+	Reason      string
+	Alternative string
+}
+
+func (e *UnsupportedToolError) Error() string {
+	return fmt.Sprintf("%s is not supported on connection %s: %s; instead, %s", e.Tool, e.Connection, e.Reason, e.Alternative)
+}
+
+func (e *UnsupportedToolError) Unwrap() error { return errToolUnsupported }
+
+// toolRequirement is a capability a tool cannot work without.
+type toolRequirement struct {
+	capability  func(*ConnectionCapabilities) *bool
+	reason      string // why the tool cannot run when the capability is missing
+	alternative string // what to use instead
+}
+
+var (
+	vectorRequirement = toolRequirement{
+		capability:  func(c *ConnectionCapabilities) *bool { return c.VectorType },
+		reason:      "the VECTOR type needs MySQL 9.0 or later",
+		alternative: "keep embeddings in a JSON or BLOB column and rank them in the client, or use fulltext_search for keyword matches",
+	}
+	heatWaveRequirement = toolRequirement{
+		capability:  func(c *ConnectionCapabilities) *bool { return c.HeatWave },
+		reason:      "the server has no HeatWave (RAPID) engine",
+		alternative: "use explain_query and run_query, which run on InnoDB",
+	}
+	auroraRequirement = toolRequirement{
+		capability:  func(c *ConnectionCapabilities) *bool { return c.Aurora },
+		reason:      "the server is not Amazon Aurora MySQL",
+		alternative: "use health_report for replication lag, and list_connections for the configured replicas",
+	}
+)
+
+// toolRequirements lists the tools that fail without a capability. Tools
+// that degrade on their own (list_sessions without performance_schema,
+// slow_query_log without mysql.slow_log) are not listed.
+var toolRequirements = map[string]toolRequirement{
+	"vector_search":       vectorRequirement,
+	"vector_insert":       vectorRequirement,
+	"vector_delete":       vectorRequirement,
+	"heatwave_status":     heatWaveRequirement,
+	"heatwave_ml_predict": heatWaveRequirement,
+	"aurora_topology":     auroraRequirement,
+	"optimizer_trace": {
+		capability:  func(c *ConnectionCapabilities) *bool { return c.OptimizerTrace },
+		reason:      "the optimizer trace needs MySQL 5.6+ or MariaDB 10.4+",
+		alternative: "use explain_query for the chosen plan",
+	},
+}
+
+// requireCapability fails with an *UnsupportedToolError when the active
+// connection is known to lack a capability tool needs.
+func requireCapability(tool string) error {
+	req, ok := toolRequirements[tool]
+	if !ok || connManager == nil {
+		return nil
+	}
+	caps, name := connManager.ActiveCapabilities()
+	if caps == nil {
+		return nil
+	}
+	if have := req.capability(caps); have == nil || *have {
+		return nil
+	}
+	return &UnsupportedToolError{Tool: tool, Connection: name, Reason: req.reason, Alternative: req.alternative}
+}
+
+// probeCapabilities probes db, a server of type st. Each probe that cannot
+// be settled leaves its capability nil.
+func probeCapabilities(ctx context.Context, db *sql.DB, st ServerType) *ConnectionCapabilities {
+	c := &ConnectionCapabilities{Flavor: string(st), ProbedAt: time.Now().UTC().Format(time.RFC3339)}
+
+	if err := db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&c.Version); err == nil {
+		c.versionFeatures(st)
+	}
+	c.Middleware = detectMiddleware(ctx, db, c.Version)
+
+	var pfs int
+	switch err := db.QueryRowContext(ctx, "SELECT @@performance_schema").Scan(&pfs); {
+	case err == nil && pfs == 0:
+		c.PerformanceSchema = capability(false)
+	case err == nil:
+		c.PerformanceSchema = probeRead(ctx, db, "SELECT 1 FROM performance_schema.global_status LIMIT 1")
+	default:
+		c.PerformanceSchema = probeOutcome(err)
+	}
+	// The InnoDB tables of information_schema need the PROCESS privilege,
+	// which also decides whether other accounts' threads are visible.
+	c.ProcessPrivilege = probeRead(ctx, db, "SELECT 1 FROM information_schema.INNODB_TRX LIMIT 1")
+	c.SlowLogTable = probeRead(ctx, db, "SELECT 1 FROM mysql.slow_log LIMIT 1")
+	c.OptimizerTrace = probeRead(ctx, db, "SELECT @@optimizer_trace")
+
+	if st == ServerTypeMariaDB {
+		c.HeatWave = capability(false)
+		c.Aurora = capability(false)
+	} else {
+		if rows, err := db.QueryContext(ctx, "SHOW GLOBAL STATUS LIKE 'rapid_service_status'"); err == nil {
+			c.HeatWave = capability(rows.Next())
+			rows.Close()
+		}
+		c.Aurora = probeRead(ctx, db, "SELECT @@aurora_version")
+	}
+
+	c.Unsupported = []UnsupportedTool{}
+	for tool, req := range toolRequirements {
+		if have := req.capability(c); have != nil && !*have {
+			c.Unsupported = append(c.Unsupported, UnsupportedTool{Tool: tool, Reason: req.reason, Alternative: req.alternative})
+		}
+	}
+	sort.Slice(c.Unsupported, func(i, j int) bool { return c.Unsupported[i].Tool < c.Unsupported[j].Tool })
+	return c
+}
+
+// versionFeatures sets the capabilities that follow from the server version.
+func (c *ConnectionCapabilities) versionFeatures(st ServerType) {
+	v, ok := parseServerVersion(c.Version)
+	if !ok || st == ServerTypeUnknown {
+		return
+	}
+	if st == ServerTypeMariaDB {
+		c.CTEs = capability(v >= 100200)
+		c.WindowFunctions = capability(v >= 100200)
+		c.JSONTable = capability(v >= 100600)
+		c.ExplainAnalyze = capability(false) // MariaDB spells it ANALYZE <statement>
+		c.VectorType = capability(false)
+		return
+	}
+	c.CTEs = capability(v >= 80000)
+	c.WindowFunctions = capability(v >= 80000)
+	c.JSONTable = capability(v >= 80004)
+	c.ExplainAnalyze = capability(v >= 80018)
+	c.VectorType = capability(v >= 90000)
+}
+
+// parseServerVersion turns "8.0.36-log" or "10.11.6-MariaDB" into
+// major*10000 + minor*100 + patch.
+func parseServerVersion(version string) (int, bool) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, false
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		end := 0
+		for end < len(p) && p[end] >= '0' && p[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(p[:end])
+		if err != nil {
+			return 0, false
+		}
+		nums[i] = n
+	}
+	return nums[0]*10000 + nums[1]*100 + nums[2], true
+}
+
+// probeRead runs query and reports whether it could be read: true when it
+// ran, false when MySQL denied it or lacks the object, nil otherwise.
+func probeRead(ctx context.Context, db *sql.DB, query string) *bool {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return probeOutcome(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		return probeOutcome(err)
+	}
+	return capability(true)
+}
+
+// probeOutcome settles a failed probe as missing when MySQL reports a denied
+// privilege or an unknown object, and leaves it unknown otherwise.
+func probeOutcome(err error) *bool {
+	var me *mysql.MySQLError
+	if !errors.As(err, &me) {
+		return nil
+	}
+	switch me.Number {
+	case errTableAccessDenied, errDBAccessDenied, errSpecificAccessDenied,
+		errNoSuchTable, errUnknownDatabase, errUnknownTable, errUnknownSystemVar:
+		return capability(false)
+	}
+	return nil
+}
+
+func capability(v bool) *bool { return &v }
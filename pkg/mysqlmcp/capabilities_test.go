@@ -0,0 +1,118 @@
+// pkg/mysqlmcp/capabilities_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	for in, want := range map[string]int{
+		"8.0.36":           80036,
+		"8.0.36-log":       80036,
+		"9.1.0-commercial": 90100,
+		"10.11.6-MariaDB":  101106,
+		"5.7":              50700,
+	} {
+		if got, ok := parseServerVersion(in); !ok || got != want {
+			t.Errorf("parseServerVersion(%q) = %d, %v; want %d", in, got, ok, want)
+		}
+	}
+	if _, ok := parseServerVersion("demo"); ok {
+		t.Error("expected an unparseable version to fail")
+	}
+}
+
+func TestProbeCapabilities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow("5.7.44-log"))
+	mock.ExpectQuery("select @@version_comment limit 1").WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow("MySQL Community Server (GPL)"))
+	mock.ExpectQuery("SELECT @@performance_schema").WillReturnRows(sqlmock.NewRows([]string{"p"}).AddRow(0))
+	mock.ExpectQuery("information_schema.INNODB_TRX").
+		WillReturnError(&mysql.MySQLError{Number: 1227, Message: "Access denied; you need (at least one of) the PROCESS privilege(s)"})
+	mock.ExpectQuery("mysql.slow_log").
+		WillReturnError(&mysql.MySQLError{Number: 1142, Message: "SELECT command denied to user 'app'@'%' for table 'slow_log'"})
+	mock.ExpectQuery("SELECT @@optimizer_trace").WillReturnError(errors.New("i/o timeout"))
+	mock.ExpectQuery("rapid_service_status").WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}))
+	mock.ExpectQuery("SELECT @@aurora_version").
+		WillReturnError(&mysql.MySQLError{Number: 1193, Message: "Unknown system variable 'aurora_version'"})
+
+	c := probeCapabilities(context.Background(), db, ServerTypeMySQL)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unfulfilled expectations: %v", err)
+	}
+	for name, got := range map[string]*bool{
+		"performance_schema": c.PerformanceSchema,
+		"process_privilege":  c.ProcessPrivilege,
+		"slow_log_table":     c.SlowLogTable,
+		"heatwave":           c.HeatWave,
+		"aurora":             c.Aurora,
+		"ctes":               c.CTEs,
+		"vector_type":        c.VectorType,
+	} {
+		if got == nil || *got {
+			t.Errorf("expected %s to be false, got %v", name, got)
+		}
+	}
+	if c.OptimizerTrace != nil {
+		t.Errorf("a probe that timed out should leave the capability unknown, got %v", *c.OptimizerTrace)
+	}
+	var tools []string
+	for _, u := range c.Unsupported {
+		tools = append(tools, u.Tool)
+	}
+	if got := strings.Join(tools, ","); got != "aurora_topology,heatwave_ml_predict,heatwave_status,vector_delete,vector_insert,vector_search" {
+		t.Errorf("unexpected unsupported tools %s", got)
+	}
+}
+
+func TestRequireCapability(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	connManager.capabilities["mock"] = &ConnectionCapabilities{Flavor: "mysql", OptimizerTrace: capability(false)}
+
+	_, _, err := toolOptimizerTraceWrapped(context.Background(), &mcp.CallToolRequest{}, OptimizerTraceInput{SQL: "SELECT 1"})
+	var unsupported *UnsupportedToolError
+	if !errors.As(err, &unsupported) || unsupported.Connection != "mock" || !strings.Contains(err.Error(), "use explain_query") {
+		t.Fatalf("expected an unsupported tool error, got %v", err)
+	}
+	w := httptest.NewRecorder()
+	writeToolError(w, err)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("expected 501, got %d", w.Code)
+	}
+
+	// An unknown capability never blocks the tool.
+	connManager.capabilities["mock"].OptimizerTrace = nil
+	if err := requireCapability("optimizer_trace"); err != nil {
+		t.Errorf("expected no error for an unknown capability, got %v", err)
+	}
+
+	mock.ExpectQuery("SELECT VERSION()").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow("8.0.36"))
+	mock.ExpectQuery("performance_schema.global_variables").WillReturnRows(sqlmock.NewRows([]string{"n", "v"}))
+	mock.ExpectQuery("performance_schema.global_status").WillReturnRows(sqlmock.NewRows([]string{"n", "v"}))
+	mock.ExpectQuery("SELECT CURRENT_USER").WillReturnRows(sqlmock.NewRows([]string{"u", "d"}).AddRow("app@%", ""))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, out, err := toolServerInfo(ctx, &mcp.CallToolRequest{}, ServerInfoInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Capabilities == nil || out.Capabilities.Flavor != "mysql" {
+		t.Errorf("expected server_info to report the capabilities, got %+v", out.Capabilities)
+	}
+}
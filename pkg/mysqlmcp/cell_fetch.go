@@ -0,0 +1,322 @@
+// pkg/mysqlmcp/cell_fetch.go
+package mysqlmcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// maxCellQueries bounds how many truncated queries fetch_cell remembers;
+	// the oldest are forgotten first.
+	maxCellQueries = 256
+	// defaultFetchCellLength is the fetch_cell range size when length is unset.
+	defaultFetchCellLength = 1 << 20
+)
+
+// cellQuery is what fetch_cell needs to re-run a query whose result had cells
+// cut by the byte limit. Only the query is kept, never the values.
+type cellQuery struct {
+	Connection string
+	Database   string
+	SQL        string
+	Args       []interface{}
+	Columns    []string
+	TimeZone   string
+	Renderer   *cellRenderer // renders cells as the result showed them
+	// Prefixes holds, per "<row>.<column>", a digest of the part of the cell
+	// the result showed, so a re-run that puts another row at that position
+	// is detected instead of returning the wrong value.
+	Prefixes map[string]cellPrefix
+}
+
+// cellPrefix identifies the start of a truncated cell.
+type cellPrefix struct {
+	Length int
+	Digest [sha256.Size]byte
+}
+
+// cellQueryRegistry maps query hashes to the queries behind issued handles.
+type cellQueryRegistry struct {
+	mu      sync.Mutex
+	queries map[string]cellQuery
+	order   []string
+}
+
+var cellHandles = &cellQueryRegistry{queries: map[string]cellQuery{}}
+
+// register remembers q and returns the handle for column col of row row:
+// <query hash>.<row>.<column index>. shown is the part of the cell the result
+// showed.
+func (r *cellQueryRegistry) register(q cellQuery, row, col int, shown string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%v\x00%s", q.Connection, q.Database, q.SQL, q.Args, q.TimeZone)))
+	hash := hex.EncodeToString(sum[:8])
+	key := fmt.Sprintf("%d.%d", row, col)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.queries[hash]; ok {
+		q.Prefixes = old.Prefixes
+	} else {
+		r.order = append(r.order, hash)
+		if len(r.order) > maxCellQueries {
+			delete(r.queries, r.order[0])
+			r.order = r.order[1:]
+		}
+		q.Prefixes = map[string]cellPrefix{}
+	}
+	q.Prefixes[key] = cellPrefix{Length: len(shown), Digest: sha256.Sum256([]byte(shown))}
+	r.queries[hash] = q
+	return hash + "." + key
+}
+
+// lookup parses handle and returns its query, row and column index, and the
+// prefix of the cell the result showed.
+func (r *cellQueryRegistry) lookup(handle string) (cellQuery, int, int, cellPrefix, error) {
+	parts := strings.Split(strings.TrimSpace(handle), ".")
+	if len(parts) != 3 {
+		return cellQuery{}, 0, 0, cellPrefix{}, fmt.Errorf("invalid cell handle %q", handle)
+	}
+	row, err1 := strconv.Atoi(parts[1])
+	col, err2 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil || row < 0 || col < 0 {
+		return cellQuery{}, 0, 0, cellPrefix{}, fmt.Errorf("invalid cell handle %q", handle)
+	}
+	r.mu.Lock()
+	q, ok := r.queries[parts[0]]
+	prefix, issued := q.Prefixes[parts[1]+"."+parts[2]]
+	r.mu.Unlock()
+	if !ok {
+		return cellQuery{}, 0, 0, cellPrefix{}, fmt.Errorf("cell handle %q has expired; re-run the query to get a new one", handle)
+	}
+	if !issued || col >= len(q.Columns) {
+		return cellQuery{}, 0, 0, cellPrefix{}, fmt.Errorf("invalid cell handle %q", handle)
+	}
+	return q, row, col, prefix, nil
+}
+
+// matches reports whether value, rendered as the result rendered column col,
+// starts with the prefix the result showed.
+func (p cellPrefix) matches(q cellQuery, col int, value []byte, isNull bool) bool {
+	if isNull {
+		return false
+	}
+	rendered, ok := q.Renderer.cell(col, string(value)).(string)
+	return ok && len(rendered) >= p.Length && sha256.Sum256([]byte(rendered[:p.Length])) == p.Digest
+}
+
+// shownPrefix returns the part of full that its truncated form cut kept.
+func shownPrefix(full, cut string) string {
+	n := 0
+	for n < len(full) && n < len(cut) && full[n] == cut[n] {
+		n++
+	}
+	return full[:n]
+}
+
+// withheldColumn reports whether the values of column are hidden from results
+// by the mask or pseudonymization patterns. No handle is issued for such a
+// column and fetch_cell refuses it, since the raw value would bypass them;
+// masks and pseudonyms are short, so the full value is never needed.
+func withheldColumn(column string) bool {
+	return cfg != nil && len(maskedColumns([]string{column}, append(append([]string{}, cfg.MaskColumns...), cfg.PseudonymizeColumns...))) > 0
+}
+
+// connectionNameFor returns the name of the connection whose pool is db,
+// falling back to the active connection.
+func connectionNameFor(db *sql.DB) string {
+	if connManager == nil {
+		return ""
+	}
+	for name, pool := range connManager.Pools() {
+		if pool == db {
+			return name
+		}
+	}
+	_, name := connManager.GetActive()
+	return name
+}
+
+func toolFetchCell(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input FetchCellInput,
+) (*mcp.CallToolResult, FetchCellOutput, error) {
+	timer := NewQueryTimer(ctx, "fetch_cell")
+
+	q, row, col, prefix, err := cellHandles.lookup(input.Handle)
+	if err != nil {
+		return nil, FetchCellOutput{}, err
+	}
+	if withheldColumn(q.Columns[col]) {
+		return nil, FetchCellOutput{}, fmt.Errorf("column %s is masked or pseudonymized (MYSQL_MCP_MASK_COLUMNS, MYSQL_MCP_PSEUDONYMIZE_COLUMNS); its value cannot be fetched", q.Columns[col])
+	}
+	if input.Offset < 0 || input.Length < 0 {
+		return nil, FetchCellOutput{}, fmt.Errorf("offset and length must not be negative")
+	}
+	encoding := strings.ToLower(strings.TrimSpace(input.Encoding))
+	if encoding == "" {
+		encoding = "auto"
+	}
+	if encoding != "auto" && encoding != "base64" {
+		return nil, FetchCellOutput{}, fmt.Errorf("encoding must be auto or base64")
+	}
+	if q.Database != "" {
+		if err := requireAllowedDatabase(q.Database); err != nil {
+			return nil, FetchCellOutput{}, err
+		}
+	}
+	db, ok := connManager.Pools()[q.Connection]
+	if !ok {
+		return nil, FetchCellOutput{}, fmt.Errorf("connection '%s' for this cell handle is no longer available", q.Connection)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	value, isNull, err := readCell(ctx, db, q, row, col)
+	if err == nil && !prefix.matches(q, col, value, isNull) {
+		err = fmt.Errorf("row %d of the re-run query is no longer the row the handle was issued for; re-run the query, ordered by a unique key, to get a new handle", row)
+	}
+	if err != nil {
+		timer.LogError(err, q.SQL, nil, nil)
+		auditCellFetch(ctx, q, timer, err)
+		return nil, FetchCellOutput{}, err
+	}
+
+	out := FetchCellOutput{Column: q.Columns[col], Row: row, Null: isNull, TotalBytes: len(value), Offset: input.Offset}
+	if input.Offset > len(value) {
+		return nil, FetchCellOutput{}, fmt.Errorf("offset %d is past the end of the %d-byte value", input.Offset, len(value))
+	}
+	length := input.Length
+	if length == 0 {
+		length = defaultFetchCellLength
+	}
+	if maxResultBytes > 0 && length > maxResultBytes {
+		length = maxResultBytes
+	}
+	end := min(input.Offset+length, len(value))
+	chunk := value[input.Offset:end]
+	if encoding == "auto" && utf8.Valid(value) {
+		// Keep text ranges on character boundaries; next_offset resumes there.
+		for end < len(value) && end > input.Offset && !utf8.RuneStart(value[end]) {
+			end--
+		}
+		chunk = value[input.Offset:end]
+	}
+	if encoding == "auto" && utf8.Valid(chunk) {
+		out.Encoding = "text"
+		out.Data = string(chunk)
+	} else {
+		out.Encoding = "base64"
+		out.Data = base64.StdEncoding.EncodeToString(chunk)
+	}
+	out.Length = len(chunk)
+	if end < len(value) {
+		out.HasMore = true
+		out.NextOffset = &end
+	}
+
+	timer.LogSuccess(1, q.SQL, nil, nil)
+	auditCellFetch(ctx, q, timer, nil)
+	return nil, out, nil
+}
+
+// auditCellFetch records a fetch_cell call, which reads data like the query
+// behind its handle.
+func auditCellFetch(ctx context.Context, q cellQuery, timer *QueryTimer, err error) {
+	if auditLogger == nil {
+		return
+	}
+	entry := &AuditEntry{
+		Tool:        "fetch_cell",
+		Database:    q.Database,
+		Query:       loggedSQL(q.SQL, 500),
+		QueryDigest: util.QueryDigest(q.SQL),
+		DurationMs:  timer.ElapsedMs(),
+		Success:     err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.RowCount = 1
+	}
+	auditLogger.LogContext(ctx, entry)
+}
+
+// readCell re-runs q on db and returns the raw bytes of column col in row
+// row. NULL reads as an empty value with isNull set.
+func readCell(ctx context.Context, db *sql.DB, q cellQuery, row, col int) (value []byte, isNull bool, err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	restoreDatabase, err := useDatabase(ctx, db, conn, q.Database)
+	if err != nil {
+		return nil, false, err
+	}
+	defer restoreDatabase()
+
+	restoreTimeZone, err := useTimeZone(ctx, conn, q.TimeZone)
+	if err != nil {
+		return nil, false, err
+	}
+	defer restoreTimeZone()
+
+	restoreSettings, err := useSessionSettings(ctx, conn)
+	if err != nil {
+		return nil, false, err
+	}
+	defer restoreSettings()
+
+	stopWatchdog, err := killQueryOnCancel(ctx, db, conn)
+	if err != nil {
+		return nil, false, err
+	}
+	defer stopWatchdog()
+
+	rows, err := conn.QueryContext(ctx, q.SQL, q.Args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get columns: %w", err)
+	}
+	if len(columns) != len(q.Columns) || columns[col] != q.Columns[col] {
+		return nil, false, fmt.Errorf("the query now returns different columns; re-run it to get a new handle")
+	}
+
+	dest := make([]interface{}, len(columns))
+	for i := range dest {
+		dest[i] = new(sql.RawBytes)
+	}
+	for n := 0; rows.Next(); n++ {
+		if n < row {
+			continue
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, false, fmt.Errorf("failed to scan row: %w", err)
+		}
+		raw := *dest[col].(*sql.RawBytes)
+		return append([]byte(nil), raw...), raw == nil, nil
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("row iteration failed: %w", err)
+	}
+	return nil, false, fmt.Errorf("row %d no longer exists; re-run the query to get a new handle", row)
+}
@@ -0,0 +1,151 @@
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestFetchCellReadsTruncatedValue(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	old := maxResultBytes
+	maxResultBytes = 1500
+	defer func() { maxResultBytes = old }()
+
+	big := strings.Repeat("ab", 1000)
+	mock.ExpectQuery("SELECT id, body FROM docs ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body"}).AddRow(1, big))
+
+	_, res, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, body FROM docs ORDER BY id"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if len(res.CellHandles) != 1 {
+		t.Fatalf("cell_handles = %+v, want one handle", res.CellHandles)
+	}
+	h := res.CellHandles[0]
+	if h.Row != 0 || h.Column != "body" || h.FullBytes != len(big) || h.Handle == "" {
+		t.Fatalf("unexpected handle %+v", h)
+	}
+
+	mock.ExpectQuery("SELECT id, body FROM docs ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body"}).AddRow(1, big))
+	_, out, err := toolFetchCell(context.Background(), &mcp.CallToolRequest{}, FetchCellInput{Handle: h.Handle, Offset: 100, Length: 1000})
+	if err != nil {
+		t.Fatalf("fetch_cell failed: %v", err)
+	}
+	if out.TotalBytes != len(big) || out.Encoding != "text" || out.Data != big[100:1100] {
+		t.Fatalf("unexpected range: total=%d encoding=%s len=%d", out.TotalBytes, out.Encoding, len(out.Data))
+	}
+	if !out.HasMore || out.NextOffset == nil || *out.NextOffset != 1100 {
+		t.Errorf("has_more=%v next_offset=%v, want 1100", out.HasMore, out.NextOffset)
+	}
+
+	// A re-run that puts another row first is detected by the prefix shown.
+	mock.ExpectQuery("SELECT id, body FROM docs ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body"}).AddRow(2, strings.Repeat("ba", 1000)))
+	if _, _, err := toolFetchCell(context.Background(), &mcp.CallToolRequest{}, FetchCellInput{Handle: h.Handle}); err == nil ||
+		!strings.Contains(err.Error(), "no longer the row") {
+		t.Errorf("expected a row mismatch error, got %v", err)
+	}
+
+	// Without ORDER BY the row number cannot find the cell again.
+	mock.ExpectQuery("SELECT id, body FROM docs").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "body"}).AddRow(1, big))
+	_, res, err = toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, body FROM docs"})
+	if err != nil || res.TruncatedCells != 1 || len(res.CellHandles) != 0 || !strings.Contains(res.Warning, "ORDER BY") {
+		t.Errorf("expected no handles for an unordered query, got %+v (%v)", res.CellHandles, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestFetchCellBinaryAndBadHandles(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	q := cellQuery{Connection: "mock", SQL: "SELECT payload FROM blobs", Columns: []string{"payload"}}
+	handle := cellHandles.register(q, 1, 0, "")
+	mock.ExpectQuery("SELECT payload FROM blobs").
+		WillReturnRows(sqlmock.NewRows([]string{"payload"}).AddRow([]byte("skip")).AddRow([]byte{0xff, 0x00, 0x01}))
+	_, out, err := toolFetchCell(context.Background(), &mcp.CallToolRequest{}, FetchCellInput{Handle: handle})
+	if err != nil {
+		t.Fatalf("fetch_cell failed: %v", err)
+	}
+	if out.Encoding != "base64" || out.Data != "/wAB" || out.HasMore {
+		t.Errorf("got %+v, want base64 /wAB", out)
+	}
+
+	for _, bad := range []string{"", "nope", "0000000000000000.0.0"} {
+		if _, _, err := toolFetchCell(context.Background(), &mcp.CallToolRequest{}, FetchCellInput{Handle: bad}); err == nil {
+			t.Errorf("handle %q: expected error", bad)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestFetchCellWithholdsMaskedColumns(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg, oldMax := cfg, maxResultBytes
+	cfg = &config.Config{MaskColumns: []string{"ssn"}}
+	maxResultBytes = 1500
+	defer func() { cfg, maxResultBytes = oldCfg, oldMax }()
+
+	secret := strings.Repeat("123-45-6789 ", 200)
+	mock.ExpectQuery("SELECT id, ssn_history FROM people ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ssn_history"}).AddRow(1, secret))
+	_, res, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, ssn_history FROM people ORDER BY id"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if res.TruncatedCells != 1 || len(res.CellHandles) != 0 || res.Rows[0][1] != "********" {
+		t.Fatalf("expected the masked cell cut without a handle, got %d handles, value %v", len(res.CellHandles), res.Rows[0][1])
+	}
+
+	// A handle issued before the mask was configured is refused too.
+	handle := cellHandles.register(cellQuery{Connection: "mock", SQL: "SELECT ssn_history FROM people", Columns: []string{"ssn_history"}}, 0, 0, "")
+	if _, _, err := toolFetchCell(context.Background(), &mcp.CallToolRequest{}, FetchCellInput{Handle: handle}); err == nil ||
+		!strings.Contains(err.Error(), "masked") {
+		t.Errorf("expected fetch_cell to refuse a masked column, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestFetchCellWithholdsPseudonymizedColumns(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg, oldMax, oldPseudonyms := cfg, maxResultBytes, pseudonyms
+	cfg = &config.Config{PseudonymizeColumns: []string{"email"}}
+	initPseudonymizer(cfg)
+	maxResultBytes = 1500
+	defer func() { cfg, maxResultBytes, pseudonyms = oldCfg, oldMax, oldPseudonyms }()
+
+	long := strings.Repeat("a", 2000) + "@example.com"
+	mock.ExpectQuery("SELECT id, email FROM users ORDER BY id").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(1, long))
+	_, res, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, email FROM users ORDER BY id"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if res.TruncatedCells != 1 || len(res.CellHandles) != 0 || strings.Contains(res.Rows[0][1].(string), "example.com") {
+		t.Fatalf("expected the pseudonymized cell cut without a handle, got %d handles, value %v", len(res.CellHandles), res.Rows[0][1])
+	}
+	handle := cellHandles.register(cellQuery{Connection: "mock", SQL: "SELECT email FROM users", Columns: []string{"email"}}, 0, 0, "")
+	if _, _, err := toolFetchCell(context.Background(), &mcp.CallToolRequest{}, FetchCellInput{Handle: handle}); err == nil {
+		t.Error("expected fetch_cell to refuse a pseudonymized column")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
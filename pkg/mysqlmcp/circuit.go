@@ -0,0 +1,204 @@
+// pkg/mysqlmcp/circuit.go
+package mysqlmcp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/dbretry"
+	"github.com/go-sql-driver/mysql"
+)
+
+// errCircuitOpen is wrapped by CircuitOpenError so HTTP handlers can answer 503.
+var errCircuitOpen = errors.New("circuit open")
+
+// errPingFailed stands in for a failed ping, which ping reports in its output
+// rather than as an error.
+var errPingFailed = errors.New("ping failed")
+
+// CircuitOpenError reports that a connection's circuit breaker is open: the
+// connection failed repeatedly and calls fail fast until the cooldown ends.
+type CircuitOpenError struct {
+	Connection string
+	Failures   int
+	LastError  string
+	RetryIn    time.Duration // until the next probe call is let through
+}
+
+func (e *CircuitOpenError) Error() string {
+	return e.localize(config.LocaleEnglish)
+}
+
+func (e *CircuitOpenError) localize(locale string) string {
+	return tr(locale, "connection %s unavailable: circuit open after %d consecutive failures (last: %s); retry in %s",
+		e.Connection, e.Failures, e.LastError, e.RetryIn.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Unwrap() error { return errCircuitOpen }
+
+// Circuit states reported by list_connections and pool_stats.
+const (
+	circuitClosed   = "closed"
+	circuitOpen     = "open"
+	circuitHalfOpen = "half_open" // cooldown over; one probe call decides
+)
+
+// circuit is the breaker state of one connection.
+type circuit struct {
+	failures  int // consecutive connection failures
+	lastError string
+	openUntil time.Time // zero while closed
+	probing   bool      // a half-open probe call is running
+}
+
+// circuitBreakers holds a breaker per connection name. threshold consecutive
+// connection failures open a circuit for cooldown; the first call after the
+// cooldown is a probe that closes it on success or reopens it on failure.
+type circuitBreakers struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+var breakers *circuitBreakers
+
+// initCircuitBreakers enables the breakers unless CircuitThreshold is 0.
+func initCircuitBreakers(c *config.Config) {
+	if c.CircuitThreshold <= 0 {
+		breakers = nil
+		return
+	}
+	cooldown := c.CircuitCooldown
+	if cooldown <= 0 {
+		cooldown = time.Duration(config.DefaultCircuitCooldownS) * time.Second
+	}
+	breakers = &circuitBreakers{
+		threshold: c.CircuitThreshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		circuits:  map[string]*circuit{},
+	}
+}
+
+// allow admits one call on conn, or fails with a *CircuitOpenError while its
+// circuit is open. The returned done func must be called with the call's error.
+func (b *circuitBreakers) allow(conn string) (func(error), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[conn]
+	if c == nil {
+		c = &circuit{}
+		b.circuits[conn] = c
+	}
+	probe := false
+	if !c.openUntil.IsZero() {
+		now := b.now()
+		if now.Before(c.openUntil) || c.probing {
+			retryIn := c.openUntil.Sub(now)
+			if retryIn < time.Second {
+				retryIn = time.Second
+			}
+			return nil, &CircuitOpenError{Connection: conn, Failures: c.failures, LastError: c.lastError, RetryIn: retryIn}
+		}
+		c.probing, probe = true, true
+	}
+	return func(err error) { b.record(conn, c, probe, err) }, nil
+}
+
+// record updates c with the outcome of one admitted call.
+func (b *circuitBreakers) record(conn string, c *circuit, probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		c.probing = false
+	}
+	switch {
+	case errors.Is(err, errPingFailed) || dbretry.IsConnectionError(err):
+		c.failures++
+		c.lastError = err.Error()
+		if probe || (c.openUntil.IsZero() && c.failures >= b.threshold) {
+			c.openUntil = b.now().Add(b.cooldown)
+			poolLog.Warn("circuit opened", map[string]interface{}{
+				"connection":  conn,
+				"failures":    c.failures,
+				"cooldown_ms": b.cooldown.Milliseconds(),
+				"error":       c.lastError,
+			})
+			notifyConnectionUnhealthy(conn, fmt.Sprintf("circuit opened after %d connection failures: %s", c.failures, c.lastError))
+		}
+	case err == nil || isServerError(err):
+		// The server answered, even if only to reject the SQL.
+		if !c.openUntil.IsZero() {
+			poolLog.Info("circuit closed", map[string]interface{}{"connection": conn})
+		}
+		c.failures, c.lastError, c.openUntil = 0, "", time.Time{}
+	}
+	// Anything else (bad input, a busy server, a canceled call) says nothing
+	// about the connection; a probe that ends that way is simply retried.
+}
+
+// isServerError reports whether err came back from MySQL itself.
+func isServerError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr)
+}
+
+// CircuitState is a connection's breaker state as reported by list_connections
+// and pool_stats.
+type CircuitState struct {
+	State          string `json:"state" jsonschema:"closed, open (calls fail fast) or half_open (the next call probes the connection)"`
+	Failures       int    `json:"failures,omitempty" jsonschema:"consecutive connection failures"`
+	LastError      string `json:"last_error,omitempty" jsonschema:"the most recent connection failure"`
+	RetryInSeconds int    `json:"retry_in_seconds,omitempty" jsonschema:"seconds until an open circuit lets a probe call through"`
+}
+
+// state returns the breaker state of conn, or nil when breakers are disabled.
+func (b *circuitBreakers) state(conn string) *CircuitState {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[conn]
+	if c == nil {
+		return &CircuitState{State: circuitClosed}
+	}
+	st := &CircuitState{State: circuitClosed, Failures: c.failures, LastError: c.lastError}
+	if !c.openUntil.IsZero() {
+		if wait := c.openUntil.Sub(b.now()); wait > 0 {
+			st.State, st.RetryInSeconds = circuitOpen, int(math.Ceil(wait.Seconds()))
+		} else {
+			st.State = circuitHalfOpen
+		}
+	}
+	return st
+}
+
+// admitCircuit checks the breaker of the active connection before a call of
+// tool. Tools that never touch MySQL are always admitted, so connections can
+// still be listed and switched while one is down.
+func admitCircuit(tool string) (func(error), error) {
+	b := breakers
+	if b == nil || connManager == nil || toolQueryWeight(tool) == 0 {
+		return func(error) {}, nil
+	}
+	_, name := connManager.GetActive()
+	return b.allow(name)
+}
+
+// circuitOutcome is the error a call's breaker records: err, or errPingFailed
+// for a ping that could not reach the server.
+func circuitOutcome(out any, err error) error {
+	if p, ok := out.(PingOutput); ok && err == nil && !p.Success {
+		return fmt.Errorf("%w: %s", errPingFailed, strings.TrimPrefix(p.Message, "ping failed: "))
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+// pkg/mysqlmcp/circuit_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/dbretry"
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useCircuitBreakers installs breakers with a settable clock for the test.
+func useCircuitBreakers(t *testing.T, threshold int, cooldown time.Duration) *time.Time {
+	t.Helper()
+	old := breakers
+	t.Cleanup(func() { breakers = old })
+	initCircuitBreakers(&config.Config{CircuitThreshold: threshold, CircuitCooldown: cooldown})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	breakers.now = func() time.Time { return now }
+	return &now
+}
+
+func TestCircuitBreakerLifecycle(t *testing.T) {
+	now := useCircuitBreakers(t, 2, 30*time.Second)
+	b := breakers
+	call := func(err error) error {
+		done, admitErr := b.allow("prod")
+		if admitErr != nil {
+			return admitErr
+		}
+		done(err)
+		return nil
+	}
+
+	// Bad input and canceled calls neither count nor reset failures; an
+	// answer from MySQL, even an error, resets them.
+	_ = call(driver.ErrBadConn)
+	_ = call(errors.New("sql is required"))
+	_ = call(context.Canceled)
+	if st := b.state("prod"); st.State != circuitClosed || st.Failures != 1 {
+		t.Fatalf("expected one failure on a closed circuit, got %+v", st)
+	}
+	_ = call(&mysql.MySQLError{Number: 1064, Message: "syntax error"})
+	if st := b.state("prod"); st.Failures != 0 {
+		t.Fatalf("expected a server answer to reset failures, got %+v", st)
+	}
+
+	_ = call(driver.ErrBadConn)
+
+	_ = call(driver.ErrBadConn)
+	var open *CircuitOpenError
+	if err := call(nil); !errors.As(err, &open) || open.Failures != 2 || open.RetryIn != 30*time.Second {
+		t.Fatalf("expected an open circuit after 2 failures, got %v", err)
+	}
+	if st := b.state("prod"); st.State != circuitOpen || st.RetryInSeconds != 30 || st.LastError == "" {
+		t.Errorf("unexpected open state: %+v", st)
+	}
+	if st := b.state("staging"); st.State != circuitClosed {
+		t.Errorf("other connections should stay closed, got %+v", st)
+	}
+
+	// After the cooldown a single probe is let through; a failed probe reopens.
+	*now = now.Add(31 * time.Second)
+	if st := b.state("prod"); st.State != circuitHalfOpen {
+		t.Fatalf("expected half_open after the cooldown, got %+v", st)
+	}
+	done, err := b.allow("prod")
+	if err != nil {
+		t.Fatalf("expected the probe to be admitted: %v", err)
+	}
+	if err := call(nil); !errors.Is(err, errCircuitOpen) {
+		t.Errorf("expected calls during the probe to fail fast, got %v", err)
+	}
+	done(context.DeadlineExceeded)
+	if st := b.state("prod"); st.State != circuitOpen || st.Failures != 3 {
+		t.Fatalf("expected a reopened circuit, got %+v", st)
+	}
+
+	// A successful probe closes the circuit.
+	*now = now.Add(31 * time.Second)
+	if err := call(nil); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if st := b.state("prod"); st.State != circuitClosed || st.Failures != 0 {
+		t.Errorf("expected a closed circuit after a good probe, got %+v", st)
+	}
+}
+
+func TestDispatchToolCircuitBreaker(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	useCircuitBreakers(t, 1, time.Minute)
+	dbRetryCfg = dbretry.Config{}
+
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	mock.ExpectQuery("SELECT 1").WillReturnError(reset)
+	runQuery := dispatchTool("run_query", toolRunQuery)
+	ctx := context.Background()
+	if _, _, err := runQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1"}); err == nil {
+		t.Fatal("expected the first call to fail")
+	}
+
+	_, _, err := runQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1"})
+	var open *CircuitOpenError
+	if !errors.As(err, &open) || open.Connection != "mock" {
+		t.Fatalf("expected a circuit open error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("the open circuit should not reach MySQL: %v", err)
+	}
+
+	// Connection management keeps working and reports the breaker.
+	_, out, err := dispatchTool("list_connections", toolListConnections)(ctx, &mcp.CallToolRequest{}, ListConnectionsInput{})
+	if err != nil {
+		t.Fatalf("list_connections: %v", err)
+	}
+	if c := out.Connections[0].Circuit; c == nil || c.State != circuitOpen || c.Failures != 1 {
+		t.Errorf("expected an open circuit in list_connections, got %+v", c)
+	}
+
+	_, _, err = runQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1"})
+	rec := httptest.NewRecorder()
+	writeToolError(rec, err)
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "60" {
+		t.Errorf("expected 503 with Retry-After 60, got %d %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
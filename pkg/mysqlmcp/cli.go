@@ -0,0 +1,195 @@
+// pkg/mysqlmcp/cli.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// cliClientName identifies run-tool and list-tools calls to RBAC client
+// mappings and in the audit log.
+const cliClientName = "mysql-mcp-server-cli"
+
+// handleTestConnection pings the named connection (or every connection) and
+// prints its latency and server version. It returns the process exit code.
+func handleTestConnection(name string) int {
+	if err := loadRuntimeConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
+		return 1
+	}
+	var names []string
+	for _, c := range cfg.Connections {
+		if name == "" || c.Name == name {
+			names = append(names, c.Name)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(os.Stderr, "connection '%s' not found\n", name)
+		return 1
+	}
+
+	openConnections()
+	defer connManager.Close()
+	pools := connManager.OpenAll()
+
+	code := 0
+	for _, n := range names {
+		db, ok := pools[n]
+		if !ok {
+			fmt.Printf("%s: FAILED (could not open connection; see log above)\n", n)
+			code = 1
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		start := time.Now()
+		err := db.PingContext(ctx)
+		latency := time.Since(start).Milliseconds()
+		var version string
+		if err == nil {
+			err = db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
+		}
+		cancel()
+		if err != nil {
+			fmt.Printf("%s: FAILED: %v\n", n, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("%s: ok (%d ms, server %s)\n", n, latency, version)
+	}
+	return code
+}
+
+// handleListTools prints the tools the current configuration registers.
+func handleListTools() int {
+	if err := loadRuntimeConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+	session, err := connectCLIClient(ctx, newMCPServer())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	defer session.Close()
+
+	res, err := session.ListTools(ctx, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	sort.Slice(res.Tools, func(i, j int) bool { return res.Tools[i].Name < res.Tools[j].Name })
+	for _, t := range res.Tools {
+		fmt.Printf("%-28s %s\n", t.Name, t.Description)
+	}
+	return 0
+}
+
+// handleRunTool calls one tool through an in-memory MCP session, so input
+// decoding, RBAC, auditing and output shaping match a real client call, and
+// prints the structured result as JSON.
+func handleRunTool(name, input string) int {
+	args, err := parseToolInput(input, os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	if err := loadRuntimeConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
+		return 1
+	}
+	if err := openAuditLog(nil); err != nil {
+		fmt.Fprintf(os.Stderr, "audit log init error: %v\n", err)
+		return 1
+	}
+	defer auditLogger.Close()
+	openConnections()
+	defer connManager.Close()
+	if connManager.GetActiveDB() == nil {
+		fmt.Fprintf(os.Stderr, "config error: no valid MySQL connections available\n")
+		return 1
+	}
+
+	ctx := context.Background()
+	session, err := connectCLIClient(ctx, newMCPServer())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	defer session.Close()
+
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return printToolResult(os.Stdout, os.Stderr, res)
+}
+
+// parseToolInput decodes --input as a JSON object; "-" reads it from stdin
+// and an empty input means no arguments.
+func parseToolInput(input string, stdin io.Reader) (map[string]any, error) {
+	if input == "-" {
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read --input from stdin: %w", err)
+		}
+		input = string(b)
+	}
+	args := map[string]any{}
+	if strings.TrimSpace(input) == "" {
+		return args, nil
+	}
+	if err := json.Unmarshal([]byte(input), &args); err != nil {
+		return nil, fmt.Errorf("--input must be a JSON object: %w", err)
+	}
+	return args, nil
+}
+
+// printToolResult writes the structured content (or text content) of res and
+// returns 1 when the tool reported an error.
+func printToolResult(stdout, stderr io.Writer, res *mcp.CallToolResult) int {
+	var texts []string
+	for _, c := range res.Content {
+		if t, ok := c.(*mcp.TextContent); ok {
+			texts = append(texts, t.Text)
+		}
+	}
+	if res.IsError {
+		fmt.Fprintf(stderr, "tool error: %s\n", strings.Join(texts, "\n"))
+		return 1
+	}
+	if res.StructuredContent != nil {
+		b, err := json.MarshalIndent(res.StructuredContent, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(b))
+		return 0
+	}
+	fmt.Fprintln(stdout, strings.Join(texts, "\n"))
+	return 0
+}
+
+// connectCLIClient connects an in-memory MCP client to server.
+func connectCLIClient(ctx context.Context, server *mcp.Server) (*mcp.ClientSession, error) {
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		return nil, fmt.Errorf("connect server: %w", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: cliClientName, Version: Version}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connect client: %w", err)
+	}
+	return session, nil
+}
@@ -0,0 +1,99 @@
+package mysqlmcp
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseToolInput(t *testing.T) {
+	args, err := parseToolInput(`{"database":"app","limit":5}`, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args["database"] != "app" || args["limit"] != float64(5) {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	args, err = parseToolInput("", nil)
+	if err != nil || len(args) != 0 {
+		t.Errorf("empty input: args=%v err=%v", args, err)
+	}
+
+	args, err = parseToolInput("-", strings.NewReader(`{"sql":"SELECT 1"}`))
+	if err != nil || args["sql"] != "SELECT 1" {
+		t.Errorf("stdin input: args=%v err=%v", args, err)
+	}
+
+	if _, err := parseToolInput(`[1,2]`, nil); err == nil {
+		t.Error("expected error for non-object input")
+	}
+}
+
+func TestPrintToolResult(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := printToolResult(&stdout, &stderr, &mcp.CallToolResult{
+		StructuredContent: map[string]any{"databases": []string{"app"}},
+	})
+	if code != 0 || !strings.Contains(stdout.String(), `"databases"`) {
+		t.Errorf("code=%d stdout=%q", code, stdout.String())
+	}
+
+	stdout.Reset()
+	code = printToolResult(&stdout, &stderr, &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: "boom"}},
+	})
+	if code != 1 || !strings.Contains(stderr.String(), "boom") || stdout.Len() != 0 {
+		t.Errorf("code=%d stdout=%q stderr=%q", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestCLIClientRunsTool(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg := cfg
+	cfg = &config.Config{}
+	defer func() { cfg = oldCfg }()
+
+	ctx := context.Background()
+	session, err := connectCLIClient(ctx, newMCPServer())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer session.Close()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	found := false
+	for _, tool := range tools.Tools {
+		found = found || tool.Name == "list_databases"
+	}
+	if !found {
+		t.Fatal("list_databases not registered")
+	}
+
+	mock.ExpectQuery("SELECT SCHEMA_NAME FROM information_schema.SCHEMATA").
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("testdb"))
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_databases", Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatalf("call tool: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := printToolResult(&stdout, &stderr, res); code != 0 {
+		t.Fatalf("code=%d stderr=%q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "testdb") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+// pkg/mysqlmcp/client_identity.go
+package mysqlmcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clientIdentity is who made a tool call, as recorded in audit entries.
+type clientIdentity struct {
+	APIKey        string // HTTP API key, masked to its last four characters
+	RemoteIP      string // HTTP client address
+	Client        string // MCP client name from the initialize handshake
+	ClientVersion string // MCP client version from the initialize handshake
+
+	apiKeyHash string // hash prefix telling apart API keys with the same masked form
+}
+
+// key names the caller for per-caller state such as quotas and query jobs:
+// the API key, else the MCP client, else the remote address. API keys are
+// told apart by a hash, since several keys can share a masked form.
+func (id clientIdentity) key() string {
+	switch {
+	case id.APIKey != "":
+		return fmt.Sprintf("api_key %s (%s)", id.APIKey, id.apiKeyHash)
+	case id.Client != "":
+		return "client " + id.Client
+	case id.RemoteIP != "":
+		return "ip " + id.RemoteIP
+	default:
+		return "anonymous"
+	}
+}
+
+type clientIdentityKey struct{}
+
+func withClientIdentity(ctx context.Context, id clientIdentity) context.Context {
+	return context.WithValue(ctx, clientIdentityKey{}, id)
+}
+
+// clientIdentityFrom returns the identity in ctx, or a zero identity.
+func clientIdentityFrom(ctx context.Context) clientIdentity {
+	if ctx == nil {
+		return clientIdentity{}
+	}
+	id, _ := ctx.Value(clientIdentityKey{}).(clientIdentity)
+	return id
+}
+
+// withHTTPClientIdentity records the remote IP and the masked API key of an
+// HTTP request for the audit entries of the tool calls it makes.
+func withHTTPClientIdentity(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := clientIdentity{RemoteIP: api.ClientIP(r)}
+		if key := apiKeyFromRequest(r); key != "" {
+			id.APIKey = config.MaskAPIKey(key)
+			sum := sha256.Sum256([]byte(key))
+			id.apiKeyHash = hex.EncodeToString(sum[:4])
+		}
+		next(w, r.WithContext(withClientIdentity(r.Context(), id)))
+	}
+}
+
+// withMCPClientIdentity adds the client name and version the MCP session sent
+// at initialization to the identity in ctx.
+func withMCPClientIdentity(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if req == nil || req.Session == nil {
+		return ctx
+	}
+	params := req.Session.InitializeParams()
+	if params == nil || params.ClientInfo == nil {
+		return ctx
+	}
+	id := clientIdentityFrom(ctx)
+	id.Client, id.ClientVersion = params.ClientInfo.Name, params.ClientInfo.Version
+	return withClientIdentity(ctx, id)
+}
@@ -0,0 +1,83 @@
+// pkg/mysqlmcp/client_identity_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHTTPClientIdentity(t *testing.T) {
+	var got clientIdentity
+	handler := withHTTPClientIdentity(func(w http.ResponseWriter, r *http.Request) {
+		got = clientIdentityFrom(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/query", nil)
+	req.RemoteAddr = "192.0.2.10:52311"
+	req.Header.Set("Authorization", "Bearer s3cr3t-key-9f3a")
+	handler(httptest.NewRecorder(), req)
+
+	if got.APIKey != "***9f3a" || got.RemoteIP != "192.0.2.10" {
+		t.Errorf("unexpected identity: %+v", got)
+	}
+	if id := clientIdentityFrom(context.Background()); id != (clientIdentity{}) {
+		t.Errorf("expected a zero identity outside a request, got %+v", id)
+	}
+}
+
+func TestAuditEntryRecordsMCPClient(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg := cfg
+	cfg = &config.Config{}
+	defer func() { cfg = oldCfg }()
+
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	oldAudit := auditLogger
+	var err error
+	auditLogger, err = NewAuditLogger(logPath)
+	if err != nil {
+		t.Fatalf("NewAuditLogger failed: %v", err)
+	}
+	defer func() {
+		auditLogger.Close()
+		auditLogger = oldAudit
+	}()
+
+	ctx := context.Background()
+	session, err := connectCLIClient(ctx, newMCPServer())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer session.Close()
+
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "run_query", Arguments: map[string]any{"sql": "SELECT 1"}})
+	if err != nil || res.IsError {
+		t.Fatalf("run_query failed: %v %+v", err, res)
+	}
+	auditLogger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	var entry AuditEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if entry.Client != cliClientName || entry.ClientVersion != Version || entry.Source != requestSourceMCP {
+		t.Errorf("unexpected client identity: %+v", entry)
+	}
+	if entry.RemoteIP != "" || entry.APIKey != "" {
+		t.Errorf("expected no HTTP identity on an MCP call: %+v", entry)
+	}
+}
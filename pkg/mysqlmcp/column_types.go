@@ -0,0 +1,86 @@
+// pkg/mysqlmcp/column_types.go
+package mysqlmcp
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+)
+
+// Result values are sent as JSON strings and numbers, which lose the MySQL
+// type: a DECIMAL(12,2) and a VARCHAR both arrive as strings. column_types
+// carries what the driver reports for each returned column so clients can
+// format values and build UIs without a describe_table round trip.
+
+// Column kinds of ColumnType.Kind.
+const (
+	columnKindInteger  = "integer"
+	columnKindDecimal  = "decimal"
+	columnKindFloat    = "float"
+	columnKindBit      = "bit"
+	columnKindTemporal = "temporal"
+	columnKindJSON     = "json"
+	columnKindSpatial  = "spatial"
+	columnKindBinary   = "binary"
+	columnKindString   = "string"
+)
+
+// columnKind groups a driver type name such as UNSIGNED BIGINT or VARCHAR.
+func columnKind(typeName string) string {
+	name := strings.TrimPrefix(strings.ToUpper(typeName), "UNSIGNED ")
+	switch {
+	case name == "":
+		return ""
+	case isBinaryColumnType(name):
+		return columnKindBinary
+	case isGeometryColumnType(name):
+		return columnKindSpatial
+	}
+	switch name {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT", "YEAR":
+		return columnKindInteger
+	case "DECIMAL":
+		return columnKindDecimal
+	case "FLOAT", "DOUBLE":
+		return columnKindFloat
+	case "BIT":
+		return columnKindBit
+	case "DATE", "DATETIME", "TIMESTAMP", "TIME":
+		return columnKindTemporal
+	case "JSON":
+		return columnKindJSON
+	}
+	return columnKindString
+}
+
+// columnTypes describes the returned columns of a result: types are the
+// column types of the query and renderer maps each returned column to one of
+// them, so columns dropped by binary_output=skip are left out.
+func columnTypes(types []*sql.ColumnType, renderer *cellRenderer, returned int) []ColumnType {
+	out := make([]ColumnType, 0, returned)
+	for i := 0; i < returned; i++ {
+		src := renderer.sourceColumn(i)
+		if src >= len(types) {
+			return nil
+		}
+		t := types[src]
+		ct := ColumnType{Name: t.Name(), Type: t.DatabaseTypeName()}
+		ct.Kind = columnKind(ct.Type)
+		if nullable, ok := t.Nullable(); ok {
+			ct.Nullable = &nullable
+		}
+		if ct.Kind == columnKindBinary {
+			ct.Charset = "binary"
+		}
+		if precision, scale, ok := t.DecimalSize(); ok {
+			if precision != math.MaxInt64 {
+				ct.Precision = &precision
+			}
+			if scale != math.MaxInt64 {
+				ct.Scale = &scale
+			}
+		}
+		out = append(out, ct)
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+// pkg/mysqlmcp/column_types_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestColumnKind(t *testing.T) {
+	tests := map[string]string{
+		"UNSIGNED BIGINT": columnKindInteger,
+		"DECIMAL":         columnKindDecimal,
+		"double":          columnKindFloat,
+		"TIMESTAMP":       columnKindTemporal,
+		"JSON":            columnKindJSON,
+		"VARBINARY":       columnKindBinary,
+		"POINT":           columnKindSpatial,
+		"VARCHAR":         columnKindString,
+		"ENUM":            columnKindString,
+		"":                "",
+	}
+	for name, want := range tests {
+		if got := columnKind(name); got != want {
+			t.Errorf("columnKind(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRunQueryColumnTypes(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT o.id, o.total, o.note, o.receipt FROM shop.orders o JOIN customers c").
+		WillReturnRows(sqlmock.NewRowsWithColumnDefinition(
+			sqlmock.NewColumn("id").OfType("UNSIGNED BIGINT", int64(0)).Nullable(false),
+			sqlmock.NewColumn("total").OfType("DECIMAL", "").Nullable(true).WithPrecisionAndScale(12, 2),
+			sqlmock.NewColumn("note").OfType("VARCHAR", "").Nullable(true),
+			sqlmock.NewColumn("receipt").OfType("BLOB", []byte(nil)),
+		).AddRow(int64(1), "19.90", "gift", []byte{0x01}))
+
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{
+		SQL:          "SELECT o.id, o.total, o.note, o.receipt FROM shop.orders o JOIN customers c ON c.id = o.customer_id",
+		BinaryOutput: config.BinaryOutputSkip,
+	})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if len(out.ColumnTypes) != 3 {
+		t.Fatalf("column_types = %+v, want the 3 returned columns", out.ColumnTypes)
+	}
+	if c := out.ColumnTypes[0]; c.Name != "id" || c.Kind != columnKindInteger || c.Nullable == nil || *c.Nullable {
+		t.Errorf("unexpected id: %+v", c)
+	}
+	if c := out.ColumnTypes[1]; c.Kind != columnKindDecimal || c.Precision == nil || *c.Precision != 12 || c.Scale == nil || *c.Scale != 2 {
+		t.Errorf("unexpected total: %+v", c)
+	}
+	if c := out.ColumnTypes[2]; c.Name != "note" || c.Type != "VARCHAR" || c.Precision != nil {
+		t.Errorf("unexpected note: %+v", c)
+	}
+	if len(out.SourceTables) != 2 || out.SourceTables[0] != "customers" || out.SourceTables[1] != "shop.orders" {
+		t.Errorf("source_tables = %v", out.SourceTables)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
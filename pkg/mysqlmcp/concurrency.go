@@ -0,0 +1,318 @@
+// pkg/mysqlmcp/concurrency.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// errServerBusy is wrapped by acquireQuerySlot so HTTP handlers can answer 503.
+var errServerBusy = errors.New("server busy")
+
+// ServerBusyError reports that a concurrency limit is saturated. Callers
+// should retry shortly rather than treat it as a query failure.
+type ServerBusyError struct {
+	Scope  string // "server" or "connection <name>"
+	Limit  int64
+	Queued int           // calls already waiting when the queue was full
+	Waited time.Duration // time spent queued before giving up
+}
+
+func (e *ServerBusyError) Error() string {
+	return e.localize(config.LocaleEnglish)
+}
+
+func (e *ServerBusyError) localize(locale string) string {
+	switch {
+	case e.Waited > 0:
+		return tr(locale, "server busy: no query slot on %s (limit %d) within %s; retry shortly", e.Scope, e.Limit, e.Waited)
+	case e.Queued > 0:
+		return tr(locale, "server busy: %s is at its limit of %d concurrent queries and %d calls are queued; retry shortly", e.Scope, e.Limit, e.Queued)
+	}
+	return tr(locale, "server busy: %s already runs its limit of %d concurrent queries; retry shortly", e.Scope, e.Limit)
+}
+
+func (e *ServerBusyError) Unwrap() error { return errServerBusy }
+
+// weightedSemaphore is a non-blocking counting semaphore where each holder
+// takes a weight out of a fixed capacity.
+type weightedSemaphore struct {
+	mu    sync.Mutex
+	scope string
+	size  int64
+	cur   int64
+}
+
+func newWeightedSemaphore(scope string, size int64) *weightedSemaphore {
+	return &weightedSemaphore{scope: scope, size: size}
+}
+
+// fits reports whether n units (clamped to the capacity) are available.
+func (s *weightedSemaphore) fits(n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur+min(n, s.size) <= s.size
+}
+
+// tryAcquire takes n units when available. A weight above the capacity is
+// clamped so heavy tools can still run alone.
+func (s *weightedSemaphore) tryAcquire(n int64) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > s.size {
+		n = s.size
+	}
+	if s.cur+n > s.size {
+		return 0, false
+	}
+	s.cur += n
+	return n, true
+}
+
+func (s *weightedSemaphore) release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cur -= n
+	if s.cur < 0 {
+		s.cur = 0
+	}
+}
+
+// Queue priorities: schema lookups and other lightweight calls are granted
+// before data queries, so one slow analytical query cannot starve them.
+const (
+	priorityLow  = 0
+	priorityHigh = 1
+)
+
+// queryLimiter holds the global and per-connection semaphores (nil or absent
+// means no limit) and, when queueDepth > 0, the calls waiting for capacity.
+type queryLimiter struct {
+	mu           sync.Mutex
+	global       *weightedSemaphore
+	perConn      map[string]*weightedSemaphore
+	queueDepth   int
+	queueTimeout time.Duration
+	waiters      []*queryWaiter
+	seq          uint64
+}
+
+// queryWaiter is one call holding or waiting for capacity on sems.
+type queryWaiter struct {
+	priority int
+	seq      uint64
+	weight   int64
+	sems     []*weightedSemaphore
+	taken    []int64
+	granted  bool
+	ready    chan struct{}
+}
+
+var limiter *queryLimiter
+
+// initConcurrencyLimits builds the semaphores from the global
+// MaxConcurrentQueries and each connection's max_concurrent_queries, and the
+// wait queue from QueryQueueDepth and QueryQueueTimeout.
+func initConcurrencyLimits(c *config.Config) {
+	l := &queryLimiter{
+		perConn:      map[string]*weightedSemaphore{},
+		queueDepth:   c.QueryQueueDepth,
+		queueTimeout: c.QueryQueueTimeout,
+	}
+	if c.MaxConcurrentQueries > 0 {
+		l.global = newWeightedSemaphore("server", int64(c.MaxConcurrentQueries))
+	}
+	for _, conn := range c.Connections {
+		if conn.MaxConcurrentQueries > 0 {
+			l.perConn[conn.Name] = newWeightedSemaphore("connection "+conn.Name, int64(conn.MaxConcurrentQueries))
+		}
+	}
+	if l.queueTimeout <= 0 {
+		l.queueTimeout = time.Duration(config.DefaultQueryQueueTimeoutS) * time.Second
+	}
+	if l.global == nil && len(l.perConn) == 0 {
+		l = nil
+	}
+	limiter = l
+}
+
+// toolQueryWeight is how many concurrency units a tool call takes. Tools that
+// never touch MySQL (or, like kill_query, must stay usable when the server is
+// saturated) take none; tools that issue many or expensive queries take more.
+func toolQueryWeight(tool string) int64 {
+	switch tool {
+	case "list_connections", "use_connection", "pool_stats", "usage_stats", "list_saved_queries", "list_reports",
+		"normalize_query", "explain_validation", "read_audit_log", "metrics_history", "kill_query", "schema_changes":
+		return 0
+	case "run_report", "schema_diff", "generate_data_dictionary", "profile_column", "pii_scan", "heatwave_ml_predict", "health_report", "search_schema":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// toolPriority ranks a queued call; data queries and heavy scans wait behind
+// introspection.
+func toolPriority(tool string) int {
+	switch tool {
+	case "run_query", "run_cross_database_query", "run_saved_query", "run_report", "fetch_cell", "vector_search", "vector_insert", "vector_delete", "fulltext_search", "schema_diff",
+		"generate_data_dictionary", "profile_column", "pii_scan", "heatwave_ml_predict", "health_report", "search_schema", "optimizer_trace":
+		return priorityLow
+	default:
+		return priorityHigh
+	}
+}
+
+// acquireQuerySlot reserves capacity for one call of tool on the active
+// connection. When a limit is saturated the call waits in the priority queue
+// (if enabled and not full) for up to the queue timeout, and otherwise fails
+// with a *ServerBusyError. The returned release func must be called when the
+// call ends.
+func acquireQuerySlot(ctx context.Context, tool string) (func(), error) {
+	l := limiter
+	weight := toolQueryWeight(tool)
+	if l == nil || weight == 0 {
+		return func() {}, nil
+	}
+	w := &queryWaiter{priority: toolPriority(tool), weight: weight, ready: make(chan struct{})}
+	if l.global != nil {
+		w.sems = append(w.sems, l.global)
+	}
+	if connManager != nil {
+		_, name := connManager.GetActive()
+		if sem := l.perConn[name]; sem != nil {
+			w.sems = append(w.sems, sem)
+		}
+	}
+	if len(w.sems) == 0 {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	l.seq++
+	w.seq = l.seq
+	l.waiters = append(l.waiters, w)
+	l.dispatchLocked()
+	if w.granted {
+		l.mu.Unlock()
+		return func() { l.release(w) }, nil
+	}
+	if len(l.waiters) > l.queueDepth {
+		l.removeLocked(w)
+		busy := l.busyErrorLocked(w)
+		if l.queueDepth > 0 {
+			busy.Queued = len(l.waiters)
+		}
+		l.mu.Unlock()
+		return nil, busy
+	}
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+	select {
+	case <-w.ready:
+		return func() { l.release(w) }, nil
+	case <-timer.C:
+		return l.abandon(w, func() error {
+			busy := l.busyErrorLocked(w)
+			busy.Waited = l.queueTimeout
+			return busy
+		})
+	case <-ctx.Done():
+		return l.abandon(w, ctx.Err)
+	}
+}
+
+// dispatchLocked grants capacity to queued calls in priority order, then
+// arrival order. A call that does not fit is skipped so calls for other
+// connections are not blocked behind it.
+func (l *queryLimiter) dispatchLocked() {
+	sort.SliceStable(l.waiters, func(i, j int) bool {
+		if l.waiters[i].priority != l.waiters[j].priority {
+			return l.waiters[i].priority > l.waiters[j].priority
+		}
+		return l.waiters[i].seq < l.waiters[j].seq
+	})
+	remaining := l.waiters[:0]
+	for _, w := range l.waiters {
+		if !l.tryGrantLocked(w) {
+			remaining = append(remaining, w)
+		}
+	}
+	for i := len(remaining); i < len(l.waiters); i++ {
+		l.waiters[i] = nil
+	}
+	l.waiters = remaining
+}
+
+func (l *queryLimiter) tryGrantLocked(w *queryWaiter) bool {
+	for _, s := range w.sems {
+		if !s.fits(w.weight) {
+			return false
+		}
+	}
+	w.taken = make([]int64, len(w.sems))
+	for i, s := range w.sems {
+		w.taken[i], _ = s.tryAcquire(w.weight)
+	}
+	w.granted = true
+	close(w.ready)
+	return true
+}
+
+func (l *queryLimiter) removeLocked(w *queryWaiter) {
+	for i, q := range l.waiters {
+		if q == w {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// busyErrorLocked describes the first of w's limits that is saturated.
+func (l *queryLimiter) busyErrorLocked(w *queryWaiter) *ServerBusyError {
+	for _, s := range w.sems {
+		if !s.fits(w.weight) {
+			return &ServerBusyError{Scope: s.scope, Limit: s.size}
+		}
+	}
+	return &ServerBusyError{Scope: w.sems[0].scope, Limit: w.sems[0].size}
+}
+
+// abandon takes w out of the queue after a timeout or cancellation. If w was
+// granted in the meantime the call proceeds instead.
+func (l *queryLimiter) abandon(w *queryWaiter, reason func() error) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w.granted {
+		return func() { l.release(w) }, nil
+	}
+	l.removeLocked(w)
+	return nil, reason()
+}
+
+func (l *queryLimiter) release(w *queryWaiter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, s := range w.sems {
+		s.release(w.taken[i])
+	}
+	l.dispatchLocked()
+}
+
+// queuedCalls returns how many calls are waiting for capacity.
+func queuedCalls() int {
+	l := limiter
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.waiters)
+}
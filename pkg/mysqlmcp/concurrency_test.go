@@ -0,0 +1,178 @@
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWeightedSemaphore(t *testing.T) {
+	s := newWeightedSemaphore("test", 3)
+	if n, ok := s.tryAcquire(2); !ok || n != 2 {
+		t.Fatalf("tryAcquire(2) = %d, %v", n, ok)
+	}
+	if _, ok := s.tryAcquire(2); ok {
+		t.Fatal("expected saturation with 2 of 3 units held")
+	}
+	s.release(2)
+	// Weights above the capacity are clamped so the call can run alone.
+	if n, ok := s.tryAcquire(5); !ok || n != 3 {
+		t.Fatalf("tryAcquire(5) = %d, %v; want clamped 3", n, ok)
+	}
+}
+
+func TestAcquireQuerySlotLimits(t *testing.T) {
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+	t.Cleanup(func() { limiter = nil })
+	ctx := context.Background()
+
+	initConcurrencyLimits(&config.Config{MaxConcurrentQueries: 2, Connections: []config.ConnectionConfig{{Name: "mock", MaxConcurrentQueries: 1}}})
+
+	release, err := acquireQuerySlot(ctx, "run_query")
+	if err != nil {
+		t.Fatalf("first slot: %v", err)
+	}
+	_, err = acquireQuerySlot(ctx, "list_tables")
+	var busy *ServerBusyError
+	if !errors.As(err, &busy) || busy.Scope != "connection mock" || !errors.Is(err, errServerBusy) {
+		t.Fatalf("expected per-connection busy error, got %v", err)
+	}
+	// The failed attempt must not leak its global unit.
+	if limiter.global.cur != 1 {
+		t.Errorf("global units held = %d, want 1", limiter.global.cur)
+	}
+	// Tools that never query MySQL are not limited.
+	if r, err := acquireQuerySlot(ctx, "kill_query"); err != nil {
+		t.Errorf("kill_query should bypass limits: %v", err)
+	} else {
+		r()
+	}
+	release()
+
+	initConcurrencyLimits(&config.Config{MaxConcurrentQueries: 1})
+	release, err = acquireQuerySlot(ctx, "ping")
+	if err != nil {
+		t.Fatalf("slot: %v", err)
+	}
+	if _, err := acquireQuerySlot(ctx, "ping"); !errors.As(err, &busy) || busy.Scope != "server" {
+		t.Errorf("expected server busy error, got %v", err)
+	}
+	release()
+	if r, err := acquireQuerySlot(ctx, "ping"); err != nil {
+		t.Errorf("slot after release: %v", err)
+	} else {
+		r()
+	}
+}
+
+func TestDispatchToolServerBusy(t *testing.T) {
+	t.Cleanup(func() { limiter = nil })
+	ctx := context.Background()
+	initConcurrencyLimits(&config.Config{MaxConcurrentQueries: 1})
+	hold, err := acquireQuerySlot(ctx, "ping")
+	if err != nil {
+		t.Fatalf("slot: %v", err)
+	}
+	defer hold()
+
+	called := false
+	h := dispatchTool("ping", func(ctx context.Context, req *mcp.CallToolRequest, in PingInput) (*mcp.CallToolResult, PingOutput, error) {
+		called = true
+		return nil, PingOutput{}, nil
+	})
+	_, _, err = h(ctx, nil, PingInput{})
+	if !errors.Is(err, errServerBusy) || called {
+		t.Fatalf("expected busy error without calling the handler, got err=%v called=%v", err, called)
+	}
+
+	w := httptest.NewRecorder()
+	writeToolError(w, err)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 503 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestQueryQueuePrioritizesLightweightTools(t *testing.T) {
+	t.Cleanup(func() { limiter = nil })
+	ctx := context.Background()
+	initConcurrencyLimits(&config.Config{MaxConcurrentQueries: 1, QueryQueueDepth: 4, QueryQueueTimeout: 5 * time.Second})
+
+	hold, err := acquireQuerySlot(ctx, "run_query")
+	if err != nil {
+		t.Fatalf("slot: %v", err)
+	}
+
+	order := make(chan string, 2)
+	wait := func(tool string) {
+		release, err := acquireQuerySlot(ctx, tool)
+		if err != nil {
+			t.Errorf("%s: %v", tool, err)
+			return
+		}
+		order <- tool
+		release()
+	}
+	// The heavy query queues first; the schema lookup must still go first.
+	go wait("run_query")
+	waitForQueued(t, 1)
+	go wait("list_tables")
+	waitForQueued(t, 2)
+
+	hold()
+	if first := <-order; first != "list_tables" {
+		t.Errorf("first granted = %s, want list_tables", first)
+	}
+	if second := <-order; second != "run_query" {
+		t.Errorf("second granted = %s, want run_query", second)
+	}
+}
+
+func TestQueryQueueDepthAndTimeout(t *testing.T) {
+	t.Cleanup(func() { limiter = nil })
+	ctx := context.Background()
+	initConcurrencyLimits(&config.Config{MaxConcurrentQueries: 1, QueryQueueDepth: 1, QueryQueueTimeout: 50 * time.Millisecond})
+
+	hold, err := acquireQuerySlot(ctx, "ping")
+	if err != nil {
+		t.Fatalf("slot: %v", err)
+	}
+	defer hold()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := acquireQuerySlot(ctx, "ping")
+		done <- err
+	}()
+	waitForQueued(t, 1)
+
+	// The queue holds one call, so a second waiter is rejected at once.
+	var busy *ServerBusyError
+	if _, err := acquireQuerySlot(ctx, "ping"); !errors.As(err, &busy) || busy.Queued != 1 {
+		t.Errorf("expected queue-full busy error, got %v", err)
+	}
+	// The queued call gives up after the queue timeout.
+	if err := <-done; !errors.As(err, &busy) || busy.Waited != 50*time.Millisecond {
+		t.Errorf("expected timeout busy error, got %v", err)
+	}
+	if n := queuedCalls(); n != 0 {
+		t.Errorf("queued calls = %d after timeout, want 0", n)
+	}
+}
+
+func waitForQueued(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for queuedCalls() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d queued calls", n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
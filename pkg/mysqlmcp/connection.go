@@ -0,0 +1,638 @@
+// pkg/mysqlmcp/connection.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/demo"
+	"github.com/askdba/mysql-mcp-server/internal/sshtunnel"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/go-sql-driver/mysql"
+)
+
+// ServerType represents the type of the database server.
+type ServerType string
+
+const (
+	ServerTypeMySQL   ServerType = "mysql"
+	ServerTypeMariaDB ServerType = "mariadb"
+	ServerTypeUnknown ServerType = "unknown"
+)
+
+// ===== Multi-DSN Connection Manager =====
+
+// ConnectionManager manages multiple MySQL connections.
+type ConnectionManager struct {
+	connections   map[string]*sql.DB
+	configs       map[string]config.ConnectionConfig
+	serverTypes   map[string]ServerType
+	capabilities  map[string]*ConnectionCapabilities // probed when the pool is first used
+	activeConn    string
+	tunnelClosers map[string]func()         // per-connection SSH tunnel close functions
+	groups        map[string]*replicaGroup  // replica groups, keyed by primary name
+	pending       map[string]*config.Config // connect_on_demand connections not opened yet, with their pool settings
+	borrowed      map[string]bool           // pools supplied by a DBProvider; their owner closes them
+	mu            sync.RWMutex
+}
+
+// NewConnectionManager creates a new connection manager.
+func NewConnectionManager() *ConnectionManager {
+	return &ConnectionManager{
+		connections:   make(map[string]*sql.DB),
+		configs:       make(map[string]config.ConnectionConfig),
+		serverTypes:   make(map[string]ServerType),
+		capabilities:  make(map[string]*ConnectionCapabilities),
+		tunnelClosers: make(map[string]func()),
+		groups:        make(map[string]*replicaGroup),
+		pending:       make(map[string]*config.Config),
+		borrowed:      make(map[string]bool),
+	}
+}
+
+// applyDefaultIOTimeouts ensures the MySQL driver read/write deadlines are set when the DSN
+// omits them. Without this, a query can block indefinitely at the TCP layer if context
+// cancellation does not unblock the driver (metadata locks, proxy stalls, etc.).
+func applyDefaultIOTimeouts(dsn string, queryTimeout time.Duration) (string, error) {
+	mysqlCfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	deadline := queryTimeout
+	if deadline <= 0 {
+		deadline = time.Duration(config.DefaultQueryTimeoutSecs) * time.Second
+	}
+	// Small margin after the logical query timeout for the server to flush result packets.
+	const margin = 2 * time.Second
+	if mysqlCfg.ReadTimeout == 0 {
+		mysqlCfg.ReadTimeout = deadline + margin
+	}
+	if mysqlCfg.WriteTimeout == 0 {
+		mysqlCfg.WriteTimeout = deadline + margin
+	}
+	return mysqlCfg.FormatDSN(), nil
+}
+
+// applyStrictReadOnlyDSN sets session parameter transaction_read_only=ON so each new
+// physical connection executes SET via the driver (see go-sql-driver/mysql handleParams).
+// Any existing DSN value for this param is replaced so strict mode cannot be bypassed.
+func applyStrictReadOnlyDSN(dsn string, strict bool) (string, error) {
+	if !strict {
+		return dsn, nil
+	}
+	mysqlCfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	if mysqlCfg.Params == nil {
+		mysqlCfg.Params = make(map[string]string)
+	}
+	// Always force ON so a DSN cannot override strict mode with OFF (or other values).
+	mysqlCfg.Params["transaction_read_only"] = "ON"
+	return mysqlCfg.FormatDSN(), nil
+}
+
+// AddConnectionWithPoolConfig adds a new connection with pool configuration.
+// If a connection with the same name already exists, it, its replicas and
+// their SSH tunnels (if any) are closed and replaced. Replicas that cannot be
+// opened are logged and left out of the group.
+func (cm *ConnectionManager) AddConnectionWithPoolConfig(connCfg config.ConnectionConfig, cfg *config.Config) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	// If replacing an existing connection, close it and its tunnel first to avoid leaks
+	cm.removeLocked(connCfg.Name)
+
+	if err := cm.addLocked(connCfg, cfg); err != nil {
+		return err
+	}
+	if len(connCfg.Replicas) > 0 {
+		cm.addReplicasLocked(connCfg, cfg)
+	}
+
+	// Set as active if it's the first connection
+	if cm.activeConn == "" {
+		cm.activeConn = connCfg.Name
+	}
+
+	return nil
+}
+
+// removeLocked closes the named connection, its replicas and their SSH
+// tunnels. Callers must hold cm.mu.
+func (cm *ConnectionManager) removeLocked(name string) {
+	if existing, ok := cm.connections[name]; ok {
+		preparedStmts.forget(existing)
+		lowerCaseTableNames.Delete(existing)
+		if !cm.borrowed[name] {
+			existing.Close()
+		}
+	}
+	delete(cm.connections, name)
+	delete(cm.borrowed, name)
+	delete(cm.pending, name)
+	delete(cm.configs, name)
+	delete(cm.serverTypes, name)
+	delete(cm.capabilities, name)
+	if closeTunnel := cm.tunnelClosers[name]; closeTunnel != nil {
+		closeTunnel()
+		delete(cm.tunnelClosers, name)
+	}
+	if g := cm.groups[name]; g != nil {
+		delete(cm.groups, name)
+		for _, member := range g.members {
+			cm.removeLocked(member)
+		}
+	}
+	if cm.activeConn == name {
+		cm.activeConn = ""
+	}
+}
+
+// addBorrowed registers a pool opened by the caller. The manager uses it like
+// its own pools but leaves closing it to the caller.
+func (cm *ConnectionManager) addBorrowed(connCfg config.ConnectionConfig, db *sql.DB) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.removeLocked(connCfg.Name)
+	cm.connections[connCfg.Name] = db
+	cm.configs[connCfg.Name] = connCfg
+	cm.borrowed[connCfg.Name] = true
+	if cm.activeConn == "" {
+		cm.activeConn = connCfg.Name
+	}
+}
+
+// addLocked opens, configures and pings the pool of connCfg and registers it.
+// A connect_on_demand connection is only registered; its pool is opened by
+// openPendingLocked on first use. Callers must hold cm.mu.
+func (cm *ConnectionManager) addLocked(connCfg config.ConnectionConfig, cfg *config.Config) error {
+	if connCfg.ConnectOnDemand && connCfg.DSN != config.DemoDSN {
+		if _, err := mysql.ParseDSN(connCfg.DSN); err != nil {
+			return fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
+		}
+		cm.configs[connCfg.Name] = connCfg
+		cm.pending[connCfg.Name] = cfg
+		return nil
+	}
+
+	conn, err := cm.openPoolLocked(connCfg, cfg)
+	if err != nil {
+		return err
+	}
+
+	pingTimeout := cfg.PingTimeout
+	if pingTimeout <= 0 {
+		pingTimeout = time.Duration(config.DefaultPingTimeoutSecs) * time.Second
+	}
+
+	// Test connection with configurable timeout
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		if closer := cm.tunnelClosers[connCfg.Name]; closer != nil {
+			closer()
+			delete(cm.tunnelClosers, connCfg.Name)
+		}
+		return fmt.Errorf("failed to ping connection %s: %w", connCfg.Name, err)
+	}
+
+	cm.connections[connCfg.Name] = conn
+	cm.configs[connCfg.Name] = connCfg
+
+	// Detect server type with a dedicated context to avoid sharing timeout with PingContext
+	ctxDetect, cancelDetect := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancelDetect()
+	st := cm.detectServerType(ctxDetect, conn)
+	cm.serverTypes[connCfg.Name] = st
+
+	// Probe capabilities with their own timeout; a slow probe leaves them unknown
+	ctxProbe, cancelProbe := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancelProbe()
+	cm.capabilities[connCfg.Name] = probeCapabilities(ctxProbe, conn, st)
+
+	return nil
+}
+
+// openPoolLocked opens the pool of connCfg and applies the pool settings of
+// cfg, without connecting. Callers must hold cm.mu.
+func (cm *ConnectionManager) openPoolLocked(connCfg config.ConnectionConfig, cfg *config.Config) (*sql.DB, error) {
+	var conn *sql.DB
+	var err error
+	if connCfg.DSN == config.DemoDSN {
+		// Demo mode: the sample schema is served in-process, so there is no
+		// DSN to rewrite and nothing to tunnel.
+		conn, err = demo.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open connection %s: %w", connCfg.Name, err)
+		}
+	} else if conn, err = cm.openMySQL(connCfg, cfg); err != nil {
+		return nil, err
+	}
+
+	// Apply pool settings with sensible defaults (defensive against zero values)
+	maxOpen := cfg.MaxOpenConns
+	if maxOpen <= 0 {
+		maxOpen = config.DefaultMaxOpenConns
+	}
+	maxIdle := cfg.MaxIdleConns
+	if maxIdle <= 0 {
+		maxIdle = config.DefaultMaxIdleConns
+	}
+	lifetime := cfg.ConnMaxLifetime
+	if lifetime <= 0 {
+		lifetime = time.Duration(config.DefaultConnMaxLifetimeMins) * time.Minute
+	}
+	idleTime := cfg.ConnMaxIdleTime
+	if idleTime <= 0 {
+		idleTime = time.Duration(config.DefaultConnMaxIdleTimeMins) * time.Minute
+	}
+
+	conn.SetMaxOpenConns(maxOpen)
+	conn.SetMaxIdleConns(maxIdle)
+	conn.SetConnMaxLifetime(lifetime)
+	conn.SetConnMaxIdleTime(idleTime)
+	return conn, nil
+}
+
+// pool returns the pool of the named connection, opening a connect_on_demand
+// connection on first use. It returns nil for unknown connections and for
+// ones that fail to open.
+func (cm *ConnectionManager) pool(name string) *sql.DB {
+	cm.mu.RLock()
+	db, pending := cm.connections[name], cm.pending[name] != nil
+	cm.mu.RUnlock()
+	if db != nil || !pending {
+		return db
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.openPendingLocked(name)
+}
+
+// openPendingLocked opens the pool of a connect_on_demand connection. The
+// server type is detected later, on the first GetServerType. Callers must
+// hold cm.mu.
+func (cm *ConnectionManager) openPendingLocked(name string) *sql.DB {
+	if db := cm.connections[name]; db != nil {
+		return db
+	}
+	poolCfg := cm.pending[name]
+	if poolCfg == nil {
+		return nil
+	}
+	conn, err := cm.openPoolLocked(cm.configs[name], poolCfg)
+	if err != nil {
+		poolLog.Warn("failed to open connection on demand", map[string]interface{}{"name": name, "error": err.Error()})
+		return nil
+	}
+	delete(cm.pending, name)
+	cm.connections[name] = conn
+	poolLog.Info("connection opened on demand", map[string]interface{}{"name": name})
+	return conn
+}
+
+// OpenAll opens every connect_on_demand connection not opened yet and returns
+// all pools, keyed by name. Connections that fail to open are left out.
+func (cm *ConnectionManager) OpenAll() map[string]*sql.DB {
+	cm.mu.Lock()
+	for name := range cm.pending {
+		cm.openPendingLocked(name)
+	}
+	cm.mu.Unlock()
+	return cm.Pools()
+}
+
+// Pending reports whether name is a connect_on_demand connection that has
+// not been opened yet.
+func (cm *ConnectionManager) Pending(name string) bool {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.pending[name] != nil
+}
+
+// openMySQL prepares the DSN for connCfg (SSL, socket/compression/collation
+// options, timeouts, strict read-only, optional SSH tunnel) and opens the pool. Callers must hold cm.mu.
+func (cm *ConnectionManager) openMySQL(connCfg config.ConnectionConfig, cfg *config.Config) (*sql.DB, error) {
+	dsn, err := config.ApplyConnectionOptionsToDSN(config.ApplySSLToDSN(connCfg.DSN, connCfg.SSL), connCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
+	}
+	dsn, err = applyDefaultIOTimeouts(dsn, cfg.QueryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
+	}
+	dsn, err = applyStrictReadOnlyDSN(dsn, cfg.StrictReadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
+	}
+	dsn, err = applyProgramNameDSN(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DSN for %s: %w", connCfg.Name, err)
+	}
+
+	// If SSH tunnel is configured, start tunnel and rewrite DSN to use local listener
+	if connCfg.SSH != nil && connCfg.SSH.Host != "" && connCfg.SSH.User != "" && connCfg.SSH.KeyPath != "" {
+		mysqlCfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DSN for SSH tunnel %s: %w", connCfg.Name, err)
+		}
+		remoteAddr := mysqlCfg.Addr
+		if remoteAddr == "" {
+			remoteAddr = "127.0.0.1:3306"
+		}
+		strict := config.EffectiveStrictSSHHostKeyChecking(connCfg.SSH)
+		tunnelCfg := sshtunnel.Config{
+			Host:                  connCfg.SSH.Host,
+			User:                  connCfg.SSH.User,
+			KeyPath:               connCfg.SSH.KeyPath,
+			Port:                  connCfg.SSH.Port,
+			InsecureIgnoreHostKey: !strict,
+			KnownHostsPath:        connCfg.SSH.KnownHostsPath,
+			HostKeyFingerprint:    connCfg.SSH.HostKeyFingerprint,
+		}
+		localAddr, closeTunnel, err := sshtunnel.Tunnel(tunnelCfg, remoteAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start SSH tunnel for %s: %w", connCfg.Name, err)
+		}
+		cm.tunnelClosers[connCfg.Name] = closeTunnel
+		mysqlCfg.Addr = localAddr
+		dsn = mysqlCfg.FormatDSN()
+	}
+
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		if closer := cm.tunnelClosers[connCfg.Name]; closer != nil {
+			closer()
+			delete(cm.tunnelClosers, connCfg.Name)
+		}
+		return nil, fmt.Errorf("failed to open connection %s: %w", connCfg.Name, err)
+	}
+	return conn, nil
+}
+
+// GetActive returns the active database connection and its name.
+func (cm *ConnectionManager) GetActive() (*sql.DB, string) {
+	cm.mu.RLock()
+	name := cm.activeConn
+	cm.mu.RUnlock()
+	return cm.pool(name), name
+}
+
+// SetActive sets the active connection by name.
+func (cm *ConnectionManager) SetActive(name string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if _, exists := cm.configs[name]; !exists {
+		return fmt.Errorf("connection '%s' not found", name)
+	}
+	cm.activeConn = name
+	return nil
+}
+
+// List returns a list of all connection configurations with masked DSNs.
+func (cm *ConnectionManager) List() []config.ConnectionConfig {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var list []config.ConnectionConfig
+	for _, cfg := range cm.configs {
+		// Mask DSN for security
+		maskedCfg := cfg
+		maskedCfg.DSN = util.MaskDSN(cfg.DSN)
+		maskedCfg.Replicas = nil
+		for _, dsn := range cfg.Replicas {
+			maskedCfg.Replicas = append(maskedCfg.Replicas, util.MaskDSN(dsn))
+		}
+		list = append(list, maskedCfg)
+	}
+	return list
+}
+
+// Config returns the configuration of the named connection (DSN unmasked).
+func (cm *ConnectionManager) Config(name string) (config.ConnectionConfig, bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	c, ok := cm.configs[name]
+	return c, ok
+}
+
+// Stats returns database/sql pool statistics for every connection, keyed by name.
+func (cm *ConnectionManager) Stats() map[string]sql.DBStats {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	stats := make(map[string]sql.DBStats, len(cm.connections))
+	for name, db := range cm.connections {
+		stats[name] = db.Stats()
+	}
+	return stats
+}
+
+// Pools returns a snapshot of every open connection pool, keyed by name.
+// connect_on_demand connections not used yet are left out.
+func (cm *ConnectionManager) Pools() map[string]*sql.DB {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	pools := make(map[string]*sql.DB, len(cm.connections))
+	for name, db := range cm.connections {
+		pools[name] = db
+	}
+	return pools
+}
+
+// GetActiveDB returns the active database connection.
+func (cm *ConnectionManager) GetActiveDB() *sql.DB {
+	db, _ := cm.GetActive()
+	return db
+}
+
+// Close closes all connections and SSH tunnels managed by the manager.
+func (cm *ConnectionManager) Close() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for name, conn := range cm.connections {
+		preparedStmts.forget(conn)
+		lowerCaseTableNames.Delete(conn)
+		if !cm.borrowed[name] {
+			conn.Close()
+		}
+	}
+	for _, closeFn := range cm.tunnelClosers {
+		closeFn()
+	}
+	cm.tunnelClosers = make(map[string]func())
+}
+
+// getDB returns the active database connection in a thread-safe manner.
+// All database access should go through this function to ensure proper
+// connection management and avoid data races when connections are switched.
+func getDB() *sql.DB {
+	if connManager == nil {
+		panic("getDB called before connManager initialized")
+	}
+	return connManager.GetActiveDB()
+}
+
+// GetServerType returns the server type of the active connection.
+// A connect_on_demand connection is detected, and its capabilities probed,
+// on its first call.
+func (cm *ConnectionManager) GetServerType() ServerType {
+	cm.mu.RLock()
+	name := cm.activeConn
+	st, exists := cm.serverTypes[name]
+	onDemand := cm.configs[name].ConnectOnDemand
+	cm.mu.RUnlock()
+	if exists {
+		return st
+	}
+	if !onDemand {
+		return ServerTypeUnknown
+	}
+	db := cm.pool(name)
+	if db == nil {
+		return ServerTypeUnknown
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	st = cm.detectServerType(ctx, db)
+	if st != ServerTypeUnknown {
+		caps := probeCapabilities(ctx, db, st)
+		cm.mu.Lock()
+		cm.serverTypes[name] = st
+		cm.capabilities[name] = caps
+		cm.mu.Unlock()
+	}
+	return st
+}
+
+// ActiveCapabilities returns the probed capabilities of the active connection
+// and its name. They are nil for a connection that was not probed yet or was
+// supplied by a DBProvider.
+func (cm *ConnectionManager) ActiveCapabilities() (*ConnectionCapabilities, string) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.capabilities[cm.activeConn], cm.activeConn
+}
+
+// detectServerType queries the server to determine if it's MySQL or MariaDB.
+func (cm *ConnectionManager) detectServerType(ctx context.Context, db *sql.DB) ServerType {
+	var version, versionComment string
+	// Try VERSION() and @@version_comment first
+	err := db.QueryRowContext(ctx, "SELECT VERSION(), @@version_comment").Scan(&version, &versionComment)
+	if err != nil {
+		// Fallback to just VERSION() if the combined query fails
+		err = db.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version)
+		if err != nil {
+			return ServerTypeUnknown
+		}
+	}
+
+	version = strings.TrimSpace(strings.ToLower(version))
+	versionComment = strings.TrimSpace(strings.ToLower(versionComment))
+
+	// If we got nothing back, we can't reliably identify the server
+	if version == "" && versionComment == "" {
+		return ServerTypeUnknown
+	}
+
+	if strings.Contains(version, "mariadb") || strings.Contains(versionComment, "mariadb") {
+		return ServerTypeMariaDB
+	}
+
+	return ServerTypeMySQL
+}
+
+// getServerType is a helper to get the active server type.
+func getServerType() ServerType {
+	if connManager == nil {
+		return ServerTypeUnknown
+	}
+	return connManager.GetServerType()
+}
+
+// ===== Sessions =====
+
+// Session pins one pooled connection so several statements run on the same
+// MySQL session. With snapshot set, they also run inside a read-only
+// transaction opened WITH CONSISTENT SNAPSHOT, so every statement sees the
+// data as of the moment the session was opened.
+type Session struct {
+	db       *sql.DB
+	conn     *sql.Conn
+	snapshot bool
+}
+
+// OpenSession takes a connection from db for a Session. Close must be
+// called to return it.
+func OpenSession(ctx context.Context, db *sql.DB, snapshot bool) (*Session, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	s := &Session{db: db, conn: conn}
+	if snapshot {
+		// A consistent snapshot only holds under REPEATABLE READ; SET
+		// TRANSACTION applies to the next transaction and leaves the
+		// session default alone.
+		for _, stmt := range []string{
+			"SET TRANSACTION ISOLATION LEVEL REPEATABLE READ",
+			"START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY",
+		} {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				s.Close()
+				return nil, fmt.Errorf("failed to start snapshot: %w", err)
+			}
+		}
+		s.snapshot = true
+	}
+	return s, nil
+}
+
+// Snapshot reports whether the session runs inside a consistent snapshot.
+func (s *Session) Snapshot() bool { return s.snapshot }
+
+// Close ends the snapshot transaction, if any, and returns the connection
+// to the pool. A connection whose transaction cannot be ended is discarded.
+func (s *Session) Close() {
+	if s.snapshot {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+		if _, err := s.conn.ExecContext(ctx, "ROLLBACK"); err != nil {
+			_ = s.conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		s.snapshot = false
+	}
+	_ = s.conn.Close()
+}
+
+type sessionKey struct{}
+
+// withSession makes the queries run under ctx on the pool of s use its
+// connection.
+func withSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// acquireConn returns the connection of the Session in ctx when it belongs
+// to db, otherwise a new connection from db. release returns a new
+// connection to the pool and leaves a session's connection open.
+func acquireConn(ctx context.Context, db *sql.DB) (conn *sql.Conn, release func(), err error) {
+	if s, ok := ctx.Value(sessionKey{}).(*Session); ok && s.db == db {
+		return s.conn, func() {}, nil
+	}
+	conn, err = db.Conn(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return conn, func() { _ = conn.Close() }, nil
+}
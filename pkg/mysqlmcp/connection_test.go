@@ -1,5 +1,5 @@
-// cmd/mysql-mcp-server/connection_test.go
-package main
+// pkg/mysqlmcp/connection_test.go
+package mysqlmcp
 
 import (
 	"context"
@@ -10,7 +10,9 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/demo"
 	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestApplyDefaultIOTimeouts(t *testing.T) {
@@ -431,3 +433,86 @@ func TestDetectServerType(t *testing.T) {
 		}
 	})
 }
+
+func TestDemoConnectionServesCoreTools(t *testing.T) {
+	oldConnManager, oldMaxRows, oldQueryTimeout := connManager, maxRows, queryTimeout
+	defer func() { connManager, maxRows, queryTimeout = oldConnManager, oldMaxRows, oldQueryTimeout }()
+
+	cm := NewConnectionManager()
+	defer cm.Close()
+	if err := cm.AddConnectionWithPoolConfig(config.DemoConnection(), &config.Config{}); err != nil {
+		t.Fatalf("failed to add demo connection: %v", err)
+	}
+	if st := cm.GetServerType(); st != ServerTypeMySQL {
+		t.Errorf("expected demo server to report MySQL, got %s", st)
+	}
+	connManager, maxRows, queryTimeout = cm, 100, 5*time.Second
+	ctx := context.Background()
+
+	_, tables, err := toolListTables(ctx, &mcp.CallToolRequest{}, ListTablesInput{Database: demo.Schema})
+	if err != nil || len(tables.Tables) != 4 {
+		t.Fatalf("list_tables: %v %+v", err, tables)
+	}
+
+	_, desc, err := toolDescribeTable(ctx, &mcp.CallToolRequest{}, DescribeTableInput{Database: demo.Schema, Table: "orders"})
+	if err != nil || len(desc.Columns) == 0 || desc.Columns[0].Key != "PRI" {
+		t.Fatalf("describe_table: %v %+v", err, desc)
+	}
+
+	_, cons, err := toolTableConstraints(ctx, &mcp.CallToolRequest{}, TableConstraintsInput{Database: demo.Schema, Table: "orders"})
+	if err != nil || len(cons.Constraints) == 0 {
+		t.Fatalf("table_constraints: %v %+v", err, cons)
+	}
+
+	_, res, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{
+		SQL:      "SELECT status, COUNT(*) AS n FROM orders GROUP BY status ORDER BY status",
+		Database: demo.Schema,
+	})
+	if err != nil {
+		t.Fatalf("run_query: %v", err)
+	}
+	if len(res.Columns) != 2 || len(res.Rows) != 4 {
+		t.Errorf("unexpected run_query result: %+v", res)
+	}
+}
+
+func TestConnectOnDemand(t *testing.T) {
+	oldPingTimeout := pingTimeout
+	pingTimeout = time.Second
+	defer func() { pingTimeout = oldPingTimeout }()
+
+	cm := NewConnectionManager()
+	defer cm.Close()
+	poolCfg := &config.Config{PingTimeout: time.Second}
+	// Nothing listens on port 1: adding must not dial it.
+	lazy := config.ConnectionConfig{Name: "lazy", DSN: "user:pass@tcp(127.0.0.1:1)/db", ConnectOnDemand: true}
+	if err := cm.AddConnectionWithPoolConfig(lazy, poolCfg); err != nil {
+		t.Fatalf("adding an on-demand connection should not connect: %v", err)
+	}
+	if len(cm.Pools()) != 0 || !cm.Pending("lazy") {
+		t.Fatalf("expected no open pool before first use, got %v", cm.Pools())
+	}
+	bad := config.ConnectionConfig{Name: "bad", DSN: "not a dsn", ConnectOnDemand: true}
+	if err := cm.AddConnectionWithPoolConfig(bad, poolCfg); err == nil {
+		t.Error("expected an invalid DSN to be rejected at startup")
+	}
+
+	db, name := cm.GetActive()
+	if db == nil || name != "lazy" || cm.Pending("lazy") {
+		t.Fatalf("expected the first use to open the pool, got %v %q", db, name)
+	}
+	if db2, _ := cm.GetActive(); db2 != db {
+		t.Error("expected the pool to be opened once")
+	}
+
+	// The readiness check opens and pings the rest like any other connection.
+	other := lazy
+	other.Name = "lazy2"
+	if err := cm.AddConnectionWithPoolConfig(other, poolCfg); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	report := checkReadiness(context.Background(), cm)
+	if len(report.Connections) != 2 || report.Connections[0].OK || report.Connections[1].OK || cm.Pending("lazy2") {
+		t.Errorf("expected an unreachable connection in the readiness report, got %+v", report.Connections)
+	}
+}
@@ -1,6 +1,6 @@
 //go:build windows
 
-package main
+package mysqlmcp
 
 // maybeDaemonize is a no-op on Windows (no fork/setsid). Use a service manager or
 // start the process in the background instead.
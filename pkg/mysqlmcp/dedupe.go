@@ -0,0 +1,79 @@
+// pkg/mysqlmcp/dedupe.go
+package mysqlmcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// run_query counts the returned rows that are exact copies of an earlier row.
+// With dedupe it drops them and reports how many went; either way a result
+// with many copies gets a hint, since agents often write joins that fan out
+// because a join condition is missing or matches more rows than intended.
+
+const (
+	// A result gets the duplicate hint when it has at least
+	// duplicateAdvisoryMinRows rows and at least duplicateAdvisoryRatio of
+	// them repeat an earlier row.
+	duplicateAdvisoryMinRows = 10
+	duplicateAdvisoryRatio   = 0.3
+)
+
+// dedupeResult counts the rows of out that repeat an earlier row and, when
+// remove is set, drops them, keeping first occurrences in order and moving
+// the cell handles of kept rows along with them.
+func dedupeResult(out *QueryResult, remove bool) int {
+	seen := make(map[string]bool, len(out.Rows))
+	kept := out.Rows[:0:0]
+	newIndex := make([]int, len(out.Rows))
+	dups := 0
+	for i, row := range out.Rows {
+		key := fmt.Sprintf("%#v", row)
+		if seen[key] {
+			dups++
+			newIndex[i] = -1
+			continue
+		}
+		seen[key] = true
+		newIndex[i] = len(kept)
+		kept = append(kept, row)
+	}
+	if !remove || dups == 0 {
+		return dups
+	}
+
+	out.Rows = kept
+	var handles []CellHandle
+	for _, h := range out.CellHandles {
+		if h.Row < len(newIndex) && newIndex[h.Row] >= 0 {
+			h.Row = newIndex[h.Row]
+			handles = append(handles, h)
+		}
+	}
+	out.CellHandles = handles
+	return dups
+}
+
+// duplicateAdvisory returns the hint for a result of total rows of which dups
+// repeat an earlier row, or "" when there are too few to matter. tables are
+// the tables the query reads.
+func duplicateAdvisory(dups, total int, tables []string, removed bool) string {
+	if total < duplicateAdvisoryMinRows || float64(dups) < duplicateAdvisoryRatio*float64(total) {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d of %d rows (%.0f%%) were exact duplicates", dups, total, 100*float64(dups)/float64(total))
+	if removed {
+		b.WriteString(" and were removed by dedupe")
+	}
+	if len(tables) > 1 {
+		b.WriteString("; the query joins " + strings.Join(tables, ", ") +
+			", so a join condition may be missing or match more rows than intended (fan-out). Check the ON clauses before trusting counts or sums")
+	} else {
+		b.WriteString("; use SELECT DISTINCT or GROUP BY if only distinct rows are wanted")
+	}
+	if !removed {
+		b.WriteString(", or pass dedupe: true")
+	}
+	return b.String()
+}
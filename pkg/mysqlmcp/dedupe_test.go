@@ -0,0 +1,81 @@
+// pkg/mysqlmcp/dedupe_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDedupeResult(t *testing.T) {
+	out := QueryResult{
+		Rows: [][]interface{}{
+			{int64(1), "a"}, {int64(1), "a"}, {"1", "a"}, {int64(2), nil}, {int64(2), nil}, {int64(3), "long…"},
+		},
+		CellHandles: []CellHandle{{Row: 5, Column: "name", Handle: "h"}},
+	}
+	if dups := dedupeResult(&out, false); dups != 2 || len(out.Rows) != 6 {
+		t.Fatalf("counting must not remove rows: %d %d", dups, len(out.Rows))
+	}
+	if dups := dedupeResult(&out, true); dups != 2 || len(out.Rows) != 4 {
+		t.Fatalf("expected 2 rows removed, got %d leaving %d", dups, len(out.Rows))
+	}
+	if out.Rows[1][0] != "1" || len(out.CellHandles) != 1 || out.CellHandles[0].Row != 3 {
+		t.Errorf("unexpected rows %v handles %+v", out.Rows, out.CellHandles)
+	}
+}
+
+func TestDuplicateAdvisory(t *testing.T) {
+	if hint := duplicateAdvisory(5, 9, nil, false); hint != "" {
+		t.Errorf("expected no hint for a small result, got %q", hint)
+	}
+	if hint := duplicateAdvisory(2, 20, nil, false); hint != "" {
+		t.Errorf("expected no hint for few duplicates, got %q", hint)
+	}
+	hint := duplicateAdvisory(15, 20, []string{"orders", "order_items"}, false)
+	if !strings.Contains(hint, "15 of 20 rows (75%)") || !strings.Contains(hint, "join condition") || !strings.Contains(hint, "dedupe: true") {
+		t.Errorf("unexpected join hint %q", hint)
+	}
+	hint = duplicateAdvisory(15, 20, []string{"orders"}, true)
+	if !strings.Contains(hint, "removed by dedupe") || !strings.Contains(hint, "DISTINCT") || strings.Contains(hint, "dedupe: true") {
+		t.Errorf("unexpected single table hint %q", hint)
+	}
+}
+
+func TestRunQueryDedupe(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	const query = "SELECT c.name FROM customers c JOIN orders o ON o.region = c.region"
+	fanOut := func() *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"name"})
+		for i := 0; i < 12; i++ {
+			rows.AddRow([]string{"Alice", "Bob", "Carol"}[i%3])
+		}
+		return rows
+	}
+
+	mock.ExpectQuery("SELECT c.name FROM customers c JOIN orders o").WillReturnRows(fanOut())
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rows) != 12 || out.DuplicateRows != 0 || !strings.Contains(out.Warning, "9 of 12 rows") {
+		t.Errorf("expected all rows and a hint, got %d rows, warning %q", len(out.Rows), out.Warning)
+	}
+
+	mock.ExpectQuery("SELECT c.name FROM customers c JOIN orders o").WillReturnRows(fanOut())
+	_, out, err = toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: query, Dedupe: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rows) != 3 || out.DuplicateRows != 9 || !strings.Contains(out.Warning, "removed by dedupe") {
+		t.Errorf("expected 3 distinct rows, got %d (%d removed), warning %q", len(out.Rows), out.DuplicateRows, out.Warning)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
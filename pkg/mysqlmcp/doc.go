@@ -0,0 +1,20 @@
+// Package mysqlmcp is the MySQL MCP tool surface of mysql-mcp-server as a
+// library. New builds a Server with the tools, prompts, validation, RBAC and
+// auditing of the binary, so a Go program can serve them over any MCP
+// transport without running mysql-mcp-server as a separate process.
+//
+// Settings come from the environment and config file exactly as for the
+// binary (see config.Load); Options add the pieces an embedding program
+// usually owns itself, such as its connection pools and audit sink.
+//
+// # One Server per process
+//
+// The tools keep their state in package variables, not on the Server: the
+// loaded configuration and limits, the connection manager, the audit log,
+// RBAC and tool filters, caches and background tasks. A process therefore
+// runs one Server at a time. New returns an error while another Server is
+// open, and Main, which uses the same state, must not run in a process
+// with an open Server. To serve several MySQL instances, hand them to one
+// Server as connections of a DBProvider; to apply a changed configuration,
+// Close the Server and call New again.
+package mysqlmcp
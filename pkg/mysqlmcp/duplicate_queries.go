@@ -0,0 +1,125 @@
+// pkg/mysqlmcp/duplicate_queries.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// Agents caught in a loop often send the same run_query five or ten times in
+// a row. With MYSQL_MCP_DUPLICATE_WINDOW_SECONDS set, a call that repeats a
+// query of the same MCP session within the window gets the earlier result
+// back, marked duplicate_suppressed, instead of querying MySQL again. Queries
+// match when they differ only in whitespace, keyword case and comments, and
+// the connection, database and result options are the same. The window runs
+// from when the result was read, so a loop sees fresh data again once it has
+// passed. HTTP calls have no session and are never suppressed.
+
+// maxDuplicateEntries caps the results held across all sessions.
+const maxDuplicateEntries = 256
+
+// duplicateEntry is the last result of one query in one session.
+type duplicateEntry struct {
+	result  QueryResult
+	at      time.Time // when result was read
+	repeats int       // calls answered from result so far
+}
+
+// duplicateCache holds recent run_query results by session and query.
+type duplicateCache struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*duplicateEntry
+}
+
+// duplicates is nil when duplicate suppression is off.
+var duplicates *duplicateCache
+
+func initDuplicates(c *config.Config) {
+	if c.DuplicateWindow <= 0 {
+		duplicates = nil
+		return
+	}
+	duplicates = &duplicateCache{window: c.DuplicateWindow, now: time.Now, entries: make(map[string]*duplicateEntry)}
+}
+
+// duplicateKey identifies a run_query call of the MCP session in ctx, or
+// returns "" outside a session. execSQL is the statement after row policies.
+func duplicateKey(ctx context.Context, input RunQueryInput, execSQL string) string {
+	session := replicaSessionFrom(ctx)
+	if session == "" {
+		return ""
+	}
+	conn := ""
+	if connManager != nil {
+		_, conn = connManager.GetActive()
+	}
+	key, _ := json.Marshal([]interface{}{
+		session, conn, input.Database, util.ExactQueryDigest(execSQL),
+		input.MaxRows, input.Offset, input.BinaryOutput, input.TimeZone, input.Dedupe,
+	})
+	return string(key)
+}
+
+// lookup returns the result stored under key if it is still within the
+// window, annotated as a suppressed duplicate.
+func (d *duplicateCache) lookup(key string) (QueryResult, bool) {
+	if d == nil || key == "" {
+		return QueryResult{}, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.entries[key]
+	if !ok {
+		return QueryResult{}, false
+	}
+	age := d.now().Sub(e.at)
+	if age >= d.window {
+		delete(d.entries, key)
+		return QueryResult{}, false
+	}
+	e.repeats++
+	out := e.result
+	out.DuplicateSuppressed = true
+	out.DuplicateRepeats = e.repeats
+	out.CachedAt = e.at.UTC().Format(time.RFC3339)
+	note := fmt.Sprintf("duplicate suppressed: this exact query already ran %s ago in this session "+
+		"(repeat %d); its result is returned without querying MySQL again. Change the query if you need different data",
+		age.Round(time.Second), e.repeats)
+	if out.Warning != "" {
+		note = out.Warning + "; " + note
+	}
+	out.Warning = note
+	return out, true
+}
+
+// store remembers result under key, dropping expired entries and, when the
+// cache is full, the oldest one.
+func (d *duplicateCache) store(key string, result QueryResult) {
+	if d == nil || key == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	var oldest string
+	for k, e := range d.entries {
+		if now.Sub(e.at) >= d.window {
+			delete(d.entries, k)
+		} else if oldest == "" || e.at.Before(d.entries[oldest].at) {
+			oldest = k
+		}
+	}
+	if len(d.entries) >= maxDuplicateEntries {
+		delete(d.entries, oldest)
+	}
+	d.entries[key] = &duplicateEntry{result: result, at: now}
+}
@@ -0,0 +1,90 @@
+// pkg/mysqlmcp/duplicate_queries_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDuplicateQuerySuppression(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	oldDuplicates := duplicates
+	duplicates = &duplicateCache{window: 30 * time.Second, now: func() time.Time { return now }, entries: map[string]*duplicateEntry{}}
+	defer func() { duplicates = oldDuplicates }()
+
+	oldAudit := auditLogger
+	audit := &recordingAuditor{}
+	auditLogger = &AuditLogger{enabled: true, sink: audit}
+	defer func() { auditLogger = oldAudit }()
+
+	session := context.WithValue(context.Background(), replicaSessionKey{}, "mcp:s1")
+	run := func(ctx context.Context, sql string) QueryResult {
+		t.Helper()
+		_, out, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: sql})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	expectOrders := func() {
+		mock.ExpectQuery("SELECT id FROM orders WHERE id = 7 LIMIT 1000").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	}
+
+	expectOrders()
+	if out := run(session, "SELECT id FROM orders WHERE id = 7"); out.DuplicateSuppressed {
+		t.Fatal("the first call must query MySQL")
+	}
+	now = now.Add(5 * time.Second)
+	out := run(session, "select id  from orders where id=7;")
+	if !out.DuplicateSuppressed || out.DuplicateRepeats != 1 || len(out.Rows) != 1 || out.CachedAt != "2026-10-15T12:00:00Z" {
+		t.Errorf("expected a suppressed duplicate, got %+v", out)
+	}
+	if !strings.Contains(out.Warning, "duplicate suppressed") || !strings.Contains(out.Warning, "5s ago") {
+		t.Errorf("unexpected warning %q", out.Warning)
+	}
+	if out := run(session, "SELECT id FROM orders WHERE id = 7"); out.DuplicateRepeats != 2 {
+		t.Errorf("expected the second repeat to be counted, got %+v", out)
+	}
+	if len(audit.entries) != 3 || audit.entries[0].Duplicate || !audit.entries[1].Duplicate || !audit.entries[2].Duplicate ||
+		audit.entries[1].RowCount != 1 || !audit.entries[1].Success {
+		t.Errorf("expected every call audited and the repeats marked duplicate, got %+v", audit.entries)
+	}
+
+	// Another session, a call without a session and a call after the window
+	// all reach MySQL.
+	expectOrders()
+	run(context.WithValue(context.Background(), replicaSessionKey{}, "mcp:s2"), "SELECT id FROM orders WHERE id = 7")
+	expectOrders()
+	run(context.Background(), "SELECT id FROM orders WHERE id = 7")
+	now = now.Add(30 * time.Second)
+	expectOrders()
+	if out := run(session, "SELECT id FROM orders WHERE id = 7"); out.DuplicateSuppressed {
+		t.Error("expected a fresh result once the window passed")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDuplicateCacheBounded(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	d := &duplicateCache{window: time.Hour, now: func() time.Time { now = now.Add(time.Millisecond); return now }, entries: map[string]*duplicateEntry{}}
+	for i := 0; i < maxDuplicateEntries+10; i++ {
+		d.store(strings.Repeat("k", i+1), QueryResult{})
+	}
+	if len(d.entries) != maxDuplicateEntries {
+		t.Errorf("expected %d entries, got %d", maxDuplicateEntries, len(d.entries))
+	}
+	if _, ok := d.lookup("k"); ok {
+		t.Error("expected the oldest entry to be evicted")
+	}
+}
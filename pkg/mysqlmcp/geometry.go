@@ -0,0 +1,205 @@
+// pkg/mysqlmcp/geometry.go
+package mysqlmcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errBadGeometry = errors.New("malformed geometry value")
+
+// WKB geometry type codes.
+const (
+	wkbPoint              = 1
+	wkbLineString         = 2
+	wkbPolygon            = 3
+	wkbMultiPoint         = 4
+	wkbMultiLineString    = 5
+	wkbMultiPolygon       = 6
+	wkbGeometryCollection = 7
+)
+
+// geometryToWKT converts a value in MySQL's internal geometry format (a
+// 4-byte little-endian SRID followed by WKB) to WKT, matching ST_AsText for
+// Cartesian data. Coordinates are printed in storage (x y) order.
+func geometryToWKT(b []byte) (string, error) {
+	if len(b) < 4 {
+		return "", errBadGeometry
+	}
+	d := &wkbDecoder{buf: b[4:]}
+	var sb strings.Builder
+	if err := d.geometry(&sb, true); err != nil {
+		return "", err
+	}
+	if len(d.buf) != 0 {
+		return "", errBadGeometry
+	}
+	return sb.String(), nil
+}
+
+type wkbDecoder struct {
+	buf   []byte
+	order binary.ByteOrder
+	depth int
+}
+
+func (d *wkbDecoder) uint32() (uint32, error) {
+	if len(d.buf) < 4 {
+		return 0, errBadGeometry
+	}
+	v := d.order.Uint32(d.buf)
+	d.buf = d.buf[4:]
+	return v, nil
+}
+
+// count reads an element count, rejecting counts the remaining bytes cannot hold.
+func (d *wkbDecoder) count(minSize int) (int, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(d.buf)) {
+		return 0, errBadGeometry
+	}
+	return int(n), nil
+}
+
+func (d *wkbDecoder) point(sb *strings.Builder) error {
+	if len(d.buf) < 16 {
+		return errBadGeometry
+	}
+	x := math.Float64frombits(d.order.Uint64(d.buf))
+	y := math.Float64frombits(d.order.Uint64(d.buf[8:]))
+	d.buf = d.buf[16:]
+	sb.WriteString(formatCoord(x))
+	sb.WriteByte(' ')
+	sb.WriteString(formatCoord(y))
+	return nil
+}
+
+// points writes a parenthesized coordinate list.
+func (d *wkbDecoder) points(sb *strings.Builder) error {
+	n, err := d.count(16)
+	if err != nil {
+		return err
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := d.point(sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+func (d *wkbDecoder) rings(sb *strings.Builder) error {
+	n, err := d.count(4)
+	if err != nil {
+		return err
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := d.points(sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// header reads the byte order and type of the next geometry.
+func (d *wkbDecoder) header() (uint32, error) {
+	if len(d.buf) < 1 {
+		return 0, errBadGeometry
+	}
+	switch d.buf[0] {
+	case 0:
+		d.order = binary.BigEndian
+	case 1:
+		d.order = binary.LittleEndian
+	default:
+		return 0, errBadGeometry
+	}
+	d.buf = d.buf[1:]
+	return d.uint32()
+}
+
+// geometry writes one WKB geometry. Members of multi-geometries are written
+// without their type name unless they sit in a GEOMETRYCOLLECTION (named).
+func (d *wkbDecoder) geometry(sb *strings.Builder, named bool) error {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > 32 {
+		return errBadGeometry
+	}
+	typ, err := d.header()
+	if err != nil {
+		return err
+	}
+	name := map[uint32]string{
+		wkbPoint: "POINT", wkbLineString: "LINESTRING", wkbPolygon: "POLYGON",
+		wkbMultiPoint: "MULTIPOINT", wkbMultiLineString: "MULTILINESTRING",
+		wkbMultiPolygon: "MULTIPOLYGON", wkbGeometryCollection: "GEOMETRYCOLLECTION",
+	}[typ]
+	if name == "" {
+		return fmt.Errorf("%w: unsupported type %d", errBadGeometry, typ)
+	}
+	if named {
+		sb.WriteString(name)
+	}
+
+	switch typ {
+	case wkbPoint:
+		sb.WriteByte('(')
+		if err := d.point(sb); err != nil {
+			return err
+		}
+		sb.WriteByte(')')
+		return nil
+	case wkbLineString:
+		return d.points(sb)
+	case wkbPolygon:
+		return d.rings(sb)
+	}
+
+	n, err := d.count(5)
+	if err != nil {
+		return err
+	}
+	if n == 0 && typ == wkbGeometryCollection {
+		sb.WriteString(" EMPTY")
+		return nil
+	}
+	sb.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		if err := d.geometry(sb, typ == wkbGeometryCollection); err != nil {
+			return err
+		}
+	}
+	sb.WriteByte(')')
+	return nil
+}
+
+// formatCoord prints a coordinate in its shortest form, switching to an
+// exponent (written without a plus sign, as MySQL does) only for very large
+// or very small magnitudes.
+func formatCoord(v float64) string {
+	if a := math.Abs(v); a != 0 && (a >= 1e15 || a < 1e-5) {
+		return strings.Replace(strconv.FormatFloat(v, 'g', -1, 64), "e+", "e", 1)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,120 @@
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// wkb builds little-endian WKB: a type header followed by uint32 counts and
+// float64 coordinates.
+func wkb(typ uint32, parts ...interface{}) []byte {
+	b := []byte{1}
+	b = binary.LittleEndian.AppendUint32(b, typ)
+	for _, p := range parts {
+		switch v := p.(type) {
+		case int:
+			b = binary.LittleEndian.AppendUint32(b, uint32(v))
+		case float64:
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+		case []byte:
+			b = append(b, v...)
+		}
+	}
+	return b
+}
+
+// mysqlGeometry prefixes WKB with a little-endian SRID as MySQL stores it.
+func mysqlGeometry(srid uint32, w []byte) []byte {
+	return append(binary.LittleEndian.AppendUint32(nil, srid), w...)
+}
+
+func TestGeometryToWKT(t *testing.T) {
+	pt := func(x, y float64) []byte { return wkb(wkbPoint, x, y) }
+	tests := []struct {
+		name string
+		wkb  []byte
+		want string
+	}{
+		{"point", pt(1, -2.5), "POINT(1 -2.5)"},
+		{"linestring", wkb(wkbLineString, 2, 0.0, 0.0, 10.0, 1e20), "LINESTRING(0 0,10 1e20)"},
+		{"polygon", wkb(wkbPolygon, 1, 4, 0.0, 0.0, 1.0, 0.0, 1.0, 1.0, 0.0, 0.0), "POLYGON((0 0,1 0,1 1,0 0))"},
+		{"multipoint", wkb(wkbMultiPoint, 2, pt(1, 1), pt(2, 2)), "MULTIPOINT((1 1),(2 2))"},
+		{"collection", wkb(wkbGeometryCollection, 2, pt(1, 1), wkb(wkbLineString, 2, 0.0, 0.0, 1.0, 1.0)), "GEOMETRYCOLLECTION(POINT(1 1),LINESTRING(0 0,1 1))"},
+		{"empty collection", wkb(wkbGeometryCollection, 0), "GEOMETRYCOLLECTION EMPTY"},
+	}
+	for _, tt := range tests {
+		got, err := geometryToWKT(mysqlGeometry(4326, tt.wkb))
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range [][]byte{nil, {0, 0, 0, 0}, mysqlGeometry(0, wkb(wkbLineString, 1000, 1.0)), mysqlGeometry(0, wkb(99))} {
+		if _, err := geometryToWKT(bad); err == nil {
+			t.Errorf("expected error for %x", bad)
+		}
+	}
+}
+
+func TestRunQueryRendersGeometryAsWKT(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("name").OfType("VARCHAR", ""),
+		sqlmock.NewColumn("location").OfType("GEOMETRY", []byte(nil)),
+	).AddRow("hq", mysqlGeometry(0, wkb(wkbPoint, 3.0, 4.0))).AddRow("bad", []byte{1, 2})
+	mock.ExpectQuery("SELECT name, location FROM sites").WillReturnRows(rows)
+
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT name, location FROM sites"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if out.Rows[0][1] != "POINT(3 4)" {
+		t.Errorf("location = %v, want POINT(3 4)", out.Rows[0][1])
+	}
+	if out.Rows[1][1] != "0x0102" {
+		t.Errorf("malformed geometry = %v, want hex fallback", out.Rows[1][1])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolSpatialInfo(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.COLUMNS c").
+		WithArgs("gis").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "DATA_TYPE", "IS_NULLABLE", "SRS_ID", "SRS_NAME"}).
+			AddRow("sites", "location", "point", "NO", 4326, "WGS 84").
+			AddRow("zones", "area", "polygon", "YES", nil, nil))
+	mock.ExpectQuery("FROM information_schema.STATISTICS").
+		WithArgs("gis").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "INDEX_NAME", "COLUMN_NAME"}).
+			AddRow("sites", "sp_location", "location").
+			AddRow("zones", "sp_area", "area"))
+
+	_, out, err := toolSpatialInfo(context.Background(), &mcp.CallToolRequest{}, SpatialInfoInput{Database: "gis"})
+	if err != nil {
+		t.Fatalf("spatial_info failed: %v", err)
+	}
+	if len(out.Columns) != 2 || out.Columns[0].SRID == nil || *out.Columns[0].SRID != 4326 || out.Columns[0].SRSName != "WGS 84" {
+		t.Fatalf("unexpected columns: %+v", out.Columns)
+	}
+	if out.Columns[0].SpatialIndex != "sp_location" || out.Columns[1].SRID != nil || !out.Columns[1].Nullable {
+		t.Errorf("unexpected columns: %+v", out.Columns)
+	}
+	if len(out.Notes) != 1 {
+		t.Errorf("expected one note about sp_area, got %v", out.Notes)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
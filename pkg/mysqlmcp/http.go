@@ -0,0 +1,1970 @@
+// pkg/mysqlmcp/http.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+)
+
+const maxJSONRequestBodyBytes int64 = 1 << 20 // 1 MiB
+
+// httpContext returns a context with timeout for HTTP handlers.
+// Uses the request's context as parent to properly handle client disconnects.
+func httpContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), cfg.HTTPRequestTimeout)
+}
+
+// queryFlag reports whether query parameter name is "1" or "true" (case-insensitive).
+func queryFlag(r *http.Request, name string) bool {
+	v := r.URL.Query().Get(name)
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+// queryInts parses optional non-negative integer query parameters into dst.
+// On an invalid value it writes a 400 response and returns false.
+func queryInts(w http.ResponseWriter, r *http.Request, dst map[string]*int) bool {
+	q := r.URL.Query()
+	for name, p := range dst {
+		if s := q.Get(name); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				api.WriteBadRequest(w, "invalid "+name+" parameter")
+				return false
+			}
+			*p = n
+		}
+	}
+	return true
+}
+
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxJSONRequestBodyBytes)
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return err
+	}
+
+	// Reject trailing data (helps avoid JSON request smuggling / ambiguity)
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		if err == nil {
+			return fmt.Errorf("request body must contain a single JSON object")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ===== Core HTTP Handlers =====
+
+// httpListDatabases handles GET /api/databases?prefix=xxx&offset=0&limit=50
+func httpListDatabases(w http.ResponseWriter, r *http.Request) {
+	input := ListDatabasesInput{Prefix: r.URL.Query().Get("prefix")}
+	if !queryInts(w, r, map[string]*int{"offset": &input.Offset, "limit": &input.Limit}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListDatabasesWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccessPageETag(w, r, out, listPage(input.Offset, input.Limit, out.Total, out.NextOffset))
+}
+
+// httpListTables handles GET /api/tables?database=xxx&pattern=yyy&prefix=zzz&include_metadata=1&offset=0&limit=50&sort=-rows&filter=engine:eq:InnoDB
+func httpListTables(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := ListTablesInput{
+		Database:        q.Get("database"),
+		Pattern:         q.Get("pattern"),
+		Prefix:          q.Get("prefix"),
+		IncludeMetadata: queryFlag(r, "include_metadata"),
+		IncludeTotal:    true,
+	}
+	if !listQueryParams(w, r, tableListFields, &input.Offset, &input.Limit, &input.Sort, &input.Filter) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListTablesWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccessPageETag(w, r, out, listPage(input.Offset, input.Limit, *out.Total, out.NextOffset))
+}
+
+// httpDescribeTable handles GET /api/describe?database=xxx&table=yyy
+func httpDescribeTable(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	table := r.URL.Query().Get("table")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolDescribeTableWrapped(ctx, nil, DescribeTableInput{Database: database, Table: table})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccessETag(w, r, out)
+}
+
+// httpRunQuery handles POST /api/query with JSON body {"sql": "...", "database": "...", "max_rows": N}
+func httpRunQuery(w http.ResponseWriter, r *http.Request) {
+	var input RunQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolRunQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeQueryResult(w, r, out)
+}
+
+// httpValidateQuery handles POST /api/validate with JSON body {"sql": "...", "database": "..."}
+func httpValidateQuery(w http.ResponseWriter, r *http.Request) {
+	var input ValidateQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolValidateQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpExplainValidation handles POST /api/validate/rules with JSON body {"sql": "...", "database": "..."}
+func httpExplainValidation(w http.ResponseWriter, r *http.Request) {
+	var input ExplainValidationInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolExplainValidationWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpFetchCell handles POST /api/cell with JSON body {"handle": "...", "offset": N, "length": N, "encoding": "..."}
+func httpFetchCell(w http.ResponseWriter, r *http.Request) {
+	var input FetchCellInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Handle == "" {
+		api.WriteBadRequest(w, "handle field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolFetchCellWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListSavedQueries handles GET /api/saved-queries
+func httpListSavedQueries(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListSavedQueriesWrapped(ctx, nil, ListSavedQueriesInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpRunSavedQuery handles POST /api/saved-queries/run with JSON body {"name": "...", "params": {...}, "database": "...", "max_rows": N}
+func httpRunSavedQuery(w http.ResponseWriter, r *http.Request) {
+	var input RunSavedQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Name == "" {
+		api.WriteBadRequest(w, "name field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolRunSavedQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeQueryResult(w, r, out)
+}
+
+// httpSaveQuery handles POST /api/saved-queries/save with JSON body {"name": "...", "sql": "...", "description": "...", "database": "...", "params": [...]}
+func httpSaveQuery(w http.ResponseWriter, r *http.Request) {
+	var input SaveQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Name == "" || input.SQL == "" {
+		api.WriteBadRequest(w, "name and sql fields are required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolSaveQueryWrapped(ctx, nil, input)
+	if errors.Is(err, errToolForbidden) || errors.Is(err, errServerBusy) || errors.Is(err, errCircuitOpen) {
+		writeToolError(w, err)
+		return
+	}
+	if err != nil {
+		api.WriteBadRequest(w, err.Error())
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpBookmarks handles /api/bookmarks: GET lists bookmarks, POST saves one
+// (body: {"name": "...", "sql": "...", "description": "...", "database": "...", "params": [...]})
+// and DELETE ?name= removes one.
+func httpBookmarks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		ctx, cancel := httpContext(r)
+		defer cancel()
+		_, out, err := toolListBookmarksWrapped(ctx, nil, ListBookmarksInput{})
+		if err != nil {
+			writeToolError(w, err)
+			return
+		}
+		api.WriteSuccess(w, out)
+	case http.MethodPost:
+		var input SaveQueryInput
+		if err := decodeJSONBody(w, r, &input); err != nil {
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+			return
+		}
+		if input.Name == "" || input.SQL == "" {
+			api.WriteBadRequest(w, "name and sql fields are required")
+			return
+		}
+		ctx, cancel := httpContext(r)
+		defer cancel()
+		_, out, err := toolSaveBookmarkWrapped(ctx, nil, input)
+		if err != nil {
+			writeToolError(w, err)
+			return
+		}
+		api.WriteSuccess(w, out)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			api.WriteBadRequest(w, "name query parameter is required")
+			return
+		}
+		ctx, cancel := httpContext(r)
+		defer cancel()
+		_, out, err := toolDeleteBookmarkWrapped(ctx, nil, DeleteBookmarkInput{Name: name})
+		if err != nil && strings.HasPrefix(err.Error(), "bookmark not found") {
+			api.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeToolError(w, err)
+			return
+		}
+		api.WriteSuccess(w, out)
+	default:
+		api.WriteMethodNotAllowed(w, "GET, POST or DELETE method required")
+	}
+}
+
+// httpRunBookmark handles GET /api/bookmarks/run?name=...&database=...&max_rows=N;
+// every other query parameter is passed as a bookmark parameter, so a
+// bookmark can be shared as a plain link.
+func httpRunBookmark(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	input := RunSavedQueryInput{
+		Name:     query.Get("name"),
+		Database: query.Get("database"),
+		TimeZone: query.Get("time_zone"),
+		Params:   map[string]interface{}{},
+	}
+	if input.Name == "" {
+		api.WriteBadRequest(w, "name query parameter is required")
+		return
+	}
+	if v := query.Get("max_rows"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			api.WriteBadRequest(w, "max_rows must be a positive integer")
+			return
+		}
+		input.MaxRows = &n
+	}
+	for key, values := range query {
+		switch key {
+		case "name", "database", "time_zone", "max_rows":
+			continue
+		}
+		input.Params[key] = values[0]
+	}
+	if q, ok := savedQueries.get(strings.TrimSpace(input.Name)); !ok || q.source != savedQuerySourceBookmark {
+		api.WriteError(w, http.StatusNotFound, "bookmark not found: "+input.Name)
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolRunSavedQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	writeQueryResult(w, r, out)
+}
+
+// httpListReports handles GET /api/reports
+func httpListReports(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListReportsWrapped(ctx, nil, ListReportsInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpRunReport handles POST /api/reports/run with JSON body {"name": "...", "variables": {...}, "database": "...", "max_rows": N}
+func httpRunReport(w http.ResponseWriter, r *http.Request) {
+	var input RunReportInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Name == "" {
+		api.WriteBadRequest(w, "name field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolRunReportWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpPing handles GET /api/ping
+func httpPing(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolPingWrapped(ctx, nil, PingInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpServerInfo handles GET /api/server-info
+func httpServerInfo(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	detailed := r.URL.Query().Get("detailed") == "1" || strings.EqualFold(r.URL.Query().Get("detailed"), "true")
+	_, out, err := toolServerInfoWrapped(ctx, nil, ServerInfoInput{Detailed: detailed})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListConnections handles GET /api/connections
+func httpListConnections(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListConnectionsWrapped(ctx, nil, ListConnectionsInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpPoolStats handles GET /api/pool
+func httpPoolStats(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolPoolStatsWrapped(ctx, nil, PoolStatsInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpUsageStats handles GET /api/stats (optional ?tool=)
+func httpUsageStats(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolUsageStatsWrapped(ctx, nil, UsageStatsInput{Tool: r.URL.Query().Get("tool")})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpUseConnection handles POST /api/connections/use with JSON body {"name": "..."}
+func httpUseConnection(w http.ResponseWriter, r *http.Request) {
+	var input UseConnectionInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Name == "" {
+		api.WriteBadRequest(w, "name field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolUseConnectionWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// ===== Extended HTTP Handlers =====
+
+// httpListIndexes handles GET /api/indexes?database=xxx&table=yyy
+func httpListIndexes(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	table := r.URL.Query().Get("table")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListIndexesWrapped(ctx, nil, ListIndexesInput{Database: database, Table: table})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpShowCreateTable handles GET /api/create-table?database=xxx&table=yyy
+func httpShowCreateTable(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	table := r.URL.Query().Get("table")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolShowCreateTableWrapped(ctx, nil, ShowCreateTableInput{Database: database, Table: table})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccessETag(w, r, out)
+}
+
+// httpExplainQuery handles POST /api/explain with JSON body {"sql": "...", "database": "..."}
+func httpExplainQuery(w http.ResponseWriter, r *http.Request) {
+	var input ExplainQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolExplainQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpCheckPartitionPruning handles POST /api/explain/partitions with JSON body {"sql": "...", "database": "..."}
+func httpCheckPartitionPruning(w http.ResponseWriter, r *http.Request) {
+	var input CheckPartitionPruningInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolPartitionPruningWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpEstimateRows handles POST /api/estimate with JSON body {"sql": "...", "database": "...", "table": "..."}
+func httpEstimateRows(w http.ResponseWriter, r *http.Request) {
+	var input EstimateRowsInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" && input.Table == "" {
+		api.WriteBadRequest(w, "sql or table field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolEstimateRowsWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpTableCounts handles POST /api/table-counts with JSON body {"database": "...", "tables": ["..."], "mode": "estimate|exact"}
+func httpTableCounts(w http.ResponseWriter, r *http.Request) {
+	var input TableCountsInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Database == "" || len(input.Tables) == 0 {
+		api.WriteBadRequest(w, "database and tables fields are required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolTableCountsWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpOptimizerTrace handles POST /api/explain/trace with JSON body {"sql": "...", "database": "...", "max_bytes": n}
+func httpOptimizerTrace(w http.ResponseWriter, r *http.Request) {
+	var input OptimizerTraceInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolOptimizerTraceWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpNormalizeQuery handles POST /api/normalize body {"sql": "..."}
+func httpNormalizeQuery(w http.ResponseWriter, r *http.Request) {
+	var input NormalizeQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolNormalizeQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListGhostTables handles GET /api/ghost-tables?database=xxx
+func httpListGhostTables(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListGhostTablesWrapped(ctx, nil, ListGhostTablesInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListSummaryTables handles GET /api/summary-tables?database=xxx[&table=yyy]
+func httpListSummaryTables(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	table := r.URL.Query().Get("table")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListSummaryWrapped(ctx, nil, ListSummaryTablesInput{Database: database, Table: table})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpDataFreshness handles GET /api/data-freshness?database=xxx[&table=yyy][&refresh=true]
+func httpDataFreshness(w http.ResponseWriter, r *http.Request) {
+	input := DataFreshnessInput{
+		Database: r.URL.Query().Get("database"),
+		Table:    r.URL.Query().Get("table"),
+		Refresh:  queryFlag(r, "refresh"),
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolDataFreshnessWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpViewLineage handles GET /api/view-lineage?database=xxx[&view=yyy]
+func httpViewLineage(w http.ResponseWriter, r *http.Request) {
+	input := ViewLineageInput{
+		Database: r.URL.Query().Get("database"),
+		View:     r.URL.Query().Get("view"),
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolViewLineageWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListViews handles GET /api/views?database=xxx
+func httpListViews(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListViewsWrapped(ctx, nil, ListViewsInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListTriggers handles GET /api/triggers?database=xxx
+func httpListTriggers(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListTriggersWrapped(ctx, nil, ListTriggersInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListProcedures handles GET /api/procedures?database=xxx
+func httpListProcedures(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListProceduresWrapped(ctx, nil, ListProceduresInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListFunctions handles GET /api/functions?database=xxx
+func httpListFunctions(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListFunctionsWrapped(ctx, nil, ListFunctionsInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListPartitions handles GET /api/partitions?database=xxx&table=yyy
+func httpListPartitions(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	table := r.URL.Query().Get("table")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListPartitionsWrapped(ctx, nil, ListPartitionsInput{Database: database, Table: table})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpDatabaseSize handles GET /api/size/database?database=xxx (optional)
+func httpDatabaseSize(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolDatabaseSizeWrapped(ctx, nil, DatabaseSizeInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpTableSize handles GET /api/size/tables?database=xxx
+func httpTableSize(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolTableSizeWrapped(ctx, nil, TableSizeInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpForeignKeys handles GET /api/foreign-keys?database=xxx&table=yyy (table optional)
+func httpForeignKeys(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	table := r.URL.Query().Get("table")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolForeignKeysWrapped(ctx, nil, ForeignKeysInput{Database: database, Table: table})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpTableConstraints handles GET /api/constraints?database=xxx&table=yyy
+func httpTableConstraints(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolTableConstraintsWrapped(ctx, nil, TableConstraintsInput{Database: q.Get("database"), Table: q.Get("table")})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpSpatialInfo handles GET /api/spatial?database=xxx&table=yyy
+func httpSpatialInfo(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolSpatialInfoWrapped(ctx, nil, SpatialInfoInput{Database: q.Get("database"), Table: q.Get("table")})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpSchemaGraph handles GET /api/schema-graph?database=xxx&format=dot|mermaid&include_isolated=true
+func httpSchemaGraph(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := SchemaGraphInput{
+		Database:        q.Get("database"),
+		Format:          q.Get("format"),
+		IncludeIsolated: queryFlag(r, "include_isolated"),
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolSchemaGraphWrapped(ctx, nil, input)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "format must be") {
+			api.WriteBadRequest(w, err.Error())
+			return
+		}
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpDataDictionary handles GET /api/data-dictionary?database=xxx&pattern=yyy&offset=0&limit=10
+func httpDataDictionary(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := GenerateDataDictionaryInput{Database: q.Get("database"), Pattern: q.Get("pattern")}
+	if !queryInts(w, r, map[string]*int{"offset": &input.Offset, "limit": &input.Limit}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolDataDictionaryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpFindColumns handles GET /api/columns?pattern=%email%&type=varchar&database=xxx&exclude=a,b&include_system=1&limit=100
+func httpFindColumns(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := FindColumnsInput{
+		Pattern:       q.Get("pattern"),
+		DataType:      q.Get("type"),
+		Database:      q.Get("database"),
+		IncludeSystem: queryFlag(r, "include_system"),
+	}
+	for _, name := range strings.Split(q.Get("exclude"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			input.ExcludeDatabases = append(input.ExcludeDatabases, name)
+		}
+	}
+	if input.Pattern == "" && input.DataType == "" {
+		api.WriteBadRequest(w, "pattern or type parameter is required")
+		return
+	}
+	if !queryInts(w, r, map[string]*int{"limit": &input.Limit}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolFindColumnsWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpSuggestIdentifiers handles GET /api/suggest?prefix=ord&database=shop&table=&kind=&limit=
+func httpSuggestIdentifiers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := SuggestIdentifiersInput{
+		Prefix:   q.Get("prefix"),
+		Database: q.Get("database"),
+		Table:    q.Get("table"),
+		Kind:     q.Get("kind"),
+	}
+	if !queryInts(w, r, map[string]*int{"limit": &input.Limit}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolSuggestIdentsWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListStatus handles GET /api/status?pattern=xxx&offset=0&limit=50&sort=-value&filter=value:gt:0 (all optional)
+func httpListStatus(w http.ResponseWriter, r *http.Request) {
+	input := ListStatusInput{Pattern: r.URL.Query().Get("pattern")}
+	if !listQueryParams(w, r, variableListFields, &input.Offset, &input.Limit, &input.Sort, &input.Filter) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListStatusWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccessPage(w, out, listPage(input.Offset, input.Limit, out.Total, out.NextOffset))
+}
+
+// httpListVariables handles GET /api/variables?pattern=xxx&offset=0&limit=50&sort=name&filter=value:eq:ON (all optional)
+func httpListVariables(w http.ResponseWriter, r *http.Request) {
+	input := ListVariablesInput{Pattern: r.URL.Query().Get("pattern")}
+	if !listQueryParams(w, r, variableListFields, &input.Offset, &input.Limit, &input.Sort, &input.Filter) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListVariablesWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccessPage(w, out, listPage(input.Offset, input.Limit, out.Total, out.NextOffset))
+}
+
+// httpHealthReport handles GET /api/health-report?top_waits=5
+func httpHealthReport(w http.ResponseWriter, r *http.Request) {
+	var n int
+	if s := r.URL.Query().Get("top_waits"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil {
+			api.WriteBadRequest(w, "invalid top_waits parameter")
+			return
+		}
+		if n <= 0 {
+			api.WriteBadRequest(w, "top_waits must be a positive integer")
+			return
+		}
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolHealthReportWrapped(ctx, nil, HealthReportInput{TopWaits: n})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpResourceUsage handles GET /api/resource-usage?top_memory=10
+func httpResourceUsage(w http.ResponseWriter, r *http.Request) {
+	var n int
+	if s := r.URL.Query().Get("top_memory"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil {
+			api.WriteBadRequest(w, "invalid top_memory parameter")
+			return
+		}
+		if n <= 0 {
+			api.WriteBadRequest(w, "top_memory must be a positive integer")
+			return
+		}
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolResourceUsageWrapped(ctx, nil, ResourceUsageInput{TopMemory: n})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpBinlogStatus handles GET /api/binlog?max_files=50
+func httpBinlogStatus(w http.ResponseWriter, r *http.Request) {
+	var input BinlogStatusInput
+	if !queryInts(w, r, map[string]*int{"max_files": &input.MaxFiles}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolBinlogStatusWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpShowGrants handles GET /api/grants?database=xxx (database optional)
+func httpShowGrants(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolShowGrantsWrapped(ctx, nil, ShowGrantsInput{Database: r.URL.Query().Get("database")})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpMetricsHistory handles GET /api/metrics/history?window_minutes=15&include_samples=1
+func httpMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	var n int
+	if s := r.URL.Query().Get("window_minutes"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil {
+			api.WriteBadRequest(w, "invalid window_minutes parameter")
+			return
+		}
+		if n <= 0 {
+			api.WriteBadRequest(w, "window_minutes must be a positive integer")
+			return
+		}
+	}
+	include := r.URL.Query().Get("include_samples") == "1" || strings.EqualFold(r.URL.Query().Get("include_samples"), "true")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolMetricsHistoryWrapped(ctx, nil, MetricsHistoryInput{WindowMinutes: n, IncludeSamples: include})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpProcessList handles GET /api/processlist
+func httpProcessList(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolProcessListWrapped(ctx, nil, ProcessListInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpListSessions handles GET /api/sessions?include_other_users=1&user=&command=&exclude_sleeping=1
+func httpListSessions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := ListSessionsInput{
+		IncludeOtherUsers: queryFlag(r, "include_other_users"),
+		User:              q.Get("user"),
+		Command:           q.Get("command"),
+		ExcludeSleeping:   queryFlag(r, "exclude_sleeping"),
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolListSessionsWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpKillQuery handles POST /api/kill body {"id": 123} (KILL QUERY).
+func httpKillQuery(w http.ResponseWriter, r *http.Request) {
+	var input KillQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.ID <= 0 {
+		api.WriteBadRequest(w, "id must be a positive integer")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolKillQueryWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpReadAuditLog handles GET /api/audit-log?lines=50
+func httpReadAuditLog(w http.ResponseWriter, r *http.Request) {
+	var n int
+	if s := r.URL.Query().Get("lines"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil {
+			api.WriteBadRequest(w, "invalid lines parameter")
+			return
+		}
+		if n <= 0 {
+			api.WriteBadRequest(w, "lines must be a positive integer")
+			return
+		}
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolReadAuditLogWrapped(ctx, nil, ReadAuditLogInput{Lines: n})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpSlowQueryLog handles GET /api/slow-log?limit=20
+func httpSlowQueryLog(w http.ResponseWriter, r *http.Request) {
+	var n int
+	if s := r.URL.Query().Get("limit"); s != "" {
+		var err error
+		n, err = strconv.Atoi(s)
+		if err != nil {
+			api.WriteBadRequest(w, "invalid limit parameter")
+			return
+		}
+		if n <= 0 {
+			api.WriteBadRequest(w, "limit must be a positive integer")
+			return
+		}
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolSlowQueryLogWrapped(ctx, nil, SlowQueryLogInput{Limit: n})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// ===== Vector HTTP Handlers =====
+
+// httpProfileColumn handles GET /api/profile?database=xxx&table=yyy&column=zzz&top_k=10&sample_size=100000
+func httpProfileColumn(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := ProfileColumnInput{Database: q.Get("database"), Table: q.Get("table"), Column: q.Get("column")}
+	if !queryInts(w, r, map[string]*int{"top_k": &input.TopK, "sample_size": &input.SampleSize}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolProfileColumnWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpPIIScan handles GET /api/pii_scan?database=xxx&table=yyy&sample_size=1000
+func httpPIIScan(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	input := PIIScanInput{Database: q.Get("database"), Table: q.Get("table")}
+	if !queryInts(w, r, map[string]*int{"sample_size": &input.SampleSize}) {
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolPIIScanWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpHeatWaveStatus handles GET /api/heatwave/status?database=xxx
+func httpHeatWaveStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolHeatWaveStatusWrapped(ctx, nil, HeatWaveStatusInput{Database: r.URL.Query().Get("database")})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpAuroraTopology handles GET /api/aurora/topology
+func httpAuroraTopology(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolAuroraTopologyWrapped(ctx, nil, AuroraTopologyInput{})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpHeatWaveMLPredict handles POST /api/heatwave/predict (body: HeatWaveMLPredictInput)
+func httpHeatWaveMLPredict(w http.ResponseWriter, r *http.Request) {
+	var input HeatWaveMLPredictInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolHeatWavePredictWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpFulltextSearch handles POST /api/fulltext/search
+func httpFulltextSearch(w http.ResponseWriter, r *http.Request) {
+	var input FulltextSearchInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.Database == "" || input.Table == "" || strings.TrimSpace(input.Query) == "" {
+		api.WriteBadRequest(w, "database, table and query are required")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolFulltextSearchWrapped(ctx, nil, input)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "mode must be") {
+			api.WriteBadRequest(w, err.Error())
+			return
+		}
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpVectorSearch handles POST /api/vector/search
+func httpVectorSearch(w http.ResponseWriter, r *http.Request) {
+	var input VectorSearchInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolVectorSearchWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpVectorInfo handles GET /api/vector/info?database=xxx
+func httpVectorInfo(w http.ResponseWriter, r *http.Request) {
+	database := r.URL.Query().Get("database")
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolVectorInfoWrapped(ctx, nil, VectorInfoInput{Database: database})
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpVectorInsert handles POST /api/vector/insert (body: VectorInsertInput)
+func httpVectorInsert(w http.ResponseWriter, r *http.Request) {
+	var input VectorInsertInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolVectorInsertWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// httpVectorDelete handles POST /api/vector/delete (body: VectorDeleteInput)
+func httpVectorDelete(w http.ResponseWriter, r *http.Request) {
+	var input VectorDeleteInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+	_, out, err := toolVectorDeleteWrapped(ctx, nil, input)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+	api.WriteSuccess(w, out)
+}
+
+// ===== Utility HTTP Handlers =====
+
+// httpHealth handles GET /health
+func httpHealth(w http.ResponseWriter, r *http.Request) {
+	api.WriteSuccess(w, map[string]interface{}{
+		"status":  "healthy",
+		"service": "mysql-mcp-server",
+	})
+}
+
+// httpAPIIndex handles GET /api
+func httpAPIIndex(w http.ResponseWriter, r *http.Request) {
+	endpoints := map[string]string{
+		"GET  /health":                "Health check",
+		"GET  /ready":                 "Readiness (pings connections; 503 when not ready)",
+		"GET  /api":                   "API index (this page)",
+		"GET  /api/databases":         "List databases (optional ?prefix=, &offset=, &limit=)",
+		"GET  /api/tables":            "List tables (requires ?database=, optional &pattern=, &prefix=, &include_metadata=1, &offset=, &limit=)",
+		"GET  /api/describe":          "Describe table (requires ?database=&table=)",
+		"POST /api/query":             "Run SQL query (body: {sql, database?, max_rows?})",
+		"POST /api/query/stream":      "Run SQL query, streaming rows as NDJSON (body: {sql, database?, max_rows?})",
+		"POST /api/jobs":              "Start an async query job; answers 202 with its id (body of /api/query)",
+		"GET  /api/jobs":              "List your query jobs",
+		"GET  /api/jobs/{id}":         "Job status, with the result once it succeeded",
+		"DELETE /api/jobs/{id}":       "Cancel a running job or discard a finished one",
+		"POST /api/validate":          "Dry-run validation + EXPLAIN without executing (body: {sql, database?})",
+		"POST /api/validate/rules":    "Every validation rule a query breaks, with hints (body: {sql, database?})",
+		"POST /api/cell":              "Read a truncated cell in full or by byte range (body: {handle, offset?, length?, encoding?})",
+		"GET  /api/ping":              "Ping database",
+		"GET  /api/server-info":       "Get server info (optional ?detailed=1 for health metrics)",
+		"GET  /api/connections":       "List connections",
+		"POST /api/connections/use":   "Switch connection (body: {name})",
+		"GET  /api/pool":              "Connection pool statistics per connection",
+		"GET  /api/metrics/tokens":    "Live token usage metrics (cumulative since startup)",
+		"GET  /api/stats":             "Per-tool call counts, error rates, p50/p95 latency and rows returned (optional ?tool=)",
+		"GET  /metrics":               "Tool usage in the Prometheus text format",
+		"GET  /api/saved-queries":     "List saved queries",
+		"POST /api/saved-queries/run": "Run a saved query (body: {name, params?, database?, max_rows?})",
+		"GET  /api/reports":           "List report templates",
+		"POST /api/reports/run":       "Run a report template (body: {name, variables?, database?, max_rows?})",
+	}
+	if cfg.SaveQueryTool {
+		endpoints["POST /api/saved-queries/save"] = "Register a saved query (body: {name, sql, description?, database?, params?}) [MYSQL_MCP_SAVE_QUERY_TOOL]"
+	}
+	if cfg.BookmarksFile != "" {
+		endpoints["GET  /api/bookmarks"] = "List query bookmarks [MYSQL_HTTP_BOOKMARKS_FILE]"
+		endpoints["POST /api/bookmarks"] = "Save a query bookmark (body: {name, sql, description?, database?, params?}) [MYSQL_HTTP_BOOKMARKS_FILE]"
+		endpoints["DELETE /api/bookmarks"] = "Delete a query bookmark (requires ?name=) [MYSQL_HTTP_BOOKMARKS_FILE]"
+		endpoints["GET  /api/bookmarks/run"] = "Run a query bookmark (requires ?name=; optional database, max_rows, time_zone; other parameters are bookmark params) [MYSQL_HTTP_BOOKMARKS_FILE]"
+	}
+	if toolGroupEnabled(toolGroupExtended) {
+		endpoints["GET  /api/indexes"] = "List indexes (requires ?database=&table=) [extended]"
+		endpoints["GET  /api/create-table"] = "Show CREATE TABLE (requires ?database=&table=) [extended]"
+		endpoints["POST /api/explain"] = "Explain query (body: {sql, database?}) [extended]"
+		endpoints["POST /api/explain/partitions"] = "Partition pruning check (body: {sql, database?}) [extended]"
+		endpoints["POST /api/estimate"] = "Row-count estimate (body: {sql?, database?, table?}) [extended]"
+		endpoints["POST /api/table-counts"] = "Row counts for several tables, estimated or exact in one snapshot (body: {database, tables, mode?}) [extended]"
+		endpoints["POST /api/explain/trace"] = "Optimizer trace (body: {sql, database?, max_bytes?}) [extended]"
+		endpoints["POST /api/normalize"] = "Query fingerprint/digest and referenced objects (body: {sql}) [extended]"
+		endpoints["GET  /api/views"] = "List views (requires ?database=) [extended]"
+		endpoints["GET  /api/view-lineage"] = "Source table columns of each view column (requires ?database=, optional &view=) [extended]"
+		endpoints["GET  /api/ghost-tables"] = "Leftover gh-ost / pt-osc / LHM tables and triggers (requires ?database=) [extended]"
+		endpoints["GET  /api/summary-tables"] = "Rollup / materialized summary tables with source and freshness (requires ?database=, optional &table=) [extended]"
+		endpoints["GET  /api/data-freshness"] = "Newest timestamp per table from freshness.tables or UPDATE_TIME (requires ?database=, optional &table=, &refresh=true) [extended]"
+		endpoints["GET  /api/triggers"] = "List triggers (requires ?database=) [extended]"
+		endpoints["GET  /api/procedures"] = "List procedures (requires ?database=) [extended]"
+		endpoints["GET  /api/functions"] = "List functions (requires ?database=) [extended]"
+		endpoints["GET  /api/partitions"] = "List table partitions (requires ?database=&table=) [extended]"
+		endpoints["GET  /api/size/database"] = "Database size (optional ?database=) [extended]"
+		endpoints["GET  /api/size/tables"] = "Table sizes (requires ?database=) [extended]"
+		endpoints["GET  /api/foreign-keys"] = "Foreign keys (requires ?database=, optional &table=) [extended]"
+		endpoints["GET  /api/constraints"] = "Table constraints incl. CHECK (requires ?database=&table=) [extended]"
+		endpoints["GET  /api/spatial"] = "Spatial columns, SRIDs and SPATIAL indexes (requires ?database=, optional &table=) [extended]"
+		endpoints["GET  /api/data-dictionary"] = "Data dictionary (requires ?database=, optional &pattern=, &offset=, &limit=) [extended]"
+		endpoints["GET  /api/schema-graph"] = "Foreign key graph (requires ?database=, optional &format=dot|mermaid, &include_isolated=true) [extended]"
+		endpoints["GET  /api/profile"] = "Column profile (requires ?database=&table=&column=, optional &top_k=, &sample_size=) [extended]"
+		endpoints["GET  /api/pii_scan"] = "Flag likely PII columns of a table (requires ?database=&table=, optional &sample_size=) [extended]"
+		endpoints["GET  /api/heatwave/status"] = "HeatWave cluster and table load status (optional ?database=) [extended, HeatWave only]"
+		endpoints["POST /api/heatwave/predict"] = "Score rows with a HeatWave AutoML model (JSON body: model_handle, rows or database/table/columns) [extended, HeatWave only]"
+		endpoints["GET  /api/aurora/topology"] = "Aurora writer/reader instances, endpoints and replica lag [extended, Aurora only]"
+		endpoints["POST /api/fulltext/search"] = "FULLTEXT MATCH ... AGAINST search (JSON body: database, table, query, optional columns, mode) [extended]"
+		endpoints["GET  /api/columns"] = "Find columns (requires ?pattern= or ?type=, optional &database=, &exclude=a,b, &include_system=1, &limit=) [extended]"
+		endpoints["GET  /api/suggest"] = "Complete or correct a database, table or column name (?prefix=, optional &database=, &table=, &kind=, &limit=) [extended]"
+		endpoints["GET  /api/status"] = "Server status (optional ?pattern=) [extended]"
+		endpoints["GET  /api/variables"] = "Server variables (optional ?pattern=) [extended]"
+		endpoints["GET  /api/health-report"] = "Server health summary with severity flags (optional ?top_waits=) [extended]"
+		endpoints["GET  /api/resource-usage"] = "Memory consumers and temp table, sort and join spill counters with severity flags (optional ?top_memory=) [extended]"
+		endpoints["GET  /api/grants"] = "Current account privileges (optional ?database=) [extended]"
+		endpoints["GET  /api/binlog"] = "Binary log files, GTID sets and retention (optional ?max_files=) [extended]"
+		if cfg.ProcessAdmin {
+			endpoints["GET  /api/processlist"] = "Active threads [extended + MYSQL_MCP_PROCESS_ADMIN]"
+			endpoints["POST /api/kill"] = "KILL QUERY for thread id (body: {id}) [extended + admin]"
+		}
+		if globalMetricsSampler != nil {
+			endpoints["GET  /api/metrics/history"] = "Status counter deltas/rates over a window (optional ?window_minutes=&include_samples=1) [extended + MYSQL_MCP_METRICS_SAMPLE_SECONDS]"
+		}
+		if cfg.SessionsTool {
+			endpoints["GET  /api/sessions"] = "Sanitized read-only sessions (optional ?include_other_users=1&user=&command=&exclude_sleeping=1) [extended + MYSQL_MCP_SESSIONS_TOOL]"
+		}
+		readAuditOK := cfg.ReadAuditTool && auditLogger != nil && auditLogger.enabled && cfg.AuditLogPath != ""
+		if readAuditOK {
+			endpoints["GET  /api/audit-log"] = "Tail audit log (optional ?lines=) [extended + MYSQL_MCP_READ_AUDIT_TOOL]"
+		}
+		if cfg.SlowQueryTool {
+			endpoints["GET  /api/slow-log"] = "Slow query log rows or settings [extended + MYSQL_MCP_SLOW_QUERY_TOOL]"
+		}
+	}
+	if cfg.VectorMode {
+		endpoints["POST /api/vector/search"] = "Vector search (body: {...}) [vector]"
+		endpoints["GET  /api/vector/info"] = "Vector info (requires ?database=) [vector]"
+		if cfg.VectorWrite {
+			endpoints["POST /api/vector/insert"] = "Insert or upsert embeddings (body: {...}) [vector + MYSQL_MCP_VECTOR_WRITE]"
+			endpoints["POST /api/vector/delete"] = "Delete embedding rows by key (body: {...}) [vector + MYSQL_MCP_VECTOR_WRITE]"
+		}
+	}
+	if tokenCard {
+		endpoints["GET  /status"] = "Token Tracking Card live dashboard [token-card]"
+	}
+	response := map[string]interface{}{
+		"service":   "mysql-mcp-server REST API",
+		"version":   Version,
+		"endpoints": endpoints,
+		"modes": map[string]bool{
+			"extended":   extendedMode,
+			"vector":     os.Getenv("MYSQL_MCP_VECTOR") == "1",
+			"token_card": tokenCard,
+		},
+	}
+	api.WriteSuccess(w, response)
+}
+
+// ===== Token Metrics HTTP Handlers =====
+
+// httpMetricsTokens handles GET /api/metrics/tokens
+// Returns cumulative token usage since server startup.
+// Always available in HTTP mode; returns zeros when token tracking is disabled.
+func httpMetricsTokens(w http.ResponseWriter, r *http.Request) {
+	api.WriteSuccess(w, globalTokenMetrics.Snapshot())
+}
+
+// httpStatusPage handles GET /status
+// Serves a live-updating HTML dashboard (the Token Tracking Card).
+func httpStatusPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(tokenCardHTML))
+}
+
+// tokenCardHTML is the embedded HTML for the live Token Tracking Card dashboard.
+// It auto-refreshes every 3 seconds via a JavaScript fetch of /api/metrics/tokens.
+const tokenCardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<title>MySQL MCP Server – Token Tracking</title>
+<style>
+  :root {
+    --bg: #0f172a; --card: #1e293b; --border: #334155;
+    --text: #f1f5f9; --muted: #94a3b8; --accent: #38bdf8;
+    --green: #4ade80; --yellow: #facc15; --red: #f87171;
+  }
+  * { box-sizing: border-box; margin: 0; padding: 0; }
+  body { background: var(--bg); color: var(--text); font-family: 'Segoe UI', system-ui, sans-serif; padding: 2rem; }
+  h1 { font-size: 1.5rem; font-weight: 700; color: var(--accent); margin-bottom: 0.25rem; }
+  .subtitle { color: var(--muted); font-size: 0.875rem; margin-bottom: 2rem; }
+  .grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(180px, 1fr)); gap: 1rem; margin-bottom: 2rem; }
+  .card { background: var(--card); border: 1px solid var(--border); border-radius: 0.75rem; padding: 1.25rem; }
+  .card .label { font-size: 0.75rem; color: var(--muted); text-transform: uppercase; letter-spacing: 0.05em; margin-bottom: 0.5rem; }
+  .card .value { font-size: 1.75rem; font-weight: 700; color: var(--text); }
+  .card .sub { font-size: 0.75rem; color: var(--muted); margin-top: 0.25rem; }
+  .badge { display: inline-block; padding: 0.2em 0.6em; border-radius: 9999px; font-size: 0.75rem; font-weight: 600; }
+  .badge-on  { background: rgba(74,222,128,.15); color: var(--green); border: 1px solid rgba(74,222,128,.3); }
+  .badge-off { background: rgba(248,113,113,.15); color: var(--red);   border: 1px solid rgba(248,113,113,.3); }
+  .section-title { font-size: 1rem; font-weight: 600; color: var(--accent); margin-bottom: 0.75rem; }
+  table { width: 100%; border-collapse: collapse; background: var(--card); border: 1px solid var(--border); border-radius: 0.75rem; overflow: hidden; }
+  th { background: #0f172a; color: var(--muted); font-size: 0.75rem; text-transform: uppercase; letter-spacing: 0.05em; padding: 0.75rem 1rem; text-align: left; }
+  td { padding: 0.7rem 1rem; border-top: 1px solid var(--border); font-size: 0.875rem; }
+  tr:hover td { background: rgba(56,189,248,.05); }
+  .ts { color: var(--muted); font-size: 0.75rem; }
+  .footer { margin-top: 1.5rem; color: var(--muted); font-size: 0.75rem; display: flex; align-items: center; gap: 0.5rem; }
+  .dot { width: 8px; height: 8px; border-radius: 50%; background: var(--green); animation: pulse 1.5s ease-in-out infinite; }
+  @keyframes pulse { 0%,100%{opacity:1} 50%{opacity:.4} }
+  .warn { color: var(--yellow); font-size: 0.875rem; padding: 1rem; background: rgba(250,204,21,.08); border: 1px solid rgba(250,204,21,.2); border-radius: 0.5rem; margin-bottom: 1.5rem; }
+</style>
+</head>
+<body>
+<h1>⚡ MySQL MCP Server – Token Tracking Card</h1>
+<p class="subtitle">Live cumulative token usage since server startup &mdash; refreshes every 3 s</p>
+
+<div id="warn-box" class="warn" style="display:none">
+  ⚠️ Token tracking is disabled. Enable it with <code>MYSQL_MCP_TOKEN_TRACKING=1</code>
+  or <code>logging.token_tracking: true</code> in your config file to see live data.
+</div>
+
+<div class="grid" id="metrics-grid">
+  <div class="card"><div class="label">Input Tokens</div><div class="value" id="v-in">–</div><div class="sub">cumulative</div></div>
+  <div class="card"><div class="label">Output Tokens</div><div class="value" id="v-out">–</div><div class="sub">cumulative</div></div>
+  <div class="card"><div class="label">Total Tokens</div><div class="value" id="v-tot">–</div><div class="sub">cumulative</div></div>
+  <div class="card"><div class="label">Est. Cost (USD)</div><div class="value" id="v-cost">–</div><div class="sub">GPT-4o pricing</div></div>
+  <div class="card"><div class="label">Queries</div><div class="value" id="v-qc">–</div><div class="sub">tool calls tracked</div></div>
+  <div class="card"><div class="label">IO Efficiency</div><div class="value" id="v-eff">–</div><div class="sub">output / input ratio</div></div>
+  <div class="card"><div class="label">Uptime</div><div class="value" id="v-up">–</div><div class="sub">since server start</div></div>
+  <div class="card"><div class="label">Token Tracking</div><div class="value"><span id="v-status" class="badge">–</span></div><div class="sub">feature status</div></div>
+</div>
+
+<p class="section-title">Recent Queries (last 5)</p>
+<table id="recent-table">
+  <thead><tr><th>Tool</th><th>Input</th><th>Output</th><th>Total</th><th>Cost (USD)</th><th>Time</th></tr></thead>
+  <tbody id="recent-body"><tr><td colspan="6" style="color:var(--muted);text-align:center">Loading…</td></tr></tbody>
+</table>
+
+<div class="footer"><div class="dot"></div><span id="last-update">Connecting…</span></div>
+
+<script>
+function fmt(n){return n===undefined?'–':n.toLocaleString();}
+function fmtCost(n){return n===undefined?'–':'$'+n.toFixed(6);}
+function fmtDur(s){if(s<60)return s+'s';if(s<3600)return Math.floor(s/60)+'m '+s%60+'s';return Math.floor(s/3600)+'h '+Math.floor((s%3600)/60)+'m';}
+function fmtTime(iso){try{return new Date(iso).toLocaleTimeString();}catch{return iso;}}
+
+async function refresh(){
+  try{
+    const r=await fetch('/api/metrics/tokens');
+    if(!r.ok)throw new Error('HTTP '+r.status);
+    const d=(await r.json()).data;
+    document.getElementById('v-in').textContent=fmt(d.total_input_tokens);
+    document.getElementById('v-out').textContent=fmt(d.total_output_tokens);
+    document.getElementById('v-tot').textContent=fmt(d.total_tokens);
+    document.getElementById('v-cost').textContent=fmtCost(d.total_cost_usd);
+    document.getElementById('v-qc').textContent=fmt(d.query_count);
+    document.getElementById('v-eff').textContent=d.io_efficiency===undefined?'–':d.io_efficiency.toFixed(2)+'x';
+    document.getElementById('v-up').textContent=fmtDur(d.uptime_seconds);
+    const sb=document.getElementById('v-status');
+    if(d.token_tracking_on){sb.className='badge badge-on';sb.textContent='Enabled';}
+    else{sb.className='badge badge-off';sb.textContent='Disabled';}
+    document.getElementById('warn-box').style.display=d.token_tracking_on?'none':'block';
+    const tbody=document.getElementById('recent-body');
+    const rq=d.recent_queries||[];
+    if(rq.length===0){tbody.innerHTML='<tr><td colspan="6" style="color:var(--muted);text-align:center">No queries recorded yet</td></tr>';}
+    else{tbody.innerHTML=rq.slice().reverse().map(q=>'<tr><td>'+q.tool+'</td><td>'+fmt(q.input_tokens)+'</td><td>'+fmt(q.output_tokens)+'</td><td>'+fmt(q.total_tokens)+'</td><td>'+fmtCost(q.cost_usd)+'</td><td class="ts">'+fmtTime(q.timestamp)+'</td></tr>').join('');}
+    document.getElementById('last-update').textContent='Last updated: '+new Date().toLocaleTimeString();
+  }catch(e){document.getElementById('last-update').textContent='Error: '+e.message;}
+}
+refresh();
+setInterval(refresh,3000);
+</script>
+</body>
+</html>`
+
+// ===== HTTP Server Setup =====
+
+// httpLogger logs HTTP requests using the application's structured logging.
+func httpLogger(method, path string, status int, duration time.Duration) {
+	httpRequestLogger("")(method, path, status, duration)
+}
+
+// startHTTPServer starts the REST API server with graceful shutdown support.
+func startHTTPServer(port int, vectorMode bool, tokenCardEnabled bool) {
+	mux := http.NewServeMux()
+
+	// Create rate limiter if enabled
+	var rateLimiter *api.RateLimiter
+	if cfg.RateLimitEnabled {
+		rateLimiter = api.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)
+		httpLog.Info("rate limiting enabled", map[string]interface{}{
+			"rps":   cfg.RateLimitRPS,
+			"burst": cfg.RateLimitBurst,
+		})
+	}
+
+	withRateLimit := api.WithRateLimit(rateLimiter)
+
+	// Health and index
+	mux.HandleFunc("/health", api.WithCORS(httpHealth))
+	mux.HandleFunc("/ready", api.WithCORS(httpReady))
+	mux.HandleFunc("/api", api.WithCORS(httpAPIIndex))
+	mux.HandleFunc("/api/", api.WithCORS(httpAPIIndex))
+
+	// Token metrics endpoint (always available; returns zeros when token tracking is off)
+	mux.HandleFunc("/api/metrics/tokens", api.WithCORS(httpMetricsTokens))
+
+	// Tool usage statistics, as JSON and for Prometheus
+	mux.HandleFunc("/api/stats", api.WithCORS(httpUsageStats))
+	mux.HandleFunc("/metrics", httpMetricsPrometheus)
+
+	// Token Card status page (only registered when enabled)
+	if tokenCardEnabled {
+		mux.HandleFunc("/status", httpStatusPage)
+		httpLog.Info("token card UI enabled", map[string]interface{}{
+			"url": fmt.Sprintf("http://localhost:%d/status", port),
+		})
+	}
+
+	// Core endpoints
+	mux.HandleFunc("/api/databases", api.WithCORS(httpListDatabases))
+	mux.HandleFunc("/api/tables", api.Chain(httpListTables, api.WithCORS, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/describe", api.Chain(httpDescribeTable, api.WithCORS, api.RequireQueryParams([]string{"database", "table"})))
+	mux.HandleFunc("/api/query", api.Chain(httpRunQuery, api.WithCORS, api.RequirePOST))
+	mux.HandleFunc("/api/query/stream", api.Chain(httpRunQueryStream, api.WithCORS, api.RequirePOST))
+	mux.HandleFunc("/api/jobs", api.Chain(httpJobs, api.WithCORS))
+	mux.HandleFunc("/api/jobs/", api.Chain(httpJob, api.WithCORS))
+	mux.HandleFunc("/api/validate", api.Chain(httpValidateQuery, api.WithCORS, api.RequirePOST))
+	mux.HandleFunc("/api/validate/rules", api.Chain(httpExplainValidation, api.WithCORS, api.RequirePOST))
+	mux.HandleFunc("/api/cell", api.Chain(httpFetchCell, api.WithCORS, api.RequirePOST))
+	mux.HandleFunc("/api/ping", api.WithCORS(httpPing))
+	mux.HandleFunc("/api/server-info", api.WithCORS(httpServerInfo))
+	mux.HandleFunc("/api/connections", api.WithCORS(httpListConnections))
+	mux.HandleFunc("/api/connections/use", api.Chain(httpUseConnection, api.WithCORS, api.RequirePOST))
+	mux.HandleFunc("/api/pool", api.WithCORS(httpPoolStats))
+	mux.HandleFunc("/api/saved-queries", api.WithCORS(httpListSavedQueries))
+	mux.HandleFunc("/api/saved-queries/run", api.Chain(httpRunSavedQuery, api.WithCORS, api.RequirePOST))
+	saveQueryFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(cfg.SaveQueryTool, "save_query (set MYSQL_MCP_SAVE_QUERY_TOOL=1)", next)
+	}
+	mux.HandleFunc("/api/saved-queries/save", api.Chain(httpSaveQuery, api.WithCORS, saveQueryFeature, api.RequirePOST))
+	bookmarksFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(cfg.BookmarksFile != "", "bookmarks (set MYSQL_HTTP_BOOKMARKS_FILE)", next)
+	}
+	mux.HandleFunc("/api/bookmarks", api.Chain(httpBookmarks, api.WithCORS, bookmarksFeature))
+	mux.HandleFunc("/api/bookmarks/run", api.Chain(httpRunBookmark, api.WithCORS, bookmarksFeature, api.RequireGET))
+	mux.HandleFunc("/api/reports", api.WithCORS(httpListReports))
+	mux.HandleFunc("/api/reports/run", api.Chain(httpRunReport, api.WithCORS, api.RequirePOST))
+
+	// Extended endpoints
+	extendedFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(toolGroupEnabled(toolGroupExtended), "extended mode (set MYSQL_MCP_EXTENDED=1 or enable the tool in features.tools)", next)
+	}
+	mux.HandleFunc("/api/indexes", api.Chain(httpListIndexes, api.WithCORS, extendedFeature, api.RequireQueryParams([]string{"database", "table"})))
+	mux.HandleFunc("/api/create-table", api.Chain(httpShowCreateTable, api.WithCORS, extendedFeature, api.RequireQueryParams([]string{"database", "table"})))
+	mux.HandleFunc("/api/explain", api.Chain(httpExplainQuery, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/explain/partitions", api.Chain(httpCheckPartitionPruning, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/estimate", api.Chain(httpEstimateRows, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/table-counts", api.Chain(httpTableCounts, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/explain/trace", api.Chain(httpOptimizerTrace, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/normalize", api.Chain(httpNormalizeQuery, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/views", api.Chain(httpListViews, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/view-lineage", api.Chain(httpViewLineage, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/ghost-tables", api.Chain(httpListGhostTables, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/summary-tables", api.Chain(httpListSummaryTables, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/data-freshness", api.Chain(httpDataFreshness, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/triggers", api.Chain(httpListTriggers, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/procedures", api.Chain(httpListProcedures, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/functions", api.Chain(httpListFunctions, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/partitions", api.Chain(httpListPartitions, api.WithCORS, extendedFeature, api.RequireQueryParam("database"), api.RequireQueryParam("table")))
+	mux.HandleFunc("/api/size/database", api.Chain(httpDatabaseSize, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/size/tables", api.Chain(httpTableSize, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/foreign-keys", api.Chain(httpForeignKeys, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/constraints", api.Chain(httpTableConstraints, api.WithCORS, extendedFeature, api.RequireQueryParam("database"), api.RequireQueryParam("table")))
+	mux.HandleFunc("/api/spatial", api.Chain(httpSpatialInfo, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/data-dictionary", api.Chain(httpDataDictionary, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/schema-graph", api.Chain(httpSchemaGraph, api.WithCORS, extendedFeature, api.RequireQueryParam("database")))
+	mux.HandleFunc("/api/columns", api.Chain(httpFindColumns, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/suggest", api.Chain(httpSuggestIdentifiers, api.WithCORS, extendedFeature, api.RequireGET))
+	mux.HandleFunc("/api/fulltext/search", api.Chain(httpFulltextSearch, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/profile", api.Chain(httpProfileColumn, api.WithCORS, extendedFeature, api.RequireQueryParam("database"), api.RequireQueryParam("table"), api.RequireQueryParam("column")))
+	mux.HandleFunc("/api/pii_scan", api.Chain(httpPIIScan, api.WithCORS, extendedFeature, api.RequireQueryParam("database"), api.RequireQueryParam("table")))
+	mux.HandleFunc("/api/heatwave/status", api.Chain(httpHeatWaveStatus, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/heatwave/predict", api.Chain(httpHeatWaveMLPredict, api.WithCORS, extendedFeature, api.RequirePOST))
+	mux.HandleFunc("/api/aurora/topology", api.Chain(httpAuroraTopology, api.WithCORS, extendedFeature, api.RequireGET))
+	mux.HandleFunc("/api/status", api.Chain(httpListStatus, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/variables", api.Chain(httpListVariables, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/health-report", api.Chain(httpHealthReport, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/resource-usage", api.Chain(httpResourceUsage, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/binlog", api.Chain(httpBinlogStatus, api.WithCORS, extendedFeature))
+	mux.HandleFunc("/api/grants", api.Chain(httpShowGrants, api.WithCORS, extendedFeature))
+
+	processAdminFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(cfg.ProcessAdmin, "process admin tools (set MYSQL_MCP_PROCESS_ADMIN=1)", next)
+	}
+	readAuditFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		ok := cfg.ReadAuditTool && auditLogger != nil && auditLogger.enabled && cfg.AuditLogPath != ""
+		return api.RequireFeature(ok, "read_audit_log (MYSQL_MCP_READ_AUDIT_TOOL=1 and MYSQL_MCP_AUDIT_LOG)", next)
+	}
+	slowQueryFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(cfg.SlowQueryTool, "slow_query_log (set MYSQL_MCP_SLOW_QUERY_TOOL=1)", next)
+	}
+	sessionsFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(cfg.SessionsTool, "list_sessions (set MYSQL_MCP_SESSIONS_TOOL=1)", next)
+	}
+	mux.HandleFunc("/api/processlist", api.Chain(httpProcessList, api.WithCORS, extendedFeature, processAdminFeature))
+	mux.HandleFunc("/api/kill", api.Chain(httpKillQuery, api.WithCORS, extendedFeature, processAdminFeature, api.RequirePOST))
+	metricsHistoryFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(globalMetricsSampler != nil, "metrics_history (set MYSQL_MCP_METRICS_SAMPLE_SECONDS)", next)
+	}
+	mux.HandleFunc("/api/metrics/history", api.Chain(httpMetricsHistory, api.WithCORS, extendedFeature, metricsHistoryFeature))
+	mux.HandleFunc("/api/sessions", api.Chain(httpListSessions, api.WithCORS, extendedFeature, sessionsFeature))
+	mux.HandleFunc("/api/audit-log", api.Chain(httpReadAuditLog, api.WithCORS, extendedFeature, readAuditFeature))
+	mux.HandleFunc("/api/slow-log", api.Chain(httpSlowQueryLog, api.WithCORS, extendedFeature, slowQueryFeature))
+
+	// Vector endpoints
+	vectorFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(vectorMode, "vector mode (set MYSQL_MCP_VECTOR=1)", next)
+	}
+	mux.HandleFunc("/api/vector/search", api.Chain(httpVectorSearch, api.WithCORS, vectorFeature, api.RequirePOST))
+	mux.HandleFunc("/api/vector/info", api.Chain(httpVectorInfo, api.WithCORS, vectorFeature, api.RequireQueryParam("database")))
+	vectorWriteFeature := func(next http.HandlerFunc) http.HandlerFunc {
+		return api.RequireFeature(cfg.VectorWrite, "vector write tools (set MYSQL_MCP_VECTOR_WRITE=1)", next)
+	}
+	mux.HandleFunc("/api/vector/insert", api.Chain(httpVectorInsert, api.WithCORS, vectorFeature, vectorWriteFeature, api.RequirePOST))
+	mux.HandleFunc("/api/vector/delete", api.Chain(httpVectorDelete, api.WithCORS, vectorFeature, vectorWriteFeature, api.RequirePOST))
+
+	addr := fmt.Sprintf(":%d", port)
+
+	// Build handler chain: rate limit -> request ID + logging -> locale -> client identity -> API key role -> quota headers -> compression -> mux
+	var handler http.HandlerFunc = mux.ServeHTTP
+	if cfg.Compression {
+		handler = api.WithCompression(cfg.CompressionMinBytes)(handler)
+	}
+	handler = withQuotaHeaders(handler)
+	handler = withAPIKeyRole(handler)
+	handler = withHTTPClientIdentity(handler)
+	handler = withHTTPLocale(handler)
+	handler = withHTTPRequestID(handler)
+	handler = withRateLimit(handler)
+
+	// Create server with timeouts
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: cfg.HTTPRequestTimeout + 5*time.Second, // Slightly longer than request timeout
+		IdleTimeout:  120 * time.Second,
+	}
+
+	// Channel to listen for shutdown signals
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	// Start server in goroutine
+	go func() {
+		httpLog.Info("HTTP REST API server starting", map[string]interface{}{
+			"port":         port,
+			"address":      "http://localhost" + addr,
+			"extendedMode": extendedMode,
+			"vectorMode":   vectorMode,
+			"version":      Version,
+		})
+
+		httpLog.Info("REST API endpoints", map[string]interface{}{
+			"api":           "http://localhost:" + strconv.Itoa(port) + "/api",
+			"health":        "http://localhost:" + strconv.Itoa(port) + "/health",
+			"token_metrics": "http://localhost:" + strconv.Itoa(port) + "/api/metrics/tokens",
+		})
+		if tokenCardEnabled {
+			httpLog.Info("token card dashboard", map[string]interface{}{
+				"url": "http://localhost:" + strconv.Itoa(port) + "/status",
+			})
+		}
+
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("HTTP server error: %v", err)
+		}
+	}()
+
+	// Wait for shutdown signal
+	<-stop
+	httpLog.Info("Shutdown signal received, stopping server...", nil)
+
+	// Create context with timeout for graceful shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Attempt graceful shutdown
+	if err := server.Shutdown(ctx); err != nil {
+		httpLog.Error("Server shutdown error", map[string]interface{}{"error": err.Error()})
+	} else {
+		httpLog.Info("Server stopped gracefully", nil)
+	}
+
+	// Stop rate limiter cleanup goroutine
+	if rateLimiter != nil {
+		rateLimiter.Stop()
+	}
+}
+
+// startTokenMetricsHTTPServer listens on cfg.HTTPPort for /health, /api/metrics/tokens, /api/stats, /metrics and optionally /status
+// while MCP runs on stdio in the same process (e.g. Claude Desktop). Set MYSQL_MCP_METRICS_HTTP=1.
+// Does not serve the full REST API; use MYSQL_MCP_HTTP=1 for that (exclusive).
+func startTokenMetricsHTTPServer(port int, tokenCardEnabled bool) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", api.WithCORS(httpHealth))
+	mux.HandleFunc("/ready", api.WithCORS(httpReady))
+	mux.HandleFunc("/api/metrics/tokens", api.WithCORS(httpMetricsTokens))
+	mux.HandleFunc("/api/stats", api.WithCORS(httpUsageStats))
+	mux.HandleFunc("/metrics", httpMetricsPrometheus)
+	if tokenCardEnabled {
+		mux.HandleFunc("/status", httpStatusPage)
+	}
+
+	index := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api" && r.URL.Path != "/api/" {
+			http.NotFound(w, r)
+			return
+		}
+		endpoints := map[string]string{
+			"GET  /health":             "Health check",
+			"GET  /ready":              "Readiness (pings connections; 503 when not ready)",
+			"GET  /api":                "This index (metrics-only; MCP uses stdio)",
+			"GET  /api/metrics/tokens": "Token usage (same process as MCP)",
+			"GET  /api/stats":          "Tool usage statistics (same process as MCP)",
+			"GET  /metrics":            "Tool usage in the Prometheus text format",
+		}
+		if tokenCardEnabled {
+			endpoints["GET  /status"] = "Token Tracking Card dashboard"
+		}
+		api.WriteSuccess(w, map[string]interface{}{
+			"service":     "mysql-mcp-server",
+			"mode":        "stdio_mcp_with_metrics_http",
+			"version":     Version,
+			"description": "HTTP exposes token metrics and tool usage; MCP protocol uses stdin/stdout.",
+			"endpoints":   endpoints,
+		})
+	}
+	mux.HandleFunc("/api", api.WithCORS(index))
+	mux.HandleFunc("/api/", api.WithCORS(index))
+
+	addr := ":" + strconv.Itoa(port)
+	handler := withHTTPRequestID(mux.ServeHTTP)
+
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+	}
+
+	httpLog.Info("token metrics HTTP sidecar (stdio MCP)", map[string]interface{}{
+		"address":    "http://127.0.0.1" + addr,
+		"http_port":  port,
+		"token_card": tokenCardEnabled,
+	})
+	if tokenCardEnabled {
+		httpLog.Info("token dashboard URL (same process as MCP)", map[string]interface{}{
+			"url": fmt.Sprintf("http://127.0.0.1:%d/status", port),
+		})
+	}
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		httpLog.Error("token metrics HTTP sidecar failed", map[string]interface{}{"error": err.Error(), "port": port})
+	}
+}
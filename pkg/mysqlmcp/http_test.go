@@ -1,5 +1,5 @@
-// cmd/mysql-mcp-server/http_test.go
-package main
+// pkg/mysqlmcp/http_test.go
+package mysqlmcp
 
 import (
 	"bytes"
@@ -48,7 +48,7 @@ func setupHTTPTestFull(t *testing.T) httpMockResult {
 	// Set up mock connection manager with mock DB
 	cm := NewConnectionManager()
 	cm.connections["mock"] = mockDB
-	cm.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: "mock://test"}
+	cm.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: mockDSN}
 	cm.activeConn = "mock"
 	connManager = cm
 
@@ -198,7 +198,7 @@ func TestHTTPListTables(t *testing.T) {
 		AddRow("users", "InnoDB", 100, "").
 		AddRow("orders", "InnoDB", 200, "")
 	mock.ExpectQuery(`(?s)SELECT\s+TABLE_NAME\s*,\s*ENGINE\s*,\s*TABLE_ROWS\s*,\s*TABLE_COMMENT\s+FROM\s+information_schema\.TABLES\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+ORDER\s+BY\s+TABLE_NAME`).
-		WithArgs("testdb").
+		WithArgs("testdb", 1001, 0).
 		WillReturnRows(rows)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/tables?database=testdb", nil)
@@ -216,16 +216,32 @@ func TestHTTPListTables(t *testing.T) {
 	}
 }
 
+// TestHTTPListTablesInvalidOffset tests that a malformed offset is rejected
+func TestHTTPListTablesInvalidOffset(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tables?database=testdb&offset=abc", nil)
+	w := httptest.NewRecorder()
+
+	httpListTables(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
+	}
+}
+
 // TestHTTPDescribeTable tests the /api/describe endpoint
 func TestHTTPDescribeTable(t *testing.T) {
 	mock, cleanup := setupHTTPTest(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"}).
-		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil).
-		AddRow("name", "varchar(255)", "NO", "", nil, "", "", "utf8mb4_general_ci")
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil, nil).
+		AddRow("name", "varchar(255)", "NO", "", nil, "", "", "utf8mb4_general_ci", nil)
 
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("testdb", "users").
 		WillReturnRows(rows)
 
@@ -244,18 +260,52 @@ func TestHTTPDescribeTable(t *testing.T) {
 	}
 }
 
+// TestHTTPDescribeTableETag tests conditional requests on /api/describe
+func TestHTTPDescribeTableETag(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	describe := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+			AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil, nil)
+		mock.ExpectQuery(`information_schema\.COLUMNS`).WithArgs("testdb", "users").WillReturnRows(rows)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/describe?database=testdb&table=users", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		httpDescribeTable(w, req)
+		return w
+	}
+
+	first := describe("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", first.Code, etag)
+	}
+	second := describe(etag)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 {
+		t.Errorf("expected an empty 304 for an unchanged table, got %d", second.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 // TestHTTPDescribeTableWithNullCollation tests /api/describe with NULL collation values
 func TestHTTPDescribeTableWithNullCollation(t *testing.T) {
 	mock, cleanup := setupHTTPTest(t)
 	defer cleanup()
 
 	// MySQL 8.4+ returns NULL for Collation on non-string columns (int, timestamp, etc.)
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"}).
-		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil).
-		AddRow("created_at", "timestamp", "YES", "", nil, "", "", nil).
-		AddRow("name", "varchar(255)", "NO", "", nil, "", "User name", "utf8mb4_general_ci")
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil, nil).
+		AddRow("created_at", "timestamp", "YES", "", nil, "", "", nil, nil).
+		AddRow("name", "varchar(255)", "NO", "", nil, "", "User name", "utf8mb4_general_ci", nil)
 
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("testdb", "users").
 		WillReturnRows(rows)
 
@@ -877,9 +927,9 @@ func TestHTTPListProceduresMissingDatabase(t *testing.T) {
 	httpListProcedures(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -918,9 +968,9 @@ func TestHTTPListFunctionsMissingDatabase(t *testing.T) {
 	httpListFunctions(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1000,9 +1050,9 @@ func TestHTTPVectorInfoMissingParams(t *testing.T) {
 	httpVectorInfo(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when params are missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when params are missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1017,9 +1067,9 @@ func TestHTTPListViewsMissingDatabase(t *testing.T) {
 	httpListViews(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1034,9 +1084,9 @@ func TestHTTPListTriggersMissingDatabase(t *testing.T) {
 	httpListTriggers(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1051,9 +1101,9 @@ func TestHTTPListIndexesMissingParams(t *testing.T) {
 	httpListIndexes(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when params are missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when params are missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1068,9 +1118,9 @@ func TestHTTPShowCreateTableMissingParams(t *testing.T) {
 	httpShowCreateTable(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when params are missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when params are missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1085,9 +1135,9 @@ func TestHTTPPartitionsMissingParams(t *testing.T) {
 	httpListPartitions(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when params are missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when params are missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1123,9 +1173,9 @@ func TestHTTPTableSizeMissingParam(t *testing.T) {
 	httpTableSize(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1140,9 +1190,9 @@ func TestHTTPForeignKeysMissingParam(t *testing.T) {
 	httpForeignKeys(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1157,9 +1207,9 @@ func TestHTTPListTablesMissingParam(t *testing.T) {
 	httpListTables(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when database is missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when database is missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
 
@@ -1174,8 +1224,8 @@ func TestHTTPDescribeTableMissingParams(t *testing.T) {
 	httpDescribeTable(w, req)
 
 	resp := w.Result()
-	// Returns 500 internal error when params are missing (tool returns error)
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	// Returns 400 when params are missing (rejected by input validation)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", resp.StatusCode)
 	}
 }
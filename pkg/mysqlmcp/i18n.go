@@ -0,0 +1,184 @@
+// pkg/mysqlmcp/i18n.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// User-facing validation and access errors can be returned in the caller's
+// language. Messages are written in English with i18nErrorf and translated
+// through messageCatalog, keyed by the English format string; anything not in
+// the catalog (MySQL errors, most handler errors) stays English. The locale
+// comes from the MCP _meta.locale of a call or the HTTP Accept-Language
+// header, falling back to MYSQL_MCP_LOCALE.
+
+// localeMetaKey is the MCP _meta key a client can set to choose the language
+// of error messages for one tool call.
+const localeMetaKey = "locale"
+
+type localeKey struct{}
+
+func withLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// localeFrom returns the locale chosen for the call in ctx, or the configured
+// default.
+func localeFrom(ctx context.Context) string {
+	if ctx != nil {
+		if l, ok := ctx.Value(localeKey{}).(string); ok && l != "" {
+			return l
+		}
+	}
+	if cfg != nil && cfg.Locale != "" {
+		return cfg.Locale
+	}
+	return config.DefaultLocale
+}
+
+// negotiateLocale picks the supported locale a caller prefers from an
+// Accept-Language style list ("de-CH, ja;q=0.8"), or "" when none matches.
+func negotiateLocale(header string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		base, _, _ = strings.Cut(base, "_")
+		if q > 0 && config.ValidLocale(base) {
+			candidates = append(candidates, candidate{base, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// ensureLocale keeps a locale already set by the HTTP layer, otherwise uses
+// the MCP call's _meta.locale when it names a supported language.
+func ensureLocale(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if l, _ := ctx.Value(localeKey{}).(string); l != "" {
+		return ctx
+	}
+	if req != nil && req.Params != nil {
+		if v, ok := req.Params.Meta[localeMetaKey].(string); ok {
+			if l := negotiateLocale(v); l != "" {
+				return withLocale(ctx, l)
+			}
+		}
+	}
+	return ctx
+}
+
+// withHTTPLocale sets the request locale from the Accept-Language header.
+func withHTTPLocale(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if l := negotiateLocale(r.Header.Get("Accept-Language")); l != "" {
+			r = r.WithContext(withLocale(r.Context(), l))
+		}
+		next(w, r)
+	}
+}
+
+// localizer is implemented by errors that can render their message in a
+// supported locale.
+type localizer interface {
+	localize(locale string) string
+}
+
+// localizedError is an error created by i18nErrorf. Error returns English;
+// errors.Is and errors.As see through it like a fmt.Errorf error.
+type localizedError struct {
+	format string
+	args   []interface{}
+	err    error // the English fmt.Errorf rendering
+}
+
+// i18nErrorf is fmt.Errorf for user-facing messages: format is looked up in
+// messageCatalog when the error is shown to a caller with another locale.
+// Arguments that are themselves localizable errors are translated too.
+func i18nErrorf(format string, args ...interface{}) error {
+	return &localizedError{format: format, args: args, err: fmt.Errorf(format, args...)}
+}
+
+func (e *localizedError) Error() string { return e.err.Error() }
+
+func (e *localizedError) Unwrap() error { return errors.Unwrap(e.err) }
+
+func (e *localizedError) localize(locale string) string {
+	return tr(locale, e.format, e.args...)
+}
+
+// tr formats a message in locale, falling back to the English format when the
+// catalog has no translation.
+func tr(locale, format string, args ...interface{}) string {
+	if t, ok := messageCatalog[locale][format]; ok {
+		format = t
+	}
+	if locale != config.LocaleEnglish {
+		translated := make([]interface{}, len(args))
+		for i, a := range args {
+			if l, ok := a.(localizer); ok {
+				a = l.localize(locale)
+			}
+			translated[i] = a
+		}
+		args = translated
+	}
+	return fmt.Sprintf(strings.ReplaceAll(format, "%w", "%v"), args...)
+}
+
+// localizeMessage returns the text of err in locale.
+func localizeMessage(locale string, err error) string {
+	if l, ok := err.(localizer); ok {
+		return l.localize(locale)
+	}
+	return err.Error()
+}
+
+// translatedError carries the translated text of an error while keeping the
+// original in the chain, so HTTP status mapping still recognizes it.
+type translatedError struct {
+	msg string
+	err error
+}
+
+func (e *translatedError) Error() string { return e.msg }
+
+func (e *translatedError) Unwrap() error { return e.err }
+
+// localizeError translates err for the locale of the call in ctx. Only errors
+// that are localizable at the top level are translated; wrapped ones keep
+// their English text.
+func localizeError(ctx context.Context, err error) error {
+	locale := localeFrom(ctx)
+	if err == nil || locale == config.LocaleEnglish {
+		return err
+	}
+	if _, ok := err.(localizer); !ok {
+		return err
+	}
+	return &translatedError{msg: localizeMessage(locale, err), err: err}
+}
@@ -0,0 +1,116 @@
+// pkg/mysqlmcp/i18n_catalog.go
+package mysqlmcp
+
+import "github.com/askdba/mysql-mcp-server/internal/config"
+
+// messageCatalog translates user-facing message formats, keyed by locale and
+// the English format string. A translation must use the same verbs in the
+// same order as the English format (TestMessageCatalogVerbs checks this);
+// %w is rendered like %v. Tool, parameter and setting names stay untranslated.
+var messageCatalog = map[string]map[string]string{
+	config.LocaleGerman: {
+		// Input validation
+		"invalid input: %s":                   "ungültige Eingabe: %s",
+		"%s is required":                      "%s ist erforderlich",
+		"%s is not a valid identifier: %w":    "%s ist kein gültiger Bezeichner: %w",
+		"%s must be one of %s":                "%s muss einer der folgenden Werte sein: %s",
+		"%s must have at least %s characters": "%s muss mindestens %s Zeichen lang sein",
+		"%s must have at most %s characters":  "%s darf höchstens %s Zeichen lang sein",
+		"%s must have at least %s items":      "%s muss mindestens %s Einträge enthalten",
+		"%s must have at most %s items":       "%s darf höchstens %s Einträge enthalten",
+		"%s must be at least %s":              "%s muss mindestens %s sein",
+		"%s must be at most %s":               "%s darf höchstens %s sein",
+
+		// Access control
+		"tool not permitted": "Tool nicht erlaubt",
+		"%w: %s requires a role (set an API key, a client mapping, or rbac.default_role)": "%w: %s erfordert eine Rolle (API-Schlüssel, Client-Zuordnung oder rbac.default_role festlegen)",
+		"%w: role %s may not call %s":                                                                                                 "%w: Rolle %s darf %s nicht aufrufen",
+		"%w: %s is turned off in features.tools":                                                                                      "%w: %s ist in features.tools abgeschaltet",
+		"database is required when MYSQL_MCP_ALLOWED_DATABASES is configured":                                                         "database ist erforderlich, wenn MYSQL_MCP_ALLOWED_DATABASES konfiguriert ist",
+		"database %q is not in MYSQL_MCP_ALLOWED_DATABASES":                                                                           "Datenbank %q ist nicht in MYSQL_MCP_ALLOWED_DATABASES enthalten",
+		"SHOW DATABASES is not allowed when MYSQL_MCP_ALLOWED_DATABASES is set; use the list_databases tool instead":                  "SHOW DATABASES ist nicht erlaubt, wenn MYSQL_MCP_ALLOWED_DATABASES gesetzt ist; verwenden Sie stattdessen das Tool list_databases",
+		"query validation failed: %w":                                                                                                 "Abfrageprüfung fehlgeschlagen: %w",
+		"query references database %q which is not in MYSQL_MCP_ALLOWED_DATABASES":                                                    "Die Abfrage verweist auf die Datenbank %q, die nicht in MYSQL_MCP_ALLOWED_DATABASES enthalten ist",
+		"connection %q is labeled %q and requires confirmation: check that this query should run there, then retry with confirm=true": "Verbindung %q ist als %q gekennzeichnet und erfordert eine Bestätigung: Prüfen Sie, ob diese Abfrage dort laufen soll, und wiederholen Sie sie mit confirm=true",
+
+		"%w (names are case-sensitive on this server: did you mean %s? Set MYSQL_MCP_IDENTIFIER_CASE=catalog to match them automatically)": "%w (Namen sind auf diesem Server case-sensitiv: meinten Sie %s? Mit MYSQL_MCP_IDENTIFIER_CASE=catalog werden sie automatisch zugeordnet)",
+
+		// Load protection
+		"server busy: no query slot on %s (limit %d) within %s; retry shortly":                            "Server ausgelastet: kein freier Abfrageplatz auf %s (Limit %d) innerhalb von %s; bitte gleich erneut versuchen",
+		"server busy: %s is at its limit of %d concurrent queries and %d calls are queued; retry shortly": "Server ausgelastet: %s hat sein Limit von %d gleichzeitigen Abfragen erreicht und %d Aufrufe warten bereits; bitte gleich erneut versuchen",
+		"server busy: %s already runs its limit of %d concurrent queries; retry shortly":                  "Server ausgelastet: %s führt bereits die maximal %d gleichzeitigen Abfragen aus; bitte gleich erneut versuchen",
+		"connection %s unavailable: circuit open after %d consecutive failures (last: %s); retry in %s":   "Verbindung %s nicht verfügbar: Circuit Breaker nach %d aufeinanderfolgenden Fehlern geöffnet (zuletzt: %s); erneuter Versuch in %s",
+		"quota exceeded: %s has received its %d rows for today; resets in %s":                             "Kontingent überschritten: %s hat seine %d Zeilen für heute erhalten; Zurücksetzung in %s",
+		"quota exceeded: %s has made its %d queries for this hour; resets in %s":                          "Kontingent überschritten: %s hat seine %d Abfragen für diese Stunde ausgeführt; Zurücksetzung in %s",
+	},
+	config.LocaleJapanese: {
+		// Input validation
+		"invalid input: %s":                   "入力が無効です: %s",
+		"%s is required":                      "%s は必須です",
+		"%s is not a valid identifier: %w":    "%s は有効な識別子ではありません: %w",
+		"%s must be one of %s":                "%s は次のいずれかである必要があります: %s",
+		"%s must have at least %s characters": "%s は %s 文字以上である必要があります",
+		"%s must have at most %s characters":  "%s は %s 文字以下である必要があります",
+		"%s must have at least %s items":      "%s には %s 件以上の項目が必要です",
+		"%s must have at most %s items":       "%s の項目は %s 件以下である必要があります",
+		"%s must be at least %s":              "%s は %s 以上である必要があります",
+		"%s must be at most %s":               "%s は %s 以下である必要があります",
+
+		// Access control
+		"tool not permitted": "ツールの使用は許可されていません",
+		"%w: %s requires a role (set an API key, a client mapping, or rbac.default_role)": "%w: %s にはロールが必要です（API キー、クライアントのマッピング、または rbac.default_role を設定してください）",
+		"%w: role %s may not call %s":                                                                                                 "%w: ロール %s は %s を呼び出せません",
+		"%w: %s is turned off in features.tools":                                                                                      "%w: %s は features.tools で無効になっています",
+		"database is required when MYSQL_MCP_ALLOWED_DATABASES is configured":                                                         "MYSQL_MCP_ALLOWED_DATABASES が設定されている場合は database が必須です",
+		"database %q is not in MYSQL_MCP_ALLOWED_DATABASES":                                                                           "データベース %q は MYSQL_MCP_ALLOWED_DATABASES に含まれていません",
+		"SHOW DATABASES is not allowed when MYSQL_MCP_ALLOWED_DATABASES is set; use the list_databases tool instead":                  "MYSQL_MCP_ALLOWED_DATABASES が設定されている場合、SHOW DATABASES は使用できません。代わりに list_databases ツールを使用してください",
+		"query validation failed: %w":                                                                                                 "クエリの検証に失敗しました: %w",
+		"query references database %q which is not in MYSQL_MCP_ALLOWED_DATABASES":                                                    "クエリが参照しているデータベース %q は MYSQL_MCP_ALLOWED_DATABASES に含まれていません",
+		"connection %q is labeled %q and requires confirmation: check that this query should run there, then retry with confirm=true": "接続 %q には %q のラベルが付いているため確認が必要です: このクエリをその接続で実行してよいか確認し、confirm=true を付けて再試行してください",
+
+		"%w (names are case-sensitive on this server: did you mean %s? Set MYSQL_MCP_IDENTIFIER_CASE=catalog to match them automatically)": "%w（このサーバーでは名前の大文字と小文字が区別されます: %s のことですか? MYSQL_MCP_IDENTIFIER_CASE=catalog を設定すると自動的に一致させます）",
+
+		// Load protection
+		"server busy: no query slot on %s (limit %d) within %s; retry shortly":                            "サーバーが混雑しています: %s（上限 %d）で %s 以内にクエリ枠を確保できませんでした。しばらくしてから再試行してください",
+		"server busy: %s is at its limit of %d concurrent queries and %d calls are queued; retry shortly": "サーバーが混雑しています: %s は同時実行クエリの上限 %d に達しており、%d 件の呼び出しが待機中です。しばらくしてから再試行してください",
+		"server busy: %s already runs its limit of %d concurrent queries; retry shortly":                  "サーバーが混雑しています: %s はすでに同時実行クエリの上限 %d 件を実行中です。しばらくしてから再試行してください",
+		"connection %s unavailable: circuit open after %d consecutive failures (last: %s); retry in %s":   "接続 %s は利用できません: %d 回連続で失敗したためサーキットが開いています（最後のエラー: %s）。%s 後に再試行してください",
+		"quota exceeded: %s has received its %d rows for today; resets in %s":                             "クォータ超過: %s は本日の %d 行を受け取りました。%s 後にリセットされます",
+		"quota exceeded: %s has made its %d queries for this hour; resets in %s":                          "クォータ超過: %s はこの1時間の %d 件のクエリを実行しました。%s 後にリセットされます",
+	},
+	config.LocaleThai: {
+		// Input validation
+		"invalid input: %s":                   "ข้อมูลนำเข้าไม่ถูกต้อง: %s",
+		"%s is required":                      "ต้องระบุ %s",
+		"%s is not a valid identifier: %w":    "%s ไม่ใช่ตัวระบุที่ถูกต้อง: %w",
+		"%s must be one of %s":                "%s ต้องเป็นค่าใดค่าหนึ่งต่อไปนี้: %s",
+		"%s must have at least %s characters": "%s ต้องมีอย่างน้อย %s ตัวอักษร",
+		"%s must have at most %s characters":  "%s ต้องมีไม่เกิน %s ตัวอักษร",
+		"%s must have at least %s items":      "%s ต้องมีอย่างน้อย %s รายการ",
+		"%s must have at most %s items":       "%s ต้องมีไม่เกิน %s รายการ",
+		"%s must be at least %s":              "%s ต้องมีค่าอย่างน้อย %s",
+		"%s must be at most %s":               "%s ต้องมีค่าไม่เกิน %s",
+
+		// Access control
+		"tool not permitted": "ไม่อนุญาตให้ใช้เครื่องมือ",
+		"%w: %s requires a role (set an API key, a client mapping, or rbac.default_role)": "%w: %s ต้องมีบทบาท (ตั้งค่า API key, การจับคู่ไคลเอนต์ หรือ rbac.default_role)",
+		"%w: role %s may not call %s":                                                                                                 "%w: บทบาท %s ไม่สามารถเรียกใช้ %s ได้",
+		"%w: %s is turned off in features.tools":                                                                                      "%w: %s ถูกปิดใช้งานใน features.tools",
+		"database is required when MYSQL_MCP_ALLOWED_DATABASES is configured":                                                         "ต้องระบุ database เมื่อมีการตั้งค่า MYSQL_MCP_ALLOWED_DATABASES",
+		"database %q is not in MYSQL_MCP_ALLOWED_DATABASES":                                                                           "ฐานข้อมูล %q ไม่อยู่ใน MYSQL_MCP_ALLOWED_DATABASES",
+		"SHOW DATABASES is not allowed when MYSQL_MCP_ALLOWED_DATABASES is set; use the list_databases tool instead":                  "ไม่อนุญาตให้ใช้ SHOW DATABASES เมื่อตั้งค่า MYSQL_MCP_ALLOWED_DATABASES โปรดใช้เครื่องมือ list_databases แทน",
+		"query validation failed: %w":                                                                                                 "การตรวจสอบคิวรีล้มเหลว: %w",
+		"query references database %q which is not in MYSQL_MCP_ALLOWED_DATABASES":                                                    "คิวรีอ้างอิงฐานข้อมูล %q ซึ่งไม่อยู่ใน MYSQL_MCP_ALLOWED_DATABASES",
+		"connection %q is labeled %q and requires confirmation: check that this query should run there, then retry with confirm=true": "การเชื่อมต่อ %q มีป้ายกำกับ %q และต้องยืนยันก่อน: ตรวจสอบว่าควรรันคิวรีนี้บนการเชื่อมต่อนั้นจริง แล้วลองใหม่โดยใส่ confirm=true",
+
+		"%w (names are case-sensitive on this server: did you mean %s? Set MYSQL_MCP_IDENTIFIER_CASE=catalog to match them automatically)": "%w (ชื่อบนเซิร์ฟเวอร์นี้แยกตัวพิมพ์เล็ก-ใหญ่: หมายถึง %s หรือไม่? ตั้งค่า MYSQL_MCP_IDENTIFIER_CASE=catalog เพื่อจับคู่ให้อัตโนมัติ)",
+
+		// Load protection
+		"server busy: no query slot on %s (limit %d) within %s; retry shortly":                            "เซิร์ฟเวอร์ไม่ว่าง: ไม่มีช่องว่างสำหรับคิวรีบน %s (ขีดจำกัด %d) ภายใน %s โปรดลองใหม่อีกครั้งในไม่ช้า",
+		"server busy: %s is at its limit of %d concurrent queries and %d calls are queued; retry shortly": "เซิร์ฟเวอร์ไม่ว่าง: %s รันคิวรีพร้อมกันถึงขีดจำกัด %d รายการแล้ว และมี %d คำขอรออยู่ในคิว โปรดลองใหม่อีกครั้งในไม่ช้า",
+		"server busy: %s already runs its limit of %d concurrent queries; retry shortly":                  "เซิร์ฟเวอร์ไม่ว่าง: %s กำลังรันคิวรีพร้อมกันเต็มขีดจำกัด %d รายการแล้ว โปรดลองใหม่อีกครั้งในไม่ช้า",
+		"connection %s unavailable: circuit open after %d consecutive failures (last: %s); retry in %s":   "การเชื่อมต่อ %s ใช้งานไม่ได้: circuit เปิดอยู่หลังจากล้มเหลวติดต่อกัน %d ครั้ง (ล่าสุด: %s) ลองใหม่ในอีก %s",
+		"quota exceeded: %s has received its %d rows for today; resets in %s":                             "เกินโควตา: %s ได้รับ %d แถวสำหรับวันนี้ครบแล้ว จะรีเซ็ตในอีก %s",
+		"quota exceeded: %s has made its %d queries for this hour; resets in %s":                          "เกินโควตา: %s ใช้คิวรี %d รายการสำหรับชั่วโมงนี้ครบแล้ว จะรีเซ็ตในอีก %s",
+	},
+}
@@ -0,0 +1,98 @@
+// pkg/mysqlmcp/i18n_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"ja":                        "ja",
+		"de-CH, en;q=0.5":           "de",
+		"fr, th-TH;q=0.8, de;q=0.7": "th",
+		"en;q=0.2, ja_JP;q=0.9":     "ja",
+		"fr, es":                    "",
+		"de;q=0, en":                "en",
+		"ja;q=abc":                  "",
+	}
+	for header, want := range tests {
+		if got := negotiateLocale(header); got != want {
+			t.Errorf("negotiateLocale(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+var formatVerbRe = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func TestMessageCatalogVerbs(t *testing.T) {
+	for locale, messages := range messageCatalog {
+		if !config.ValidLocale(locale) {
+			t.Errorf("catalog has unsupported locale %q", locale)
+		}
+		for english, translated := range messages {
+			want := strings.Join(formatVerbRe.FindAllString(english, -1), " ")
+			if got := strings.Join(formatVerbRe.FindAllString(translated, -1), " "); got != want {
+				t.Errorf("%s: %q has verbs %q, want %q", locale, translated, got, want)
+			}
+		}
+	}
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	err := validateInput(DescribeTableInput{Database: "shop"})
+	if err.Error() != "invalid input: table is required" {
+		t.Errorf("unexpected English message: %v", err)
+	}
+	if got := localizeMessage(config.LocaleGerman, err); got != "ungültige Eingabe: table ist erforderlich" {
+		t.Errorf("unexpected German message: %q", got)
+	}
+
+	denied := i18nErrorf("%w: role %s may not call %s", errToolForbidden, "analyst", "schema_diff")
+	if got := localizeMessage(config.LocaleJapanese, denied); got != "ツールの使用は許可されていません: ロール analyst は schema_diff を呼び出せません" {
+		t.Errorf("unexpected Japanese message: %q", got)
+	}
+
+	ctx := withLocale(context.Background(), config.LocaleThai)
+	translated := localizeError(ctx, denied)
+	if !errors.Is(translated, errToolForbidden) || !strings.HasPrefix(translated.Error(), "ไม่อนุญาตให้ใช้เครื่องมือ") {
+		t.Errorf("unexpected Thai error: %v", translated)
+	}
+	plain := errors.New("Error 1146: Table 'shop.x' doesn't exist")
+	if localizeError(ctx, plain) != plain {
+		t.Error("errors without a translation should be returned unchanged")
+	}
+}
+
+func TestDispatchToolLocale(t *testing.T) {
+	h := dispatchTool("describe_table", func(ctx context.Context, req *mcp.CallToolRequest, in DescribeTableInput) (*mcp.CallToolResult, DescribeTableOutput, error) {
+		return nil, DescribeTableOutput{}, nil
+	})
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Meta: mcp.Meta{localeMetaKey: "ja-JP"}}}
+	_, _, err := h(context.Background(), req, DescribeTableInput{})
+	if err == nil || !strings.HasPrefix(err.Error(), "入力が無効です: database は必須です") {
+		t.Errorf("expected a Japanese input error, got %v", err)
+	}
+
+	// The HTTP layer sets the locale from Accept-Language; the status still
+	// follows the untranslated error.
+	httpReq := httptest.NewRequest(http.MethodGet, "/api/describe", nil)
+	httpReq.Header.Set("Accept-Language", "de-DE,de;q=0.9")
+	var ctx context.Context
+	withHTTPLocale(func(w http.ResponseWriter, r *http.Request) { ctx = r.Context() })(httptest.NewRecorder(), httpReq)
+	_, _, err = h(ctx, nil, DescribeTableInput{})
+	w := httptest.NewRecorder()
+	writeToolError(w, err)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "ungültige Eingabe") {
+		t.Errorf("expected a German 400, got %d %s", w.Code, w.Body.String())
+	}
+}
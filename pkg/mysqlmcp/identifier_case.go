@@ -0,0 +1,173 @@
+// pkg/mysqlmcp/identifier_case.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Database and table names are case-sensitive on servers with
+// lower_case_table_names=0 (the default on Linux), so a schema created on
+// Windows or macOS, or a name typed in another case, gives "table doesn't
+// exist". dispatchTool applies query.identifier_case to the database and
+// table arguments of every tool and, whatever the mode, points at the
+// catalog's spelling when MySQL reports an unknown table or database.
+
+// MySQL error numbers for missing objects.
+const (
+	errNoSuchTable     = 1146 // ER_NO_SUCH_TABLE
+	errUnknownDatabase = 1049 // ER_BAD_DB_ERROR
+)
+
+var (
+	noSuchTableRe     = regexp.MustCompile(`^Table '([^']*)\.([^']*)' doesn't exist`)
+	unknownDatabaseRe = regexp.MustCompile(`^Unknown database '([^']*)'`)
+)
+
+// lowerCaseTableNames caches @@lower_case_table_names per pool; it is fixed
+// when the server is initialized. Entries are dropped when the
+// ConnectionManager replaces or closes a pool.
+var lowerCaseTableNames sync.Map // *sql.DB -> int
+
+// serverLowerCaseTableNames returns lower_case_table_names of db, or -1 when
+// it cannot be read.
+func serverLowerCaseTableNames(ctx context.Context, db *sql.DB) int {
+	if db == nil {
+		return -1
+	}
+	if v, ok := lowerCaseTableNames.Load(db); ok {
+		return v.(int)
+	}
+	var n int
+	if err := db.QueryRowContext(ctx, "SELECT @@lower_case_table_names").Scan(&n); err != nil {
+		return -1
+	}
+	lowerCaseTableNames.Store(db, n)
+	return n
+}
+
+func identifierCaseMode() string {
+	if cfg == nil || cfg.IdentifierCase == "" {
+		return config.IdentifierCasePreserve
+	}
+	return cfg.IdentifierCase
+}
+
+// catalogName returns the catalog's spelling of name from query, which must
+// select names matching LOWER(?) with name as the last argument. The name is
+// returned unchanged when it exists as given, has no match or is ambiguous.
+func catalogName(ctx context.Context, db *sql.DB, query, name string, args ...interface{}) string {
+	rows, err := db.QueryContext(ctx, query, append(args, name)...)
+	if err != nil {
+		return name
+	}
+	defer rows.Close()
+	var matches []string
+	for rows.Next() {
+		var m string
+		if err := rows.Scan(&m); err != nil {
+			return name
+		}
+		if m == name {
+			return name
+		}
+		matches = append(matches, m)
+	}
+	if rows.Err() != nil || len(matches) != 1 {
+		return name
+	}
+	return matches[0]
+}
+
+const (
+	catalogSchemaQuery = "SELECT SCHEMA_NAME FROM information_schema.SCHEMATA WHERE LOWER(SCHEMA_NAME) = LOWER(?)"
+	catalogTableQuery  = "SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND LOWER(TABLE_NAME) = LOWER(?)"
+)
+
+// foldInputIdentifiers applies the identifier case mode to the database
+// (json database or *_database) and table fields of the tool input that
+// input points to, on servers where those names are case-sensitive.
+func foldInputIdentifiers(ctx context.Context, input interface{}) {
+	mode := identifierCaseMode()
+	if mode == config.IdentifierCasePreserve {
+		return
+	}
+	rv := reflect.ValueOf(input)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	rv = rv.Elem()
+	var databases []reflect.Value
+	var database, table reflect.Value
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		if f.Kind() != reflect.String || f.String() == "" || !rv.Type().Field(i).IsExported() {
+			continue
+		}
+		switch name := strings.Split(rv.Type().Field(i).Tag.Get("json"), ",")[0]; {
+		case name == "database":
+			database = f
+			databases = append(databases, f)
+		case strings.HasSuffix(name, "_database"):
+			databases = append(databases, f)
+		case name == "table":
+			table = f
+		}
+	}
+	if len(databases) == 0 && !table.IsValid() {
+		return
+	}
+	db := getDB()
+	if serverLowerCaseTableNames(ctx, db) != 0 {
+		return
+	}
+
+	if mode == config.IdentifierCaseLower {
+		for _, f := range databases {
+			f.SetString(strings.ToLower(f.String()))
+		}
+		if table.IsValid() {
+			table.SetString(strings.ToLower(table.String()))
+		}
+		return
+	}
+	for _, f := range databases {
+		f.SetString(catalogName(ctx, db, catalogSchemaQuery, f.String()))
+	}
+	if table.IsValid() && database.IsValid() {
+		table.SetString(catalogName(ctx, db, catalogTableQuery, table.String(), database.String()))
+	}
+}
+
+// identifierCaseHint adds the catalog's spelling to an unknown table or
+// database error when the name exists in another case on a case-sensitive
+// server.
+func identifierCaseHint(ctx context.Context, err error) error {
+	var mysqlErr *mysql.MySQLError
+	if err == nil || !errors.As(err, &mysqlErr) || (mysqlErr.Number != errNoSuchTable && mysqlErr.Number != errUnknownDatabase) {
+		return err
+	}
+	db := getDB()
+	if serverLowerCaseTableNames(ctx, db) != 0 {
+		return err
+	}
+	var given, suggestion string
+	if m := noSuchTableRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+		schema := catalogName(ctx, db, catalogSchemaQuery, m[1])
+		given, suggestion = m[1]+"."+m[2], schema+"."+catalogName(ctx, db, catalogTableQuery, m[2], schema)
+	} else if m := unknownDatabaseRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+		given, suggestion = m[1], catalogName(ctx, db, catalogSchemaQuery, m[1])
+	}
+	if suggestion == given {
+		return err
+	}
+	return i18nErrorf("%w (names are case-sensitive on this server: did you mean %s? Set MYSQL_MCP_IDENTIFIER_CASE=catalog to match them automatically)", err, suggestion)
+}
@@ -0,0 +1,105 @@
+// pkg/mysqlmcp/identifier_case_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/go-sql-driver/mysql"
+)
+
+func withIdentifierCase(t *testing.T, mode string) {
+	oldCfg := cfg
+	cfg = &config.Config{IdentifierCase: mode}
+	t.Cleanup(func() { cfg = oldCfg })
+}
+
+func expectLowerCaseTableNames(mock sqlmock.Sqlmock, n int) {
+	mock.ExpectQuery("SELECT @@lower_case_table_names").
+		WillReturnRows(sqlmock.NewRows([]string{"@@lower_case_table_names"}).AddRow(n))
+}
+
+func TestFoldInputIdentifiersCatalog(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	withIdentifierCase(t, config.IdentifierCaseCatalog)
+
+	expectLowerCaseTableNames(mock, 0)
+	mock.ExpectQuery("FROM information_schema.SCHEMATA").WithArgs("Shop").
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("shop"))
+	mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop", "orders").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("Orders"))
+
+	input := DescribeTableInput{Database: "Shop", Table: "orders"}
+	foldInputIdentifiers(context.Background(), &input)
+	if input.Database != "shop" || input.Table != "Orders" {
+		t.Errorf("expected catalog spelling shop.Orders, got %s.%s", input.Database, input.Table)
+	}
+
+	// Ambiguous names (orders and ORDERS both exist) are left as given.
+	mock.ExpectQuery("FROM information_schema.SCHEMATA").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("shop"))
+	mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop", "Orders").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("orders").AddRow("ORDERS"))
+	input = DescribeTableInput{Database: "shop", Table: "Orders"}
+	foldInputIdentifiers(context.Background(), &input)
+	if input.Table != "Orders" {
+		t.Errorf("expected an ambiguous name to be kept, got %s", input.Table)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestFoldInputIdentifiersLower(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	withIdentifierCase(t, config.IdentifierCaseLower)
+
+	expectLowerCaseTableNames(mock, 0)
+	input := SchemaDiffInput{SourceDatabase: "Prod", TargetDatabase: "STAGING"}
+	foldInputIdentifiers(context.Background(), &input)
+	if input.SourceDatabase != "prod" || input.TargetDatabase != "staging" {
+		t.Errorf("expected lower-cased databases, got %+v", input)
+	}
+
+	// Servers that already fold names are left alone (the setting is cached).
+	lowerCaseTableNames.Store(getDB(), 1)
+	input = SchemaDiffInput{SourceDatabase: "Prod"}
+	foldInputIdentifiers(context.Background(), &input)
+	if input.SourceDatabase != "Prod" {
+		t.Errorf("expected no folding with lower_case_table_names=1, got %s", input.SourceDatabase)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIdentifierCaseHint(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	expectLowerCaseTableNames(mock, 0)
+	mock.ExpectQuery("FROM information_schema.SCHEMATA").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).AddRow("shop"))
+	mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop", "orders").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).AddRow("Orders"))
+
+	mysqlErr := &mysql.MySQLError{Number: errNoSuchTable, Message: "Table 'shop.orders' doesn't exist"}
+	err := identifierCaseHint(context.Background(), fmt.Errorf("query failed: %w", mysqlErr))
+	if err == nil || !strings.Contains(err.Error(), "did you mean shop.Orders?") {
+		t.Errorf("expected a catalog spelling hint, got %v", err)
+	}
+
+	other := fmt.Errorf("query failed: %w", &mysql.MySQLError{Number: 1064, Message: "syntax error"})
+	if identifierCaseHint(context.Background(), other) != other {
+		t.Error("expected other errors to pass through unchanged")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
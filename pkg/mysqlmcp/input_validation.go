@@ -0,0 +1,247 @@
+// pkg/mysqlmcp/input_validation.go
+package mysqlmcp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// Tool inputs declare their constraints in a validate struct tag, checked by
+// dispatchTool before the handler runs, for MCP and HTTP calls alike:
+//
+//	Database string `json:"database" validate:"required,ident"`
+//	Limit    int    `json:"limit,omitempty" validate:"min=0,max=1000"`
+//
+// Rules:
+//   - required: a string that is not blank, a non-empty slice or map, or a non-nil pointer
+//   - ident: a valid MySQL identifier (util.ValidateIdent); empty strings pass
+//   - min=N, max=N: bounds for numbers, string lengths in characters and
+//     slice lengths; a zero number counts as unset and is not checked
+//   - oneof=a|b|c: one of the listed values, case-insensitive; empty passes
+//
+// Struct fields and slices of structs are checked recursively.
+
+// InputError reports the input fields that broke their declared constraints,
+// one problem per field. HTTP answers it with 400.
+type InputError struct {
+	Problems []error
+}
+
+func (e *InputError) Error() string {
+	return e.localize(config.LocaleEnglish)
+}
+
+func (e *InputError) localize(locale string) string {
+	parts := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		parts[i] = localizeMessage(locale, p)
+	}
+	return tr(locale, "invalid input: %s", strings.Join(parts, "; "))
+}
+
+// inputRule is one parsed validate tag entry.
+type inputRule struct {
+	name string
+	arg  string
+}
+
+// inputField is a struct field that carries rules or may contain some.
+type inputField struct {
+	index []int
+	name  string // JSON name
+	rules []inputRule
+}
+
+var inputFieldCache sync.Map // reflect.Type -> []inputField
+
+// validateInput checks v, a tool input struct, against its validate tags and
+// returns an *InputError listing every violation, or nil.
+func validateInput(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var problems []error
+	checkInputStruct(rv, "", &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &InputError{Problems: problems}
+}
+
+func checkInputStruct(rv reflect.Value, prefix string, problems *[]error) {
+	for _, f := range inputFields(rv.Type()) {
+		fv := rv.FieldByIndex(f.index)
+		path := prefix + f.name
+		for _, r := range f.rules {
+			if err := checkInputRule(fv, path, r); err != nil {
+				*problems = append(*problems, err)
+				break // one problem per field
+			}
+		}
+		checkInputNested(fv, path, problems)
+	}
+}
+
+func checkInputNested(fv reflect.Value, path string, problems *[]error) {
+	switch fv.Kind() {
+	case reflect.Pointer:
+		if !fv.IsNil() {
+			checkInputNested(fv.Elem(), path, problems)
+		}
+	case reflect.Struct:
+		checkInputStruct(fv, path+".", problems)
+	case reflect.Slice:
+		if elem := fv.Type().Elem(); elem.Kind() != reflect.Struct || len(inputFields(elem)) == 0 {
+			return
+		}
+		for i := 0; i < fv.Len(); i++ {
+			checkInputStruct(fv.Index(i), fmt.Sprintf("%s[%d].", path, i), problems)
+		}
+	}
+}
+
+// inputFields returns the fields of a struct type worth checking: those with
+// rules and those of struct type (which may have rules of their own).
+func inputFields(t reflect.Type) []inputField {
+	if cached, ok := inputFieldCache.Load(t); ok {
+		return cached.([]inputField)
+	}
+	var fields []inputField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		var rules []inputRule
+		if tag := sf.Tag.Get("validate"); tag != "" {
+			for _, part := range strings.Split(tag, ",") {
+				rname, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+				rules = append(rules, inputRule{name: rname, arg: arg})
+			}
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if len(rules) > 0 || ft.Kind() == reflect.Struct {
+			fields = append(fields, inputField{index: sf.Index, name: name, rules: rules})
+		}
+	}
+	inputFieldCache.Store(t, fields)
+	return fields
+}
+
+// checkInputRule returns why fv, the value at path, breaks r, or nil when it
+// satisfies it.
+func checkInputRule(fv reflect.Value, path string, r inputRule) error {
+	switch r.name {
+	case "required":
+		switch fv.Kind() {
+		case reflect.String:
+			if strings.TrimSpace(fv.String()) == "" {
+				return i18nErrorf("%s is required", path)
+			}
+		case reflect.Slice, reflect.Map:
+			if fv.Len() == 0 {
+				return i18nErrorf("%s is required", path)
+			}
+		case reflect.Pointer, reflect.Interface:
+			if fv.IsNil() {
+				return i18nErrorf("%s is required", path)
+			}
+		}
+	case "ident":
+		if fv.Kind() == reflect.String && fv.String() != "" {
+			if err := util.ValidateIdent(fv.String()); err != nil {
+				return i18nErrorf("%s is not a valid identifier: %w", path, err)
+			}
+		}
+	case "oneof":
+		if fv.Kind() == reflect.String && fv.String() != "" {
+			allowed := strings.Split(r.arg, "|")
+			for _, a := range allowed {
+				if strings.EqualFold(fv.String(), a) {
+					return nil
+				}
+			}
+			return i18nErrorf("%s must be one of %s", path, strings.Join(allowed, ", "))
+		}
+	case "min", "max":
+		return checkInputBound(fv, path, r)
+	default:
+		panic(fmt.Sprintf("unknown validate rule %q", r.name))
+	}
+	return nil
+}
+
+func checkInputBound(fv reflect.Value, path string, r inputRule) error {
+	bound, err := strconv.ParseFloat(r.arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validate rule %s: bad bound %q", r.name, r.arg))
+	}
+	for fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return nil
+		}
+		fv = fv.Elem()
+	}
+	var n float64
+	unit := ""
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(fv.Int())
+	case reflect.Float32, reflect.Float64:
+		n = fv.Float()
+	case reflect.String:
+		n, unit = float64(len([]rune(fv.String()))), "characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = float64(fv.Len()), "items"
+	default:
+		return nil
+	}
+	if n == 0 {
+		return nil // unset numbers and empty values are left to required
+	}
+	switch {
+	case r.name == "min" && n < bound && unit == "characters":
+		return i18nErrorf("%s must have at least %s characters", path, r.arg)
+	case r.name == "min" && n < bound && unit == "items":
+		return i18nErrorf("%s must have at least %s items", path, r.arg)
+	case r.name == "min" && n < bound:
+		return i18nErrorf("%s must be at least %s", path, r.arg)
+	case r.name == "max" && n > bound && unit == "characters":
+		return i18nErrorf("%s must have at most %s characters", path, r.arg)
+	case r.name == "max" && n > bound && unit == "items":
+		return i18nErrorf("%s must have at most %s items", path, r.arg)
+	case r.name == "max" && n > bound:
+		return i18nErrorf("%s must be at most %s", path, r.arg)
+	}
+	return nil
+}
+
+// isInputError reports whether err comes from validateInput.
+func isInputError(err error) bool {
+	var ie *InputError
+	return errors.As(err, &ie)
+}
@@ -0,0 +1,108 @@
+// pkg/mysqlmcp/input_validation_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestValidateInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+		want  []string // problems, in field order
+	}{
+		{"valid", DescribeTableInput{Database: "shop", Table: "orders"}, nil},
+		{"missing", DescribeTableInput{}, []string{"database is required", "table is required"}},
+		{"blank", ListTablesInput{Database: "  "}, []string{"database is required"}},
+		{"bad identifier", ListViewsInput{Database: "shop`; DROP"}, []string{"database is not a valid identifier"}},
+		{"optional identifier", RunQueryInput{SQL: "SELECT 1"}, nil},
+		{"oneof", FulltextSearchInput{Database: "d", Table: "t", Query: "q", Mode: "Boolean"}, nil},
+		{"oneof rejected", FulltextSearchInput{Database: "d", Table: "t", Query: "q", Mode: "fuzzy"}, []string{"mode must be one of natural, boolean, query_expansion"}},
+		{"max items", VectorDeleteInput{Database: "d", Table: "t", Column: "c", KeyColumn: "id", Keys: make([]interface{}, 1001)}, []string{"keys must have at most 1000 items"}},
+		{"nested", VectorInsertInput{Database: "d", Table: "t", Column: "c", Rows: []VectorInsertRow{{}}}, nil},
+		{"pointer", &ListGhostTablesInput{}, []string{"database is required"}},
+		{"no rules", PingInput{}, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateInput(tc.input)
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			var ie *InputError
+			if !errors.As(err, &ie) {
+				t.Fatalf("expected *InputError, got %v", err)
+			}
+			if len(ie.Problems) != len(tc.want) {
+				t.Fatalf("expected %d problems, got %q", len(tc.want), ie.Problems)
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(ie.Problems[i].Error(), want) {
+					t.Errorf("problem %d = %q, want prefix %q", i, ie.Problems[i].Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateInputNestedPath(t *testing.T) {
+	type row struct {
+		Key string `json:"key" validate:"required"`
+	}
+	type input struct {
+		Rows []row `json:"rows"`
+		Opt  *row  `json:"opt,omitempty"`
+	}
+	err := validateInput(input{Rows: []row{{Key: "a"}, {}}, Opt: &row{}})
+	if err == nil || err.Error() != "invalid input: rows[1].key is required; opt.key is required" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateInputBounds(t *testing.T) {
+	type input struct {
+		Limit int      `json:"limit,omitempty" validate:"min=1,max=10"`
+		Ratio *float64 `json:"ratio,omitempty" validate:"max=1"`
+		Name  string   `json:"name,omitempty" validate:"min=2"`
+	}
+	if err := validateInput(input{}); err != nil {
+		t.Errorf("zero values should be treated as unset, got %v", err)
+	}
+	ratio := 1.5
+	err := validateInput(input{Limit: 11, Ratio: &ratio, Name: "a"})
+	want := "invalid input: limit must be at most 10; ratio must be at most 1; name must have at least 2 characters"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+}
+
+func TestDispatchToolValidatesInput(t *testing.T) {
+	called := false
+	h := dispatchTool("describe_table", func(ctx context.Context, req *mcp.CallToolRequest, in DescribeTableInput) (*mcp.CallToolResult, DescribeTableOutput, error) {
+		called = true
+		return nil, DescribeTableOutput{}, nil
+	})
+	_, _, err := h(context.Background(), nil, DescribeTableInput{Database: "shop"})
+	if called {
+		t.Error("handler ran despite invalid input")
+	}
+	if !isInputError(err) || !strings.Contains(err.Error(), "table is required") {
+		t.Errorf("expected an input error, got %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	writeToolError(w, err)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected HTTP 400 for input errors, got %d", w.Code)
+	}
+}
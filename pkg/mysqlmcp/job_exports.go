@@ -0,0 +1,187 @@
+// pkg/mysqlmcp/job_exports.go
+package mysqlmcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Export jobs are query jobs whose rows go to object storage instead of the
+// job store: the rows are streamed, with the row cap of /api/query/stream,
+// into a temporary file that is uploaded to an export target once the query
+// finishes. The job then reports a signed URL the caller downloads the
+// result from, so multi-GB extracts never pass through the server's HTTP
+// responses or memory.
+
+// Export formats (JobExportInput.Format).
+const (
+	exportNDJSON = "ndjson" // the lines of /api/query/stream
+	exportCSV    = "csv"    // a header row, then one record per row
+)
+
+// exportTarget returns the export target called name.
+func exportTarget(name string) (config.ExportTarget, bool) {
+	if cfg == nil {
+		return config.ExportTarget{}, false
+	}
+	for _, t := range cfg.ExportTargets {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return config.ExportTarget{}, false
+}
+
+// checkJobExport validates the export of a job request, defaulting its format.
+func checkJobExport(e *JobExportInput) error {
+	if _, ok := exportTarget(e.Target); !ok {
+		return fmt.Errorf("unknown export target '%s'", e.Target)
+	}
+	switch e.Format {
+	case "":
+		e.Format = exportNDJSON
+	case exportNDJSON, exportCSV:
+	default:
+		return fmt.Errorf("export format must be ndjson or csv")
+	}
+	return nil
+}
+
+// runExport streams the rows of input into a temporary file and uploads it
+// to the target of export as the object <prefix><job id>.<format>.
+func runExport(ctx context.Context, jobID string, input RunQueryInput, export JobExportInput) (*QueryJobExport, error) {
+	target, ok := exportTarget(export.Target)
+	if !ok {
+		return nil, fmt.Errorf("unknown export target '%s'", export.Target)
+	}
+	store, err := newObjectStore(target)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "mysql-mcp-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	sink := newExportFile(f, export.Format)
+	_, summary, err := dispatchTool("run_query", sink.run)(ctx, nil, input)
+	result := &QueryJobExport{
+		Target:    target.Name,
+		Format:    export.Format,
+		Object:    target.Prefix + jobID + "." + export.Format,
+		RowCount:  summary.RowCount,
+		Truncated: summary.Truncated,
+	}
+	if err != nil {
+		return result, err
+	}
+	if err := sink.close(summary); err != nil {
+		return result, fmt.Errorf("failed to write export file: %w", err)
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return result, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return result, err
+	}
+	if err := store.put(ctx, result.Object, f, size, sink.contentType()); err != nil {
+		return result, err
+	}
+	result.Bytes = size
+
+	url, err := store.signedURL(result.Object, target.URLExpiry)
+	if err != nil {
+		return result, fmt.Errorf("failed to sign the download URL: %w", err)
+	}
+	result.URL = url
+	result.URLExpiresAt = time.Now().Add(target.URLExpiry).UTC().Format(time.RFC3339)
+	return result, nil
+}
+
+// exportFile writes the rows of an export to a file in its format.
+type exportFile struct {
+	w      *bufio.Writer
+	format string
+	enc    *json.Encoder
+	csv    *csv.Writer
+}
+
+func newExportFile(f *os.File, format string) *exportFile {
+	w := bufio.NewWriterSize(f, 256<<10)
+	e := &exportFile{w: w, format: format}
+	if format == exportCSV {
+		e.csv = csv.NewWriter(w)
+	} else {
+		e.enc = json.NewEncoder(w)
+	}
+	return e
+}
+
+// run is the run_query handler of an export: it streams the rows into the file.
+func (e *exportFile) run(ctx context.Context, req *mcp.CallToolRequest, input RunQueryInput) (*mcp.CallToolResult, streamSummaryLine, error) {
+	summary, err := streamQuery(ctx, input, e, "run_query_export")
+	return nil, summary, err
+}
+
+func (e *exportFile) start(header streamColumnsLine) error {
+	if e.csv != nil {
+		return e.csv.Write(header.Columns)
+	}
+	return e.enc.Encode(header)
+}
+
+func (e *exportFile) row(values []interface{}, n int) error {
+	if e.csv == nil {
+		return e.enc.Encode(streamRowLine{Row: values})
+	}
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			record[i] = string(data)
+		}
+	}
+	return e.csv.Write(record)
+}
+
+// close ends the file, with the summary line of a stream for ndjson.
+func (e *exportFile) close(summary streamSummaryLine) error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	} else {
+		summary.Done = true
+		if err := e.enc.Encode(summary); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+func (e *exportFile) contentType() string {
+	if e.csv != nil {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
@@ -0,0 +1,402 @@
+// pkg/mysqlmcp/jobs.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// Async query jobs let HTTP clients run analytical queries that outlast
+// MYSQL_HTTP_REQUEST_TIMEOUT_SECONDS: POST /api/jobs starts run_query in the
+// background under the job timeout and answers with a job ID at once; the
+// client polls GET /api/jobs/{id} for the result and can cancel it with
+// DELETE. Finished results are kept for the retention period within a byte
+// budget shared by all jobs. Jobs live in memory and are only visible to the
+// caller (API key, client or address) that started them. Export jobs upload
+// their rows to object storage instead (job_exports.go).
+
+// Job states reported by /api/jobs.
+const (
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	jobCanceled  = "canceled"
+	jobExpired   = "expired" // result dropped to make room for newer ones
+)
+
+// maxQueryJobs caps the jobs held at once, running or finished.
+const maxQueryJobs = 100
+
+// queryJob is one background run_query call.
+type queryJob struct {
+	id       string
+	owner    string
+	input    RunQueryInput
+	created  time.Time
+	finished time.Time // zero while running
+	status   string
+	err      string
+	result   *QueryResult
+	size     int             // JSON size of result
+	export   *QueryJobExport // export jobs only
+	cancel   context.CancelFunc
+	canceled bool // canceled by the client rather than timed out
+}
+
+// jobStore holds the jobs of the HTTP API.
+type jobStore struct {
+	timeout    time.Duration
+	retention  time.Duration
+	storeBytes int
+	now        func() time.Time
+
+	mu     sync.Mutex
+	jobs   map[string]*queryJob
+	stored int // result bytes held by finished jobs
+}
+
+var queryJobs = newJobStore(&config.Config{})
+
+func newJobStore(c *config.Config) *jobStore {
+	s := &jobStore{
+		timeout:    c.JobTimeout,
+		retention:  c.JobRetention,
+		storeBytes: c.JobStoreBytes,
+		now:        time.Now,
+		jobs:       make(map[string]*queryJob),
+	}
+	if s.timeout <= 0 {
+		s.timeout = config.DefaultJobTimeoutS * time.Second
+	}
+	if s.retention <= 0 {
+		s.retention = config.DefaultJobRetentionS * time.Second
+	}
+	if s.storeBytes <= 0 {
+		s.storeBytes = config.DefaultJobStoreBytes
+	}
+	return s
+}
+
+type queryTimeoutKey struct{}
+
+// queryTimeoutFor returns how long run_query may run in ctx: the job
+// timeout for async jobs, queryTimeout otherwise.
+func queryTimeoutFor(ctx context.Context) time.Duration {
+	if d, ok := ctx.Value(queryTimeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	return queryTimeout
+}
+
+// start runs input in the background for the caller in ctx. The job keeps
+// the request's identity, role and request ID but not its deadline.
+func (s *jobStore) start(ctx context.Context, input QueryJobInput) (QueryJobInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked()
+	if len(s.jobs) >= maxQueryJobs && !s.evictOldestLocked() {
+		return QueryJobInfo{}, fmt.Errorf("%w: %d query jobs are already running", errServerBusy, len(s.jobs))
+	}
+
+	j := &queryJob{
+		id:      newRequestID(),
+		owner:   clientIdentityFrom(ctx).key(),
+		input:   input.RunQueryInput,
+		created: s.now(),
+		status:  jobRunning,
+	}
+	if input.Export != nil {
+		j.export = &QueryJobExport{Target: input.Export.Target, Format: input.Export.Format}
+	}
+	jctx := context.WithoutCancel(ctx)
+	jctx = context.WithValue(jctx, quotaHeadersKey{}, http.Header(nil)) // the response is gone by the time the job ends
+	jctx = context.WithValue(jctx, queryTimeoutKey{}, s.timeout)
+	jctx, j.cancel = context.WithTimeout(jctx, s.timeout)
+	s.jobs[j.id] = j
+
+	go func() {
+		defer j.cancel()
+		if input.Export != nil {
+			export, err := runExport(jctx, j.id, input.RunQueryInput, *input.Export)
+			s.finishExport(j, export, err, jctx.Err())
+			return
+		}
+		_, out, err := toolRunQueryWrapped(jctx, nil, input.RunQueryInput)
+		s.finish(j, out, err, jctx.Err())
+	}()
+	return j.info(false, s.retention), nil
+}
+
+// finish records the outcome of j; ctxErr is the error of the job's context.
+func (s *jobStore) finish(j *queryJob, out QueryResult, err, ctxErr error) {
+	var size int
+	if err == nil {
+		data, merr := json.Marshal(out)
+		if merr != nil {
+			err = merr
+		} else {
+			size = len(data)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.finished = s.now()
+	switch {
+	case s.failedLocked(j, err, ctxErr):
+	case size > s.storeBytes:
+		j.status = jobFailed
+		j.err = fmt.Sprintf("result of %d bytes exceeds the job store of %d bytes; lower max_rows or select fewer columns", size, s.storeBytes)
+	default:
+		for s.stored+size > s.storeBytes {
+			if !s.expireOldestResultLocked() {
+				break
+			}
+		}
+		j.status, j.result, j.size = jobSucceeded, &out, size
+		s.stored += size
+	}
+}
+
+// finishExport records the outcome of the export job j.
+func (s *jobStore) finishExport(j *queryJob, export *QueryJobExport, err, ctxErr error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.finished = s.now()
+	if export != nil {
+		j.export = export
+	}
+	if !s.failedLocked(j, err, ctxErr) {
+		j.status = jobSucceeded
+	}
+}
+
+// failedLocked sets the status of j when it was canceled or failed with err,
+// reporting whether it did.
+func (s *jobStore) failedLocked(j *queryJob, err, ctxErr error) bool {
+	switch {
+	case j.canceled:
+		j.status = jobCanceled
+	case err != nil && errors.Is(ctxErr, context.DeadlineExceeded):
+		j.status, j.err = jobFailed, fmt.Sprintf("job timed out after %s: %v", s.timeout, err)
+	case err != nil:
+		j.status, j.err = jobFailed, err.Error()
+	default:
+		return false
+	}
+	return true
+}
+
+// get returns the job id of owner with its result.
+func (s *jobStore) get(owner, id string) (QueryJobInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked()
+	j, ok := s.jobs[id]
+	if !ok || j.owner != owner {
+		return QueryJobInfo{}, false
+	}
+	return j.info(true, s.retention), true
+}
+
+// list returns the jobs of owner, newest first, without results.
+func (s *jobStore) list(owner string) []QueryJobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked()
+	out := []QueryJobInfo{}
+	for _, j := range s.jobs {
+		if j.owner == owner {
+			out = append(out, j.info(false, s.retention))
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].CreatedAt > out[b].CreatedAt })
+	return out
+}
+
+// cancel stops a running job of owner, or discards a finished or canceled one.
+func (s *jobStore) cancel(owner, id string) (QueryJobInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok || j.owner != owner {
+		return QueryJobInfo{}, false
+	}
+	if j.status == jobRunning {
+		j.canceled, j.status = true, jobCanceled
+		j.cancel()
+	} else {
+		s.removeLocked(j)
+	}
+	return j.info(false, s.retention), true
+}
+
+// pruneLocked drops finished jobs past the retention period.
+func (s *jobStore) pruneLocked() {
+	cutoff := s.now().Add(-s.retention)
+	for _, j := range s.jobs {
+		if !j.finished.IsZero() && j.finished.Before(cutoff) {
+			s.removeLocked(j)
+		}
+	}
+}
+
+// evictOldestLocked drops the finished job that ended first, reporting
+// whether there was one.
+func (s *jobStore) evictOldestLocked() bool {
+	var oldest *queryJob
+	for _, j := range s.jobs {
+		if !j.finished.IsZero() && (oldest == nil || j.finished.Before(oldest.finished)) {
+			oldest = j
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	s.removeLocked(oldest)
+	return true
+}
+
+// expireOldestResultLocked drops the oldest kept result, leaving its job as
+// expired, and reports whether there was one.
+func (s *jobStore) expireOldestResultLocked() bool {
+	var oldest *queryJob
+	for _, j := range s.jobs {
+		if j.result != nil && (oldest == nil || j.finished.Before(oldest.finished)) {
+			oldest = j
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	s.stored -= oldest.size
+	oldest.status, oldest.result, oldest.size = jobExpired, nil, 0
+	oldest.err = "result dropped to make room for newer jobs (http.job_store_bytes)"
+	return true
+}
+
+func (s *jobStore) removeLocked(j *queryJob) {
+	s.stored -= j.size
+	delete(s.jobs, j.id)
+}
+
+func (j *queryJob) info(withResult bool, retention time.Duration) QueryJobInfo {
+	info := QueryJobInfo{
+		ID:        j.id,
+		Status:    j.status,
+		SQL:       j.input.SQL,
+		Database:  j.input.Database,
+		CreatedAt: j.created.UTC().Format(time.RFC3339Nano),
+		Error:     j.err,
+	}
+	if !j.finished.IsZero() {
+		info.FinishedAt = j.finished.UTC().Format(time.RFC3339Nano)
+		info.ExpiresAt = j.finished.Add(retention).UTC().Format(time.RFC3339)
+		info.DurationMs = j.finished.Sub(j.created).Milliseconds()
+	}
+	if j.export != nil {
+		export := *j.export
+		info.Export = &export
+	}
+	if j.result != nil {
+		rows := len(j.result.Rows)
+		info.RowCount, info.ResultBytes = &rows, j.size
+		if withResult {
+			info.Result = j.result
+		}
+	}
+	return info
+}
+
+// httpJobs handles /api/jobs: POST starts a job with the body of /api/query,
+// plus an optional export, and answers 202 with its ID; GET lists the
+// caller's jobs.
+func httpJobs(w http.ResponseWriter, r *http.Request) {
+	owner := clientIdentityFrom(r.Context()).key()
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		api.WriteSuccess(w, ListQueryJobsOutput{Jobs: queryJobs.list(owner)})
+	case http.MethodPost:
+		var input QueryJobInput
+		if err := decodeJSONBody(w, r, &input); err != nil {
+			var mbe *http.MaxBytesError
+			if errors.As(err, &mbe) {
+				api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+			return
+		}
+		if input.SQL == "" {
+			api.WriteBadRequest(w, "sql field is required")
+			return
+		}
+		if input.Export != nil {
+			if input.Offset != nil {
+				api.WriteBadRequest(w, "offset is not supported for exports")
+				return
+			}
+			if err := checkJobExport(input.Export); err != nil {
+				api.WriteBadRequest(w, err.Error())
+				return
+			}
+		}
+		// Refuse what run_query would refuse now rather than in a failed job.
+		if err := authorizeTool(r.Context(), nil, "run_query"); err != nil {
+			writeToolError(w, localizeError(r.Context(), err))
+			return
+		}
+		if err := validateInput(input.RunQueryInput); err != nil {
+			writeToolError(w, localizeError(r.Context(), err))
+			return
+		}
+		if err := validateSQL(input.SQL); err != nil {
+			api.WriteBadRequest(w, "query validation failed: "+err.Error())
+			return
+		}
+		info, err := queryJobs.start(r.Context(), input)
+		if err != nil {
+			writeToolError(w, err)
+			return
+		}
+		w.Header().Set("Location", "/api/jobs/"+info.ID)
+		api.WriteJSON(w, http.StatusAccepted, api.Response{Success: true, Data: info})
+	default:
+		api.WriteMethodNotAllowed(w, "GET or POST method required")
+	}
+}
+
+// httpJob handles /api/jobs/{id}: GET returns the job with its result once
+// it succeeded; DELETE cancels a running job or discards a finished one.
+func httpJob(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/jobs/")
+	owner := clientIdentityFrom(r.Context()).key()
+	var (
+		info QueryJobInfo
+		ok   bool
+	)
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		info, ok = queryJobs.get(owner, id)
+	case http.MethodDelete:
+		info, ok = queryJobs.cancel(owner, id)
+	default:
+		api.WriteMethodNotAllowed(w, "GET or DELETE method required")
+		return
+	}
+	if !ok {
+		api.WriteNotFound(w, "job not found: "+id)
+		return
+	}
+	api.WriteSuccess(w, info)
+}
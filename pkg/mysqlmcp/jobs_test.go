@@ -0,0 +1,221 @@
+// pkg/mysqlmcp/jobs_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// waitForJob polls the job until it leaves the running state.
+func waitForJob(t *testing.T, owner, id string) QueryJobInfo {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		queryJobs.mu.Lock()
+		j := queryJobs.jobs[id]
+		done := j == nil || !j.finished.IsZero()
+		queryJobs.mu.Unlock()
+		if done {
+			info, _ := queryJobs.get(owner, id)
+			return info
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s still running", id)
+	return QueryJobInfo{}
+}
+
+func TestHTTPJobs(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldJobs := queryJobs
+	queryJobs = newJobStore(&config.Config{})
+	defer func() { queryJobs = oldJobs }()
+
+	mock.ExpectQuery("SELECT id FROM orders LIMIT 1000").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	owner := withClientIdentity(context.Background(), clientIdentity{RemoteIP: "10.0.0.1"})
+	req := httptest.NewRequest(http.MethodPost, "/api/jobs", strings.NewReader(`{"sql": "SELECT id FROM orders"}`)).WithContext(owner)
+	w := httptest.NewRecorder()
+	httpJobs(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var started struct {
+		Data QueryJobInfo `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatal(err)
+	}
+	if started.Data.ID == "" || w.Header().Get("Location") != "/api/jobs/"+started.Data.ID {
+		t.Fatalf("unexpected start response: %s", w.Body.String())
+	}
+
+	info := waitForJob(t, "ip 10.0.0.1", started.Data.ID)
+	if info.Status != jobSucceeded || info.Result == nil || len(info.Result.Rows) != 2 || *info.RowCount != 2 {
+		t.Fatalf("unexpected job: %+v", info)
+	}
+
+	w = httptest.NewRecorder()
+	httpJob(w, httptest.NewRequest(http.MethodGet, "/api/jobs/"+info.ID, nil).WithContext(owner))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"rows":[[1],[2]]`) {
+		t.Errorf("unexpected GET response %d: %s", w.Code, w.Body.String())
+	}
+	other := withClientIdentity(context.Background(), clientIdentity{RemoteIP: "10.0.0.2"})
+	w = httptest.NewRecorder()
+	httpJob(w, httptest.NewRequest(http.MethodGet, "/api/jobs/"+info.ID, nil).WithContext(other))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("another caller should not see the job, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	httpJob(w, httptest.NewRequest(http.MethodDelete, "/api/jobs/"+info.ID, nil).WithContext(owner))
+	if w.Code != http.StatusOK || len(queryJobs.list("ip 10.0.0.1")) != 0 {
+		t.Errorf("expected the finished job to be discarded, got %d: %s", w.Code, w.Body.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPJobsRejectsInvalidQuery(t *testing.T) {
+	w := httptest.NewRecorder()
+	httpJobs(w, httptest.NewRequest(http.MethodPost, "/api/jobs", strings.NewReader(`{"sql": "DELETE FROM orders"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestJobCancel(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldJobs := queryJobs
+	queryJobs = newJobStore(&config.Config{})
+	defer func() { queryJobs = oldJobs }()
+
+	mock.ExpectQuery("SELECT id FROM events").WillDelayFor(time.Minute).
+		WillReturnRows(sqlmock.NewRows([]string{"s"}).AddRow(0))
+	info, err := queryJobs.start(context.Background(), QueryJobInput{RunQueryInput: RunQueryInput{SQL: "SELECT id FROM events"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := queryJobs.cancel("anonymous", info.ID); !ok {
+		t.Fatal("expected to cancel the job")
+	}
+	if got := waitForJob(t, "anonymous", info.ID); got.Status != jobCanceled {
+		t.Errorf("expected a canceled job, got %+v", got)
+	}
+}
+
+func TestJobExport(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+	oldJobs := queryJobs
+	queryJobs = newJobStore(&config.Config{})
+	defer func() { queryJobs = oldJobs }()
+
+	uploads := map[string]string{}
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		uploads[r.URL.Path] = string(body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	cfg.ExportTargets = []config.ExportTarget{{
+		Name: "lake", Type: config.ExportTargetS3, Bucket: "exports", Prefix: "daily/", Endpoint: srv.URL,
+		AccessKeyID: "key", SecretAccessKey: "secret", URLExpiry: time.Hour,
+	}}
+
+	mock.ExpectQuery("SELECT id, name FROM orders").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a,b").AddRow(2, nil))
+
+	w := httptest.NewRecorder()
+	httpJobs(w, httptest.NewRequest(http.MethodPost, "/api/jobs",
+		strings.NewReader(`{"sql": "SELECT id, name FROM orders", "export": {"target": "lake", "format": "csv"}}`)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var started struct {
+		Data QueryJobInfo `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &started); err != nil {
+		t.Fatal(err)
+	}
+
+	info := waitForJob(t, "anonymous", started.Data.ID)
+	e := info.Export
+	if info.Status != jobSucceeded || e == nil || info.Result != nil {
+		t.Fatalf("unexpected job: %+v", info)
+	}
+	if e.Object != "daily/"+info.ID+".csv" || e.RowCount != 2 || e.Bytes != 19 || !strings.Contains(e.URL, "X-Amz-Signature=") {
+		t.Errorf("unexpected export: %+v", e)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if got := uploads["/exports/"+e.Object]; got != "id,name\n1,\"a,b\"\n2,\n" {
+		t.Errorf("unexpected upload %q (%v)", got, uploads)
+	}
+
+	for body, want := range map[string]string{
+		`{"sql": "SELECT 1", "export": {"target": "missing"}}`:                "unknown export target",
+		`{"sql": "SELECT 1", "export": {"target": "lake", "format": "xlsx"}}`: "ndjson or csv",
+	} {
+		w := httptest.NewRecorder()
+		httpJobs(w, httptest.NewRequest(http.MethodPost, "/api/jobs", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), want) {
+			t.Errorf("%s: expected 400 with %q, got %d: %s", body, want, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestJobStoreBudget(t *testing.T) {
+	s := newJobStore(&config.Config{JobStoreBytes: 150, JobRetention: time.Hour})
+	now := time.Date(2026, 10, 15, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	add := func(id string, cells int, err error) *queryJob {
+		j := &queryJob{id: id, owner: "o", created: now, status: jobRunning, cancel: func() {}}
+		s.jobs[id] = j
+		out := QueryResult{Columns: []string{"v"}}
+		for i := 0; i < cells; i++ {
+			out.Rows = append(out.Rows, []interface{}{"xxxxxxxxxx"})
+		}
+		s.finish(j, out, err, nil)
+		now = now.Add(time.Second)
+		return j
+	}
+
+	first := add("a", 2, nil)
+	second := add("b", 2, nil)
+	if first.status != jobSucceeded || second.status != jobSucceeded {
+		t.Fatalf("expected both results to fit: %s, %s (%d bytes)", first.status, second.status, s.stored)
+	}
+	add("c", 2, nil)
+	if first.status != jobExpired || first.result != nil || s.stored > 150 {
+		t.Errorf("expected the oldest result to make room: %+v, stored %d", first, s.stored)
+	}
+	if big := add("d", 20, nil); big.status != jobFailed || !strings.Contains(big.err, "exceeds the job store") {
+		t.Errorf("expected an oversized result to fail: %+v", big)
+	}
+	if failed := add("e", 0, errors.New("boom")); failed.status != jobFailed || failed.err != "boom" {
+		t.Errorf("unexpected failed job: %+v", failed)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if jobs := s.list("o"); len(jobs) != 0 || s.stored != 0 {
+		t.Errorf("expected finished jobs to be dropped after the retention period: %+v", jobs)
+	}
+}
@@ -0,0 +1,290 @@
+// pkg/mysqlmcp/list_params.go
+package mysqlmcp
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+)
+
+// listField is a field of a list tool that sort and filter may name. expr is
+// the SQL expression it maps to (empty for lists filtered in memory);
+// numeric fields compare as numbers.
+type listField struct {
+	expr    string
+	numeric bool
+}
+
+// listFilter is one parsed field:op:value filter.
+type listFilter struct {
+	field string
+	op    string
+	value string
+	num   float64
+	like  *regexp.Regexp // like filters matched in memory
+}
+
+// listFilterOps maps filter operators to SQL.
+var listFilterOps = map[string]string{
+	"eq":   "=",
+	"ne":   "<>",
+	"gt":   ">",
+	"gte":  ">=",
+	"lt":   "<",
+	"lte":  "<=",
+	"like": "LIKE",
+}
+
+// tableListFields are the sort and filter fields of list_tables.
+var tableListFields = map[string]listField{
+	"name":       {expr: "TABLE_NAME"},
+	"engine":     {expr: "ENGINE"},
+	"type":       {expr: "TABLE_TYPE"},
+	"comment":    {expr: "TABLE_COMMENT"},
+	"created_at": {expr: "CREATE_TIME"},
+	"updated_at": {expr: "UPDATE_TIME"},
+	"rows":       {expr: "TABLE_ROWS", numeric: true},
+	"data_mb":    {expr: "ROUND(DATA_LENGTH / 1024 / 1024, 2)", numeric: true},
+	"index_mb":   {expr: "ROUND(INDEX_LENGTH / 1024 / 1024, 2)", numeric: true},
+}
+
+// variableListFields are the sort and filter fields of list_status and
+// list_variables. Values compare as numbers when both sides are numeric.
+var variableListFields = map[string]listField{
+	"name":  {},
+	"value": {},
+}
+
+// listFieldNames returns the field names of fields, sorted, for error messages.
+func listFieldNames(fields map[string]listField) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// parseListSort parses a sort parameter: a field name, prefixed with - for
+// descending order. An empty sort returns an empty field.
+func parseListSort(s string, fields map[string]listField) (field string, desc bool, err error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") {
+		s, desc = s[1:], true
+	}
+	if s == "" {
+		return "", false, nil
+	}
+	if _, ok := fields[s]; !ok {
+		return "", false, fmt.Errorf("cannot sort by %q; fields: %s", s, listFieldNames(fields))
+	}
+	return s, desc, nil
+}
+
+// parseListFilters parses filters of the form field:op:value, where op is
+// one of eq, ne, gt, gte, lt, lte and like (string fields only).
+func parseListFilters(filters []string, fields map[string]listField) ([]listFilter, error) {
+	out := make([]listFilter, 0, len(filters))
+	for _, raw := range filters {
+		parts := strings.SplitN(raw, ":", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid filter %q; expected field:op:value", raw)
+		}
+		f := listFilter{field: strings.TrimSpace(parts[0]), op: strings.ToLower(strings.TrimSpace(parts[1])), value: parts[2]}
+		field, ok := fields[f.field]
+		if !ok {
+			return nil, fmt.Errorf("cannot filter on %q; fields: %s", f.field, listFieldNames(fields))
+		}
+		if _, ok := listFilterOps[f.op]; !ok {
+			return nil, fmt.Errorf("invalid filter operator %q; use eq, ne, gt, gte, lt, lte or like", f.op)
+		}
+		if field.numeric {
+			if f.op == "like" {
+				return nil, fmt.Errorf("like is not supported on numeric field %q", f.field)
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(f.value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("filter on %q needs a number, got %q", f.field, f.value)
+			}
+			f.num = n
+		}
+		if f.op == "like" {
+			f.like = likePattern(f.value)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// listSQLPredicates returns " AND expr op ?" for each filter, with the
+// values to bind. Only field expressions from fields reach the SQL text.
+func listSQLPredicates(filters []listFilter, fields map[string]listField) (string, []interface{}) {
+	var b strings.Builder
+	args := make([]interface{}, 0, len(filters))
+	for _, f := range filters {
+		fmt.Fprintf(&b, " AND %s %s ?", fields[f.field].expr, listFilterOps[f.op])
+		if fields[f.field].numeric {
+			args = append(args, f.num)
+		} else {
+			args = append(args, f.value)
+		}
+	}
+	return b.String(), args
+}
+
+// compareListValues compares a and b as numbers when both parse as numbers
+// and as case-insensitive strings otherwise.
+func compareListValues(a, b string) int {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// likePattern compiles a LIKE pattern into a case-insensitive regexp.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?is)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// matchListFilter reports whether value satisfies f.
+func matchListFilter(f listFilter, value string) bool {
+	if f.op == "like" {
+		return f.like.MatchString(value)
+	}
+	c := compareListValues(value, f.value)
+	switch f.op {
+	case "eq":
+		return c == 0
+	case "ne":
+		return c != 0
+	case "gt":
+		return c > 0
+	case "gte":
+		return c >= 0
+	case "lt":
+		return c < 0
+	case "lte":
+		return c <= 0
+	}
+	return false
+}
+
+// pageNameValues filters, sorts and pages name/value pairs in memory. get
+// returns the name and value of an item. It returns the page, the number of
+// matching items and the next offset when more remain.
+func pageNameValues[T any](items []T, get func(T) (string, string), filters []listFilter, sortField string, desc bool, offset, limit int) ([]T, int, *int) {
+	field := func(item T, name string) string {
+		n, v := get(item)
+		if name == "value" {
+			return v
+		}
+		return n
+	}
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		ok := true
+		for _, f := range filters {
+			if !matchListFilter(f, field(item, f.field)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+	if sortField != "" {
+		sort.SliceStable(matched, func(i, j int) bool {
+			c := compareListValues(field(matched[i], sortField), field(matched[j], sortField))
+			if desc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+
+	total := len(matched)
+	if offset > total {
+		offset = total
+	}
+	end := total
+	var next *int
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+		next = &end
+	}
+	return matched[offset:end], total, next
+}
+
+// listQueryParams reads the offset, limit, sort and filter query parameters
+// of a list endpoint, checking sort and filter against fields. On an invalid
+// value it writes a 400 response and returns false.
+func listQueryParams(w http.ResponseWriter, r *http.Request, fields map[string]listField, offset, limit *int, sortBy *string, filters *[]string) bool {
+	if !queryInts(w, r, map[string]*int{"offset": offset, "limit": limit}) {
+		return false
+	}
+	q := r.URL.Query()
+	*sortBy = q.Get("sort")
+	*filters = q["filter"]
+	if _, _, err := parseListSort(*sortBy, fields); err != nil {
+		api.WriteBadRequest(w, err.Error())
+		return false
+	}
+	if _, err := parseListFilters(*filters, fields); err != nil {
+		api.WriteBadRequest(w, err.Error())
+		return false
+	}
+	return true
+}
+
+// likePrefixPattern returns a LIKE pattern, for use with ESCAPE '!', that
+// matches names starting with prefix.
+func likePrefixPattern(prefix string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(prefix) + "%"
+}
+
+// listLimit returns the page size of a list tool: limit, capped at and
+// defaulting to the server row limit.
+func listLimit(limit int) int {
+	if limit <= 0 || limit > maxRows {
+		return maxRows
+	}
+	return limit
+}
+
+// listPage builds the response envelope page of a list tool call.
+func listPage(offset, limit, total int, next *int) api.Page {
+	return api.Page{Total: total, Offset: offset, Limit: listLimit(limit), HasMore: next != nil, NextOffset: next}
+}
@@ -0,0 +1,166 @@
+// pkg/mysqlmcp/list_params_test.go
+package mysqlmcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/api"
+)
+
+func TestParseListFilters(t *testing.T) {
+	filters, err := parseListFilters([]string{"engine:eq:InnoDB", "rows:GT:1000", "comment:like:a:b%"}, tableListFields)
+	if err != nil {
+		t.Fatalf("parseListFilters failed: %v", err)
+	}
+	where, args := listSQLPredicates(filters, tableListFields)
+	if want := " AND ENGINE = ? AND TABLE_ROWS > ? AND TABLE_COMMENT LIKE ?"; where != want {
+		t.Errorf("predicates = %q, want %q", where, want)
+	}
+	if len(args) != 3 || args[0] != "InnoDB" || args[1] != float64(1000) || args[2] != "a:b%" {
+		t.Errorf("unexpected args: %v", args)
+	}
+
+	for _, bad := range []string{
+		"engine",                    // no operator
+		"TABLE_NAME:eq:x",           // not a field name
+		"engine:regexp:x",           // unknown operator
+		"rows:like:1%",              // like on a numeric field
+		"rows:gt:1; DROP TABLE t",   // not a number
+		"name) OR 1=1 --:eq:orders", // not a field name
+	} {
+		if _, err := parseListFilters([]string{bad}, tableListFields); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestParseListSort(t *testing.T) {
+	if f, desc, err := parseListSort("-rows", tableListFields); err != nil || f != "rows" || !desc {
+		t.Errorf("parseListSort(-rows) = %q, %v, %v", f, desc, err)
+	}
+	if f, _, err := parseListSort("", tableListFields); err != nil || f != "" {
+		t.Errorf("parseListSort('') = %q, %v", f, err)
+	}
+	if _, _, err := parseListSort("TABLE_ROWS", tableListFields); err == nil {
+		t.Error("expected an unknown sort field to be rejected")
+	}
+}
+
+func TestPageNameValues(t *testing.T) {
+	vars := []ServerVariable{
+		{Name: "max_connections", Value: "151"},
+		{Name: "Threads_connected", Value: "9"},
+		{Name: "Threads_running", Value: "12"},
+		{Name: "read_only", Value: "OFF"},
+	}
+	get := func(v ServerVariable) (string, string) { return v.Name, v.Value }
+
+	filters, err := parseListFilters([]string{"name:like:threads%", "value:gt:5"}, variableListFields)
+	if err != nil {
+		t.Fatalf("parseListFilters failed: %v", err)
+	}
+	page, total, next := pageNameValues(vars, get, filters, "value", true, 0, 1)
+	if total != 2 || len(page) != 1 || page[0].Name != "Threads_running" {
+		t.Errorf("expected Threads_running first of 2 (numeric sort), got %v of %d", page, total)
+	}
+	if next == nil || *next != 1 {
+		t.Errorf("expected next offset 1, got %v", next)
+	}
+
+	page, total, next = pageNameValues(vars, get, nil, "", false, 10, 2)
+	if total != 4 || len(page) != 0 || next != nil {
+		t.Errorf("offset past the end: got %v of %d, next %v", page, total, next)
+	}
+}
+
+func TestHTTPListTablesSortFilterTotal(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "TABLE_COMMENT"}).
+		AddRow("orders", "InnoDB", 900, "").
+		AddRow("users", "InnoDB", 100, "")
+	mock.ExpectQuery(`(?s)WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND ENGINE = \?\s+ORDER\s+BY\s+TABLE_ROWS DESC, TABLE_NAME LIMIT \? OFFSET \?`).
+		WithArgs("testdb", "InnoDB", 2, 0).
+		WillReturnRows(rows)
+	mock.ExpectQuery(`(?s)SELECT COUNT\(\*\)\s+FROM\s+information_schema\.TABLES\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND ENGINE = \?$`).
+		WithArgs("testdb", "InnoDB").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(7))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tables?database=testdb&limit=1&sort=-rows&filter=engine:eq:InnoDB", nil)
+	w := httptest.NewRecorder()
+	httpListTables(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp api.Response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Page == nil || resp.Page.Total != 7 || !resp.Page.HasMore || resp.Page.Limit != 1 || *resp.Page.NextOffset != 1 {
+		t.Errorf("unexpected page: %+v", resp.Page)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPListParamsInvalid(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	for _, tt := range []struct {
+		url     string
+		handler http.HandlerFunc
+	}{
+		{"/api/tables?database=testdb&sort=bogus", httpListTables},
+		{"/api/tables?database=testdb&filter=rows:gt:many", httpListTables},
+		{"/api/status?filter=engine:eq:InnoDB", httpListStatus},
+		{"/api/variables?limit=-1", httpListVariables},
+	} {
+		w := httptest.NewRecorder()
+		tt.handler(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tt.url, w.Code)
+		}
+	}
+}
+
+func TestHTTPListStatusPage(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).
+		AddRow("Threads_connected", "5").
+		AddRow("Threads_running", "2").
+		AddRow("Uptime", "12345")
+	mock.ExpectQuery("performance_schema.global_status").WillReturnRows(rows)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/status?filter=name:like:threads%25&sort=value", nil)
+	w := httptest.NewRecorder()
+	httpListStatus(w, req)
+
+	var resp struct {
+		Data ListStatusOutput `json:"data"`
+		Page *api.Page        `json:"page"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Page == nil || resp.Page.Total != 2 || resp.Page.HasMore {
+		t.Errorf("unexpected page: %+v", resp.Page)
+	}
+	if len(resp.Data.Variables) != 2 || resp.Data.Variables[0].Name != "Threads_running" {
+		t.Errorf("expected threads variables sorted by value, got %+v", resp.Data.Variables)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,427 @@
+// pkg/mysqlmcp/logging.go
+package mysqlmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// ===== Structured Logging =====
+
+// LogEntry represents a structured log entry.
+type LogEntry struct {
+	Timestamp string                 `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Component string                 `json:"component,omitempty"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// logLevel orders log levels, lowest first.
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+var logLevelNames = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+
+func (l logLevel) String() string { return logLevelNames[l] }
+
+// parseLogLevel maps a config.LogLevel* value to a logLevel.
+func parseLogLevel(s string) (logLevel, bool) {
+	for i, name := range logLevelNames {
+		if strings.EqualFold(s, name) {
+			return logLevel(i), true
+		}
+	}
+	return levelInfo, false
+}
+
+// logSettings are the levels set by configureLogging.
+type logSettings struct {
+	level      logLevel
+	components map[string]logLevel
+	redactSQL  bool
+}
+
+var currentLogSettings atomic.Pointer[logSettings]
+
+func init() {
+	currentLogSettings.Store(&logSettings{level: levelInfo})
+}
+
+// configureLogging applies the log levels of cfg. --silent still wins: it
+// limits every component to errors.
+func configureLogging(c *config.Config) error {
+	level := c.LogLevel
+	if level == "" {
+		level = config.DefaultLogLevel
+	}
+	if err := config.CheckLogLevels(level, c.ComponentLogLevels); err != nil {
+		return fmt.Errorf("MYSQL_MCP_LOG_LEVEL / MYSQL_MCP_LOG_COMPONENTS: %w", err)
+	}
+	s := &logSettings{components: map[string]logLevel{}, redactSQL: c.LogRedactSQL}
+	s.level, _ = parseLogLevel(level)
+	for name, l := range c.ComponentLogLevels {
+		s.components[name], _ = parseLogLevel(l)
+	}
+	currentLogSettings.Store(s)
+	return nil
+}
+
+// Logger writes leveled log lines for one component. Components without a
+// level of their own log at the global level.
+type Logger struct {
+	component string
+}
+
+// Component loggers. serverLog is for everything not owned by a component.
+var (
+	serverLog    = &Logger{}
+	httpLog      = &Logger{component: "http"}
+	poolLog      = &Logger{component: "pool"}
+	validatorLog = &Logger{component: "validator"}
+	auditLog     = &Logger{component: "audit"}
+)
+
+// Enabled reports whether l writes lines of the given level.
+func (l *Logger) Enabled(level logLevel) bool {
+	if silentMode && level < levelError {
+		return false
+	}
+	s := currentLogSettings.Load()
+	min, ok := s.components[l.component]
+	if !ok {
+		min = s.level
+	}
+	return level >= min
+}
+
+func (l *Logger) Debug(message string, fields map[string]interface{}) {
+	l.log(levelDebug, message, fields)
+}
+
+func (l *Logger) Info(message string, fields map[string]interface{}) {
+	l.log(levelInfo, message, fields)
+}
+
+func (l *Logger) Warn(message string, fields map[string]interface{}) {
+	l.log(levelWarn, message, fields)
+}
+
+func (l *Logger) Error(message string, fields map[string]interface{}) {
+	l.log(levelError, message, fields)
+}
+
+// DebugSQL logs the full text of sqlText at debug level, through loggedSQL.
+func (l *Logger) DebugSQL(message, sqlText string, fields map[string]interface{}) {
+	if !l.Enabled(levelDebug) {
+		return
+	}
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	fields["sql"] = loggedSQL(sqlText, 0)
+	l.log(levelDebug, message, fields)
+}
+
+// loggedSQL is sqlText as written to log lines and audit entries: with its
+// literals replaced by ? when MYSQL_MCP_LOG_REDACT_SQL is set, then cut to
+// maxLen bytes (0 = no limit). Redacting first keeps a value cut in half by
+// the limit out of the log too.
+func loggedSQL(sqlText string, maxLen int) string {
+	if currentLogSettings.Load().redactSQL {
+		sqlText = util.RedactLiterals(sqlText)
+	}
+	if maxLen > 0 {
+		return util.TruncateQuery(sqlText, maxLen)
+	}
+	return sqlText
+}
+
+func (l *Logger) log(level logLevel, message string, fields map[string]interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	if jsonLogging {
+		entry := LogEntry{
+			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+			Level:     level.String(),
+			Component: l.component,
+			Message:   message,
+			Fields:    fields,
+		}
+		data, _ := json.Marshal(entry)
+		fmt.Fprintln(os.Stderr, string(data))
+		return
+	}
+	prefix := "[" + level.String() + "] "
+	if l.component != "" {
+		prefix += l.component + ": "
+	}
+	if len(fields) > 0 {
+		log.Printf("%s%s %v", prefix, message, fields)
+	} else {
+		log.Printf("%s%s", prefix, message)
+	}
+}
+
+// ===== Audit Logging =====
+
+// AuditEntry represents an audit log entry for query tracking.
+type AuditEntry struct {
+	Timestamp     string `json:"timestamp"`
+	RequestID     string `json:"request_id,omitempty"`
+	Source        string `json:"source,omitempty"`         // transport the call came in on: mcp or http (scheduler for scheduled runs)
+	APIKey        string `json:"api_key,omitempty"`        // masked HTTP API key
+	RemoteIP      string `json:"remote_ip,omitempty"`      // HTTP client address
+	Client        string `json:"client,omitempty"`         // MCP client name
+	ClientVersion string `json:"client_version,omitempty"` // MCP client version
+	Connection    string `json:"connection,omitempty"`     // name of the connection the tool ran on
+	DBUser        string `json:"db_user,omitempty"`        // MySQL account of that connection
+	Tool          string `json:"tool"`
+	Database      string `json:"database,omitempty"`
+	Query         string `json:"query,omitempty"`
+	QueryDigest   string `json:"query_digest,omitempty"`
+	DurationMs    int64  `json:"duration_ms"`
+	RowCount      int    `json:"row_count,omitempty"`
+	InputTokens   int    `json:"input_tokens,omitempty"`
+	OutputTokens  int    `json:"output_tokens,omitempty"`
+	Success       bool   `json:"success"`
+	Error         string `json:"error,omitempty"`
+	Duplicate     bool   `json:"duplicate,omitempty"` // answered from an identical call moments earlier, without querying MySQL
+	// Token efficiency metrics
+	TokensPerRow    float64 `json:"tokens_per_row,omitempty"`
+	IOEfficiency    float64 `json:"io_efficiency,omitempty"`
+	CostEstimateUSD float64 `json:"cost_estimate_usd,omitempty"`
+}
+
+// AuditLogger handles writing audit logs to a file.
+type AuditLogger struct {
+	file    *os.File
+	path    string
+	mu      sync.Mutex
+	enabled bool
+	format  string  // config.AuditFormat* value; "" writes JSON
+	sink    Auditor // receives the entries instead of file (Options.Auditor)
+}
+
+const auditReadTailMaxBytes = 512 * 1024
+
+// NewAuditLogger creates a new audit logger.
+// If path is empty, the logger is disabled.
+func NewAuditLogger(path string) (*AuditLogger, error) {
+	if path == "" {
+		return &AuditLogger{enabled: false}, nil
+	}
+	// Clean the path to prevent directory traversal attacks
+	cleanPath := filepath.Clean(path)
+	// #nosec G304 -- path is from trusted environment variable MYSQL_MCP_AUDIT_LOG
+	// #nosec G302 -- audit logs need to be readable by log aggregation tools
+	f, err := os.OpenFile(cleanPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLogger{file: f, path: cleanPath, enabled: true}, nil
+}
+
+// ReadRecentLines returns up to maxLines non-empty lines from the end of the audit file.
+func (a *AuditLogger) ReadRecentLines(maxLines int) ([]string, bool, error) {
+	if !a.enabled || a.path == "" {
+		return nil, false, fmt.Errorf("audit log is not enabled")
+	}
+	if maxLines < 1 {
+		maxLines = 50
+	}
+	if maxLines > 500 {
+		maxLines = 500
+	}
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return nil, false, fmt.Errorf("audit log stat: %w", err)
+	}
+	size := info.Size()
+	var start int64
+	if size > auditReadTailMaxBytes {
+		start = size - auditReadTailMaxBytes
+	}
+	f, err := os.Open(a.path) // #nosec G304 -- path from trusted config only
+	if err != nil {
+		return nil, false, fmt.Errorf("audit log open for read: %w", err)
+	}
+	defer f.Close()
+	if start > 0 {
+		if _, err := f.Seek(start, io.SeekStart); err != nil {
+			return nil, false, fmt.Errorf("audit log seek: %w", err)
+		}
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, false, fmt.Errorf("audit log read: %w", err)
+	}
+	truncated := start > 0
+	if start > 0 {
+		if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+			data = data[idx+1:]
+		}
+	}
+	lines := bytes.Split(data, []byte{'\n'})
+	out := make([]string, 0, maxLines)
+	for i := len(lines) - 1; i >= 0 && len(out) < maxLines; i-- {
+		line := strings.TrimSpace(string(lines[i]))
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, truncated, nil
+}
+
+// Log writes an audit entry to the log file.
+func (a *AuditLogger) Log(entry *AuditEntry) {
+	if !a.enabled {
+		return
+	}
+	now := time.Now().UTC()
+	entry.Timestamp = now.Format(time.RFC3339Nano)
+	if entry.Connection == "" {
+		entry.Connection, entry.DBUser = activeConnectionIdentity()
+	}
+	if a.sink != nil {
+		a.sink.Audit(entry)
+		return
+	}
+	line := formatAuditEntry(a.format, entry, now)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.WriteString(line + "\n"); err != nil {
+		auditLog.Error("audit log write failed", map[string]interface{}{"path": a.path, "error": err.Error()})
+		return
+	}
+	auditLog.Debug("audit entry written", map[string]interface{}{"tool": entry.Tool, "request_id": entry.RequestID, "success": entry.Success})
+}
+
+// LogContext writes entry with the request ID, source and client identity of
+// the tool call in ctx.
+func (a *AuditLogger) LogContext(ctx context.Context, entry *AuditEntry) {
+	if !a.enabled {
+		return
+	}
+	entry.RequestID = requestIDFrom(ctx)
+	entry.Source = requestSourceFrom(ctx)
+	id := clientIdentityFrom(ctx)
+	entry.APIKey, entry.RemoteIP = id.APIKey, id.RemoteIP
+	entry.Client, entry.ClientVersion = id.Client, id.ClientVersion
+	a.Log(entry)
+}
+
+// Close closes the audit log file.
+func (a *AuditLogger) Close() {
+	if a.file != nil {
+		a.file.Close()
+	}
+}
+
+// ===== Query Timing Helper =====
+
+// QueryTimer tracks query execution time and provides logging helpers.
+type QueryTimer struct {
+	start     time.Time
+	tool      string
+	requestID string
+}
+
+// NewQueryTimer creates a new query timer for the given tool. Log lines carry
+// the request ID from ctx, if any.
+func NewQueryTimer(ctx context.Context, tool string) *QueryTimer {
+	return &QueryTimer{start: time.Now(), tool: tool, requestID: requestIDFrom(ctx)}
+}
+
+// Elapsed returns the time elapsed since the timer was created.
+func (t *QueryTimer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// ElapsedMs returns the elapsed time in milliseconds.
+func (t *QueryTimer) ElapsedMs() int64 {
+	return t.Elapsed().Milliseconds()
+}
+
+// LogSuccess logs a successful query execution.
+func (t *QueryTimer) LogSuccess(rowCount int, query string, tokens *TokenUsage, efficiency *TokenEfficiency) {
+	fields := map[string]interface{}{
+		"tool":        t.tool,
+		"duration_ms": t.ElapsedMs(),
+		"row_count":   rowCount,
+	}
+	if t.requestID != "" {
+		fields["request_id"] = t.requestID
+	}
+	if query != "" && len(query) <= 200 {
+		fields["query"] = loggedSQL(query, 0)
+	}
+	if tokens != nil && tokenTracking {
+		tokenFields := map[string]interface{}{
+			"input_estimated":  tokens.InputEstimated,
+			"output_estimated": tokens.OutputEstimated,
+			"total_estimated":  tokens.TotalEstimated,
+			"model":            tokens.Model,
+		}
+		if efficiency != nil {
+			tokenFields["tokens_per_row"] = efficiency.TokensPerRow
+			tokenFields["io_efficiency"] = efficiency.IOEfficiency
+			tokenFields["cost_estimate_usd"] = efficiency.CostEstimateUSD
+		}
+		fields["tokens"] = tokenFields
+	}
+	serverLog.Info("query executed", fields)
+}
+
+// LogError logs a failed query execution.
+func (t *QueryTimer) LogError(err error, query string, tokens *TokenUsage, efficiency *TokenEfficiency) {
+	fields := map[string]interface{}{
+		"tool":        t.tool,
+		"duration_ms": t.ElapsedMs(),
+		"error":       err.Error(),
+	}
+	if t.requestID != "" {
+		fields["request_id"] = t.requestID
+	}
+	if query != "" && len(query) <= 200 {
+		fields["query"] = loggedSQL(query, 0)
+	}
+	if tokens != nil && tokenTracking {
+		tokenFields := map[string]interface{}{
+			"input_estimated":  tokens.InputEstimated,
+			"output_estimated": tokens.OutputEstimated,
+			"total_estimated":  tokens.TotalEstimated,
+			"model":            tokens.Model,
+		}
+		if efficiency != nil {
+			tokenFields["tokens_per_row"] = efficiency.TokensPerRow
+			tokenFields["io_efficiency"] = efficiency.IOEfficiency
+			tokenFields["cost_estimate_usd"] = efficiency.CostEstimateUSD
+		}
+		fields["tokens"] = tokenFields
+	}
+	serverLog.Error("query failed", fields)
+}
@@ -1,8 +1,9 @@
-// cmd/mysql-mcp-server/logging_test.go
-package main
+// pkg/mysqlmcp/logging_test.go
+package mysqlmcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"log"
 	"os"
@@ -10,10 +11,12 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
 )
 
 func TestLogInfoSilentMode(t *testing.T) {
-	// When silentMode is true, logInfo and logWarn must not produce output; logError must still output.
+	// When silentMode is true, Info and Warn must not produce output; Error must still output.
 	oldSilent := silentMode
 	defer func() { silentMode = oldSilent }()
 
@@ -24,9 +27,9 @@ func TestLogInfoSilentMode(t *testing.T) {
 	log.SetOutput(w)
 
 	silentMode = true
-	logInfo("should not appear", map[string]interface{}{"key": "value"})
-	logWarn("also should not appear", nil)
-	logError("this must appear", map[string]interface{}{"error": "test"})
+	serverLog.Info("should not appear", map[string]interface{}{"key": "value"})
+	httpLog.Warn("also should not appear", nil)
+	serverLog.Error("this must appear", map[string]interface{}{"error": "test"})
 
 	w.Close()
 	os.Stderr = oldStderr
@@ -44,8 +47,84 @@ func TestLogInfoSilentMode(t *testing.T) {
 	}
 }
 
+// captureLog configures logging from c and returns the text log lines
+// written by fn.
+func captureLog(t *testing.T, c *config.Config, fn func()) string {
+	t.Helper()
+	oldSettings, oldJSON := currentLogSettings.Load(), jsonLogging
+	oldOutput, oldFlags := log.Writer(), log.Flags()
+	defer func() {
+		currentLogSettings.Store(oldSettings)
+		jsonLogging = oldJSON
+		log.SetOutput(oldOutput)
+		log.SetFlags(oldFlags)
+	}()
+	if err := configureLogging(c); err != nil {
+		t.Fatalf("configureLogging failed: %v", err)
+	}
+	jsonLogging = false
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	fn()
+	return buf.String()
+}
+
+func TestComponentLogLevels(t *testing.T) {
+	out := captureLog(t, &config.Config{LogLevel: "warn", ComponentLogLevels: map[string]string{"validator": "debug"}}, func() {
+		serverLog.Info("server info", nil)
+		serverLog.Warn("server warn", nil)
+		httpLog.Info("http info", nil)
+		validatorLog.Debug("validator debug", nil)
+	})
+	for _, want := range []string{"[WARN] server warn", "[DEBUG] validator: validator debug"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"server info", "http info"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected %q to be filtered out:\n%s", unwanted, out)
+		}
+	}
+
+	if err := configureLogging(&config.Config{LogLevel: "info", ComponentLogLevels: map[string]string{"parser": "debug"}}); err == nil {
+		t.Error("expected an unknown component to be rejected")
+	}
+}
+
+func TestValidatorDebugSQL(t *testing.T) {
+	sqlText := "SELECT name FROM users WHERE email = 'alice@example.com'"
+	out := captureLog(t, &config.Config{LogLevel: "info", ComponentLogLevels: map[string]string{"validator": "debug"}}, func() {
+		if err := validateSQL(sqlText); err != nil {
+			t.Fatalf("validateSQL failed: %v", err)
+		}
+		_ = validateSQL("DROP TABLE users")
+	})
+	if !strings.Contains(out, "query accepted") || !strings.Contains(out, "alice@example.com") {
+		t.Errorf("expected the full SQL at debug level:\n%s", out)
+	}
+	if !strings.Contains(out, "query rejected") || !strings.Contains(out, "DROP TABLE users") {
+		t.Errorf("expected the rejected statement at debug level:\n%s", out)
+	}
+
+	out = captureLog(t, &config.Config{LogLevel: "debug", LogRedactSQL: true}, func() {
+		_ = validateSQL(sqlText)
+	})
+	if strings.Contains(out, "alice@example.com") || !strings.Contains(out, "email = ?") {
+		t.Errorf("expected literals to be redacted:\n%s", out)
+	}
+
+	out = captureLog(t, &config.Config{LogLevel: "info"}, func() {
+		_ = validateSQL(sqlText)
+	})
+	if out != "" {
+		t.Errorf("expected no validator output at info level, got:\n%s", out)
+	}
+}
+
 func TestNewQueryTimer(t *testing.T) {
-	timer := NewQueryTimer("test_tool")
+	timer := NewQueryTimer(context.Background(), "test_tool")
 	if timer == nil {
 		t.Fatal("NewQueryTimer returned nil")
 	}
@@ -74,7 +153,7 @@ func TestQueryTimerLogSuccess(t *testing.T) {
 	os.Stderr = w
 	log.SetOutput(w)
 
-	timer := NewQueryTimer("test_query")
+	timer := NewQueryTimer(context.Background(), "test_query")
 	timer.LogSuccess(5, "SELECT * FROM test", nil, nil)
 
 	w.Close()
@@ -101,7 +180,7 @@ func TestQueryTimerLogError(t *testing.T) {
 	os.Stderr = w
 	log.SetOutput(w)
 
-	timer := NewQueryTimer("test_query")
+	timer := NewQueryTimer(context.Background(), "test_query")
 	timer.LogError(os.ErrNotExist, "SELECT * FROM test", nil, nil)
 
 	w.Close()
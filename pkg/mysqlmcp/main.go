@@ -0,0 +1,1206 @@
+// pkg/mysqlmcp/main.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/dbretry"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// Version information, set by cmd/mysql-mcp-server from its build-time ldflags.
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+	GitCommit = "unknown"
+)
+
+// ===== Global State =====
+
+// Global configuration and state shared by all tools.
+var (
+	cfg         *config.Config
+	connManager *ConnectionManager
+	auditLogger *AuditLogger
+
+	// Convenience aliases from config (for tool access)
+	maxRows        int
+	maxResultBytes int
+	queryTimeout   time.Duration
+	pingTimeout    time.Duration
+	dbRetryCfg     dbretry.Config
+	extendedMode   bool
+	jsonLogging    bool
+	tokenTracking  bool
+	tokenCard      bool
+	tokenModel     string
+	tokenEstimator TokenEstimator
+
+	// silentMode suppresses INFO and WARN logs (--silent); ERROR still goes to stderr.
+	silentMode bool
+)
+
+// ===== Argument Parsing =====
+
+// parsedArgs holds the result of command-line argument parsing.
+type parsedArgs struct {
+	action        string // "", "version", "help", "print-config", "validate-config", "healthcheck", or a subcommand
+	configPath    string // path from --config or --config=
+	validatePath  string // path for --validate-config
+	subjectName   string // connection for test-connection, tool for run-tool
+	toolInput     string // JSON arguments for run-tool (--input)
+	silent        bool   // --silent or -s: suppress INFO/WARN logs
+	daemon        bool   // --daemon: fork to background (HTTP mode)
+	tokenCardFlag bool   // --token-card: enable live token monitoring UI
+	err           error  // parsing error (e.g., unknown flag)
+}
+
+// parseArgs parses command-line arguments and returns the result.
+// This is separated from Main() for testability.
+func parseArgs(args []string) parsedArgs {
+	var result parsedArgs
+
+	for len(args) > 0 {
+		arg := args[0]
+		args = args[1:]
+
+		switch arg {
+		case "--version", "-v":
+			result.action = "version"
+			return result
+		case "--help", "-h", "help":
+			result.action = "help"
+			return result
+		case "--config", "-c":
+			if len(args) < 1 {
+				result.err = fmt.Errorf("--config requires a path argument")
+				return result
+			}
+			result.configPath = args[0]
+			args = args[1:]
+		case "--print-config":
+			result.action = "print-config"
+		case "--healthcheck":
+			result.action = "healthcheck"
+		case "validate-config":
+			// Subcommand form; the path is optional and defaults to the usual search order.
+			result.action = "validate-config"
+			if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+				result.validatePath = args[0]
+				args = args[1:]
+			}
+		case "test-connection":
+			result.action = "test-connection"
+			if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+				result.subjectName = args[0]
+				args = args[1:]
+			}
+		case "list-tools":
+			result.action = "list-tools"
+		case "run-tool":
+			if len(args) < 1 || strings.HasPrefix(args[0], "-") {
+				result.err = fmt.Errorf("run-tool requires a tool name")
+				return result
+			}
+			result.action = "run-tool"
+			result.subjectName = args[0]
+			args = args[1:]
+		case "--input":
+			if len(args) < 1 {
+				result.err = fmt.Errorf("--input requires a JSON argument")
+				return result
+			}
+			result.toolInput = args[0]
+			args = args[1:]
+		case "--validate-config":
+			if len(args) < 1 {
+				result.err = fmt.Errorf("--validate-config requires a path argument")
+				return result
+			}
+			result.action = "validate-config"
+			result.validatePath = args[0]
+			args = args[1:]
+		case "--silent", "-s":
+			result.silent = true
+		case "--daemon", "-d":
+			result.daemon = true
+		case "--token-card":
+			result.tokenCardFlag = true
+		default:
+			// Check if it's --config=path format
+			if len(arg) > 9 && arg[:9] == "--config=" {
+				result.configPath = arg[9:]
+			} else {
+				result.err = fmt.Errorf("unknown flag '%s'", arg)
+				return result
+			}
+		}
+	}
+
+	return result
+}
+
+// ===== Main Entry Point =====
+
+// Main runs the mysql-mcp-server command line with os.Args. It is the whole
+// of cmd/mysql-mcp-server; programs embedding the tools use New instead.
+func Main() {
+	// Parse command-line arguments
+	parsed := parseArgs(os.Args[1:])
+
+	// Handle parsing errors
+	if parsed.err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", parsed.err)
+		printHelp()
+		os.Exit(1)
+	}
+
+	// Set config path if specified
+	if parsed.configPath != "" {
+		config.ConfigFilePath = parsed.configPath
+	}
+	silentMode = parsed.silent
+
+	// Handle immediate actions
+	switch parsed.action {
+	case "version":
+		fmt.Printf("mysql-mcp-server %s\n", Version)
+		fmt.Printf("  Build time: %s\n", BuildTime)
+		fmt.Printf("  Git commit: %s\n", GitCommit)
+		os.Exit(0)
+	case "help":
+		printHelp()
+		os.Exit(0)
+	case "print-config":
+		handlePrintConfig()
+		os.Exit(0)
+	case "validate-config":
+		handleValidateConfig(parsed.validatePath)
+		os.Exit(0)
+	case "healthcheck":
+		os.Exit(handleHealthcheck())
+	case "test-connection":
+		os.Exit(handleTestConnection(parsed.subjectName))
+	case "list-tools":
+		os.Exit(handleListTools())
+	case "run-tool":
+		os.Exit(handleRunTool(parsed.subjectName, parsed.toolInput))
+	}
+
+	// ---- Load configuration ----
+	if err := loadRuntimeConfig(); err != nil {
+		log.Fatalf("config error: %v", err)
+	}
+
+	// Daemon mode requires HTTP mode; defer until after config load so we can check.
+	if parsed.daemon {
+		if !cfg.HTTPMode {
+			fmt.Fprintf(os.Stderr, "Error: daemon mode requires HTTP mode (set MYSQL_MCP_HTTP=1 or http.enabled: true in config)\n")
+			os.Exit(1)
+		}
+		maybeDaemonize(parsed)
+	}
+
+	// CLI --token-card overrides config (OR with config value)
+	tokenCard = cfg.TokenCard || parsed.tokenCardFlag
+
+	// Initialize audit logger
+	if err := openAuditLog(nil); err != nil {
+		log.Fatalf("audit log init error: %v", err)
+	}
+	defer auditLogger.Close()
+
+	// Initialize token estimator (optional)
+	initTokenEstimator()
+
+	// ---- Initialize Connection Manager ----
+	openConnections()
+	defer connManager.Close()
+
+	// Verify we have at least one valid connection
+	if connManager.GetActiveDB() == nil {
+		connManager.Close() // Clean up before exit
+		log.Fatalf("config error: no valid MySQL connections available")
+	}
+
+	if cfg.VirtualConnection != "" {
+		if _, err := enterVirtualConnection(cfg.VirtualConnection); err != nil {
+			connManager.Close()
+			log.Fatalf("config error: virtual connection %q: %v", cfg.VirtualConnection, err)
+		}
+		poolLog.Info("locked to virtual connection", map[string]interface{}{"virtual_connection": cfg.VirtualConnection})
+	}
+
+	_, activeName := connManager.GetActive()
+
+	// Statement warm-up, metrics sampler, pool keepalive and session reaper
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	startBackgroundTasks(backgroundCtx)
+
+	// Log startup configuration
+	serverLog.Info("mysql-mcp-server started", map[string]interface{}{
+		"version":          Version,
+		"buildTime":        BuildTime,
+		"maxRows":          maxRows,
+		"queryTimeout":     queryTimeout.String(),
+		"demoMode":         cfg.DemoMode,
+		"extendedMode":     extendedMode,
+		"vectorMode":       cfg.VectorMode,
+		"httpMode":         cfg.HTTPMode,
+		"metricsHTTP":      cfg.MetricsHTTP,
+		"httpPort":         cfg.HTTPPort,
+		"jsonLogging":      jsonLogging,
+		"logLevel":         cfg.LogLevel,
+		"auditLogEnabled":  auditLogger.enabled,
+		"tokenTracking":    tokenTracking,
+		"tokenCard":        tokenCard,
+		"tokenModel":       tokenModel,
+		"metricsSampling":  cfg.MetricsSampleInterval.String(),
+		"connections":      len(cfg.Connections),
+		"activeConnection": activeName,
+	})
+
+	// If HTTP mode is enabled, start REST API server instead of MCP
+	if cfg.HTTPMode {
+		readiness.markStarted()
+		startHTTPServer(cfg.HTTPPort, toolGroupEnabled(toolGroupVector) || toolGroupEnabled(toolGroupVectorWrite), tokenCard)
+		return
+	}
+
+	// Optional: token metrics + /status on HTTP while MCP uses stdio (Claude Desktop, Cursor)
+	if cfg.MetricsHTTP {
+		go startTokenMetricsHTTPServer(cfg.HTTPPort, tokenCard)
+	}
+
+	// ---- Build MCP server ----
+	server := newMCPServer()
+
+	readiness.markStarted()
+
+	// ---- Run over stdio ----
+	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadRuntimeConfig loads the configuration and everything derived from it:
+// access control, saved queries, reports and the package-level aliases.
+func loadRuntimeConfig() error {
+	loaded, err := config.Load()
+	if err != nil {
+		return err
+	}
+	return initRuntimeConfig(loaded)
+}
+
+// initRuntimeConfig makes loaded the configuration and sets up everything
+// derived from it.
+func initRuntimeConfig(loaded *config.Config) error {
+	cfg = loaded
+	initAccessControl(cfg.AllowedDatabases)
+	initConfirmPolicy(cfg.ConfirmRequired)
+	initVirtualConnections(cfg.VirtualConnections)
+	initConcurrencyLimits(cfg)
+	initCircuitBreakers(cfg)
+	initWebhooks(cfg)
+	queryJobs = newJobStore(cfg)
+	initPseudonymizer(cfg)
+	initDuplicates(cfg)
+	initSchemaWatch(cfg)
+	if !config.ValidBinaryOutput(cfg.BinaryOutput) {
+		return fmt.Errorf("MYSQL_MCP_BINARY_OUTPUT / query.binary_output '%s' must be one of hex, base64, length, skip or raw", cfg.BinaryOutput)
+	}
+	if err := configureLogging(cfg); err != nil {
+		return err
+	}
+	for _, c := range cfg.Connections {
+		if err := config.CheckReplicas(c); err != nil {
+			return err
+		}
+		if err := checkRowPolicies(c); err != nil {
+			return err
+		}
+	}
+	if err := config.CheckSessionReaper(cfg); err != nil {
+		return err
+	}
+	if cfg.VectorWrite && cfg.StrictReadOnly {
+		return fmt.Errorf("MYSQL_MCP_VECTOR_WRITE / features.vector_write cannot be combined with strict read-only connections")
+	}
+	if cfg.AuditFormat != "" && !config.ValidAuditFormat(cfg.AuditFormat) {
+		return fmt.Errorf("MYSQL_MCP_AUDIT_FORMAT / logging.audit.format '%s' must be one of json, cef or leef", cfg.AuditFormat)
+	}
+	if cfg.IdentifierCase != "" && !config.ValidIdentifierCase(cfg.IdentifierCase) {
+		return fmt.Errorf("MYSQL_MCP_IDENTIFIER_CASE / query.identifier_case '%s' must be one of preserve, lower or catalog", cfg.IdentifierCase)
+	}
+	if cfg.ProxyCompat != "" && !config.ValidProxyCompat(cfg.ProxyCompat) {
+		return fmt.Errorf("MYSQL_MCP_PROXY_COMPAT / query.proxy_compat '%s' must be one of auto, on or off", cfg.ProxyCompat)
+	}
+	if cfg.TimeZone != "" && !config.ValidTimeZone(cfg.TimeZone) {
+		return fmt.Errorf("MYSQL_MCP_TIME_ZONE / query.time_zone '%s' must be SYSTEM, an offset such as +00:00 or a zone name such as Europe/Berlin", cfg.TimeZone)
+	}
+	if cfg.Locale != "" && !config.ValidLocale(cfg.Locale) {
+		return fmt.Errorf("MYSQL_MCP_LOCALE / locale '%s' must be one of en, de, ja or th", cfg.Locale)
+	}
+	if err := config.CheckWebhooks(cfg.Webhooks); err != nil {
+		return fmt.Errorf("MYSQL_MCP_WEBHOOK_URL / webhooks: %w", err)
+	}
+	if err := checkToolFlags(cfg.ToolFlags); err != nil {
+		return err
+	}
+	if err := checkVirtualConnections(cfg); err != nil {
+		return err
+	}
+	if err := loadSavedQueries(cfg.SavedQueries); err != nil {
+		return err
+	}
+	if err := loadReports(cfg.Reports); err != nil {
+		return err
+	}
+	if err := loadSchedules(cfg.Schedules); err != nil {
+		return err
+	}
+	if cfg.BookmarksFile != "" {
+		store, err := openBookmarkStore(cfg.BookmarksFile)
+		if err != nil {
+			return err
+		}
+		bookmarks = store
+		readiness.recordSubsystem("bookmarks", fmt.Sprintf("ok (%d)", len(store.list())))
+	}
+	if cfg.PlanBaselineFile != "" {
+		store, err := openPlanBaselineStore(cfg.PlanBaselineFile)
+		if err != nil {
+			return err
+		}
+		planBaselines = store
+		readiness.recordSubsystem("plan_baselines", fmt.Sprintf("ok (%d)", store.count()))
+	}
+	if err := initQuotas(cfg); err != nil {
+		return err
+	}
+	if quotas != nil {
+		readiness.recordSubsystem("quotas", fmt.Sprintf("ok (%d tracked)", len(quotas.usage)))
+	}
+	readiness.recordSubsystem("saved_queries", fmt.Sprintf("ok (%d)", len(savedQueries.list())))
+	readiness.recordSubsystem("reports", fmt.Sprintf("ok (%d)", len(reports)))
+
+	// Set convenience aliases
+	maxRows = cfg.MaxRows
+	maxResultBytes = cfg.MaxResultBytes
+	binaryOutput = cfg.BinaryOutput
+	queryTimeout = cfg.QueryTimeout
+	pingTimeout = cfg.PingTimeout
+	dbRetryCfg = dbretry.Config{
+		MaxRetries:      cfg.DBRetryMaxRetries,
+		InitialInterval: cfg.DBRetryInitialInterval,
+		MaxInterval:     cfg.DBRetryMaxInterval,
+	}
+	if dbRetryCfg.MaxInterval <= 0 {
+		dbRetryCfg.MaxInterval = 10 * time.Second
+	}
+	extendedMode = cfg.ExtendedMode
+	jsonLogging = cfg.JSONLogging
+	tokenTracking = cfg.TokenTracking
+	tokenModel = cfg.TokenModel
+	return nil
+}
+
+// openAuditLog opens the audit log of cfg. A non-nil sink receives the
+// entries instead of the log file (see Options.Auditor).
+func openAuditLog(sink Auditor) error {
+	if sink != nil {
+		auditLogger = &AuditLogger{enabled: true, sink: sink}
+	} else {
+		var err error
+		if auditLogger, err = NewAuditLogger(cfg.AuditLogPath); err != nil {
+			return err
+		}
+	}
+	auditLogger.format = cfg.AuditFormat
+	if auditLogger.enabled {
+		readiness.recordSubsystem("audit_log", "ok")
+	} else {
+		readiness.recordSubsystem("audit_log", "disabled")
+	}
+	return nil
+}
+
+// initTokenEstimator sets up token estimation when it is turned on; a
+// tokenizer that fails to load turns it off again.
+func initTokenEstimator() {
+	if !tokenTracking {
+		return
+	}
+	var err error
+	tokenEstimator, err = NewTokenEstimator(tokenModel)
+	if err != nil {
+		serverLog.Warn("token tracking requested but tokenizer init failed; disabling token tracking", map[string]interface{}{
+			"error": err.Error(),
+			"model": tokenModel,
+		})
+		tokenTracking = false
+		tokenEstimator = nil
+		readiness.recordSubsystem("token_estimator", "degraded: "+err.Error())
+		return
+	}
+	readiness.recordSubsystem("token_estimator", "ok")
+}
+
+// startBackgroundTasks starts the work that runs beside tool calls until ctx
+// is canceled: statement warm-up, the metrics_history sampler, webhook
+// delivery, scheduled saved queries, the schema watcher, the keepalive of
+// idle pooled connections and the reaper of abandoned sessions.
+func startBackgroundTasks(ctx context.Context) {
+	// Prepare the fixed metadata queries in the background so the first
+	// schema tool calls do not pay the prepare round trips.
+	if cfg.PreparedStatements {
+		go func() {
+			warmCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+			warmUpStatements(warmCtx, connManager)
+		}()
+	}
+
+	// Optional background status sampler for metrics_history
+	if cfg.MetricsSampleInterval > 0 {
+		globalMetricsSampler = newMetricsSampler(cfg.MetricsSampleInterval, cfg.MetricsHistorySize)
+		go globalMetricsSampler.Run(ctx)
+		readiness.recordSubsystem("metrics_sampler", "ok")
+	}
+
+	// Optional webhooks for blocked and slow queries and unhealthy connections
+	if webhooks != nil {
+		go webhooks.run(ctx)
+		readiness.recordSubsystem("webhooks", fmt.Sprintf("ok (%d)", len(cfg.Webhooks)))
+	}
+
+	// Optional background runs of saved queries for scheduled_results
+	if len(schedules) > 0 {
+		startSchedules(ctx)
+		readiness.recordSubsystem("schedules", fmt.Sprintf("ok (%d)", len(schedules)))
+	}
+
+	// Optional schema change watcher for schema_changes
+	if schemaWatch != nil {
+		go schemaWatch.run(ctx)
+		readiness.recordSubsystem("schema_watch", fmt.Sprintf("ok (%d)", len(schemaWatch.databases)))
+	}
+
+	// Optional quota state file, written as counters change
+	if quotas != nil && quotas.path != "" {
+		go quotas.run(ctx)
+	}
+
+	// Optional keepalive of idle pooled connections and reaper of abandoned sessions
+	startPoolMaintenance(ctx, cfg)
+}
+
+// openConnections creates connManager and adds every configured connection.
+// Connections that fail to open are logged and skipped.
+func openConnections() {
+	connManager = NewConnectionManager()
+	for _, connCfg := range cfg.Connections {
+		if err := connManager.AddConnectionWithPoolConfig(connCfg, cfg); err != nil {
+			poolLog.Warn("failed to add connection", map[string]interface{}{"name": connCfg.Name, "error": err.Error()})
+		} else if connCfg.ConnectOnDemand {
+			poolLog.Info("connection registered; opens on first use", map[string]interface{}{
+				"name": connCfg.Name,
+				"dsn":  util.MaskDSN(connCfg.DSN),
+			})
+		} else {
+			poolLog.Info("connection added", map[string]interface{}{
+				"name": connCfg.Name,
+				"dsn":  util.MaskDSN(connCfg.DSN),
+			})
+		}
+	}
+}
+
+// newMCPServer builds the MCP server with every tool enabled by cfg.
+func newMCPServer() *mcp.Server {
+	server := mcp.NewServer(
+		&mcp.Implementation{
+			Name:    "mysql-mcp-server",
+			Version: Version,
+		},
+		nil,
+	)
+
+	// Register core tools
+	registerCoreTools(server)
+
+	// Register the saved query library tools
+	registerSavedQueryTools(server)
+
+	// Register multi-DSN tools
+	registerConnectionTools(server)
+
+	// Register vector tools (MYSQL_MCP_VECTOR=1 or features.tools)
+	if toolGroupEnabled(toolGroupVector) || toolGroupEnabled(toolGroupVectorWrite) {
+		registerVectorTools(server)
+	}
+
+	// Register extended tools (MYSQL_MCP_EXTENDED=1 or features.tools)
+	if toolGroupEnabled(toolGroupExtended) {
+		registerExtendedTools(server)
+	}
+
+	// Register the canned analysis prompts
+	registerPrompts(server)
+
+	// Hide tools the client's role cannot call or features.tools turns off
+	// (calls are checked in the tool wrappers)
+	if rbacEnabled() {
+		server.AddReceivingMiddleware(filterToolsByRole)
+	}
+	if toolFlagsEnabled() {
+		server.AddReceivingMiddleware(filterDisabledTools)
+	}
+
+	// Announce schema changes to this server's clients
+	if schemaWatch != nil {
+		schemaWatch.addServer(server)
+	}
+	return server
+}
+
+// ===== Tool Registration =====
+
+func registerCoreTools(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name:        "list_databases",
+		Description: "List accessible databases in the configured MySQL server; on servers with many schemas, narrow with prefix and page with offset/limit",
+	}, toolListDatabasesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_tables",
+		Description: "List tables in a given database",
+	}, toolListTablesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "describe_table",
+		Description: "Describe columns of a given table",
+	}, toolDescribeTableWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name: "run_query",
+		Description: "Execute a read-only SQL query (SELECT/SHOW/DESCRIBE/EXPLAIN only). " +
+			"IMPORTANT: Always specify only the columns you need instead of SELECT * to reduce " +
+			"payload size and improve performance. Results are automatically capped at the " +
+			"configured row limit (default: 200 rows); include a LIMIT clause in your query " +
+			"to request fewer rows. For large SELECT result sets, use the offset field with " +
+			"max_rows as page size (omit LIMIT from SQL; the server injects LIMIT/OFFSET). " +
+			"The response includes has_more and next_offset when another page may exist. " +
+			"Pass format=table to get the rows as a mysql client-style text table, e.g. to quote them in an answer. " +
+			"Pass dedupe=true to drop repeated rows; a warning flags results with many duplicates, often a fan-out join. " +
+			"Apply MySQL optimization guidelines (e.g., filter early, use indexed columns, " +
+			"avoid functions on indexed columns, use EXPLAIN) before executing.",
+	}, toolRunQueryWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "run_cross_database_query",
+		Description: "Run a SELECT that joins tables from different databases on the active connection. List every table as database.table in tables; bare table names in sql are qualified from that list, so no default database is needed. Returns the final SQL and the run_query result.",
+	}, toolRunCrossDatabaseWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "fetch_cell",
+		Description: "Read the full value of a cell that run_query or run_saved_query cut to fit the result byte limit, or a byte range of it. Pass a handle from the result's cell_handles; the query is re-run, so an unordered or changing result may yield a different row. Page with offset/next_offset.",
+	}, toolFetchCellWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "validate_query",
+		Description: "Dry-run a query without executing it: runs run_query's validation and access checks plus EXPLAIN, and returns whether it would be accepted (with the failing stage and reason if not), the tables it reads, the SQL after LIMIT injection, estimated rows and optimizer cost. Use it to self-correct before run_query.",
+	}, toolValidateQueryWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "explain_validation",
+		Description: "List every validation rule a query breaks and why: multi-statement, parser (syntax, statement type, dangerous functions, system schemas, data-modifying CTEs), regex defense-in-depth patterns and the database allowlist, each with a hint. Runs offline; use it when run_query rejects a query with a terse error.",
+	}, toolExplainValidationWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "ping",
+		Description: "Test database connectivity and measure latency",
+	}, toolPingWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "server_info",
+		Description: "Get MySQL server version, uptime, and configuration details. Pass detailed=true for health metrics (ping ms, threads_running, slow_queries, buffer pool hit rate). Reports the connection's capability matrix (performance_schema, PROCESS privilege, version features) and the tools it cannot run, with alternatives. When MYSQL_MCP_TOKEN_TRACKING=1, includes token usage totals.",
+	}, toolServerInfoWrapped)
+}
+
+func registerSavedQueryTools(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name:        "list_saved_queries",
+		Description: "List the curated saved queries (name, description, SQL, parameters) that run_saved_query can execute. Prefer these over free-form SQL when one fits.",
+	}, toolListSavedQueriesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "run_saved_query",
+		Description: "Run a saved query by name with parameter values (bound, never interpolated). Results have the same shape and row cap as run_query.",
+	}, toolRunSavedQueryWrapped)
+
+	if cfg.SaveQueryTool {
+		addTool(server, &mcp.Tool{
+			Name:        "save_query",
+			Description: "Register a named read-only query with :name parameters for run_saved_query (kept in memory until restart; cannot replace config-file queries). Requires MYSQL_MCP_SAVE_QUERY_TOOL=1.",
+		}, toolSaveQueryWrapped)
+	}
+
+	if len(reports) > 0 {
+		addTool(server, &mcp.Tool{
+			Name:        "list_reports",
+			Description: "List the report templates (name, description, variables, sections) that run_report can execute.",
+		}, toolListReportsWrapped)
+
+		addTool(server, &mcp.Tool{
+			Name:        "run_report",
+			Description: "Run a multi-query report template by name with typed variables. Returns one named result section per query; a failing section reports its error without stopping the others.",
+		}, toolRunReportWrapped)
+	}
+
+	if len(schedules) > 0 {
+		addTool(server, &mcp.Tool{
+			Name:        "scheduled_results",
+			Description: "Status and latest results of the saved queries run on a schedule. Pass a schedule name for its latest result and the rows added and removed since the previous run; omit it to list the schedules.",
+		}, toolScheduledResultsWrapped)
+	}
+
+	if schemaWatch != nil {
+		addTool(server, &mcp.Tool{
+			Name:        "schema_changes",
+			Description: "Recent table and column changes in the databases watched by schema_watch, newest first: tables added or dropped and columns added, dropped or redefined, found by snapshotting information_schema every interval. Check it when a query fails on a missing table or column.",
+		}, toolSchemaChangesWrapped)
+	}
+}
+
+func registerConnectionTools(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name:        "list_connections",
+		Description: "List all configured MySQL connections and show which is active",
+	}, toolListConnectionsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "use_connection",
+		Description: "Switch to a different MySQL connection by name",
+	}, toolUseConnectionWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "pool_stats",
+		Description: "Connection pool statistics per configured connection: open, in-use and idle connections, wait count and duration, and connections closed by idle/lifetime limits",
+	}, toolPoolStatsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "usage_stats",
+		Description: "Per-tool usage since startup: call counts, error rates, p50/p95 latency and rows returned. Tools that are registered but never called show zero calls; use it to decide which tools to enable.",
+	}, toolUsageStatsWrapped)
+}
+
+func registerVectorTools(server *mcp.Server) {
+	serverLog.Info("Registering MySQL vector tools (MySQL 9.0+ required)...", nil)
+
+	addTool(server, &mcp.Tool{
+		Name:        "vector_search",
+		Description: "Perform similarity search on vector columns (MySQL 9.0+ required)",
+	}, toolVectorSearchWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "vector_info",
+		Description: "List vector columns and their properties in a database",
+	}, toolVectorInfoWrapped)
+
+	if cfg.VectorWrite {
+		addTool(server, &mcp.Tool{
+			Name:        "vector_insert",
+			Description: "Insert rows into a table with a VECTOR column (upsert=true updates rows that hit a primary or unique key). Each vector must match the column's dimensions. Requires MYSQL_MCP_VECTOR_WRITE=1.",
+		}, toolVectorInsertWrapped)
+		addTool(server, &mcp.Tool{
+			Name:        "vector_delete",
+			Description: "Delete rows of a table with a VECTOR column by key (DELETE ... WHERE key_column IN (keys)). Requires MYSQL_MCP_VECTOR_WRITE=1.",
+		}, toolVectorDeleteWrapped)
+	}
+}
+
+func registerExtendedTools(server *mcp.Server) {
+	serverLog.Info("Registering extended MySQL tools...", nil)
+
+	if cfg.ProcessAdmin {
+		addTool(server, &mcp.Tool{
+			Name:        "process_list",
+			Description: "Show active server threads (SHOW PROCESSLIST). Requires MYSQL_MCP_PROCESS_ADMIN=1 and PROCESS privilege.",
+		}, toolProcessListWrapped)
+		addTool(server, &mcp.Tool{
+			Name:        "kill_query",
+			Description: "Cancel the currently executing statement for a connection using id from process_list (KILL QUERY; does not disconnect the client). Requires MYSQL_MCP_PROCESS_ADMIN=1.",
+		}, toolKillQueryWrapped)
+	}
+
+	if cfg.SessionsTool {
+		addTool(server, &mcp.Tool{
+			Name:        "list_sessions",
+			Description: "Read-only, sanitized view of client sessions (performance_schema.threads or SHOW PROCESSLIST): client ports stripped, credentials redacted, only your own user unless include_other_users=true. No kill support. Requires MYSQL_MCP_SESSIONS_TOOL=1.",
+		}, toolListSessionsWrapped)
+	}
+
+	if cfg.ReadAuditTool && auditLogger != nil && auditLogger.enabled && cfg.AuditLogPath != "" {
+		addTool(server, &mcp.Tool{
+			Name:        "read_audit_log",
+			Description: "Return the last lines of the configured MYSQL_MCP_AUDIT_LOG file (read-only). Requires MYSQL_MCP_READ_AUDIT_TOOL=1.",
+		}, toolReadAuditLogWrapped)
+	}
+
+	if cfg.SlowQueryTool {
+		addTool(server, &mcp.Tool{
+			Name:        "slow_query_log",
+			Description: "Read recent rows from mysql.slow_log when slow_query_log uses TABLE output; otherwise summarize settings. Requires MYSQL_MCP_SLOW_QUERY_TOOL=1.",
+		}, toolSlowQueryLogWrapped)
+	}
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_indexes",
+		Description: "List indexes on a table",
+	}, toolListIndexesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "show_create_table",
+		Description: "Show the CREATE TABLE statement for a table",
+	}, toolShowCreateTableWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "explain_query",
+		Description: "Get the execution plan for a SELECT query",
+	}, toolExplainQueryWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "check_saved_queries",
+		Description: "Plan regression check for the saved query library: EXPLAINs each saved query with sample parameter values and compares every table's access type and index with the baseline plan recorded on its first check, reporting new full scans and access type regressions. Baselines persist in MYSQL_MCP_PLAN_BASELINE_FILE when set; update_baseline accepts the current plans.",
+	}, toolCheckSavedQueriesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "check_partition_pruning",
+		Description: "Report whether a SELECT prunes partitions: compares the partitions EXPLAIN will access with information_schema.PARTITIONS for each partitioned table in the plan",
+	}, toolPartitionPruningWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "optimizer_trace",
+		Description: "Explain why MySQL chose a plan: enables optimizer_trace for one session, runs EXPLAIN on the SELECT and returns the plan plus the optimizer trace JSON (size-capped by max_bytes)",
+	}, toolOptimizerTraceWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "session_settings",
+		Description: "Show the sql_mode, optimizer_switch flags and transaction isolation level query tools run with on the active connection, including overrides from set_session_setting",
+	}, toolSessionSettingsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "set_session_setting",
+		Description: "Change how later queries and EXPLAINs on the active connection are checked or planned: add or remove allow-listed sql_mode modes (e.g. ONLY_FULL_GROUP_BY) or turn optimizer_switch flags on or off (e.g. hash_join=off). Applied per call and restored afterwards; reset drops the overrides.",
+	}, toolSetSessionSettingWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "estimate_rows",
+		Description: "Estimate result size before running a query: optimizer row estimate for a SELECT (via EXPLAIN) and/or information_schema TABLE_ROWS for a table, with a run/paginate/refine recommendation against the row cap",
+	}, toolEstimateRowsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "table_counts",
+		Description: "Row counts for several tables of a database in one call: mode=estimate (default) reads information_schema TABLE_ROWS with one query; mode=exact runs COUNT(*) for every table inside one consistent snapshot, so the counts agree with each other. Use it instead of one COUNT(*) query per table.",
+	}, toolTableCountsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "normalize_query",
+		Description: "Return the fingerprint (literals replaced by ?) and digest of a SQL statement plus the tables and columns it references, without executing it. Use the digest to group or deduplicate queries.",
+	}, toolNormalizeQueryWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_views",
+		Description: "List views in a database",
+	}, toolListViewsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "view_lineage",
+		Description: "Trace where the columns of a database's views come from: parses each VIEW definition and returns, per view column, the base table columns it derives from, whether it is computed (with the expression) and which tables the view reads. Use it to check a view's provenance before relying on it.",
+	}, toolViewLineageWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_ghost_tables",
+		Description: "Find leftovers of online schema changes in a database: gh-ost (_t_gho, _t_ghc, _t_del), pt-online-schema-change (_t_new, _t_old) and LHM tables, and their triggers. Use it to tell real tables from migration artifacts in list_tables output.",
+	}, toolListGhostTablesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_summary_tables",
+		Description: "Find rollup and materialized summary tables in a database (declared in summary_tables or named like agg_*, *_summary, *_daily, *_by_month) with the fact tables they summarize, their grain and freshness (MAX of updated_at or the configured column). Check it before aggregating a large fact table: a summary at the right grain answers the same question far more cheaply.",
+	}, toolListSummaryWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "data_freshness",
+		Description: "Check how up to date a database's tables are before analysing them: the newest value of each table's configured timestamp column (freshness.tables), otherwise its information_schema UPDATE_TIME, with the age in seconds. Answers are cached briefly; pass refresh=true to re-read.",
+	}, toolDataFreshnessWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_triggers",
+		Description: "List triggers in a database",
+	}, toolListTriggersWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_procedures",
+		Description: "List stored procedures in a database",
+	}, toolListProceduresWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_functions",
+		Description: "List stored functions in a database",
+	}, toolListFunctionsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_partitions",
+		Description: "List partitions of a table",
+	}, toolListPartitionsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "database_size",
+		Description: "Get size information for databases",
+	}, toolDatabaseSizeWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "table_size",
+		Description: "Get size information for tables",
+	}, toolTableSizeWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "foreign_keys",
+		Description: "List foreign key constraints",
+	}, toolForeignKeysWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "table_constraints",
+		Description: "List a table's PRIMARY KEY, UNIQUE, FOREIGN KEY and CHECK constraints with their columns and check expressions",
+	}, toolTableConstraintsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "spatial_info",
+		Description: "List spatial (GEOMETRY, POINT, POLYGON, ...) columns with their SRIDs and SPATIAL indexes, flagging indexes the optimizer cannot use",
+	}, toolSpatialInfoWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "schema_graph",
+		Description: "Foreign key relationship graph of a database as nodes/edges, optionally rendered as DOT or Mermaid",
+	}, toolSchemaGraphWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "generate_data_dictionary",
+		Description: "Per-table documentation (columns, indexes, foreign keys, row estimate, size) for a database, paginated across tables",
+	}, toolDataDictionaryWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_status",
+		Description: "List MySQL server status variables",
+	}, toolListStatusWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "list_variables",
+		Description: "List MySQL server configuration variables",
+	}, toolListVariablesWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "health_report",
+		Description: "One-call server health summary: uptime, buffer pool hit ratio, connection usage, tmp disk tables, slow query rate, replication lag, and top wait events, each with an ok/warning/critical severity flag and an overall status",
+	}, toolHealthReportWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "resource_usage",
+		Description: "Capacity summary: the largest memory consumers from performance_schema (sys.memory_global_by_current_bytes), temporary table, sort and join counters explained with their rates, and checks flagging temp tables on disk, sort merge passes and joins without indexes against thresholds. Use it instead of reading raw SHOW STATUS keys.",
+	}, toolResourceUsageWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "binlog_status",
+		Description: "Binary log and GTID status in one read-only call: log files and sizes, current file/position, binlog format, executed/purged GTID sets and retention settings",
+	}, toolBinlogStatusWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "show_grants",
+		Description: "Privileges of the current MySQL account (SHOW GRANTS FOR CURRENT_USER) parsed into per-object grants and roles, optionally filtered to one database; useful to explain access denied errors. Never reports other accounts or password hashes.",
+	}, toolShowGrantsWrapped)
+
+	if globalMetricsSampler != nil {
+		addTool(server, &mcp.Tool{
+			Name:        "metrics_history",
+			Description: "Trends from the background status sampler: per-counter deltas and rates (QPS, bytes sent/received, slow queries) and gauge min/max/avg (threads, buffer pool pages) over a time window. Requires MYSQL_MCP_METRICS_SAMPLE_SECONDS.",
+		}, toolMetricsHistoryWrapped)
+	}
+
+	addTool(server, &mcp.Tool{
+		Name:        "search_schema",
+		Description: "Find tables and columns matching a pattern across databases",
+	}, toolSearchSchemaWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "find_columns",
+		Description: "Find columns by name pattern and/or data type across one or all accessible databases (system schemas excluded by default), e.g. every column like %email%",
+	}, toolFindColumnsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name: "suggest_identifiers",
+		Description: "Complete a database, table or column name from a prefix (or database.table.prefix) using the cached schema catalog; " +
+			"also returns near misses, so a misspelled name in generated SQL can be repaired without running it",
+	}, toolSuggestIdentsWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "fulltext_search",
+		Description: "Relevance-ranked MATCH ... AGAINST search (natural language, boolean or query expansion mode) on a table's FULLTEXT index; the index is validated and the search text is bound as a parameter",
+	}, toolFulltextSearchWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "profile_column",
+		Description: "Profile one column: row and NULL counts, min/max, distinct count and top-K values (sampled), and a per-month distribution for date/datetime/timestamp columns",
+	}, toolProfileColumnWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "pii_scan",
+		Description: "Sample a table and flag columns that likely hold PII (emails, phone numbers, national IDs, credit cards, names, addresses) by value patterns and column names; sampled values are never returned",
+	}, toolPIIScanWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "heatwave_status",
+		Description: "HeatWave (MySQL HeatWave on OCI/AWS/Azure) status: cluster state, tables defined with SECONDARY_ENGINE=RAPID and their load progress, and with sql, whether EXPLAIN offloads the query to HeatWave. Fails on servers without the HeatWave engine.",
+	}, toolHeatWaveStatusWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "heatwave_ml_predict",
+		Description: "Score rows with a trained HeatWave AutoML model via sys.ML_PREDICT_ROW (read-only): pass feature rows, or database, table and columns to score table rows. Fails on servers without the HeatWave engine.",
+	}, toolHeatWavePredictWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "aurora_topology",
+		Description: "Amazon Aurora MySQL cluster topology from information_schema.replica_host_status: the writer and reader instances with their endpoints, replica lag and CPU, and which one the active connection is on. Fails on servers that are not Aurora.",
+	}, toolAuroraTopologyWrapped)
+
+	addTool(server, &mcp.Tool{
+		Name:        "schema_diff",
+		Description: "Compare the schema between two databases",
+	}, toolSchemaDiffWrapped)
+}
+
+// ===== Config File Commands =====
+
+func handlePrintConfig() {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(config.PrintConfig(cfg))
+}
+
+func handleValidateConfig(path string) {
+	if path == "" {
+		path = config.FindConfigFile()
+	}
+	if path == "" {
+		fmt.Fprintf(os.Stderr, "Config validation failed: no config file found (pass a path or --config)\n")
+		os.Exit(1)
+	}
+	if err := config.ValidateConfigFile(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Config validation failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Config file %s is valid\n", path)
+}
+
+// ===== Help and Usage =====
+
+func printHelp() {
+	fmt.Printf(`mysql-mcp-server - MySQL Server for Model Context Protocol (MCP)
+
+USAGE:
+    mysql-mcp-server [OPTIONS]
+    mysql-mcp-server <SUBCOMMAND> [ARGS] [OPTIONS]
+
+SUBCOMMANDS:
+    validate-config [PATH]      Validate a config file (default: the config file search order)
+    test-connection [NAME]      Ping one configured connection (default: all) and print server version
+    list-tools                  List the MCP tools enabled by the current configuration
+    run-tool NAME [--input JSON]
+                                Call one tool with JSON arguments (use --input - to read stdin)
+                                and print its result, without an MCP client
+
+OPTIONS:
+    -h, --help                  Show this help message
+    -v, --version               Show version information
+    -c, --config PATH           Use config file at PATH
+    -s, --silent                Suppress INFO and WARN logs (ERROR still printed)
+    -d, --daemon                Run in background (fork and detach; use with MYSQL_MCP_HTTP=1)
+    --token-card                Enable live token monitoring UI at /status (HTTP mode)
+    --print-config              Print current configuration as YAML
+    --validate-config PATH      Validate config file at PATH
+    --healthcheck               Check readiness (GET /ready in HTTP mode, in-process ping in stdio mode); exits 1 if not ready
+
+DESCRIPTION:
+    A fast, read-only MySQL Server for the Model Context Protocol (MCP).
+    Exposes safe MySQL introspection tools to Claude Desktop via MCP.
+
+CONFIGURATION:
+    Configuration can be provided via config file or environment variables.
+    Environment variables take precedence over config file values.
+
+    Config file search order:
+        1. --config flag or MYSQL_MCP_CONFIG env var
+        2. ./mysql-mcp-server.yaml (current directory)
+        3. ~/.config/mysql-mcp-server/config.yaml (user config)
+        4. /etc/mysql-mcp-server/config.yaml (system config)
+
+    Required (via env var or config file):
+        MYSQL_DSN                    MySQL DSN (e.g., user:pass@tcp(localhost:3306)/db)
+
+    Optional:
+        MYSQL_MAX_ROWS               Max rows returned per query (default: 200)
+        MYSQL_MCP_DATABASE_MAX_ROWS  Per-database row caps for run_query (e.g. analytics=1000,logs=50)
+        MYSQL_MCP_DUPLICATE_WINDOW_SECONDS  Answer a run_query repeated in the same MCP session within N seconds from the earlier result (default: off)
+        MYSQL_MCP_INJECT_LIMIT       Append LIMIT to SELECTs without one (default: 1; 0 = truncate client-side)
+        MYSQL_MCP_SUGGEST_IDENTIFIERS  Add the closest names to unknown table/column errors of run_query (default: 1)
+        MYSQL_MCP_IDENTIFIER_CASE    Database/table name case on case-sensitive servers: preserve (default), lower or catalog
+        MYSQL_MCP_PROXY_COMPAT       Avoid USE and session variables behind ProxySQL/Vitess: auto (default), on or off
+        MYSQL_MCP_TIME_ZONE          Session time_zone for queries, e.g. +00:00 or Europe/Berlin (default: server setting)
+        MYSQL_MCP_PLAN_BASELINE_FILE JSON file keeping check_saved_queries plan baselines (default: in memory)
+        MYSQL_QUERY_TIMEOUT_SECONDS  Query timeout in seconds (default: 30)
+        MYSQL_QUERY_TIMEOUT          Query timeout in milliseconds (e.g. 30000); overridden by MYSQL_QUERY_TIMEOUT_SECONDS
+        MYSQL_MCP_DEMO               Serve a built-in read-only sample schema instead of MySQL (set to 1)
+        MYSQL_MCP_EXTENDED           Enable extended tools (set to 1)
+        MYSQL_MCP_TOOLS              Per-tool or per-group switches, e.g. explain_query=1,list_variables=0
+        MYSQL_MCP_JSON_LOGS          Enable JSON structured logging (set to 1)
+        MYSQL_MCP_LOG_LEVEL          Log level: debug, info (default), warn or error
+        MYSQL_MCP_LOG_COMPONENTS     Per-component levels, e.g. validator=debug,http=warn
+        MYSQL_MCP_LOG_REDACT_SQL     Replace literals in logged and audited SQL with ? (set to 1)
+        MYSQL_MCP_TOKEN_TRACKING     Enable token usage estimation (set to 1)
+        MYSQL_MCP_TOKEN_MODEL        Tokenizer encoding to use (default: cl100k_base)
+        MYSQL_MCP_TOKEN_CARD         Live token UI at /status: on by default in HTTP mode; set to 0 to disable
+        MYSQL_MCP_AUDIT_LOG          Path to audit log file
+        MYSQL_MCP_AUDIT_FORMAT       Audit log format: json (default), cef or leef
+        MYSQL_MCP_SCHEMA_WATCH       Comma-separated databases watched for table and column changes (schema_changes)
+        MYSQL_MCP_SCHEMA_WATCH_SECONDS  Seconds between schema snapshots (default: 60)
+        MYSQL_MCP_WEBHOOK_URL        POST query_blocked, query_slow, connection_unhealthy and schema_changed events here
+        MYSQL_MCP_WEBHOOK_SECRET     HMAC-SHA256 key for the X-MCP-Signature header
+        MYSQL_MCP_WEBHOOK_EVENTS     Comma-separated events to send (default: all)
+        MYSQL_MCP_WEBHOOK_SLOW_QUERY_SECONDS  Send query_slow for query tool calls slower than this (default: off)
+        MYSQL_MCP_QUOTA_QUERIES_PER_HOUR  Tool calls reaching MySQL per hour for each API key or client (default: unlimited)
+        MYSQL_MCP_QUOTA_ROWS_PER_DAY Result rows per UTC day for each API key or client (default: unlimited)
+        MYSQL_MCP_QUOTA_FILE         JSON file keeping quota counters across restarts (default: memory only)
+        MYSQL_MCP_LOCALE             Language of validation and access errors: en (default), de, ja or th
+        MYSQL_MCP_ALLOWED_DATABASES Comma-separated schema allowlist (optional)
+        MYSQL_MCP_VIRTUAL_CONNECTION Lock the server to a virtual_connections entry (tenant scope)
+        MYSQL_MCP_STRICT_READ_ONLY   Set 1 for transaction_read_only=ON on connections
+        MYSQL_MCP_PROCESS_ADMIN      Set 1 for process_list / kill_query tools (extended)
+        MYSQL_MCP_READ_AUDIT_TOOL    Set 1 for read_audit_log when audit path set
+        MYSQL_MCP_SLOW_QUERY_TOOL    Set 1 for slow_query_log tool (extended)
+        MYSQL_MCP_SESSIONS_TOOL      Set 1 for read-only list_sessions tool (extended)
+        MYSQL_MCP_METRICS_SAMPLE_SECONDS  Background status sampling interval for metrics_history (default: off)
+        MYSQL_MCP_METRICS_HISTORY_SIZE    Samples kept in memory by the sampler (default: 720)
+        MYSQL_MCP_VECTOR             Enable vector tools for MySQL 9.0+ (set to 1)
+        MYSQL_MCP_VECTOR_WRITE       Set 1 for vector_insert / vector_delete (needs MYSQL_MCP_VECTOR)
+        MYSQL_MCP_HTTP               Enable REST API mode (set to 1)
+        MYSQL_MCP_METRICS_HTTP       With stdio MCP only: serve /status, /api/metrics/tokens, /api/stats and /metrics on MYSQL_HTTP_PORT (set to 1); not used when MYSQL_MCP_HTTP=1
+        MYSQL_HTTP_PORT              HTTP port for REST API or metrics sidecar (default: 9306)
+        MYSQL_HTTP_BOOKMARKS_FILE    JSON file for query bookmarks shared over /api/bookmarks (default: off)
+        MYSQL_HTTP_JOB_TIMEOUT_SECONDS    Run time limit of async query jobs (/api/jobs) (default: 1800)
+        MYSQL_HTTP_JOB_RETENTION_SECONDS  How long finished job results are kept (default: 3600)
+        MYSQL_HTTP_JOB_STORE_BYTES   Result bytes kept across all jobs (default: 67108864)
+        MYSQL_HTTP_RATE_LIMIT        Enable rate limiting for HTTP mode (set to 1)
+        MYSQL_HTTP_RATE_LIMIT_RPS    Rate limit: requests per second (default: 100)
+        MYSQL_HTTP_RATE_LIMIT_BURST  Rate limit: burst size (default: 200)
+        MYSQL_POOL_SIZE              Connection pool size / max open connections (default: 10); alias for MYSQL_MAX_OPEN_CONNS
+        MYSQL_MAX_OPEN_CONNS         Max open database connections (default: 10); overrides MYSQL_POOL_SIZE
+        MYSQL_MAX_IDLE_CONNS         Max idle database connections (default: 5)
+        MYSQL_CONN_MAX_LIFETIME_MINUTES  Connection max lifetime in minutes (default: 30)
+
+MULTI-DSN CONFIGURATION:
+    Configure multiple MySQL connections using numbered environment variables:
+
+        MYSQL_DSN_1                  Additional connection DSN
+        MYSQL_DSN_1_NAME             Connection name (default: connection_1)
+        MYSQL_DSN_1_DESC             Connection description
+
+    Or use JSON configuration:
+
+        MYSQL_CONNECTIONS='[
+          {"name": "production", "dsn": "user:pass@tcp(prod:3306)/db", "description": "Production"},
+          {"name": "staging", "dsn": "user:pass@tcp(staging:3306)/db", "description": "Staging"}
+        ]'
+
+EXAMPLES:
+    # Basic usage with single connection
+    export MYSQL_DSN="root:password@tcp(127.0.0.1:3306)/mysql?parseTime=true"
+    mysql-mcp-server
+
+    # Try the tools without a MySQL server (built-in sample schema)
+    MYSQL_MCP_DEMO=1 mysql-mcp-server
+
+    # With config file
+    mysql-mcp-server --config /path/to/config.yaml
+
+    # Validate a config file
+    mysql-mcp-server --validate-config /path/to/config.yaml
+
+    # Print current configuration
+    mysql-mcp-server --print-config
+
+    # With extended tools enabled
+    export MYSQL_DSN="user:pass@tcp(localhost:3306)/mydb"
+    export MYSQL_MCP_EXTENDED=1
+    mysql-mcp-server
+
+    # HTTP REST API mode
+    export MYSQL_DSN="user:pass@tcp(localhost:3306)/mydb"
+    export MYSQL_MCP_HTTP=1
+    export MYSQL_HTTP_PORT=9306
+    mysql-mcp-server
+
+    # Claude Desktop: stdio MCP + token dashboard on http://127.0.0.1:9306/status (same process)
+    export MYSQL_DSN="user:pass@tcp(127.0.0.1:3306)/db?parseTime=true"
+    export MYSQL_MCP_TOKEN_TRACKING=1
+    export MYSQL_MCP_METRICS_HTTP=1
+    export MYSQL_HTTP_PORT=9306
+    mysql-mcp-server
+
+    # Silent mode (production; only errors to stderr)
+    mysql-mcp-server --silent --config /etc/mysql-mcp-server/config.yaml
+
+    # Run HTTP server as daemon (Unix)
+    MYSQL_MCP_HTTP=1 mysql-mcp-server --daemon --config /path/to/config.yaml
+
+FEATURES:
+    - Fully read-only (blocks all non-SELECT/SHOW/DESCRIBE/EXPLAIN)
+    - Multi-DSN support (connect to multiple MySQL instances)
+    - Vector search (MySQL 9.0+)
+    - Query timeouts and row limits
+    - Structured logging and audit logs
+    - REST API mode for HTTP clients
+
+MCP TOOLS:
+    Core: list_databases, list_tables, describe_table, run_query, ping, server_info
+    Connections: list_connections, use_connection, pool_stats, usage_stats
+    Extended: list_indexes, show_create_table, explain_query, list_views, etc.
+    Vector: vector_search, vector_info (MySQL 9.0+); vector_insert, vector_delete with MYSQL_MCP_VECTOR_WRITE=1
+
+SECURITY:
+    - SQL validation blocks dangerous operations
+    - Read-only enforcement
+    - Multi-statement prevention
+    - Recommended: Use a read-only MySQL user
+
+    CREATE USER 'mcp'@'localhost' IDENTIFIED BY 'strongpass';
+    GRANT SELECT ON *.* TO 'mcp'@'localhost';
+
+DOCUMENTATION:
+    Full documentation: https://github.com/askdba/mysql-mcp-server
+
+`)
+}
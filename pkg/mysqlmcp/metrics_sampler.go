@@ -0,0 +1,119 @@
+// pkg/mysqlmcp/metrics_sampler.go
+package mysqlmcp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sampledCounters are cumulative status counters; metrics_history reports deltas and rates.
+var sampledCounters = []string{
+	"Questions", "Com_select", "Slow_queries",
+	"Bytes_sent", "Bytes_received",
+	"Innodb_buffer_pool_read_requests", "Innodb_buffer_pool_reads",
+	"Created_tmp_disk_tables",
+}
+
+// sampledGauges are point-in-time values; metrics_history reports min/max/avg.
+var sampledGauges = []string{
+	"Threads_connected", "Threads_running",
+	"Innodb_buffer_pool_pages_data", "Innodb_buffer_pool_pages_free", "Innodb_buffer_pool_pages_dirty",
+}
+
+// MetricsSample is one snapshot of status values taken by the background sampler.
+type MetricsSample struct {
+	Time       time.Time
+	Connection string
+	Values     map[string]int64 // lowercased status name -> value
+}
+
+// MetricsSampler periodically records status counters into a fixed-size ring.
+type MetricsSampler struct {
+	mu       sync.Mutex
+	interval time.Duration
+	samples  []MetricsSample
+	next     int
+	full     bool
+}
+
+// globalMetricsSampler is nil unless MYSQL_MCP_METRICS_SAMPLE_SECONDS is set.
+var globalMetricsSampler *MetricsSampler
+
+func newMetricsSampler(interval time.Duration, size int) *MetricsSampler {
+	if size <= 0 {
+		size = 1
+	}
+	return &MetricsSampler{
+		interval: interval,
+		samples:  make([]MetricsSample, size),
+	}
+}
+
+// Add appends a sample, overwriting the oldest one when the ring is full.
+func (s *MetricsSampler) Add(sample MetricsSample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % len(s.samples)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Since returns samples for connection taken at or after t, oldest first.
+func (s *MetricsSampler) Since(t time.Time, connection string) []MetricsSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.next
+	start := 0
+	if s.full {
+		n = len(s.samples)
+		start = s.next
+	}
+	out := make([]MetricsSample, 0, n)
+	for i := 0; i < n; i++ {
+		sample := s.samples[(start+i)%len(s.samples)]
+		if sample.Time.Before(t) || sample.Connection != connection {
+			continue
+		}
+		out = append(out, sample)
+	}
+	return out
+}
+
+// sampleOnce records the current status values of the active connection.
+func (s *MetricsSampler) sampleOnce(ctx context.Context) error {
+	db, name := connManager.GetActive()
+	if db == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	names := append(append([]string{}, sampledCounters...), sampledGauges...)
+	values, err := fetchGlobalStatus(ctx, db, names)
+	if err != nil {
+		return err
+	}
+	s.Add(MetricsSample{Time: time.Now(), Connection: name, Values: values})
+	return nil
+}
+
+// Run samples until ctx is cancelled. Failures are logged and sampling continues.
+func (s *MetricsSampler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sampleOnce(ctx); err != nil && ctx.Err() == nil {
+			serverLog.Warn("metrics sample failed", map[string]interface{}{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// pkg/mysqlmcp/metrics_sampler_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMetricsSamplerRingOverwritesOldest(t *testing.T) {
+	s := newMetricsSampler(time.Second, 3)
+	base := time.Now().Add(-time.Minute)
+	for i := 0; i < 5; i++ {
+		s.Add(MetricsSample{Time: base.Add(time.Duration(i) * time.Second), Connection: "mock", Values: map[string]int64{"questions": int64(i)}})
+	}
+
+	got := s.Since(time.Time{}, "mock")
+	if len(got) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(got))
+	}
+	for i, want := range []int64{2, 3, 4} {
+		if got[i].Values["questions"] != want {
+			t.Errorf("sample %d: expected questions=%d, got %d", i, want, got[i].Values["questions"])
+		}
+	}
+	if other := s.Since(time.Time{}, "other"); len(other) != 0 {
+		t.Errorf("expected no samples for other connection, got %d", len(other))
+	}
+	if recent := s.Since(base.Add(4*time.Second), "mock"); len(recent) != 1 {
+		t.Errorf("expected 1 sample in window, got %d", len(recent))
+	}
+}
+
+func TestMetricsSamplerSampleOnce(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM performance_schema.global_status").
+		WillReturnRows(sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).
+			AddRow("Questions", "100").
+			AddRow("Threads_running", "3"))
+
+	s := newMetricsSampler(time.Second, 10)
+	if err := s.sampleOnce(context.Background()); err != nil {
+		t.Fatalf("sampleOnce failed: %v", err)
+	}
+	got := s.Since(time.Time{}, "mock")
+	if len(got) != 1 || got[0].Values["questions"] != 100 || got[0].Values["threads_running"] != 3 {
+		t.Fatalf("unexpected samples: %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolMetricsHistoryDisabled(t *testing.T) {
+	old := globalMetricsSampler
+	globalMetricsSampler = nil
+	defer func() { globalMetricsSampler = old }()
+
+	_, _, err := toolMetricsHistory(context.Background(), &mcp.CallToolRequest{}, MetricsHistoryInput{})
+	if err == nil {
+		t.Fatal("expected error when sampler is disabled")
+	}
+}
+
+func TestToolMetricsHistoryDeltas(t *testing.T) {
+	_, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	old := globalMetricsSampler
+	globalMetricsSampler = newMetricsSampler(10*time.Second, 10)
+	defer func() { globalMetricsSampler = old }()
+
+	now := time.Now()
+	for i, v := range []struct{ questions, threads int64 }{{1000, 2}, {1500, 6}, {200, 4}} {
+		globalMetricsSampler.Add(MetricsSample{
+			Time:       now.Add(time.Duration(i-2) * 10 * time.Second),
+			Connection: "mock",
+			Values:     map[string]int64{"questions": v.questions, "threads_running": v.threads},
+		})
+	}
+
+	_, out, err := toolMetricsHistory(context.Background(), &mcp.CallToolRequest{}, MetricsHistoryInput{IncludeSamples: true})
+	if err != nil {
+		t.Fatalf("toolMetricsHistory failed: %v", err)
+	}
+	if out.SampleCount != 3 || len(out.Samples) != 3 {
+		t.Fatalf("expected 3 samples, got count=%d samples=%d", out.SampleCount, len(out.Samples))
+	}
+	var questions, threads MetricSeriesSummary
+	for _, m := range out.Metrics {
+		switch m.Name {
+		case "Questions":
+			questions = m
+		case "Threads_running":
+			threads = m
+		}
+	}
+	// 1000 -> 1500 (+500), then restart to 200 (+200).
+	if questions.Delta != 700 || !questions.Reset {
+		t.Errorf("unexpected Questions summary: %+v", questions)
+	}
+	if questions.RatePerSec != 35 {
+		t.Errorf("expected 35 qps, got %v", questions.RatePerSec)
+	}
+	if threads.Min != 2 || threads.Max != 6 || threads.Avg != 4 {
+		t.Errorf("unexpected Threads_running summary: %+v", threads)
+	}
+}
@@ -0,0 +1,323 @@
+// pkg/mysqlmcp/object_storage.go
+package mysqlmcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// Uploads to the object storage targets of job exports (export_targets),
+// signed with the providers' REST schemes so no cloud SDK is needed: AWS
+// Signature Version 4 for s3 and for the S3-compatible XML API of gcs, Shared
+// Key for azure. Downloads go through signed URLs (presigned GET, service
+// SAS), so callers need no credentials of their own. An object is written
+// with a single PUT, which both S3 and Azure Blob accept up to 5 GB.
+
+// objectStore is one export target.
+type objectStore interface {
+	// put uploads size bytes of body as the object key.
+	put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// signedURL returns a URL that downloads key until expiry has passed.
+	signedURL(key string, expiry time.Duration) (string, error)
+}
+
+// azureVersion is the Blob service version requests and SAS tokens use.
+const azureVersion = "2020-12-06"
+
+// objectStoreClient has no timeout: uploads run under the job timeout.
+var objectStoreClient = &http.Client{}
+
+// newObjectStore returns the store of t. Credentials missing from the
+// config file are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN (s3) or AZURE_STORAGE_KEY (azure).
+func newObjectStore(t config.ExportTarget) (objectStore, error) {
+	switch t.Type {
+	case config.ExportTargetS3, config.ExportTargetGCS:
+		s := &s3Store{target: t, keyID: t.AccessKeyID, secret: t.SecretAccessKey, region: t.Region, now: time.Now}
+		if t.Type == config.ExportTargetS3 {
+			if s.keyID == "" {
+				s.keyID, s.secret, s.token = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+			}
+			if s.region == "" {
+				s.region = os.Getenv("AWS_REGION")
+			}
+			if s.region == "" {
+				s.region = "us-east-1"
+			}
+		} else {
+			s.region = "auto"
+		}
+		if s.keyID == "" || s.secret == "" {
+			return nil, fmt.Errorf("export target '%s' has no access key", t.Name)
+		}
+		return s, nil
+	case config.ExportTargetAzure:
+		key := t.AccountKey
+		if key == "" {
+			key = os.Getenv("AZURE_STORAGE_KEY")
+		}
+		if key == "" {
+			return nil, fmt.Errorf("export target '%s' has no account key", t.Name)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("export target '%s' account key is not base64: %w", t.Name, err)
+		}
+		return &azureStore{target: t, key: decoded, now: time.Now}, nil
+	}
+	return nil, fmt.Errorf("export target '%s' has unknown type '%s'", t.Name, t.Type)
+}
+
+// s3Store signs requests to S3, S3-compatible stores and GCS.
+type s3Store struct {
+	target config.ExportTarget
+	keyID  string
+	secret string
+	token  string // session token of temporary credentials
+	region string
+	now    func() time.Time
+}
+
+// objectURL returns the URL of key: virtual-hosted on AWS, path-style on
+// GCS and custom endpoints.
+func (s *s3Store) objectURL(key string) (*url.URL, error) {
+	var base, path string
+	switch {
+	case s.target.Endpoint != "":
+		base, path = s.target.Endpoint, "/"+s.target.Bucket+"/"+key
+	case s.target.Type == config.ExportTargetGCS:
+		base, path = "https://storage.googleapis.com", "/"+s.target.Bucket+"/"+key
+	case s.region == "us-east-1":
+		base, path = "https://"+s.target.Bucket+".s3.amazonaws.com", "/"+key
+	default:
+		base, path = "https://"+s.target.Bucket+".s3."+s.region+".amazonaws.com", "/"+key
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	u.Path += path
+	u.RawPath = sigV4Escape(u.Path, true)
+	return u, nil
+}
+
+func (s *s3Store) put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	amzDate, scope := s.scope()
+	headers := map[string]string{
+		"host":                 u.Host,
+		"x-amz-content-sha256": "UNSIGNED-PAYLOAD",
+		"x-amz-date":           amzDate,
+	}
+	if s.token != "" {
+		headers["x-amz-security-token"] = s.token
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+		if name != "host" {
+			req.Header.Set(name, headers[name])
+		}
+	}
+	signed := strings.Join(names, ";")
+	creq := strings.Join([]string{http.MethodPut, u.EscapedPath(), "", canonical.String(), signed, "UNSIGNED-PAYLOAD"}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.keyID, scope, signed, s.signature(amzDate, scope, creq)))
+	return doUpload(req, s.target.Name)
+}
+
+func (s *s3Store) signedURL(key string, expiry time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	amzDate, scope := s.scope()
+	params := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    s.keyID + "/" + scope,
+		"X-Amz-Date":          amzDate,
+		"X-Amz-Expires":       strconv.Itoa(int(expiry.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if s.token != "" {
+		params["X-Amz-Security-Token"] = s.token
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = sigV4Escape(name, false) + "=" + sigV4Escape(params[name], false)
+	}
+	query := strings.Join(pairs, "&")
+	creq := strings.Join([]string{http.MethodGet, u.EscapedPath(), query, "host:" + u.Host + "\n", "host", "UNSIGNED-PAYLOAD"}, "\n")
+	u.RawQuery = query + "&X-Amz-Signature=" + s.signature(amzDate, scope, creq)
+	return u.String(), nil
+}
+
+// scope returns the request time and credential scope of a signature made now.
+func (s *s3Store) scope() (amzDate, scope string) {
+	amzDate = s.now().UTC().Format("20060102T150405Z")
+	return amzDate, amzDate[:8] + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature signs the canonical request creq.
+func (s *s3Store) signature(amzDate, scope, creq string) string {
+	hash := sha256.Sum256([]byte(creq))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	key := []byte("AWS4" + s.secret)
+	for _, part := range []string{amzDate[:8], s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// sigV4Escape percent-encodes every byte of v but the unreserved characters
+// and, with keepSlash, "/".
+func sigV4Escape(v string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// azureStore signs requests to Azure Blob Storage.
+type azureStore struct {
+	target config.ExportTarget
+	key    []byte
+	now    func() time.Time
+}
+
+// objectURL returns the URL of the blob key in the target's container.
+func (s *azureStore) objectURL(key string) (*url.URL, error) {
+	base := s.target.Endpoint
+	if base == "" {
+		base = "https://" + s.target.Account + ".blob.core.windows.net"
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	u.Path += "/" + s.target.Bucket + "/" + key
+	u.RawPath = sigV4Escape(u.Path, true)
+	return u, nil
+}
+
+func (s *azureStore) put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	date := s.now().UTC().Format(http.TimeFormat)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-date", date)
+	req.Header.Set("x-ms-version", azureVersion)
+
+	length := ""
+	if size > 0 {
+		length = strconv.FormatInt(size, 10)
+	}
+	// VERB, Content-Encoding, Content-Language, Content-Length, Content-MD5,
+	// Content-Type, Date, If-Modified-Since, If-Match, If-None-Match,
+	// If-Unmodified-Since, Range, then the x-ms- headers and the resource.
+	stringToSign := strings.Join([]string{http.MethodPut, "", "", length, "", contentType, "", "", "", "", "", ""}, "\n") + "\n" +
+		"x-ms-blob-type:BlockBlob\nx-ms-date:" + date + "\nx-ms-version:" + azureVersion + "\n" +
+		"/" + s.target.Account + u.EscapedPath()
+	req.Header.Set("Authorization", "SharedKey "+s.target.Account+":"+base64.StdEncoding.EncodeToString(hmacSHA256(s.key, stringToSign)))
+	return doUpload(req, s.target.Name)
+}
+
+// signedURL returns the blob URL with a read-only service SAS.
+func (s *azureStore) signedURL(key string, expiry time.Duration) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	expires := s.now().Add(expiry).UTC().Format("2006-01-02T15:04:05Z")
+	protocol := "https"
+	if u.Scheme == "http" {
+		protocol = "https,http"
+	}
+	resource := "/blob/" + s.target.Account + "/" + s.target.Bucket + "/" + key
+	// sp, st, se, resource, si, sip, spr, sv, sr, snapshot time, ses, rscc,
+	// rscd, rsce, rscl, rsct.
+	stringToSign := strings.Join([]string{"r", "", expires, resource, "", "", protocol, azureVersion, "b", "", "", "", "", "", "", ""}, "\n")
+	q := url.Values{
+		"sp":  {"r"},
+		"se":  {expires},
+		"spr": {protocol},
+		"sv":  {azureVersion},
+		"sr":  {"b"},
+		"sig": {base64.StdEncoding.EncodeToString(hmacSHA256(s.key, stringToSign))},
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// doUpload sends req and turns a non-2xx answer into an error.
+func doUpload(req *http.Request, target string) error {
+	if req.ContentLength == 0 {
+		req.Body = http.NoBody
+	}
+	resp, err := objectStoreClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload to export target '%s' failed: %w", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload to export target '%s' failed: %s: %s", target, resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
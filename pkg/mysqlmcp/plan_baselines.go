@@ -0,0 +1,353 @@
+// pkg/mysqlmcp/plan_baselines.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// check_saved_queries turns the saved query library into a plan regression
+// check: it EXPLAINs each saved query with sample parameter values and
+// compares every table's access type and index with the plan recorded the
+// first time the query was checked. Baselines are kept per connection,
+// database and query, in memory or in MYSQL_MCP_PLAN_BASELINE_FILE so they
+// survive restarts. A query whose SQL changed gets a new baseline instead of
+// a comparison.
+
+const (
+	planStatusBaseline  = "baseline_recorded"
+	planStatusUnchanged = "unchanged"
+	planStatusChanged   = "changed"
+	planStatusRegressed = "regressed"
+	planStatusError     = "error"
+)
+
+// accessRank orders EXPLAIN access types from best to worst, following the
+// join types section of the MySQL EXPLAIN documentation.
+var accessRank = map[string]int{
+	"system":          0,
+	"const":           1,
+	"eq_ref":          2,
+	"ref":             3,
+	"fulltext":        4,
+	"ref_or_null":     5,
+	"index_merge":     6,
+	"unique_subquery": 7,
+	"index_subquery":  8,
+	"range":           9,
+	"index":           10,
+	"ALL":             11,
+}
+
+// planBaseline is the recorded plan of one saved query.
+type planBaseline struct {
+	Connection string     `json:"connection"`
+	Database   string     `json:"database,omitempty"`
+	Query      string     `json:"query"`
+	Digest     string     `json:"digest"` // util.QueryDigest of the saved query's SQL
+	Steps      []PlanStep `json:"steps"`
+	RecordedAt time.Time  `json:"recorded_at"`
+}
+
+func (b planBaseline) key() string {
+	return b.Connection + "\x00" + strings.ToLower(b.Database) + "\x00" + b.Query
+}
+
+// planBaselineFile is the layout of the baselines file.
+type planBaselineFile struct {
+	Baselines []planBaseline `json:"baselines"`
+}
+
+// planBaselineStore holds baselines, mirrored to path when it is set.
+type planBaselineStore struct {
+	mu        sync.Mutex
+	path      string
+	baselines map[string]planBaseline
+}
+
+// planBaselines keeps baselines in memory until main opens the configured file.
+var planBaselines = &planBaselineStore{baselines: make(map[string]planBaseline)}
+
+// openPlanBaselineStore reads path, which may not exist yet.
+func openPlanBaselineStore(path string) (*planBaselineStore, error) {
+	s := &planBaselineStore{path: path, baselines: make(map[string]planBaseline)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan baseline file: %w", err)
+	}
+	var f planBaselineFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid plan baseline file %s: %w", path, err)
+	}
+	for _, b := range f.Baselines {
+		s.baselines[b.key()] = b
+	}
+	return s, nil
+}
+
+func (s *planBaselineStore) get(connection, database, query string) (planBaseline, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.baselines[planBaseline{Connection: connection, Database: database, Query: query}.key()]
+	return b, ok
+}
+
+// record stores bs and writes the file once; on a write error the previous
+// baselines are kept.
+func (s *planBaselineStore) record(bs []planBaseline) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous := make(map[string]planBaseline, len(s.baselines))
+	for k, b := range s.baselines {
+		previous[k] = b
+	}
+	for _, b := range bs {
+		s.baselines[b.key()] = b
+	}
+	if s.path == "" {
+		return nil
+	}
+	f := planBaselineFile{Baselines: make([]planBaseline, 0, len(s.baselines))}
+	for _, b := range s.baselines {
+		f.Baselines = append(f.Baselines, b)
+	}
+	sort.Slice(f.Baselines, func(i, j int) bool { return f.Baselines[i].key() < f.Baselines[j].key() })
+	if err := writeJSONFile(s.path, f); err != nil {
+		s.baselines = previous
+		return fmt.Errorf("failed to write plan baseline file: %w", err)
+	}
+	return nil
+}
+
+func (s *planBaselineStore) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.baselines)
+}
+
+// planSteps reduces a traditional EXPLAIN plan to what the check compares.
+// Rows without an access type (no table used, impossible WHERE) are skipped.
+func planSteps(plan []map[string]interface{}) []PlanStep {
+	steps := []PlanStep{}
+	for _, row := range plan {
+		if row["type"] == nil {
+			continue
+		}
+		step := PlanStep{
+			Table:  fmt.Sprintf("%v", row["table"]),
+			Access: fmt.Sprintf("%v", row["type"]),
+			Rows:   int64(numericValue(row["rows"])),
+		}
+		if row["key"] != nil {
+			step.Key = fmt.Sprintf("%v", row["key"])
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// comparePlans lists how after differs from before, matching steps by table;
+// a table that appears twice is matched by occurrence.
+func comparePlans(before, after []PlanStep) []PlanChange {
+	keyed := func(steps []PlanStep) ([]string, map[string]PlanStep) {
+		seen := make(map[string]int, len(steps))
+		keys := make([]string, 0, len(steps))
+		byKey := make(map[string]PlanStep, len(steps))
+		for _, s := range steps {
+			seen[s.Table]++
+			k := s.Table
+			if n := seen[s.Table]; n > 1 {
+				k = fmt.Sprintf("%s#%d", s.Table, n)
+			}
+			keys = append(keys, k)
+			byKey[k] = s
+		}
+		return keys, byKey
+	}
+	_, old := keyed(before)
+	keys, cur := keyed(after)
+
+	var changes []PlanChange
+	for _, k := range keys {
+		now := cur[k]
+		prev, ok := old[k]
+		if !ok {
+			if now.Access == "ALL" {
+				changes = append(changes, PlanChange{Table: now.Table, Kind: "new_full_scan", After: now.Access})
+			}
+			continue
+		}
+		prevRank, prevKnown := accessRank[prev.Access]
+		nowRank, nowKnown := accessRank[now.Access]
+		switch {
+		case now.Access == "ALL" && prev.Access != "ALL":
+			changes = append(changes, PlanChange{Table: now.Table, Kind: "new_full_scan", Before: prev.Access, After: now.Access})
+		case prevKnown && nowKnown && nowRank > prevRank:
+			changes = append(changes, PlanChange{Table: now.Table, Kind: "access_regression", Before: prev.Access, After: now.Access})
+		case prevKnown && nowKnown && nowRank < prevRank:
+			changes = append(changes, PlanChange{Table: now.Table, Kind: "access_improvement", Before: prev.Access, After: now.Access})
+		case now.Access == prev.Access && !strings.EqualFold(now.Key, prev.Key):
+			changes = append(changes, PlanChange{Table: now.Table, Kind: "index_changed", Before: prev.Key, After: now.Key})
+		}
+	}
+	return changes
+}
+
+// planStatus classifies the changes of one query.
+func planStatus(changes []PlanChange) string {
+	if len(changes) == 0 {
+		return planStatusUnchanged
+	}
+	for _, c := range changes {
+		if c.Kind == "new_full_scan" || c.Kind == "access_regression" {
+			return planStatusRegressed
+		}
+	}
+	return planStatusChanged
+}
+
+// planSampleValues fills required parameters without a default with a value
+// of their type, so EXPLAIN sees the shape of a real call. Optional ones stay
+// NULL, as in a call that omits them.
+func planSampleValues(q *savedQuery) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, p := range q.Params {
+		if !p.Required || p.Default != nil {
+			continue
+		}
+		switch p.Type {
+		case "int", "number":
+			values[p.Name] = 1
+		case "bool":
+			values[p.Name] = true
+		case "date":
+			values[p.Name] = time.Now().Format("2006-01-02")
+		default:
+			values[p.Name] = "x"
+		}
+	}
+	return values
+}
+
+// explainSavedQuery returns the plan of q run in database, with the checks
+// run_saved_query applies.
+func explainSavedQuery(ctx context.Context, q *savedQuery, database string) ([]PlanStep, error) {
+	if accessControlEnabled() && database == "" {
+		return nil, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+	}
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, err
+		}
+	}
+	if err := requireReferencedSchemasInQuery(q.boundSQL); err != nil {
+		return nil, err
+	}
+	args, err := q.bindArgs(planSampleValues(q))
+	if err != nil {
+		return nil, err
+	}
+	sqlText, err := applyRowPolicies(q.boundSQL, database)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	plan, err := runExplain(ctx, database, sqlText, args...)
+	if err != nil {
+		return nil, err
+	}
+	return planSteps(plan), nil
+}
+
+func toolCheckSavedQueries(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input CheckSavedQueriesInput,
+) (*mcp.CallToolResult, CheckSavedQueriesOutput, error) {
+	queries := savedQueries.list()
+	if len(input.Names) > 0 {
+		queries = queries[:0:0]
+		for _, name := range input.Names {
+			q, ok := savedQueries.get(strings.TrimSpace(name))
+			if !ok {
+				return nil, CheckSavedQueriesOutput{}, fmt.Errorf("saved query not found: %s", name)
+			}
+			queries = append(queries, q)
+		}
+		sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	}
+
+	out := CheckSavedQueriesOutput{Queries: []SavedQueryPlanCheck{}, Persisted: planBaselines.path != ""}
+	if connManager != nil {
+		_, out.Connection = connManager.GetActive()
+	}
+	now := time.Now().UTC()
+	var record []planBaseline
+	for _, q := range queries {
+		// Hidden from list_saved_queries, so not checked either.
+		if q.Database != "" && accessControlEnabled() && !databaseAllowed(q.Database) {
+			continue
+		}
+		database := q.Database
+		if database == "" {
+			database = strings.TrimSpace(input.Database)
+		}
+		check := SavedQueryPlanCheck{Name: q.Name, Database: database}
+		steps, err := explainSavedQuery(ctx, q, database)
+		if err != nil {
+			check.Status, check.Error = planStatusError, err.Error()
+			out.Queries = append(out.Queries, check)
+			continue
+		}
+		out.Checked++
+		check.Plan = steps
+
+		current := planBaseline{
+			Connection: out.Connection,
+			Database:   database,
+			Query:      q.Name,
+			Digest:     util.QueryDigest(q.boundSQL),
+			Steps:      steps,
+			RecordedAt: now,
+		}
+		base, ok := planBaselines.get(out.Connection, database, q.Name)
+		if !ok || base.Digest != current.Digest {
+			check.Status = planStatusBaseline
+			record = append(record, current)
+			out.Queries = append(out.Queries, check)
+			continue
+		}
+		check.BaselineAt = base.RecordedAt.Format(time.RFC3339)
+		check.Changes = comparePlans(base.Steps, steps)
+		check.Status = planStatus(check.Changes)
+		if check.Status == planStatusRegressed {
+			out.Regressed++
+		}
+		if input.UpdateBaseline {
+			record = append(record, current)
+		}
+		out.Queries = append(out.Queries, check)
+	}
+
+	if len(record) > 0 {
+		if err := planBaselines.record(record); err != nil {
+			return nil, CheckSavedQueriesOutput{}, err
+		}
+	}
+	return nil, out, nil
+}
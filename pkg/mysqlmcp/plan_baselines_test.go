@@ -0,0 +1,106 @@
+// pkg/mysqlmcp/plan_baselines_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestComparePlans(t *testing.T) {
+	before := []PlanStep{
+		{Table: "o", Access: "ref", Key: "ix_customer"},
+		{Table: "c", Access: "eq_ref", Key: "PRIMARY"},
+		{Table: "i", Access: "range", Key: "ix_created"},
+		{Table: "p", Access: "ref", Key: "ix_a"},
+	}
+	after := []PlanStep{
+		{Table: "o", Access: "ALL"},
+		{Table: "c", Access: "ref", Key: "ix_email"},
+		{Table: "i", Access: "const", Key: "PRIMARY"},
+		{Table: "p", Access: "ref", Key: "ix_b"},
+		{Table: "r", Access: "ALL"},
+	}
+	got := comparePlans(before, after)
+	want := []PlanChange{
+		{Table: "o", Kind: "new_full_scan", Before: "ref", After: "ALL"},
+		{Table: "c", Kind: "access_regression", Before: "eq_ref", After: "ref"},
+		{Table: "i", Kind: "access_improvement", Before: "range", After: "const"},
+		{Table: "p", Kind: "index_changed", Before: "ix_a", After: "ix_b"},
+		{Table: "r", Kind: "new_full_scan", After: "ALL"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("comparePlans = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if planStatus(got) != planStatusRegressed || planStatus(got[2:4]) != planStatusChanged || planStatus(nil) != planStatusUnchanged {
+		t.Error("unexpected planStatus")
+	}
+}
+
+func TestToolCheckSavedQueries(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	defer withSavedQueries(t, config.SavedQuery{
+		Name:     "orders_by_customer",
+		SQL:      "SELECT id FROM orders WHERE customer_id = :customer_id",
+		Database: "shop",
+		Params:   []config.SavedQueryParam{{Name: "customer_id", Type: "int", Required: true}},
+	})()
+	oldStore := planBaselines
+	defer func() { planBaselines = oldStore }()
+	path := filepath.Join(t.TempDir(), "plans.json")
+	store, err := openPlanBaselineStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	planBaselines = store
+
+	expectPlan := func(access string, key interface{}) {
+		mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`EXPLAIN SELECT id FROM orders WHERE customer_id = \?`).
+			WithArgs(int64(1)).
+			WillReturnRows(sqlmock.NewRows(explainColumns()).
+				AddRow(1, "SIMPLE", "orders", nil, access, "ix_customer", key, nil, nil, 12, 100.0, ""))
+		expectRestoreDatabase(mock)
+	}
+	ctx := context.Background()
+
+	expectPlan("ref", "ix_customer")
+	_, out, err := toolCheckSavedQueries(ctx, &mcp.CallToolRequest{}, CheckSavedQueriesInput{})
+	if err != nil {
+		t.Fatalf("first check failed: %v", err)
+	}
+	if out.Checked != 1 || !out.Persisted || out.Queries[0].Status != planStatusBaseline {
+		t.Fatalf("unexpected first check: %+v", out)
+	}
+
+	// The baseline survives a restart.
+	if planBaselines, err = openPlanBaselineStore(path); err != nil || planBaselines.count() != 1 {
+		t.Fatalf("reopened store: %v", err)
+	}
+	expectPlan("ALL", nil)
+	_, out, err = toolCheckSavedQueries(ctx, &mcp.CallToolRequest{}, CheckSavedQueriesInput{Names: []string{"orders_by_customer"}})
+	if err != nil {
+		t.Fatalf("second check failed: %v", err)
+	}
+	q := out.Queries[0]
+	if out.Regressed != 1 || q.Status != planStatusRegressed || len(q.Changes) != 1 || q.Changes[0].Kind != "new_full_scan" || q.BaselineAt == "" {
+		t.Errorf("unexpected second check: %+v", out)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+
+	if _, _, err := toolCheckSavedQueries(ctx, &mcp.CallToolRequest{}, CheckSavedQueriesInput{Names: []string{"missing"}}); err == nil {
+		t.Error("expected an error for an unknown saved query")
+	}
+}
@@ -0,0 +1,215 @@
+// pkg/mysqlmcp/pool_maintenance.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/go-sql-driver/mysql"
+)
+
+// connectionProgramName is sent as the program_name connection attribute on
+// every session the server opens, so the idle session reaper can tell its
+// own sessions from those of other clients using the same account.
+const connectionProgramName = "mysql-mcp-server"
+
+// applyProgramNameDSN adds program_name=mysql-mcp-server to the DSN's
+// connection attributes unless the DSN already sets a program_name.
+func applyProgramNameDSN(dsn string) (string, error) {
+	mysqlCfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+	for _, attr := range strings.Split(mysqlCfg.ConnectionAttributes, ",") {
+		if k, _, _ := strings.Cut(attr, ":"); strings.TrimSpace(k) == "program_name" {
+			return dsn, nil
+		}
+	}
+	attrs := "program_name:" + connectionProgramName
+	if mysqlCfg.ConnectionAttributes != "" {
+		attrs = mysqlCfg.ConnectionAttributes + "," + attrs
+	}
+	mysqlCfg.ConnectionAttributes = attrs
+	return mysqlCfg.FormatDSN(), nil
+}
+
+// startPoolMaintenance starts the keepalive and idle session reaper enabled
+// by c. Both stop when ctx is cancelled.
+func startPoolMaintenance(ctx context.Context, c *config.Config) {
+	if c.KeepaliveInterval > 0 {
+		go runKeepalive(ctx, c.KeepaliveInterval)
+		readiness.recordSubsystem("keepalive", "ok")
+	}
+	if c.ReapIdleSessionsAfter > 0 {
+		go newSessionReaper(c.ReapIdleSessionsAfter).Run(ctx)
+		readiness.recordSubsystem("session_reaper", "ok")
+	}
+}
+
+// runKeepalive pings the idle connections of every open pool each interval,
+// so connections the pool keeps are not dropped by wait_timeout or a firewall
+// while the server is quiet.
+func runKeepalive(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for name, db := range connManager.Pools() {
+			pinged, failed := pingIdleConns(ctx, db)
+			fields := map[string]interface{}{"connection": name, "pinged": pinged, "failed": failed}
+			if failed > 0 {
+				poolLog.Warn("keepalive ping failed", fields)
+				notifyConnectionUnhealthy(name, fmt.Sprintf("%d of %d keepalive pings failed", failed, pinged+failed))
+			} else if pinged > 0 {
+				poolLog.Debug("keepalive", fields)
+			}
+		}
+	}
+}
+
+// pingIdleConns checks out the idle connections of db, pings each and returns
+// them to the pool; database/sql discards one whose ping reports a bad
+// connection. It stops when no idle connection is left rather than open new ones.
+func pingIdleConns(ctx context.Context, db *sql.DB) (pinged, failed int) {
+	var conns []*sql.Conn
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	for n := db.Stats().Idle; n > 0 && db.Stats().Idle > 0; n-- {
+		c, err := db.Conn(ctx)
+		if err != nil {
+			break
+		}
+		conns = append(conns, c)
+	}
+	for _, c := range conns {
+		pctx, cancel := context.WithTimeout(ctx, pingTimeout)
+		if err := c.PingContext(pctx); err != nil {
+			failed++
+		} else {
+			pinged++
+		}
+		cancel()
+	}
+	return pinged, failed
+}
+
+// reapableSessionsQuery lists the sessions of the current account opened by
+// this server (any process) that have been idle for at least ? seconds.
+const reapableSessionsQuery = `SELECT p.ID, p.TIME, p.HOST
+	FROM information_schema.PROCESSLIST p
+	JOIN performance_schema.session_account_connect_attrs a
+	  ON a.PROCESSLIST_ID = p.ID AND a.ATTR_NAME = 'program_name' AND a.ATTR_VALUE = ?
+	WHERE p.COMMAND = 'Sleep' AND p.TIME >= ? AND p.ID <> CONNECTION_ID()`
+
+// sessionReaper kills this server's sessions left in Sleep longer than
+// after. The threshold exceeds the pool idle time, so such a session is not
+// held by this process' pool: it was left by a process that exited or lost
+// its client without closing it.
+type sessionReaper struct {
+	after time.Duration
+
+	mu          sync.Mutex
+	unavailable map[string]bool // connections where the sessions query failed
+}
+
+func newSessionReaper(after time.Duration) *sessionReaper {
+	return &sessionReaper{after: after, unavailable: map[string]bool{}}
+}
+
+// Run sweeps every open pool each after/2 until ctx is cancelled.
+func (r *sessionReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.after / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for name, db := range connManager.Pools() {
+			r.sweep(ctx, name, db)
+		}
+	}
+}
+
+// reapedSession is one killed session, as reported in the log.
+type reapedSession struct {
+	ID          int64  `json:"id"`
+	IdleSeconds int64  `json:"idle_seconds"`
+	Host        string `json:"host"`
+}
+
+// sweep kills the reapable sessions of one connection and logs them. It
+// returns the sessions killed.
+func (r *sessionReaper) sweep(ctx context.Context, name string, db *sql.DB) []reapedSession {
+	r.mu.Lock()
+	skip := r.unavailable[name]
+	r.mu.Unlock()
+	if skip {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	rows, err := db.QueryContext(ctx, reapableSessionsQuery, connectionProgramName, int64(r.after.Seconds()))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.mu.Lock()
+			r.unavailable[name] = true
+			r.mu.Unlock()
+			poolLog.Warn("idle session reaper unavailable on this connection", map[string]interface{}{
+				"connection": name,
+				"error":      err.Error(),
+			})
+		}
+		return nil
+	}
+	var candidates []reapedSession
+	for rows.Next() {
+		var s reapedSession
+		var host sql.NullString
+		if err := rows.Scan(&s.ID, &s.IdleSeconds, &host); err != nil {
+			continue
+		}
+		s.Host = sessionHostWithoutPort(host.String)
+		candidates = append(candidates, s)
+	}
+	rows.Close()
+
+	var reaped []reapedSession
+	for _, s := range candidates {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("KILL %d", s.ID)); err != nil {
+			var mysqlErr *mysql.MySQLError
+			if !errors.As(err, &mysqlErr) || mysqlErr.Number != 1094 { // 1094: already gone
+				poolLog.Warn("failed to reap idle session", map[string]interface{}{
+					"connection": name,
+					"id":         s.ID,
+					"error":      err.Error(),
+				})
+			}
+			continue
+		}
+		reaped = append(reaped, s)
+	}
+	if len(reaped) > 0 {
+		poolLog.Info("reaped idle sessions", map[string]interface{}{
+			"connection": name,
+			"count":      len(reaped),
+			"sessions":   reaped,
+		})
+	}
+	return reaped
+}
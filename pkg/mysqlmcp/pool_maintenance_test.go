@@ -0,0 +1,93 @@
+// pkg/mysqlmcp/pool_maintenance_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestApplyProgramNameDSN(t *testing.T) {
+	tests := []struct {
+		dsn, want string
+	}{
+		{"u@tcp(h:3306)/db", "program_name:mysql-mcp-server"},
+		{"u@tcp(h:3306)/db?connectionAttributes=team:data", "team:data,program_name:mysql-mcp-server"},
+		{"u@tcp(h:3306)/db?connectionAttributes=program_name:etl", "program_name:etl"},
+	}
+	for _, tt := range tests {
+		dsn, err := applyProgramNameDSN(tt.dsn)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.dsn, err)
+		}
+		cfg, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			t.Fatalf("%s: %v", dsn, err)
+		}
+		if cfg.ConnectionAttributes != tt.want {
+			t.Errorf("%s: attributes = %q, want %q", tt.dsn, cfg.ConnectionAttributes, tt.want)
+		}
+	}
+}
+
+func TestPingIdleConns(t *testing.T) {
+	oldPingTimeout := pingTimeout
+	pingTimeout = time.Second
+	defer func() { pingTimeout = oldPingTimeout }()
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	// sqlmock.New leaves one idle connection in the pool.
+	mock.ExpectPing()
+	if pinged, failed := pingIdleConns(context.Background(), db); pinged != 1 || failed != 0 {
+		t.Errorf("expected one successful ping, got %d/%d", pinged, failed)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSessionReaperSweep(t *testing.T) {
+	oldQueryTimeout := queryTimeout
+	queryTimeout = time.Second
+	defer func() { queryTimeout = oldQueryTimeout }()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+	r := newSessionReaper(10 * time.Minute)
+
+	mock.ExpectQuery("FROM information_schema.PROCESSLIST").
+		WithArgs(connectionProgramName, int64(600)).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "TIME", "HOST"}).
+			AddRow(41, 900, "10.0.0.5:51234").
+			AddRow(42, 1200, "10.0.0.6:40000"))
+	mock.ExpectExec("KILL 41").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("KILL 42").WillReturnError(&mysql.MySQLError{Number: 1094, Message: "Unknown thread id: 42"})
+
+	reaped := r.sweep(context.Background(), "prod", db)
+	if len(reaped) != 1 || reaped[0] != (reapedSession{ID: 41, IdleSeconds: 900, Host: "10.0.0.5"}) {
+		t.Fatalf("unexpected reaped sessions: %+v", reaped)
+	}
+
+	// Without performance_schema the connection is skipped from then on.
+	mock.ExpectQuery("FROM information_schema.PROCESSLIST").
+		WillReturnError(&mysql.MySQLError{Number: 1142, Message: "SELECT command denied"})
+	r.sweep(context.Background(), "prod", db)
+	r.sweep(context.Background(), "prod", db)
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if !r.unavailable["prod"] {
+		t.Error("expected the connection to be marked unavailable")
+	}
+}
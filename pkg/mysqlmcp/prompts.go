@@ -0,0 +1,202 @@
+// pkg/mysqlmcp/prompts.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// promptSpec is one entry of the prompt registry: the MCP prompt definition,
+// whether the current configuration serves it, and the function rendering its
+// text from the (trimmed) arguments. Texts only name tools that are registered
+// under the current configuration.
+type promptSpec struct {
+	prompt  *mcp.Prompt
+	enabled func() bool
+	render  func(args map[string]string) (string, error)
+}
+
+// promptRegistry lists the canned analysis prompts served by the server.
+var promptRegistry = []promptSpec{
+	{
+		prompt: &mcp.Prompt{
+			Name:        "analyze_slow_queries",
+			Title:       "Analyze slow queries",
+			Description: "Find the slowest recent statements, explain their plans and propose indexes or rewrites",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "database", Description: "only consider statements against this database"},
+				{Name: "limit", Description: "number of statements to analyze (default 5)"},
+			},
+		},
+		enabled: func() bool { return extendedMode },
+		render:  renderAnalyzeSlowQueries,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "review_schema",
+			Title:       "Review this schema",
+			Description: "Review a database or table for missing keys, redundant indexes, type and naming problems",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "database", Description: "database to review", Required: true},
+				{Name: "table", Description: "review only this table"},
+			},
+		},
+		render: renderReviewSchema,
+	},
+	{
+		prompt: &mcp.Prompt{
+			Name:        "explain_query_plan",
+			Title:       "Explain this query plan",
+			Description: "Explain how MySQL executes a query, point out costly steps and suggest fixes",
+			Arguments: []*mcp.PromptArgument{
+				{Name: "sql", Description: "the SELECT query to explain", Required: true},
+				{Name: "database", Description: "database the query runs in"},
+			},
+		},
+		render: renderExplainQueryPlan,
+	},
+}
+
+// registerPrompts adds the prompts of the registry enabled by cfg.
+func registerPrompts(server *mcp.Server) {
+	for _, spec := range promptRegistry {
+		if spec.enabled != nil && !spec.enabled() {
+			continue
+		}
+		server.AddPrompt(spec.prompt, promptHandler(spec))
+	}
+}
+
+func promptHandler(spec promptSpec) mcp.PromptHandler {
+	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := map[string]string{}
+		for name, v := range req.Params.Arguments {
+			args[name] = strings.TrimSpace(v)
+		}
+		for _, a := range spec.prompt.Arguments {
+			if a.Required && args[a.Name] == "" {
+				return nil, fmt.Errorf("prompt %s requires the %s argument", spec.prompt.Name, a.Name)
+			}
+		}
+		if db := args["database"]; db != "" {
+			if err := requireAllowedDatabase(db); err != nil {
+				return nil, err
+			}
+		}
+		text, err := spec.render(args)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.GetPromptResult{
+			Description: spec.prompt.Description,
+			Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: text}},
+			},
+		}, nil
+	}
+}
+
+// promptSteps numbers steps, skipping empty ones left by disabled tools.
+func promptSteps(b *strings.Builder, steps ...string) {
+	n := 0
+	for _, s := range steps {
+		if s == "" {
+			continue
+		}
+		n++
+		fmt.Fprintf(b, "%d. %s\n", n, s)
+	}
+}
+
+// promptIf returns s if cond holds and "" otherwise.
+func promptIf(cond bool, s string) string {
+	if cond {
+		return s
+	}
+	return ""
+}
+
+func renderAnalyzeSlowQueries(args map[string]string) (string, error) {
+	limit := 5
+	if s := args["limit"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 || n > 50 {
+			return "", fmt.Errorf("limit must be a number between 1 and 50")
+		}
+		limit = n
+	}
+	scope := "the server"
+	if db := args["database"]; db != "" {
+		scope = fmt.Sprintf("the %s database", db)
+	}
+	slowLog := cfg != nil && cfg.SlowQueryTool
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Analyze the slowest queries on %s and recommend fixes for the top %d.\n\n", scope, limit)
+	promptSteps(&b,
+		"Call health_report and note the slow query rate, buffer pool hit ratio, tmp disk tables and top wait events.",
+		promptIf(slowLog, fmt.Sprintf("Call slow_query_log with limit %d or more and group the statements by shape; rank them by total time (count times average).", limit*4)),
+		promptIf(!slowLog, "The slow query log tool is not enabled on this server (MYSQL_MCP_SLOW_QUERY_TOOL=1); ask the user for the slow statements or a slow log excerpt."),
+		fmt.Sprintf("For each of the top %d statements, call explain_query (format json) and list_indexes for the tables it reads.", limit),
+		"Identify full scans, filesorts, temporary tables and large rows-examined to rows-sent ratios.",
+		"Recommend concrete changes: the exact CREATE INDEX or rewritten SQL, why it helps, and the expected effect. Do not run DDL; present it for review.",
+	)
+	b.WriteString("\nFinish with a table of statement, problem, fix and expected impact, ordered by impact.\n")
+	return b.String(), nil
+}
+
+func renderReviewSchema(args map[string]string) (string, error) {
+	db, table := args["database"], args["table"]
+	target := fmt.Sprintf("the %s database", db)
+	if table != "" {
+		target = fmt.Sprintf("the table %s.%s", db, table)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review the schema of %s as an experienced MySQL DBA.\n\n", target)
+	if table == "" {
+		promptSteps(&b,
+			fmt.Sprintf("Call list_tables for %s with include_metadata true to see engines, sizes and row estimates.", db),
+			promptIf(extendedMode, fmt.Sprintf("Call schema_graph for %s to see how the tables relate.", db)),
+			"Call describe_table for each table (start with the largest ones if there are many).",
+			promptIf(extendedMode, "Call list_indexes and table_constraints for each table you review."),
+		)
+	} else {
+		promptSteps(&b,
+			fmt.Sprintf("Call describe_table for %s in %s.", table, db),
+			promptIf(extendedMode, "Call show_create_table, list_indexes and table_constraints for it."),
+			promptIf(extendedMode, fmt.Sprintf("Call foreign_keys for %s to see which tables reference it.", db)),
+		)
+	}
+	b.WriteString("\nCheck for: tables without a primary key; redundant or duplicate indexes (an index that is a prefix of another); " +
+		"foreign key columns without an index; oversized or mismatched column types (e.g. VARCHAR(255) everywhere, " +
+		"ids stored as strings, mismatched types across a foreign key); nullable columns that should not be; " +
+		"non-InnoDB tables; inconsistent naming and character sets.\n\n")
+	b.WriteString("Report each finding with the table, the problem, its impact and the suggested DDL. Do not run DDL; present it for review.\n")
+	return b.String(), nil
+}
+
+func renderExplainQueryPlan(args map[string]string) (string, error) {
+	where := ""
+	if db := args["database"]; db != "" {
+		where = fmt.Sprintf(" in the %s database", db)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Explain how MySQL executes this query%s and how to make it faster:\n\n```sql\n%s\n```\n\n", where, args["sql"])
+	promptSteps(&b,
+		"Call validate_query with the query to confirm it is accepted and see the tables it reads and the optimizer's row estimate.",
+		promptIf(extendedMode, "Call explain_query with format tree (and json for costs) to get the full plan."),
+		promptIf(!extendedMode, "Use the plan returned by validate_query; the detailed plan tools need MYSQL_MCP_EXTENDED=1."),
+		promptIf(extendedMode, "Call list_indexes for each table in the plan."),
+		promptIf(extendedMode, "If the optimizer's choice looks wrong, call optimizer_trace to see the alternatives it considered."),
+	)
+	b.WriteString("\nWalk through the plan step by step in plain language: access type, index used, rows examined, " +
+		"filtering, sorting and temporary tables. Point out the most expensive steps, then suggest index changes or " +
+		"rewrites, showing the exact SQL. Do not run DDL; present it for review.\n")
+	return b.String(), nil
+}
@@ -0,0 +1,129 @@
+// pkg/mysqlmcp/prompts_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// promptSession connects an in-memory client to a server built with the
+// given extended mode and config.
+func promptSession(t *testing.T, extended bool, c *config.Config) *mcp.ClientSession {
+	t.Helper()
+	oldCfg, oldExtended := cfg, extendedMode
+	cfg, extendedMode = c, extended
+	t.Cleanup(func() { cfg, extendedMode = oldCfg, oldExtended })
+
+	session, err := connectCLIClient(context.Background(), newMCPServer())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func promptText(t *testing.T, res *mcp.GetPromptResult) string {
+	t.Helper()
+	if len(res.Messages) != 1 {
+		t.Fatalf("expected one message, got %d", len(res.Messages))
+	}
+	text, ok := res.Messages[0].Content.(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", res.Messages[0].Content)
+	}
+	return text.Text
+}
+
+func TestPromptsListedByMode(t *testing.T) {
+	for _, extended := range []bool{false, true} {
+		session := promptSession(t, extended, &config.Config{})
+		res, err := session.ListPrompts(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("list prompts: %v", err)
+		}
+		names := map[string]bool{}
+		for _, p := range res.Prompts {
+			names[p.Name] = true
+		}
+		if !names["review_schema"] || !names["explain_query_plan"] {
+			t.Errorf("extended=%v: core prompts missing from %v", extended, names)
+		}
+		if names["analyze_slow_queries"] != extended {
+			t.Errorf("extended=%v: analyze_slow_queries listed = %v", extended, names["analyze_slow_queries"])
+		}
+	}
+}
+
+func TestPromptsNameOnlyRegisteredTools(t *testing.T) {
+	ctx := context.Background()
+
+	// Core mode: the plan prompt must not send the model to extended tools.
+	session := promptSession(t, false, &config.Config{})
+	res, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "explain_query_plan",
+		Arguments: map[string]string{"sql": "SELECT * FROM orders WHERE id = 1", "database": "shop"},
+	})
+	if err != nil {
+		t.Fatalf("get prompt: %v", err)
+	}
+	text := promptText(t, res)
+	if !strings.Contains(text, "SELECT * FROM orders WHERE id = 1") || !strings.Contains(text, "validate_query") {
+		t.Errorf("plan prompt misses the query or validate_query:\n%s", text)
+	}
+	if strings.Contains(text, "Call explain_query") || strings.Contains(text, "optimizer_trace") {
+		t.Errorf("core-mode plan prompt names extended tools:\n%s", text)
+	}
+
+	// Extended mode with the slow log tool enabled.
+	session = promptSession(t, true, &config.Config{SlowQueryTool: true})
+	res, err = session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "analyze_slow_queries",
+		Arguments: map[string]string{"limit": "3"},
+	})
+	if err != nil {
+		t.Fatalf("get prompt: %v", err)
+	}
+	text = promptText(t, res)
+	for _, want := range []string{"health_report", "slow_query_log with limit 12", "top 3", "explain_query"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("slow query prompt misses %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPromptArgumentErrors(t *testing.T) {
+	ctx := context.Background()
+	session := promptSession(t, true, &config.Config{})
+	t.Cleanup(func() { initAccessControl(nil) })
+	initAccessControl([]string{"shop"})
+
+	tests := []struct {
+		name string
+		args map[string]string
+	}{
+		{"review_schema", map[string]string{}},
+		{"review_schema", map[string]string{"database": "hr"}},
+		{"explain_query_plan", map[string]string{"sql": "  "}},
+		{"analyze_slow_queries", map[string]string{"limit": "lots"}},
+	}
+	for _, tt := range tests {
+		if _, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: tt.name, Arguments: tt.args}); err == nil {
+			t.Errorf("%s %v: expected an error", tt.name, tt.args)
+		}
+	}
+
+	res, err := session.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "review_schema",
+		Arguments: map[string]string{"database": "shop", "table": "orders"},
+	})
+	if err != nil {
+		t.Fatalf("get prompt: %v", err)
+	}
+	if text := promptText(t, res); !strings.Contains(text, "shop.orders") || !strings.Contains(text, "list_indexes") {
+		t.Errorf("unexpected review prompt:\n%s", text)
+	}
+}
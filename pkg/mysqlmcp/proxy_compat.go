@@ -0,0 +1,104 @@
+// pkg/mysqlmcp/proxy_compat.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// ProxySQL and Vitess multiplex client connections over their own backend
+// pools, so session state set by one statement (USE, SET time_zone, SET
+// SESSION sql_mode) may be missing on the backend that runs the next one.
+// In proxy compatibility mode (query.proxy_compat, on by default for
+// connections whose probe found one of them) query tools qualify the tables
+// of a statement with its database instead of running USE, refuse per-call
+// session variables with an error that says why, and skip KILL QUERY on
+// cancel, whose connection id names a proxy or backend thread rather than
+// the statement's.
+
+// Middlewares recognized by the capability probe.
+const (
+	middlewareProxySQL = "proxysql"
+	middlewareVitess   = "vitess"
+)
+
+// detectMiddleware returns the middleware db connects through, or "".
+// vtgate reports versions such as 8.0.31-Vitess; ProxySQL answers this exact
+// version_comment query itself (clients send it on connect) with "(ProxySQL)".
+func detectMiddleware(ctx context.Context, db *sql.DB, version string) string {
+	if strings.Contains(strings.ToLower(version), middlewareVitess) {
+		return middlewareVitess
+	}
+	var comment string
+	if err := db.QueryRowContext(ctx, "select @@version_comment limit 1").Scan(&comment); err == nil &&
+		strings.Contains(strings.ToLower(comment), middlewareProxySQL) {
+		return middlewareProxySQL
+	}
+	return ""
+}
+
+func proxyCompatMode() string {
+	if cfg == nil || cfg.ProxyCompat == "" {
+		return config.DefaultProxyCompat
+	}
+	return cfg.ProxyCompat
+}
+
+// activeMiddleware returns the middleware probed on the active connection.
+func activeMiddleware() string {
+	if connManager == nil {
+		return ""
+	}
+	caps, _ := connManager.ActiveCapabilities()
+	if caps == nil {
+		return ""
+	}
+	return caps.Middleware
+}
+
+// proxyCompat reports whether tools must avoid session state on the active
+// connection.
+func proxyCompat() bool {
+	switch proxyCompatMode() {
+	case config.ProxyCompatOn:
+		return true
+	case config.ProxyCompatOff:
+		return false
+	}
+	return activeMiddleware() != ""
+}
+
+// proxySessionError reports that what needs session state the proxy does not
+// keep. It wraps errToolUnsupported so HTTP handlers answer 501.
+func proxySessionError(what, instead string) error {
+	via := "a proxy"
+	switch activeMiddleware() {
+	case middlewareProxySQL:
+		via = "ProxySQL"
+	case middlewareVitess:
+		via = "Vitess"
+	}
+	return fmt.Errorf("%w: %s needs session state, which is not kept reliably behind %s (MYSQL_MCP_PROXY_COMPAT=%s); %s",
+		errToolUnsupported, what, via, proxyCompatMode(), instead)
+}
+
+// scopeStatement prepares sqlText to run in database on db. Normally the
+// statement is returned as is and useDatabase selects database with USE; in
+// proxy compatibility mode its unqualified tables are qualified with
+// database instead and the database returned is "".
+func scopeStatement(db *sql.DB, sqlText, database string) (string, string, error) {
+	if database == "" || !proxyCompat() || database == homeDatabase(db) {
+		return sqlText, database, nil
+	}
+	scoped, err := util.QualifyDefaultDatabase(sqlText, database)
+	if err != nil {
+		return "", "", fmt.Errorf("proxy compatibility mode runs no USE, so the tables of the statement must be qualified with database %s: %w; write them as %s.table and leave database empty",
+			database, err, database)
+	}
+	return scoped, "", nil
+}
@@ -0,0 +1,87 @@
+// pkg/mysqlmcp/proxy_compat_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestDetectMiddleware(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if got := detectMiddleware(ctx, db, "8.0.31-Vitess"); got != middlewareVitess {
+		t.Errorf("expected vitess from the version, got %q", got)
+	}
+	mock.ExpectQuery("select @@version_comment limit 1").
+		WillReturnRows(sqlmock.NewRows([]string{"@@version_comment"}).AddRow("(ProxySQL)"))
+	if got := detectMiddleware(ctx, db, "5.5.30"); got != middlewareProxySQL {
+		t.Errorf("expected proxysql from the version comment, got %q", got)
+	}
+	mock.ExpectQuery("select @@version_comment limit 1").
+		WillReturnRows(sqlmock.NewRows([]string{"@@version_comment"}).AddRow("MySQL Community Server - GPL"))
+	if got := detectMiddleware(ctx, db, "8.0.36"); got != "" {
+		t.Errorf("expected no middleware, got %q", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRunQueryProxyCompat(t *testing.T) {
+	res := setupMockDBFull(t)
+	defer res.cleanup()
+	mock := res.mock
+	oldCfg := cfg
+	cfg = &config.Config{ProxyCompat: config.ProxyCompatAuto}
+	defer func() { cfg = oldCfg }()
+	ctx := context.Background()
+
+	// Without a detected proxy, auto mode selects the database with USE.
+	if proxyCompat() {
+		t.Fatal("auto mode should be off without a detected middleware")
+	}
+	connManager.capabilities["mock"] = &ConnectionCapabilities{Middleware: middlewareProxySQL}
+	if !proxyCompat() {
+		t.Fatal("auto mode should be on behind ProxySQL")
+	}
+
+	mock.ExpectQuery("select id from shop.orders as o join crm.customers as c").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	_, out, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{
+		SQL: "SELECT id FROM orders o JOIN crm.customers c ON c.id = o.customer_id", Database: "shop",
+	})
+	if err != nil || len(out.Rows) != 1 {
+		t.Fatalf("expected the qualified query to run without USE, got %v", err)
+	}
+
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SHOW TABLES", Database: "shop"}); err == nil ||
+		!strings.Contains(err.Error(), "shop.table") {
+		t.Errorf("expected SHOW with a database to be refused, got %v", err)
+	}
+	_, _, err = toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1", TimeZone: "+00:00"})
+	if !errors.Is(err, errToolUnsupported) || !strings.Contains(err.Error(), "ProxySQL") {
+		t.Errorf("expected time_zone to be refused behind ProxySQL, got %v", err)
+	}
+	if _, _, err := toolSetSessionSetting(ctx, &mcp.CallToolRequest{}, SetSessionSettingInput{Setting: "sql_mode", Add: []string{"ONLY_FULL_GROUP_BY"}}); !errors.Is(err, errToolUnsupported) {
+		t.Errorf("expected set_session_setting to be refused, got %v", err)
+	}
+
+	cfg.ProxyCompat = config.ProxyCompatOff
+	if proxyCompat() {
+		t.Error("off mode should ignore the detected middleware")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
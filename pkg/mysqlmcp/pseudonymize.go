@@ -0,0 +1,132 @@
+// pkg/mysqlmcp/pseudonymize.go
+package mysqlmcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// pseudonymizer replaces the values of configured columns with keyed hashes,
+// so the same real value always reads as the same pseudonym and results can
+// still be grouped, counted and joined without exposing the values.
+type pseudonymizer struct {
+	patterns   []string
+	key        []byte
+	perSession bool // derive a key per MCP session, so pseudonyms differ between sessions
+}
+
+var pseudonyms *pseudonymizer
+
+// initPseudonymizer enables pseudonymization when PseudonymizeColumns is set.
+// Without a configured PseudonymKey a random key is drawn at startup and
+// pseudonyms are only consistent within one MCP session.
+func initPseudonymizer(c *config.Config) {
+	if len(c.PseudonymizeColumns) == 0 {
+		pseudonyms = nil
+		return
+	}
+	p := &pseudonymizer{patterns: c.PseudonymizeColumns}
+	if c.PseudonymKey != "" {
+		p.key = []byte(c.PseudonymKey)
+	} else {
+		p.key = make([]byte, 32)
+		rand.Read(p.key)
+		p.perSession = true
+	}
+	pseudonyms = p
+}
+
+// sessionKey returns the hashing key for the MCP session of ctx. HTTP calls
+// have no session and share one key for the server's lifetime.
+func (p *pseudonymizer) sessionKey(ctx context.Context) []byte {
+	if !p.perSession {
+		return p.key
+	}
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(replicaSessionFrom(ctx)))
+	return mac.Sum(nil)
+}
+
+// rowFunc returns a func that pseudonymizes one row of cols in place, or nil
+// when no column matches. Columns already masked are left to the mask.
+func (p *pseudonymizer) rowFunc(ctx context.Context, cols []string, masked map[int]bool) func([]interface{}) {
+	if p == nil {
+		return nil
+	}
+	prefixes := map[int]string{}
+	for i, col := range cols {
+		if masked[i] {
+			continue
+		}
+		lowerCol := strings.ToLower(col)
+		for _, pat := range p.patterns {
+			if pat = strings.ToLower(strings.TrimSpace(pat)); pat != "" && strings.Contains(lowerCol, pat) {
+				prefixes[i] = pseudonymPrefix(pat)
+				break
+			}
+		}
+	}
+	if len(prefixes) == 0 {
+		return nil
+	}
+	key := p.sessionKey(ctx)
+	return func(row []interface{}) {
+		for idx, prefix := range prefixes {
+			if idx < len(row) && row[idx] != nil {
+				row[idx] = pseudonym(key, prefix, row[idx])
+			}
+		}
+	}
+}
+
+// pseudonymizeResults pseudonymizes rows in place according to the configured
+// columns, skipping those matched by the mask patterns.
+func pseudonymizeResults(ctx context.Context, cols []string, rows [][]interface{}) {
+	var mask []string
+	if cfg != nil {
+		mask = cfg.MaskColumns
+	}
+	if f := pseudonyms.rowFunc(ctx, cols, maskedColumns(cols, mask)); f != nil {
+		for _, row := range rows {
+			f(row)
+		}
+	}
+}
+
+// pseudonym hashes v under key. The prefix names the matched pattern; it is
+// not hashed, so a value reads the same in every column matched by it.
+func pseudonym(key []byte, prefix string, v interface{}) string {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case []byte:
+		s = string(t)
+	default:
+		s = fmt.Sprint(t)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return prefix + "_" + hex.EncodeToString(mac.Sum(nil)[:6])
+}
+
+// pseudonymPrefix turns a column pattern into a pseudonym prefix such as "email".
+func pseudonymPrefix(pattern string) string {
+	prefix := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, pattern)
+	if strings.Trim(prefix, "_") == "" {
+		return "anon"
+	}
+	return prefix
+}
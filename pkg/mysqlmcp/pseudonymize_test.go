@@ -0,0 +1,86 @@
+// pkg/mysqlmcp/pseudonymize_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPseudonymizer(t *testing.T) {
+	old := pseudonyms
+	defer func() { pseudonyms = old }()
+	sessionA := context.WithValue(context.Background(), replicaSessionKey{}, "mcp:a")
+	sessionB := context.WithValue(context.Background(), replicaSessionKey{}, "mcp:b")
+	cols := []string{"id", "email", "contact_email", "password"}
+	row := func() []interface{} {
+		return []interface{}{int64(7), "a@example.com", []byte("a@example.com"), "secret"}
+	}
+
+	initPseudonymizer(&config.Config{PseudonymizeColumns: []string{"email", "pass"}})
+	pseudonymize := pseudonyms.rowFunc(sessionA, cols, map[int]bool{3: true})
+	r1, r2 := row(), row()
+	pseudonymize(r1)
+	pseudonymize(r2)
+	email, ok := r1[1].(string)
+	if !ok || !strings.HasPrefix(email, "email_") || len(email) != len("email_")+12 {
+		t.Fatalf("unexpected pseudonym %v", r1[1])
+	}
+	if r1[0] != int64(7) || r1[3] != "secret" {
+		t.Errorf("unmatched and masked columns should be left alone: %v", r1)
+	}
+	if r1[2] != email || r2[1] != email {
+		t.Errorf("the same value should map to the same pseudonym: %v %v", r1, r2)
+	}
+
+	other := row()
+	pseudonyms.rowFunc(sessionB, cols, nil)(other)
+	if other[1] == email {
+		t.Error("pseudonyms should differ between sessions without a key")
+	}
+	if other[3] == "secret" || !strings.HasPrefix(other[3].(string), "pass_") {
+		t.Errorf("expected an unmasked matching column to be pseudonymized, got %v", other[3])
+	}
+
+	// A configured key keeps pseudonyms stable across sessions and restarts.
+	initPseudonymizer(&config.Config{PseudonymizeColumns: []string{"email"}, PseudonymKey: "k"})
+	a, b := row(), row()
+	pseudonyms.rowFunc(sessionA, cols, nil)(a)
+	pseudonyms.rowFunc(sessionB, cols, nil)(b)
+	if a[1] != b[1] || a[1] == email {
+		t.Errorf("expected keyed pseudonyms to match across sessions: %v %v", a[1], b[1])
+	}
+
+	if pseudonyms.rowFunc(sessionA, []string{"id"}, nil) != nil {
+		t.Error("expected no row func when no column matches")
+	}
+}
+
+func TestToolRunQueryPseudonymizes(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg, oldPseudonyms := cfg, pseudonyms
+	defer func() { cfg, pseudonyms = oldCfg, oldPseudonyms }()
+	cfg = &config.Config{MaskColumns: []string{"ssn"}, PseudonymizeColumns: []string{"email", "ssn"}}
+	initPseudonymizer(cfg)
+
+	mock.ExpectQuery("SELECT email, ssn FROM users").WillReturnRows(
+		sqlmock.NewRows([]string{"email", "ssn"}).AddRow("a@example.com", "123-45-6789").AddRow(nil, nil))
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT email, ssn FROM users"})
+	if err != nil {
+		t.Fatalf("run_query: %v", err)
+	}
+	if s, _ := out.Rows[0][0].(string); !strings.HasPrefix(s, "email_") {
+		t.Errorf("expected a pseudonymized email, got %v", out.Rows[0][0])
+	}
+	if out.Rows[0][1] != "********" {
+		t.Errorf("masking should win over pseudonymization, got %v", out.Rows[0][1])
+	}
+	if out.Rows[1][0] != nil {
+		t.Errorf("NULL should stay NULL, got %v", out.Rows[1][0])
+	}
+}
@@ -0,0 +1,368 @@
+// pkg/mysqlmcp/query_stream.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// A stream is flushed to the client every streamFlushRows rows, or sooner
+	// when streamFlushInterval has passed since the last flush.
+	streamFlushRows     = 100
+	streamFlushInterval = 100 * time.Millisecond
+)
+
+// Lines of a /api/query/stream response: one streamColumnsLine, one
+// streamRowLine per row, then one streamSummaryLine.
+type streamColumnsLine struct {
+	Columns         []string         `json:"columns"`
+	SkippedColumns  []string         `json:"skipped_columns,omitempty"`
+	TimeZone        string           `json:"time_zone,omitempty"`
+	UTCOffset       string           `json:"utc_offset,omitempty"`
+	TemporalColumns []TemporalColumn `json:"temporal_columns,omitempty"`
+	ColumnTypes     []ColumnType     `json:"column_types,omitempty"`
+	SourceTables    []string         `json:"source_tables,omitempty"`
+}
+
+type streamRowLine struct {
+	Row []interface{} `json:"row"`
+}
+
+type streamSummaryLine struct {
+	Done      bool   `json:"done"`
+	RowCount  int    `json:"row_count"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// rowSink receives the rows of a streamed query: the columns line before the
+// first row, then each row with its 1-based number.
+type rowSink interface {
+	start(header streamColumnsLine) error
+	row(values []interface{}, n int) error
+}
+
+// queryStream writes the rows of one query to an HTTP response as they are
+// scanned. Writes block while the client is slow to read, so scanning pauses
+// with them instead of buffering rows.
+type queryStream struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	enc       *json.Encoder
+	started   bool
+	lastFlush time.Time
+}
+
+func newQueryStream(w http.ResponseWriter) *queryStream {
+	return &queryStream{w: w, rc: http.NewResponseController(w), enc: json.NewEncoder(w)}
+}
+
+// start sends the response headers and the columns line.
+func (s *queryStream) start(line streamColumnsLine) error {
+	s.w.Header().Set("Content-Type", "application/x-ndjson")
+	s.w.Header().Set("Cache-Control", "no-store")
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+	if err := s.enc.Encode(line); err != nil {
+		return fmt.Errorf("failed to write to client: %w", err)
+	}
+	return s.flush()
+}
+
+func (s *queryStream) row(values []interface{}, n int) error {
+	if err := s.enc.Encode(streamRowLine{Row: values}); err != nil {
+		return fmt.Errorf("failed to write to client: %w", err)
+	}
+	if n%streamFlushRows == 0 || time.Since(s.lastFlush) >= streamFlushInterval {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *queryStream) finish(line streamSummaryLine) {
+	if err := s.enc.Encode(line); err == nil {
+		_ = s.flush()
+	}
+}
+
+func (s *queryStream) flush() error {
+	s.lastFlush = time.Now()
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return fmt.Errorf("failed to write to client: %w", err)
+	}
+	return nil
+}
+
+// streamRowLimit is the row cap of a stream: MYSQL_HTTP_STREAM_MAX_ROWS,
+// lowered by the database's max_rows setting and by the request's max_rows.
+// 0 means unlimited.
+func streamRowLimit(database string, requested *int) int {
+	limit := 0
+	if cfg != nil {
+		limit = cfg.StreamMaxRows
+	}
+	lower := func(n int) {
+		if n > 0 && (limit <= 0 || n < limit) {
+			limit = n
+		}
+	}
+	if n, ok := databaseRowLimit(database); ok {
+		lower(n)
+	}
+	if requested != nil {
+		lower(*requested)
+	}
+	return limit
+}
+
+// httpRunQueryStream handles POST /api/query/stream with the body of
+// /api/query. It applies the same checks as run_query, then answers with
+// newline-delimited JSON written while the rows are scanned. Errors found
+// before the first row are ordinary JSON error responses; later ones end the
+// stream with an {"error": ...} line.
+func httpRunQueryStream(w http.ResponseWriter, r *http.Request) {
+	var input RunQueryInput
+	if err := decodeJSONBody(w, r, &input); err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			api.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		api.WriteBadRequest(w, "invalid JSON body: "+err.Error())
+		return
+	}
+	if input.SQL == "" {
+		api.WriteBadRequest(w, "sql field is required")
+		return
+	}
+	if input.Offset != nil {
+		api.WriteBadRequest(w, "offset is not supported when streaming; use /api/query for pages")
+		return
+	}
+	ctx, cancel := httpContext(r)
+	defer cancel()
+
+	stream := newQueryStream(w)
+	_, summary, err := dispatchTool("run_query", stream.run)(ctx, nil, input)
+	switch {
+	case err != nil && !stream.started:
+		writeToolError(w, err)
+	case err != nil:
+		summary.Error = err.Error()
+		stream.finish(summary)
+	default:
+		summary.Done = true
+		stream.finish(summary)
+	}
+}
+
+// run executes the query and streams its rows to the client.
+func (s *queryStream) run(ctx context.Context, req *mcp.CallToolRequest, input RunQueryInput) (*mcp.CallToolResult, streamSummaryLine, error) {
+	summary, err := streamQuery(ctx, input, s, "run_query_stream")
+	return nil, summary, err
+}
+
+// streamQuery runs input with the checks of run_query and hands its rows to
+// sink as they are scanned, auditing the call as tool. The returned summary
+// counts the rows sent so far, also on error.
+func streamQuery(ctx context.Context, input RunQueryInput, sink rowSink, tool string) (streamSummaryLine, error) {
+	timer := NewQueryTimer(ctx, tool)
+	var summary streamSummaryLine
+
+	sqlText := strings.TrimSpace(input.SQL)
+	database := strings.TrimSpace(input.Database)
+	if accessControlEnabled() {
+		if database == "" {
+			return summary, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+		}
+		if err := requireAllowedDatabase(database); err != nil {
+			return summary, err
+		}
+	}
+	audit := func(query string, err error) {
+		if auditLogger == nil {
+			return
+		}
+		entry := &AuditEntry{
+			Tool:        tool,
+			Database:    database,
+			Query:       loggedSQL(query, 500),
+			QueryDigest: util.QueryDigest(sqlText),
+			DurationMs:  timer.ElapsedMs(),
+			RowCount:    summary.RowCount,
+			Success:     err == nil,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		auditLogger.LogContext(ctx, entry)
+	}
+
+	if err := validateSQL(sqlText); err != nil {
+		validatorLog.Warn("query rejected by validator", map[string]interface{}{
+			"error": err.Error(),
+			"query": loggedSQL(sqlText, 200),
+		})
+		audit(sqlText, err)
+		return summary, fmt.Errorf("query validation failed: %w", err)
+	}
+	if err := requireReferencedSchemasInQuery(sqlText); err != nil {
+		return summary, err
+	}
+	if err := requireConfirmation(input.Confirm); err != nil {
+		return summary, err
+	}
+	binary, err := resolveBinaryOutput(input.BinaryOutput)
+	if err != nil {
+		return summary, err
+	}
+	timeZone, err := resolveTimeZone(input.TimeZone)
+	if err != nil {
+		return summary, err
+	}
+
+	finalSQL, err := applyRowPolicies(sqlText, database)
+	if err != nil {
+		return summary, err
+	}
+
+	limit := streamRowLimit(database, input.MaxRows)
+	if limit > 0 && (cfg == nil || cfg.InjectLimit) {
+		// One extra row tells a capped stream from one that ended on its own.
+		finalSQL = util.InjectLimit(finalSQL, limit+1)
+	}
+
+	ctx, cancel := context.WithTimeout(withQueryTimeZone(ctx, timeZone), queryTimeoutFor(ctx))
+	defer cancel()
+	err = scanRows(ctx, sink, finalSQL, database, limit, binary, &summary)
+	if err != nil {
+		timer.LogError(err, finalSQL, nil, nil)
+		audit(finalSQL, err)
+		return summary, err
+	}
+	timer.LogSuccess(summary.RowCount, finalSQL, nil, nil)
+	audit(finalSQL, nil)
+	return summary, nil
+}
+
+// scanRows runs finalSQL on a dedicated connection, like runQueryScan, and
+// sends each row to sink as soon as it is read. Queries are not retried: rows
+// may already have reached the client when an error occurs.
+func scanRows(ctx context.Context, sink rowSink, finalSQL, database string, limit int, binary string, summary *streamSummaryLine) error {
+	db := getReadDB(ctx)
+	finalSQL, database, err := scopeStatement(db, finalSQL, database)
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	restoreDatabase, err := useDatabase(ctx, db, conn, database)
+	if err != nil {
+		return err
+	}
+	defer restoreDatabase()
+
+	tz := queryTimeZone(ctx)
+	restoreTimeZone, err := useTimeZone(ctx, conn, tz)
+	if err != nil {
+		return err
+	}
+	defer restoreTimeZone()
+
+	restoreSettings, err := useSessionSettings(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer restoreSettings()
+	var header streamColumnsLine
+	if tz != "" {
+		// The header goes out before the rows are read, while the
+		// connection is busy, so the zone is read up front.
+		header.TimeZone, header.UTCOffset, _ = sessionTimeZone(ctx, conn)
+	}
+
+	stopWatchdog, err := killQueryOnCancel(ctx, db, conn)
+	if err != nil {
+		return err
+	}
+	defer stopWatchdog()
+
+	rows, err := conn.QueryContext(ctx, withMaxExecutionTime(ctx, finalSQL))
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to get columns: %w", err)
+	}
+	renderer, err := newCellRenderer(rows, binary)
+	if err != nil {
+		return err
+	}
+	header.Columns, header.SkippedColumns = renderer.columns(columns)
+	if types, err := rows.ColumnTypes(); err == nil {
+		header.TemporalColumns = temporalColumns(types)
+		header.ColumnTypes = columnTypes(types, renderer, len(header.Columns))
+	}
+	if n := util.NormalizeQuery(finalSQL); n.Parsed {
+		header.SourceTables = n.Tables
+	}
+	if len(header.TemporalColumns) == 0 {
+		header.TimeZone, header.UTCOffset = "", ""
+	}
+	var masked map[int]bool
+	if cfg != nil {
+		masked = maskedColumns(header.Columns, cfg.MaskColumns)
+	}
+	pseudonymize := pseudonyms.rowFunc(ctx, header.Columns, masked)
+	if err := sink.start(header); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		if limit > 0 && summary.RowCount >= limit {
+			summary.Truncated = true
+			break
+		}
+		values, err := scanAndNormalizeRow(rows, len(columns))
+		if err != nil {
+			return err
+		}
+		values = renderer.row(values)
+		for idx := range masked {
+			if values[idx] != nil {
+				values[idx] = "********"
+			}
+		}
+		if pseudonymize != nil {
+			pseudonymize(values)
+		}
+		summary.RowCount++
+		if err := sink.row(values, summary.RowCount); err != nil {
+			return err
+		}
+	}
+	if summary.Truncated {
+		return nil
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration failed: %w", err)
+	}
+	return nil
+}
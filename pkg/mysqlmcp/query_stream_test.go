@@ -0,0 +1,149 @@
+// pkg/mysqlmcp/query_stream_test.go
+package mysqlmcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// streamLines decodes an NDJSON response body into one map per line.
+func streamLines(t *testing.T, body string) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", sc.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func postStream(body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/query/stream", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	httpRunQueryStream(w, req)
+	return w
+}
+
+func TestHTTPRunQueryStream(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+	cfg.MaskColumns = []string{"email"}
+
+	rows := sqlmock.NewRows([]string{"id", "email"}).
+		AddRow(1, "a@example.com").
+		AddRow(2, "b@example.com")
+	mock.ExpectQuery("SELECT id, email FROM users").WillReturnRows(rows)
+
+	w := postStream(`{"sql": "SELECT id, email FROM users"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Errorf("expected NDJSON content type, got %q", ct)
+	}
+	if !w.Flushed {
+		t.Error("expected the stream to be flushed")
+	}
+
+	lines := streamLines(t, w.Body.String())
+	if len(lines) != 4 {
+		t.Fatalf("expected columns, 2 rows and a summary, got %v", lines)
+	}
+	if cols := lines[0]["columns"].([]interface{}); len(cols) != 2 || cols[1] != "email" {
+		t.Errorf("unexpected columns line: %v", lines[0])
+	}
+	row := lines[1]["row"].([]interface{})
+	if row[0] != float64(1) || row[1] != "********" {
+		t.Errorf("unexpected first row (email should be masked): %v", row)
+	}
+	if lines[3]["done"] != true || lines[3]["row_count"] != float64(2) || lines[3]["truncated"] != nil {
+		t.Errorf("unexpected summary: %v", lines[3])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPRunQueryStreamRowCap(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+	cfg.StreamMaxRows = 5
+	cfg.InjectLimit = true
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3)
+	mock.ExpectQuery("SELECT id FROM t LIMIT 3").WillReturnRows(rows)
+
+	// max_rows lowers the server-side cap of 5; one extra row is fetched to
+	// detect truncation.
+	w := postStream(`{"sql": "SELECT id FROM t", "max_rows": 2}`)
+	lines := streamLines(t, w.Body.String())
+	if len(lines) != 4 {
+		t.Fatalf("expected columns, 2 rows and a summary, got %v", lines)
+	}
+	if lines[3]["row_count"] != float64(2) || lines[3]["truncated"] != true {
+		t.Errorf("expected a truncated summary of 2 rows, got %v", lines[3])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPRunQueryStreamErrors(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"invalid json", `{invalid`, http.StatusBadRequest},
+		{"empty sql", `{"sql": ""}`, http.StatusBadRequest},
+		{"offset", `{"sql": "SELECT 1", "offset": 10}`, http.StatusBadRequest},
+		{"rejected", `{"sql": "DELETE FROM users"}`, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if w := postStream(tt.body); w.Code != tt.code {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.code, w.Code)
+		}
+	}
+
+	// A failure before any row is sent is a plain JSON error.
+	mock.ExpectQuery("SELECT id FROM missing").WillReturnError(errors.New("Table 'missing' doesn't exist"))
+	w := postStream(`{"sql": "SELECT id FROM missing"}`)
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "doesn't exist") {
+		t.Errorf("expected a 500 JSON error, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// A failure mid-stream ends the stream with an error line.
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).RowError(1, errors.New("connection lost"))
+	mock.ExpectQuery("SELECT id FROM t").WillReturnRows(rows)
+	w = postStream(`{"sql": "SELECT id FROM t"}`)
+	lines := streamLines(t, w.Body.String())
+	last := lines[len(lines)-1]
+	if w.Code != http.StatusOK || last["done"] != false || !strings.Contains(last["error"].(string), "connection lost") {
+		t.Errorf("expected an error line ending the stream, got %d: %v", w.Code, lines)
+	}
+	if last["row_count"] != float64(1) {
+		t.Errorf("expected 1 row before the error, got %v", last)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// pkg/mysqlmcp/query_watchdog.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// killOnCancelEnabled reports whether canceled queries are killed. Behind a
+// proxy, CONNECTION_ID() does not name the thread running the statement.
+func killOnCancelEnabled() bool {
+	return cfg != nil && cfg.KillOnCancel && !proxyCompat()
+}
+
+// killQueryOnCancel arranges for KILL QUERY to be sent for conn's server thread
+// if ctx ends (client cancel, HTTP disconnect or timeout) before the returned
+// stop function is called. The driver only abandons the connection on
+// cancellation; without this MySQL keeps executing the statement.
+//
+// The KILL runs on a separate pooled connection. stop waits for an in-flight
+// KILL so it always lands before conn is returned to the pool and reused.
+func killQueryOnCancel(ctx context.Context, db *sql.DB, conn *sql.Conn) (stop func(), err error) {
+	if !killOnCancelEnabled() {
+		return func() {}, nil
+	}
+
+	var id int64
+	if err := conn.QueryRowContext(ctx, "SELECT CONNECTION_ID()").Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to read connection id: %w", err)
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+
+		killCtx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+		if _, err := db.ExecContext(killCtx, fmt.Sprintf("KILL QUERY %d", id)); err != nil {
+			serverLog.Warn("failed to kill canceled query", map[string]interface{}{
+				"connection_id": id,
+				"error":         err.Error(),
+			})
+			return
+		}
+		serverLog.Info("killed query after cancellation", map[string]interface{}{
+			"connection_id": id,
+			"reason":        ctx.Err().Error(),
+		})
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}, nil
+}
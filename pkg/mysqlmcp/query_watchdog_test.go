@@ -0,0 +1,56 @@
+// pkg/mysqlmcp/query_watchdog_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+func TestRunQueryScanKillsQueryOnCancel(t *testing.T) {
+	res := setupMockDBFull(t)
+	defer res.cleanup()
+	mock := res.mock
+
+	oldCfg := cfg
+	cfg = &config.Config{KillOnCancel: true}
+	defer func() { cfg = oldCfg }()
+
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery("SELECT CONNECTION_ID\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"CONNECTION_ID()"}).AddRow(42))
+	mock.ExpectQuery("SELECT SLEEP\\(10\\)").
+		WillDelayFor(5 * time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"SLEEP(10)"}).AddRow(0))
+	mock.ExpectExec("KILL QUERY 42").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	if _, err := runQueryScan(ctx, res.mockDB, "SELECT SLEEP(10)", "", 10, false, 0, ""); err == nil {
+		t.Fatal("expected canceled query to fail")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRunQueryScanWatchdogDisabledByDefault(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+
+	if _, err := runQueryScan(context.Background(), getDB(), "SELECT 1", "", 10, false, 0, ""); err != nil {
+		t.Fatalf("runQueryScan failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+// pkg/mysqlmcp/quotas.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Quotas cap what each caller of a shared server may use: tool calls that
+// reach MySQL per clock hour and result rows per UTC day. The global HTTP
+// rate limiter protects the server; quotas keep one API key or MCP client
+// from using up a shared deployment. A call is checked before it runs and
+// counted when it finishes, so calls that are denied, rejected as invalid or
+// refused by the circuit breaker cost nothing. Tools that never touch MySQL
+// (toolQueryWeight 0) are not limited.
+
+// errQuotaExceeded is wrapped by QuotaExceededError so HTTP handlers can answer 429.
+var errQuotaExceeded = errors.New("quota exceeded")
+
+// quotaMetaKey is the MCP result _meta key holding the caller's remaining quota.
+const quotaMetaKey = "quota"
+
+// quotaFlushInterval is how often changed counters are written to the state file.
+const quotaFlushInterval = 10 * time.Second
+
+// QuotaExceededError reports that a caller used up one of its quotas.
+type QuotaExceededError struct {
+	Identity string
+	Rows     bool // the daily row quota, rather than the hourly query quota
+	Limit    int
+	RetryIn  time.Duration // until the window resets
+}
+
+func (e *QuotaExceededError) Error() string {
+	return e.localize(config.LocaleEnglish)
+}
+
+func (e *QuotaExceededError) localize(locale string) string {
+	if e.Rows {
+		return tr(locale, "quota exceeded: %s has received its %d rows for today; resets in %s",
+			e.Identity, e.Limit, e.RetryIn.Round(time.Second))
+	}
+	return tr(locale, "quota exceeded: %s has made its %d queries for this hour; resets in %s",
+		e.Identity, e.Limit, e.RetryIn.Round(time.Second))
+}
+
+func (e *QuotaExceededError) Unwrap() error { return errQuotaExceeded }
+
+// quotaUsage is one caller's counters. A counter whose window has passed
+// reads as zero.
+type quotaUsage struct {
+	Hour    time.Time `json:"hour"` // start of the clock hour Queries counts
+	Queries int       `json:"queries"`
+	Day     time.Time `json:"day"` // start of the UTC day Rows counts
+	Rows    int       `json:"rows"`
+}
+
+// current returns u with the counters of past windows reset.
+func (u quotaUsage) current(now time.Time) quotaUsage {
+	hour, day := quotaWindows(now)
+	if !u.Hour.Equal(hour) {
+		u.Hour, u.Queries = hour, 0
+	}
+	if !u.Day.Equal(day) {
+		u.Day, u.Rows = day, 0
+	}
+	return u
+}
+
+// quotaWindows returns the start of the hour and of the UTC day around now.
+func quotaWindows(now time.Time) (hour, day time.Time) {
+	now = now.UTC()
+	return now.Truncate(time.Hour), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// quotaFile is the layout of the quota state file.
+type quotaFile struct {
+	Identities map[string]quotaUsage `json:"identities"`
+}
+
+// quotaTracker holds the counters of every caller.
+type quotaTracker struct {
+	path string // "" = memory only
+	now  func() time.Time
+
+	mu    sync.Mutex
+	usage map[string]quotaUsage
+	dirty bool // changed since the last write of path
+}
+
+// quotas is nil when no quota is configured.
+var quotas *quotaTracker
+
+// initQuotas enables quotas when the default quota or a role quota limits
+// anything, reading the counters of cfg.QuotaFile when it exists.
+func initQuotas(c *config.Config) error {
+	enabled := c.Quota.Enabled()
+	for _, q := range c.QuotaRoles {
+		enabled = enabled || q.Enabled()
+	}
+	if !enabled {
+		quotas = nil
+		return nil
+	}
+	t := &quotaTracker{path: c.QuotaFile, now: time.Now, usage: make(map[string]quotaUsage)}
+	if t.path != "" {
+		data, err := os.ReadFile(t.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read quota state file: %w", err)
+		}
+		if err == nil {
+			var f quotaFile
+			if err := json.Unmarshal(data, &f); err != nil {
+				return fmt.Errorf("invalid quota state file %s: %w", t.path, err)
+			}
+			for id, u := range f.Identities {
+				t.usage[id] = u
+			}
+		}
+	}
+	quotas = t
+	return nil
+}
+
+// quotaFor returns the quota of role, falling back to the default quota.
+func quotaFor(role string) config.Quota {
+	if q, ok := cfg.QuotaRoles[role]; ok {
+		return q
+	}
+	return cfg.Quota
+}
+
+// check returns a QuotaExceededError when identity has no queries or rows
+// left under limits.
+func (t *quotaTracker) check(identity string, limits config.Quota) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	u := t.usage[identity].current(now)
+	hour, day := quotaWindows(now)
+	if limits.MaxQueriesPerHour > 0 && u.Queries >= limits.MaxQueriesPerHour {
+		return &QuotaExceededError{Identity: identity, Limit: limits.MaxQueriesPerHour, RetryIn: hour.Add(time.Hour).Sub(now)}
+	}
+	if limits.MaxRowsPerDay > 0 && u.Rows >= limits.MaxRowsPerDay {
+		return &QuotaExceededError{Identity: identity, Rows: true, Limit: limits.MaxRowsPerDay, RetryIn: day.AddDate(0, 0, 1).Sub(now)}
+	}
+	return nil
+}
+
+// record counts one finished call that returned rows and reports what is
+// left of identity's quota.
+func (t *quotaTracker) record(identity string, limits config.Quota, rows int) quotaStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u := t.usage[identity].current(t.now())
+	u.Queries++
+	u.Rows += rows
+	t.usage[identity] = u
+	t.dirty = true
+	return newQuotaStatus(identity, limits, u)
+}
+
+// run writes changed counters to the state file until ctx is canceled, and
+// once more on the way out.
+func (t *quotaTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(quotaFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			t.flush()
+			return
+		case <-ticker.C:
+			t.flush()
+		}
+	}
+}
+
+// flush writes the counters of the current windows to the state file if
+// they changed. Callers whose windows have all passed are dropped.
+func (t *quotaTracker) flush() {
+	t.mu.Lock()
+	if !t.dirty || t.path == "" {
+		t.mu.Unlock()
+		return
+	}
+	now := t.now()
+	f := quotaFile{Identities: make(map[string]quotaUsage, len(t.usage))}
+	for id, u := range t.usage {
+		if c := u.current(now); c.Queries == 0 && c.Rows == 0 {
+			delete(t.usage, id)
+			continue
+		}
+		f.Identities[id] = u
+	}
+	t.dirty = false
+	t.mu.Unlock()
+
+	if err := writeJSONFile(t.path, f); err != nil {
+		serverLog.Warn("failed to write quota state file", map[string]interface{}{"path": t.path, "error": err.Error()})
+		t.mu.Lock()
+		t.dirty = true
+		t.mu.Unlock()
+	}
+}
+
+// quotaStatus is the caller's remaining quota, sent in the result _meta of
+// MCP calls and as X-Quota-* headers on HTTP responses. Unlimited quotas are
+// left out.
+type quotaStatus struct {
+	Identity         string `json:"identity"`
+	QueriesRemaining *int   `json:"queries_remaining,omitempty"`
+	QueriesReset     string `json:"queries_reset,omitempty"` // RFC 3339
+	RowsRemaining    *int   `json:"rows_remaining,omitempty"`
+	RowsReset        string `json:"rows_reset,omitempty"` // RFC 3339
+}
+
+func newQuotaStatus(identity string, limits config.Quota, u quotaUsage) quotaStatus {
+	s := quotaStatus{Identity: identity}
+	if limits.MaxQueriesPerHour > 0 {
+		left := max(0, limits.MaxQueriesPerHour-u.Queries)
+		s.QueriesRemaining = &left
+		s.QueriesReset = u.Hour.Add(time.Hour).Format(time.RFC3339)
+	}
+	if limits.MaxRowsPerDay > 0 {
+		left := max(0, limits.MaxRowsPerDay-u.Rows)
+		s.RowsRemaining = &left
+		s.RowsReset = u.Day.AddDate(0, 0, 1).Format(time.RFC3339)
+	}
+	return s
+}
+
+// writeHeaders sets the X-Quota-* headers of s on h.
+func (s quotaStatus) writeHeaders(h http.Header) {
+	if s.QueriesRemaining != nil {
+		h.Set("X-Quota-Queries-Remaining", strconv.Itoa(*s.QueriesRemaining))
+		h.Set("X-Quota-Queries-Reset", s.QueriesReset)
+	}
+	if s.RowsRemaining != nil {
+		h.Set("X-Quota-Rows-Remaining", strconv.Itoa(*s.RowsRemaining))
+		h.Set("X-Quota-Rows-Reset", s.RowsReset)
+	}
+}
+
+// quotaCall is a tool call admitted under a caller's quota.
+type quotaCall struct {
+	identity string
+	limits   config.Quota
+}
+
+// admitQuota checks the caller's quota before a call of tool runs. It
+// returns nil when the call is not limited.
+func admitQuota(ctx context.Context, req *mcp.CallToolRequest, tool string) (*quotaCall, error) {
+	t := quotas
+	if t == nil || toolQueryWeight(tool) == 0 {
+		return nil, nil
+	}
+	limits := quotaFor(callerRole(ctx, req))
+	if !limits.Enabled() {
+		return nil, nil
+	}
+	c := &quotaCall{identity: clientIdentityFrom(ctx).key(), limits: limits}
+	if err := t.check(c.identity, limits); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// finish counts the call with the rows it returned and reports the
+// remaining quota in res and, for HTTP requests, the response headers.
+func (c *quotaCall) finish(ctx context.Context, res *mcp.CallToolResult, rows int) *mcp.CallToolResult {
+	if c == nil || quotas == nil {
+		return res
+	}
+	status := quotas.record(c.identity, c.limits, rows)
+	if h := quotaHeadersFrom(ctx); h != nil {
+		status.writeHeaders(h)
+	}
+	if res == nil {
+		res = &mcp.CallToolResult{}
+	}
+	if res.Meta == nil {
+		res.Meta = mcp.Meta{}
+	}
+	res.Meta[quotaMetaKey] = status
+	return res
+}
+
+type quotaHeadersKey struct{}
+
+// quotaHeadersFrom returns the HTTP response headers in ctx, or nil for MCP calls.
+func quotaHeadersFrom(ctx context.Context) http.Header {
+	h, _ := ctx.Value(quotaHeadersKey{}).(http.Header)
+	return h
+}
+
+// withQuotaHeaders lets the tool calls of an HTTP request report the
+// caller's remaining quota in the response headers.
+func withQuotaHeaders(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if quotas == nil {
+			next(w, r)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), quotaHeadersKey{}, w.Header())))
+	}
+}
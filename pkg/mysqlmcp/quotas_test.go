@@ -0,0 +1,121 @@
+// pkg/mysqlmcp/quotas_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestQuotaTrackerWindows(t *testing.T) {
+	now := time.Date(2026, 10, 15, 9, 40, 0, 0, time.UTC)
+	tr := &quotaTracker{now: func() time.Time { return now }, usage: map[string]quotaUsage{}}
+	limits := config.Quota{MaxQueriesPerHour: 2, MaxRowsPerDay: 100}
+
+	tr.record("client a", limits, 10)
+	status := tr.record("client a", limits, 10)
+	if *status.QueriesRemaining != 0 || *status.RowsRemaining != 80 || status.QueriesReset != "2026-10-15T10:00:00Z" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	var over *QuotaExceededError
+	if err := tr.check("client a", limits); !errors.As(err, &over) || over.Rows || over.RetryIn != 20*time.Minute {
+		t.Fatalf("expected the hourly quota to be used up, got %v", err)
+	}
+	if err := tr.check("client b", limits); err != nil {
+		t.Errorf("another caller should have its own quota: %v", err)
+	}
+
+	// The next hour resets the queries but not the rows of the day.
+	now = now.Add(time.Hour)
+	if err := tr.check("client a", limits); err != nil {
+		t.Fatalf("expected a fresh hourly quota: %v", err)
+	}
+	tr.record("client a", limits, 90)
+	if err := tr.check("client a", limits); !errors.As(err, &over) || !over.Rows || over.Limit != 100 {
+		t.Fatalf("expected the daily row quota to be used up, got %v", err)
+	}
+	now = time.Date(2026, 10, 16, 0, 0, 1, 0, time.UTC)
+	if err := tr.check("client a", limits); err != nil {
+		t.Errorf("expected a fresh daily quota: %v", err)
+	}
+}
+
+func TestDispatchToolQuota(t *testing.T) {
+	oldCfg, oldQuotas := cfg, quotas
+	defer func() { cfg, quotas = oldCfg, oldQuotas }()
+	cfg = &config.Config{
+		Quota:      config.Quota{MaxQueriesPerHour: 1},
+		QuotaRoles: map[string]config.Quota{"admin": {}},
+	}
+	if err := initQuotas(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	h := dispatchTool("run_query", func(ctx context.Context, req *mcp.CallToolRequest, in RunQueryInput) (*mcp.CallToolResult, QueryResult, error) {
+		return nil, QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{1}, {2}}}, nil
+	})
+	header := http.Header{}
+	ctx := withClientIdentity(context.Background(), clientIdentity{APIKey: "***abcd", apiKeyHash: "01020304"})
+	ctx = context.WithValue(ctx, quotaHeadersKey{}, header)
+
+	res, _, err := h(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t"})
+	if err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	status, ok := res.Meta[quotaMetaKey].(quotaStatus)
+	if !ok || status.Identity != "api_key ***abcd (01020304)" || *status.QueriesRemaining != 0 || status.RowsRemaining != nil {
+		t.Errorf("unexpected quota meta: %+v", res.Meta)
+	}
+	if header.Get("X-Quota-Queries-Remaining") != "0" || header.Get("X-Quota-Rows-Remaining") != "" {
+		t.Errorf("unexpected quota headers: %v", header)
+	}
+
+	if _, _, err := h(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t"}); !errors.Is(err, errQuotaExceeded) {
+		t.Errorf("expected the second call to be over quota, got %v", err)
+	}
+	// A role without limits is not counted.
+	admin := withCallerRole(ctx, "admin")
+	if res, _, err := h(admin, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t"}); err != nil || res != nil {
+		t.Errorf("expected an unlimited call, got %v, %v", res, err)
+	}
+}
+
+func TestWriteToolErrorQuota(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeToolError(w, &QuotaExceededError{Identity: "ip 10.0.0.1", Limit: 100, RetryIn: 90 * time.Second})
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "90" {
+		t.Errorf("expected 429 with Retry-After 90, got %d %q", w.Code, w.Header().Get("Retry-After"))
+	}
+}
+
+func TestQuotaStateFile(t *testing.T) {
+	oldQuotas := quotas
+	defer func() { quotas = oldQuotas }()
+	c := &config.Config{
+		Quota:     config.Quota{MaxRowsPerDay: 1000},
+		QuotaFile: filepath.Join(t.TempDir(), "quotas.json"),
+	}
+	if err := initQuotas(c); err != nil {
+		t.Fatal(err)
+	}
+	quotas.record("client a", c.Quota, 400)
+	quotas.usage["client old"] = quotaUsage{Hour: time.Unix(0, 0).UTC(), Queries: 5, Day: time.Unix(0, 0).UTC(), Rows: 5}
+	quotas.flush()
+
+	if err := initQuotas(c); err != nil {
+		t.Fatal(err)
+	}
+	if u := quotas.usage["client a"]; u.Rows != 400 || u.Queries != 1 {
+		t.Errorf("expected the counters to survive a restart, got %+v", u)
+	}
+	if _, ok := quotas.usage["client old"]; ok {
+		t.Error("expected expired counters to be dropped")
+	}
+}
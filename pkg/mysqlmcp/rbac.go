@@ -0,0 +1,286 @@
+// pkg/mysqlmcp/rbac.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tool groups that role definitions can grant in place of individual tool names.
+const (
+	toolGroupCore     = "core"
+	toolGroupExtended = "extended"
+	toolGroupVector   = "vector"
+	// toolGroupVectorWrite holds the tools that change table data, so a role
+	// granting vector stays read-only when MYSQL_MCP_VECTOR_WRITE is on.
+	toolGroupVectorWrite = "vector_write"
+	toolGroupAll         = "*"
+)
+
+// toolGroups assigns every tool to the group a role grants it through. A tool
+// missing here is only callable by roles that name it explicitly or grant "*".
+var toolGroups = map[string]string{
+	"list_databases":     toolGroupCore,
+	"list_tables":        toolGroupCore,
+	"describe_table":     toolGroupCore,
+	"run_query":          toolGroupCore,
+	"validate_query":     toolGroupCore,
+	"explain_validation": toolGroupCore,
+	"fetch_cell":         toolGroupCore,
+	"ping":               toolGroupCore,
+	"server_info":        toolGroupCore,
+	"list_saved_queries": toolGroupCore,
+	"run_saved_query":    toolGroupCore,
+	"save_query":         toolGroupCore,
+	"list_reports":       toolGroupCore,
+	"run_report":         toolGroupCore,
+	"scheduled_results":  toolGroupCore,
+	"schema_changes":     toolGroupCore,
+	"list_bookmarks":     toolGroupCore,
+	"save_bookmark":      toolGroupCore,
+	"delete_bookmark":    toolGroupCore,
+	"list_connections":   toolGroupCore,
+	"use_connection":     toolGroupCore,
+	"pool_stats":         toolGroupCore,
+	"usage_stats":        toolGroupCore,
+
+	"run_cross_database_query": toolGroupCore,
+
+	"vector_search": toolGroupVector,
+	"vector_info":   toolGroupVector,
+	"vector_insert": toolGroupVectorWrite,
+	"vector_delete": toolGroupVectorWrite,
+
+	"process_list":             toolGroupExtended,
+	"kill_query":               toolGroupExtended,
+	"list_sessions":            toolGroupExtended,
+	"read_audit_log":           toolGroupExtended,
+	"slow_query_log":           toolGroupExtended,
+	"list_indexes":             toolGroupExtended,
+	"show_create_table":        toolGroupExtended,
+	"explain_query":            toolGroupExtended,
+	"check_saved_queries":      toolGroupExtended,
+	"check_partition_pruning":  toolGroupExtended,
+	"optimizer_trace":          toolGroupExtended,
+	"session_settings":         toolGroupExtended,
+	"set_session_setting":      toolGroupExtended,
+	"estimate_rows":            toolGroupExtended,
+	"table_counts":             toolGroupExtended,
+	"normalize_query":          toolGroupExtended,
+	"list_views":               toolGroupExtended,
+	"view_lineage":             toolGroupExtended,
+	"list_ghost_tables":        toolGroupExtended,
+	"list_summary_tables":      toolGroupExtended,
+	"data_freshness":           toolGroupExtended,
+	"list_triggers":            toolGroupExtended,
+	"list_procedures":          toolGroupExtended,
+	"list_functions":           toolGroupExtended,
+	"list_partitions":          toolGroupExtended,
+	"database_size":            toolGroupExtended,
+	"table_size":               toolGroupExtended,
+	"foreign_keys":             toolGroupExtended,
+	"table_constraints":        toolGroupExtended,
+	"spatial_info":             toolGroupExtended,
+	"schema_graph":             toolGroupExtended,
+	"generate_data_dictionary": toolGroupExtended,
+	"list_status":              toolGroupExtended,
+	"list_variables":           toolGroupExtended,
+	"health_report":            toolGroupExtended,
+	"resource_usage":           toolGroupExtended,
+	"binlog_status":            toolGroupExtended,
+	"show_grants":              toolGroupExtended,
+	"metrics_history":          toolGroupExtended,
+	"search_schema":            toolGroupExtended,
+	"find_columns":             toolGroupExtended,
+	"suggest_identifiers":      toolGroupExtended,
+	"fulltext_search":          toolGroupExtended,
+	"profile_column":           toolGroupExtended,
+	"pii_scan":                 toolGroupExtended,
+	"heatwave_status":          toolGroupExtended,
+	"heatwave_ml_predict":      toolGroupExtended,
+	"aurora_topology":          toolGroupExtended,
+	"schema_diff":              toolGroupExtended,
+}
+
+// errToolForbidden is wrapped by authorizeTool so HTTP handlers can answer 403.
+var errToolForbidden = i18nErrorf("tool not permitted")
+
+type callerRoleKey struct{}
+
+func rbacEnabled() bool {
+	return cfg != nil && len(cfg.Roles) > 0
+}
+
+// withCallerRole records the role resolved from an HTTP API key.
+func withCallerRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, callerRoleKey{}, role)
+}
+
+// callerRole resolves the caller's role: an HTTP API key first, then the MCP
+// client name sent at initialization, then the configured default role.
+func callerRole(ctx context.Context, req *mcp.CallToolRequest) string {
+	if role, ok := ctx.Value(callerRoleKey{}).(string); ok {
+		return role
+	}
+	if name := mcpClientName(req); name != "" {
+		if role, ok := cfg.ClientRoles[name]; ok {
+			return role
+		}
+	}
+	return cfg.DefaultRole
+}
+
+func mcpClientName(req *mcp.CallToolRequest) string {
+	if req == nil || req.Session == nil {
+		return ""
+	}
+	params := req.Session.InitializeParams()
+	if params == nil || params.ClientInfo == nil {
+		return ""
+	}
+	return params.ClientInfo.Name
+}
+
+// roleAllows reports whether role grants tool, either by name or through the
+// tool's group. Unknown roles grant nothing.
+func roleAllows(role, tool string) bool {
+	grants, ok := cfg.Roles[role]
+	if !ok {
+		return false
+	}
+	group := toolGroups[tool]
+	for _, g := range grants {
+		g = strings.TrimSpace(g)
+		if g == toolGroupAll || g == tool || (group != "" && g == group) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeTool enforces features.tools and the rbac roles for one tool call.
+// It is a no-op when neither is configured.
+func authorizeTool(ctx context.Context, req *mcp.CallToolRequest, tool string) error {
+	if toolFlagsEnabled() && !toolEnabled(tool) {
+		return i18nErrorf("%w: %s is turned off in features.tools", errToolForbidden, tool)
+	}
+	if !rbacEnabled() {
+		return nil
+	}
+	role := callerRole(ctx, req)
+	if role == "" {
+		return i18nErrorf("%w: %s requires a role (set an API key, a client mapping, or rbac.default_role)", errToolForbidden, tool)
+	}
+	if !roleAllows(role, tool) {
+		return i18nErrorf("%w: role %s may not call %s", errToolForbidden, role, tool)
+	}
+	return nil
+}
+
+// filterToolsByRole hides tools the MCP client's role cannot call from
+// tools/list. Calls are still checked by dispatchTool.
+func filterToolsByRole(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		res, err := next(ctx, method, req)
+		list, ok := res.(*mcp.ListToolsResult)
+		if err != nil || !ok || method != "tools/list" {
+			return res, err
+		}
+		role := cfg.DefaultRole
+		if ss, ok := req.GetSession().(*mcp.ServerSession); ok {
+			role = callerRole(ctx, &mcp.CallToolRequest{Session: ss})
+		}
+		visible := list.Tools[:0]
+		for _, t := range list.Tools {
+			if roleAllows(role, t.Name) {
+				visible = append(visible, t)
+			}
+		}
+		list.Tools = visible
+		return list, nil
+	}
+}
+
+// apiKeyFromRequest returns the key from "Authorization: Bearer <key>" or X-API-Key.
+func apiKeyFromRequest(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+// withAPIKeyRole resolves an HTTP API key to its role for authorizeTool.
+// Requests without a key fall back to the default role; an unknown key is
+// rejected with 401.
+func withAPIKeyRole(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !rbacEnabled() || r.Method == http.MethodOptions {
+			next(w, r)
+			return
+		}
+		key := apiKeyFromRequest(r)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		role, ok := cfg.APIKeys[key]
+		if !ok {
+			api.WriteError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		next(w, r.WithContext(withCallerRole(r.Context(), role)))
+	}
+}
+
+// writeToolError answers 400 for inputs that fail validation or name an
+// unknown table or column (with the suggested names as details), 403 for RBAC
+// denials, 429 when the caller's quota is used up, 503 when a concurrency
+// limit is saturated or the connection's circuit is open, and 500 otherwise.
+func writeToolError(w http.ResponseWriter, err error) {
+	if isInputError(err) {
+		api.WriteBadRequest(w, err.Error())
+		return
+	}
+	var unknown *UnknownIdentifierError
+	if errors.As(err, &unknown) {
+		api.WriteErrorDetails(w, http.StatusBadRequest, err.Error(), map[string]interface{}{
+			"kind":        unknown.Kind,
+			"name":        unknown.Name,
+			"suggestions": unknown.Suggestions,
+		})
+		return
+	}
+	if errors.Is(err, errToolForbidden) {
+		api.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if errors.Is(err, errToolUnsupported) {
+		api.WriteError(w, http.StatusNotImplemented, err.Error())
+		return
+	}
+	var open *CircuitOpenError
+	if errors.As(err, &open) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(open.RetryIn.Seconds()))))
+		api.WriteError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	var overQuota *QuotaExceededError
+	if errors.As(err, &overQuota) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(overQuota.RetryIn.Seconds()))))
+		api.WriteError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if errors.Is(err, errServerBusy) {
+		w.Header().Set("Retry-After", "1")
+		api.WriteError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	api.WriteInternalError(w, err.Error())
+}
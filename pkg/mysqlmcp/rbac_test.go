@@ -0,0 +1,211 @@
+// pkg/mysqlmcp/rbac_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withRBAC installs a config with analyst/dba/ml roles for the test.
+func withRBAC(t *testing.T, defaultRole string) func() {
+	t.Helper()
+	old := cfg
+	cfg = &config.Config{
+		Roles: map[string][]string{
+			"analyst": {"core"},
+			"dba":     {"core", "extended"},
+			"ml":      {"vector", "describe_table"},
+			"admin":   {"*"},
+		},
+		APIKeys:     map[string]string{"dba-key": "dba"},
+		ClientRoles: map[string]string{"cursor": "dba"},
+		DefaultRole: defaultRole,
+	}
+	return func() { cfg = old }
+}
+
+func TestToolGroupsCoverRegisteredTools(t *testing.T) {
+	src, err := os.ReadFile("main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := regexp.MustCompile(`Name:\s+"([a-z_]+)",`).FindAllStringSubmatch(string(src), -1)
+	if len(names) < 10 {
+		t.Fatalf("expected to find registered tools, got %d", len(names))
+	}
+	for _, m := range names {
+		if m[1] == "mysql-mcp-server" {
+			continue
+		}
+		if _, ok := toolGroups[m[1]]; !ok {
+			t.Errorf("tool %s has no rbac group", m[1])
+		}
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	defer withRBAC(t, "")()
+
+	tests := []struct {
+		role, tool string
+		want       bool
+	}{
+		{"analyst", "run_query", true},
+		{"analyst", "list_indexes", false},
+		{"analyst", "vector_search", false},
+		{"dba", "list_indexes", true},
+		{"ml", "vector_search", true},
+		{"ml", "vector_insert", false},
+		{"admin", "vector_delete", true},
+		{"ml", "describe_table", true},
+		{"ml", "run_query", false},
+		{"admin", "schema_diff", true},
+		{"admin", "not_a_tool", true},
+		{"dba", "not_a_tool", false},
+		{"missing", "ping", false},
+	}
+	for _, tt := range tests {
+		if got := roleAllows(tt.role, tt.tool); got != tt.want {
+			t.Errorf("roleAllows(%s, %s) = %v, want %v", tt.role, tt.tool, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorizeTool(t *testing.T) {
+	old := cfg
+	cfg = nil
+	if err := authorizeTool(context.Background(), nil, "schema_diff"); err != nil {
+		t.Errorf("expected no enforcement without roles, got %v", err)
+	}
+	cfg = old
+
+	defer withRBAC(t, "")()
+	if err := authorizeTool(context.Background(), nil, "ping"); !errors.Is(err, errToolForbidden) {
+		t.Errorf("expected denial without a role, got %v", err)
+	}
+	cfg.DefaultRole = "analyst"
+	if err := authorizeTool(context.Background(), nil, "ping"); err != nil {
+		t.Errorf("expected default role to allow ping, got %v", err)
+	}
+	if err := authorizeTool(context.Background(), nil, "list_indexes"); !errors.Is(err, errToolForbidden) {
+		t.Errorf("expected analyst to be denied list_indexes, got %v", err)
+	}
+	ctx := withCallerRole(context.Background(), "dba")
+	if err := authorizeTool(ctx, nil, "list_indexes"); err != nil {
+		t.Errorf("expected dba to be allowed list_indexes, got %v", err)
+	}
+
+	wrapped := wrapTool("list_indexes", func(ctx context.Context, req *mcp.CallToolRequest, in ListIndexesInput) (*mcp.CallToolResult, ListIndexesOutput, error) {
+		t.Error("handler should not run for a denied call")
+		return nil, ListIndexesOutput{}, nil
+	})
+	if _, _, err := wrapped(context.Background(), nil, ListIndexesInput{}); !errors.Is(err, errToolForbidden) {
+		t.Errorf("expected wrapper to deny, got %v", err)
+	}
+}
+
+func TestWithAPIKeyRole(t *testing.T) {
+	defer withRBAC(t, "analyst")()
+
+	var gotRole string
+	h := withAPIKeyRole(func(w http.ResponseWriter, r *http.Request) {
+		gotRole = callerRole(r.Context(), nil)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		status   int
+		wantRole string
+	}{
+		{"no key uses default", "", "", http.StatusOK, "analyst"},
+		{"bearer key", "Authorization", "Bearer dba-key", http.StatusOK, "dba"},
+		{"x-api-key", "X-API-Key", "dba-key", http.StatusOK, "dba"},
+		{"unknown key", "X-API-Key", "nope", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRole = ""
+			req := httptest.NewRequest(http.MethodGet, "/api/databases", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			h(w, req)
+			if w.Code != tt.status || gotRole != tt.wantRole {
+				t.Errorf("status=%d role=%q, want %d %q", w.Code, gotRole, tt.status, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestWriteToolErrorForbidden(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeToolError(w, fmt.Errorf("%w: role analyst may not call schema_diff", errToolForbidden))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	writeToolError(w, errors.New("boom"))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500, got %d", w.Code)
+	}
+}
+
+func TestRBACMCPClientIdentity(t *testing.T) {
+	defer withRBAC(t, "analyst")()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0"}, nil)
+	for _, name := range []string{"ping", "list_indexes"} {
+		mcp.AddTool(server, &mcp.Tool{Name: name}, wrapTool(name, func(ctx context.Context, req *mcp.CallToolRequest, in PingInput) (*mcp.CallToolResult, PingOutput, error) {
+			return nil, PingOutput{Success: true}, nil
+		}))
+	}
+	server.AddReceivingMiddleware(filterToolsByRole)
+
+	connect := func(clientName string) *mcp.ClientSession {
+		t.Helper()
+		st, ct := mcp.NewInMemoryTransports()
+		if _, err := server.Connect(context.Background(), st, nil); err != nil {
+			t.Fatal(err)
+		}
+		client := mcp.NewClient(&mcp.Implementation{Name: clientName, Version: "0"}, nil)
+		cs, err := client.Connect(context.Background(), ct, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		return cs
+	}
+
+	ctx := context.Background()
+	dba := connect("cursor")
+	tools, err := dba.ListTools(ctx, nil)
+	if err != nil || len(tools.Tools) != 2 {
+		t.Fatalf("expected dba client to see both tools: %+v, %v", tools, err)
+	}
+
+	analyst := connect("other-client")
+	tools, err = analyst.ListTools(ctx, nil)
+	if err != nil || len(tools.Tools) != 1 || tools.Tools[0].Name != "ping" {
+		t.Fatalf("expected analyst client to see only ping: %+v, %v", tools, err)
+	}
+	res, err := analyst.CallTool(ctx, &mcp.CallToolParams{Name: "list_indexes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsError {
+		t.Error("expected list_indexes call to be denied for analyst")
+	}
+}
@@ -0,0 +1,153 @@
+// pkg/mysqlmcp/readiness.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+)
+
+// readinessState tracks startup progress for /ready: the status of each
+// feature subsystem and whether startup finished.
+type readinessState struct {
+	mu         sync.RWMutex
+	subsystems map[string]string
+	started    bool
+}
+
+var readiness = &readinessState{subsystems: map[string]string{}}
+
+// recordSubsystem stores a subsystem's status ("ok", "disabled", or a
+// description of a degraded state) for the readiness report.
+func (r *readinessState) recordSubsystem(name, status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subsystems[name] = status
+}
+
+// markStarted is called once every subsystem is initialized and the server is
+// about to accept requests.
+func (r *readinessState) markStarted() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = true
+}
+
+func (r *readinessState) snapshot() (map[string]string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subsystems := make(map[string]string, len(r.subsystems))
+	for k, v := range r.subsystems {
+		subsystems[k] = v
+	}
+	return subsystems, r.started
+}
+
+// checkReadiness pings every connection within pingTimeout, opening
+// connect_on_demand connections that are not open yet; this is where their
+// reachability is checked instead of at startup. The server is ready when
+// startup completed and at least one connection answers.
+func checkReadiness(ctx context.Context, cm *ConnectionManager) ReadinessReport {
+	subsystems, started := readiness.snapshot()
+	report := ReadinessReport{
+		Started:     started,
+		Subsystems:  subsystems,
+		Connections: []ConnectionReadiness{},
+	}
+	if cm == nil {
+		return report
+	}
+
+	pools := cm.OpenAll()
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]ConnectionReadiness, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+			start := time.Now()
+			err := pools[name].PingContext(pctx)
+			results[i] = ConnectionReadiness{Name: name, OK: err == nil, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, name)
+	}
+	wg.Wait()
+
+	for _, c := range results {
+		report.Ready = report.Ready || c.OK
+	}
+	report.Connections = results
+	report.Ready = report.Ready && started
+	return report
+}
+
+// httpReady handles GET /ready: 200 when ready, 503 otherwise. Unlike /health
+// it checks the database connections.
+func httpReady(w http.ResponseWriter, r *http.Request) {
+	report := checkReadiness(r.Context(), connManager)
+	if !report.Ready {
+		api.WriteJSON(w, http.StatusServiceUnavailable, api.Response{Success: false, Data: report, Error: "not ready"})
+		return
+	}
+	api.WriteSuccess(w, report)
+}
+
+// handleHealthcheck implements --healthcheck for container probes and returns
+// the process exit code. When an HTTP listener is configured it asks the
+// running server's /ready endpoint; in stdio mode, where there is no listener,
+// it loads the configuration and runs the same checks in-process.
+func handleHealthcheck() int {
+	if err := loadRuntimeConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "healthcheck: config error: %v\n", err)
+		return 1
+	}
+	if cfg.HTTPMode || cfg.MetricsHTTP {
+		return probeReadyEndpoint(fmt.Sprintf("http://127.0.0.1:%d/ready", cfg.HTTPPort))
+	}
+
+	openConnections()
+	defer connManager.Close()
+	readiness.markStarted()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*pingTimeout)
+	defer cancel()
+	report := checkReadiness(ctx, connManager)
+	_ = json.NewEncoder(os.Stdout).Encode(report)
+	if !report.Ready {
+		return 1
+	}
+	return 0
+}
+
+// probeReadyEndpoint returns 0 when url answers 200.
+func probeReadyEndpoint(url string) int {
+	client := &http.Client{Timeout: 2*pingTimeout + time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "healthcheck: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "healthcheck: %s returned %s\n", url, resp.Status)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+// pkg/mysqlmcp/readiness_test.go
+package mysqlmcp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/api"
+)
+
+func withReadiness(t *testing.T, started bool) {
+	t.Helper()
+	old := readiness
+	readiness = &readinessState{subsystems: map[string]string{"audit_log": "disabled"}, started: started}
+	t.Cleanup(func() { readiness = old })
+}
+
+func readyResponse(t *testing.T) (int, ReadinessReport) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	httpReady(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	var body struct {
+		api.Response
+		Data ReadinessReport `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return w.Code, body.Data
+}
+
+func TestHTTPReady(t *testing.T) {
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+	withReadiness(t, true)
+
+	code, report := readyResponse(t)
+	if code != http.StatusOK || !report.Ready || len(report.Connections) != 1 || !report.Connections[0].OK {
+		t.Errorf("expected ready, got %d %+v", code, report)
+	}
+	if report.Subsystems["audit_log"] != "disabled" {
+		t.Errorf("expected subsystem statuses, got %+v", report.Subsystems)
+	}
+}
+
+func TestHTTPReadyNotReady(t *testing.T) {
+	withReadiness(t, false)
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+
+	code, report := readyResponse(t)
+	if code != http.StatusServiceUnavailable || report.Ready || report.Started {
+		t.Errorf("expected 503 before startup completes, got %d %+v", code, report)
+	}
+
+	mockDB, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer mockDB.Close()
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	connManager.connections["mock"] = mockDB
+	readiness.markStarted()
+
+	code, report = readyResponse(t)
+	if code != http.StatusServiceUnavailable || report.Ready || report.Connections[0].Error == "" {
+		t.Errorf("expected 503 when no connection answers, got %d %+v", code, report)
+	}
+}
@@ -0,0 +1,218 @@
+// pkg/mysqlmcp/replica.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// replicaLagInterval is how old least_lag measurements may get before a
+	// read triggers a refresh.
+	replicaLagInterval = 5 * time.Second
+	// maxPinnedSessions bounds the session -> replica map of pin_replica.
+	maxPinnedSessions = 1024
+)
+
+// replicaGroup routes the reads of a connection with replicas. Its members
+// are ordinary connections named "<group>/replicaN".
+type replicaGroup struct {
+	members []string // replica connection names, in config order
+	routing string   // config.ReplicaRouting* value
+	pin     bool
+	next    atomic.Uint64 // round-robin position
+
+	mu         sync.Mutex
+	lag        map[string]replicaLag // last least_lag measurement per member
+	measured   time.Time
+	refreshing bool
+	pinned     map[string]string // session key -> member
+}
+
+// replicaLag is one lag measurement; ok is false when the replica's
+// replication is stopped or its status cannot be read.
+type replicaLag struct {
+	seconds float64
+	ok      bool
+}
+
+// replicaName returns the connection name of the i-th (0-based) replica of group.
+func replicaName(group string, i int) string {
+	return fmt.Sprintf("%s/replica%d", group, i+1)
+}
+
+// addReplicasLocked opens the replicas of connCfg, which share its settings,
+// and registers them as its replica group. Callers must hold cm.mu.
+func (cm *ConnectionManager) addReplicasLocked(connCfg config.ConnectionConfig, cfg *config.Config) {
+	g := &replicaGroup{routing: connCfg.ReplicaRouting, pin: connCfg.PinReplica, pinned: map[string]string{}}
+	if g.routing == "" {
+		g.routing = config.DefaultReplicaRouting
+	}
+	for i, dsn := range connCfg.Replicas {
+		rc := connCfg
+		rc.Name = replicaName(connCfg.Name, i)
+		rc.DSN = dsn
+		rc.Replicas, rc.ReplicaRouting, rc.PinReplica = nil, "", false
+		rc.ReplicaOf = connCfg.Name
+		if err := cm.addLocked(rc, cfg); err != nil {
+			poolLog.Warn("failed to add replica", map[string]interface{}{"name": rc.Name, "error": err.Error()})
+			continue
+		}
+		poolLog.Info("replica added", map[string]interface{}{"name": rc.Name, "dsn": util.MaskDSN(dsn)})
+		g.members = append(g.members, rc.Name)
+	}
+	if cm.groups == nil {
+		cm.groups = make(map[string]*replicaGroup)
+	}
+	cm.groups[connCfg.Name] = g
+}
+
+// ReadDB returns the pool a read on the active connection should use and its
+// name: a replica when the active connection is a replica group with a usable
+// replica, otherwise the active connection itself. session keys pin_replica;
+// "" disables pinning for the call.
+func (cm *ConnectionManager) ReadDB(session string) (*sql.DB, string) {
+	cm.mu.RLock()
+	active := cm.activeConn
+	g := cm.groups[active]
+	cm.mu.RUnlock()
+
+	pools := make(map[string]*sql.DB)
+	if g != nil {
+		for _, m := range g.members {
+			if db := cm.pool(m); db != nil {
+				pools[m] = db
+			}
+		}
+	}
+	primary := cm.pool(active)
+
+	if len(pools) == 0 {
+		return primary, active
+	}
+	if m := g.pick(session, pools); m != "" {
+		return pools[m], m
+	}
+	return primary, active
+}
+
+// pick chooses the member for one read, or "" to read from the primary.
+func (g *replicaGroup) pick(session string, pools map[string]*sql.DB) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.routing == config.ReplicaRoutingLeastLag && time.Since(g.measured) > replicaLagInterval && !g.refreshing {
+		g.refreshing = true
+		go g.refreshLag(pools)
+	}
+	if g.pin && session != "" {
+		if m, ok := g.pinned[session]; ok && pools[m] != nil && g.usable(m) {
+			return m
+		}
+	}
+	m := g.choose(pools)
+	if g.pin && session != "" && m != "" {
+		if len(g.pinned) >= maxPinnedSessions {
+			g.pinned = map[string]string{}
+		}
+		g.pinned[session] = m
+	}
+	return m
+}
+
+// usable reports whether member may serve reads. Until the first least_lag
+// measurement every member is usable. Callers must hold g.mu.
+func (g *replicaGroup) usable(member string) bool {
+	if g.routing != config.ReplicaRoutingLeastLag || g.lag == nil {
+		return true
+	}
+	return g.lag[member].ok
+}
+
+// choose returns the next member by the group's routing, or "" when no member
+// is usable. Callers must hold g.mu.
+func (g *replicaGroup) choose(pools map[string]*sql.DB) string {
+	n := uint64(len(g.members))
+	start := g.next.Add(1) - 1
+	best := ""
+	for i := uint64(0); i < n; i++ {
+		m := g.members[(start+i)%n]
+		if pools[m] == nil || !g.usable(m) {
+			continue
+		}
+		if g.routing != config.ReplicaRoutingLeastLag || g.lag == nil {
+			return m
+		}
+		if best == "" || g.lag[m].seconds < g.lag[best].seconds {
+			best = m
+		}
+	}
+	return best
+}
+
+// refreshLag measures the replication lag of every member.
+func (g *replicaGroup) refreshLag(pools map[string]*sql.DB) {
+	lags := make(map[string]replicaLag, len(pools))
+	for name, db := range pools {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		raw, isReplica, err := readReplicaLag(ctx, db)
+		cancel()
+		var l replicaLag
+		switch {
+		case err != nil:
+			poolLog.Debug("replica lag unavailable", map[string]interface{}{"name": name, "error": err.Error()})
+		case !isReplica:
+			l.ok = true // not replicating: as fresh as its data gets
+		case raw.Valid:
+			if secs, perr := strconv.ParseFloat(raw.String, 64); perr == nil {
+				l = replicaLag{seconds: secs, ok: true}
+			}
+		}
+		poolLog.Debug("replica lag measured", map[string]interface{}{"name": name, "seconds": l.seconds, "ok": l.ok})
+		lags[name] = l
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lag = lags
+	g.measured = time.Now()
+	g.refreshing = false
+}
+
+type replicaSessionKey struct{}
+
+// withReplicaSession keys pin_replica to the MCP session of req. HTTP calls
+// have no session and are routed one by one.
+func withReplicaSession(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if req == nil || req.Session == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, replicaSessionKey{}, "mcp:"+req.Session.ID())
+}
+
+func replicaSessionFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	s, _ := ctx.Value(replicaSessionKey{}).(string)
+	return s
+}
+
+// getReadDB returns the pool for a read-only query tool: a replica of the
+// active connection when it has replicas, otherwise the active connection.
+func getReadDB(ctx context.Context) *sql.DB {
+	if connManager == nil {
+		panic("getReadDB called before connManager initialized")
+	}
+	db, _ := connManager.ReadDB(replicaSessionFrom(ctx))
+	return db
+}
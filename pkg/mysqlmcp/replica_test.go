@@ -0,0 +1,164 @@
+// pkg/mysqlmcp/replica_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// newReplicaGroupManager builds a manager whose active connection "main" has
+// the given replicas, each backed by sqlmock.
+func newReplicaGroupManager(t *testing.T, routing string, pin bool, replicas int) (*ConnectionManager, map[string]sqlmock.Sqlmock) {
+	t.Helper()
+	oldPingTimeout := pingTimeout
+	pingTimeout = time.Duration(config.DefaultPingTimeoutSecs) * time.Second
+	t.Cleanup(func() { pingTimeout = oldPingTimeout })
+	cm := NewConnectionManager()
+	mocks := map[string]sqlmock.Sqlmock{}
+	open := func(name string) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+		cm.connections[name] = db
+		mocks[name] = mock
+	}
+	open("main")
+	cm.activeConn = "main"
+	g := &replicaGroup{routing: routing, pin: pin, pinned: map[string]string{}}
+	for i := 0; i < replicas; i++ {
+		name := replicaName("main", i)
+		open(name)
+		g.members = append(g.members, name)
+	}
+	cm.groups["main"] = g
+	return cm, mocks
+}
+
+func readName(cm *ConnectionManager, session string) string {
+	_, name := cm.ReadDB(session)
+	return name
+}
+
+func TestReplicaRoundRobin(t *testing.T) {
+	cm, _ := newReplicaGroupManager(t, config.ReplicaRoutingRoundRobin, false, 2)
+	got := []string{readName(cm, "s"), readName(cm, "s"), readName(cm, "s")}
+	want := []string{"main/replica1", "main/replica2", "main/replica1"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reads went to %v, want %v", got, want)
+		}
+	}
+
+	cm.activeConn = "main/replica2"
+	if name := readName(cm, "s"); name != "main/replica2" {
+		t.Errorf("a connection without replicas should read from itself, got %s", name)
+	}
+}
+
+func TestReplicaPinning(t *testing.T) {
+	cm, _ := newReplicaGroupManager(t, config.ReplicaRoutingRoundRobin, true, 3)
+	first := readName(cm, "mcp:a")
+	for i := 0; i < 3; i++ {
+		if name := readName(cm, "mcp:a"); name != first {
+			t.Fatalf("pinned session moved from %s to %s", first, name)
+		}
+	}
+	if other := readName(cm, "mcp:b"); other == first {
+		t.Errorf("expected a new session to get the next replica, got %s again", other)
+	}
+	if a, b := readName(cm, ""), readName(cm, ""); a == b {
+		t.Errorf("calls without a session should not be pinned, got %s twice", a)
+	}
+}
+
+func TestReplicaLeastLag(t *testing.T) {
+	cm, mocks := newReplicaGroupManager(t, config.ReplicaRoutingLeastLag, false, 3)
+	g := cm.groups["main"]
+	pools := map[string]*sql.DB{}
+	for _, m := range g.members {
+		pools[m] = cm.connections[m]
+	}
+	expectLag := func(name string, lag interface{}) {
+		mocks[name].ExpectQuery("SHOW REPLICA STATUS").
+			WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_Running", "Seconds_Behind_Source"}).AddRow("Yes", lag))
+	}
+
+	expectLag("main/replica1", "30")
+	expectLag("main/replica2", "2")
+	expectLag("main/replica3", nil)
+	g.refreshLag(pools)
+	for i := 0; i < 3; i++ {
+		if name := readName(cm, ""); name != "main/replica2" {
+			t.Fatalf("expected the least lagging replica, got %s", name)
+		}
+	}
+
+	for _, m := range g.members {
+		expectLag(m, nil)
+	}
+	g.refreshLag(pools)
+	if name := readName(cm, ""); name != "main" {
+		t.Errorf("expected the primary when no replica is replicating, got %s", name)
+	}
+	for name, mock := range mocks {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestAddConnectionWithReplicas(t *testing.T) {
+	cm := NewConnectionManager()
+	defer cm.Close()
+	poolCfg := &config.Config{}
+	group := config.ConnectionConfig{Name: "demo", DSN: config.DemoDSN, Replicas: []string{config.DemoDSN, config.DemoDSN}}
+	if err := cm.AddConnectionWithPoolConfig(group, poolCfg); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if c, ok := cm.Config("demo/replica2"); !ok || c.ReplicaOf != "demo" || len(c.Replicas) != 0 {
+		t.Fatalf("expected a registered replica, got %+v (ok=%v)", c, ok)
+	}
+	if name := readName(cm, ""); name != "demo/replica1" {
+		t.Errorf("expected reads on a replica, got %s", name)
+	}
+
+	oldCM := connManager
+	connManager = cm
+	defer func() { connManager = oldCM }()
+	_, out, err := toolListConnections(context.Background(), nil, ListConnectionsInput{})
+	if err != nil {
+		t.Fatalf("list_connections: %v", err)
+	}
+	for _, c := range out.Connections {
+		switch c.Name {
+		case "demo":
+			if len(c.Replicas) != 2 || c.ReplicaRouting != config.ReplicaRoutingRoundRobin {
+				t.Errorf("unexpected group info: %+v", c)
+			}
+		case "demo/replica1", "demo/replica2":
+			if c.ReplicaOf != "demo" {
+				t.Errorf("unexpected replica info: %+v", c)
+			}
+		default:
+			t.Errorf("unexpected connection %s", c.Name)
+		}
+	}
+
+	// Replacing the group closes and drops its old replicas.
+	if err := cm.AddConnectionWithPoolConfig(config.ConnectionConfig{Name: "demo", DSN: config.DemoDSN}, poolCfg); err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	if _, ok := cm.Config("demo/replica1"); ok {
+		t.Error("expected the old replicas to be removed")
+	}
+	if name := readName(cm, ""); name != "demo" {
+		t.Errorf("expected reads on the primary without replicas, got %s", name)
+	}
+}
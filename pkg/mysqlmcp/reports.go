@@ -0,0 +1,426 @@
+// pkg/mysqlmcp/reports.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/dbretry"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// report is a validated report template.
+type report struct {
+	config.Report
+	vars map[string]config.SavedQueryParam
+}
+
+// reports holds the config file's report templates, keyed by name.
+var reports = map[string]*report{}
+
+// loadReports validates the config file's reports. Saved queries must be
+// loaded first so saved_query sections can be checked.
+func loadReports(defs []config.Report) error {
+	loaded := make(map[string]*report, len(defs))
+	for _, def := range defs {
+		r, err := compileReport(def)
+		if err != nil {
+			return err
+		}
+		loaded[r.Name] = r
+	}
+	reports = loaded
+	return nil
+}
+
+func compileReport(def config.Report) (*report, error) {
+	def.Name = strings.TrimSpace(def.Name)
+	def.Database = strings.TrimSpace(def.Database)
+	if !savedQueryNamePattern.MatchString(def.Name) {
+		return nil, fmt.Errorf("invalid report name %q (letters, digits, _ . -; max 64 characters)", def.Name)
+	}
+	if len(def.Sections) == 0 {
+		return nil, fmt.Errorf("report %s: at least one section is required", def.Name)
+	}
+
+	r := &report{Report: def, vars: make(map[string]config.SavedQueryParam, len(def.Variables))}
+	r.Variables = nil
+	for _, v := range def.Variables {
+		v.Name = strings.TrimSpace(v.Name)
+		v.Type = strings.ToLower(strings.TrimSpace(v.Type))
+		if v.Type == "" {
+			v.Type = "string"
+		}
+		switch v.Type {
+		case "string", "int", "number", "bool", "date", "identifier":
+		default:
+			return nil, fmt.Errorf("report %s: variable %s has unsupported type %q", def.Name, v.Name, v.Type)
+		}
+		if v.Name == "" || r.vars[v.Name].Name != "" {
+			return nil, fmt.Errorf("report %s: variable names must be unique and non-empty", def.Name)
+		}
+		if v.Default != nil {
+			if _, err := convertReportVariable(v, v.Default); err != nil {
+				return nil, fmt.Errorf("report %s: default for %s: %w", def.Name, v.Name, err)
+			}
+		}
+		r.vars[v.Name] = v
+		r.Variables = append(r.Variables, v)
+	}
+
+	// Render every inline section with sample values so template and SQL
+	// errors surface at startup instead of on first use.
+	samples := make(map[string]interface{}, len(r.vars))
+	for name, v := range r.vars {
+		switch v.Type {
+		case "identifier":
+			samples[name] = "sample"
+		case "int":
+			samples[name] = int64(1)
+		case "number":
+			samples[name] = float64(1)
+		}
+	}
+
+	seen := make(map[string]bool, len(def.Sections))
+	r.Sections = nil
+	for _, s := range def.Sections {
+		s.Name = strings.TrimSpace(s.Name)
+		s.SQL = strings.TrimSpace(s.SQL)
+		s.SavedQuery = strings.TrimSpace(s.SavedQuery)
+		if s.Name == "" || seen[s.Name] {
+			return nil, fmt.Errorf("report %s: section names must be unique and non-empty", def.Name)
+		}
+		seen[s.Name] = true
+
+		switch {
+		case s.SQL != "" && s.SavedQuery != "":
+			return nil, fmt.Errorf("report %s section %s: set sql or saved_query, not both", def.Name, s.Name)
+		case s.SavedQuery != "":
+			q, ok := savedQueries.get(s.SavedQuery)
+			if !ok {
+				return nil, fmt.Errorf("report %s section %s: saved query %s not found", def.Name, s.Name, s.SavedQuery)
+			}
+			for _, p := range q.Params {
+				if _, ok := r.vars[p.Name]; !ok && p.Required && p.Default == nil {
+					return nil, fmt.Errorf("report %s section %s: saved query parameter %s has no matching report variable", def.Name, s.Name, p.Name)
+				}
+			}
+		case s.SQL != "":
+			rendered, err := renderReportSQL(s.SQL, r.vars, samples)
+			if err != nil {
+				return nil, fmt.Errorf("report %s section %s: %w", def.Name, s.Name, err)
+			}
+			bound, names := util.BindNamedParams(rendered)
+			for _, name := range names {
+				if _, ok := r.vars[name]; !ok {
+					return nil, fmt.Errorf("report %s section %s: :%s is not a declared variable", def.Name, s.Name, name)
+				}
+			}
+			if err := validateSQL(bound); err != nil {
+				return nil, fmt.Errorf("report %s section %s: %w", def.Name, s.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("report %s section %s: sql or saved_query is required", def.Name, s.Name)
+		}
+		r.Sections = append(r.Sections, s)
+	}
+	return r, nil
+}
+
+// convertReportVariable coerces a variable value like a saved query
+// parameter; identifier variables, which only reports have, must be valid
+// table or column names.
+func convertReportVariable(v config.SavedQueryParam, val interface{}) (interface{}, error) {
+	if v.Type != "identifier" {
+		return convertSavedQueryParam(v, val)
+	}
+	x, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("unsupported value %v for type %s", val, v.Type)
+	}
+	if _, err := util.QuoteIdent(x); err != nil {
+		return nil, fmt.Errorf("invalid identifier %q: %w", x, err)
+	}
+	return x, nil
+}
+
+// renderReportSQL expands {{name}} references. Only identifier (backtick-quoted
+// after validation), int and number variables may be substituted into the SQL
+// text; every other value must be bound with :name.
+func renderReportSQL(sqlText string, vars map[string]config.SavedQueryParam, values map[string]interface{}) (string, error) {
+	var b strings.Builder
+	rest := sqlText
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			if strings.Contains(rest, "}}") {
+				return "", fmt.Errorf("unmatched }} in template")
+			}
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return "", fmt.Errorf("unclosed {{ in template")
+		}
+		b.WriteString(rest[:start])
+		name := strings.TrimSpace(rest[start+2 : start+end])
+		rest = rest[start+end+2:]
+
+		v, ok := vars[name]
+		if !ok {
+			return "", fmt.Errorf("{{%s}} is not a declared variable", name)
+		}
+		switch v.Type {
+		case "identifier":
+			s, _ := values[name].(string)
+			quoted, err := util.QuoteIdent(s)
+			if err != nil {
+				return "", fmt.Errorf("variable %s: %w", name, err)
+			}
+			b.WriteString(quoted)
+		case "int":
+			n, ok := values[name].(int64)
+			if !ok {
+				return "", fmt.Errorf("variable %s has no value", name)
+			}
+			b.WriteString(strconv.FormatInt(n, 10))
+		case "number":
+			f, ok := values[name].(float64)
+			if !ok {
+				return "", fmt.Errorf("variable %s has no value", name)
+			}
+			b.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+		default:
+			return "", fmt.Errorf("variable %s of type %s cannot be substituted with {{}}; bind it with :%s", name, v.Type, name)
+		}
+	}
+}
+
+// resolveVariables converts caller values and defaults to typed values.
+func (r *report) resolveVariables(values map[string]interface{}) (map[string]interface{}, error) {
+	for name := range values {
+		if _, ok := r.vars[name]; !ok {
+			return nil, fmt.Errorf("unknown variable %s for report %s", name, r.Name)
+		}
+	}
+	resolved := make(map[string]interface{}, len(r.vars))
+	for name, v := range r.vars {
+		val, ok := values[name]
+		if !ok || val == nil {
+			if v.Default == nil {
+				if v.Required {
+					return nil, fmt.Errorf("variable %s is required", name)
+				}
+				resolved[name] = nil
+				continue
+			}
+			val = v.Default
+		}
+		converted, err := convertReportVariable(v, val)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", name, err)
+		}
+		resolved[name] = converted
+	}
+	return resolved, nil
+}
+
+// sectionQuery returns the SQL and arguments for one section.
+func (r *report) sectionQuery(s config.ReportSection, values map[string]interface{}) (string, []interface{}, error) {
+	if s.SavedQuery != "" {
+		q, ok := savedQueries.get(s.SavedQuery)
+		if !ok {
+			return "", nil, fmt.Errorf("saved query %s not found", s.SavedQuery)
+		}
+		params := make(map[string]interface{})
+		for _, p := range q.Params {
+			if v, ok := values[p.Name]; ok && v != nil {
+				params[p.Name] = v
+			}
+		}
+		args, err := q.bindArgs(params)
+		return q.boundSQL, args, err
+	}
+
+	rendered, err := renderReportSQL(s.SQL, r.vars, values)
+	if err != nil {
+		return "", nil, err
+	}
+	bound, names := util.BindNamedParams(rendered)
+	if err := validateSQL(bound); err != nil {
+		return "", nil, err
+	}
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		args[i] = values[name]
+	}
+	return bound, args, nil
+}
+
+func (r *report) info() ReportInfo {
+	info := ReportInfo{
+		Name:        r.Name,
+		Description: r.Description,
+		Database:    r.Database,
+		Variables:   make([]SavedQueryParamInfo, 0, len(r.Variables)),
+		Sections:    make([]string, 0, len(r.Sections)),
+	}
+	for _, v := range r.Variables {
+		info.Variables = append(info.Variables, SavedQueryParamInfo(v))
+	}
+	for _, s := range r.Sections {
+		info.Sections = append(info.Sections, s.Name)
+	}
+	return info
+}
+
+func toolListReports(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListReportsInput,
+) (*mcp.CallToolResult, ListReportsOutput, error) {
+	out := ListReportsOutput{Reports: []ReportInfo{}}
+	for _, r := range reports {
+		if r.Database != "" && accessControlEnabled() && !databaseAllowed(r.Database) {
+			continue
+		}
+		out.Reports = append(out.Reports, r.info())
+	}
+	sort.Slice(out.Reports, func(i, j int) bool { return out.Reports[i].Name < out.Reports[j].Name })
+	return nil, out, nil
+}
+
+func toolRunReport(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input RunReportInput,
+) (*mcp.CallToolResult, RunReportOutput, error) {
+	timer := NewQueryTimer(ctx, "run_report")
+
+	r, ok := reports[strings.TrimSpace(input.Name)]
+	if !ok {
+		return nil, RunReportOutput{}, fmt.Errorf("report not found: %s", input.Name)
+	}
+
+	database := r.Database
+	if requested := strings.TrimSpace(input.Database); requested != "" {
+		if database != "" && !strings.EqualFold(requested, database) {
+			return nil, RunReportOutput{}, fmt.Errorf("report %s runs in database %s", r.Name, database)
+		}
+		database = requested
+	}
+	if accessControlEnabled() && database == "" {
+		return nil, RunReportOutput{}, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+	}
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, RunReportOutput{}, err
+		}
+	}
+
+	values, err := r.resolveVariables(input.Variables)
+	if err != nil {
+		return nil, RunReportOutput{}, err
+	}
+
+	out := RunReportOutput{
+		Name:        r.Name,
+		Description: r.Description,
+		Variables:   values,
+		Sections:    make([]ReportSectionResult, 0, len(r.Sections)),
+	}
+	totalRows := 0
+	db := getReadDB(ctx)
+	if input.Snapshot {
+		session, err := OpenSession(ctx, db, true)
+		if err != nil {
+			return nil, RunReportOutput{}, err
+		}
+		defer session.Close()
+		ctx = withSession(ctx, session)
+		out.Snapshot = true
+	}
+	for _, s := range r.Sections {
+		result := ReportSectionResult{Name: s.Name, Columns: []string{}, Rows: [][]interface{}{}}
+
+		sqlText, args, err := r.sectionQuery(s, values)
+		if err == nil {
+			err = requireReferencedSchemasInQuery(sqlText)
+		}
+		if err == nil {
+			sqlText, err = applyRowPolicies(sqlText, database)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			out.Sections = append(out.Sections, result)
+			continue
+		}
+
+		limit := defaultRowLimit(database)
+		if s.MaxRows > 0 && s.MaxRows < limit {
+			limit = s.MaxRows
+		}
+		if input.MaxRows != nil && *input.MaxRows > 0 && *input.MaxRows < limit {
+			limit = *input.MaxRows
+		}
+		if cfg == nil || cfg.InjectLimit {
+			sqlText = util.InjectLimit(sqlText, limit)
+		}
+
+		sectionCtx, cancel := context.WithTimeout(ctx, queryTimeout)
+		var res QueryResult
+		var stats dbretry.Stats
+		if out.Snapshot {
+			// A retry on another connection would leave the snapshot.
+			res, err = runQueryScan(sectionCtx, db, sqlText, database, limit, false, 0, binaryOutput, args...)
+		} else {
+			stats, err = dbretry.DoWithStats(sectionCtx, db, dbRetryCfg, pingTimeout, func() error {
+				var e error
+				res, e = runQueryScan(sectionCtx, db, sqlText, database, limit, false, 0, binaryOutput, args...)
+				return e
+			})
+		}
+		cancel()
+		result.Retries, result.RetryReason = retryMetadata(ctx, "run_report", stats)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, RunReportOutput{}, err
+			}
+			serverLog.Warn("report section failed", map[string]interface{}{
+				"report":  r.Name,
+				"section": s.Name,
+				"error":   err.Error(),
+			})
+			result.Error = err.Error()
+			out.Sections = append(out.Sections, result)
+			continue
+		}
+		if cfg != nil && len(cfg.MaskColumns) > 0 {
+			maskResults(res.Columns, res.Rows, cfg.MaskColumns)
+		}
+		pseudonymizeResults(ctx, res.Columns, res.Rows)
+		result.Columns, result.Rows, result.Truncated = res.Columns, res.Rows, res.Truncated
+		totalRows += len(res.Rows)
+		out.Sections = append(out.Sections, result)
+	}
+
+	timer.LogSuccess(totalRows, "", nil, nil)
+	if auditLogger != nil {
+		auditLogger.LogContext(ctx, &AuditEntry{
+			Tool:       "run_report",
+			Database:   database,
+			Query:      "report: " + r.Name,
+			DurationMs: timer.ElapsedMs(),
+			RowCount:   totalRows,
+			Success:    true,
+		})
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,200 @@
+// pkg/mysqlmcp/reports_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withReports loads report templates for the test and restores the previous set.
+func withReports(t *testing.T, defs ...config.Report) func() {
+	t.Helper()
+	old := reports
+	if err := loadReports(defs); err != nil {
+		t.Fatalf("loadReports failed: %v", err)
+	}
+	return func() { reports = old }
+}
+
+func TestRenderReportSQL(t *testing.T) {
+	vars := map[string]config.SavedQueryParam{
+		"tbl":   {Name: "tbl", Type: "identifier"},
+		"top":   {Name: "top", Type: "int"},
+		"ratio": {Name: "ratio", Type: "number"},
+		"day":   {Name: "day", Type: "date"},
+	}
+	values := map[string]interface{}{"tbl": "orders", "top": int64(5), "ratio": 0.25, "day": "2026-01-31"}
+
+	got, err := renderReportSQL("SELECT * FROM {{ tbl }} WHERE r > {{ratio}} AND d = :day LIMIT {{top}}", vars, values)
+	if err != nil {
+		t.Fatalf("renderReportSQL failed: %v", err)
+	}
+	if got != "SELECT * FROM `orders` WHERE r > 0.25 AND d = :day LIMIT 5" {
+		t.Errorf("unexpected render: %s", got)
+	}
+
+	for _, tmpl := range []string{
+		"SELECT {{day}}",
+		"SELECT {{missing}}",
+		"SELECT {{top",
+		"SELECT top}}",
+	} {
+		if _, err := renderReportSQL(tmpl, vars, values); err == nil {
+			t.Errorf("expected error for %q", tmpl)
+		}
+	}
+
+	values["tbl"] = "orders`; DROP TABLE x; --"
+	if _, err := renderReportSQL("SELECT * FROM {{tbl}}", vars, values); err == nil {
+		t.Error("expected invalid identifier to be rejected")
+	}
+}
+
+func TestCompileReport(t *testing.T) {
+	defer withSavedQueries(t, config.SavedQuery{
+		Name:   "top_customers",
+		SQL:    "SELECT id FROM customers WHERE since >= :since",
+		Params: []config.SavedQueryParam{{Name: "since", Type: "date", Required: true}},
+	})()
+
+	valid := config.Report{
+		Name:      "daily_sales",
+		Variables: []config.SavedQueryParam{{Name: "since", Type: "date", Required: true}},
+		Sections: []config.ReportSection{
+			{Name: "totals", SQL: "SELECT SUM(total) FROM orders WHERE created_at >= :since"},
+			{Name: "customers", SavedQuery: "top_customers"},
+		},
+	}
+	if _, err := compileReport(valid); err != nil {
+		t.Fatalf("compileReport failed: %v", err)
+	}
+
+	invalid := []config.Report{
+		{Name: "empty"},
+		{Name: "undeclared", Sections: []config.ReportSection{{Name: "a", SQL: "SELECT :x"}}},
+		{Name: "writes", Sections: []config.ReportSection{{Name: "a", SQL: "DELETE FROM orders"}}},
+		{Name: "dupe", Sections: []config.ReportSection{{Name: "a", SQL: "SELECT 1"}, {Name: "a", SQL: "SELECT 2"}}},
+		{Name: "both", Sections: []config.ReportSection{{Name: "a", SQL: "SELECT 1", SavedQuery: "top_customers"}}},
+		{Name: "missing_query", Sections: []config.ReportSection{{Name: "a", SavedQuery: "nope"}}},
+		{Name: "unbound_param", Sections: []config.ReportSection{{Name: "a", SavedQuery: "top_customers"}}},
+		{Name: "string_inline", Variables: []config.SavedQueryParam{{Name: "s"}},
+			Sections: []config.ReportSection{{Name: "a", SQL: "SELECT {{s}}"}}},
+	}
+	for _, r := range invalid {
+		if _, err := compileReport(r); err == nil {
+			t.Errorf("expected report %s to be rejected", r.Name)
+		}
+	}
+}
+
+func TestToolRunReport(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	defer withReports(t, config.Report{
+		Name:     "daily_sales",
+		Database: "shop",
+		Variables: []config.SavedQueryParam{
+			{Name: "day", Type: "date", Required: true},
+			{Name: "tbl", Type: "identifier", Default: "orders"},
+			{Name: "top", Type: "int", Default: 3},
+		},
+		Sections: []config.ReportSection{
+			{Name: "totals", SQL: "SELECT COUNT(*) FROM {{tbl}} WHERE day = :day"},
+			{Name: "top_orders", SQL: "SELECT id FROM {{tbl}} WHERE day = :day ORDER BY total DESC", MaxRows: 10},
+		},
+	})()
+
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `orders` WHERE day = \\? LIMIT 1000").
+		WithArgs("2026-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(12))
+	expectRestoreDatabase(mock)
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM `orders` WHERE day = \\? ORDER BY total DESC LIMIT 10").
+		WithArgs("2026-03-01").
+		WillReturnError(errors.New("Table 'shop.orders' doesn't exist"))
+	expectRestoreDatabase(mock)
+
+	_, out, err := toolRunReport(context.Background(), &mcp.CallToolRequest{}, RunReportInput{
+		Name:      "daily_sales",
+		Variables: map[string]interface{}{"day": "2026-03-01"},
+	})
+	if err != nil {
+		t.Fatalf("toolRunReport failed: %v", err)
+	}
+	if len(out.Sections) != 2 || out.Sections[0].Name != "totals" || len(out.Sections[0].Rows) != 1 {
+		t.Fatalf("unexpected sections: %+v", out.Sections)
+	}
+	if out.Sections[1].Error == "" {
+		t.Error("expected the failing section to report its error")
+	}
+	if out.Variables["top"] != int64(3) {
+		t.Errorf("expected default to be resolved, got %#v", out.Variables["top"])
+	}
+
+	for _, vars := range []map[string]interface{}{
+		{},
+		{"day": "March 1st"},
+		{"day": "2026-03-01", "tbl": "bad`name"},
+		{"day": "2026-03-01", "extra": 1},
+	} {
+		if _, _, err := toolRunReport(context.Background(), &mcp.CallToolRequest{}, RunReportInput{Name: "daily_sales", Variables: vars}); err == nil {
+			t.Errorf("expected variables %v to be rejected", vars)
+		}
+	}
+	if _, _, err := toolRunReport(context.Background(), &mcp.CallToolRequest{}, RunReportInput{Name: "missing"}); err == nil {
+		t.Error("expected error for unknown report")
+	}
+
+	_, list, _ := toolListReports(context.Background(), &mcp.CallToolRequest{}, ListReportsInput{})
+	if len(list.Reports) != 1 || strings.Join(list.Reports[0].Sections, ",") != "totals,top_orders" {
+		t.Errorf("unexpected list: %+v", list.Reports)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolRunReportSnapshot(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	defer withReports(t, config.Report{
+		Name: "stock",
+		Sections: []config.ReportSection{
+			{Name: "orders", SQL: "SELECT COUNT(*) FROM orders"},
+			{Name: "items", SQL: "SELECT COUNT(*) FROM order_items"},
+		},
+	})()
+
+	mock.ExpectExec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM orders").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(4))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM order_items").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(9))
+	mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, out, err := toolRunReport(context.Background(), &mcp.CallToolRequest{}, RunReportInput{Name: "stock", Snapshot: true})
+	if err != nil {
+		t.Fatalf("toolRunReport failed: %v", err)
+	}
+	if !out.Snapshot || len(out.Sections) != 2 || out.Sections[1].Error != "" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+
+	// A server that cannot start the snapshot fails the call instead of
+	// silently running without one.
+	mock.ExpectExec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY").WillReturnError(errors.New("syntax error"))
+	if _, _, err := toolRunReport(context.Background(), &mcp.CallToolRequest{}, RunReportInput{Name: "stock", Snapshot: true}); err == nil || !strings.Contains(err.Error(), "failed to start snapshot") {
+		t.Errorf("expected a snapshot error, got %v", err)
+	}
+}
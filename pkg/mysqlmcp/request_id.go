@@ -0,0 +1,141 @@
+// pkg/mysqlmcp/request_id.go
+package mysqlmcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// requestIDHeader carries the request ID on HTTP requests and responses.
+const requestIDHeader = "X-Request-ID"
+
+// requestIDMetaKey is the MCP _meta key a client can set to correlate a tool
+// call with its own logs.
+const requestIDMetaKey = "request_id"
+
+type requestIDKey struct{}
+
+type requestSourceKey struct{}
+
+// Transports a tool call can come in on, recorded as the audit entry source.
+// Scheduled saved query runs have their own source.
+const (
+	requestSourceMCP       = "mcp"
+	requestSourceHTTP      = "http"
+	requestSourceScheduler = "scheduler"
+)
+
+// newRequestID returns a random 16-byte hex ID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID accepts caller-supplied IDs of up to 128 characters from
+// [A-Za-z0-9._:-], so they are safe to echo into logs and headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.' || c == '_' || c == ':' || c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// requestIDFrom returns the request ID in ctx, or "" outside a tool call.
+func requestIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func withRequestSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, requestSourceKey{}, source)
+}
+
+// requestSourceFrom returns the transport set by the HTTP layer, or mcp.
+func requestSourceFrom(ctx context.Context) string {
+	if ctx != nil {
+		if s, ok := ctx.Value(requestSourceKey{}).(string); ok {
+			return s
+		}
+	}
+	return requestSourceMCP
+}
+
+// ensureRequestID keeps an ID already set by the HTTP layer, otherwise uses a
+// valid _meta.request_id from the MCP call, otherwise generates one.
+func ensureRequestID(ctx context.Context, req *mcp.CallToolRequest) context.Context {
+	if requestIDFrom(ctx) != "" {
+		return ctx
+	}
+	if req != nil && req.Params != nil {
+		if id, ok := req.Params.Meta[requestIDMetaKey].(string); ok && validRequestID(id) {
+			return withRequestID(ctx, id)
+		}
+	}
+	return withRequestID(ctx, newRequestID())
+}
+
+// withRequestIDError appends the request ID to a tool error so a client-visible
+// failure can be matched to the server and audit logs.
+func withRequestIDError(ctx context.Context, err error) error {
+	id := requestIDFrom(ctx)
+	if err == nil || id == "" {
+		return err
+	}
+	return fmt.Errorf("%w (request_id: %s)", err, id)
+}
+
+// withHTTPRequestID assigns every HTTP request an ID, taken from a valid
+// X-Request-ID header or generated, echoes it in the response header and
+// includes it in the request log line.
+func withHTTPRequestID(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		r = r.WithContext(withRequestSource(withRequestID(r.Context(), id), requestSourceHTTP))
+		api.WithLogging(httpRequestLogger(id))(next)(w, r)
+	}
+}
+
+// httpRequestLogger logs HTTP requests with their request ID.
+func httpRequestLogger(requestID string) api.Logger {
+	return func(method, path string, status int, duration time.Duration) {
+		fields := map[string]interface{}{
+			"method":      method,
+			"path":        path,
+			"status":      status,
+			"duration_ms": duration.Milliseconds(),
+		}
+		if requestID != "" {
+			fields["request_id"] = requestID
+		}
+		httpLog.Info("http request", fields)
+	}
+}
@@ -0,0 +1,90 @@
+// pkg/mysqlmcp/request_id_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestValidRequestID(t *testing.T) {
+	for _, id := range []string{"abc", "req-1.2:3_x", newRequestID()} {
+		if !validRequestID(id) {
+			t.Errorf("expected %q to be valid", id)
+		}
+	}
+	for _, id := range []string{"", "has space", "line\nbreak", "semi;colon", strings.Repeat("a", 129)} {
+		if validRequestID(id) {
+			t.Errorf("expected %q to be rejected", id)
+		}
+	}
+	if len(newRequestID()) != 32 {
+		t.Error("expected 32 hex characters")
+	}
+}
+
+func TestEnsureRequestID(t *testing.T) {
+	ctx := ensureRequestID(context.Background(), nil)
+	if !validRequestID(requestIDFrom(ctx)) {
+		t.Fatalf("expected a generated ID, got %q", requestIDFrom(ctx))
+	}
+
+	preset := withRequestID(context.Background(), "from-http")
+	if got := requestIDFrom(ensureRequestID(preset, nil)); got != "from-http" {
+		t.Errorf("expected HTTP ID to be kept, got %q", got)
+	}
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Meta: mcp.Meta{"request_id": "agent-step-7"}}}
+	if got := requestIDFrom(ensureRequestID(context.Background(), req)); got != "agent-step-7" {
+		t.Errorf("expected _meta request_id, got %q", got)
+	}
+	req.Params.Meta["request_id"] = "bad id"
+	if got := requestIDFrom(ensureRequestID(context.Background(), req)); got == "bad id" {
+		t.Error("expected invalid _meta request_id to be replaced")
+	}
+}
+
+func TestDispatchToolRequestID(t *testing.T) {
+	var seen string
+	baseErr := errors.New("boom")
+	h := dispatchTool("ping", func(ctx context.Context, req *mcp.CallToolRequest, in PingInput) (*mcp.CallToolResult, PingOutput, error) {
+		seen = requestIDFrom(ctx)
+		return nil, PingOutput{}, baseErr
+	})
+
+	_, _, err := h(withRequestID(context.Background(), "corr-1"), nil, PingInput{})
+	if seen != "corr-1" {
+		t.Errorf("handler saw request ID %q", seen)
+	}
+	if !errors.Is(err, baseErr) || !strings.Contains(err.Error(), "request_id: corr-1") {
+		t.Errorf("expected error tagged with request ID, got %v", err)
+	}
+}
+
+func TestWithHTTPRequestID(t *testing.T) {
+	var seen string
+	h := withHTTPRequestID(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestIDFrom(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	req.Header.Set(requestIDHeader, "client-42")
+	w := httptest.NewRecorder()
+	h(w, req)
+	if seen != "client-42" || w.Header().Get(requestIDHeader) != "client-42" {
+		t.Errorf("expected incoming ID to propagate, got ctx=%q header=%q", seen, w.Header().Get(requestIDHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	req.Header.Set(requestIDHeader, "not valid!")
+	w = httptest.NewRecorder()
+	h(w, req)
+	if seen == "not valid!" || !validRequestID(seen) || w.Header().Get(requestIDHeader) != seen {
+		t.Errorf("expected a generated ID, got ctx=%q header=%q", seen, w.Header().Get(requestIDHeader))
+	}
+}
@@ -0,0 +1,97 @@
+// pkg/mysqlmcp/result_size.go
+package mysqlmcp
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// cellBytes approximates the JSON payload size of one normalized cell value.
+func cellBytes(v interface{}) int64 {
+	switch x := v.(type) {
+	case nil:
+		return 4
+	case string:
+		return int64(len(x))
+	case []byte:
+		return int64(len(x))
+	case bool:
+		return 5
+	case int64, int32, int, uint64, uint32, float64, float32:
+		return 8
+	case time.Time:
+		return 25
+	default:
+		return int64(len(fmt.Sprint(x)))
+	}
+}
+
+func rowBytes(row []interface{}) int64 {
+	var n int64
+	for _, v := range row {
+		n += cellBytes(v)
+	}
+	return n
+}
+
+// truncationMarker is appended to a cell cut by the result byte limit.
+func truncationMarker(removed int) string {
+	return fmt.Sprintf("…[truncated %d bytes]", removed)
+}
+
+// truncateRowToFit shortens the largest string cells of row, longest first,
+// until the row fits in budget bytes. It returns the indexes of the cells cut
+// and whether the row now fits; cut cells end with truncationMarker.
+func truncateRowToFit(row []interface{}, budget int64) ([]int, bool) {
+	size := rowBytes(row)
+	if size <= budget {
+		return nil, true
+	}
+	idx := make([]int, 0, len(row))
+	for i, v := range row {
+		if _, ok := v.(string); ok {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return len(row[idx[a]].(string)) > len(row[idx[b]].(string))
+	})
+
+	var cut []int
+	for _, i := range idx {
+		if size <= budget {
+			break
+		}
+		s := row[i].(string)
+		excess := size - budget
+		// Leave room for the marker itself.
+		keep := int64(len(s)) - excess - int64(len(truncationMarker(len(s))))
+		if keep < 0 {
+			keep = 0
+		}
+		prefix := trimPartialRune(s[:keep])
+		row[i] = prefix + truncationMarker(len(s)-len(prefix))
+		size += int64(len(row[i].(string))) - int64(len(s))
+		cut = append(cut, i)
+	}
+	return cut, size <= budget
+}
+
+// trimPartialRune drops a multi-byte rune split by the cut at the end of s.
+// Invalid bytes earlier in s, as in binary data, are left alone.
+func trimPartialRune(s string) string {
+	if r, size := utf8.DecodeLastRuneInString(s); r != utf8.RuneError || size != 1 {
+		return s
+	}
+	for i := len(s) - 1; i >= 0 && i >= len(s)-(utf8.UTFMax-1); i-- {
+		if utf8.RuneStart(s[i]) {
+			if !utf8.FullRuneInString(s[i:]) {
+				return s[:i]
+			}
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestTruncateRowToFit(t *testing.T) {
+	row := []interface{}{int64(1), strings.Repeat("a", 1000), "short", strings.Repeat("é", 400)}
+	cut, fits := truncateRowToFit(row, 600)
+	if !fits || len(cut) != 2 || cut[0] != 1 {
+		t.Fatalf("cut=%v fits=%v, want cells 1 and 3 cut and a fitting row", cut, fits)
+	}
+	if rowBytes(row) > 600 {
+		t.Errorf("row is %d bytes, want <= 600", rowBytes(row))
+	}
+	long := row[1].(string)
+	if !strings.Contains(long, "[truncated ") || !utf8.ValidString(row[3].(string)) {
+		t.Errorf("unexpected cells: %q / %q", long, row[3])
+	}
+	if row[2] != "short" {
+		t.Errorf("short cell changed: %q", row[2])
+	}
+
+	// Non-string cells cannot be shortened.
+	if _, fits := truncateRowToFit([]interface{}{int64(1), int64(2)}, 4); fits {
+		t.Error("expected numeric row not to fit in 4 bytes")
+	}
+}
+
+func TestTruncateRowToFitBinaryCell(t *testing.T) {
+	// An invalid byte near the start must not make the cut drop the rest.
+	blob := "\xff\xfe" + strings.Repeat("\x00\x9f", 1<<20)
+	row := []interface{}{blob}
+	cut, fits := truncateRowToFit(row, 1<<20)
+	if !fits || len(cut) != 1 {
+		t.Fatalf("cut=%v fits=%v", cut, fits)
+	}
+	kept := strings.Index(row[0].(string), "…[truncated ")
+	if kept < 1<<20-100 || row[0].(string)[:2] != "\xff\xfe" {
+		t.Errorf("kept %d bytes of the binary cell, want close to 1 MiB", kept)
+	}
+
+	// A rune split by the cut is dropped whole.
+	if got := trimPartialRune("ab" + "€"[:2]); got != "ab" {
+		t.Errorf("trimPartialRune split rune = %q", got)
+	}
+	if got := trimPartialRune("ab€"); got != "ab€" {
+		t.Errorf("trimPartialRune whole rune = %q", got)
+	}
+	if got := trimPartialRune("ab\xff"); got != "ab\xff" {
+		t.Errorf("trimPartialRune invalid byte = %q", got)
+	}
+}
+
+func TestRunQueryStopsAtMaxResultBytes(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	old := maxResultBytes
+	maxResultBytes = 2500
+	defer func() { maxResultBytes = old }()
+
+	big := strings.Repeat("x", 1000)
+	rows := sqlmock.NewRows([]string{"id", "body"}).
+		AddRow(1, big).
+		AddRow(2, big).
+		AddRow(3, big).
+		AddRow(4, big)
+	mock.ExpectQuery("SELECT id, body FROM docs").WillReturnRows(rows)
+
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, body FROM docs"})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if len(out.Rows) != 3 || !out.Truncated || out.TruncatedCells != 1 {
+		t.Fatalf("rows=%d truncated=%v truncated_cells=%d, want 3 rows with the last cut", len(out.Rows), out.Truncated, out.TruncatedCells)
+	}
+	if out.SizeBytes > 2500 || out.SizeBytes < 2000 {
+		t.Errorf("size_bytes = %d, want close to the 2500 limit", out.SizeBytes)
+	}
+	if !strings.Contains(out.Rows[2][1].(string), "[truncated ") {
+		t.Errorf("last row was not marked: %q", out.Rows[2][1])
+	}
+	if !strings.Contains(out.Warning, "MYSQL_MCP_MAX_RESULT_BYTES") {
+		t.Errorf("expected byte-limit warning, got %q", out.Warning)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
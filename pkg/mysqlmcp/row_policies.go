@@ -0,0 +1,114 @@
+// pkg/mysqlmcp/row_policies.go
+package mysqlmcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Row policies (row_policies on a connection) make a predicate mandatory for
+// every read of a table, so shared tables can be exposed to per-customer
+// agents: run_query, run_query_stream, run_saved_query and run_report rewrite
+// the parsed statement to filter each such table (see util.ApplyRowPolicies),
+// and tools that read table rows themselves refuse the table. Predicates read
+// {session.name} from the session of the virtual connection in use.
+
+// checkRowPolicies validates the row policies of c, including predicate syntax.
+func checkRowPolicies(c config.ConnectionConfig) error {
+	if err := config.CheckRowPolicies(c); err != nil {
+		return err
+	}
+	for table, pred := range c.RowPolicies {
+		if err := util.CheckRowPolicyPredicate(pred); err != nil {
+			return fmt.Errorf("connection '%s': row policy on '%s': %w", c.Name, table, err)
+		}
+	}
+	return nil
+}
+
+// activeRowPolicies returns the row policies of the active connection and its
+// default database.
+func activeRowPolicies() ([]util.RowPolicy, string) {
+	c, ok := activeConnectionConfig()
+	if !ok || len(c.RowPolicies) == 0 {
+		return nil, ""
+	}
+	policies := make([]util.RowPolicy, 0, len(c.RowPolicies))
+	for table, pred := range c.RowPolicies {
+		p := util.RowPolicy{Table: strings.TrimSpace(table), Predicate: pred}
+		if db, t, ok := strings.Cut(p.Table, "."); ok {
+			p.Database, p.Table = db, t
+		}
+		policies = append(policies, p)
+	}
+	// Qualified policies first, so they win over a bare table name.
+	sort.Slice(policies, func(i, j int) bool {
+		if (policies[i].Database == "") != (policies[j].Database == "") {
+			return policies[i].Database != ""
+		}
+		return policies[i].Database+"."+policies[i].Table < policies[j].Database+"."+policies[j].Table
+	})
+	defaultDB := ""
+	if dsn, err := mysql.ParseDSN(c.DSN); err == nil {
+		defaultDB = dsn.DBName
+	}
+	return policies, defaultDB
+}
+
+// rowPolicySession returns the {session.name} values for predicates.
+func rowPolicySession() map[string]string {
+	if vc := activeVirtualConnection(); vc != nil {
+		return vc.Session
+	}
+	return nil
+}
+
+// applyRowPolicies rewrites sqlText, run with database selected, so it reads
+// policy tables of the active connection through their predicates.
+func applyRowPolicies(sqlText, database string) (string, error) {
+	policies, defaultDB := activeRowPolicies()
+	if len(policies) == 0 {
+		return sqlText, nil
+	}
+	if database != "" {
+		defaultDB = database
+	}
+	out, changed, err := util.ApplyRowPolicies(sqlText, defaultDB, policies, rowPolicySession())
+	if err != nil {
+		return "", err
+	}
+	if changed {
+		validatorLog.Debug("row policies applied", map[string]interface{}{"query": loggedSQL(out, 200)})
+	}
+	return out, nil
+}
+
+// requireNoRowPolicy refuses tools that read rows of database.table without
+// going through SQL the row policies can rewrite.
+func requireNoRowPolicy(database, table string) error {
+	policies, _ := activeRowPolicies()
+	for _, p := range policies {
+		if strings.EqualFold(p.Table, table) && (p.Database == "" || strings.EqualFold(p.Database, database)) {
+			return fmt.Errorf("table %s.%s has a row policy on this connection; query it with run_query instead", database, table)
+		}
+	}
+	return nil
+}
+
+// rowPolicyTables lists the tables with a row policy on c, for list_connections.
+func rowPolicyTables(c config.ConnectionConfig) []string {
+	if len(c.RowPolicies) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(c.RowPolicies))
+	for table := range c.RowPolicies {
+		out = append(out, strings.TrimSpace(table))
+	}
+	sort.Strings(out)
+	return out
+}
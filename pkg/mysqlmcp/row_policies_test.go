@@ -0,0 +1,69 @@
+// pkg/mysqlmcp/row_policies_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRunQueryAppliesRowPolicies(t *testing.T) {
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+	t.Cleanup(func() { initVirtualConnections(nil) })
+	connManager.configs["mock"] = config.ConnectionConfig{
+		Name:        "mock",
+		DSN:         mockDSN,
+		RowPolicies: map[string]string{"orders": "tenant_id = {session.tenant}"},
+	}
+	ctx := context.Background()
+
+	// Without a session value the query is refused, not run unfiltered.
+	initVirtualConnections(nil)
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM orders"}); err == nil || !strings.Contains(err.Error(), "session value") {
+		t.Fatalf("expected missing session value error, got %v", err)
+	}
+
+	initVirtualConnections([]config.VirtualConnection{{Name: "acme", Schemas: []string{"%"}, Session: map[string]string{"tenant": "42"}}})
+	if _, err := enterVirtualConnection("acme"); err != nil {
+		t.Fatal(err)
+	}
+	result.mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	result.mock.ExpectQuery(`select o.id from \(select \* from orders where tenant_id = 42\) as o where o.total > 10 LIMIT 1000`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectRestoreDatabase(result.mock)
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT o.id FROM orders o WHERE o.total > 10", Database: "shop"}); err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if err := result.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+
+	if _, _, err := toolProfileColumn(ctx, &mcp.CallToolRequest{}, ProfileColumnInput{Database: "shop", Table: "orders", Column: "total"}); err == nil || !strings.Contains(err.Error(), "row policy") {
+		t.Errorf("profile_column should refuse a policy table, got %v", err)
+	}
+	_, conns, _ := toolListConnections(ctx, &mcp.CallToolRequest{}, ListConnectionsInput{})
+	if len(conns.Connections) != 1 || len(conns.Connections[0].RowPolicies) != 1 || conns.Connections[0].RowPolicies[0] != "orders" {
+		t.Errorf("unexpected row_policies: %+v", conns.Connections)
+	}
+}
+
+func TestCheckRowPolicies(t *testing.T) {
+	ok := config.ConnectionConfig{Name: "shared", RowPolicies: map[string]string{"shop.orders": "tenant_id = {session.tenant}"}}
+	if err := checkRowPolicies(ok); err != nil {
+		t.Errorf("valid policy rejected: %v", err)
+	}
+	for _, bad := range []map[string]string{
+		{"orders": "tenant_id ="},
+		{"a.b.c": "x = 1"},
+		{"orders": " "},
+	} {
+		if err := checkRowPolicies(config.ConnectionConfig{Name: "shared", RowPolicies: bad}); err == nil {
+			t.Errorf("%v: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,434 @@
+// pkg/mysqlmcp/saved_queries.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/dbretry"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	savedQuerySourceConfig  = "config"
+	savedQuerySourceRuntime = "runtime"
+)
+
+var savedQueryNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// savedQuery is a validated library entry: SQL is rewritten to ? placeholders
+// and order lists the parameter bound to each one.
+type savedQuery struct {
+	config.SavedQuery
+	source   string
+	boundSQL string
+	order    []string
+}
+
+// queryLibrary holds saved queries from the config file plus any registered at
+// runtime with save_query or as bookmarks. Runtime entries live in memory only,
+// bookmarks also in their file; neither can replace config entries.
+type queryLibrary struct {
+	mu      sync.RWMutex
+	queries map[string]*savedQuery
+}
+
+func newQueryLibrary() *queryLibrary {
+	return &queryLibrary{queries: make(map[string]*savedQuery)}
+}
+
+var savedQueries = newQueryLibrary()
+
+// loadSavedQueries registers the config file's saved queries at startup.
+func loadSavedQueries(queries []config.SavedQuery) error {
+	for _, q := range queries {
+		if _, err := savedQueries.add(q, savedQuerySourceConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileSavedQuery validates q and binds its :name placeholders. Placeholders
+// without a declared parameter become required string parameters.
+func compileSavedQuery(q config.SavedQuery, source string) (*savedQuery, error) {
+	q.Name = strings.TrimSpace(q.Name)
+	q.SQL = strings.TrimSpace(q.SQL)
+	q.Database = strings.TrimSpace(q.Database)
+	if !savedQueryNamePattern.MatchString(q.Name) {
+		return nil, fmt.Errorf("invalid saved query name %q (letters, digits, _ . -; max 64 characters)", q.Name)
+	}
+	if q.SQL == "" {
+		return nil, fmt.Errorf("saved query %s: sql is required", q.Name)
+	}
+
+	boundSQL, order := util.BindNamedParams(q.SQL)
+	if err := validateSQL(boundSQL); err != nil {
+		return nil, fmt.Errorf("saved query %s: %w", q.Name, err)
+	}
+
+	declared := make(map[string]bool, len(q.Params))
+	params := make([]config.SavedQueryParam, 0, len(q.Params))
+	for _, p := range q.Params {
+		p.Name = strings.TrimSpace(p.Name)
+		p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+		if p.Type == "" {
+			p.Type = "string"
+		}
+		switch p.Type {
+		case "string", "int", "number", "bool", "date":
+		default:
+			return nil, fmt.Errorf("saved query %s: parameter %s has unsupported type %q", q.Name, p.Name, p.Type)
+		}
+		if p.Name == "" || declared[p.Name] {
+			return nil, fmt.Errorf("saved query %s: parameter names must be unique and non-empty", q.Name)
+		}
+		if p.Default != nil {
+			if _, err := convertSavedQueryParam(p, p.Default); err != nil {
+				return nil, fmt.Errorf("saved query %s: default for %s: %w", q.Name, p.Name, err)
+			}
+		}
+		declared[p.Name] = true
+		params = append(params, p)
+	}
+
+	used := make(map[string]bool, len(order))
+	for _, name := range order {
+		if !declared[name] {
+			declared[name] = true
+			params = append(params, config.SavedQueryParam{Name: name, Type: "string", Required: true})
+		}
+		used[name] = true
+	}
+	for _, p := range params {
+		if !used[p.Name] {
+			return nil, fmt.Errorf("saved query %s: parameter %s is not used in the sql", q.Name, p.Name)
+		}
+	}
+	q.Params = params
+
+	return &savedQuery{SavedQuery: q, source: source, boundSQL: boundSQL, order: order}, nil
+}
+
+// add validates and stores q, reporting whether it replaced an entry.
+func (l *queryLibrary) add(q config.SavedQuery, source string) (bool, error) {
+	compiled, err := compileSavedQuery(q, source)
+	if err != nil {
+		return false, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	existing, replaced := l.queries[compiled.Name]
+	if replaced && existing.source == savedQuerySourceConfig && source != savedQuerySourceConfig {
+		return false, fmt.Errorf("saved query %s is defined in the config file and cannot be replaced", compiled.Name)
+	}
+	l.queries[compiled.Name] = compiled
+	return replaced, nil
+}
+
+func (l *queryLibrary) get(name string) (*savedQuery, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	q, ok := l.queries[name]
+	return q, ok
+}
+
+// set stores q under name, or removes name when q is nil. Bookmarks use it to
+// drop entries and to undo an add whose file write failed.
+func (l *queryLibrary) set(name string, q *savedQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if q == nil {
+		delete(l.queries, name)
+		return
+	}
+	l.queries[name] = q
+}
+
+func (l *queryLibrary) list() []*savedQuery {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	out := make([]*savedQuery, 0, len(l.queries))
+	for _, q := range l.queries {
+		out = append(out, q)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (q *savedQuery) info() SavedQueryInfo {
+	info := SavedQueryInfo{
+		Name:        q.Name,
+		Description: q.Description,
+		Database:    q.Database,
+		SQL:         q.SQL,
+		Params:      make([]SavedQueryParamInfo, 0, len(q.Params)),
+		Source:      q.source,
+	}
+	for _, p := range q.Params {
+		info.Params = append(info.Params, SavedQueryParamInfo(p))
+	}
+	return info
+}
+
+// bindArgs resolves caller values and defaults into placeholder order.
+func (q *savedQuery) bindArgs(values map[string]interface{}) ([]interface{}, error) {
+	byName := make(map[string]config.SavedQueryParam, len(q.Params))
+	for _, p := range q.Params {
+		byName[p.Name] = p
+	}
+	for name := range values {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("unknown parameter %s for saved query %s", name, q.Name)
+		}
+	}
+
+	resolved := make(map[string]interface{}, len(q.Params))
+	for _, p := range q.Params {
+		v, ok := values[p.Name]
+		if !ok || v == nil {
+			if p.Default == nil {
+				if p.Required {
+					return nil, fmt.Errorf("parameter %s is required", p.Name)
+				}
+				resolved[p.Name] = nil
+				continue
+			}
+			v = p.Default
+		}
+		converted, err := convertSavedQueryParam(p, v)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		resolved[p.Name] = converted
+	}
+
+	args := make([]interface{}, len(q.order))
+	for i, name := range q.order {
+		args[i] = resolved[name]
+	}
+	return args, nil
+}
+
+// convertSavedQueryParam coerces a JSON or YAML value to the parameter type.
+func convertSavedQueryParam(p config.SavedQueryParam, v interface{}) (interface{}, error) {
+	switch p.Type {
+	case "int":
+		switch x := v.(type) {
+		case int:
+			return int64(x), nil
+		case int64:
+			return x, nil
+		case float64:
+			if x != math.Trunc(x) {
+				return nil, fmt.Errorf("expected an integer, got %v", x)
+			}
+			return int64(x), nil
+		case string:
+			n, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("expected an integer, got %q", x)
+			}
+			return n, nil
+		}
+	case "number":
+		switch x := v.(type) {
+		case int:
+			return float64(x), nil
+		case int64:
+			return float64(x), nil
+		case float64:
+			return x, nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+			if err != nil {
+				return nil, fmt.Errorf("expected a number, got %q", x)
+			}
+			return f, nil
+		}
+	case "bool":
+		switch x := v.(type) {
+		case bool:
+			return x, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(x))
+			if err != nil {
+				return nil, fmt.Errorf("expected a boolean, got %q", x)
+			}
+			return b, nil
+		}
+	case "date":
+		if t, ok := v.(time.Time); ok {
+			return t.Format("2006-01-02"), nil
+		}
+		if x, ok := v.(string); ok {
+			x = strings.TrimSpace(x)
+			if _, err := time.Parse("2006-01-02", x); err != nil {
+				return nil, fmt.Errorf("expected a YYYY-MM-DD date, got %q", x)
+			}
+			return x, nil
+		}
+	default:
+		switch x := v.(type) {
+		case string:
+			return x, nil
+		case bool, int, int64, float64:
+			return fmt.Sprint(x), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported value %v for type %s", v, p.Type)
+}
+
+func toolListSavedQueries(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListSavedQueriesInput,
+) (*mcp.CallToolResult, ListSavedQueriesOutput, error) {
+	out := ListSavedQueriesOutput{Queries: []SavedQueryInfo{}}
+	for _, q := range savedQueries.list() {
+		if q.Database != "" && accessControlEnabled() && !databaseAllowed(q.Database) {
+			continue
+		}
+		out.Queries = append(out.Queries, q.info())
+	}
+	return nil, out, nil
+}
+
+func toolRunSavedQuery(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input RunSavedQueryInput,
+) (*mcp.CallToolResult, QueryResult, error) {
+	timer := NewQueryTimer(ctx, "run_saved_query")
+
+	q, ok := savedQueries.get(strings.TrimSpace(input.Name))
+	if !ok {
+		return nil, QueryResult{}, fmt.Errorf("saved query not found: %s", input.Name)
+	}
+
+	database := q.Database
+	if requested := strings.TrimSpace(input.Database); requested != "" {
+		if database != "" && !strings.EqualFold(requested, database) {
+			return nil, QueryResult{}, fmt.Errorf("saved query %s runs in database %s", q.Name, database)
+		}
+		database = requested
+	}
+	if accessControlEnabled() && database == "" {
+		return nil, QueryResult{}, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+	}
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, QueryResult{}, err
+		}
+	}
+	if err := requireReferencedSchemasInQuery(q.boundSQL); err != nil {
+		return nil, QueryResult{}, err
+	}
+
+	args, err := q.bindArgs(input.Params)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+	binary, err := resolveBinaryOutput(input.BinaryOutput)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+	timeZone, err := resolveTimeZone(input.TimeZone)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+
+	limit := defaultRowLimit(database)
+	if input.MaxRows != nil && *input.MaxRows > 0 && *input.MaxRows < limit {
+		limit = *input.MaxRows
+	}
+	finalSQL, err := applyRowPolicies(q.boundSQL, database)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+	if cfg == nil || cfg.InjectLimit {
+		finalSQL = util.InjectLimit(finalSQL, limit)
+	}
+
+	ctx, cancel := context.WithTimeout(withQueryTimeZone(ctx, timeZone), queryTimeout)
+	defer cancel()
+
+	db := getReadDB(ctx)
+	var out QueryResult
+	stats, err := dbretry.DoWithStats(ctx, db, dbRetryCfg, pingTimeout, func() error {
+		var e error
+		out, e = runQueryScan(ctx, db, finalSQL, database, limit, false, 0, binary, args...)
+		return e
+	})
+	out.Retries, out.RetryReason = retryMetadata(ctx, "run_saved_query", stats)
+
+	entry := &AuditEntry{
+		Tool:        "run_saved_query",
+		Database:    database,
+		Query:       q.Name + ": " + loggedSQL(finalSQL, 500),
+		QueryDigest: util.QueryDigest(q.boundSQL),
+	}
+	if err != nil {
+		timer.LogError(err, finalSQL, nil, nil)
+		if auditLogger != nil {
+			entry.DurationMs = timer.ElapsedMs()
+			entry.Error = err.Error()
+			auditLogger.LogContext(ctx, entry)
+		}
+		return nil, QueryResult{}, err
+	}
+
+	if cfg != nil && len(cfg.MaskColumns) > 0 {
+		maskResults(out.Columns, out.Rows, cfg.MaskColumns)
+	}
+	pseudonymizeResults(ctx, out.Columns, out.Rows)
+
+	timer.LogSuccess(len(out.Rows), finalSQL, nil, nil)
+	if auditLogger != nil {
+		entry.DurationMs = timer.ElapsedMs()
+		entry.RowCount = len(out.Rows)
+		entry.Success = true
+		auditLogger.LogContext(ctx, entry)
+	}
+	return nil, out, nil
+}
+
+func toolSaveQuery(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SaveQueryInput,
+) (*mcp.CallToolResult, SaveQueryOutput, error) {
+	if input.Database != "" {
+		if err := requireAllowedDatabase(strings.TrimSpace(input.Database)); err != nil {
+			return nil, SaveQueryOutput{}, err
+		}
+	}
+
+	q := config.SavedQuery{
+		Name:        input.Name,
+		Description: input.Description,
+		SQL:         input.SQL,
+		Database:    input.Database,
+	}
+	for _, p := range input.Params {
+		q.Params = append(q.Params, config.SavedQueryParam(p))
+	}
+	replaced, err := savedQueries.add(q, savedQuerySourceRuntime)
+	if err != nil {
+		return nil, SaveQueryOutput{}, err
+	}
+	stored, _ := savedQueries.get(strings.TrimSpace(input.Name))
+
+	serverLog.Info("saved query registered", map[string]interface{}{"name": stored.Name, "replaced": replaced})
+	return nil, SaveQueryOutput{Query: stored.info(), Replaced: replaced}, nil
+}
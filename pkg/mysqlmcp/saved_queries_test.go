@@ -0,0 +1,161 @@
+// pkg/mysqlmcp/saved_queries_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withSavedQueries swaps in a fresh library for the test.
+func withSavedQueries(t *testing.T, queries ...config.SavedQuery) func() {
+	t.Helper()
+	old := savedQueries
+	savedQueries = newQueryLibrary()
+	if err := loadSavedQueries(queries); err != nil {
+		t.Fatalf("loadSavedQueries failed: %v", err)
+	}
+	return func() { savedQueries = old }
+}
+
+func TestCompileSavedQuery(t *testing.T) {
+	q, err := compileSavedQuery(config.SavedQuery{
+		Name: "orders_by_customer",
+		SQL:  "SELECT id FROM orders WHERE customer_id = :customer_id AND status = :status LIMIT :limit",
+		Params: []config.SavedQueryParam{
+			{Name: "customer_id", Type: "int", Required: true},
+			{Name: "limit", Type: "int", Default: 20},
+		},
+	}, savedQuerySourceConfig)
+	if err != nil {
+		t.Fatalf("compileSavedQuery failed: %v", err)
+	}
+	if q.boundSQL != "SELECT id FROM orders WHERE customer_id = ? AND status = ? LIMIT ?" {
+		t.Errorf("unexpected bound SQL: %s", q.boundSQL)
+	}
+	if len(q.Params) != 3 || q.Params[2].Name != "status" || !q.Params[2].Required || q.Params[2].Type != "string" {
+		t.Errorf("expected undeclared placeholder to become a required string: %+v", q.Params)
+	}
+
+	args, err := q.bindArgs(map[string]interface{}{"customer_id": float64(42), "status": "open"})
+	if err != nil {
+		t.Fatalf("bindArgs failed: %v", err)
+	}
+	if args[0] != int64(42) || args[1] != "open" || args[2] != int64(20) {
+		t.Errorf("unexpected args: %#v", args)
+	}
+
+	for _, values := range []map[string]interface{}{
+		{"status": "open"},
+		{"customer_id": 1.5, "status": "open"},
+		{"customer_id": 1, "status": "open", "extra": 1},
+	} {
+		if _, err := q.bindArgs(values); err == nil {
+			t.Errorf("expected bindArgs error for %v", values)
+		}
+	}
+
+	invalid := []config.SavedQuery{
+		{Name: "bad name!", SQL: "SELECT 1"},
+		{Name: "writes", SQL: "DELETE FROM orders WHERE id = :id"},
+		{Name: "unused", SQL: "SELECT 1", Params: []config.SavedQueryParam{{Name: "id"}}},
+		{Name: "badtype", SQL: "SELECT :id", Params: []config.SavedQueryParam{{Name: "id", Type: "uuid"}}},
+		{Name: "ident", SQL: "SELECT :tbl", Params: []config.SavedQueryParam{{Name: "tbl", Type: "identifier"}}},
+	}
+	for _, sq := range invalid {
+		if _, err := compileSavedQuery(sq, savedQuerySourceConfig); err == nil {
+			t.Errorf("expected %s to be rejected", sq.Name)
+		}
+	}
+}
+
+func TestToolRunSavedQuery(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	defer withSavedQueries(t, config.SavedQuery{
+		Name:     "open_orders",
+		Database: "shop",
+		SQL:      "SELECT id, total FROM orders WHERE customer_id = :customer_id",
+		Params:   []config.SavedQueryParam{{Name: "customer_id", Type: "int", Required: true}},
+	})()
+
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, total FROM orders WHERE customer_id = \\? LIMIT 1000").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "total"}).AddRow(1, "9.99"))
+	expectRestoreDatabase(mock)
+
+	_, out, err := toolRunSavedQuery(context.Background(), &mcp.CallToolRequest{}, RunSavedQueryInput{
+		Name:   "open_orders",
+		Params: map[string]interface{}{"customer_id": "7"},
+	})
+	if err != nil {
+		t.Fatalf("toolRunSavedQuery failed: %v", err)
+	}
+	if len(out.Rows) != 1 || out.Columns[1] != "total" {
+		t.Errorf("unexpected result: %+v", out)
+	}
+
+	if _, _, err := toolRunSavedQuery(context.Background(), &mcp.CallToolRequest{}, RunSavedQueryInput{
+		Name: "open_orders", Database: "other", Params: map[string]interface{}{"customer_id": 1},
+	}); err == nil {
+		t.Error("expected error when overriding a pinned database")
+	}
+	if _, _, err := toolRunSavedQuery(context.Background(), &mcp.CallToolRequest{}, RunSavedQueryInput{Name: "missing"}); err == nil {
+		t.Error("expected error for unknown saved query")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolSaveQuery(t *testing.T) {
+	defer withSavedQueries(t, config.SavedQuery{Name: "reviewed", SQL: "SELECT 1"})()
+
+	_, out, err := toolSaveQuery(context.Background(), &mcp.CallToolRequest{}, SaveQueryInput{
+		Name: "by_email",
+		SQL:  "SELECT id FROM users WHERE email = :email",
+	})
+	if err != nil {
+		t.Fatalf("toolSaveQuery failed: %v", err)
+	}
+	if out.Replaced || out.Query.Source != savedQuerySourceRuntime || len(out.Query.Params) != 1 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	_, out, err = toolSaveQuery(context.Background(), &mcp.CallToolRequest{}, SaveQueryInput{
+		Name: "by_email",
+		SQL:  "SELECT id, name FROM users WHERE email = :email",
+	})
+	if err != nil || !out.Replaced {
+		t.Errorf("expected runtime query to be replaced: %+v, %v", out, err)
+	}
+
+	if _, _, err := toolSaveQuery(context.Background(), &mcp.CallToolRequest{}, SaveQueryInput{Name: "reviewed", SQL: "SELECT 2"}); err == nil {
+		t.Error("expected config query to be protected")
+	}
+
+	_, list, _ := toolListSavedQueries(context.Background(), &mcp.CallToolRequest{}, ListSavedQueriesInput{})
+	if len(list.Queries) != 2 || list.Queries[0].Name != "by_email" || list.Queries[1].Source != savedQuerySourceConfig {
+		t.Errorf("unexpected list: %+v", list.Queries)
+	}
+}
+
+func TestHTTPRunSavedQueryBadRequest(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/saved-queries/run", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	httpRunSavedQuery(w, req)
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Result().StatusCode)
+	}
+}
@@ -0,0 +1,289 @@
+// pkg/mysqlmcp/scheduler.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/cron"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Schedules run saved queries in the background on cron expressions and keep
+// the latest two results, so scheduled_results can answer "what changed since
+// the last run" without the client polling. A tick that comes while the
+// previous run is still going is skipped rather than stacked, and jitter
+// spreads schedules sharing an expression over a few seconds.
+
+// scheduledQuery is a validated schedule and its last two runs.
+type scheduledQuery struct {
+	config.Schedule
+	expr *cron.Schedule
+
+	mu       sync.Mutex
+	running  bool
+	runs     int
+	skipped  int // ticks skipped because the previous run was still going
+	next     time.Time
+	latest   *scheduledRun
+	previous *scheduledRun
+}
+
+// scheduledRun is the outcome of one scheduled execution.
+type scheduledRun struct {
+	started  time.Time
+	duration time.Duration
+	result   QueryResult
+	err      string
+}
+
+// schedules holds the config file's schedules, keyed by name.
+var schedules = map[string]*scheduledQuery{}
+
+// loadSchedules validates the config file's schedules. Saved queries must be
+// loaded first so their names and parameters can be checked.
+func loadSchedules(defs []config.Schedule) error {
+	loaded := make(map[string]*scheduledQuery, len(defs))
+	for _, def := range defs {
+		s, err := compileSchedule(def)
+		if err != nil {
+			return err
+		}
+		loaded[s.Name] = s
+	}
+	schedules = loaded
+	return nil
+}
+
+func compileSchedule(def config.Schedule) (*scheduledQuery, error) {
+	def.Name = strings.TrimSpace(def.Name)
+	if !savedQueryNamePattern.MatchString(def.Name) {
+		return nil, fmt.Errorf("invalid schedule name %q (letters, digits, _ . -; max 64 characters)", def.Name)
+	}
+	expr, err := cron.Parse(def.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("schedule %s: %w", def.Name, err)
+	}
+	if def.Jitter < 0 {
+		return nil, fmt.Errorf("schedule %s: jitter must not be negative", def.Name)
+	}
+	q, ok := savedQueries.get(strings.TrimSpace(def.SavedQuery))
+	if !ok {
+		return nil, fmt.Errorf("schedule %s: saved query %s not found", def.Name, def.SavedQuery)
+	}
+	if _, err := q.bindArgs(def.Params); err != nil {
+		return nil, fmt.Errorf("schedule %s: %w", def.Name, err)
+	}
+	def.SavedQuery = q.Name
+	if def.Database == "" {
+		def.Database = q.Database
+	}
+	return &scheduledQuery{Schedule: def, expr: expr}, nil
+}
+
+// startSchedules runs every schedule until ctx is canceled.
+func startSchedules(ctx context.Context) {
+	for _, s := range schedules {
+		go s.loop(ctx)
+	}
+}
+
+// loop waits for each activation of s and starts a run unless the previous
+// one is still going.
+func (s *scheduledQuery) loop(ctx context.Context) {
+	for {
+		next := s.expr.Next(time.Now())
+		if next.IsZero() {
+			serverLog.Warn("schedule never fires again; stopping it", map[string]interface{}{"schedule": s.Name, "cron": s.Cron})
+			return
+		}
+		if s.Jitter > 0 {
+			next = next.Add(rand.N(s.Jitter))
+		}
+		s.mu.Lock()
+		s.next = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.begin() {
+			serverLog.Warn("scheduled run skipped; previous run still going", map[string]interface{}{"schedule": s.Name})
+			continue
+		}
+		go s.execute(ctx)
+	}
+}
+
+// begin marks s running, or reports false when it already is.
+func (s *scheduledQuery) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		s.skipped++
+		return false
+	}
+	s.running = true
+	return true
+}
+
+// execute runs the saved query once and records the result. It takes the
+// same circuit breaker and concurrency slot as a run_saved_query call, and
+// its audit entry has source scheduler.
+func (s *scheduledQuery) execute(ctx context.Context) {
+	ctx = withRequestSource(withRequestID(ctx, newRequestID()), requestSourceScheduler)
+	run := &scheduledRun{started: time.Now()}
+	out, err := runScheduledQuery(ctx, s)
+	run.duration = time.Since(run.started)
+	run.result = out
+	if err != nil {
+		run.err = err.Error()
+		if ctx.Err() == nil {
+			serverLog.Warn("scheduled run failed", map[string]interface{}{"schedule": s.Name, "error": run.err})
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	if ctx.Err() != nil {
+		return
+	}
+	s.runs++
+	s.previous, s.latest = s.latest, run
+}
+
+func runScheduledQuery(ctx context.Context, s *scheduledQuery) (QueryResult, error) {
+	const tool = "run_saved_query"
+	done, err := admitCircuit(tool)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	release, err := acquireQuerySlot(ctx, tool)
+	if err != nil {
+		done(err)
+		return QueryResult{}, err
+	}
+	defer release()
+	_, out, err := toolRunSavedQuery(ctx, nil, RunSavedQueryInput{Name: s.SavedQuery, Params: s.Params, Database: s.Database})
+	done(err)
+	return out, err
+}
+
+func (s *scheduledQuery) info() ScheduleInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := ScheduleInfo{
+		Name:            s.Name,
+		SavedQuery:      s.SavedQuery,
+		Cron:            s.Cron,
+		Database:        s.Database,
+		Runs:            s.runs,
+		SkippedOverlaps: s.skipped,
+		Running:         s.running,
+	}
+	if !s.next.IsZero() {
+		info.NextRun = s.next.UTC().Format(time.RFC3339)
+	}
+	if s.latest != nil {
+		info.LastRun = s.latest.started.UTC().Format(time.RFC3339)
+		info.LastDurationMs = s.latest.duration.Milliseconds()
+		info.LastError = s.latest.err
+	}
+	return info
+}
+
+// visible reports whether the caller may see s under MYSQL_MCP_ALLOWED_DATABASES.
+func (s *scheduledQuery) visible() bool {
+	return s.Database == "" || !accessControlEnabled() || databaseAllowed(s.Database)
+}
+
+// diffScheduledRows compares two results row by row, treating each as a
+// multiset so duplicate rows are counted.
+func diffScheduledRows(before, after QueryResult) ScheduledChanges {
+	var ch ScheduledChanges
+	if strings.Join(before.Columns, "\x00") != strings.Join(after.Columns, "\x00") {
+		ch.ColumnsChanged = true
+		ch.Added, ch.Removed = after.Rows, before.Rows
+		return ch
+	}
+	key := func(row []interface{}) string {
+		b, _ := json.Marshal(row)
+		return string(b)
+	}
+	remaining := make(map[string]int, len(before.Rows))
+	for _, row := range before.Rows {
+		remaining[key(row)]++
+	}
+	for _, row := range after.Rows {
+		k := key(row)
+		if remaining[k] > 0 {
+			remaining[k]--
+			ch.Unchanged++
+			continue
+		}
+		ch.Added = append(ch.Added, row)
+	}
+	for _, row := range before.Rows {
+		k := key(row)
+		if remaining[k] > 0 {
+			remaining[k]--
+			ch.Removed = append(ch.Removed, row)
+		}
+	}
+	return ch
+}
+
+func toolScheduledResults(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ScheduledResultsInput,
+) (*mcp.CallToolResult, ScheduledResultsOutput, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		out := ScheduledResultsOutput{Schedules: []ScheduleInfo{}}
+		for _, s := range schedules {
+			if s.visible() {
+				out.Schedules = append(out.Schedules, s.info())
+			}
+		}
+		sort.Slice(out.Schedules, func(i, j int) bool { return out.Schedules[i].Name < out.Schedules[j].Name })
+		return nil, out, nil
+	}
+
+	s, ok := schedules[name]
+	if !ok || !s.visible() {
+		return nil, ScheduledResultsOutput{}, fmt.Errorf("schedule not found: %s", name)
+	}
+	info := s.info()
+	out := ScheduledResultsOutput{Schedule: &info}
+
+	s.mu.Lock()
+	latest, previous := s.latest, s.previous
+	s.mu.Unlock()
+	if latest == nil {
+		return nil, out, nil
+	}
+	if latest.err == "" && !input.ChangesOnly {
+		result := latest.result
+		out.Result = &result
+	}
+	if latest.err == "" && previous != nil && previous.err == "" {
+		ch := diffScheduledRows(previous.result, latest.result)
+		ch.Since = previous.started.UTC().Format(time.RFC3339)
+		out.Changes = &ch
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,107 @@
+// pkg/mysqlmcp/scheduler_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestLoadSchedules(t *testing.T) {
+	defer withSavedQueries(t, config.SavedQuery{
+		Name: "orders_by_status",
+		SQL:  "SELECT id FROM orders WHERE status = :status",
+	})()
+	old := schedules
+	defer func() { schedules = old }()
+
+	if err := loadSchedules([]config.Schedule{{
+		Name: "pending", SavedQuery: "orders_by_status", Cron: "*/5 * * * *",
+		Params: map[string]interface{}{"status": "pending"},
+	}}); err != nil {
+		t.Fatalf("loadSchedules: %v", err)
+	}
+	if schedules["pending"] == nil {
+		t.Fatal("expected schedule pending to be loaded")
+	}
+
+	for _, def := range []config.Schedule{
+		{Name: "missing", SavedQuery: "nope", Cron: "@hourly"},
+		{Name: "badcron", SavedQuery: "orders_by_status", Cron: "often", Params: map[string]interface{}{"status": "x"}},
+		{Name: "noparam", SavedQuery: "orders_by_status", Cron: "@hourly"},
+		{Name: "bad name!", SavedQuery: "orders_by_status", Cron: "@hourly", Params: map[string]interface{}{"status": "x"}},
+	} {
+		if err := loadSchedules([]config.Schedule{def}); err == nil {
+			t.Errorf("expected schedule %q to be rejected", def.Name)
+		}
+	}
+}
+
+func TestScheduledResults(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	defer withSavedQueries(t, config.SavedQuery{Name: "stuck", SQL: "SELECT id, status FROM orders"})()
+	old := schedules
+	defer func() { schedules = old }()
+	if err := loadSchedules([]config.Schedule{{Name: "stuck_orders", SavedQuery: "stuck", Cron: "@every 1m"}}); err != nil {
+		t.Fatal(err)
+	}
+	s := schedules["stuck_orders"]
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT id, status FROM orders").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "pending").AddRow(2, "pending"))
+	mock.ExpectQuery("SELECT id, status FROM orders").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(2, "pending").AddRow(3, "pending"))
+	for i := 0; i < 2; i++ {
+		if !s.begin() {
+			t.Fatal("expected the run to start")
+		}
+		if i == 0 && s.begin() {
+			t.Error("expected an overlapping run to be skipped")
+		}
+		s.execute(ctx)
+	}
+
+	_, out, err := toolScheduledResults(ctx, &mcp.CallToolRequest{}, ScheduledResultsInput{Name: "stuck_orders"})
+	if err != nil {
+		t.Fatalf("scheduled_results: %v", err)
+	}
+	if out.Schedule == nil || out.Schedule.Runs != 2 || out.Schedule.SkippedOverlaps != 1 || out.Schedule.LastError != "" {
+		t.Fatalf("unexpected schedule status: %+v", out.Schedule)
+	}
+	if out.Result == nil || len(out.Result.Rows) != 2 {
+		t.Errorf("unexpected latest result: %+v", out.Result)
+	}
+	if ch := out.Changes; ch == nil || len(ch.Added) != 1 || len(ch.Removed) != 1 || ch.Unchanged != 1 {
+		t.Errorf("unexpected changes: %+v", ch)
+	}
+
+	_, list, err := toolScheduledResults(ctx, &mcp.CallToolRequest{}, ScheduledResultsInput{})
+	if err != nil || len(list.Schedules) != 1 || list.Result != nil {
+		t.Errorf("unexpected listing: %+v, %v", list, err)
+	}
+	if _, _, err := toolScheduledResults(ctx, &mcp.CallToolRequest{}, ScheduledResultsInput{Name: "missing"}); err == nil {
+		t.Error("expected error for an unknown schedule")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestDiffScheduledRows(t *testing.T) {
+	before := QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{1}, {1}, {2}}}
+	after := QueryResult{Columns: []string{"id"}, Rows: [][]interface{}{{1}, {3}}}
+	ch := diffScheduledRows(before, after)
+	if ch.Unchanged != 1 || len(ch.Added) != 1 || len(ch.Removed) != 2 || ch.ColumnsChanged {
+		t.Errorf("unexpected changes: %+v", ch)
+	}
+
+	ch = diffScheduledRows(before, QueryResult{Columns: []string{"id", "name"}, Rows: [][]interface{}{{1, "a"}}})
+	if !ch.ColumnsChanged || len(ch.Added) != 1 || len(ch.Removed) != 3 {
+		t.Errorf("unexpected changes after a column change: %+v", ch)
+	}
+}
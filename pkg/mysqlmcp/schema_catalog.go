@@ -0,0 +1,122 @@
+// pkg/mysqlmcp/schema_catalog.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The schema catalog keeps the database, table and column names of the
+// active connection for schemaCatalogTTL, so identifier suggestions answer
+// from memory instead of querying information_schema on every keystroke.
+// Names are loaded one database at a time, with a single COLUMNS query.
+
+const schemaCatalogTTL = time.Minute
+
+// catalogTable is one table or view of a cached database with its columns
+// in ordinal order.
+type catalogTable struct {
+	name    string
+	columns []string
+}
+
+type catalogEntry struct {
+	names   []string       // database names, for the database list
+	tables  []catalogTable // for one database
+	fetched time.Time
+}
+
+// schemaCatalog holds names keyed by connection and lowercased database; the
+// database list of a connection is kept under an empty database.
+type schemaCatalog struct {
+	mu      sync.Mutex
+	entries map[string]catalogEntry
+}
+
+var identifierCatalog = &schemaCatalog{entries: map[string]catalogEntry{}}
+
+func schemaCatalogKey(database string) string {
+	return activeConnectionName() + "\x00" + strings.ToLower(database)
+}
+
+func (c *schemaCatalog) get(key string) (catalogEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Since(e.fetched) >= schemaCatalogTTL {
+		return catalogEntry{}, false
+	}
+	return e, true
+}
+
+func (c *schemaCatalog) put(key string, e catalogEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = e
+}
+
+// databases returns the names of the databases the caller may use.
+func (c *schemaCatalog) databases(ctx context.Context, db *sql.DB) ([]string, time.Time, error) {
+	key := schemaCatalogKey("")
+	if e, ok := c.get(key); ok {
+		return e.names, e.fetched, nil
+	}
+	rows, err := db.QueryContext(ctx, "SELECT SCHEMA_NAME FROM information_schema.SCHEMATA ORDER BY SCHEMA_NAME")
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read databases: %w", err)
+	}
+	defer rows.Close()
+	e := catalogEntry{names: []string{}, fetched: time.Now()}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to read databases: %w", err)
+		}
+		if databaseAllowed(name) {
+			e.names = append(e.names, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read databases: %w", err)
+	}
+	c.put(key, e)
+	return e.names, e.fetched, nil
+}
+
+// tables returns the tables and views of database with their columns.
+func (c *schemaCatalog) tables(ctx context.Context, db *sql.DB, database string) ([]catalogTable, time.Time, error) {
+	key := schemaCatalogKey(database)
+	if e, ok := c.get(key); ok {
+		return e.tables, e.fetched, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT TABLE_NAME, COLUMN_NAME
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`, database)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read columns of %s: %w", database, err)
+	}
+	defer rows.Close()
+	e := catalogEntry{tables: []catalogTable{}, fetched: time.Now()}
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to read columns of %s: %w", database, err)
+		}
+		if n := len(e.tables); n == 0 || e.tables[n-1].name != table {
+			e.tables = append(e.tables, catalogTable{name: table})
+		}
+		t := &e.tables[len(e.tables)-1]
+		t.columns = append(t.columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read columns of %s: %w", database, err)
+	}
+	c.put(key, e)
+	return e.tables, e.fetched, nil
+}
@@ -0,0 +1,73 @@
+// pkg/mysqlmcp/schema_scope.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/go-sql-driver/mysql"
+)
+
+// homeDatabase returns the default database in the DSN of the connection
+// whose pool is db, or "" when the DSN names none or cannot be parsed.
+func homeDatabase(db *sql.DB) string {
+	if connManager == nil {
+		return ""
+	}
+	c, ok := connManager.Config(connectionNameFor(db))
+	if !ok {
+		return ""
+	}
+	parsed, err := mysql.ParseDSN(c.DSN)
+	if err != nil {
+		return ""
+	}
+	return parsed.DBName
+}
+
+// useDatabase makes database the default schema of conn for one call. The
+// returned restore func must run before conn goes back to the pool: it
+// switches back to the DSN's default database so the next call on the pooled
+// connection does not inherit the schema. MySQL cannot clear a session's
+// default schema, so when the DSN names none (or switching back fails) the
+// connection is discarded instead.
+func useDatabase(ctx context.Context, db *sql.DB, conn *sql.Conn, database string) (restore func(), err error) {
+	home := homeDatabase(db)
+	if database == "" || database == home {
+		return func() {}, nil
+	}
+	if proxyCompat() {
+		// Callers qualify the statement with scopeStatement first.
+		return nil, proxySessionError("selecting database "+database+" with USE", "qualify the tables as "+database+".table")
+	}
+	quoted, err := util.QuoteIdent(database)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database name: %w", err)
+	}
+	restore = func() { resetDatabase(conn, home) }
+	if _, err := conn.ExecContext(ctx, "USE "+quoted); err != nil {
+		// The USE may have been applied before the error (e.g. a timeout).
+		restore()
+		return nil, fmt.Errorf("failed to select database '%s': %w", database, err)
+	}
+	return restore, nil
+}
+
+// resetDatabase switches conn back to home, or discards it from the pool.
+func resetDatabase(conn *sql.Conn, home string) {
+	if home != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+		if quoted, err := util.QuoteIdent(home); err == nil {
+			if _, err := conn.ExecContext(ctx, "USE "+quoted); err == nil {
+				return
+			}
+		}
+	}
+	// Returning driver.ErrBadConn from Raw makes database/sql close the
+	// connection instead of pooling it.
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
@@ -0,0 +1,119 @@
+// pkg/mysqlmcp/schema_scope_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+func TestUseDatabaseRestoresHome(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectRestoreDatabase(mock)
+
+	ctx := context.Background()
+	db := getDB()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	restore, err := useDatabase(ctx, db, conn, "shop")
+	if err != nil {
+		t.Fatalf("useDatabase failed: %v", err)
+	}
+	restore()
+	conn.Close()
+
+	// The connection went back to the pool and is reused.
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	if _, err := db.QueryContext(ctx, "SELECT 1"); err != nil {
+		t.Fatalf("pooled connection was not reusable: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUseDatabaseSkipsHome(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	db := getDB()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	for _, database := range []string{"", "mockdb"} {
+		restore, err := useDatabase(ctx, db, conn, database)
+		if err != nil {
+			t.Fatalf("useDatabase(%q) failed: %v", database, err)
+		}
+		restore()
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUseDatabaseDiscardsWithoutHome(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	connManager.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: "mock@tcp(mock:3306)/"}
+
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx := context.Background()
+	db := getDB()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	restore, err := useDatabase(ctx, db, conn, "shop")
+	if err != nil {
+		t.Fatalf("useDatabase failed: %v", err)
+	}
+	restore()
+	conn.Close()
+
+	if db.Stats().OpenConnections != 0 {
+		t.Errorf("expected the connection to be discarded, %d still open", db.Stats().OpenConnections)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUseDatabaseError(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectExec("USE `missing`").WillReturnError(errors.New("Unknown database 'missing'"))
+	expectRestoreDatabase(mock)
+
+	ctx := context.Background()
+	db := getDB()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := useDatabase(ctx, db, conn, "missing"); err == nil {
+		t.Fatal("expected an error for an unknown database")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,341 @@
+// pkg/mysqlmcp/schema_watch.go
+package mysqlmcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// The schema watcher snapshots the tables and columns of the databases listed
+// in schema_watch every interval and hashes them. When a hash changes, the
+// difference to the previous snapshot (tables and columns added, dropped or
+// redefined) is kept for schema_changes, sent to every connected MCP client
+// as a notifications/message log entry from the "schema_watch" logger, and
+// posted to schema_changed webhooks. Agents working against a schema that is
+// being migrated learn that their assumptions are stale instead of finding
+// out from failing queries. The first snapshot of a database on a connection
+// is the baseline and reports nothing; clients only receive the log entries
+// after setting a log level of notice or lower.
+
+// maxSchemaChanges caps the changes kept for schema_changes.
+const maxSchemaChanges = 200
+
+// schemaWatchQuery reads the column metadata one snapshot hashes.
+const schemaWatchQuery = `SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_DEFAULT
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = ?
+ORDER BY TABLE_NAME, COLUMN_NAME`
+
+// schemaSnapshot is the column definitions of one database, by table and column.
+type schemaSnapshot struct {
+	hash   string
+	tables map[string]map[string]string
+}
+
+// schemaWatcher keeps the latest snapshot of each watched database and the
+// changes found between snapshots.
+type schemaWatcher struct {
+	databases []string
+	interval  time.Duration
+
+	mu        sync.Mutex
+	snapshots map[string]*schemaSnapshot // connection + "\x00" + database
+	changes   []SchemaChange             // oldest first
+	lastCheck time.Time
+	lastError string
+	servers   []*mcp.Server // notified of changes
+}
+
+// schemaWatch is nil unless schema_watch lists databases.
+var schemaWatch *schemaWatcher
+
+func initSchemaWatch(c *config.Config) {
+	if len(c.SchemaWatchDatabases) == 0 {
+		schemaWatch = nil
+		return
+	}
+	interval := c.SchemaWatchInterval
+	if interval <= 0 {
+		interval = config.DefaultSchemaWatchS * time.Second
+	}
+	schemaWatch = &schemaWatcher{
+		databases: c.SchemaWatchDatabases,
+		interval:  interval,
+		snapshots: make(map[string]*schemaSnapshot),
+	}
+}
+
+// addServer sends future changes to the clients connected to server.
+func (w *schemaWatcher) addServer(server *mcp.Server) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.servers = append(w.servers, server)
+}
+
+// run checks the watched databases until ctx is canceled. Failures are logged
+// and checking continues.
+func (w *schemaWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.checkOnce(ctx); err != nil && ctx.Err() == nil {
+			serverLog.Warn("schema watch failed", map[string]interface{}{"error": err.Error()})
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkOnce snapshots every watched database on the active connection and
+// reports the ones whose hash changed. A database that fails to load keeps
+// its previous snapshot; the first error is returned after the others ran.
+func (w *schemaWatcher) checkOnce(ctx context.Context) error {
+	db, conn := connManager.GetActive()
+	if db == nil {
+		return nil
+	}
+	var firstErr error
+	for _, database := range w.databases {
+		snap, err := loadSchemaSnapshot(ctx, db, database)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", database, err)
+			}
+			continue
+		}
+		if change, ok := w.record(conn, database, snap, time.Now()); ok {
+			w.announce(ctx, change)
+		}
+	}
+	w.mu.Lock()
+	w.lastCheck = time.Now()
+	w.lastError = ""
+	if firstErr != nil {
+		w.lastError = firstErr.Error()
+	}
+	w.mu.Unlock()
+	return firstErr
+}
+
+// loadSchemaSnapshot reads and hashes the columns of database.
+func loadSchemaSnapshot(ctx context.Context, db *sql.DB, database string) (*schemaSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, schemaWatchQuery, database)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snap := &schemaSnapshot{tables: make(map[string]map[string]string)}
+	h := sha256.New()
+	for rows.Next() {
+		var table, column, colType, nullable string
+		var def sql.NullString
+		if err := rows.Scan(&table, &column, &colType, &nullable, &def); err != nil {
+			return nil, err
+		}
+		definition := colType
+		if nullable == "NO" {
+			definition += " NOT NULL"
+		}
+		if def.Valid {
+			definition += " DEFAULT " + strconv.Quote(def.String)
+		}
+		if snap.tables[table] == nil {
+			snap.tables[table] = make(map[string]string)
+		}
+		snap.tables[table][column] = definition
+		fmt.Fprintf(h, "%s\x00%s\x00%s\n", table, column, definition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	snap.hash = hex.EncodeToString(h.Sum(nil))
+	return snap, nil
+}
+
+// record makes snap the latest snapshot of database on conn and returns the
+// change from the previous one, if there was one and its hash differs.
+func (w *schemaWatcher) record(conn, database string, snap *schemaSnapshot, now time.Time) (SchemaChange, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	key := conn + "\x00" + database
+	prev := w.snapshots[key]
+	w.snapshots[key] = snap
+	if prev == nil || prev.hash == snap.hash {
+		return SchemaChange{}, false
+	}
+	change := diffSchemaSnapshots(prev, snap)
+	change.Time = now.UTC().Format(time.RFC3339)
+	change.Connection = conn
+	change.Database = database
+	change.Fingerprint = snap.hash
+	w.changes = append(w.changes, change)
+	if len(w.changes) > maxSchemaChanges {
+		w.changes = w.changes[len(w.changes)-maxSchemaChanges:]
+	}
+	return change, true
+}
+
+// diffSchemaSnapshots lists the tables and columns that differ between two
+// snapshots. Columns of added and dropped tables are not listed separately.
+func diffSchemaSnapshots(before, after *schemaSnapshot) SchemaChange {
+	var ch SchemaChange
+	for table, cols := range after.tables {
+		old, ok := before.tables[table]
+		if !ok {
+			ch.TablesAdded = append(ch.TablesAdded, table)
+			continue
+		}
+		for column, def := range cols {
+			switch oldDef, ok := old[column]; {
+			case !ok:
+				ch.ColumnsAdded = append(ch.ColumnsAdded, SchemaColumnChange{Table: table, Column: column, After: def})
+			case oldDef != def:
+				ch.ColumnsChanged = append(ch.ColumnsChanged, SchemaColumnChange{Table: table, Column: column, Before: oldDef, After: def})
+			}
+		}
+		for column, def := range old {
+			if _, ok := cols[column]; !ok {
+				ch.ColumnsDropped = append(ch.ColumnsDropped, SchemaColumnChange{Table: table, Column: column, Before: def})
+			}
+		}
+	}
+	for table := range before.tables {
+		if _, ok := after.tables[table]; !ok {
+			ch.TablesDropped = append(ch.TablesDropped, table)
+		}
+	}
+	sort.Strings(ch.TablesAdded)
+	sort.Strings(ch.TablesDropped)
+	for _, cols := range [][]SchemaColumnChange{ch.ColumnsAdded, ch.ColumnsDropped, ch.ColumnsChanged} {
+		sort.Slice(cols, func(i, j int) bool {
+			if cols[i].Table != cols[j].Table {
+				return cols[i].Table < cols[j].Table
+			}
+			return cols[i].Column < cols[j].Column
+		})
+	}
+	return ch
+}
+
+// summary is a one-line description of the change, e.g.
+// "shop: 1 table added, 2 columns changed".
+func (c SchemaChange) summary() string {
+	var parts []string
+	for _, p := range []struct {
+		n    int
+		what string
+	}{
+		{len(c.TablesAdded), "table added"},
+		{len(c.TablesDropped), "table dropped"},
+		{len(c.ColumnsAdded), "column added"},
+		{len(c.ColumnsDropped), "column dropped"},
+		{len(c.ColumnsChanged), "column changed"},
+	} {
+		if p.n == 0 {
+			continue
+		}
+		noun, verb, _ := strings.Cut(p.what, " ")
+		if p.n > 1 {
+			noun += "s"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s %s", p.n, noun, verb))
+	}
+	return c.Database + ": " + strings.Join(parts, ", ")
+}
+
+// announce logs change, sends it to the MCP clients and queues the
+// schema_changed webhook.
+func (w *schemaWatcher) announce(ctx context.Context, change SchemaChange) {
+	summary := change.summary()
+	serverLog.Info("schema change detected", map[string]interface{}{
+		"connection": change.Connection,
+		"database":   change.Database,
+		"changes":    summary,
+	})
+
+	w.mu.Lock()
+	servers := append([]*mcp.Server(nil), w.servers...)
+	w.mu.Unlock()
+	for _, server := range servers {
+		for ss := range server.Sessions() {
+			if err := ss.Log(ctx, &mcp.LoggingMessageParams{Level: "notice", Logger: "schema_watch", Data: change}); err != nil {
+				serverLog.Debug("schema change notification failed", map[string]interface{}{"error": err.Error()})
+			}
+		}
+	}
+
+	if webhooks != nil {
+		webhooks.notify(WebhookEvent{
+			Event:      config.WebhookEventSchemaChanged,
+			Connection: change.Connection,
+			Database:   change.Database,
+			Text:       fmt.Sprintf("mysql-mcp-server: schema changed on %s: %s", change.Connection, summary),
+			Schema:     &change,
+		})
+	}
+}
+
+func toolSchemaChanges(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SchemaChangesInput,
+) (*mcp.CallToolResult, SchemaChangesOutput, error) {
+	w := schemaWatch
+	if w == nil {
+		return nil, SchemaChangesOutput{}, fmt.Errorf("schema watching is not enabled (set schema_watch.databases or MYSQL_MCP_SCHEMA_WATCH)")
+	}
+	database := strings.TrimSpace(input.Database)
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, SchemaChangesOutput{}, err
+		}
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	out := SchemaChangesOutput{
+		Databases:       []string{},
+		IntervalSeconds: int(w.interval.Seconds()),
+		Changes:         []SchemaChange{},
+	}
+	for _, db := range w.databases {
+		if databaseAllowed(db) {
+			out.Databases = append(out.Databases, db)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.lastCheck.IsZero() {
+		out.LastCheck = w.lastCheck.UTC().Format(time.RFC3339)
+	}
+	out.LastError = w.lastError
+	for i := len(w.changes) - 1; i >= 0 && len(out.Changes) < limit; i-- {
+		c := w.changes[i]
+		if (database == "" || c.Database == database) && databaseAllowed(c.Database) {
+			out.Changes = append(out.Changes, c)
+		}
+	}
+	return nil, out, nil
+}
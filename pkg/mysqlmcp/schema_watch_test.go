@@ -0,0 +1,117 @@
+// pkg/mysqlmcp/schema_watch_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSchemaWatch(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldWatch := schemaWatch
+	defer func() { schemaWatch = oldWatch }()
+	initSchemaWatch(&config.Config{SchemaWatchDatabases: []string{"shop"}, SchemaWatchInterval: time.Minute})
+	n := withWebhooks(t, config.Webhook{URL: "https://hooks.example.com/x"})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0"}, nil)
+	schemaWatch.addServer(server)
+	st, ct := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(context.Background(), st, nil); err != nil {
+		t.Fatal(err)
+	}
+	logged := make(chan *mcp.LoggingMessageParams, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "agent", Version: "0"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) { logged <- req.Params },
+	})
+	cs, err := client.Connect(context.Background(), ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if err := cs.SetLoggingLevel(context.Background(), &mcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []string{"TABLE_NAME", "COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_DEFAULT"}
+	mock.ExpectQuery("FROM information_schema.COLUMNS").WithArgs("shop").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("orders", "id", "int", "NO", nil).
+		AddRow("orders", "note", "varchar(64)", "YES", nil).
+		AddRow("legacy", "id", "int", "NO", nil))
+	mock.ExpectQuery("FROM information_schema.COLUMNS").WithArgs("shop").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow("orders", "id", "bigint", "NO", nil).
+		AddRow("orders", "status", "varchar(16)", "NO", "new").
+		AddRow("refunds", "id", "int", "NO", nil))
+
+	ctx := context.Background()
+	if err := schemaWatch.checkOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(schemaWatch.changes) != 0 || len(n.queue) != 0 {
+		t.Fatalf("the first snapshot should only be the baseline: %+v", schemaWatch.changes)
+	}
+	if err := schemaWatch.checkOnce(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	_, out, err := toolSchemaChanges(ctx, &mcp.CallToolRequest{}, SchemaChangesInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Changes) != 1 || out.IntervalSeconds != 60 || out.LastCheck == "" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	got, _ := json.Marshal(out.Changes[0])
+	var change map[string]interface{}
+	_ = json.Unmarshal(got, &change)
+	delete(change, "time")
+	delete(change, "fingerprint")
+	got, _ = json.Marshal(change)
+	want := `{"columns_added":[{"after":"varchar(16) NOT NULL DEFAULT \"new\"","column":"status","table":"orders"}],` +
+		`"columns_changed":[{"after":"bigint NOT NULL","before":"int NOT NULL","column":"id","table":"orders"}],` +
+		`"columns_dropped":[{"before":"varchar(64)","column":"note","table":"orders"}],` +
+		`"connection":"mock","database":"shop","tables_added":["refunds"],"tables_dropped":["legacy"]}`
+	if string(got) != want {
+		t.Errorf("unexpected change:\n got %s\nwant %s", got, want)
+	}
+	if s := out.Changes[0].summary(); s != "shop: 1 table added, 1 table dropped, 1 column added, 1 column dropped, 1 column changed" {
+		t.Errorf("unexpected summary %q", s)
+	}
+
+	if len(n.queue) != 1 {
+		t.Fatalf("expected one webhook event, got %d", len(n.queue))
+	}
+	if ev := <-n.queue; ev.Event != config.WebhookEventSchemaChanged || ev.Database != "shop" || ev.Schema == nil {
+		t.Errorf("unexpected webhook event: %+v", ev)
+	}
+	select {
+	case params := <-logged:
+		if params.Logger != "schema_watch" || params.Level != "notice" {
+			t.Errorf("unexpected notification: %+v", params)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("expected the client to be notified")
+	}
+
+	if _, out, _ := toolSchemaChanges(ctx, &mcp.CallToolRequest{}, SchemaChangesInput{Database: "billing"}); len(out.Changes) != 0 {
+		t.Errorf("expected no changes for another database: %+v", out.Changes)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSchemaChangesDisabled(t *testing.T) {
+	oldWatch := schemaWatch
+	defer func() { schemaWatch = oldWatch }()
+	schemaWatch = nil
+	if _, _, err := toolSchemaChanges(context.Background(), &mcp.CallToolRequest{}, SchemaChangesInput{}); err == nil {
+		t.Error("expected an error when schema watching is off")
+	}
+}
@@ -0,0 +1,173 @@
+// pkg/mysqlmcp/server.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// DBProvider supplies the connection pools a Server runs its tools on, in
+// place of the connections in the configuration.
+type DBProvider interface {
+	// Connections returns the pools by name; the first one starts active.
+	// The Server does not close them.
+	Connections() ([]Connection, error)
+}
+
+// Connection is a named pool handed to a Server by a DBProvider.
+type Connection struct {
+	Name        string
+	DB          *sql.DB
+	DSN         string // optional; gives the default database and the MySQL user in audit entries
+	Description string // shown by list_connections
+}
+
+// Auditor receives the audit entry of every audited tool call, with the
+// request ID, client identity and connection filled in.
+type Auditor interface {
+	Audit(entry *AuditEntry)
+}
+
+// Options configure New. The zero value behaves like the binary: the
+// configured connections and audit log are opened.
+type Options struct {
+	// ConfigFile is the config file to load; empty searches the usual
+	// locations (MYSQL_MCP_CONFIG, ./mysql-mcp-server.yaml, ...). It only
+	// applies to this call of New.
+	ConfigFile string
+
+	// DB supplies the connection pools. With a DB provider no MYSQL_DSN
+	// or configured connection is needed.
+	DB DBProvider
+
+	// Auditor receives audit entries instead of the MYSQL_MCP_AUDIT_LOG file.
+	Auditor Auditor
+}
+
+// Server is the MySQL MCP tool surface, ready to be served over an MCP
+// transport. Its state is shared by the whole process, so only one Server
+// can be open at a time (see the package documentation).
+type Server struct {
+	mcp    *mcp.Server
+	stop   context.CancelFunc
+	closed atomic.Bool
+}
+
+// serverOpen is set while a Server is open; the tools keep their state in
+// package variables, so only one can be.
+var serverOpen atomic.Bool
+
+var errServerOpen = errors.New("mysqlmcp: a Server is already open in this process")
+
+// New loads the configuration, opens the connections and builds the MCP
+// server with every tool the configuration enables. Close releases it. New
+// fails while another Server is open in the process.
+func New(opts Options) (*Server, error) {
+	if !serverOpen.CompareAndSwap(false, true) {
+		return nil, errServerOpen
+	}
+	s := &Server{stop: func() {}}
+	if err := s.init(opts); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Server) init(opts Options) error {
+	if opts.ConfigFile != "" {
+		defer func(path string) { config.ConfigFilePath = path }(config.ConfigFilePath)
+		config.ConfigFilePath = opts.ConfigFile
+	}
+
+	var conns []Connection
+	var loaded *config.Config
+	var err error
+	if opts.DB != nil {
+		if conns, err = opts.DB.Connections(); err != nil {
+			return fmt.Errorf("db provider: %w", err)
+		}
+		if len(conns) == 0 {
+			return fmt.Errorf("db provider returned no connections")
+		}
+		if loaded, err = config.LoadSettings(); err != nil {
+			return err
+		}
+		loaded.Connections = make([]config.ConnectionConfig, 0, len(conns))
+		for _, c := range conns {
+			if c.Name == "" || c.DB == nil {
+				return fmt.Errorf("db provider returned a connection without a name or pool")
+			}
+			loaded.Connections = append(loaded.Connections, config.ConnectionConfig{Name: c.Name, DSN: c.DSN, Description: c.Description})
+		}
+	} else if loaded, err = config.Load(); err != nil {
+		return err
+	}
+	if err := initRuntimeConfig(loaded); err != nil {
+		return err
+	}
+	if err := openAuditLog(opts.Auditor); err != nil {
+		return err
+	}
+	initTokenEstimator()
+
+	if opts.DB != nil {
+		connManager = NewConnectionManager()
+		for i, c := range conns {
+			connManager.addBorrowed(loaded.Connections[i], c.DB)
+		}
+	} else {
+		openConnections()
+	}
+	if connManager.GetActiveDB() == nil {
+		return fmt.Errorf("no valid MySQL connections available")
+	}
+	if cfg.VirtualConnection != "" {
+		if _, err := enterVirtualConnection(cfg.VirtualConnection); err != nil {
+			return fmt.Errorf("virtual connection %q: %w", cfg.VirtualConnection, err)
+		}
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	s.stop = stop
+	startBackgroundTasks(ctx)
+
+	s.mcp = newMCPServer()
+	readiness.markStarted()
+	return nil
+}
+
+// MCPServer returns the MCP server with the tools and prompts, to serve over
+// a transport of the caller's choice, e.g. mcp.NewStreamableHTTPHandler.
+func (s *Server) MCPServer() *mcp.Server {
+	return s.mcp
+}
+
+// Run serves the tools over t until the client disconnects or ctx is done.
+func (s *Server) Run(ctx context.Context, t mcp.Transport) error {
+	return s.mcp.Run(ctx, t)
+}
+
+// Close stops the background tasks and closes the connections the Server
+// opened and its audit log. Pools from a DBProvider stay open. Calling Close
+// again does nothing, so it never releases a Server opened afterwards.
+func (s *Server) Close() {
+	if !s.closed.CompareAndSwap(false, true) {
+		return
+	}
+	s.stop()
+	if connManager != nil {
+		connManager.Close()
+	}
+	if auditLogger != nil {
+		auditLogger.Close()
+	}
+	serverOpen.Store(false)
+}
@@ -0,0 +1,135 @@
+// pkg/mysqlmcp/server_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+type staticDBProvider []Connection
+
+func (p staticDBProvider) Connections() ([]Connection, error) { return p, nil }
+
+type recordingAuditor struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+func (a *recordingAuditor) Audit(entry *AuditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, *entry)
+}
+
+// keepServerGlobals restores the package state New replaces.
+func keepServerGlobals(t *testing.T) {
+	oldCfg, oldConnManager, oldAuditLogger, oldReadiness := cfg, connManager, auditLogger, readiness
+	oldMaxRows, oldMaxResultBytes, oldBinaryOutput := maxRows, maxResultBytes, binaryOutput
+	oldQueryTimeout, oldPingTimeout, oldDBRetryCfg := queryTimeout, pingTimeout, dbRetryCfg
+	oldExtended, oldJSONLogging, oldTokenTracking, oldTokenModel := extendedMode, jsonLogging, tokenTracking, tokenModel
+	readiness = &readinessState{subsystems: map[string]string{}}
+	t.Cleanup(func() {
+		cfg, connManager, auditLogger, readiness = oldCfg, oldConnManager, oldAuditLogger, oldReadiness
+		maxRows, maxResultBytes, binaryOutput = oldMaxRows, oldMaxResultBytes, oldBinaryOutput
+		queryTimeout, pingTimeout, dbRetryCfg = oldQueryTimeout, oldPingTimeout, oldDBRetryCfg
+		extendedMode, jsonLogging, tokenTracking, tokenModel = oldExtended, oldJSONLogging, oldTokenTracking, oldTokenModel
+	})
+}
+
+func TestServerWithDBProvider(t *testing.T) {
+	t.Setenv("MYSQL_MCP_PREPARED_STATEMENTS", "0")
+	keepServerGlobals(t)
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	auditor := &recordingAuditor{}
+	s, err := New(Options{
+		DB:      staticDBProvider{{Name: "app", DB: db, DSN: "app@tcp(db:3306)/shop"}},
+		Auditor: auditor,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := New(Options{}); !errors.Is(err, errServerOpen) {
+		t.Errorf("second New: got %v, want errServerOpen", err)
+	}
+
+	ctx := context.Background()
+	session, err := connectCLIClient(ctx, s.MCPServer())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock.ExpectQuery(`SELECT .*id FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	res, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "run_query", Arguments: map[string]any{"sql": "SELECT id FROM users"}})
+	if err != nil || res.IsError {
+		t.Fatalf("run_query: err=%v result=%+v", err, res)
+	}
+	session.Close()
+	s.Close()
+	s.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Errorf("Close closed the provider's pool: %v", err)
+	}
+	if len(auditor.entries) != 1 || auditor.entries[0].Tool != "run_query" || auditor.entries[0].Connection != "app" || !auditor.entries[0].Success {
+		t.Errorf("unexpected audit entries: %+v", auditor.entries)
+	}
+
+	// The Server is closed, so another one can be opened.
+	if _, err := New(Options{DB: staticDBProvider{}}); err == nil || errors.Is(err, errServerOpen) {
+		t.Errorf("expected the empty provider to be refused, got %v", err)
+	}
+}
+
+func TestServerCloseReleasesOnlyItself(t *testing.T) {
+	t.Setenv("MYSQL_MCP_PREPARED_STATEMENTS", "0")
+	keepServerGlobals(t)
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	provider := staticDBProvider{{Name: "app", DB: db}}
+	dir := t.TempDir()
+	binaryFile, embeddedFile := filepath.Join(dir, "binary.yaml"), filepath.Join(dir, "embedded.yaml")
+	for _, file := range []string{binaryFile, embeddedFile} {
+		if err := os.WriteFile(file, []byte("{}\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	oldPath := config.ConfigFilePath
+	defer func() { config.ConfigFilePath = oldPath }()
+	config.ConfigFilePath = binaryFile
+
+	first, err := New(Options{DB: provider, ConfigFile: embeddedFile, Auditor: &recordingAuditor{}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if config.ConfigFilePath != binaryFile {
+		t.Errorf("New left ConfigFilePath at %q", config.ConfigFilePath)
+	}
+	first.Close()
+
+	second, err := New(Options{DB: provider, Auditor: &recordingAuditor{}})
+	if err != nil {
+		t.Fatalf("New after Close: %v", err)
+	}
+	defer second.Close()
+	first.Close()
+	if _, err := New(Options{DB: provider}); !errors.Is(err, errServerOpen) {
+		t.Errorf("a repeated Close of the first Server released the second: %v", err)
+	}
+}
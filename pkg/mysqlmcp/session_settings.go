@@ -0,0 +1,321 @@
+// pkg/mysqlmcp/session_settings.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Query tools run on pooled connections, so a plain SET would only reach one
+// of them. set_session_setting instead records overrides for the active
+// connection, and the query and EXPLAIN tools apply them to their connection
+// for one call and put the previous values back afterwards, like time_zone.
+// Only settings that change how reads are checked or planned are offered:
+// sql_mode modes from sqlModeAllowed and optimizer_switch flags.
+
+// sqlModeAllowed lists the modes set_session_setting may add to or remove
+// from sql_mode. ANSI_QUOTES, PIPES_AS_CONCAT and NO_BACKSLASH_ESCAPES are
+// left out: they change how MySQL reads the statement text, so the server
+// would no longer run the statement the validator checked.
+var sqlModeAllowed = map[string]bool{
+	"ONLY_FULL_GROUP_BY":         true,
+	"STRICT_TRANS_TABLES":        true,
+	"STRICT_ALL_TABLES":          true,
+	"ERROR_FOR_DIVISION_BY_ZERO": true,
+	"NO_ZERO_DATE":               true,
+	"NO_ZERO_IN_DATE":            true,
+	"NO_ENGINE_SUBSTITUTION":     true,
+	"REAL_AS_FLOAT":              true,
+	"PAD_CHAR_TO_FULL_LENGTH":    true,
+	"TIME_TRUNCATE_FRACTIONAL":   true,
+	"IGNORE_SPACE":               true,
+	"HIGH_NOT_PRECEDENCE":        true,
+}
+
+var optimizerFlagPattern = regexp.MustCompile(`^[a-z_]{1,64}$`)
+
+// sessionOverrides are the settings applied on top of a connection's
+// session defaults.
+type sessionOverrides struct {
+	sqlModeAdd      []string
+	sqlModeRemove   []string
+	optimizerSwitch map[string]string // flag -> on/off
+}
+
+func (o sessionOverrides) empty() bool {
+	return len(o.sqlModeAdd) == 0 && len(o.sqlModeRemove) == 0 && len(o.optimizerSwitch) == 0
+}
+
+func (o sessionOverrides) clone() sessionOverrides {
+	return sessionOverrides{
+		sqlModeAdd:      slices.Clone(o.sqlModeAdd),
+		sqlModeRemove:   slices.Clone(o.sqlModeRemove),
+		optimizerSwitch: maps.Clone(o.optimizerSwitch),
+	}
+}
+
+// sqlMode returns current with the overrides applied.
+func (o sessionOverrides) sqlMode(current string) string {
+	modes := []string{}
+	have := make(map[string]bool)
+	for _, m := range strings.Split(current, ",") {
+		if m = strings.TrimSpace(m); m == "" || slices.Contains(o.sqlModeRemove, m) {
+			continue
+		}
+		modes = append(modes, m)
+		have[m] = true
+	}
+	for _, m := range o.sqlModeAdd {
+		if !have[m] {
+			modes = append(modes, m)
+		}
+	}
+	return strings.Join(modes, ",")
+}
+
+// optimizerSwitchValue returns the flags as an optimizer_switch assignment;
+// flags not listed keep their value.
+func (o sessionOverrides) optimizerSwitchValue() string {
+	flags := make([]string, 0, len(o.optimizerSwitch))
+	for flag, v := range o.optimizerSwitch {
+		flags = append(flags, flag+"="+v)
+	}
+	sort.Strings(flags)
+	return strings.Join(flags, ",")
+}
+
+func (o sessionOverrides) info() SessionOverrideInfo {
+	return SessionOverrideInfo{
+		SQLModeAdded:    o.sqlModeAdd,
+		SQLModeRemoved:  o.sqlModeRemove,
+		OptimizerSwitch: o.optimizerSwitch,
+	}
+}
+
+var (
+	sessionOverridesMu     sync.Mutex
+	sessionOverridesByConn = make(map[string]sessionOverrides)
+)
+
+func activeConnectionName() string {
+	if connManager == nil {
+		return ""
+	}
+	_, name := connManager.GetActive()
+	return name
+}
+
+// activeSessionOverrides returns the overrides of the active connection.
+func activeSessionOverrides() sessionOverrides {
+	sessionOverridesMu.Lock()
+	defer sessionOverridesMu.Unlock()
+	return sessionOverridesByConn[activeConnectionName()].clone()
+}
+
+func setSessionOverrides(name string, o sessionOverrides) {
+	sessionOverridesMu.Lock()
+	defer sessionOverridesMu.Unlock()
+	if o.empty() {
+		delete(sessionOverridesByConn, name)
+		return
+	}
+	sessionOverridesByConn[name] = o
+}
+
+// useSessionSettings applies the overrides of the active connection to conn
+// for one call. Like useTimeZone, the returned restore func must run before
+// conn goes back to the pool; it puts back the previous values, or discards
+// the connection when that fails.
+func useSessionSettings(ctx context.Context, conn *sql.Conn) (restore func(), err error) {
+	o := activeSessionOverrides()
+	if o.empty() {
+		return func() {}, nil
+	}
+	if proxyCompat() {
+		return nil, proxySessionError("set_session_setting overrides", "reset them with set_session_setting")
+	}
+	var sqlMode, optimizerSwitch string
+	if err := conn.QueryRowContext(ctx, "SELECT @@session.sql_mode, @@session.optimizer_switch").Scan(&sqlMode, &optimizerSwitch); err != nil {
+		return nil, fmt.Errorf("failed to read session settings: %w", err)
+	}
+	restore = func() { resetSessionSettings(conn, sqlMode, optimizerSwitch) }
+
+	stmt := "SET SESSION sql_mode = ?"
+	args := []interface{}{o.sqlMode(sqlMode)}
+	if len(o.optimizerSwitch) > 0 {
+		stmt += ", SESSION optimizer_switch = ?"
+		args = append(args, o.optimizerSwitchValue())
+	}
+	if _, err := conn.ExecContext(ctx, stmt, args...); err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to apply session settings: %w", err)
+	}
+	return restore, nil
+}
+
+// resetSessionSettings puts back the previous sql_mode and optimizer_switch
+// of conn, or discards it from the pool.
+func resetSessionSettings(conn *sql.Conn, sqlMode, optimizerSwitch string) {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if _, err := conn.ExecContext(ctx, "SET SESSION sql_mode = ?, SESSION optimizer_switch = ?", sqlMode, optimizerSwitch); err == nil {
+		return
+	}
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
+
+// parseOptimizerSwitch splits an optimizer_switch value into its flags.
+func parseOptimizerSwitch(value string) map[string]string {
+	flags := make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		if flag, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			flags[flag] = v
+		}
+	}
+	return flags
+}
+
+// readSessionSettings returns the settings a query tool on the active
+// connection runs with.
+func readSessionSettings(ctx context.Context) (SessionSettingsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	conn, err := getDB().Conn(ctx)
+	if err != nil {
+		return SessionSettingsOutput{}, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+	restore, err := useSessionSettings(ctx, conn)
+	if err != nil {
+		return SessionSettingsOutput{}, err
+	}
+	defer restore()
+
+	isolation := "@@session.transaction_isolation"
+	if getServerType() == ServerTypeMariaDB {
+		isolation = "@@session.tx_isolation"
+	}
+	var sqlMode, optimizerSwitch, level string
+	if err := conn.QueryRowContext(ctx, "SELECT @@session.sql_mode, @@session.optimizer_switch, "+isolation).Scan(&sqlMode, &optimizerSwitch, &level); err != nil {
+		return SessionSettingsOutput{}, fmt.Errorf("failed to read session settings: %w", err)
+	}
+	return SessionSettingsOutput{
+		Connection:           activeConnectionName(),
+		SQLMode:              sqlMode,
+		OptimizerSwitch:      parseOptimizerSwitch(optimizerSwitch),
+		TransactionIsolation: level,
+		Overrides:            activeSessionOverrides().info(),
+	}, nil
+}
+
+func toolSessionSettings(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SessionSettingsInput,
+) (*mcp.CallToolResult, SessionSettingsOutput, error) {
+	out, err := readSessionSettings(ctx)
+	if err != nil {
+		return nil, SessionSettingsOutput{}, err
+	}
+	return nil, out, nil
+}
+
+func toolSetSessionSetting(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SetSessionSettingInput,
+) (*mcp.CallToolResult, SessionSettingsOutput, error) {
+	if proxyCompat() && !input.Reset {
+		return nil, SessionSettingsOutput{}, proxySessionError("set_session_setting", "add the setting to the proxy's or the server's defaults")
+	}
+	name := activeConnectionName()
+	previous := activeSessionOverrides()
+	o := previous.clone()
+
+	switch input.Setting {
+	case "sql_mode":
+		if input.Reset {
+			o.sqlModeAdd, o.sqlModeRemove = nil, nil
+		} else if len(input.Add) == 0 && len(input.Remove) == 0 {
+			return nil, SessionSettingsOutput{}, fmt.Errorf("add or remove is required for sql_mode")
+		}
+		for _, m := range input.Add {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if !sqlModeAllowed[m] {
+				return nil, SessionSettingsOutput{}, fmt.Errorf("sql_mode %s cannot be changed here; allowed modes: %s", m, strings.Join(slices.Sorted(maps.Keys(sqlModeAllowed)), ", "))
+			}
+			o.sqlModeRemove = removeString(o.sqlModeRemove, m)
+			if !slices.Contains(o.sqlModeAdd, m) {
+				o.sqlModeAdd = append(o.sqlModeAdd, m)
+			}
+		}
+		for _, m := range input.Remove {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if !sqlModeAllowed[m] {
+				return nil, SessionSettingsOutput{}, fmt.Errorf("sql_mode %s cannot be changed here; allowed modes: %s", m, strings.Join(slices.Sorted(maps.Keys(sqlModeAllowed)), ", "))
+			}
+			o.sqlModeAdd = removeString(o.sqlModeAdd, m)
+			if !slices.Contains(o.sqlModeRemove, m) {
+				o.sqlModeRemove = append(o.sqlModeRemove, m)
+			}
+		}
+		sort.Strings(o.sqlModeAdd)
+		sort.Strings(o.sqlModeRemove)
+
+	case "optimizer_switch":
+		if input.Reset {
+			o.optimizerSwitch = nil
+		} else if len(input.Flags) == 0 {
+			return nil, SessionSettingsOutput{}, fmt.Errorf("flags is required for optimizer_switch")
+		}
+		if len(input.Flags) > 0 {
+			var current string
+			if err := getDB().QueryRowContext(ctx, "SELECT @@session.optimizer_switch").Scan(&current); err != nil {
+				return nil, SessionSettingsOutput{}, fmt.Errorf("failed to read optimizer_switch: %w", err)
+			}
+			known := parseOptimizerSwitch(current)
+			if o.optimizerSwitch == nil {
+				o.optimizerSwitch = make(map[string]string, len(input.Flags))
+			}
+			for flag, v := range input.Flags {
+				flag = strings.ToLower(strings.TrimSpace(flag))
+				v = strings.ToLower(strings.TrimSpace(v))
+				if _, ok := known[flag]; !ok || !optimizerFlagPattern.MatchString(flag) {
+					return nil, SessionSettingsOutput{}, fmt.Errorf("unknown optimizer_switch flag %q", flag)
+				}
+				if v != "on" && v != "off" {
+					return nil, SessionSettingsOutput{}, fmt.Errorf("optimizer_switch flag %s must be on or off", flag)
+				}
+				o.optimizerSwitch[flag] = v
+			}
+		}
+
+	default:
+		return nil, SessionSettingsOutput{}, fmt.Errorf("setting must be sql_mode or optimizer_switch")
+	}
+
+	setSessionOverrides(name, o)
+	// Reading back applies the overrides once, so the server rejects a bad
+	// combination here rather than in the next query.
+	out, err := readSessionSettings(ctx)
+	if err != nil {
+		setSessionOverrides(name, previous)
+		return nil, SessionSettingsOutput{}, err
+	}
+	return nil, out, nil
+}
+
+func removeString(list []string, s string) []string {
+	return slices.DeleteFunc(slices.Clone(list), func(v string) bool { return v == s })
+}
@@ -0,0 +1,93 @@
+// pkg/mysqlmcp/session_settings_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSessionOverridesSQLMode(t *testing.T) {
+	o := sessionOverrides{sqlModeAdd: []string{"ONLY_FULL_GROUP_BY"}, sqlModeRemove: []string{"STRICT_TRANS_TABLES"}}
+	if got := o.sqlMode("STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION"); got != "NO_ENGINE_SUBSTITUTION,ONLY_FULL_GROUP_BY" {
+		t.Errorf("sqlMode = %q", got)
+	}
+	if got := o.sqlMode("ONLY_FULL_GROUP_BY"); got != "ONLY_FULL_GROUP_BY" {
+		t.Errorf("sqlMode = %q", got)
+	}
+	o.optimizerSwitch = map[string]string{"mrr": "on", "hash_join": "off"}
+	if got := o.optimizerSwitchValue(); got != "hash_join=off,mrr=on" {
+		t.Errorf("optimizerSwitchValue = %q", got)
+	}
+}
+
+func TestSetSessionSetting(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	t.Cleanup(func() { sessionOverridesByConn = make(map[string]sessionOverrides) })
+	ctx := context.Background()
+
+	const defaults = "index_merge=on,mrr=on,hash_join=on"
+	mock.ExpectQuery(`SELECT @@session.optimizer_switch`).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(defaults))
+	mock.ExpectQuery(`SELECT @@session.sql_mode, @@session.optimizer_switch$`).
+		WillReturnRows(sqlmock.NewRows([]string{"m", "o"}).AddRow("STRICT_TRANS_TABLES", defaults))
+	mock.ExpectExec(`SET SESSION sql_mode = \?, SESSION optimizer_switch = \?`).
+		WithArgs("STRICT_TRANS_TABLES", "hash_join=off").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT @@session.sql_mode, @@session.optimizer_switch, @@session.transaction_isolation`).
+		WillReturnRows(sqlmock.NewRows([]string{"m", "o", "i"}).AddRow("STRICT_TRANS_TABLES", "index_merge=on,mrr=on,hash_join=off", "REPEATABLE-READ"))
+	mock.ExpectExec(`SET SESSION sql_mode = \?, SESSION optimizer_switch = \?`).
+		WithArgs("STRICT_TRANS_TABLES", defaults).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, out, err := toolSetSessionSetting(ctx, &mcp.CallToolRequest{}, SetSessionSettingInput{
+		Setting: "optimizer_switch",
+		Flags:   map[string]string{"hash_join": "OFF"},
+	})
+	if err != nil {
+		t.Fatalf("set_session_setting failed: %v", err)
+	}
+	if out.Connection != "mock" || out.OptimizerSwitch["hash_join"] != "off" || out.Overrides.OptimizerSwitch["hash_join"] != "off" || out.TransactionIsolation != "REPEATABLE-READ" {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	// Later queries run with the override and restore the defaults.
+	mock.ExpectQuery(`SELECT @@session.sql_mode, @@session.optimizer_switch$`).
+		WillReturnRows(sqlmock.NewRows([]string{"m", "o"}).AddRow("STRICT_TRANS_TABLES", defaults))
+	mock.ExpectExec(`SET SESSION sql_mode = \?, SESSION optimizer_switch = \?`).
+		WithArgs("STRICT_TRANS_TABLES", "hash_join=off").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectExec(`SET SESSION sql_mode = \?, SESSION optimizer_switch = \?`).
+		WithArgs("STRICT_TRANS_TABLES", defaults).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM users"}); err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT @@session.optimizer_switch`).
+		WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow(defaults))
+	if _, _, err := toolSetSessionSetting(ctx, &mcp.CallToolRequest{}, SetSessionSettingInput{
+		Setting: "optimizer_switch",
+		Flags:   map[string]string{"no_such_flag": "on"},
+	}); err == nil || !strings.Contains(err.Error(), "unknown optimizer_switch flag") {
+		t.Errorf("expected unknown flag error, got %v", err)
+	}
+	if _, _, err := toolSetSessionSetting(ctx, &mcp.CallToolRequest{}, SetSessionSettingInput{
+		Setting: "sql_mode",
+		Add:     []string{"ansi_quotes"},
+	}); err == nil || !strings.Contains(err.Error(), "ANSI_QUOTES cannot be changed") {
+		t.Errorf("expected ANSI_QUOTES to be refused, got %v", err)
+	}
+	if o := activeSessionOverrides(); len(o.optimizerSwitch) != 1 || len(o.sqlModeAdd) != 0 {
+		t.Errorf("rejected calls changed the overrides: %+v", o)
+	}
+}
@@ -0,0 +1,172 @@
+// pkg/mysqlmcp/stmt_cache.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Fixed metadata queries issued by the hot schema tools. Keeping the text in one
+// place lets the warm-up phase prepare exactly what the handlers later execute.
+const (
+	queryListViews = `SELECT TABLE_NAME, DEFINER, SECURITY_TYPE, IS_UPDATABLE
+		FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ?`
+	queryListTriggers = `SELECT TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_TABLE, ACTION_TIMING,
+		LEFT(ACTION_STATEMENT, 200) FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ?`
+	queryListProcedures = `SELECT ROUTINE_NAME, DEFINER, CREATED, LAST_ALTERED,
+		IFNULL(PARAMETER_STYLE, '') FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE'`
+	queryListFunctions = `SELECT ROUTINE_NAME, DEFINER, DTD_IDENTIFIER, CREATED
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION'`
+	queryListPartitions = `SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION,
+		PARTITION_DESCRIPTION, TABLE_ROWS, DATA_LENGTH
+		FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL`
+	queryDescribeTable = `SELECT
+				COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY,
+				COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, COLLATION_NAME, GENERATION_EXPRESSION
+			  FROM information_schema.COLUMNS
+			  WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+			  ORDER BY ORDINAL_POSITION`
+	querySchemaExists = "SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ? LIMIT 1"
+	queryTableExists  = "SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? LIMIT 1"
+)
+
+// hotQueries are prepared on every connection during startup warm-up.
+var hotQueries = []string{
+	queryListViews, queryListTriggers, queryListProcedures, queryListFunctions,
+	queryListPartitions, queryDescribeTable, querySchemaExists, queryTableExists,
+}
+
+// stmtCache keeps one prepared statement per (pool, query). database/sql
+// re-prepares a Stmt transparently on whichever pooled connection runs it, so
+// each physical connection pays the prepare round trip once instead of on
+// every call (the MySQL driver otherwise prepares, executes and closes a
+// statement for each parameterized query).
+type stmtCache struct {
+	mu    sync.Mutex
+	stmts map[*sql.DB]map[string]*sql.Stmt
+}
+
+func newStmtCache() *stmtCache {
+	return &stmtCache{stmts: make(map[*sql.DB]map[string]*sql.Stmt)}
+}
+
+// preparedStmts is shared by all handlers; entries are dropped when the
+// ConnectionManager replaces or closes a pool.
+var preparedStmts = newStmtCache()
+
+// get returns the cached statement for query on db, preparing it on first use.
+// A nil statement with nil error means the server refused to prepare it
+// earlier; callers then fall back to an unprepared query.
+func (c *stmtCache) get(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, error) {
+	c.mu.Lock()
+	stmt, ok := c.stmts[db][query]
+	c.mu.Unlock()
+	if ok {
+		return stmt, nil
+	}
+
+	// Prepare outside the lock so a slow link does not serialize unrelated tools.
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		stmt = nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.stmts[db][query]; ok {
+		if stmt != nil {
+			stmt.Close()
+		}
+		return existing, nil
+	}
+	if c.stmts[db] == nil {
+		c.stmts[db] = make(map[string]*sql.Stmt)
+	}
+	c.stmts[db][query] = stmt
+	return stmt, nil
+}
+
+// forget closes and drops every statement prepared on db.
+func (c *stmtCache) forget(db *sql.DB) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, stmt := range c.stmts[db] {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	delete(c.stmts, db)
+}
+
+// len reports the number of cached entries for db (including refused ones).
+func (c *stmtCache) len(db *sql.DB) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.stmts[db])
+}
+
+func preparedStatementsEnabled() bool {
+	return cfg == nil || cfg.PreparedStatements
+}
+
+// queryPrepared runs a fixed metadata query on the active connection through
+// the statement cache, falling back to a plain query when preparing is
+// disabled or unsupported.
+func queryPrepared(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	db := getDB()
+	if preparedStatementsEnabled() {
+		stmt, err := preparedStmts.get(ctx, db, query)
+		if err != nil {
+			return nil, err
+		}
+		if stmt != nil {
+			return stmt.QueryContext(ctx, args...)
+		}
+	}
+	return db.QueryContext(ctx, query, args...)
+}
+
+// queryRowPrepared is the single-row variant of queryPrepared.
+func queryRowPrepared(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	db := getDB()
+	if preparedStatementsEnabled() {
+		if stmt, err := preparedStmts.get(ctx, db, query); err == nil && stmt != nil {
+			return stmt.QueryRowContext(ctx, args...)
+		}
+	}
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// warmUpStatements prepares hotQueries on every managed connection so the
+// first tool calls do not pay the prepare round trips. Failures are logged
+// and otherwise ignored; handlers prepare lazily anyway.
+func warmUpStatements(ctx context.Context, cm *ConnectionManager) {
+	for name, db := range cm.Pools() {
+		if c, _ := cm.Config(name); c.ConnectOnDemand {
+			continue // connects on first use, not at startup
+		}
+		prepared := 0
+		for _, q := range hotQueries {
+			stmt, err := preparedStmts.get(ctx, db, q)
+			if err != nil {
+				poolLog.Warn("statement warm-up aborted", map[string]interface{}{"connection": name, "error": err.Error()})
+				return
+			}
+			if stmt != nil {
+				prepared++
+			}
+		}
+		poolLog.Info("statement warm-up complete", map[string]interface{}{
+			"connection": name,
+			"prepared":   prepared,
+			"total":      len(hotQueries),
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// pkg/mysqlmcp/stmt_cache_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestListViewsReusesPreparedStatement(t *testing.T) {
+	res := setupMockDBFull(t)
+	defer res.cleanup()
+	mock := res.mock
+
+	prep := mock.ExpectPrepare(regexp.QuoteMeta(queryListViews))
+	prep.ExpectQuery().WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "DEFINER", "SECURITY_TYPE", "IS_UPDATABLE"}).
+			AddRow("v_orders", "root@%", "DEFINER", "NO"))
+	prep.ExpectQuery().WithArgs("crm").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "DEFINER", "SECURITY_TYPE", "IS_UPDATABLE"}))
+
+	for _, db := range []string{"shop", "crm"} {
+		if _, _, err := toolListViews(context.Background(), &mcp.CallToolRequest{}, ListViewsInput{Database: db}); err != nil {
+			t.Fatalf("toolListViews(%s) failed: %v", db, err)
+		}
+	}
+	if n := preparedStmts.len(res.mockDB); n != 1 {
+		t.Errorf("expected 1 cached statement, got %d", n)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestQueryPreparedFallsBackWhenPrepareFails(t *testing.T) {
+	res := setupMockDBFull(t)
+	defer res.cleanup()
+	mock := res.mock
+
+	mock.ExpectPrepare(regexp.QuoteMeta(querySchemaExists)).WillReturnError(errors.New("prepared statements not supported"))
+	mock.ExpectQuery(regexp.QuoteMeta(querySchemaExists)).WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1))
+	// The refusal is remembered: no second prepare attempt.
+	mock.ExpectQuery(regexp.QuoteMeta(querySchemaExists)).WithArgs("crm").
+		WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	if ok, err := schemaExists(context.Background(), "shop"); err != nil || !ok {
+		t.Fatalf("schemaExists(shop) = %v, %v", ok, err)
+	}
+	if ok, err := schemaExists(context.Background(), "crm"); err != nil || ok {
+		t.Fatalf("schemaExists(crm) = %v, %v", ok, err)
+	}
+
+	preparedStmts.forget(res.mockDB)
+	if n := preparedStmts.len(res.mockDB); n != 0 {
+		t.Errorf("expected cache to be empty after forget, got %d", n)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// pkg/mysqlmcp/text_table.go
+package mysqlmcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/askdba/mysql-mcp-server/internal/api"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Query results can also be rendered the way the mysql command-line client
+// prints them: a boxed ASCII table with numeric columns right-aligned and a
+// "N rows in set" footer. run_query returns it as its text content with
+// format=table, and the REST query endpoints answer with it when the request
+// sends Accept: text/plain. curl users and agents quoting a result in an
+// answer get something readable without post-processing the JSON.
+
+// Output formats of run_query.
+const (
+	outputFormatJSON  = "json"
+	outputFormatTable = "table"
+)
+
+// resolveOutputFormat validates a run_query format, defaulting to json.
+func resolveOutputFormat(format string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", outputFormatJSON:
+		return outputFormatJSON, nil
+	case outputFormatTable:
+		return f, nil
+	default:
+		return "", fmt.Errorf("format must be json or table")
+	}
+}
+
+// renderTextTable renders res as a mysql client-style table.
+func renderTextTable(res QueryResult) string {
+	var b strings.Builder
+	if len(res.Rows) == 0 {
+		b.WriteString("Empty set\n")
+	} else {
+		numeric := numericColumns(res)
+		cells := make([][]string, len(res.Rows))
+		widths := make([]int, len(res.Columns))
+		for i, c := range res.Columns {
+			widths[i] = utf8.RuneCountInString(c)
+		}
+		for r, row := range res.Rows {
+			cells[r] = make([]string, len(res.Columns))
+			for i := range res.Columns {
+				var v interface{}
+				if i < len(row) {
+					v = row[i]
+				}
+				cells[r][i] = textCell(v)
+				if n := utf8.RuneCountInString(cells[r][i]); n > widths[i] {
+					widths[i] = n
+				}
+			}
+		}
+
+		border := textTableBorder(widths)
+		b.WriteString(border)
+		writeTextTableRow(&b, res.Columns, widths, nil)
+		b.WriteString(border)
+		for _, row := range cells {
+			writeTextTableRow(&b, row, widths, numeric)
+		}
+		b.WriteString(border)
+
+		if len(res.Rows) == 1 {
+			b.WriteString("1 row in set")
+		} else {
+			fmt.Fprintf(&b, "%d rows in set", len(res.Rows))
+		}
+		if res.Truncated || res.HasMore {
+			b.WriteString(" (more rows exist; narrow the query or page with offset)")
+		}
+		b.WriteString("\n")
+	}
+	if res.Warning != "" {
+		b.WriteString("Warning: " + res.Warning + "\n")
+	}
+	return b.String()
+}
+
+// formattedResult is the tool result of a run_query answer in format: nil
+// for json, which lets the SDK send the JSON as text, and the rendered table
+// as text content otherwise.
+func formattedResult(format string, res QueryResult) *mcp.CallToolResult {
+	if format != outputFormatTable {
+		return nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: renderTextTable(res)}}}
+}
+
+// numericColumns reports which columns hold numbers and are right-aligned,
+// from column_types when they line up with the columns and otherwise from
+// the values of the first row.
+func numericColumns(res QueryResult) []bool {
+	numeric := make([]bool, len(res.Columns))
+	if len(res.ColumnTypes) == len(res.Columns) {
+		for i, ct := range res.ColumnTypes {
+			switch ct.Kind {
+			case columnKindInteger, columnKindDecimal, columnKindFloat, columnKindBit:
+				numeric[i] = true
+			}
+		}
+		return numeric
+	}
+	if len(res.Rows) > 0 {
+		for i, v := range res.Rows[0] {
+			if i >= len(numeric) {
+				break
+			}
+			switch v.(type) {
+			case int, int32, int64, uint, uint32, uint64, float32, float64, json.Number:
+				numeric[i] = true
+			}
+		}
+	}
+	return numeric
+}
+
+// textCell formats one value as the mysql client shows it. Line breaks and
+// tabs are escaped so a cell stays on its row.
+func textCell(v interface{}) string {
+	var s string
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		s = x
+	case []byte:
+		s = string(x)
+	case float64:
+		s = strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(x)
+		if err != nil {
+			s = fmt.Sprint(x)
+		} else {
+			s = string(data)
+		}
+	default:
+		s = fmt.Sprint(x)
+	}
+	return strings.NewReplacer("\r", `\r`, "\n", `\n`, "\t", `\t`).Replace(s)
+}
+
+func textTableBorder(widths []int) string {
+	var b strings.Builder
+	b.WriteString("+")
+	for _, w := range widths {
+		b.WriteString(strings.Repeat("-", w+2))
+		b.WriteString("+")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func writeTextTableRow(b *strings.Builder, cells []string, widths []int, rightAlign []bool) {
+	b.WriteString("|")
+	for i, c := range cells {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c))
+		if rightAlign != nil && rightAlign[i] {
+			b.WriteString(" " + pad + c + " |")
+		} else {
+			b.WriteString(" " + c + pad + " |")
+		}
+	}
+	b.WriteString("\n")
+}
+
+// wantsTextTable reports whether an HTTP request asks for text/plain rather
+// than JSON. Only an explicit text/plain media type counts; */* and missing
+// Accept headers keep JSON.
+func wantsTextTable(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mt != "text/plain" {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// writeQueryResult writes res as JSON, or as a text table when the request
+// accepts text/plain.
+func writeQueryResult(w http.ResponseWriter, r *http.Request, res QueryResult) {
+	if !wantsTextTable(r) {
+		api.WriteSuccess(w, res)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(renderTextTable(res)))
+}
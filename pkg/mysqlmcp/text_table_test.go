@@ -0,0 +1,101 @@
+// pkg/mysqlmcp/text_table_test.go
+package mysqlmcp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRenderTextTable(t *testing.T) {
+	res := QueryResult{
+		Columns: []string{"id", "name", "balance"},
+		Rows: [][]interface{}{
+			{int64(1), "Zoë", "12.50"},
+			{int64(10), "line\nbreak", nil},
+		},
+		ColumnTypes: []ColumnType{{Kind: columnKindInteger}, {Kind: columnKindString}, {Kind: columnKindDecimal}},
+		Truncated:   true,
+	}
+	want := "" +
+		"+----+-------------+---------+\n" +
+		"| id | name        | balance |\n" +
+		"+----+-------------+---------+\n" +
+		"|  1 | Zoë         |   12.50 |\n" +
+		"| 10 | line\\nbreak |    NULL |\n" +
+		"+----+-------------+---------+\n" +
+		"2 rows in set (more rows exist; narrow the query or page with offset)\n"
+	if got := renderTextTable(res); got != want {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := renderTextTable(QueryResult{Columns: []string{"id"}, Warning: "SELECT * retrieves all columns"}); got != "Empty set\nWarning: SELECT * retrieves all columns\n" {
+		t.Errorf("unexpected empty result %q", got)
+	}
+	// Without column types, numbers are recognized by their values.
+	if got := renderTextTable(QueryResult{Columns: []string{"total"}, Rows: [][]interface{}{{float64(3.5)}, {float64(10)}}}); !strings.Contains(got, "|   3.5 |\n|    10 |") {
+		t.Errorf("expected right-aligned numbers:\n%s", got)
+	}
+}
+
+func TestWantsTextTable(t *testing.T) {
+	for accept, want := range map[string]bool{
+		"":                                   false,
+		"*/*":                                false,
+		"application/json":                   false,
+		"text/plain":                         true,
+		"text/plain; charset=utf-8":          true,
+		"application/json, text/plain;q=0.5": true,
+		"text/plain;q=0":                     false,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/api/query", nil)
+		r.Header.Set("Accept", accept)
+		if got := wantsTextTable(r); got != want {
+			t.Errorf("wantsTextTable(%q) = %v, want %v", accept, got, want)
+		}
+	}
+}
+
+func TestRunQueryTableFormat(t *testing.T) {
+	mock, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Alice"))
+	req := httptest.NewRequest(http.MethodPost, "/api/query", bytes.NewBufferString(`{"sql": "SELECT id, name FROM users"}`))
+	req.Header.Set("Accept", "text/plain")
+	w := httptest.NewRecorder()
+	httpRunQuery(w, req)
+	if ct := w.Header().Get("Content-Type"); w.Code != http.StatusOK || !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("expected a text/plain 200, got %d %s: %s", w.Code, ct, w.Body.String())
+	}
+	if body := w.Body.String(); !strings.Contains(body, "| Alice |") || !strings.HasSuffix(body, "1 row in set\n") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Alice"))
+	res, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, name FROM users", Format: "table"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Rows) != 1 || res == nil || len(res.Content) != 1 {
+		t.Fatalf("expected the rows and one text content, got %+v %+v", out, res)
+	}
+	if text, ok := res.Content[0].(*mcp.TextContent); !ok || !strings.Contains(text.Text, "| Alice |") {
+		t.Errorf("unexpected content %+v", res.Content[0])
+	}
+
+	if _, _, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1", Format: "csv"}); err == nil {
+		t.Error("expected an unknown format to be rejected")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
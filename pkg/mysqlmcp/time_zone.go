@@ -0,0 +1,127 @@
+// pkg/mysqlmcp/time_zone.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+// MySQL converts TIMESTAMP values from UTC to the session time_zone when they
+// are read, while DATETIME, DATE and TIME come back exactly as written. A
+// result mixing both, read in an unknown zone, is easy to misread, so the
+// query tools can pin time_zone per call (query.time_zone or the time_zone
+// argument) and label the temporal columns of every result with the zone and
+// offset they were read in.
+
+type queryTimeZoneKey struct{}
+
+// withQueryTimeZone sets the time_zone for the queries run under ctx,
+// overriding query.time_zone. An empty tz keeps the configured zone.
+func withQueryTimeZone(ctx context.Context, tz string) context.Context {
+	if tz == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, queryTimeZoneKey{}, tz)
+}
+
+// queryTimeZone returns the time_zone to set for queries run under ctx, or ""
+// to keep the session's zone.
+func queryTimeZone(ctx context.Context) string {
+	if tz, ok := ctx.Value(queryTimeZoneKey{}).(string); ok {
+		return tz
+	}
+	if cfg == nil {
+		return ""
+	}
+	return cfg.TimeZone
+}
+
+// resolveTimeZone validates the time_zone argument of a query tool.
+func resolveTimeZone(tz string) (string, error) {
+	tz = strings.TrimSpace(tz)
+	if tz != "" && !config.ValidTimeZone(tz) {
+		return "", fmt.Errorf("time_zone must be SYSTEM, an offset such as +00:00 or a zone name such as Europe/Berlin")
+	}
+	return tz, nil
+}
+
+// useTimeZone sets the time_zone of conn for one call. Like useDatabase, the
+// returned restore func must run before conn goes back to the pool; it puts
+// back the previous zone, or discards the connection when that fails.
+func useTimeZone(ctx context.Context, conn *sql.Conn, tz string) (restore func(), err error) {
+	if tz == "" {
+		return func() {}, nil
+	}
+	if proxyCompat() {
+		return nil, proxySessionError("time_zone "+tz, "set time_zone as a DSN parameter or on the server, and leave time_zone empty")
+	}
+	var previous string
+	if err := conn.QueryRowContext(ctx, "SELECT @@session.time_zone").Scan(&previous); err != nil {
+		return nil, fmt.Errorf("failed to read time_zone: %w", err)
+	}
+	if previous == tz {
+		return func() {}, nil
+	}
+	restore = func() { resetTimeZone(conn, previous) }
+	if _, err := conn.ExecContext(ctx, "SET time_zone = ?", tz); err != nil {
+		restore()
+		return nil, fmt.Errorf("failed to set time_zone '%s': %w", tz, err)
+	}
+	return restore, nil
+}
+
+// resetTimeZone switches conn back to the previous zone, or discards it from
+// the pool.
+func resetTimeZone(conn *sql.Conn, previous string) {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	if _, err := conn.ExecContext(ctx, "SET time_zone = ?", previous); err == nil {
+		return
+	}
+	_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+}
+
+// temporalColumns returns the TIMESTAMP, DATETIME, DATE and TIME columns of
+// a result. TIMESTAMP columns are in the session zone; the others are as
+// stored.
+func temporalColumns(types []*sql.ColumnType) []TemporalColumn {
+	var out []TemporalColumn
+	for _, t := range types {
+		switch name := strings.ToUpper(t.DatabaseTypeName()); name {
+		case "TIMESTAMP":
+			out = append(out, TemporalColumn{Column: t.Name(), Type: name, Zone: "session"})
+		case "DATETIME", "DATE", "TIME":
+			out = append(out, TemporalColumn{Column: t.Name(), Type: name, Zone: "as_stored"})
+		}
+	}
+	return out
+}
+
+// sessionTimeZone reads the time_zone of conn and its current offset from
+// UTC, formatted as +HH:MM.
+func sessionTimeZone(ctx context.Context, conn *sql.Conn) (zone, offset string, err error) {
+	var diff string
+	if err := conn.QueryRowContext(ctx, "SELECT @@session.time_zone, TIMEDIFF(NOW(), UTC_TIMESTAMP())").Scan(&zone, &diff); err != nil {
+		return "", "", err
+	}
+	return zone, formatUTCOffset(diff), nil
+}
+
+// formatUTCOffset turns a TIMEDIFF result such as 05:30:00 or -08:00:00 into
+// +05:30 or -08:00.
+func formatUTCOffset(diff string) string {
+	sign := "+"
+	if strings.HasPrefix(diff, "-") {
+		sign, diff = "-", diff[1:]
+	}
+	parts := strings.SplitN(diff, ":", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("%s%02s:%s", sign, parts[0], parts[1])
+}
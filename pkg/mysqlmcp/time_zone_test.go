@@ -0,0 +1,81 @@
+// pkg/mysqlmcp/time_zone_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func temporalRows() *sqlmock.Rows {
+	return sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("INT", int64(0)),
+		sqlmock.NewColumn("created_at").OfType("TIMESTAMP", time.Time{}),
+		sqlmock.NewColumn("shipped_on").OfType("DATE", time.Time{}),
+	).AddRow(int64(1), "2026-03-01 10:00:00", "2026-03-02")
+}
+
+func TestRunQueryTimeZone(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT @@session.time_zone").
+		WillReturnRows(sqlmock.NewRows([]string{"@@session.time_zone"}).AddRow("SYSTEM"))
+	mock.ExpectExec("SET time_zone = ?").WithArgs("Europe/Berlin").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, created_at, shipped_on FROM orders").WillReturnRows(temporalRows())
+	mock.ExpectQuery("SELECT @@session.time_zone, TIMEDIFF").
+		WillReturnRows(sqlmock.NewRows([]string{"zone", "diff"}).AddRow("Europe/Berlin", "01:00:00"))
+	mock.ExpectExec("SET time_zone = ?").WithArgs("SYSTEM").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{
+		SQL:      "SELECT id, created_at, shipped_on FROM orders",
+		TimeZone: "Europe/Berlin",
+	})
+	if err != nil {
+		t.Fatalf("run_query failed: %v", err)
+	}
+	if out.TimeZone != "Europe/Berlin" || out.UTCOffset != "+01:00" {
+		t.Errorf("unexpected zone %q offset %q", out.TimeZone, out.UTCOffset)
+	}
+	want := []TemporalColumn{
+		{Column: "created_at", Type: "TIMESTAMP", Zone: "session"},
+		{Column: "shipped_on", Type: "DATE", Zone: "as_stored"},
+	}
+	if len(out.TemporalColumns) != len(want) {
+		t.Fatalf("temporal_columns = %+v, want %+v", out.TemporalColumns, want)
+	}
+	for i := range want {
+		if out.TemporalColumns[i] != want[i] {
+			t.Errorf("temporal_columns[%d] = %+v, want %+v", i, out.TemporalColumns[i], want[i])
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRunQueryTimeZoneRejected(t *testing.T) {
+	_, _, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT 1", TimeZone: "UTC'; DROP"})
+	if err == nil || !strings.Contains(err.Error(), "time_zone must be") {
+		t.Errorf("expected a time_zone error, got %v", err)
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	tests := map[string]string{
+		"00:00:00":  "+00:00",
+		"05:30:00":  "+05:30",
+		"-08:00:00": "-08:00",
+		"-3:30:00":  "-03:30",
+		"":          "",
+	}
+	for diff, want := range tests {
+		if got := formatUTCOffset(diff); got != want {
+			t.Errorf("formatUTCOffset(%q) = %q, want %q", diff, got, want)
+		}
+	}
+}
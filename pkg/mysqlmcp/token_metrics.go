@@ -1,5 +1,5 @@
-// cmd/mysql-mcp-server/token_metrics.go
-package main
+// pkg/mysqlmcp/token_metrics.go
+package mysqlmcp
 
 import (
 	"sync"
@@ -1,4 +1,4 @@
-package main
+package mysqlmcp
 
 import (
 	"encoding/json"
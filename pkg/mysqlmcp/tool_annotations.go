@@ -0,0 +1,70 @@
+// pkg/mysqlmcp/tool_annotations.go
+package mysqlmcp
+
+import (
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolWriteHints lists the tools that change state, and how. Every other tool
+// only reads from MySQL (run_query and friends are enforced read-only).
+var toolWriteHints = map[string]struct {
+	destructive bool
+	idempotent  bool
+}{
+	// KILL QUERY aborts another client's statement.
+	"kill_query": {destructive: true, idempotent: true},
+	// Registers a saved query in memory, replacing a runtime one of that name.
+	"save_query": {destructive: true, idempotent: true},
+	// Records plan baselines, replacing them with update_baseline.
+	"check_saved_queries": {destructive: true},
+	// Changes sql_mode / optimizer_switch for later query tool calls.
+	"set_session_setting": {idempotent: true},
+	// Switches the connection later tool calls use.
+	"use_connection": {idempotent: true},
+	// Write embedding rows (MYSQL_MCP_VECTOR_WRITE).
+	"vector_insert": {destructive: true},
+	"vector_delete": {destructive: true, idempotent: true},
+}
+
+// toolAnnotations returns the MCP annotations of a tool: a display title and
+// the hints clients use to decide whether a call needs confirmation. No tool
+// reaches outside the configured MySQL servers, so none is open-world.
+func toolAnnotations(name string) *mcp.ToolAnnotations {
+	a := &mcp.ToolAnnotations{
+		Title:           toolTitle(name),
+		ReadOnlyHint:    true,
+		DestructiveHint: boolPtr(false),
+		IdempotentHint:  true,
+		OpenWorldHint:   boolPtr(false),
+	}
+	if hint, ok := toolWriteHints[name]; ok {
+		a.ReadOnlyHint = false
+		a.DestructiveHint = boolPtr(hint.destructive)
+		a.IdempotentHint = hint.idempotent
+	}
+	return a
+}
+
+// toolTitle turns a tool name into a title: list_tables becomes "List tables".
+func toolTitle(name string) string {
+	title := strings.ReplaceAll(name, "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// addTool registers a tool like mcp.AddTool, filling in its annotations
+// unless the definition sets them, and lists it in globalUsage. mcp.AddTool derives the input and output
+// JSON schemas from I and O.
+func addTool[I, O any](server *mcp.Server, tool *mcp.Tool, h mcp.ToolHandlerFor[I, O]) {
+	if tool.Annotations == nil {
+		tool.Annotations = toolAnnotations(tool.Name)
+	}
+	globalUsage.register(tool.Name)
+	mcp.AddTool(server, tool, h)
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,55 @@
+// pkg/mysqlmcp/tool_annotations_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+func TestToolAnnotations(t *testing.T) {
+	a := toolAnnotations("list_tables")
+	if a.Title != "List tables" || !a.ReadOnlyHint || *a.DestructiveHint || !a.IdempotentHint || *a.OpenWorldHint {
+		t.Errorf("unexpected annotations for list_tables: %+v", a)
+	}
+	a = toolAnnotations("kill_query")
+	if a.ReadOnlyHint || !*a.DestructiveHint {
+		t.Errorf("kill_query should be a destructive write: %+v", a)
+	}
+	a = toolAnnotations("use_connection")
+	if a.ReadOnlyHint || *a.DestructiveHint {
+		t.Errorf("use_connection should be a non-destructive write: %+v", a)
+	}
+}
+
+func TestToolsHaveAnnotationsAndOutputSchemas(t *testing.T) {
+	oldCfg, oldExtended := cfg, extendedMode
+	cfg = &config.Config{VectorMode: true, VectorWrite: true, SaveQueryTool: true, ProcessAdmin: true, SessionsTool: true, SlowQueryTool: true}
+	extendedMode = true
+	defer func() { cfg, extendedMode = oldCfg, oldExtended }()
+
+	ctx := context.Background()
+	session, err := connectCLIClient(ctx, newMCPServer())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer session.Close()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("list tools: %v", err)
+	}
+	if len(tools.Tools) == 0 {
+		t.Fatal("no tools registered")
+	}
+	for _, tool := range tools.Tools {
+		if tool.Annotations == nil || tool.Annotations.Title == "" || tool.Annotations.DestructiveHint == nil {
+			t.Errorf("%s: missing annotations: %+v", tool.Name, tool.Annotations)
+		}
+		schema, ok := tool.OutputSchema.(map[string]any)
+		if !ok || schema["type"] != "object" {
+			t.Errorf("%s: expected an object output schema, got %v", tool.Name, tool.OutputSchema)
+		}
+	}
+}
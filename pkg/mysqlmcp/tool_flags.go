@@ -0,0 +1,95 @@
+// pkg/mysqlmcp/tool_flags.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// features.tools (MYSQL_MCP_TOOLS) switches single tools or whole groups on
+// or off, so a deployment can enable extended mode without list_variables,
+// or explain_query without the rest of extended mode. A tool's own flag wins
+// over its group's, and both win over the group's mode switch
+// (MYSQL_MCP_EXTENDED, MYSQL_MCP_VECTOR). A group is registered when any of
+// its tools ends up on; dispatchTool refuses, and tools/list hides, the ones
+// left off. Tools behind their own switch (security.process_admin,
+// security.slow_query_tool, ...) still need it to be registered at all.
+
+// checkToolFlags rejects features.tools names that are neither a tool nor a
+// tool group.
+func checkToolFlags(flags map[string]bool) error {
+	for name := range flags {
+		if _, ok := toolGroups[name]; ok {
+			continue
+		}
+		switch name {
+		case toolGroupCore, toolGroupExtended, toolGroupVector, toolGroupVectorWrite:
+			continue
+		}
+		return fmt.Errorf("features.tools / MYSQL_MCP_TOOLS: unknown tool or group '%s'", name)
+	}
+	return nil
+}
+
+func toolFlagsEnabled() bool {
+	return cfg != nil && len(cfg.ToolFlags) > 0
+}
+
+// groupMode reports the mode switch of a tool group; core tools are always on.
+func groupMode(group string) bool {
+	switch group {
+	case toolGroupExtended:
+		return extendedMode
+	case toolGroupVector, toolGroupVectorWrite:
+		return cfg != nil && cfg.VectorMode
+	}
+	return true
+}
+
+// toolEnabled reports whether tool is on: its own flag, else its group's flag,
+// else the group's mode switch.
+func toolEnabled(tool string) bool {
+	group := toolGroups[tool]
+	if cfg != nil {
+		if on, ok := cfg.ToolFlags[tool]; ok {
+			return on
+		}
+		if on, ok := cfg.ToolFlags[group]; ok && group != "" {
+			return on
+		}
+	}
+	return groupMode(group)
+}
+
+// toolGroupEnabled reports whether any tool of group is on, and so whether
+// the group's tools are registered.
+func toolGroupEnabled(group string) bool {
+	for tool, g := range toolGroups {
+		if g == group && toolEnabled(tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterDisabledTools hides tools features.tools turns off from tools/list.
+// Calls are still checked by dispatchTool.
+func filterDisabledTools(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		res, err := next(ctx, method, req)
+		list, ok := res.(*mcp.ListToolsResult)
+		if err != nil || !ok || method != "tools/list" {
+			return res, err
+		}
+		visible := list.Tools[:0]
+		for _, t := range list.Tools {
+			if toolEnabled(t.Name) {
+				visible = append(visible, t)
+			}
+		}
+		list.Tools = visible
+		return list, nil
+	}
+}
@@ -0,0 +1,75 @@
+// pkg/mysqlmcp/tool_flags_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolFlags(t *testing.T) {
+	oldCfg, oldExtended := cfg, extendedMode
+	defer func() { cfg, extendedMode = oldCfg, oldExtended }()
+	extendedMode = false
+	cfg = &config.Config{ToolFlags: map[string]bool{"explain_query": true, "list_variables": false, "vector": true, "vector_write": true, "vector_delete": false}}
+
+	for tool, want := range map[string]bool{
+		"explain_query":  true,  // own flag beats the mode switch
+		"list_indexes":   false, // extended mode is off
+		"list_variables": false,
+		"vector_search":  true, // group flag
+		"vector_insert":  true, // its own group's flag
+		"vector_delete":  false,
+		"run_query":      true,
+	} {
+		if got := toolEnabled(tool); got != want {
+			t.Errorf("toolEnabled(%s) = %v, want %v", tool, got, want)
+		}
+	}
+	if !toolGroupEnabled(toolGroupExtended) || !toolGroupEnabled(toolGroupVector) {
+		t.Error("groups with an enabled tool should be registered")
+	}
+
+	if err := authorizeTool(context.Background(), nil, "list_variables"); !errors.Is(err, errToolForbidden) {
+		t.Errorf("expected list_variables to be refused, got %v", err)
+	}
+	if err := authorizeTool(context.Background(), nil, "explain_query"); err != nil {
+		t.Errorf("explain_query refused: %v", err)
+	}
+
+	// Turning the extended group off wins over the mode switch.
+	extendedMode = true
+	cfg.ToolFlags = map[string]bool{"extended": false, "list_indexes": true}
+	if toolEnabled("explain_query") || !toolEnabled("list_indexes") {
+		t.Error("group flag should override extended mode, tool flag the group flag")
+	}
+}
+
+func TestFilterDisabledTools(t *testing.T) {
+	oldCfg := cfg
+	defer func() { cfg = oldCfg }()
+	cfg = &config.Config{ToolFlags: map[string]bool{"list_variables": false}}
+
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.ListToolsResult{Tools: []*mcp.Tool{{Name: "ping"}, {Name: "list_variables"}}}, nil
+	}
+	res, err := filterDisabledTools(next)(context.Background(), "tools/list", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tools := res.(*mcp.ListToolsResult).Tools; len(tools) != 1 || tools[0].Name != "ping" {
+		t.Errorf("unexpected tools: %+v", tools)
+	}
+}
+
+func TestCheckToolFlags(t *testing.T) {
+	if err := checkToolFlags(map[string]bool{"extended": true, "explain_query": false}); err != nil {
+		t.Errorf("valid flags rejected: %v", err)
+	}
+	if err := checkToolFlags(map[string]bool{"explain": true}); err == nil {
+		t.Error("expected an unknown tool error")
+	}
+}
@@ -0,0 +1,233 @@
+package mysqlmcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func toolSkipsOutputTokenEstimate(name string) bool {
+	switch name {
+	case "list_variables", "list_status", "show_create_table":
+		return true
+	default:
+		return false
+	}
+}
+
+// dispatchTool is the entry point shared by every tool, in MCP and HTTP mode:
+// it assigns the call a request ID, enforces rbac and the concurrent query
+// limits, tags errors with the ID and records the call in globalUsage.
+func dispatchTool[I any, O any](toolName string, h mcp.ToolHandlerFor[I, O]) mcp.ToolHandlerFor[I, O] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input I) (res *mcp.CallToolResult, out O, err error) {
+		start := time.Now()
+		defer func() {
+			globalUsage.record(toolName, time.Since(start), resultRows(out), err != nil || (res != nil && res.IsError))
+		}()
+		ctx = ensureRequestID(ctx, req)
+		ctx = withMCPClientIdentity(ctx, req)
+		ctx = withReplicaSession(ctx, req)
+		ctx = ensureLocale(ctx, req)
+		if err := authorizeTool(ctx, req, toolName); err != nil {
+			var zero O
+			serverLog.Warn("tool call denied", map[string]interface{}{
+				"tool":       toolName,
+				"client":     mcpClientName(req),
+				"request_id": requestIDFrom(ctx),
+				"error":      err.Error(),
+			})
+			return nil, zero, withRequestIDError(ctx, localizeError(ctx, err))
+		}
+		applyVirtualDatabase(&input)
+		if err := requireCapability(toolName); err != nil {
+			var zero O
+			serverLog.Debug("tool not supported on this connection", map[string]interface{}{
+				"tool":       toolName,
+				"request_id": requestIDFrom(ctx),
+				"error":      err.Error(),
+			})
+			return nil, zero, withRequestIDError(ctx, err)
+		}
+		if err := validateInput(input); err != nil {
+			var zero O
+			serverLog.Debug("tool input rejected", map[string]interface{}{
+				"tool":       toolName,
+				"request_id": requestIDFrom(ctx),
+				"error":      err.Error(),
+			})
+			return nil, zero, withRequestIDError(ctx, localizeError(ctx, err))
+		}
+		quota, err := admitQuota(ctx, req, toolName)
+		if err != nil {
+			var zero O
+			serverLog.Warn("tool call over quota", map[string]interface{}{
+				"tool":       toolName,
+				"request_id": requestIDFrom(ctx),
+				"error":      err.Error(),
+			})
+			return nil, zero, withRequestIDError(ctx, localizeError(ctx, err))
+		}
+		done, err := admitCircuit(toolName)
+		if err == nil {
+			var release func()
+			if release, err = acquireQuerySlot(ctx, toolName); err != nil {
+				done(err)
+			} else {
+				defer release()
+			}
+		}
+		if err != nil {
+			var zero O
+			serverLog.Warn("tool call rejected", map[string]interface{}{
+				"tool":       toolName,
+				"request_id": requestIDFrom(ctx),
+				"error":      err.Error(),
+			})
+			return nil, zero, withRequestIDError(ctx, localizeError(ctx, err))
+		}
+		foldInputIdentifiers(ctx, &input)
+		began := time.Now()
+		res, out, err = h(ctx, req, input)
+		done(circuitOutcome(any(out), err))
+		res = quota.finish(ctx, res, resultRows(out))
+		notifyToolWebhooks(ctx, toolName, input, time.Since(began), err)
+		err = identifierCaseHint(ctx, err)
+		return res, out, withRequestIDError(ctx, localizeError(ctx, err))
+	}
+}
+
+func wrapTool[I any, O any](toolName string, h mcp.ToolHandlerFor[I, O]) mcp.ToolHandlerFor[I, O] {
+	h = dispatchTool(toolName, h)
+	return func(ctx context.Context, req *mcp.CallToolRequest, input I) (*mcp.CallToolResult, O, error) {
+		start := time.Now()
+		res, out, err := h(ctx, req, input)
+
+		// Only emit these extra logs when token tracking is explicitly enabled.
+		// This keeps default behavior unchanged.
+		if tokenTracking && toolName != "run_query" {
+			inputTokens, _ := estimateTokensForValue(input)
+			outputTokens := 0
+			// Skip output estimation for tools that routinely return very large JSON;
+			// tiktoken over big payloads can stall long enough for MCP clients to time out.
+			if err == nil && !toolSkipsOutputTokenEstimate(toolName) {
+				outputTokens, _ = estimateTokensForValue(out)
+			}
+			tokens := TokenUsage{
+				InputEstimated:  inputTokens,
+				OutputEstimated: outputTokens,
+				TotalEstimated:  inputTokens + outputTokens,
+				Model:           tokenModel,
+			}
+
+			// Record into the global metrics aggregator
+			globalTokenMetrics.Record(toolName, inputTokens, outputTokens)
+
+			fields := map[string]interface{}{
+				"tool":        toolName,
+				"duration_ms": time.Since(start).Milliseconds(),
+				"tokens": map[string]interface{}{
+					"input_estimated":  tokens.InputEstimated,
+					"output_estimated": tokens.OutputEstimated,
+					"total_estimated":  tokens.TotalEstimated,
+					"model":            tokens.Model,
+				},
+			}
+			if err != nil {
+				fields["error"] = err.Error()
+				serverLog.Error("tool failed", fields)
+			} else {
+				serverLog.Info("tool executed", fields)
+			}
+		}
+
+		return res, out, err
+	}
+}
+
+// Wrapped tool handlers used by both MCP and HTTP.
+var (
+	toolListDatabasesWrapped     = wrapTool("list_databases", toolListDatabases)
+	toolListTablesWrapped        = wrapTool("list_tables", toolListTables)
+	toolDescribeTableWrapped     = wrapTool("describe_table", toolDescribeTable)
+	toolValidateQueryWrapped     = wrapTool("validate_query", toolValidateQuery)
+	toolExplainValidationWrapped = wrapTool("explain_validation", toolExplainValidation)
+	toolFetchCellWrapped         = wrapTool("fetch_cell", toolFetchCell)
+	toolRunQueryWrapped          = dispatchTool("run_query", toolRunQuery) // run_query has dedicated query/audit logs with tokens
+	toolPingWrapped              = wrapTool("ping", toolPing)
+	toolServerInfoWrapped        = wrapTool("server_info", toolServerInfo)
+	toolListConnectionsWrapped   = wrapTool("list_connections", toolListConnections)
+	toolUseConnectionWrapped     = wrapTool("use_connection", toolUseConnection)
+	toolPoolStatsWrapped         = wrapTool("pool_stats", toolPoolStats)
+	toolUsageStatsWrapped        = wrapTool("usage_stats", toolUsageStats)
+
+	toolListSavedQueriesWrapped = wrapTool("list_saved_queries", toolListSavedQueries)
+	toolRunSavedQueryWrapped    = wrapTool("run_saved_query", toolRunSavedQuery)
+	toolSaveQueryWrapped        = wrapTool("save_query", toolSaveQuery)
+	toolListReportsWrapped      = wrapTool("list_reports", toolListReports)
+	toolRunReportWrapped        = wrapTool("run_report", toolRunReport)
+	toolScheduledResultsWrapped = wrapTool("scheduled_results", toolScheduledResults)
+	toolSchemaChangesWrapped    = wrapTool("schema_changes", toolSchemaChanges)
+	toolListBookmarksWrapped    = wrapTool("list_bookmarks", toolListBookmarks)
+	toolSaveBookmarkWrapped     = wrapTool("save_bookmark", toolSaveBookmark)
+	toolDeleteBookmarkWrapped   = wrapTool("delete_bookmark", toolDeleteBookmark)
+
+	toolVectorSearchWrapped = wrapTool("vector_search", toolVectorSearch)
+	toolVectorInfoWrapped   = wrapTool("vector_info", toolVectorInfo)
+	toolVectorInsertWrapped = wrapTool("vector_insert", toolVectorInsert)
+	toolVectorDeleteWrapped = wrapTool("vector_delete", toolVectorDelete)
+
+	toolListIndexesWrapped      = wrapTool("list_indexes", toolListIndexes)
+	toolShowCreateTableWrapped  = wrapTool("show_create_table", toolShowCreateTable)
+	toolExplainQueryWrapped     = wrapTool("explain_query", toolExplainQuery)
+	toolNormalizeQueryWrapped   = wrapTool("normalize_query", toolNormalizeQuery)
+	toolListViewsWrapped        = wrapTool("list_views", toolListViews)
+	toolViewLineageWrapped      = wrapTool("view_lineage", toolViewLineage)
+	toolListGhostTablesWrapped  = wrapTool("list_ghost_tables", toolListGhostTables)
+	toolListSummaryWrapped      = wrapTool("list_summary_tables", toolListSummaryTables)
+	toolDataFreshnessWrapped    = wrapTool("data_freshness", toolDataFreshness)
+	toolListTriggersWrapped     = wrapTool("list_triggers", toolListTriggers)
+	toolListProceduresWrapped   = wrapTool("list_procedures", toolListProcedures)
+	toolListFunctionsWrapped    = wrapTool("list_functions", toolListFunctions)
+	toolListPartitionsWrapped   = wrapTool("list_partitions", toolListPartitions)
+	toolDatabaseSizeWrapped     = wrapTool("database_size", toolDatabaseSize)
+	toolTableSizeWrapped        = wrapTool("table_size", toolTableSize)
+	toolForeignKeysWrapped      = wrapTool("foreign_keys", toolForeignKeys)
+	toolTableConstraintsWrapped = wrapTool("table_constraints", toolTableConstraints)
+	toolSpatialInfoWrapped      = wrapTool("spatial_info", toolSpatialInfo)
+	toolProfileColumnWrapped    = wrapTool("profile_column", toolProfileColumn)
+	toolPIIScanWrapped          = wrapTool("pii_scan", toolPIIScan)
+	toolHeatWaveStatusWrapped   = wrapTool("heatwave_status", toolHeatWaveStatus)
+	toolHeatWavePredictWrapped  = wrapTool("heatwave_ml_predict", toolHeatWaveMLPredict)
+	toolAuroraTopologyWrapped   = wrapTool("aurora_topology", toolAuroraTopology)
+	toolSchemaGraphWrapped      = wrapTool("schema_graph", toolSchemaGraph)
+	toolDataDictionaryWrapped   = wrapTool("generate_data_dictionary", toolGenerateDataDictionary)
+	toolFindColumnsWrapped      = wrapTool("find_columns", toolFindColumns)
+	toolSuggestIdentsWrapped    = wrapTool("suggest_identifiers", toolSuggestIdentifiers)
+	toolFulltextSearchWrapped   = wrapTool("fulltext_search", toolFulltextSearch)
+	toolPartitionPruningWrapped = wrapTool("check_partition_pruning", toolCheckPartitionPruning)
+	toolOptimizerTraceWrapped   = wrapTool("optimizer_trace", toolOptimizerTrace)
+	toolEstimateRowsWrapped     = wrapTool("estimate_rows", toolEstimateRows)
+	toolTableCountsWrapped      = wrapTool("table_counts", toolTableCounts)
+	toolBinlogStatusWrapped     = wrapTool("binlog_status", toolBinlogStatus)
+	toolShowGrantsWrapped       = wrapTool("show_grants", toolShowGrants)
+	toolListStatusWrapped       = wrapTool("list_status", toolListStatus)
+	toolListVariablesWrapped    = wrapTool("list_variables", toolListVariables)
+	toolResourceUsageWrapped    = wrapTool("resource_usage", toolResourceUsage)
+	toolHealthReportWrapped     = wrapTool("health_report", toolHealthReport)
+	toolMetricsHistoryWrapped   = wrapTool("metrics_history", toolMetricsHistory)
+
+	toolCheckSavedQueriesWrapped = wrapTool("check_saved_queries", toolCheckSavedQueries)
+	toolRunCrossDatabaseWrapped  = wrapTool("run_cross_database_query", toolRunCrossDatabaseQuery)
+	toolSessionSettingsWrapped   = wrapTool("session_settings", toolSessionSettings)
+	toolSetSessionSettingWrapped = wrapTool("set_session_setting", toolSetSessionSetting)
+
+	toolSearchSchemaWrapped = wrapTool("search_schema", toolSearchSchema)
+	toolSchemaDiffWrapped   = wrapTool("schema_diff", toolSchemaDiff)
+
+	toolProcessListWrapped  = wrapTool("process_list", toolProcessList)
+	toolKillQueryWrapped    = wrapTool("kill_query", toolKillQuery)
+	toolListSessionsWrapped = wrapTool("list_sessions", toolListSessions)
+	toolReadAuditLogWrapped = wrapTool("read_audit_log", toolReadAuditLog)
+	toolSlowQueryLogWrapped = wrapTool("slow_query_log", toolSlowQueryLog)
+)
@@ -1,15 +1,17 @@
-// cmd/mysql-mcp-server/tools.go
-package main
+// pkg/mysqlmcp/tools.go
+package mysqlmcp
 
 import (
 	"context"
 	"database/sql"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/askdba/mysql-mcp-server/internal/config"
 	"github.com/askdba/mysql-mcp-server/internal/dbretry"
 	"github.com/askdba/mysql-mcp-server/internal/util"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -28,17 +30,29 @@ func toolListDatabases(
 	input ListDatabasesInput,
 ) (*mcp.CallToolResult, ListDatabasesOutput, error) {
 
+	if input.Offset < 0 {
+		return nil, ListDatabasesOutput{}, fmt.Errorf("offset must be >= 0")
+	}
+	limit := listLimit(input.Limit)
+
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	// Use information_schema for better compatibility and to filter out system dbs if needed
-	rows, err := getDB().QueryContext(ctx, "SELECT SCHEMA_NAME FROM information_schema.SCHEMATA ORDER BY SCHEMA_NAME")
+	// Use information_schema for better compatibility and to filter out system dbs if needed.
+	// The allowlist is applied here rather than in SQL, so the page is cut in memory.
+	query := "SELECT SCHEMA_NAME FROM information_schema.SCHEMATA"
+	var args []interface{}
+	if input.Prefix != "" {
+		query += " WHERE SCHEMA_NAME LIKE ? ESCAPE '!'"
+		args = append(args, likePrefixPattern(input.Prefix))
+	}
+	rows, err := getDB().QueryContext(ctx, query+" ORDER BY SCHEMA_NAME", args...)
 	if err != nil {
 		return nil, ListDatabasesOutput{}, fmt.Errorf("ListDatabases failed: %w", err)
 	}
 	defer rows.Close()
 
-	out := ListDatabasesOutput{Databases: []DatabaseInfo{}}
+	var names []string
 	for rows.Next() {
 		var name string
 		if err := rows.Scan(&name); err != nil {
@@ -47,15 +61,24 @@ func toolListDatabases(
 		if !databaseAllowed(name) {
 			continue
 		}
-		out.Databases = append(out.Databases, DatabaseInfo{Name: name})
-		if len(out.Databases) >= maxRows {
-			break
-		}
+		names = append(names, name)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, ListDatabasesOutput{}, fmt.Errorf("row iteration failed: %w", err)
 	}
 
+	out := ListDatabasesOutput{Databases: []DatabaseInfo{}, Total: len(names)}
+	start := min(input.Offset, len(names))
+	end := len(names)
+	if start+limit < end {
+		end = start + limit
+		out.HasMore = true
+		out.NextOffset = &end
+	}
+	for _, name := range names[start:end] {
+		out.Databases = append(out.Databases, DatabaseInfo{Name: name})
+	}
+
 	return nil, out, nil
 }
 
@@ -72,16 +95,62 @@ func toolListTables(
 		return nil, ListTablesOutput{}, err
 	}
 
+	if input.Offset < 0 {
+		return nil, ListTablesOutput{}, fmt.Errorf("offset must be >= 0")
+	}
+	limit := listLimit(input.Limit)
+	sortField, desc, err := parseListSort(input.Sort, tableListFields)
+	if err != nil {
+		return nil, ListTablesOutput{}, err
+	}
+	filters, err := parseListFilters(input.Filter, tableListFields)
+	if err != nil {
+		return nil, ListTablesOutput{}, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
 	// Fetch enhanced table metadata in a single query
-	query := `SELECT TABLE_NAME, ENGINE, TABLE_ROWS, TABLE_COMMENT 
+	query := `SELECT TABLE_NAME, ENGINE, TABLE_ROWS, TABLE_COMMENT`
+	if input.IncludeMetadata {
+		query += `, TABLE_TYPE, CREATE_TIME, UPDATE_TIME,
+			  ROUND(DATA_LENGTH / 1024 / 1024, 2), ROUND(INDEX_LENGTH / 1024 / 1024, 2)`
+	}
+	where := ` 
 			  FROM information_schema.TABLES 
-			  WHERE TABLE_SCHEMA = ?
-			  ORDER BY TABLE_NAME`
+			  WHERE TABLE_SCHEMA = ?`
+	args := []interface{}{input.Database}
+	if input.Pattern != "" {
+		where += " AND TABLE_NAME LIKE ?"
+		args = append(args, input.Pattern)
+	}
+	if input.Prefix != "" {
+		where += " AND TABLE_NAME LIKE ? ESCAPE '!'"
+		args = append(args, likePrefixPattern(input.Prefix))
+	}
+	predicates, filterArgs := listSQLPredicates(filters, tableListFields)
+	where += predicates
+	args = append(args, filterArgs...)
+	query += where
+
+	// Sorted fields other than the name keep the name as a tie-breaker so
+	// pages are stable. Fetch one extra row to detect whether another page exists.
+	order := "TABLE_NAME"
+	if sortField != "" && sortField != "name" {
+		order = tableListFields[sortField].expr
+		if desc {
+			order += " DESC"
+		}
+		order += ", TABLE_NAME"
+	} else if desc {
+		order += " DESC"
+	}
+	query += `
+			  ORDER BY ` + order + ` LIMIT ? OFFSET ?`
+	pageArgs := append(append([]interface{}{}, args...), limit+1, input.Offset)
 
-	rows, err := getDB().QueryContext(ctx, query, input.Database)
+	rows, err := getDB().QueryContext(ctx, query, pageArgs...)
 	if err != nil {
 		return nil, ListTablesOutput{}, fmt.Errorf("ListTables failed: %w", err)
 	}
@@ -95,33 +164,69 @@ func toolListTables(
 	out := ListTablesOutput{Tables: []TableInfo{}}
 	for rows.Next() {
 		var name string
-		var engine, comment sql.NullString
+		var engine, comment, tableType, created, updated sql.NullString
 		var tableRows sql.NullInt64
+		var dataMB, indexMB sql.NullFloat64
 
-		if err := rows.Scan(&name, &engine, &tableRows, &comment); err != nil {
+		dest := []interface{}{&name, &engine, &tableRows, &comment}
+		if input.IncludeMetadata {
+			dest = append(dest, &tableType, &created, &updated, &dataMB, &indexMB)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, ListTablesOutput{}, fmt.Errorf("scan failed: %w", err)
 		}
+		if len(out.Tables) == limit {
+			out.HasMore = true
+			break
+		}
 
 		info := TableInfo{
-			Name:    name,
-			Engine:  engine.String,
-			Comment: comment.String,
+			Name:      name,
+			Engine:    engine.String,
+			Comment:   comment.String,
+			Type:      tableType.String,
+			CreatedAt: created.String,
+			UpdatedAt: updated.String,
 		}
 		if tableRows.Valid {
 			rowsVal := tableRows.Int64
 			info.Rows = &rowsVal
 		}
+		if dataMB.Valid {
+			v := dataMB.Float64
+			info.DataMB = &v
+		}
+		if indexMB.Valid {
+			v := indexMB.Float64
+			info.IndexMB = &v
+		}
 
 		out.Tables = append(out.Tables, info)
-		if len(out.Tables) >= maxRows {
-			break
-		}
 	}
 	if err := rows.Err(); err != nil {
 		return nil, ListTablesOutput{}, fmt.Errorf("ListTables rows iteration: %w", err)
 	}
+	if out.HasMore {
+		next := input.Offset + limit
+		out.NextOffset = &next
+	}
+	if input.IncludeTotal {
+		// The total is known without counting unless rows remain past this
+		// page or the offset is past the last row.
+		total := input.Offset + len(out.Tables)
+		if out.HasMore || (len(out.Tables) == 0 && input.Offset > 0) {
+			if err := rows.Close(); err != nil {
+				return nil, ListTablesOutput{}, fmt.Errorf("failed to close rows: %w", err)
+			}
+			rowsClosed = true
+			if err := getDB().QueryRowContext(ctx, "SELECT COUNT(*)"+where, args...).Scan(&total); err != nil {
+				return nil, ListTablesOutput{}, fmt.Errorf("ListTables count failed: %w", err)
+			}
+		}
+		out.Total = &total
+	}
 
-	if len(out.Tables) == 0 {
+	if len(out.Tables) == 0 && input.Offset == 0 {
 		if !rowsClosed {
 			if err := rows.Close(); err != nil {
 				return nil, ListTablesOutput{}, fmt.Errorf("failed to close rows: %w", err)
@@ -160,14 +265,7 @@ func toolDescribeTable(
 	defer cancel()
 
 	// Fetch comprehensive column info from information_schema
-	query := `SELECT 
-				COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, 
-				COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, COLLATION_NAME
-			  FROM information_schema.COLUMNS 
-			  WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
-			  ORDER BY ORDINAL_POSITION`
-
-	rows, err := getDB().QueryContext(ctx, query, input.Database, input.Table)
+	rows, err := queryPrepared(ctx, queryDescribeTable, input.Database, input.Table)
 	if err != nil {
 		return nil, DescribeTableOutput{}, fmt.Errorf("DescribeTable failed: %w", err)
 	}
@@ -175,10 +273,10 @@ func toolDescribeTable(
 
 	out := DescribeTableOutput{Columns: []ColumnInfo{}}
 	for rows.Next() {
-		var name, colType, nullable, key, extra, comment, collation sql.NullString
+		var name, colType, nullable, key, extra, comment, collation, generation sql.NullString
 		var dataDefault sql.NullString // Defaults can be null
 
-		if err := rows.Scan(&name, &colType, &nullable, &key, &dataDefault, &extra, &comment, &collation); err != nil {
+		if err := rows.Scan(&name, &colType, &nullable, &key, &dataDefault, &extra, &comment, &collation, &generation); err != nil {
 			return nil, DescribeTableOutput{}, fmt.Errorf("scan failed: %w", err)
 		}
 
@@ -192,6 +290,7 @@ func toolDescribeTable(
 			Comment:   comment.String,
 			Collation: collation.String,
 		}
+		setGeneration(&col, generation)
 		out.Columns = append(out.Columns, col)
 		if len(out.Columns) >= maxRows {
 			break
@@ -224,11 +323,7 @@ func toolDescribeTable(
 
 func schemaExists(ctx context.Context, database string) (bool, error) {
 	var found int
-	err := getDB().QueryRowContext(
-		ctx,
-		"SELECT 1 FROM information_schema.SCHEMATA WHERE SCHEMA_NAME = ? LIMIT 1",
-		database,
-	).Scan(&found)
+	err := queryRowPrepared(ctx, querySchemaExists, database).Scan(&found)
 	if err == nil {
 		return true, nil
 	}
@@ -240,12 +335,7 @@ func schemaExists(ctx context.Context, database string) (bool, error) {
 
 func tableExists(ctx context.Context, database, table string) (bool, error) {
 	var found int
-	err := getDB().QueryRowContext(
-		ctx,
-		"SELECT 1 FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? LIMIT 1",
-		database,
-		table,
-	).Scan(&found)
+	err := queryRowPrepared(ctx, queryTableExists, database, table).Scan(&found)
 	if err == nil {
 		return true, nil
 	}
@@ -255,6 +345,13 @@ func tableExists(ctx context.Context, database, table string) (bool, error) {
 	return false, fmt.Errorf("table existence check failed: %w", err)
 }
 
+// setGeneration records information_schema.COLUMNS.GENERATION_EXPRESSION on
+// col. The column is empty (MySQL) or NULL (MariaDB) for ordinary columns.
+func setGeneration(col *ColumnInfo, expr sql.NullString) {
+	col.GenerationExpression = strings.TrimSpace(expr.String)
+	col.IsGenerated = col.GenerationExpression != ""
+}
+
 // scanAndNormalizeRow reads one row from rows and returns normalized cell values.
 func scanAndNormalizeRow(rows *sql.Rows, ncols int) ([]interface{}, error) {
 	values := make([]interface{}, ncols)
@@ -272,28 +369,88 @@ func scanAndNormalizeRow(rows *sql.Rows, ncols int) ([]interface{}, error) {
 	return rowValues, nil
 }
 
-// runQueryScan executes finalSQL on a dedicated connection (USE database when set),
-// scans rows, and enforces limit. When paginated is true, finalSQL must request at
+// defaultRowLimit returns the run_query row cap for database: the per-database
+// override from MYSQL_MCP_DATABASE_MAX_ROWS / query.database_max_rows when one
+// matches, otherwise maxRows.
+func defaultRowLimit(database string) int {
+	if n, ok := databaseRowLimit(database); ok {
+		return n
+	}
+	return maxRows
+}
+
+// databaseRowLimit returns the per-database row cap configured for database.
+func databaseRowLimit(database string) (int, bool) {
+	if cfg == nil || database == "" || len(cfg.DatabaseMaxRows) == 0 {
+		return 0, false
+	}
+	if n, ok := cfg.DatabaseMaxRows[database]; ok {
+		return n, true
+	}
+	for name, n := range cfg.DatabaseMaxRows {
+		if strings.EqualFold(name, database) {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// withMaxExecutionTime adds a MAX_EXECUTION_TIME hint of the time left before
+// ctx's deadline (the tool timeout) to a SELECT, so MySQL stops the query
+// itself instead of running on after the client gives up. MariaDB spells this
+// max_statement_time and is skipped, as are servers of unknown type.
+func withMaxExecutionTime(ctx context.Context, sqlText string) string {
+	if cfg != nil && !cfg.MaxExecTimeHint {
+		return sqlText
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok || getServerType() != ServerTypeMySQL {
+		return sqlText
+	}
+	return util.InjectMaxExecutionTime(sqlText, time.Until(deadline).Milliseconds())
+}
+
+// runQueryScan executes finalSQL on a dedicated connection, or on the connection
+// of the Session in ctx (USE database when set), scans rows, and enforces limit. When paginated is true, finalSQL must request at
 // most limit+1 rows (server-side); HasMore and NextOffset are derived from the extra row.
-// limit must be positive when paginated is true (callers validate).
-func runQueryScan(ctx context.Context, db *sql.DB, finalSQL, database string, limit int, paginated bool, pageOffset int) (QueryResult, error) {
-	conn, err := db.Conn(ctx)
+// limit must be positive when paginated is true (callers validate). binary is the
+// resolved binary_output mode. args are bound to ? placeholders in finalSQL.
+func runQueryScan(ctx context.Context, db *sql.DB, finalSQL, database string, limit int, paginated bool, pageOffset int, binary string, args ...interface{}) (QueryResult, error) {
+	finalSQL, database, err := scopeStatement(db, finalSQL, database)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	conn, release, err := acquireConn(ctx, db)
 	if err != nil {
-		return QueryResult{}, fmt.Errorf("failed to get connection: %w", err)
+		return QueryResult{}, err
 	}
-	defer conn.Close()
+	defer release()
 
-	if database != "" {
-		quotedDB, err := util.QuoteIdent(database)
-		if err != nil {
-			return QueryResult{}, fmt.Errorf("invalid database name: %w", err)
-		}
-		if _, err := conn.ExecContext(ctx, "USE "+quotedDB); err != nil {
-			return QueryResult{}, fmt.Errorf("failed to select database '%s': %w", database, err)
-		}
+	restoreDatabase, err := useDatabase(ctx, db, conn, database)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer restoreDatabase()
+
+	restoreTimeZone, err := useTimeZone(ctx, conn, queryTimeZone(ctx))
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer restoreTimeZone()
+
+	restoreSettings, err := useSessionSettings(ctx, conn)
+	if err != nil {
+		return QueryResult{}, err
+	}
+	defer restoreSettings()
+
+	stopWatchdog, err := killQueryOnCancel(ctx, db, conn)
+	if err != nil {
+		return QueryResult{}, err
 	}
+	defer stopWatchdog()
 
-	rows, err := conn.QueryContext(ctx, finalSQL)
+	rows, err := conn.QueryContext(ctx, withMaxExecutionTime(ctx, finalSQL), args...)
 	if err != nil {
 		return QueryResult{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -315,9 +472,22 @@ func runQueryScan(ctx context.Context, db *sql.DB, finalSQL, database string, li
 		rowsClosed = true
 		return QueryResult{}, fmt.Errorf("failed to get columns: %w", err)
 	}
-	out.Columns = columns
+	renderer, err := newCellRenderer(rows, binary)
+	if err != nil {
+		_ = rows.Close()
+		rowsClosed = true
+		return QueryResult{}, err
+	}
+	out.Columns, out.SkippedColumns = renderer.columns(columns)
+	if types, err := rows.ColumnTypes(); err == nil {
+		out.TemporalColumns = temporalColumns(types)
+		out.ColumnTypes = columnTypes(types, renderer, len(out.Columns))
+	}
 
 	ncols := len(columns)
+	budget := int64(maxResultBytes)
+	overBudget := false
+	unordered := false
 	for rows.Next() {
 		rowValues, err := scanAndNormalizeRow(rows, ncols)
 		if err != nil {
@@ -325,9 +495,45 @@ func runQueryScan(ctx context.Context, db *sql.DB, finalSQL, database string, li
 			rowsClosed = true
 			return QueryResult{}, err
 		}
+		rowValues = renderer.row(rowValues)
 		if len(out.Rows) < limit {
+			size := rowBytes(rowValues)
+			if budget > 0 && out.SizeBytes+size > budget {
+				// Keep as much of the crossing row as fits, then stop.
+				overBudget = true
+				full := append([]interface{}(nil), rowValues...)
+				cut, fits := truncateRowToFit(rowValues, budget-out.SizeBytes)
+				if !fits {
+					break
+				}
+				out.TruncatedCells += len(cut)
+				// fetch_cell finds a cell again by its row number, which only
+				// holds when the query fixes the order of its rows.
+				unordered = !util.HasOrderBy(finalSQL)
+				ref := cellQuery{
+					Connection: connectionNameFor(db), Database: database, SQL: finalSQL, Args: args, Columns: columns,
+					TimeZone: queryTimeZone(ctx), Renderer: renderer,
+				}
+				for _, col := range cut {
+					if unordered || withheldColumn(out.Columns[col]) {
+						continue
+					}
+					fullValue := full[col].(string)
+					out.CellHandles = append(out.CellHandles, CellHandle{
+						Row:       len(out.Rows),
+						Column:    out.Columns[col],
+						FullBytes: len(fullValue),
+						Handle:    cellHandles.register(ref, len(out.Rows), renderer.sourceColumn(col), shownPrefix(fullValue, rowValues[col].(string))),
+					})
+				}
+				size = rowBytes(rowValues)
+			}
 			out.Rows = append(out.Rows, rowValues)
-			continue
+			out.SizeBytes += size
+			if !overBudget {
+				continue
+			}
+			break
 		}
 		if paginated {
 			out.HasMore = true
@@ -340,6 +546,17 @@ func runQueryScan(ctx context.Context, db *sql.DB, finalSQL, database string, li
 		rowsClosed = true
 		break
 	}
+	if overBudget {
+		if paginated {
+			out.HasMore = true
+		} else {
+			out.Truncated = true
+		}
+		out.Warning = fmt.Sprintf("result stopped at %d rows by the %d-byte result limit (MYSQL_MCP_MAX_RESULT_BYTES); select fewer or shorter columns", len(out.Rows), budget)
+		if unordered && out.TruncatedCells > 0 {
+			out.Warning += "; cut cells get no cell_handles because the query has no ORDER BY, add one on a unique key to read them with fetch_cell"
+		}
+	}
 
 	if !rowsClosed {
 		if err := rows.Err(); err != nil {
@@ -354,9 +571,16 @@ func runQueryScan(ctx context.Context, db *sql.DB, finalSQL, database string, li
 	}
 
 	if out.HasMore {
-		next := pageOffset + limit
+		next := pageOffset + len(out.Rows)
 		out.NextOffset = &next
 	}
+	if len(out.TemporalColumns) > 0 {
+		// Best effort: a result without the zone is still a result.
+		out.TimeZone, out.UTCOffset, _ = sessionTimeZone(ctx, conn)
+	}
+	if n := util.NormalizeQuery(finalSQL); n.Parsed {
+		out.SourceTables = n.Tables
+	}
 
 	return out, nil
 }
@@ -366,7 +590,7 @@ func toolRunQuery(
 	req *mcp.CallToolRequest,
 	input RunQueryInput,
 ) (*mcp.CallToolResult, QueryResult, error) {
-	timer := NewQueryTimer("run_query")
+	timer := NewQueryTimer(ctx, "run_query")
 
 	sqlText := strings.TrimSpace(input.SQL)
 	if sqlText == "" {
@@ -391,16 +615,17 @@ func toolRunQuery(
 	}
 
 	// Enhanced SQL validation using parser + regex defense-in-depth
-	if err := util.ValidateSQLCombined(sqlText); err != nil {
-		logWarn("query rejected by validator", map[string]interface{}{
+	if err := validateSQL(sqlText); err != nil {
+		validatorLog.Warn("query rejected by validator", map[string]interface{}{
 			"error": err.Error(),
-			"query": util.TruncateQuery(sqlText, 200),
+			"query": loggedSQL(sqlText, 200),
 		})
 		if auditLogger != nil {
-			auditLogger.Log(&AuditEntry{
+			auditLogger.LogContext(ctx, &AuditEntry{
 				Tool:        "run_query",
 				Database:    database,
-				Query:       util.TruncateQuery(sqlText, 500),
+				Query:       loggedSQL(sqlText, 500),
+				QueryDigest: util.QueryDigest(sqlText),
 				InputTokens: inputTokens,
 				Success:     false,
 				Error:       err.Error(),
@@ -411,9 +636,17 @@ func toolRunQuery(
 	if err := requireReferencedSchemasInQuery(sqlText); err != nil {
 		return nil, QueryResult{}, err
 	}
+	if err := requireConfirmation(input.Confirm); err != nil {
+		return nil, QueryResult{}, err
+	}
+	execSQL, err := applyRowPolicies(sqlText, database)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
 
-	limit := maxRows
-	if input.MaxRows != nil && *input.MaxRows > 0 && *input.MaxRows < maxRows {
+	rowCap := defaultRowLimit(database)
+	limit := rowCap
+	if input.MaxRows != nil && *input.MaxRows > 0 && *input.MaxRows < rowCap {
 		limit = *input.MaxRows
 	}
 	if limit < 0 {
@@ -435,60 +668,137 @@ func toolRunQuery(
 		}
 	}
 
+	binary, err := resolveBinaryOutput(input.BinaryOutput)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+	timeZone, err := resolveTimeZone(input.TimeZone)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+	format, err := resolveOutputFormat(input.Format)
+	if err != nil {
+		return nil, QueryResult{}, err
+	}
+
+	dupKey := duplicateKey(ctx, input, execSQL)
+	if out, ok := duplicates.lookup(dupKey); ok {
+		serverLog.Info("duplicate query suppressed", map[string]interface{}{
+			"repeats":    out.DuplicateRepeats,
+			"query":      loggedSQL(sqlText, 200),
+			"request_id": requestIDFrom(ctx),
+		})
+		// The rows still reach the caller, so the call is audited like any read.
+		if auditLogger != nil {
+			auditLogger.LogContext(ctx, &AuditEntry{
+				Tool:        "run_query",
+				Database:    database,
+				Query:       loggedSQL(execSQL, 500),
+				QueryDigest: util.QueryDigest(sqlText),
+				DurationMs:  timer.ElapsedMs(),
+				RowCount:    len(out.Rows),
+				InputTokens: inputTokens,
+				Success:     true,
+				Duplicate:   true,
+			})
+		}
+		return formattedResult(format, out), out, nil
+	}
+
 	// Detect SELECT * before rewriting so we can surface a warning.
 	hasStar := util.HasSelectStar(sqlText)
 
 	var finalSQL string
 	if usePagination {
 		var err error
-		finalSQL, err = util.InjectLimitWithOffset(sqlText, limit+1, pageOffset)
+		finalSQL, err = util.InjectLimitWithOffset(execSQL, limit+1, pageOffset)
 		if err != nil {
 			return nil, QueryResult{}, fmt.Errorf("pagination: %w", err)
 		}
-	} else {
+	} else if cfg == nil || cfg.InjectLimit {
 		// Inject a server-side LIMIT so MySQL stops processing early.
 		// This is a best-effort optimization; we still enforce the row cap on
 		// the client side below to guard against non-SELECT statements where
 		// InjectLimit is a no-op.
-		finalSQL = util.InjectLimit(sqlText, limit)
+		finalSQL = util.InjectLimit(execSQL, limit)
+	} else {
+		// Injection disabled (MYSQL_MCP_INJECT_LIMIT=0): run the SQL as written
+		// and truncate on the client side.
+		finalSQL = execSQL
 	}
 
-	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	ctx, cancel := context.WithTimeout(withQueryTimeZone(ctx, timeZone), queryTimeoutFor(ctx))
 	defer cancel()
 
-	db := getDB()
+	db := getReadDB(ctx)
 	var out QueryResult
-	err := dbretry.Do(ctx, db, dbRetryCfg, pingTimeout, func() error {
+	stats, err := dbretry.DoWithStats(ctx, db, dbRetryCfg, pingTimeout, func() error {
 		var e error
-		out, e = runQueryScan(ctx, db, finalSQL, database, limit, usePagination, pageOffset)
+		out, e = runQueryScan(ctx, db, finalSQL, database, limit, usePagination, pageOffset, binary)
 		return e
 	})
+	out.Retries, out.RetryReason = retryMetadata(ctx, "run_query", stats)
 	if err != nil {
 		timer.LogError(err, finalSQL, tokens, nil)
 		if auditLogger != nil {
-			auditLogger.Log(&AuditEntry{
+			auditLogger.LogContext(ctx, &AuditEntry{
 				Tool:        "run_query",
 				Database:    database,
-				Query:       util.TruncateQuery(finalSQL, 500),
+				Query:       loggedSQL(finalSQL, 500),
+				QueryDigest: util.QueryDigest(sqlText),
 				DurationMs:  timer.ElapsedMs(),
 				InputTokens: inputTokens,
 				Success:     false,
 				Error:       err.Error(),
 			})
 		}
+		if cfg == nil || cfg.SuggestNames {
+			err = suggestUnknownIdentifiers(ctx, err, sqlText, database)
+		}
 		return nil, QueryResult{}, err
 	}
 
 	// Attach a warning when SELECT * was used so the AI can adjust future queries.
 	if hasStar {
-		out.Warning = "SELECT * retrieves all columns, which increases payload size. " +
+		starWarning := "SELECT * retrieves all columns, which increases payload size. " +
 			"Specify only the columns you need for better performance."
+		if out.Warning != "" {
+			starWarning = out.Warning + "; " + starWarning
+		}
+		out.Warning = starWarning
+	}
+
+	// Compare rows before masking, which can make distinct rows look alike.
+	returned := len(out.Rows)
+	dups := dedupeResult(&out, input.Dedupe)
+	if input.Dedupe {
+		out.DuplicateRows = dups
+	}
+	if hint := duplicateAdvisory(dups, returned, out.SourceTables, input.Dedupe); hint != "" {
+		if out.Warning != "" {
+			hint = out.Warning + "; " + hint
+		}
+		out.Warning = hint
+	}
+
+	// Tell the agent which environment answered when connections are labeled.
+	if c, ok := activeConnectionConfig(); ok && c.Environment != "" {
+		out.Connection, out.Environment = c.Name, c.Environment
 	}
 
-	// Apply column masking if configured
+	// Apply column masking and pseudonymization if configured
 	if cfg != nil && len(cfg.MaskColumns) > 0 {
 		maskResults(out.Columns, out.Rows, cfg.MaskColumns)
 	}
+	pseudonymizeResults(ctx, out.Columns, out.Rows)
+	if pii := piiResultColumns(out.Columns); len(pii) > 0 {
+		out.PIIColumns = pii
+		piiWarning := "Result contains likely PII in columns: " + strings.Join(pii, ", ")
+		if out.Warning != "" {
+			piiWarning = out.Warning + "; " + piiWarning
+		}
+		out.Warning = piiWarning
+	}
 
 	// Token estimation for output (optional)
 	outputTokens, _ := estimateTokensForValue(out)
@@ -509,7 +819,8 @@ func toolRunQuery(
 		entry := &AuditEntry{
 			Tool:         "run_query",
 			Database:     database,
-			Query:        util.TruncateQuery(finalSQL, 500),
+			Query:        loggedSQL(finalSQL, 500),
+			QueryDigest:  util.QueryDigest(sqlText),
 			DurationMs:   timer.ElapsedMs(),
 			RowCount:     len(out.Rows),
 			InputTokens:  inputTokens,
@@ -521,10 +832,28 @@ func toolRunQuery(
 			entry.IOEfficiency = eff.IOEfficiency
 			entry.CostEstimateUSD = eff.CostEstimateUSD
 		}
-		auditLogger.Log(entry)
+		auditLogger.LogContext(ctx, entry)
 	}
 
-	return nil, out, nil
+	duplicates.store(dupKey, out)
+	return formattedResult(format, out), out, nil
+}
+
+// retryMetadata logs the transient-error retries of a query and returns the
+// retry count and reason reported in its result.
+func retryMetadata(ctx context.Context, tool string, stats dbretry.Stats) (int, string) {
+	n := stats.Retries()
+	if n == 0 {
+		return 0, ""
+	}
+	reason := dbretry.Reason(stats.LastRetry)
+	serverLog.Info("query retried", map[string]interface{}{
+		"tool":       tool,
+		"retries":    n,
+		"reason":     reason,
+		"request_id": requestIDFrom(ctx),
+	})
+	return n, reason
 }
 
 func toolPing(
@@ -536,7 +865,7 @@ func toolPing(
 	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
 	defer cancel()
 
-	start := NewQueryTimer("ping")
+	start := NewQueryTimer(ctx, "ping")
 	db := getDB()
 	err := dbretry.Do(ctx, db, dbRetryCfg, pingTimeout, func() error {
 		return db.PingContext(ctx)
@@ -576,6 +905,8 @@ func toolServerInfo(
 	}
 
 	out.ServerEngine = string(getServerType())
+	out.Capabilities, _ = connManager.ActiveCapabilities()
+	out.Middleware, out.ProxyCompat = activeMiddleware(), proxyCompat()
 
 	// Get various server variables in one query
 	rows, err := getDB().QueryContext(ctx, `
@@ -585,7 +916,9 @@ func toolServerInfo(
 			'version_comment',
 			'character_set_server',
 			'collation_server',
-			'max_connections'
+			'max_connections',
+			'thread_handling',
+			'audit_log_policy'
 		)
 	`)
 	if err != nil {
@@ -595,7 +928,9 @@ func toolServerInfo(
 				'version_comment',
 				'character_set_server',
 				'collation_server',
-				'max_connections'
+				'max_connections',
+				'thread_handling',
+				'audit_log_policy'
 			)
 		`)
 		if err != nil {
@@ -604,6 +939,8 @@ func toolServerInfo(
 	}
 	defer rows.Close()
 
+	var threadHandling string
+	var auditLog bool
 	for rows.Next() {
 		var name, value string
 		if err := rows.Scan(&name, &value); err != nil {
@@ -618,23 +955,35 @@ func toolServerInfo(
 			out.Collation = value
 		case "max_connections":
 			out.MaxConnections, _ = strconv.Atoi(value)
+		case "thread_handling":
+			threadHandling = value
+		case "audit_log_policy":
+			// Only defined while the audit_log plugin is loaded.
+			auditLog = true
 		}
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, ServerInfoOutput{}, fmt.Errorf("server variables iteration failed: %w", err)
 	}
+	if strings.Contains(strings.ToLower(out.VersionComment), "percona") {
+		out.Percona = &PerconaInfo{
+			ThreadPool:     strings.EqualFold(threadHandling, "pool-of-threads"),
+			ThreadHandling: threadHandling,
+			AuditLog:       auditLog,
+		}
+	}
 
-	// Get uptime and threads connected from status
+	// Get uptime, threads connected and the HeatWave (RAPID) status from status
 	statusRows, err := getDB().QueryContext(ctx, `
 		SELECT VARIABLE_NAME, VARIABLE_VALUE
 		FROM performance_schema.global_status
-		WHERE VARIABLE_NAME IN ('Uptime', 'Threads_connected')
+		WHERE VARIABLE_NAME IN ('Uptime', 'Threads_connected', `+heatWaveStatusList+`)
 	`)
 	if err != nil {
 		// Fallback for older MySQL or restricted permissions
 		statusRows, err = getDB().QueryContext(ctx, `
-			SHOW GLOBAL STATUS WHERE Variable_name IN ('Uptime', 'Threads_connected')
+			SHOW GLOBAL STATUS WHERE Variable_name IN ('Uptime', 'Threads_connected', `+heatWaveStatusList+`)
 		`)
 		if err != nil {
 			return nil, ServerInfoOutput{}, fmt.Errorf("failed to get server status: %w", err)
@@ -642,22 +991,26 @@ func toolServerInfo(
 	}
 	defer statusRows.Close()
 
+	rapid := map[string]string{}
 	for statusRows.Next() {
 		var name, value string
 		if err := statusRows.Scan(&name, &value); err != nil {
 			continue
 		}
-		switch strings.ToLower(name) {
+		switch name = strings.ToLower(name); name {
 		case "uptime":
 			out.Uptime, _ = strconv.ParseInt(value, 10, 64)
 		case "threads_connected":
 			out.ThreadsConnected, _ = strconv.Atoi(value)
+		default:
+			rapid[name] = value
 		}
 	}
 
 	if err := statusRows.Err(); err != nil {
 		return nil, ServerInfoOutput{}, fmt.Errorf("server status iteration failed: %w", err)
 	}
+	out.HeatWave = heatWaveFromStatus(rapid)
 
 	// Get current user and database
 	row = getDB().QueryRowContext(ctx, "SELECT CURRENT_USER(), IFNULL(DATABASE(), '')")
@@ -767,12 +1120,93 @@ func toolListConnections(
 		Active:      activeName,
 	}
 
+	replicas := map[string][]string{}
 	for _, cfg := range configs {
-		out.Connections = append(out.Connections, ConnectionInfo{
-			Name:        cfg.Name,
-			DSN:         cfg.DSN, // Already masked
-			Description: cfg.Description,
-			Active:      cfg.Name == activeName,
+		if cfg.ReplicaOf != "" {
+			replicas[cfg.ReplicaOf] = append(replicas[cfg.ReplicaOf], cfg.Name)
+		}
+	}
+	for _, cfg := range configs {
+		info := ConnectionInfo{
+			Name:            cfg.Name,
+			DSN:             cfg.DSN, // Already masked
+			Description:     cfg.Description,
+			Environment:     cfg.Environment,
+			Tags:            cfg.Tags,
+			RequiresConfirm: confirmationLabel(cfg) != "",
+			RowPolicies:     rowPolicyTables(cfg),
+			ReplicaOf:       cfg.ReplicaOf,
+			ConnectOnDemand: cfg.ConnectOnDemand,
+			Pending:         connManager.Pending(cfg.Name),
+			Circuit:         breakers.state(cfg.Name),
+			Active:          cfg.Name == activeName,
+		}
+		if info.Active {
+			info.AuroraRole = activeAuroraRole(ctx)
+		}
+		if members := replicas[cfg.Name]; len(members) > 0 {
+			sort.Strings(members)
+			info.Replicas = members
+			info.ReplicaRouting = cfg.ReplicaRouting
+			if info.ReplicaRouting == "" {
+				info.ReplicaRouting = config.DefaultReplicaRouting
+			}
+		}
+		out.Connections = append(out.Connections, info)
+	}
+
+	// Once locked, other tenants' virtual connections are not listed.
+	if vc := activeVirtualConnection(); vc != nil {
+		out.VirtualConnection = vc.Name
+		out.VirtualConnections = []VirtualConnectionInfo{virtualConnectionInfo(*vc, true)}
+	} else {
+		names := make([]string, 0, len(virtualConnections))
+		for name := range virtualConnections {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			out.VirtualConnections = append(out.VirtualConnections, virtualConnectionInfo(virtualConnections[name], false))
+		}
+	}
+
+	return nil, out, nil
+}
+
+func toolPoolStats(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input PoolStatsInput,
+) (*mcp.CallToolResult, PoolStatsOutput, error) {
+	if connManager == nil {
+		return nil, PoolStatsOutput{}, fmt.Errorf("connection manager not initialized")
+	}
+
+	stats := connManager.Stats()
+	_, activeName := connManager.GetActive()
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := PoolStatsOutput{Pools: make([]PoolStats, 0, len(names)), Active: activeName, QueuedCalls: queuedCalls()}
+	for _, name := range names {
+		st := stats[name]
+		out.Pools = append(out.Pools, PoolStats{
+			Name:              name,
+			Active:            name == activeName,
+			MaxOpen:           st.MaxOpenConnections,
+			Open:              st.OpenConnections,
+			InUse:             st.InUse,
+			Idle:              st.Idle,
+			WaitCount:         st.WaitCount,
+			WaitDurationMs:    st.WaitDuration.Milliseconds(),
+			MaxIdleClosed:     st.MaxIdleClosed,
+			MaxIdleTimeClosed: st.MaxIdleTimeClosed,
+			MaxLifetimeClosed: st.MaxLifetimeClosed,
+			Circuit:           breakers.state(name),
 		})
 	}
 
@@ -792,6 +1226,12 @@ func toolUseConnection(
 		return nil, UseConnectionOutput{}, fmt.Errorf("connection name is required")
 	}
 
+	if _, ok := lookupVirtualConnection(input.Name); ok {
+		return useVirtualConnection(input.Name)
+	}
+	if err := requireConnectionSwitch(input.Name); err != nil {
+		return nil, UseConnectionOutput{}, err
+	}
 	if err := connManager.SetActive(input.Name); err != nil {
 		return nil, UseConnectionOutput{
 			Success: false,
@@ -804,13 +1244,13 @@ func toolUseConnection(
 	var dbQueryErr error
 	if err := getDB().QueryRowContext(ctx, "SELECT DATABASE()").Scan(&currentDB); err != nil {
 		dbQueryErr = err
-		logWarn("failed to get current database after connection switch", map[string]interface{}{
+		poolLog.Warn("failed to get current database after connection switch", map[string]interface{}{
 			"connection": input.Name,
 			"error":      err.Error(),
 		})
 	}
 
-	logInfo("switched connection", map[string]interface{}{
+	poolLog.Info("switched connection", map[string]interface{}{
 		"connection": input.Name,
 	})
 
@@ -828,6 +1268,22 @@ func toolUseConnection(
 }
 
 func maskResults(cols []string, rows [][]interface{}, patterns []string) {
+	maskIndices := maskedColumns(cols, patterns)
+	if len(maskIndices) == 0 {
+		return
+	}
+
+	for _, row := range rows {
+		for idx := range maskIndices {
+			if idx < len(row) && row[idx] != nil {
+				row[idx] = "********"
+			}
+		}
+	}
+}
+
+// maskedColumns returns the indexes of cols matching any of patterns.
+func maskedColumns(cols []string, patterns []string) map[int]bool {
 	var nonEmpty []string
 	for _, p := range patterns {
 		if t := strings.TrimSpace(p); t != "" {
@@ -835,7 +1291,7 @@ func maskResults(cols []string, rows [][]interface{}, patterns []string) {
 		}
 	}
 	if len(nonEmpty) == 0 {
-		return
+		return nil
 	}
 
 	maskIndices := make(map[int]bool)
@@ -849,15 +1305,5 @@ func maskResults(cols []string, rows [][]interface{}, patterns []string) {
 		}
 	}
 
-	if len(maskIndices) == 0 {
-		return
-	}
-
-	for _, row := range rows {
-		for idx := range maskIndices {
-			if idx < len(row) && row[idx] != nil {
-				row[idx] = "********"
-			}
-		}
-	}
+	return maskIndices
 }
@@ -0,0 +1,195 @@
+// pkg/mysqlmcp/tools_aurora.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// aurora_topology reads information_schema.replica_host_status, which every
+// instance of an Aurora MySQL cluster keeps: one row per instance, the
+// writer's with SESSION_ID 'MASTER_SESSION_ID' and each reader's with its
+// replica lag. Instance and cluster endpoints follow from the connection's
+// host name when it is an RDS endpoint, since Aurora builds them all from
+// the same cluster suffix.
+
+const (
+	auroraRoleWriter     = "writer"
+	auroraRoleReader     = "reader"
+	auroraWriterSession  = "MASTER_SESSION_ID"
+	auroraEndpointDomain = ".rds.amazonaws.com"
+)
+
+// auroraStaleSeconds drops instances that stopped reporting (deleted or
+// failed readers stay in replica_host_status for a while).
+const auroraStaleSeconds = 300
+
+func toolAuroraTopology(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input AuroraTopologyInput,
+) (*mcp.CallToolResult, AuroraTopologyOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	db := getDB()
+	version, err := auroraVersion(ctx, db)
+	if err != nil {
+		return nil, AuroraTopologyOutput{}, err
+	}
+	out := AuroraTopologyOutput{AuroraVersion: version, Connection: activeConnectionName(), Instances: []AuroraInstance{}}
+	var readOnly int
+	if err := db.QueryRowContext(ctx, "SELECT @@aurora_server_id, @@innodb_read_only").Scan(&out.ServerID, &readOnly); err != nil {
+		return nil, AuroraTopologyOutput{}, fmt.Errorf("failed to read the Aurora instance: %w", err)
+	}
+	out.Role = auroraRoleWriter
+	if readOnly != 0 {
+		out.Role = auroraRoleReader
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT SERVER_ID, SESSION_ID, REPLICA_LAG_IN_MILLISECONDS, CPU, LAST_UPDATE_TIMESTAMP
+		FROM information_schema.replica_host_status
+		WHERE SESSION_ID = '%s' OR TIME_TO_SEC(TIMEDIFF(NOW(), LAST_UPDATE_TIMESTAMP)) <= %d
+		ORDER BY SESSION_ID <> '%s', SERVER_ID`, auroraWriterSession, auroraStaleSeconds, auroraWriterSession))
+	if err != nil {
+		return nil, AuroraTopologyOutput{}, fmt.Errorf("failed to read replica_host_status: %w", err)
+	}
+	defer rows.Close()
+
+	domain, cluster := auroraEndpointParts(connectionHost(out.Connection))
+	for rows.Next() {
+		var inst AuroraInstance
+		var session, lastUpdate sql.NullString
+		var lag, cpu sql.NullFloat64
+		if err := rows.Scan(&inst.ServerID, &session, &lag, &cpu, &lastUpdate); err != nil {
+			return nil, AuroraTopologyOutput{}, fmt.Errorf("failed to read replica_host_status: %w", err)
+		}
+		inst.Role = auroraRoleReader
+		if session.String == auroraWriterSession {
+			inst.Role = auroraRoleWriter
+			out.Writer = inst.ServerID
+		} else if lag.Valid {
+			inst.ReplicaLagMs = &lag.Float64
+			out.MaxReplicaLagMs = max(out.MaxReplicaLagMs, lag.Float64)
+		}
+		if cpu.Valid {
+			inst.CPU = &cpu.Float64
+		}
+		if domain != "" {
+			inst.Endpoint = inst.ServerID + "." + domain
+		}
+		inst.Current = inst.ServerID == out.ServerID
+		inst.LastUpdate = lastUpdate.String
+		out.Instances = append(out.Instances, inst)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, AuroraTopologyOutput{}, fmt.Errorf("failed to read replica_host_status: %w", err)
+	}
+
+	switch {
+	case cluster != "":
+		out.ClusterEndpoint = cluster + ".cluster-" + domain
+		out.ReaderEndpoint = cluster + ".cluster-ro-" + domain
+	case domain != "":
+		out.Notes = append(out.Notes, "The connection uses an instance endpoint, so the cluster name and its writer and reader endpoints are not known; see the RDS console.")
+	default:
+		out.Notes = append(out.Notes, "The connection's host is not an RDS endpoint (a proxy, tunnel or custom DNS name), so instance endpoints are not shown.")
+	}
+	if out.Writer == "" {
+		out.Notes = append(out.Notes, "No writer reported in replica_host_status; the cluster may be failing over.")
+	}
+	return nil, out, nil
+}
+
+// auroraVersion returns @@aurora_version, or an error when db is not Aurora
+// MySQL.
+func auroraVersion(ctx context.Context, db *sql.DB) (string, error) {
+	if getServerType() == ServerTypeMariaDB {
+		return "", fmt.Errorf("aurora_topology needs Amazon Aurora MySQL; this connection is MariaDB")
+	}
+	var version string
+	err := db.QueryRowContext(ctx, "SELECT @@aurora_version").Scan(&version)
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == errUnknownSystemVar {
+		return "", fmt.Errorf("aurora_topology needs Amazon Aurora MySQL; this server has no @@aurora_version (see server_info)")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read @@aurora_version: %w", err)
+	}
+	return version, nil
+}
+
+// activeAuroraRole returns the role of the instance the active connection is
+// on, or "" when the connection is not known to be Aurora or the role cannot
+// be read.
+func activeAuroraRole(ctx context.Context) string {
+	if connManager == nil {
+		return ""
+	}
+	if caps, _ := connManager.ActiveCapabilities(); caps == nil || caps.Aurora == nil || !*caps.Aurora {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	var readOnly int
+	if err := getDB().QueryRowContext(ctx, "SELECT @@innodb_read_only").Scan(&readOnly); err != nil {
+		return ""
+	}
+	if readOnly != 0 {
+		return auroraRoleReader
+	}
+	return auroraRoleWriter
+}
+
+// connectionHost returns the host name in the DSN of connection name.
+func connectionHost(name string) string {
+	if connManager == nil {
+		return ""
+	}
+	c, ok := connManager.Config(name)
+	if !ok {
+		return ""
+	}
+	parsed, err := mysql.ParseDSN(c.DSN)
+	if err != nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(parsed.Addr)
+	if err != nil {
+		return parsed.Addr
+	}
+	return host
+}
+
+// auroraEndpointParts splits an Aurora endpoint such as
+// prod.cluster-ro-c1x2y3.eu-west-1.rds.amazonaws.com into the domain its
+// instance endpoints share (c1x2y3.eu-west-1.rds.amazonaws.com) and, for a
+// cluster endpoint, the cluster name (prod). Both are "" for other hosts.
+func auroraEndpointParts(host string) (domain, cluster string) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if !strings.Contains(host, auroraEndpointDomain) {
+		return "", ""
+	}
+	labels := strings.SplitN(host, ".", 3)
+	if len(labels) < 3 {
+		return "", ""
+	}
+	for _, prefix := range []string{"cluster-custom-", "cluster-ro-", "cluster-"} {
+		if id, ok := strings.CutPrefix(labels[1], prefix); ok {
+			if prefix == "cluster-custom-" {
+				// Custom endpoints are named by the user, not by cluster.
+				return id + "." + labels[2], ""
+			}
+			return id + "." + labels[2], labels[0]
+		}
+	}
+	return labels[1] + "." + labels[2], ""
+}
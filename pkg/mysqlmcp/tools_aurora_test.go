@@ -0,0 +1,83 @@
+// pkg/mysqlmcp/tools_aurora_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAuroraEndpointParts(t *testing.T) {
+	tests := []struct {
+		host, domain, cluster string
+	}{
+		{"prod.cluster-c1x2y3.eu-west-1.rds.amazonaws.com", "c1x2y3.eu-west-1.rds.amazonaws.com", "prod"},
+		{"Prod.cluster-ro-c1x2y3.eu-west-1.rds.amazonaws.com", "c1x2y3.eu-west-1.rds.amazonaws.com", "prod"},
+		{"reporting.cluster-custom-c1x2y3.eu-west-1.rds.amazonaws.com", "c1x2y3.eu-west-1.rds.amazonaws.com", ""},
+		{"prod-instance-1.c1x2y3.eu-west-1.rds.amazonaws.com", "c1x2y3.eu-west-1.rds.amazonaws.com", ""},
+		{"127.0.0.1", "", ""},
+		{"db.example.com", "", ""},
+	}
+	for _, tt := range tests {
+		domain, cluster := auroraEndpointParts(tt.host)
+		if domain != tt.domain || cluster != tt.cluster {
+			t.Errorf("auroraEndpointParts(%q) = %q, %q; want %q, %q", tt.host, domain, cluster, tt.domain, tt.cluster)
+		}
+	}
+}
+
+func TestToolAuroraTopology(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	connManager.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: "app@tcp(prod.cluster-ro-c1x2y3.eu-west-1.rds.amazonaws.com:3306)/shop"}
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT @@aurora_version").WillReturnRows(sqlmock.NewRows([]string{"v"}).AddRow("3.05.2"))
+	mock.ExpectQuery("SELECT @@aurora_server_id, @@innodb_read_only").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ro"}).AddRow("prod-instance-2", 1))
+	mock.ExpectQuery("FROM information_schema.replica_host_status").
+		WillReturnRows(sqlmock.NewRows([]string{"SERVER_ID", "SESSION_ID", "REPLICA_LAG_IN_MILLISECONDS", "CPU", "LAST_UPDATE_TIMESTAMP"}).
+			AddRow("prod-instance-1", "MASTER_SESSION_ID", 0.0, 12.5, "2026-10-15 09:00:00").
+			AddRow("prod-instance-2", "b1e9c0a2", 18.0, 4.0, "2026-10-15 09:00:00").
+			AddRow("prod-instance-3", "c7d2e4f1", 42.5, nil, "2026-10-15 09:00:00"))
+
+	_, out, err := toolAuroraTopology(ctx, &mcp.CallToolRequest{}, AuroraTopologyInput{})
+	if err != nil {
+		t.Fatalf("aurora_topology: %v", err)
+	}
+	if out.Role != auroraRoleReader || out.Writer != "prod-instance-1" || out.MaxReplicaLagMs != 42.5 || len(out.Instances) != 3 {
+		t.Fatalf("unexpected topology: %+v", out)
+	}
+	if out.ClusterEndpoint != "prod.cluster-c1x2y3.eu-west-1.rds.amazonaws.com" || out.ReaderEndpoint != "prod.cluster-ro-c1x2y3.eu-west-1.rds.amazonaws.com" {
+		t.Errorf("unexpected cluster endpoints %q %q", out.ClusterEndpoint, out.ReaderEndpoint)
+	}
+	writer, current := out.Instances[0], out.Instances[1]
+	if writer.Role != auroraRoleWriter || writer.ReplicaLagMs != nil || writer.Endpoint != "prod-instance-1.c1x2y3.eu-west-1.rds.amazonaws.com" {
+		t.Errorf("unexpected writer %+v", writer)
+	}
+	if !current.Current || current.ReplicaLagMs == nil || *current.ReplicaLagMs != 18 || out.Instances[2].CPU != nil {
+		t.Errorf("unexpected readers %+v", out.Instances[1:])
+	}
+
+	mock.ExpectQuery("SELECT @@aurora_version").
+		WillReturnError(&mysql.MySQLError{Number: errUnknownSystemVar, Message: "Unknown system variable 'aurora_version'"})
+	if _, _, err := toolAuroraTopology(ctx, &mcp.CallToolRequest{}, AuroraTopologyInput{}); err == nil || !strings.Contains(err.Error(), "needs Amazon Aurora") {
+		t.Errorf("expected a not-Aurora error, got %v", err)
+	}
+
+	// list_connections marks the role of the active connection on Aurora only.
+	connManager.capabilities["mock"] = &ConnectionCapabilities{Flavor: "mysql", Aurora: capability(true)}
+	mock.ExpectQuery("SELECT @@innodb_read_only").WillReturnRows(sqlmock.NewRows([]string{"ro"}).AddRow(0))
+	_, conns, err := toolListConnections(ctx, &mcp.CallToolRequest{}, ListConnectionsInput{})
+	if err != nil || len(conns.Connections) != 1 || conns.Connections[0].AuroraRole != auroraRoleWriter {
+		t.Errorf("expected the active connection marked writer, got %+v (%v)", conns.Connections, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
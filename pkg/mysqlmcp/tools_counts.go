@@ -0,0 +1,180 @@
+// pkg/mysqlmcp/tools_counts.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// table_counts answers "how many rows are in these tables?" in one call
+// instead of one count query per table. The estimate mode reads TABLE_ROWS
+// for every table with a single information_schema query; the exact mode runs
+// COUNT(*) for each table inside one read-only transaction opened WITH
+// CONSISTENT SNAPSHOT, so all counts describe the same moment even while
+// writes continue.
+const (
+	countModeEstimate = "estimate"
+	countModeExact    = "exact"
+
+	// tableCountsLimit caps the tables one call counts.
+	tableCountsLimit = 50
+)
+
+func toolTableCounts(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TableCountsInput,
+) (*mcp.CallToolResult, TableCountsOutput, error) {
+	if input.Database == "" {
+		return nil, TableCountsOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, TableCountsOutput{}, err
+	}
+	mode := strings.ToLower(strings.TrimSpace(input.Mode))
+	if mode == "" {
+		mode = countModeEstimate
+	}
+	if mode != countModeEstimate && mode != countModeExact {
+		return nil, TableCountsOutput{}, fmt.Errorf("mode must be estimate or exact")
+	}
+
+	var tables []string
+	seen := make(map[string]bool)
+	for _, t := range input.Tables {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[strings.ToLower(t)] {
+			continue
+		}
+		seen[strings.ToLower(t)] = true
+		tables = append(tables, t)
+	}
+	if len(tables) == 0 {
+		return nil, TableCountsOutput{}, fmt.Errorf("tables is required")
+	}
+	if len(tables) > tableCountsLimit {
+		return nil, TableCountsOutput{}, fmt.Errorf("at most %d tables can be counted per call, got %d", tableCountsLimit, len(tables))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	out := TableCountsOutput{Database: input.Database, Mode: mode}
+	var err error
+	if mode == countModeExact {
+		out.Tables, err = exactTableCounts(ctx, input.Database, tables)
+		out.Snapshot = true
+		out.Notes = append(out.Notes, "Counts were read in one consistent snapshot; tables of non-transactional engines such as MyISAM are not covered by it.")
+	} else {
+		out.Tables, err = estimateTableCounts(ctx, input.Database, tables)
+		out.Notes = append(out.Notes, "TABLE_ROWS is sampled by InnoDB and can be off by 40-50%; use mode=exact for exact counts.")
+	}
+	if err != nil {
+		return nil, TableCountsOutput{}, err
+	}
+	for _, t := range out.Tables {
+		if t.Rows != nil {
+			out.TotalRows += *t.Rows
+		}
+	}
+	return nil, out, nil
+}
+
+// estimateTableCounts reads TABLE_ROWS of tables with one query. Tables the
+// query does not return are reported as not found.
+func estimateTableCounts(ctx context.Context, database string, tables []string) ([]TableCount, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(tables)), ", ")
+	args := []interface{}{database}
+	for _, t := range tables {
+		args = append(args, t)
+	}
+	rows, err := getReadDB(ctx).QueryContext(ctx,
+		"SELECT TABLE_NAME, TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME IN ("+placeholders+")",
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TABLE_ROWS: %w", err)
+	}
+	defer rows.Close()
+	found := make(map[string]sql.NullInt64)
+	for rows.Next() {
+		var name string
+		var n sql.NullInt64
+		if err := rows.Scan(&name, &n); err != nil {
+			return nil, fmt.Errorf("failed to read TABLE_ROWS: %w", err)
+		}
+		found[strings.ToLower(name)] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read TABLE_ROWS: %w", err)
+	}
+
+	counts := make([]TableCount, 0, len(tables))
+	for _, t := range tables {
+		tc := TableCount{Table: t}
+		n, ok := found[strings.ToLower(t)]
+		switch {
+		case !ok:
+			tc.Error = fmt.Sprintf("table not found: %s.%s", database, t)
+		case n.Valid:
+			tc.Rows = &n.Int64
+		default:
+			tc.Error = "TABLE_ROWS is NULL (view or storage engine without statistics); use mode=exact"
+		}
+		counts = append(counts, tc)
+	}
+	return counts, nil
+}
+
+// exactTableCounts runs COUNT(*) for each table inside one consistent
+// snapshot. A table that cannot be counted reports its error; a failure to
+// open the snapshot fails the call.
+func exactTableCounts(ctx context.Context, database string, tables []string) ([]TableCount, error) {
+	qdb, err := util.QuoteIdent(database)
+	if err != nil {
+		return nil, err
+	}
+	db := getReadDB(ctx)
+	session, err := OpenSession(ctx, db, true)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+	ctx = withSession(ctx, session)
+	conn, release, err := acquireConn(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	counts := make([]TableCount, 0, len(tables))
+	for _, t := range tables {
+		tc := TableCount{Table: t}
+		if err := requireNoRowPolicy(database, t); err != nil {
+			tc.Error = err.Error()
+			counts = append(counts, tc)
+			continue
+		}
+		qtable, err := util.QuoteIdent(t)
+		if err != nil {
+			tc.Error = err.Error()
+			counts = append(counts, tc)
+			continue
+		}
+		var n int64
+		if err := conn.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", qdb, qtable)).Scan(&n); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			tc.Error = fmt.Sprintf("COUNT(*) failed: %v", err)
+		} else {
+			tc.Rows = &n
+		}
+		counts = append(counts, tc)
+	}
+	return counts, nil
+}
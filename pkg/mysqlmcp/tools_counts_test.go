@@ -0,0 +1,88 @@
+// pkg/mysqlmcp/tools_counts_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolTableCountsEstimate(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT TABLE_NAME, TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = \\? AND TABLE_NAME IN \\(\\?, \\?, \\?\\)").
+		WithArgs("shop", "orders", "Refunds", "missing").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "TABLE_ROWS"}).
+			AddRow("orders", 120).
+			AddRow("refunds", 8))
+
+	_, out, err := toolTableCounts(context.Background(), &mcp.CallToolRequest{}, TableCountsInput{
+		Database: "shop",
+		Tables:   []string{"orders", "Refunds", "missing", " orders "},
+	})
+	if err != nil {
+		t.Fatalf("table_counts: %v", err)
+	}
+	if out.Mode != countModeEstimate || out.Snapshot || len(out.Tables) != 3 || out.TotalRows != 128 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if tc := out.Tables[1]; tc.Table != "Refunds" || tc.Rows == nil || *tc.Rows != 8 {
+		t.Errorf("unexpected Refunds: %+v", tc)
+	}
+	if tc := out.Tables[2]; tc.Rows != nil || !strings.Contains(tc.Error, "not found") {
+		t.Errorf("unexpected missing: %+v", tc)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolTableCountsExact(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectExec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `shop`.`orders`").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(4))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `shop`.`gone`").WillReturnError(errors.New("Table 'shop.gone' doesn't exist"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM `shop`.`order_items`").WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(9))
+	mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, out, err := toolTableCounts(context.Background(), &mcp.CallToolRequest{}, TableCountsInput{
+		Database: "shop",
+		Tables:   []string{"orders", "gone", "order_items"},
+		Mode:     "EXACT",
+	})
+	if err != nil {
+		t.Fatalf("table_counts: %v", err)
+	}
+	if out.Mode != countModeExact || !out.Snapshot || len(out.Tables) != 3 || out.TotalRows != 13 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if tc := out.Tables[1]; tc.Rows != nil || !strings.Contains(tc.Error, "doesn't exist") {
+		t.Errorf("unexpected gone: %+v", tc)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+
+	many := make([]string, tableCountsLimit+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("t%d", i)
+	}
+	for _, input := range []TableCountsInput{
+		{Database: "shop"},
+		{Database: "shop", Tables: []string{"orders"}, Mode: "fast"},
+		{Database: "shop", Tables: many},
+	} {
+		if _, _, err := toolTableCounts(context.Background(), &mcp.CallToolRequest{}, input); err == nil {
+			t.Errorf("expected an error for %+v", input)
+		}
+	}
+}
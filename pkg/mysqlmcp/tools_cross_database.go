@@ -0,0 +1,98 @@
+// pkg/mysqlmcp/tools_cross_database.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// run_cross_database_query joins tables of different databases on one
+// connection. Agents often write such joins with bare table names, which fail
+// with "No database selected" or hit the wrong schema; here the caller lists
+// every table as database.table, each one is checked for access and
+// existence, and the bare names in the SQL are qualified before the query
+// goes through run_query.
+
+func toolRunCrossDatabaseQuery(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input RunCrossDatabaseQueryInput,
+) (*mcp.CallToolResult, RunCrossDatabaseQueryOutput, error) {
+	refs := make([]util.TableRef, 0, len(input.Tables))
+	databases := map[string]bool{}
+	for _, t := range input.Tables {
+		ref, err := util.ParseTableRef(t)
+		if err != nil {
+			return nil, RunCrossDatabaseQueryOutput{}, err
+		}
+		if err := requireAllowedDatabase(ref.Database); err != nil {
+			return nil, RunCrossDatabaseQueryOutput{}, err
+		}
+		refs = append(refs, ref)
+		databases[strings.ToLower(ref.Database)] = true
+	}
+	if len(databases) < 2 {
+		return nil, RunCrossDatabaseQueryOutput{}, fmt.Errorf("tables must come from at least two databases; use run_query with database for a single one")
+	}
+
+	qctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	if err := requireTablesExist(qctx, refs); err != nil {
+		return nil, RunCrossDatabaseQueryOutput{}, err
+	}
+
+	sqlText, qualified, err := util.QualifyTables(input.SQL, refs)
+	if err != nil {
+		return nil, RunCrossDatabaseQueryOutput{}, err
+	}
+	_, result, err := toolRunQuery(ctx, req, RunQueryInput{
+		SQL:      sqlText,
+		MaxRows:  input.MaxRows,
+		Database: refs[0].Database,
+		Confirm:  input.Confirm,
+	})
+	if err != nil {
+		return nil, RunCrossDatabaseQueryOutput{}, err
+	}
+	return nil, RunCrossDatabaseQueryOutput{SQL: sqlText, Qualified: qualified, Result: result}, nil
+}
+
+// requireTablesExist returns an error naming the first of refs that is
+// neither a table nor a view.
+func requireTablesExist(ctx context.Context, refs []util.TableRef) error {
+	conds := make([]string, 0, len(refs))
+	args := make([]interface{}, 0, 2*len(refs))
+	for _, r := range refs {
+		conds = append(conds, "(TABLE_SCHEMA = ? AND TABLE_NAME = ?)")
+		args = append(args, r.Database, r.Table)
+	}
+	rows, err := getDB().QueryContext(ctx, `
+		SELECT TABLE_SCHEMA, TABLE_NAME
+		FROM information_schema.TABLES
+		WHERE `+strings.Join(conds, " OR "), args...)
+	if err != nil {
+		return fmt.Errorf("failed to look up tables: %w", err)
+	}
+	defer rows.Close()
+	found := map[string]bool{}
+	for rows.Next() {
+		var db, table string
+		if err := rows.Scan(&db, &table); err != nil {
+			return fmt.Errorf("failed to look up tables: %w", err)
+		}
+		found[strings.ToLower(db+"."+table)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to look up tables: %w", err)
+	}
+	for _, r := range refs {
+		if !found[strings.ToLower(r.String())] {
+			return fmt.Errorf("table %s not found", r)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// pkg/mysqlmcp/tools_cross_database_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolRunCrossDatabaseQuery(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	mock.ExpectQuery("FROM information_schema.TABLES").
+		WithArgs("shop", "orders", "crm", "customers").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME"}).
+			AddRow("shop", "orders").AddRow("crm", "customers"))
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("select o.id, c.name from shop.orders as o join crm.customers as c on c.id = o.customer_id LIMIT 1000").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Ada"))
+	expectRestoreDatabase(mock)
+
+	_, out, err := toolRunCrossDatabaseQuery(ctx, &mcp.CallToolRequest{}, RunCrossDatabaseQueryInput{
+		Tables: []string{"shop.orders", "crm.customers"},
+		SQL:    "SELECT o.id, c.name FROM orders o JOIN customers c ON c.id = o.customer_id",
+	})
+	if err != nil {
+		t.Fatalf("run_cross_database_query: %v", err)
+	}
+	if len(out.Qualified) != 2 || !strings.Contains(out.SQL, "crm.customers") || len(out.Result.Rows) != 1 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolRunCrossDatabaseQueryRefuses(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, in := range []RunCrossDatabaseQueryInput{
+		{Tables: []string{"orders", "crm.customers"}, SQL: "SELECT 1"},
+		{Tables: []string{"shop.orders", "shop.refunds"}, SQL: "SELECT 1"},
+	} {
+		if _, _, err := toolRunCrossDatabaseQuery(ctx, &mcp.CallToolRequest{}, in); err == nil {
+			t.Errorf("expected %v to be refused", in.Tables)
+		}
+	}
+
+	mock.ExpectQuery("FROM information_schema.TABLES").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME"}).AddRow("shop", "orders"))
+	_, _, err := toolRunCrossDatabaseQuery(ctx, &mcp.CallToolRequest{}, RunCrossDatabaseQueryInput{
+		Tables: []string{"shop.orders", "crm.customer"},
+		SQL:    "SELECT * FROM orders JOIN customer ON customer.id = orders.customer_id",
+	})
+	if err == nil || !strings.Contains(err.Error(), "crm.customer not found") {
+		t.Errorf("expected a missing table error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
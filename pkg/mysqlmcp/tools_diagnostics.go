@@ -1,9 +1,10 @@
-package main
+package mysqlmcp
 
 import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -13,6 +14,20 @@ import (
 
 const maxProcessList = 200
 const maxInfoRunes = 4000
+const maxSessionInfoRunes = 1000
+
+// sessionCredentialPattern matches quoted secrets in statements that may carry
+// credentials (CREATE/ALTER USER, SET PASSWORD, CHANGE REPLICATION SOURCE, ...).
+var sessionCredentialPattern = regexp.MustCompile(
+	`(?i)((?:IDENTIFIED\s+(?:WITH\s+\S+\s+)?(?:BY|AS)|PASSWORD\s*(?:=|\(|FOR\s+\S+\s*=)|(?:MASTER|SOURCE)_PASSWORD\s*=)\s*)('(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*")`,
+)
+
+const sessionsFromPerformanceSchema = `SELECT PROCESSLIST_ID AS id, PROCESSLIST_USER AS user, PROCESSLIST_HOST AS host,
+	PROCESSLIST_DB AS db, PROCESSLIST_COMMAND AS command, PROCESSLIST_TIME AS time,
+	PROCESSLIST_STATE AS state, PROCESSLIST_INFO AS info
+	FROM performance_schema.threads
+	WHERE TYPE = 'FOREGROUND' AND PROCESSLIST_ID IS NOT NULL
+	ORDER BY PROCESSLIST_ID`
 
 // truncateRunes shortens s to at most maxRunes Unicode code points and appends an ellipsis when truncated.
 func truncateRunes(s string, maxRunes int) string {
@@ -94,6 +109,129 @@ func toolProcessList(
 	return nil, out, nil
 }
 
+// redactSessionInfo removes quoted credentials from a processlist statement and truncates it.
+func redactSessionInfo(info string) string {
+	if info == "" {
+		return ""
+	}
+	info = sessionCredentialPattern.ReplaceAllString(info, "${1}'***'")
+	return truncateRunes(info, maxSessionInfoRunes)
+}
+
+// sessionHostWithoutPort strips the client port from a processlist host ("10.0.0.5:51234" -> "10.0.0.5").
+func sessionHostWithoutPort(host string) string {
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i > 0 {
+			return host[1:i]
+		}
+	}
+	if strings.Count(host, ":") == 1 {
+		return host[:strings.Index(host, ":")]
+	}
+	return host
+}
+
+// toolListSessions is a read-only, sanitized view of client sessions. It prefers
+// performance_schema.threads and falls back to SHOW FULL PROCESSLIST. Unlike
+// process_list it never exposes kill support, hides other users by default,
+// strips client ports and redacts credentials from statement text.
+func toolListSessions(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListSessionsInput,
+) (*mcp.CallToolResult, ListSessionsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	out := ListSessionsOutput{Sessions: []SessionRow{}}
+
+	var current string
+	if err := getDB().QueryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&current); err != nil {
+		return nil, ListSessionsOutput{}, fmt.Errorf("could not determine current user: %w", err)
+	}
+	if i := strings.LastIndex(current, "@"); i >= 0 {
+		current = current[:i]
+	}
+	out.CurrentUser = current
+
+	out.Source = "performance_schema"
+	rows, err := getDB().QueryContext(ctx, sessionsFromPerformanceSchema)
+	if err != nil {
+		out.Source = "processlist"
+		out.Note = fmt.Sprintf("performance_schema.threads unavailable (%v); using SHOW FULL PROCESSLIST", err)
+		rows, err = getDB().QueryContext(ctx, "SHOW FULL PROCESSLIST")
+		if err != nil {
+			return nil, ListSessionsOutput{}, fmt.Errorf("list sessions: %w", err)
+		}
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, ListSessionsOutput{}, fmt.Errorf("list sessions columns: %w", err)
+	}
+	idx := map[string]int{}
+	for i, c := range cols {
+		idx[strings.ToLower(c)] = i
+	}
+
+	raw := make([]sql.NullString, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range raw {
+		ptrs[i] = &raw[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			continue
+		}
+		get := func(name string) string {
+			if j, ok := idx[name]; ok && j < len(raw) && raw[j].Valid {
+				return raw[j].String
+			}
+			return ""
+		}
+		user := get("user")
+		command := get("command")
+		db := get("db")
+		if !input.IncludeOtherUsers && user != current {
+			continue
+		}
+		if input.User != "" && user != input.User {
+			continue
+		}
+		if input.Command != "" && !strings.EqualFold(command, input.Command) {
+			continue
+		}
+		if input.ExcludeSleeping && strings.EqualFold(command, "Sleep") {
+			continue
+		}
+		if accessControlEnabled() && !databaseAllowed(db) {
+			continue
+		}
+		if len(out.Sessions) >= maxProcessList {
+			out.Truncated = true
+			break
+		}
+		id, _ := strconv.ParseInt(get("id"), 10, 64)
+		t, _ := strconv.ParseInt(get("time"), 10, 64)
+		out.Sessions = append(out.Sessions, SessionRow{
+			ID:      id,
+			User:    user,
+			Host:    sessionHostWithoutPort(get("host")),
+			DB:      db,
+			Command: command,
+			Time:    t,
+			State:   get("state"),
+			Info:    redactSessionInfo(get("info")),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ListSessionsOutput{}, err
+	}
+	return nil, out, nil
+}
+
 func toolKillQuery(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
@@ -185,24 +323,8 @@ func toolSlowQueryLog(
 	var rows *sql.Rows
 	var err error
 	if accessControlEnabled() {
-		allowed := allowedDatabasesLower()
-		if len(allowed) == 0 {
-			out.Mode = "error"
-			out.Message = "database allowlist is empty; cannot query mysql.slow_log"
-			return nil, out, nil
-		}
-		ph := strings.Repeat("?,", len(allowed))
-		if len(ph) > 0 {
-			ph = ph[:len(ph)-1]
-		}
-		q := fmt.Sprintf(
-			`SELECT * FROM mysql.slow_log WHERE LOWER(IFNULL(db, '')) IN (%s) ORDER BY start_time DESC LIMIT ?`,
-			ph,
-		)
-		args := make([]interface{}, 0, len(allowed)+1)
-		for _, db := range allowed {
-			args = append(args, db)
-		}
+		filter, args := allowedSchemaFilter("IFNULL(db, '')")
+		q := `SELECT * FROM mysql.slow_log WHERE ` + filter + ` ORDER BY start_time DESC LIMIT ?`
 		args = append(args, limit)
 		rows, err = getDB().QueryContext(ctx, q, args...)
 	} else {
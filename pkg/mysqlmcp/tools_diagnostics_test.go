@@ -0,0 +1,132 @@
+// pkg/mysqlmcp/tools_diagnostics_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ===== toolListSessions Tests =====
+
+var sessionColumns = []string{"id", "user", "host", "db", "command", "time", "state", "info"}
+
+func TestToolListSessionsOwnUserOnly(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT CURRENT_USER\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"CURRENT_USER()"}).AddRow("app@%"))
+	mock.ExpectQuery("FROM performance_schema.threads").
+		WillReturnRows(sqlmock.NewRows(sessionColumns).
+			AddRow(10, "app", "10.0.0.5:51234", "shop", "Query", 3, "executing", "SELECT * FROM orders").
+			AddRow(11, "root", "localhost", nil, "Sleep", 100, nil, nil).
+			AddRow(12, "app", "10.0.0.6:40000", "shop", "Sleep", 7, nil, nil))
+
+	_, out, err := toolListSessions(context.Background(), &mcp.CallToolRequest{}, ListSessionsInput{})
+	if err != nil {
+		t.Fatalf("toolListSessions failed: %v", err)
+	}
+	if out.Source != "performance_schema" {
+		t.Errorf("expected performance_schema source, got %q", out.Source)
+	}
+	if out.CurrentUser != "app" {
+		t.Errorf("expected current user app, got %q", out.CurrentUser)
+	}
+	if len(out.Sessions) != 2 {
+		t.Fatalf("expected 2 sessions for current user, got %d: %+v", len(out.Sessions), out.Sessions)
+	}
+	if out.Sessions[0].Host != "10.0.0.5" {
+		t.Errorf("expected port stripped from host, got %q", out.Sessions[0].Host)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolListSessionsFiltersAndFallback(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT CURRENT_USER\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"CURRENT_USER()"}).AddRow("app@localhost"))
+	mock.ExpectQuery("FROM performance_schema.threads").
+		WillReturnError(fmt.Errorf("SELECT command denied"))
+	mock.ExpectQuery("SHOW FULL PROCESSLIST").
+		WillReturnRows(sqlmock.NewRows([]string{"Id", "User", "Host", "db", "Command", "Time", "State", "Info"}).
+			AddRow(10, "app", "10.0.0.5:51234", "shop", "Query", 3, "executing", "SELECT 1").
+			AddRow(11, "root", "localhost", nil, "Sleep", 100, nil, nil).
+			AddRow(12, "root", "[::1]:40000", nil, "Query", 1, "starting", "ALTER USER 'x'@'%' IDENTIFIED BY 's3cret'"))
+
+	_, out, err := toolListSessions(context.Background(), &mcp.CallToolRequest{}, ListSessionsInput{
+		IncludeOtherUsers: true,
+		ExcludeSleeping:   true,
+		User:              "root",
+	})
+	if err != nil {
+		t.Fatalf("toolListSessions failed: %v", err)
+	}
+	if out.Source != "processlist" || out.Note == "" {
+		t.Errorf("expected processlist fallback with note, got source=%q note=%q", out.Source, out.Note)
+	}
+	if len(out.Sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d: %+v", len(out.Sessions), out.Sessions)
+	}
+	s := out.Sessions[0]
+	if s.ID != 12 || s.Host != "::1" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+	if strings.Contains(s.Info, "s3cret") {
+		t.Errorf("expected credential redacted, got %q", s.Info)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRedactSessionInfo(t *testing.T) {
+	tests := []struct {
+		in     string
+		secret string
+	}{
+		{"CREATE USER 'u'@'%' IDENTIFIED BY 'hunter2'", "hunter2"},
+		{"ALTER USER u IDENTIFIED WITH caching_sha2_password BY \"pw\"", "pw\""},
+		{"SET PASSWORD FOR 'u'@'%' = 'abc123'", "abc123"},
+		{"SET PASSWORD = 'abc123'", "abc123"},
+		{"CHANGE REPLICATION SOURCE TO SOURCE_USER='r', SOURCE_PASSWORD='topsecret'", "topsecret"},
+		{"CHANGE MASTER TO MASTER_PASSWORD = 'it''s'", "it''s"},
+	}
+	for _, tt := range tests {
+		got := redactSessionInfo(tt.in)
+		if strings.Contains(got, tt.secret) {
+			t.Errorf("redactSessionInfo(%q) = %q, still contains secret", tt.in, got)
+		}
+		if !strings.Contains(got, "'***'") {
+			t.Errorf("redactSessionInfo(%q) = %q, expected redaction marker", tt.in, got)
+		}
+	}
+	if got := redactSessionInfo("SELECT 'plain'"); got != "SELECT 'plain'" {
+		t.Errorf("expected non-credential statement unchanged, got %q", got)
+	}
+}
+
+func TestSessionHostWithoutPort(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.5:51234": "10.0.0.5",
+		"localhost":      "localhost",
+		"[::1]:3306":     "::1",
+		"fe80::1":        "fe80::1",
+		"":               "",
+	}
+	for in, want := range tests {
+		if got := sessionHostWithoutPort(in); got != want {
+			t.Errorf("sessionHostWithoutPort(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// pkg/mysqlmcp/tools_estimate.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// paginateFactor is how many row caps an estimate may span before
+// estimate_rows recommends refining the query instead of paging through it.
+const paginateFactor = 100
+
+func toolEstimateRows(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input EstimateRowsInput,
+) (*mcp.CallToolResult, EstimateRowsOutput, error) {
+	sqlText := strings.TrimSpace(input.SQL)
+	database := strings.TrimSpace(input.Database)
+	table := strings.TrimSpace(input.Table)
+	if sqlText == "" && table == "" {
+		return nil, EstimateRowsOutput{}, fmt.Errorf("sql or table is required")
+	}
+	if table != "" && database == "" {
+		return nil, EstimateRowsOutput{}, fmt.Errorf("database is required with table")
+	}
+	if sqlText != "" && !strings.HasPrefix(strings.ToUpper(sqlText), "SELECT") {
+		return nil, EstimateRowsOutput{}, fmt.Errorf("only SELECT statements can be estimated")
+	}
+	if accessControlEnabled() && database == "" {
+		return nil, EstimateRowsOutput{}, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+	}
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, EstimateRowsOutput{}, err
+		}
+	}
+	if sqlText != "" {
+		if err := requireReferencedSchemasInQuery(sqlText); err != nil {
+			return nil, EstimateRowsOutput{}, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	out := EstimateRowsOutput{RowCap: defaultRowLimit(database)}
+	var estimate int64 = -1
+
+	if table != "" {
+		var rows sql.NullInt64
+		err := getDB().QueryRowContext(ctx,
+			"SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?",
+			database, table).Scan(&rows)
+		if err == sql.ErrNoRows {
+			return nil, EstimateRowsOutput{}, fmt.Errorf("table not found: %s.%s", database, table)
+		}
+		if err != nil {
+			return nil, EstimateRowsOutput{}, fmt.Errorf("failed to read TABLE_ROWS: %w", err)
+		}
+		if rows.Valid {
+			out.TableRows = &rows.Int64
+			estimate = rows.Int64
+			out.Notes = append(out.Notes, "TABLE_ROWS is sampled by InnoDB and can be off by 40-50%; run ANALYZE TABLE to refresh it.")
+		} else {
+			out.Notes = append(out.Notes, "TABLE_ROWS is NULL (view or storage engine without statistics).")
+		}
+	}
+
+	if sqlText != "" {
+		plan, err := runExplain(ctx, database, sqlText)
+		if err != nil {
+			return nil, EstimateRowsOutput{}, err
+		}
+		out.Plan = plan
+		estimated, examined, planRows := planRowEstimates(plan)
+		out.PlanRows = planRows
+		out.EstimatedRows = &estimated
+		out.RowsExamined = &examined
+		estimate = estimated
+		out.Notes = append(out.Notes, "EXPLAIN estimates come from index statistics; LIMIT, GROUP BY and subqueries are not reflected in estimated_rows.")
+	}
+
+	out.Recommendation = rowRecommendation(estimate, out.RowCap)
+	return nil, out, nil
+}
+
+// planRowEstimates derives the result estimate from a traditional EXPLAIN plan:
+// the product of rows x filtered% over the tables of the top-level join
+// (select id 1), plus the total rows examined by every plan row.
+func planRowEstimates(plan []map[string]interface{}) (estimated, examined int64, rows []PlanRowEstimate) {
+	product := 1.0
+	joined := false
+	for _, row := range plan {
+		r := PlanRowEstimate{
+			Table:    fmt.Sprintf("%v", row["table"]),
+			Rows:     int64(numericValue(row["rows"])),
+			Filtered: 100,
+		}
+		if row["type"] != nil {
+			r.AccessType = fmt.Sprintf("%v", row["type"])
+		}
+		if row["filtered"] != nil {
+			r.Filtered = numericValue(row["filtered"])
+		}
+		rows = append(rows, r)
+		examined += r.Rows
+
+		if id := numericValue(row["id"]); id == 1 && row["rows"] != nil {
+			product *= float64(r.Rows) * r.Filtered / 100
+			joined = true
+		}
+	}
+	if joined {
+		estimated = int64(math.Round(product))
+	}
+	return estimated, examined, rows
+}
+
+// rowRecommendation compares an estimate with the run_query row cap.
+func rowRecommendation(estimate int64, rowCap int) string {
+	switch {
+	case estimate < 0:
+		return "unknown"
+	case rowCap <= 0 || estimate <= int64(rowCap):
+		return "run"
+	case estimate <= int64(rowCap)*paginateFactor:
+		return "paginate"
+	default:
+		return "refine"
+	}
+}
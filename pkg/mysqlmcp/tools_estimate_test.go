@@ -0,0 +1,88 @@
+// pkg/mysqlmcp/tools_estimate_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolEstimateRows(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT TABLE_ROWS FROM information_schema.TABLES").
+		WithArgs("app", "orders").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_ROWS"}).AddRow(2000000))
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("EXPLAIN SELECT").
+		WillReturnRows(sqlmock.NewRows(explainColumns()).
+			AddRow(1, "SIMPLE", "o", nil, "ALL", nil, nil, nil, nil, 2000000, 10.0, "Using where").
+			AddRow(1, "SIMPLE", "c", nil, "eq_ref", "PRIMARY", "PRIMARY", 4, "app.o.customer_id", 1, 100.0, "").
+			AddRow(2, "SUBQUERY", "r", nil, "ALL", nil, nil, nil, nil, 50, 100.0, ""))
+	expectRestoreDatabase(mock)
+
+	_, out, err := toolEstimateRows(context.Background(), &mcp.CallToolRequest{}, EstimateRowsInput{
+		SQL:      "SELECT o.id FROM orders o JOIN customers c ON c.id = o.customer_id WHERE o.total > 100",
+		Database: "app",
+		Table:    "orders",
+	})
+	if err != nil {
+		t.Fatalf("toolEstimateRows failed: %v", err)
+	}
+	if out.TableRows == nil || *out.TableRows != 2000000 {
+		t.Errorf("unexpected table_rows: %v", out.TableRows)
+	}
+	if out.EstimatedRows == nil || *out.EstimatedRows != 200000 {
+		t.Errorf("unexpected estimated_rows: %v", out.EstimatedRows)
+	}
+	if out.RowsExamined == nil || *out.RowsExamined != 2000051 {
+		t.Errorf("unexpected rows_examined: %v", out.RowsExamined)
+	}
+	if len(out.PlanRows) != 3 || out.PlanRows[0].AccessType != "ALL" || out.PlanRows[0].Filtered != 10 {
+		t.Errorf("unexpected plan rows: %+v", out.PlanRows)
+	}
+	if out.RowCap != 1000 || out.Recommendation != "refine" {
+		t.Errorf("unexpected recommendation %q (cap %d)", out.Recommendation, out.RowCap)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolEstimateRowsValidation(t *testing.T) {
+	_, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	for _, input := range []EstimateRowsInput{
+		{},
+		{Table: "orders"},
+		{SQL: "DELETE FROM orders"},
+	} {
+		if _, _, err := toolEstimateRows(context.Background(), &mcp.CallToolRequest{}, input); err == nil {
+			t.Errorf("expected error for %+v", input)
+		}
+	}
+}
+
+func TestRowRecommendation(t *testing.T) {
+	tests := []struct {
+		estimate int64
+		want     string
+	}{
+		{-1, "unknown"},
+		{0, "run"},
+		{1000, "run"},
+		{1001, "paginate"},
+		{100000, "paginate"},
+		{100001, "refine"},
+	}
+	for _, tt := range tests {
+		if got := rowRecommendation(tt.estimate, 1000); got != tt.want {
+			t.Errorf("rowRecommendation(%d) = %q, want %q", tt.estimate, got, tt.want)
+		}
+	}
+}
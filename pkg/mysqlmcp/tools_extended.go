@@ -1,10 +1,11 @@
-// cmd/mysql-mcp-server/tools_extended.go
-package main
+// pkg/mysqlmcp/tools_extended.go
+package mysqlmcp
 
 import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -127,6 +128,25 @@ func toolShowCreateTable(
 	return nil, ShowCreateTableOutput{CreateStatement: createStmt}, nil
 }
 
+func toolNormalizeQuery(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input NormalizeQueryInput,
+) (*mcp.CallToolResult, NormalizeQueryOutput, error) {
+	if strings.TrimSpace(input.SQL) == "" {
+		return nil, NormalizeQueryOutput{}, fmt.Errorf("sql is required")
+	}
+	n := util.NormalizeQuery(input.SQL)
+	return nil, NormalizeQueryOutput{
+		Fingerprint:   n.Fingerprint,
+		Digest:        n.Digest,
+		StatementType: n.StatementType,
+		Tables:        n.Tables,
+		Columns:       n.Columns,
+		Parsed:        n.Parsed,
+	}, nil
+}
+
 func toolExplainQuery(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
@@ -159,39 +179,77 @@ func toolExplainQuery(
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	explainSQL := "EXPLAIN " + sqlText
+	plan, err := runExplain(ctx, database, sqlText)
+	if err != nil {
+		return nil, ExplainQueryOutput{}, err
+	}
+	out := ExplainQueryOutput{Plan: plan}
+	out.Warnings = analyzeExplainPlan(out.Plan)
+
+	return nil, out, nil
+}
+
+// runExplain returns the traditional EXPLAIN plan for sqlText, optionally in
+// the context of database, binding args to its ? placeholders. Every row
+// carries a "partitions" key (nil for non-partitioned tables); MariaDB only
+// reports it with EXPLAIN PARTITIONS.
+func runExplain(ctx context.Context, database, sqlText string, args ...interface{}) ([]map[string]interface{}, error) {
+	explain := "EXPLAIN"
+	if getServerType() == ServerTypeMariaDB {
+		explain = "EXPLAIN PARTITIONS"
+	}
+	return runExplainStatement(ctx, database, explain, sqlText, args...)
+}
+
+// runExplainStatement runs explain, an EXPLAIN variant, for sqlText in
+// database (if set), with the session settings of set_session_setting, and
+// returns the result rows as column -> value maps.
+func runExplainStatement(ctx context.Context, database, explain, sqlText string, args ...interface{}) ([]map[string]interface{}, error) {
+	sqlText, database, err := scopeStatement(getDB(), sqlText, database)
+	if err != nil {
+		return nil, err
+	}
+	explainSQL := explain + " " + sqlText
 	var rows *sql.Rows
-	var err error
 
-	if database != "" {
-		var dbName string
-		dbName, err = util.QuoteIdent(database)
-		if err != nil {
-			return nil, ExplainQueryOutput{}, fmt.Errorf("invalid database name: %w", err)
-		}
+	if database != "" || !activeSessionOverrides().empty() {
+		db := getDB()
 		var conn *sql.Conn
-		conn, err = getDB().Conn(ctx)
+		conn, err = db.Conn(ctx)
 		if err != nil {
-			return nil, ExplainQueryOutput{}, fmt.Errorf("failed to get connection: %w", err)
+			return nil, fmt.Errorf("failed to get connection: %w", err)
 		}
 		defer conn.Close()
 
-		_, err = conn.ExecContext(ctx, "USE "+dbName)
+		var restoreDatabase func()
+		restoreDatabase, err = useDatabase(ctx, db, conn, database)
+		if err != nil {
+			return nil, err
+		}
+		defer restoreDatabase()
+
+		var restoreSettings func()
+		restoreSettings, err = useSessionSettings(ctx, conn)
 		if err != nil {
-			return nil, ExplainQueryOutput{}, fmt.Errorf("failed to switch database: %w", err)
+			return nil, err
 		}
-		rows, err = conn.QueryContext(ctx, explainSQL)
+		defer restoreSettings()
+		rows, err = conn.QueryContext(ctx, explainSQL, args...)
 	} else {
-		rows, err = getDB().QueryContext(ctx, explainSQL)
+		rows, err = getDB().QueryContext(ctx, explainSQL, args...)
 	}
 
 	if err != nil {
-		return nil, ExplainQueryOutput{}, fmt.Errorf("EXPLAIN failed: %w", err)
+		return nil, fmt.Errorf("EXPLAIN failed: %w", err)
 	}
 	defer rows.Close()
+	return readPlan(rows), nil
+}
 
+// readPlan converts EXPLAIN result rows to column -> value maps.
+func readPlan(rows *sql.Rows) []map[string]interface{} {
 	cols, _ := rows.Columns()
-	out := ExplainQueryOutput{Plan: []map[string]interface{}{}}
+	plan := []map[string]interface{}{}
 
 	for rows.Next() {
 		values := make([]interface{}, len(cols))
@@ -202,16 +260,13 @@ func toolExplainQuery(
 		if err := rows.Scan(ptrs...); err != nil {
 			continue
 		}
-		row := make(map[string]interface{})
+		row := map[string]interface{}{"partitions": nil}
 		for i, col := range cols {
 			row[col] = util.NormalizeValue(values[i])
 		}
-		out.Plan = append(out.Plan, row)
+		plan = append(plan, row)
 	}
-
-	out.Warnings = analyzeExplainPlan(out.Plan)
-
-	return nil, out, nil
+	return plan
 }
 
 // analyzeExplainPlan inspects a traditional EXPLAIN plan and returns actionable
@@ -291,9 +346,7 @@ func toolListViews(
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	query := `SELECT TABLE_NAME, DEFINER, SECURITY_TYPE, IS_UPDATABLE 
-		FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ?`
-	rows, err := getDB().QueryContext(ctx, query, input.Database)
+	rows, err := queryPrepared(ctx, queryListViews, input.Database)
 	if err != nil {
 		return nil, ListViewsOutput{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -332,9 +385,7 @@ func toolListTriggers(
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	query := `SELECT TRIGGER_NAME, EVENT_MANIPULATION, EVENT_OBJECT_TABLE, ACTION_TIMING, 
-		LEFT(ACTION_STATEMENT, 200) FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ?`
-	rows, err := getDB().QueryContext(ctx, query, input.Database)
+	rows, err := queryPrepared(ctx, queryListTriggers, input.Database)
 	if err != nil {
 		return nil, ListTriggersOutput{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -373,10 +424,7 @@ func toolListProcedures(
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	query := `SELECT ROUTINE_NAME, DEFINER, CREATED, LAST_ALTERED, 
-		IFNULL(PARAMETER_STYLE, '') FROM information_schema.ROUTINES 
-		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'PROCEDURE'`
-	rows, err := getDB().QueryContext(ctx, query, input.Database)
+	rows, err := queryPrepared(ctx, queryListProcedures, input.Database)
 	if err != nil {
 		return nil, ListProceduresOutput{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -415,10 +463,7 @@ func toolListFunctions(
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	query := `SELECT ROUTINE_NAME, DEFINER, DTD_IDENTIFIER, CREATED 
-		FROM information_schema.ROUTINES 
-		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE = 'FUNCTION'`
-	rows, err := getDB().QueryContext(ctx, query, input.Database)
+	rows, err := queryPrepared(ctx, queryListFunctions, input.Database)
 	if err != nil {
 		return nil, ListFunctionsOutput{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -457,11 +502,7 @@ func toolListPartitions(
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
-	query := `SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION, 
-		PARTITION_DESCRIPTION, TABLE_ROWS, DATA_LENGTH 
-		FROM information_schema.PARTITIONS 
-		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL`
-	rows, err := getDB().QueryContext(ctx, query, input.Database, input.Table)
+	rows, err := queryPrepared(ctx, queryListPartitions, input.Database, input.Table)
 	if err != nil {
 		return nil, ListPartitionsOutput{}, fmt.Errorf("query failed: %w", err)
 	}
@@ -682,11 +723,22 @@ func toolListStatus(
 	req *mcp.CallToolRequest,
 	input ListStatusInput,
 ) (*mcp.CallToolResult, ListStatusOutput, error) {
+	if input.Offset < 0 {
+		return nil, ListStatusOutput{}, fmt.Errorf("offset must be >= 0")
+	}
+	sortField, desc, err := parseListSort(input.Sort, variableListFields)
+	if err != nil {
+		return nil, ListStatusOutput{}, err
+	}
+	filters, err := parseListFilters(input.Filter, variableListFields)
+	if err != nil {
+		return nil, ListStatusOutput{}, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
 	var rows *sql.Rows
-	var err error
 
 	// Use performance_schema for better performance and flexibility
 	if input.Pattern != "" {
@@ -710,21 +762,22 @@ func toolListStatus(
 	}
 	defer rows.Close()
 
-	out := ListStatusOutput{Variables: []StatusVariable{}}
+	all := []StatusVariable{}
 	for rows.Next() {
 		var v StatusVariable
 		if err := rows.Scan(&v.Name, &v.Value); err != nil {
 			continue
 		}
-		out.Variables = append(out.Variables, v)
-		if len(out.Variables) >= maxRows {
-			break
-		}
+		all = append(all, v)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, ListStatusOutput{}, err
 	}
 
+	out := ListStatusOutput{}
+	out.Variables, out.Total, out.NextOffset = pageNameValues(all, func(v StatusVariable) (string, string) { return v.Name, v.Value },
+		filters, sortField, desc, input.Offset, listLimit(input.Limit))
+	out.HasMore = out.NextOffset != nil
 	return nil, out, nil
 }
 
@@ -733,11 +786,22 @@ func toolListVariables(
 	req *mcp.CallToolRequest,
 	input ListVariablesInput,
 ) (*mcp.CallToolResult, ListVariablesOutput, error) {
+	if input.Offset < 0 {
+		return nil, ListVariablesOutput{}, fmt.Errorf("offset must be >= 0")
+	}
+	sortField, desc, err := parseListSort(input.Sort, variableListFields)
+	if err != nil {
+		return nil, ListVariablesOutput{}, err
+	}
+	filters, err := parseListFilters(input.Filter, variableListFields)
+	if err != nil {
+		return nil, ListVariablesOutput{}, err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
 	defer cancel()
 
 	var rows *sql.Rows
-	var err error
 
 	// Prefer SHOW GLOBAL VARIABLES first: it is the most compatible path across managed
 	// MySQL/MariaDB deployments. Some environments stall when selecting from
@@ -762,21 +826,22 @@ func toolListVariables(
 	}
 	defer rows.Close()
 
-	out := ListVariablesOutput{Variables: []ServerVariable{}}
+	all := []ServerVariable{}
 	for rows.Next() {
 		var v ServerVariable
 		if err := rows.Scan(&v.Name, &v.Value); err != nil {
 			continue
 		}
-		out.Variables = append(out.Variables, v)
-		if len(out.Variables) >= maxRows {
-			break
-		}
+		all = append(all, v)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, ListVariablesOutput{}, err
 	}
 
+	out := ListVariablesOutput{}
+	out.Variables, out.Total, out.NextOffset = pageNameValues(all, func(v ServerVariable) (string, string) { return v.Name, v.Value },
+		filters, sortField, desc, input.Offset, listLimit(input.Limit))
+	out.HasMore = out.NextOffset != nil
 	return nil, out, nil
 }
 
@@ -810,16 +875,9 @@ func toolSearchSchema(
 		tableQuery += " AND TABLE_SCHEMA = ?"
 		tableArgs = append(tableArgs, input.Database)
 	} else if accessControlEnabled() {
-		allowed := allowedDatabasesLower()
-		if len(allowed) == 0 {
-			return nil, SearchSchemaOutput{}, fmt.Errorf("MYSQL_MCP_ALLOWED_DATABASES is set but empty; cannot run search_schema without a database filter")
-		}
-		ph := strings.Repeat("?,", len(allowed))
-		ph = ph[:len(ph)-1]
-		tableQuery += " AND LOWER(TABLE_SCHEMA) IN (" + ph + ")"
-		for _, db := range allowed {
-			tableArgs = append(tableArgs, db)
-		}
+		filter, args := allowedSchemaFilter("TABLE_SCHEMA")
+		tableQuery += " AND " + filter
+		tableArgs = append(tableArgs, args...)
 	} else {
 		tableQuery += " AND TABLE_SCHEMA NOT IN ('information_schema', 'performance_schema', 'mysql', 'sys')"
 	}
@@ -855,16 +913,9 @@ func toolSearchSchema(
 		colQuery += " AND TABLE_SCHEMA = ?"
 		colArgs = append(colArgs, input.Database)
 	} else if accessControlEnabled() {
-		allowed := allowedDatabasesLower()
-		if len(allowed) == 0 {
-			return nil, SearchSchemaOutput{}, fmt.Errorf("MYSQL_MCP_ALLOWED_DATABASES is set but empty; cannot run search_schema without a database filter")
-		}
-		ph := strings.Repeat("?,", len(allowed))
-		ph = ph[:len(ph)-1]
-		colQuery += " AND LOWER(TABLE_SCHEMA) IN (" + ph + ")"
-		for _, db := range allowed {
-			colArgs = append(colArgs, db)
-		}
+		filter, args := allowedSchemaFilter("TABLE_SCHEMA")
+		colQuery += " AND " + filter
+		colArgs = append(colArgs, args...)
 	} else {
 		colQuery += " AND TABLE_SCHEMA NOT IN ('information_schema', 'performance_schema', 'mysql', 'sys')"
 	}
@@ -1044,6 +1095,9 @@ func compareTableSchema(ctx context.Context, sourceDB, targetDB, table string) (
 
 // ===== Vector Tool Handlers (MySQL 9.0+) =====
 
+// vectorSearchMaxQueries caps the query vectors of one batched vector_search.
+const vectorSearchMaxQueries = 32
+
 func toolVectorSearch(
 	ctx context.Context,
 	req *mcp.CallToolRequest,
@@ -1055,9 +1109,23 @@ func toolVectorSearch(
 	if err := requireAllowedDatabase(input.Database); err != nil {
 		return nil, VectorSearchOutput{}, err
 	}
-	if len(input.Query) == 0 {
+	if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+		return nil, VectorSearchOutput{}, err
+	}
+	if len(input.Query) > 0 && len(input.Queries) > 0 {
+		return nil, VectorSearchOutput{}, fmt.Errorf("set either query or queries, not both")
+	}
+	if len(input.Query) == 0 && len(input.Queries) == 0 {
 		return nil, VectorSearchOutput{}, fmt.Errorf("query vector is required")
 	}
+	if len(input.Queries) > vectorSearchMaxQueries {
+		return nil, VectorSearchOutput{}, fmt.Errorf("at most %d query vectors can be searched per call, got %d", vectorSearchMaxQueries, len(input.Queries))
+	}
+	for i, q := range input.Queries {
+		if len(q) == 0 {
+			return nil, VectorSearchOutput{}, fmt.Errorf("queries[%d] is empty", i)
+		}
+	}
 
 	dbName, err := util.QuoteIdent(input.Database)
 	if err != nil {
@@ -1084,9 +1152,6 @@ func toolVectorSearch(
 		limit = maxRows
 	}
 
-	// Build vector string for MySQL
-	vectorStr := buildVectorString(input.Query)
-
 	// Determine distance function
 	distFunc := "COSINE"
 	switch strings.ToLower(input.DistanceFunc) {
@@ -1106,38 +1171,89 @@ func toolVectorSearch(
 		selectCols = validatedCols
 	}
 
+	// Validate WHERE clause if provided
+	where := ""
+	if input.Where != "" {
+		if err := util.ValidateWhereClause(input.Where); err != nil {
+			return nil, VectorSearchOutput{}, fmt.Errorf("invalid where clause: %w", err)
+		}
+		where = " WHERE " + input.Where
+	}
+
+	if input.ReturnVector {
+		selectCols += fmt.Sprintf(", VECTOR_TO_STRING(%s) AS _vector", colName)
+	}
+
+	// HAVING can filter on the _distance alias, which WHERE cannot see.
+	having := ""
+	if input.MaxDistance != nil {
+		if math.IsNaN(*input.MaxDistance) || math.IsInf(*input.MaxDistance, 0) {
+			return nil, VectorSearchOutput{}, fmt.Errorf("max_distance must be a finite number")
+		}
+		having = " HAVING _distance <= " + strconv.FormatFloat(*input.MaxDistance, 'g', -1, 64)
+	}
+
 	// Build query with vector distance
-	query := fmt.Sprintf(`
+	buildQuery := func(vec []float64) string {
+		return fmt.Sprintf(`
 		SELECT %s, 
 			DISTANCE(%s, STRING_TO_VECTOR('%s'), '%s') AS _distance
 		FROM %s.%s
-	`, selectCols, colName, vectorStr, distFunc, dbName, tableName)
+	`, selectCols, colName, buildVectorString(vec), distFunc, dbName, tableName) +
+			where + having + fmt.Sprintf(" ORDER BY _distance ASC LIMIT %d", limit)
+	}
 
-	// Validate WHERE clause if provided
-	if input.Where != "" {
-		if err := util.ValidateWhereClause(input.Where); err != nil {
-			return nil, VectorSearchOutput{}, fmt.Errorf("invalid where clause: %w", err)
+	if len(input.Queries) == 0 {
+		results, err := runVectorSearch(ctx, getDB(), buildQuery(input.Query))
+		if err != nil {
+			return nil, VectorSearchOutput{}, err
 		}
-		query += " WHERE " + input.Where
+		return nil, VectorSearchOutput{Results: results, Count: len(results)}, nil
 	}
 
-	query += fmt.Sprintf(" ORDER BY _distance ASC LIMIT %d", limit)
+	// A batch runs its searches one after another on a single pooled
+	// connection, so it costs one connection however many vectors it has.
+	conn, err := getDB().Conn(ctx)
+	if err != nil {
+		return nil, VectorSearchOutput{}, fmt.Errorf("vector search failed: %w", err)
+	}
+	defer conn.Close()
 
-	rows, err := getDB().QueryContext(ctx, query)
+	out := VectorSearchOutput{Results: []VectorSearchResult{}, Batches: make([]VectorSearchBatch, 0, len(input.Queries))}
+	for i, vec := range input.Queries {
+		results, err := runVectorSearch(ctx, conn, buildQuery(vec))
+		if err != nil {
+			return nil, VectorSearchOutput{}, fmt.Errorf("queries[%d]: %w", i, err)
+		}
+		out.Batches = append(out.Batches, VectorSearchBatch{Query: i, Results: results, Count: len(results)})
+		out.Count += len(results)
+	}
+	return nil, out, nil
+}
+
+// vectorQueryer is a *sql.DB or, for a batched search, a single *sql.Conn.
+type vectorQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// runVectorSearch runs one vector_search query and returns its rows ordered
+// by distance.
+func runVectorSearch(ctx context.Context, db vectorQueryer, query string) ([]VectorSearchResult, error) {
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		if strings.Contains(err.Error(), "DISTANCE") || strings.Contains(err.Error(), "STRING_TO_VECTOR") {
-			return nil, VectorSearchOutput{}, fmt.Errorf("vector search failed (MySQL 9.0+ required): %w", err)
+			return nil, fmt.Errorf("vector search failed (MySQL 9.0+ required): %w", err)
 		}
-		return nil, VectorSearchOutput{}, fmt.Errorf("vector search failed: %w", err)
+		return nil, fmt.Errorf("vector search failed: %w", err)
 	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, VectorSearchOutput{}, fmt.Errorf("failed to get columns: %w", err)
+		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
-	out := VectorSearchOutput{Results: []VectorSearchResult{}}
+	results := []VectorSearchResult{}
 
 	for rows.Next() {
 		values := make([]interface{}, len(cols))
@@ -1155,20 +1271,21 @@ func toolVectorSearch(
 		}
 
 		for i, col := range cols {
-			if col == "_distance" {
-				if dist, ok := values[i].(float64); ok {
-					result.Distance = dist
-				}
-			} else {
+			switch col {
+			case "_distance":
+				// DOUBLE, or DECIMAL bytes when the server casts the score.
+				result.Distance = numericValue(values[i])
+			case "_vector":
+				result.Vector = parseVectorString(values[i])
+			default:
 				result.Data[col] = util.NormalizeValue(values[i])
 			}
 		}
 
-		out.Results = append(out.Results, result)
+		results = append(results, result)
 	}
 
-	out.Count = len(out.Results)
-	return nil, out, nil
+	return results, nil
 }
 
 func toolVectorInfo(
@@ -1264,6 +1381,30 @@ func buildVectorString(vec []float64) string {
 	return "[" + strings.Join(parts, ",") + "]"
 }
 
+// parseVectorString parses VECTOR_TO_STRING output such as
+// "[1.00000e+00,2.50000e-01]". Elements that do not parse are returned as 0.
+func parseVectorString(v interface{}) []float64 {
+	var s string
+	switch x := v.(type) {
+	case []byte:
+		s = string(x)
+	case string:
+		s = x
+	default:
+		return nil
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "["), "]"))
+	if s == "" {
+		return []float64{}
+	}
+	parts := strings.Split(s, ",")
+	vec := make([]float64, len(parts))
+	for i, p := range parts {
+		vec[i], _ = strconv.ParseFloat(strings.TrimSpace(p), 64)
+	}
+	return vec
+}
+
 // isVectorSupported checks if MySQL version supports VECTOR type (9.0+).
 // Returns false for MariaDB and unknown server types to avoid incorrectly
 // enabling MySQL-specific features.
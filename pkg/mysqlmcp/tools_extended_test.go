@@ -1,5 +1,5 @@
-// cmd/mysql-mcp-server/tools_extended_test.go
-package main
+// pkg/mysqlmcp/tools_extended_test.go
+package mysqlmcp
 
 import (
 	"context"
@@ -25,21 +25,24 @@ func setupExtendedMockDB(t *testing.T) (sqlmock.Sqlmock, func()) {
 	oldConnManager := connManager
 	oldMaxRows := maxRows
 	oldQueryTimeout := queryTimeout
+	oldPingTimeout := pingTimeout
 
 	// Set up mock connection manager with mock DB
 	cm := NewConnectionManager()
 	cm.connections["mock"] = mockDB
-	cm.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: "mock://test"}
+	cm.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: mockDSN}
 	cm.activeConn = "mock"
 	connManager = cm
 
 	maxRows = 1000
 	queryTimeout = 30 * time.Second
+	pingTimeout = time.Duration(config.DefaultPingTimeoutSecs) * time.Second
 
 	cleanup := func() {
 		connManager = oldConnManager
 		maxRows = oldMaxRows
 		queryTimeout = oldQueryTimeout
+		pingTimeout = oldPingTimeout
 		mockDB.Close()
 	}
 
@@ -188,6 +191,47 @@ func TestToolShowCreateTableMissingInputs(t *testing.T) {
 	}
 }
 
+// ===== toolNormalizeQuery Tests =====
+
+func TestToolNormalizeQuerySuccess(t *testing.T) {
+	ctx := context.Background()
+	_, a, err := toolNormalizeQuery(ctx, &mcp.CallToolRequest{}, NormalizeQueryInput{
+		SQL: "SELECT u.name FROM users u WHERE u.id IN (1, 2, 3) AND u.status = 'active'",
+	})
+	if err != nil {
+		t.Fatalf("toolNormalizeQuery failed: %v", err)
+	}
+	_, b, err := toolNormalizeQuery(ctx, &mcp.CallToolRequest{}, NormalizeQueryInput{
+		SQL: "select u.name from users u where u.id in (42) and u.status = 'banned';",
+	})
+	if err != nil {
+		t.Fatalf("toolNormalizeQuery failed: %v", err)
+	}
+
+	if a.Digest != b.Digest {
+		t.Errorf("expected equal digests, got %q and %q", a.Fingerprint, b.Fingerprint)
+	}
+	if strings.Contains(a.Fingerprint, "active") {
+		t.Errorf("fingerprint still contains literal: %s", a.Fingerprint)
+	}
+	if len(a.Tables) != 1 || a.Tables[0] != "users" {
+		t.Errorf("unexpected tables: %v", a.Tables)
+	}
+	if len(a.Columns) != 3 || a.Columns[0] != "users.id" {
+		t.Errorf("unexpected columns: %v", a.Columns)
+	}
+}
+
+func TestToolNormalizeQueryEmptySQL(t *testing.T) {
+	_, _, err := toolNormalizeQuery(context.Background(), &mcp.CallToolRequest{}, NormalizeQueryInput{SQL: "  "})
+	if err == nil {
+		t.Fatal("expected error for empty SQL")
+	}
+	if err.Error() != "sql is required" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 // ===== toolExplainQuery Tests =====
 
 func TestToolExplainQuerySuccess(t *testing.T) {
@@ -1044,6 +1088,75 @@ func TestToolVectorSearchMissingInputs(t *testing.T) {
 	}
 }
 
+func TestToolVectorSearchBatch(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("STRING_TO_VECTOR\\('\\[0.100000,0.200000\\]'\\).*WHERE lang = 'en' ORDER BY _distance ASC LIMIT 2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "_distance"}).AddRow(1, 0.1).AddRow(2, 0.3))
+	mock.ExpectQuery("STRING_TO_VECTOR\\('\\[0.900000,0.800000\\]'\\).*WHERE lang = 'en' ORDER BY _distance ASC LIMIT 2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "_distance"}).AddRow(7, 0.05))
+
+	_, out, err := toolVectorSearch(context.Background(), &mcp.CallToolRequest{}, VectorSearchInput{
+		Database: "db", Table: "docs", Column: "vec", Limit: 2, Where: "lang = 'en'",
+		Queries: [][]float64{{0.1, 0.2}, {0.9, 0.8}},
+	})
+	if err != nil {
+		t.Fatalf("vector_search: %v", err)
+	}
+	if len(out.Batches) != 2 || out.Count != 3 || len(out.Results) != 0 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if b := out.Batches[1]; b.Query != 1 || b.Count != 1 || b.Results[0].Data["id"] != int64(7) || b.Results[0].Distance != 0.05 {
+		t.Errorf("unexpected second batch: %+v", b)
+	}
+
+	_, _, err = toolVectorSearch(context.Background(), &mcp.CallToolRequest{}, VectorSearchInput{
+		Database: "db", Table: "docs", Column: "vec", Query: []float64{0.1}, Queries: [][]float64{{0.1}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected query/queries conflict, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolVectorSearchMaxDistanceAndVector(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	// The distance comes back as DECIMAL bytes, as when the score is cast.
+	mock.ExpectQuery("SELECT `id`, VECTOR_TO_STRING\\(`vec`\\) AS _vector,.*HAVING _distance <= 0.25 ORDER BY _distance").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "_vector", "_distance"}).
+			AddRow(1, []byte("[1.00000e+00,2.50000e-01]"), []byte("0.1250")))
+
+	maxDist := 0.25
+	_, out, err := toolVectorSearch(context.Background(), &mcp.CallToolRequest{}, VectorSearchInput{
+		Database: "db", Table: "docs", Column: "vec", Select: "id",
+		Query: []float64{1, 0}, MaxDistance: &maxDist, ReturnVector: true,
+	})
+	if err != nil {
+		t.Fatalf("vector_search: %v", err)
+	}
+	if out.Count != 1 {
+		t.Fatalf("expected 1 result, got %+v", out)
+	}
+	r := out.Results[0]
+	if r.Distance != 0.125 {
+		t.Errorf("expected distance 0.125, got %v", r.Distance)
+	}
+	if len(r.Vector) != 2 || r.Vector[0] != 1 || r.Vector[1] != 0.25 {
+		t.Errorf("unexpected vector: %v", r.Vector)
+	}
+	if _, ok := r.Data["_vector"]; ok {
+		t.Error("_vector should not be part of the row data")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 // ===== toolVectorInfo Tests =====
 
 func TestToolVectorInfoMissingDatabase(t *testing.T) {
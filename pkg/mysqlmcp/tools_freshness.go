@@ -0,0 +1,211 @@
+// pkg/mysqlmcp/tools_freshness.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// data_freshness tells how current each table is: MAX() of the timestamp
+// column configured in freshness.tables (or a summary_tables freshness_column)
+// and otherwise UPDATE_TIME from information_schema.TABLES. Answers are kept
+// per connection and table for freshness.cache_seconds, so agents can check
+// before every analysis without rescanning the same index.
+const (
+	freshnessSourceColumn     = "column"
+	freshnessSourceUpdateTime = "update_time"
+	freshnessSourceUnknown    = "unknown"
+
+	// dataFreshnessLimit caps the tables one call reports.
+	dataFreshnessLimit = 50
+)
+
+type freshnessEntry struct {
+	table   TableFreshness
+	fetched time.Time
+}
+
+// freshnessCache holds data_freshness answers keyed by connection, database
+// and table.
+type freshnessCache struct {
+	mu      sync.Mutex
+	entries map[string]freshnessEntry
+}
+
+var dataFreshnessCache = &freshnessCache{entries: map[string]freshnessEntry{}}
+
+func freshnessCacheKey(database, table string) string {
+	return activeConnectionName() + "\x00" + strings.ToLower(database) + "\x00" + strings.ToLower(table)
+}
+
+func (c *freshnessCache) get(key string, ttl time.Duration) (TableFreshness, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Since(e.fetched) >= ttl {
+		return TableFreshness{}, false
+	}
+	return e.table, true
+}
+
+func (c *freshnessCache) put(key string, t TableFreshness) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = freshnessEntry{table: t, fetched: time.Now()}
+}
+
+// freshnessColumn returns the configured timestamp column of database.table,
+// or "".
+func freshnessColumn(database, table string) string {
+	if cfg == nil {
+		return ""
+	}
+	for name, column := range cfg.FreshnessColumns {
+		if db, t, _ := strings.Cut(name, "."); strings.EqualFold(db, database) && strings.EqualFold(t, table) {
+			return column
+		}
+	}
+	for _, st := range cfg.SummaryTables {
+		if db, t, _ := strings.Cut(st.Table, "."); st.FreshnessColumn != "" && strings.EqualFold(db, database) && strings.EqualFold(t, table) {
+			return st.FreshnessColumn
+		}
+	}
+	return ""
+}
+
+type tableUpdateTime struct {
+	name       string
+	updateTime sql.NullString
+	age        sql.NullInt64
+}
+
+func toolDataFreshness(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input DataFreshnessInput,
+) (*mcp.CallToolResult, DataFreshnessOutput, error) {
+	if input.Database == "" {
+		return nil, DataFreshnessOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, DataFreshnessOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	q := `
+		SELECT TABLE_NAME, UPDATE_TIME, TIMESTAMPDIFF(SECOND, UPDATE_TIME, NOW())
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'`
+	args := []interface{}{input.Database}
+	if input.Table != "" {
+		q += ` AND TABLE_NAME = ?`
+		args = append(args, input.Table)
+	}
+	rows, err := getDB().QueryContext(ctx, q+` ORDER BY TABLE_NAME`, args...)
+	if err != nil {
+		return nil, DataFreshnessOutput{}, fmt.Errorf("failed to list tables: %w", err)
+	}
+	var tables []tableUpdateTime
+	for rows.Next() {
+		var t tableUpdateTime
+		if err := rows.Scan(&t.name, &t.updateTime, &t.age); err != nil {
+			rows.Close()
+			return nil, DataFreshnessOutput{}, fmt.Errorf("failed to read tables: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, DataFreshnessOutput{}, fmt.Errorf("failed to read tables: %w", err)
+	}
+	if input.Table != "" && len(tables) == 0 {
+		return nil, DataFreshnessOutput{}, fmt.Errorf("table %s.%s not found", input.Database, input.Table)
+	}
+
+	out := DataFreshnessOutput{Database: input.Database, Tables: []TableFreshness{}}
+	if len(tables) > dataFreshnessLimit {
+		out.Notes = append(out.Notes, fmt.Sprintf("Only the first %d of %d tables are reported; pass table to check another one.", dataFreshnessLimit, len(tables)))
+		tables = tables[:dataFreshnessLimit]
+	}
+	ttl := time.Duration(0)
+	if cfg != nil {
+		ttl = cfg.FreshnessCacheTTL
+	}
+	var usedUpdateTime bool
+	for _, t := range tables {
+		key := freshnessCacheKey(input.Database, t.name)
+		if !input.Refresh {
+			if cached, ok := dataFreshnessCache.get(key, ttl); ok {
+				cached.Cached = true
+				out.Tables = append(out.Tables, cached)
+				usedUpdateTime = usedUpdateTime || cached.Source == freshnessSourceUpdateTime
+				continue
+			}
+		}
+		tf := tableFreshness(ctx, input.Database, t)
+		dataFreshnessCache.put(key, tf)
+		out.Tables = append(out.Tables, tf)
+		usedUpdateTime = usedUpdateTime || tf.Source == freshnessSourceUpdateTime
+	}
+	if usedUpdateTime {
+		out.Notes = append(out.Notes, "update_time comes from information_schema.TABLES: InnoDB does not keep it across restarts, and MySQL 8.0 may serve it from a statistics cache up to information_schema_stats_expiry seconds old. Configure a timestamp column in freshness.tables for exact answers.")
+	}
+	return nil, out, nil
+}
+
+// tableFreshness reads MAX() of t's configured column, falling back to its
+// UPDATE_TIME when there is none or the query fails.
+func tableFreshness(ctx context.Context, database string, t tableUpdateTime) TableFreshness {
+	tf := TableFreshness{Table: t.name, Source: freshnessSourceUnknown, CheckedAt: time.Now().UTC().Format(time.RFC3339)}
+	if column := freshnessColumn(database, t.name); column != "" {
+		if err := requireNoRowPolicy(database, t.name); err != nil {
+			tf.Error = err.Error()
+		} else if last, age, err := maxColumnValue(ctx, database, t.name, column); err != nil {
+			tf.Error = err.Error()
+		} else {
+			tf.Source, tf.Column, tf.LastUpdated = freshnessSourceColumn, column, last.String
+			if age.Valid {
+				tf.AgeSeconds = &age.Int64
+			}
+			return tf
+		}
+	}
+	if t.updateTime.Valid {
+		tf.Source, tf.LastUpdated = freshnessSourceUpdateTime, t.updateTime.String
+		if t.age.Valid {
+			tf.AgeSeconds = &t.age.Int64
+		}
+	}
+	return tf
+}
+
+func maxColumnValue(ctx context.Context, database, table, column string) (sql.NullString, sql.NullInt64, error) {
+	var last sql.NullString
+	var age sql.NullInt64
+	qdb, err := util.QuoteIdent(database)
+	if err != nil {
+		return last, age, err
+	}
+	qtable, err := util.QuoteIdent(table)
+	if err != nil {
+		return last, age, err
+	}
+	qcol, err := util.QuoteIdent(column)
+	if err != nil {
+		return last, age, err
+	}
+	q := fmt.Sprintf("SELECT MAX(%s), TIMESTAMPDIFF(SECOND, MAX(%s), NOW()) FROM %s.%s", qcol, qcol, qdb, qtable)
+	if err := getDB().QueryRowContext(ctx, q).Scan(&last, &age); err != nil {
+		return last, age, fmt.Errorf("MAX(%s) failed: %w", column, err)
+	}
+	return last, age, nil
+}
@@ -0,0 +1,85 @@
+// pkg/mysqlmcp/tools_freshness_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolDataFreshness(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	oldCfg, oldCache := cfg, dataFreshnessCache
+	cfg = &config.Config{
+		FreshnessColumns:  map[string]string{"shop.orders": "created_at"},
+		FreshnessCacheTTL: time.Minute,
+		SummaryTables:     []config.SummaryTable{{Table: "shop.store_revenue", FreshnessColumn: "refreshed_at"}},
+	}
+	dataFreshnessCache = &freshnessCache{entries: map[string]freshnessEntry{}}
+	defer func() { cfg, dataFreshnessCache = oldCfg, oldCache }()
+	ctx := context.Background()
+
+	listTables := func() {
+		mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop").
+			WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "UPDATE_TIME", "AGE"}).
+				AddRow("audit", nil, nil).
+				AddRow("orders", "2026-10-15 08:00:00", 7200).
+				AddRow("store_revenue", nil, nil).
+				AddRow("users", "2026-10-15 09:59:00", 60))
+	}
+	listTables()
+	mock.ExpectQuery("SELECT MAX\\(`created_at`\\), TIMESTAMPDIFF\\(SECOND, MAX\\(`created_at`\\), NOW\\(\\)\\) FROM `shop`.`orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"max", "age"}).AddRow("2026-10-15 09:59:30", 30))
+	mock.ExpectQuery("SELECT MAX\\(`refreshed_at`\\).* FROM `shop`.`store_revenue`").
+		WillReturnError(errors.New("Unknown column 'refreshed_at'"))
+
+	_, out, err := toolDataFreshness(ctx, &mcp.CallToolRequest{}, DataFreshnessInput{Database: "shop"})
+	if err != nil {
+		t.Fatalf("data_freshness: %v", err)
+	}
+	if len(out.Tables) != 4 || len(out.Notes) != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if tf := out.Tables[0]; tf.Source != freshnessSourceUnknown || tf.LastUpdated != "" {
+		t.Errorf("unexpected audit: %+v", tf)
+	}
+	if tf := out.Tables[1]; tf.Source != freshnessSourceColumn || tf.Column != "created_at" || tf.AgeSeconds == nil || *tf.AgeSeconds != 30 || tf.Cached {
+		t.Errorf("unexpected orders: %+v", tf)
+	}
+	if tf := out.Tables[2]; tf.Source != freshnessSourceUnknown || !strings.Contains(tf.Error, "refreshed_at") {
+		t.Errorf("unexpected store_revenue: %+v", tf)
+	}
+	if tf := out.Tables[3]; tf.Source != freshnessSourceUpdateTime || tf.LastUpdated != "2026-10-15 09:59:00" || *tf.AgeSeconds != 60 {
+		t.Errorf("unexpected users: %+v", tf)
+	}
+
+	// A second call within the TTL reuses the MAX() answers.
+	listTables()
+	_, out, err = toolDataFreshness(ctx, &mcp.CallToolRequest{}, DataFreshnessInput{Database: "shop"})
+	if err != nil {
+		t.Fatalf("data_freshness: %v", err)
+	}
+	if tf := out.Tables[1]; !tf.Cached || *tf.AgeSeconds != 30 {
+		t.Errorf("expected a cached answer for orders, got %+v", tf)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolDataFreshnessUnknownTable(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop", "nope").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "UPDATE_TIME", "AGE"}))
+	if _, _, err := toolDataFreshness(context.Background(), &mcp.CallToolRequest{}, DataFreshnessInput{Database: "shop", Table: "nope"}); err == nil {
+		t.Error("expected error for an unknown table")
+	}
+}
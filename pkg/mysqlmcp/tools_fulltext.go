@@ -0,0 +1,257 @@
+// pkg/mysqlmcp/tools_fulltext.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MATCH ... AGAINST search modifiers accepted by fulltext_search.
+var fulltextModes = map[string]string{
+	"natural":         "IN NATURAL LANGUAGE MODE",
+	"boolean":         "IN BOOLEAN MODE",
+	"query_expansion": "WITH QUERY EXPANSION",
+}
+
+func toolFulltextSearch(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input FulltextSearchInput,
+) (*mcp.CallToolResult, FulltextSearchOutput, error) {
+	if input.Database == "" || input.Table == "" {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("database and table are required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, FulltextSearchOutput{}, err
+	}
+	if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+		return nil, FulltextSearchOutput{}, err
+	}
+	if strings.TrimSpace(input.Query) == "" {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("query is required")
+	}
+	mode := strings.ToLower(strings.TrimSpace(input.Mode))
+	if mode == "" {
+		mode = "natural"
+	}
+	modifier, ok := fulltextModes[mode]
+	if !ok {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("mode must be one of: natural, boolean, query_expansion")
+	}
+
+	dbName, err := util.QuoteIdent(input.Database)
+	if err != nil {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("invalid database name: %w", err)
+	}
+	tableName, err := util.QuoteIdent(input.Table)
+	if err != nil {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("invalid table name: %w", err)
+	}
+
+	selectCols := "*"
+	if input.Select != "" {
+		selectCols, err = util.ValidateSelectColumns(input.Select)
+		if err != nil {
+			return nil, FulltextSearchOutput{}, fmt.Errorf("invalid select columns: %w", err)
+		}
+	}
+	if err := util.ValidateWhereClause(input.Where); err != nil {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("invalid where clause: %w", err)
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxRows {
+		limit = maxRows
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	index, columns, err := resolveFulltextIndex(ctx, input.Database, input.Table, input.Columns)
+	if err != nil {
+		return nil, FulltextSearchOutput{}, err
+	}
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		if quoted[i], err = util.QuoteIdent(c); err != nil {
+			return nil, FulltextSearchOutput{}, fmt.Errorf("invalid column name: %w", err)
+		}
+	}
+
+	// The search text is always bound as a parameter; MATCH appears twice so
+	// MySQL can use the FULLTEXT index for filtering and the score.
+	match := fmt.Sprintf("MATCH(%s) AGAINST(? %s)", strings.Join(quoted, ", "), modifier)
+	query := fmt.Sprintf("SELECT %s, %s AS _score FROM %s.%s WHERE %s",
+		selectCols, match, dbName, tableName, match)
+	args := []interface{}{input.Query, input.Query}
+	if input.Where != "" {
+		query += " AND (" + input.Where + ")"
+	}
+	query += fmt.Sprintf(" ORDER BY _score DESC LIMIT %d", limit)
+
+	rows, err := getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("fulltext search failed: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	out := FulltextSearchOutput{
+		Index:   index,
+		Columns: columns,
+		Mode:    mode,
+		Results: []FulltextSearchResult{},
+	}
+	// The row data goes through the same masking and pseudonymization as
+	// run_query; the score column is kept apart so no pattern can hit it.
+	var dataCols []string
+	for _, col := range cols {
+		if col != "_score" {
+			dataCols = append(dataCols, col)
+		}
+	}
+	var dataRows [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, FulltextSearchOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+
+		var result FulltextSearchResult
+		data := make([]interface{}, 0, len(dataCols))
+		for i, col := range cols {
+			if col == "_score" {
+				result.Score = numericValue(values[i])
+			} else {
+				data = append(data, util.NormalizeValue(values[i]))
+			}
+		}
+		out.Results = append(out.Results, result)
+		dataRows = append(dataRows, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FulltextSearchOutput{}, fmt.Errorf("fulltext search rows iteration: %w", err)
+	}
+
+	if cfg != nil && len(cfg.MaskColumns) > 0 {
+		maskResults(dataCols, dataRows, cfg.MaskColumns)
+	}
+	pseudonymizeResults(ctx, dataCols, dataRows)
+	for i, data := range dataRows {
+		out.Results[i].Data = make(map[string]interface{}, len(dataCols))
+		for j, col := range dataCols {
+			out.Results[i].Data[col] = data[j]
+		}
+	}
+
+	out.Count = len(out.Results)
+	return nil, out, nil
+}
+
+// resolveFulltextIndex returns the FULLTEXT index on database.table covering
+// exactly the requested columns (in index order). With no columns requested,
+// the table must have a single FULLTEXT index.
+func resolveFulltextIndex(ctx context.Context, database, table string, want []string) (string, []string, error) {
+	rows, err := getDB().QueryContext(ctx, `SELECT INDEX_NAME, COLUMN_NAME
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_TYPE = 'FULLTEXT'
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`, database, table)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read FULLTEXT indexes: %w", err)
+	}
+	defer rows.Close()
+
+	indexes := map[string][]string{}
+	var names []string
+	for rows.Next() {
+		var name, column string
+		if err := rows.Scan(&name, &column); err != nil {
+			return "", nil, fmt.Errorf("scan failed: %w", err)
+		}
+		if _, ok := indexes[name]; !ok {
+			names = append(names, name)
+		}
+		indexes[name] = append(indexes[name], column)
+	}
+	if err := rows.Err(); err != nil {
+		return "", nil, fmt.Errorf("FULLTEXT indexes iteration: %w", err)
+	}
+
+	if len(names) == 0 {
+		return "", nil, fmt.Errorf("table %s.%s has no FULLTEXT index", database, table)
+	}
+	if len(want) == 0 {
+		if len(names) > 1 {
+			return "", nil, fmt.Errorf("table %s.%s has several FULLTEXT indexes; set columns to one of: %s",
+				database, table, describeFulltextIndexes(names, indexes))
+		}
+		return names[0], indexes[names[0]], nil
+	}
+
+	key := fulltextColumnKey(want)
+	for _, name := range names {
+		if fulltextColumnKey(indexes[name]) == key {
+			return name, indexes[name], nil
+		}
+	}
+	return "", nil, fmt.Errorf("no FULLTEXT index on %s.%s covers exactly (%s); available: %s",
+		database, table, strings.Join(want, ", "), describeFulltextIndexes(names, indexes))
+}
+
+// fulltextColumnKey is an order- and case-insensitive key for a column set.
+func fulltextColumnKey(columns []string) string {
+	lower := make([]string, len(columns))
+	for i, c := range columns {
+		lower[i] = strings.ToLower(strings.TrimSpace(c))
+	}
+	sort.Strings(lower)
+	return strings.Join(lower, ",")
+}
+
+func describeFulltextIndexes(names []string, indexes map[string][]string) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s(%s)", name, strings.Join(indexes[name], ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// numericValue converts a numeric column value (relevance score, vector
+// distance, EXPLAIN rows) to float64; the text protocol returns numbers, and
+// DECIMAL in either protocol, as bytes.
+func numericValue(v interface{}) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case float32:
+		return float64(x)
+	case int64:
+		return float64(x)
+	case uint64:
+		return float64(x)
+	case []byte:
+		f, _ := strconv.ParseFloat(string(x), 64)
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(x, 64)
+		return f
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+// pkg/mysqlmcp/tools_fulltext_test.go
+package mysqlmcp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+)
+
+func fulltextIndexRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"INDEX_NAME", "COLUMN_NAME"}).
+		AddRow("ft_body", "body").
+		AddRow("ft_title_body", "title").
+		AddRow("ft_title_body", "body")
+}
+
+func TestToolFulltextSearchBooleanMode(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM information_schema.STATISTICS`).
+		WithArgs("blog", "posts").
+		WillReturnRows(fulltextIndexRows())
+	mock.ExpectQuery("SELECT `id`, `title`, MATCH\\(`title`, `body`\\) AGAINST\\(\\? IN BOOLEAN MODE\\) AS _score FROM `blog`.`posts` WHERE MATCH\\(`title`, `body`\\) AGAINST\\(\\? IN BOOLEAN MODE\\) AND \\(published = 1\\) ORDER BY _score DESC LIMIT 5").
+		WithArgs("+mysql -oracle", "+mysql -oracle").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "_score"}).
+			AddRow(7, "MySQL tips", []byte("1.25")).
+			AddRow(3, "Intro", 0.5))
+
+	_, out, err := toolFulltextSearch(context.Background(), &mcp.CallToolRequest{}, FulltextSearchInput{
+		Database: "blog",
+		Table:    "posts",
+		Columns:  []string{"Body", "title"},
+		Query:    "+mysql -oracle",
+		Mode:     "boolean",
+		Select:   "id, title",
+		Where:    "published = 1",
+		Limit:    5,
+	})
+	if err != nil {
+		t.Fatalf("toolFulltextSearch failed: %v", err)
+	}
+	if out.Index != "ft_title_body" || out.Mode != "boolean" || out.Count != 2 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if out.Results[0].Score != 1.25 || out.Results[0].Data["title"] != "MySQL tips" {
+		t.Errorf("unexpected first result: %+v", out.Results[0])
+	}
+	if _, ok := out.Results[0].Data["_score"]; ok {
+		t.Error("score should not be duplicated in data")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolFulltextSearchMasksColumns(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	oldCfg, oldPseudonyms := cfg, pseudonyms
+	cfg = &config.Config{MaskColumns: []string{"secret"}, PseudonymizeColumns: []string{"email"}}
+	initPseudonymizer(cfg)
+	defer func() { cfg, pseudonyms = oldCfg, oldPseudonyms }()
+
+	mock.ExpectQuery(`FROM information_schema.STATISTICS`).
+		WithArgs("blog", "posts").
+		WillReturnRows(sqlmock.NewRows([]string{"INDEX_NAME", "COLUMN_NAME"}).AddRow("ft_body", "body"))
+	mock.ExpectQuery("SELECT \\*, MATCH\\(`body`\\)").
+		WithArgs("mysql", "mysql").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "author_email", "api_secret", "_score"}).
+			AddRow(7, "ada@example.com", "s3cr3t", 1.5).
+			AddRow(8, nil, "hunter2", 0.5))
+
+	_, out, err := toolFulltextSearch(context.Background(), &mcp.CallToolRequest{}, FulltextSearchInput{
+		Database: "blog",
+		Table:    "posts",
+		Query:    "mysql",
+	})
+	if err != nil {
+		t.Fatalf("toolFulltextSearch failed: %v", err)
+	}
+	if out.Count != 2 || out.Results[0].Score != 1.5 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	first := out.Results[0].Data
+	if first["api_secret"] != "********" || out.Results[1].Data["api_secret"] != "********" {
+		t.Errorf("expected api_secret masked, got %+v", out.Results)
+	}
+	if email, _ := first["author_email"].(string); email == "" || strings.Contains(email, "ada") {
+		t.Errorf("expected author_email pseudonymized, got %v", first["author_email"])
+	}
+	if out.Results[1].Data["author_email"] != nil || first["id"] != int64(7) {
+		t.Errorf("unexpected unredacted values: %+v", out.Results)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolFulltextSearchIndexValidation(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`FROM information_schema.STATISTICS`).
+		WithArgs("blog", "posts").
+		WillReturnRows(fulltextIndexRows())
+	mock.ExpectQuery(`FROM information_schema.STATISTICS`).
+		WithArgs("blog", "posts").
+		WillReturnRows(fulltextIndexRows())
+	mock.ExpectQuery(`FROM information_schema.STATISTICS`).
+		WithArgs("blog", "tags").
+		WillReturnRows(sqlmock.NewRows([]string{"INDEX_NAME", "COLUMN_NAME"}))
+
+	ctx := context.Background()
+	_, _, err := toolFulltextSearch(ctx, &mcp.CallToolRequest{}, FulltextSearchInput{
+		Database: "blog", Table: "posts", Columns: []string{"title"}, Query: "x",
+	})
+	if err == nil || !strings.Contains(err.Error(), "ft_title_body(title, body)") {
+		t.Errorf("expected uncovered-columns error listing indexes, got %v", err)
+	}
+	_, _, err = toolFulltextSearch(ctx, &mcp.CallToolRequest{}, FulltextSearchInput{
+		Database: "blog", Table: "posts", Query: "x",
+	})
+	if err == nil || !strings.Contains(err.Error(), "several FULLTEXT indexes") {
+		t.Errorf("expected ambiguous index error, got %v", err)
+	}
+	_, _, err = toolFulltextSearch(ctx, &mcp.CallToolRequest{}, FulltextSearchInput{
+		Database: "blog", Table: "tags", Query: "x",
+	})
+	if err == nil || !strings.Contains(err.Error(), "no FULLTEXT index") {
+		t.Errorf("expected missing index error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolFulltextSearchInvalidInput(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cases := []FulltextSearchInput{
+		{Table: "posts", Query: "x"},
+		{Database: "blog", Table: "posts"},
+		{Database: "blog", Table: "posts", Query: "x", Mode: "fuzzy"},
+		{Database: "blog", Table: "posts", Query: "x", Where: "1=1; DROP TABLE posts"},
+	}
+	for _, in := range cases {
+		if _, _, err := toolFulltextSearch(ctx, &mcp.CallToolRequest{}, in); err == nil {
+			t.Errorf("expected error for %+v", in)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPFulltextSearchBadRequest(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	for _, body := range []string{`{"database": "blog", "table": "posts"}`, `{"database": "blog", "table": "posts", "query": "x", "mode": "fuzzy"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/api/fulltext/search", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		httpFulltextSearch(w, req)
+
+		if w.Result().StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Result().StatusCode)
+		}
+	}
+}
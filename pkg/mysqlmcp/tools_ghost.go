@@ -0,0 +1,142 @@
+// pkg/mysqlmcp/tools_ghost.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Online schema change tools reported by list_ghost_tables.
+const (
+	ghostToolGhost = "gh-ost"
+	ghostToolPTOSC = "pt-online-schema-change"
+	ghostToolLHM   = "lhm"
+)
+
+var (
+	// gh-ost: _t_gho (ghost copy), _t_ghc (changelog), _t_del or
+	// _t_20240131120000_del (old table kept with --ok-to-drop-table off).
+	ghostGhOstRe = regexp.MustCompile(`^_(.+?)(?:_\d{14})?_(gho|ghc|del)$`)
+	// pt-online-schema-change: _t_new (copy), _t_old or __t_old (old table).
+	ghostPTOSCRe = regexp.MustCompile(`^_+(.+)_(new|old)$`)
+	// LHM: lhmn_t (copy), lhma_2024_01_31_12_00_00_000_t (archived old table).
+	ghostLHMNewRe     = regexp.MustCompile(`^lhmn_(.+)$`)
+	ghostLHMArchiveRe = regexp.MustCompile(`^lhma_[\d_]+_(.+)$`)
+)
+
+// classifyGhostTable reports whether name is an artifact of an online schema
+// change tool, which tool left it, its role and the table it was copied from.
+func classifyGhostTable(name string) (tool, role, original string, ok bool) {
+	if m := ghostGhOstRe.FindStringSubmatch(name); m != nil {
+		role := map[string]string{"gho": "ghost copy", "ghc": "changelog", "del": "old table"}[m[2]]
+		return ghostToolGhost, role, m[1], true
+	}
+	if m := ghostPTOSCRe.FindStringSubmatch(name); m != nil {
+		role := "new copy"
+		if m[2] == "old" {
+			role = "old table"
+		}
+		return ghostToolPTOSC, role, m[1], true
+	}
+	if m := ghostLHMNewRe.FindStringSubmatch(name); m != nil {
+		return ghostToolLHM, "new copy", m[1], true
+	}
+	if m := ghostLHMArchiveRe.FindStringSubmatch(name); m != nil {
+		return ghostToolLHM, "old table", m[1], true
+	}
+	return "", "", "", false
+}
+
+func toolListGhostTables(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListGhostTablesInput,
+) (*mcp.CallToolResult, ListGhostTablesOutput, error) {
+	if input.Database == "" {
+		return nil, ListGhostTablesOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, ListGhostTablesOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	rows, err := getDB().QueryContext(ctx, `
+		SELECT TABLE_NAME, TABLE_ROWS, DATA_LENGTH + INDEX_LENGTH, CREATE_TIME
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'
+		ORDER BY TABLE_NAME`, input.Database)
+	if err != nil {
+		return nil, ListGhostTablesOutput{}, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	out := ListGhostTablesOutput{Database: input.Database, Tables: []GhostTable{}, Triggers: []GhostTrigger{}}
+	exists := map[string]bool{}
+	for rows.Next() {
+		var name string
+		var tableRows, size sql.NullInt64
+		var created sql.NullString
+		if err := rows.Scan(&name, &tableRows, &size, &created); err != nil {
+			return nil, ListGhostTablesOutput{}, fmt.Errorf("failed to read tables: %w", err)
+		}
+		exists[strings.ToLower(name)] = true
+		tool, role, original, ok := classifyGhostTable(name)
+		if !ok {
+			continue
+		}
+		out.Tables = append(out.Tables, GhostTable{
+			Table:         name,
+			Tool:          tool,
+			Role:          role,
+			OriginalTable: original,
+			Rows:          tableRows.Int64,
+			SizeBytes:     size.Int64,
+			CreateTime:    created.String,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ListGhostTablesOutput{}, fmt.Errorf("failed to read tables: %w", err)
+	}
+	for i := range out.Tables {
+		out.Tables[i].OriginalExists = exists[strings.ToLower(out.Tables[i].OriginalTable)]
+	}
+
+	// pt-osc and LHM copy writes with triggers on the original table; left
+	// behind, they keep slowing down every write to it.
+	trgRows, err := getDB().QueryContext(ctx, `
+		SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE
+		FROM information_schema.TRIGGERS
+		WHERE TRIGGER_SCHEMA = ? AND (TRIGGER_NAME LIKE 'pt\_osc\_%' OR TRIGGER_NAME LIKE 'lhmt\_%')
+		ORDER BY TRIGGER_NAME`, input.Database)
+	if err != nil {
+		return nil, ListGhostTablesOutput{}, fmt.Errorf("failed to list triggers: %w", err)
+	}
+	defer trgRows.Close()
+	for trgRows.Next() {
+		var t GhostTrigger
+		if err := trgRows.Scan(&t.Trigger, &t.Table); err != nil {
+			return nil, ListGhostTablesOutput{}, fmt.Errorf("failed to read triggers: %w", err)
+		}
+		t.Tool = ghostToolPTOSC
+		if strings.HasPrefix(strings.ToLower(t.Trigger), "lhmt_") {
+			t.Tool = ghostToolLHM
+		}
+		out.Triggers = append(out.Triggers, t)
+	}
+	if err := trgRows.Err(); err != nil {
+		return nil, ListGhostTablesOutput{}, fmt.Errorf("failed to read triggers: %w", err)
+	}
+
+	out.Count = len(out.Tables)
+	if out.Count > 0 || len(out.Triggers) > 0 {
+		out.Notes = append(out.Notes, "A migration may still be running: check the process list (or gh-ost's changelog table) before dropping anything.")
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,66 @@
+// pkg/mysqlmcp/tools_ghost_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestClassifyGhostTable(t *testing.T) {
+	tests := []struct {
+		name, tool, role, original string
+	}{
+		{"_orders_gho", ghostToolGhost, "ghost copy", "orders"},
+		{"_orders_ghc", ghostToolGhost, "changelog", "orders"},
+		{"_orders_20240131120000_del", ghostToolGhost, "old table", "orders"},
+		{"_order_items_new", ghostToolPTOSC, "new copy", "order_items"},
+		{"__orders_old", ghostToolPTOSC, "old table", "orders"},
+		{"lhmn_users", ghostToolLHM, "new copy", "users"},
+		{"lhma_2024_01_31_12_00_00_000_users", ghostToolLHM, "old table", "users"},
+		{"orders", "", "", ""},
+		{"orders_old", "", "", ""},
+	}
+	for _, tc := range tests {
+		tool, role, original, ok := classifyGhostTable(tc.name)
+		if ok != (tc.tool != "") || tool != tc.tool || role != tc.role || original != tc.original {
+			t.Errorf("classifyGhostTable(%q) = %q, %q, %q, %v; want %q, %q, %q", tc.name, tool, role, original, ok, tc.tool, tc.role, tc.original)
+		}
+	}
+}
+
+func TestToolListGhostTables(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "TABLE_ROWS", "SIZE", "CREATE_TIME"}).
+			AddRow("_orders_gho", 1200, 65536, "2024-01-31 12:00:00").
+			AddRow("_payments_old", 10, 16384, nil).
+			AddRow("orders", 5000, 262144, "2020-01-01 00:00:00"))
+	mock.ExpectQuery("FROM information_schema.TRIGGERS").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TRIGGER_NAME", "EVENT_OBJECT_TABLE"}).
+			AddRow("pt_osc_shop_payments_ins", "payments"))
+
+	_, out, err := toolListGhostTables(context.Background(), &mcp.CallToolRequest{}, ListGhostTablesInput{Database: "shop"})
+	if err != nil {
+		t.Fatalf("list_ghost_tables: %v", err)
+	}
+	if out.Count != 2 || len(out.Notes) != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if g := out.Tables[0]; g.Tool != ghostToolGhost || g.OriginalTable != "orders" || !g.OriginalExists || g.SizeBytes != 65536 {
+		t.Errorf("unexpected gh-ost table: %+v", g)
+	}
+	if g := out.Tables[1]; g.Tool != ghostToolPTOSC || g.OriginalExists {
+		t.Errorf("unexpected pt-osc table (payments does not exist): %+v", g)
+	}
+	if len(out.Triggers) != 1 || out.Triggers[0].Tool != ghostToolPTOSC {
+		t.Errorf("unexpected triggers: %+v", out.Triggers)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+// pkg/mysqlmcp/tools_grants.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func toolShowGrants(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ShowGrantsInput,
+) (*mcp.CallToolResult, ShowGrantsOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	out := ShowGrantsOutput{Grants: []GrantEntry{}}
+	if err := getDB().QueryRowContext(ctx, "SELECT CURRENT_USER()").Scan(&out.User); err != nil {
+		return nil, ShowGrantsOutput{}, fmt.Errorf("failed to get current user: %w", err)
+	}
+
+	// Always CURRENT_USER(): the tool never reports other accounts.
+	rows, err := getDB().QueryContext(ctx, "SHOW GRANTS FOR CURRENT_USER()")
+	if err != nil {
+		return nil, ShowGrantsOutput{}, fmt.Errorf("SHOW GRANTS failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, ShowGrantsOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		g, roles, ok := parseGrantStatement(line)
+		if !ok {
+			out.Notes = append(out.Notes, "unrecognized grant statement skipped")
+			continue
+		}
+		if roles != nil {
+			out.Roles = append(out.Roles, roles...)
+			continue
+		}
+		if input.Database != "" && !grantAppliesToDatabase(g, input.Database) {
+			continue
+		}
+		out.Grants = append(out.Grants, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ShowGrantsOutput{}, fmt.Errorf("SHOW GRANTS rows iteration: %w", err)
+	}
+
+	return nil, out, nil
+}
+
+// parseGrantStatement parses one SHOW GRANTS line. Role grants
+// ("GRANT `r`@`%` TO ...") return the role names instead of a GrantEntry. The
+// grantee and everything after it (including IDENTIFIED BY PASSWORD hashes on
+// older servers) are never returned.
+func parseGrantStatement(line string) (GrantEntry, []string, bool) {
+	stmt := strings.TrimSpace(line)
+	upper := strings.ToUpper(stmt)
+
+	var g GrantEntry
+	switch {
+	case strings.HasPrefix(upper, "GRANT "):
+		stmt = stmt[len("GRANT "):]
+	case strings.HasPrefix(upper, "REVOKE "):
+		stmt = stmt[len("REVOKE "):]
+		g.Revoke = true
+	default:
+		return GrantEntry{}, nil, false
+	}
+
+	on := indexTopLevelKeyword(stmt, " ON ")
+	to := indexTopLevelKeyword(stmt, " TO ")
+	if g.Revoke {
+		to = indexTopLevelKeyword(stmt, " FROM ")
+	}
+	if to < 0 {
+		return GrantEntry{}, nil, false
+	}
+	if on < 0 || on > to {
+		// Role grant: no object, just role accounts.
+		var roles []string
+		for _, r := range splitTopLevel(stmt[:to], ',') {
+			roles = append(roles, unquoteAccount(r))
+		}
+		return GrantEntry{}, roles, true
+	}
+
+	tail := strings.ToUpper(stmt[to:])
+	g.GrantOption = strings.Contains(tail, "WITH GRANT OPTION")
+
+	for _, p := range splitTopLevel(stmt[:on], ',') {
+		name, cols := p, ""
+		if i := strings.Index(p, "("); i >= 0 && strings.HasSuffix(p, ")") {
+			name, cols = strings.TrimSpace(p[:i]), p[i+1:len(p)-1]
+		}
+		name = strings.ToUpper(name)
+		g.Privileges = append(g.Privileges, name)
+		if cols != "" {
+			if g.Columns == nil {
+				g.Columns = map[string][]string{}
+			}
+			for _, c := range splitTopLevel(cols, ',') {
+				g.Columns[name] = append(g.Columns[name], unquoteIdent(c))
+			}
+		}
+	}
+
+	object := strings.TrimSpace(stmt[on+len(" ON ") : to])
+	objUpper := strings.ToUpper(object)
+	for _, kind := range []string{"TABLE ", "FUNCTION ", "PROCEDURE "} {
+		if strings.HasPrefix(objUpper, kind) {
+			g.ObjectType = strings.ToLower(strings.TrimSpace(kind))
+			object = strings.TrimSpace(object[len(kind):])
+		}
+	}
+
+	switch {
+	case len(g.Privileges) == 1 && g.Privileges[0] == "PROXY":
+		// The object is another account; do not expose it.
+		g.Level = "proxy"
+	default:
+		parts := splitTopLevel(object, '.')
+		if len(parts) != 2 {
+			return GrantEntry{}, nil, false
+		}
+		g.Database, g.Object = unquoteIdent(parts[0]), unquoteIdent(parts[1])
+		switch {
+		case g.Database == "*":
+			g.Level = "global"
+			g.Database, g.Object = "", ""
+		case g.Object == "*":
+			g.Level = "database"
+			g.Object = ""
+		case g.ObjectType == "function" || g.ObjectType == "procedure":
+			g.Level = "routine"
+		case g.Columns != nil:
+			g.Level = "column"
+		default:
+			g.Level = "table"
+		}
+	}
+	return g, nil, true
+}
+
+// grantAppliesToDatabase reports whether g grants (or revokes) anything in database.
+func grantAppliesToDatabase(g GrantEntry, database string) bool {
+	switch g.Level {
+	case "global":
+		return true
+	case "proxy":
+		return false
+	}
+	if strings.ContainsAny(g.Database, "%_") {
+		return likeToRegexp(g.Database).MatchString(database)
+	}
+	return strings.EqualFold(g.Database, database)
+}
+
+// likeToRegexp converts a grant database pattern (% and _ wildcards, \ escapes) to a regexp.
+func likeToRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?i)^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// indexTopLevelKeyword returns the index of kw (case-insensitive) outside quotes
+// and parentheses, or -1.
+func indexTopLevelKeyword(s, kw string) int {
+	upper := strings.ToUpper(s)
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '`' || c == '\'' || c == '"':
+			quote = c
+			continue
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		}
+		if depth == 0 && strings.HasPrefix(upper[i:], kw) {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s on sep outside quotes and parentheses, trimming each part.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '`' || c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func unquoteIdent(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '`' || s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return strings.ReplaceAll(s[1:len(s)-1], string(s[0])+string(s[0]), string(s[0]))
+	}
+	return s
+}
+
+// unquoteAccount turns `name`@`host` into name@host.
+func unquoteAccount(s string) string {
+	parts := splitTopLevel(s, '@')
+	for i := range parts {
+		parts[i] = unquoteIdent(parts[i])
+	}
+	return strings.Join(parts, "@")
+}
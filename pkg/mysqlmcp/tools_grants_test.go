@@ -0,0 +1,97 @@
+// pkg/mysqlmcp/tools_grants_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestParseGrantStatement(t *testing.T) {
+	tests := []struct {
+		line string
+		want GrantEntry
+	}{
+		{
+			"GRANT USAGE ON *.* TO `app`@`%`",
+			GrantEntry{Level: "global", Privileges: []string{"USAGE"}},
+		},
+		{
+			"GRANT SELECT, SHOW VIEW ON `app\\_db`.* TO `app`@`%` WITH GRANT OPTION",
+			GrantEntry{Level: "database", Database: "app\\_db", Privileges: []string{"SELECT", "SHOW VIEW"}, GrantOption: true},
+		},
+		{
+			"GRANT SELECT (`id`, `email`), INSERT ON `crm`.`customers` TO 'app'@'localhost'",
+			GrantEntry{Level: "column", Database: "crm", Object: "customers", Privileges: []string{"SELECT", "INSERT"},
+				Columns: map[string][]string{"SELECT": {"id", "email"}}},
+		},
+		{
+			"GRANT EXECUTE ON PROCEDURE `app`.`refresh` TO `app`@`%`",
+			GrantEntry{Level: "routine", Database: "app", Object: "refresh", ObjectType: "procedure", Privileges: []string{"EXECUTE"}},
+		},
+		{
+			"REVOKE INSERT ON `mysql`.* FROM `app`@`%`",
+			GrantEntry{Level: "database", Database: "mysql", Privileges: []string{"INSERT"}, Revoke: true},
+		},
+		{
+			"GRANT PROXY ON 'admin'@'localhost' TO 'app'@'%'",
+			GrantEntry{Level: "proxy", Privileges: []string{"PROXY"}},
+		},
+	}
+	for _, tt := range tests {
+		got, roles, ok := parseGrantStatement(tt.line)
+		if !ok || roles != nil {
+			t.Errorf("%s: ok=%v roles=%v", tt.line, ok, roles)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.line, got, tt.want)
+		}
+	}
+
+	_, roles, ok := parseGrantStatement("GRANT `reader`@`%`,`auditor`@`%` TO `app`@`%`")
+	if !ok || !reflect.DeepEqual(roles, []string{"reader@%", "auditor@%"}) {
+		t.Errorf("unexpected roles %v (ok=%v)", roles, ok)
+	}
+	if _, _, ok := parseGrantStatement("something else"); ok {
+		t.Error("expected non-grant line to be rejected")
+	}
+}
+
+func TestToolShowGrants(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT CURRENT_USER\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"CURRENT_USER()"}).AddRow("app@%"))
+	mock.ExpectQuery("SHOW GRANTS FOR CURRENT_USER\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"Grants for app@%"}).
+			AddRow("GRANT USAGE ON *.* TO 'app'@'%' IDENTIFIED BY PASSWORD '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'").
+			AddRow("GRANT SELECT ON `shop%`.* TO 'app'@'%'").
+			AddRow("GRANT SELECT ON `crm`.* TO 'app'@'%'").
+			AddRow("GRANT `reader`@`%` TO `app`@`%`"))
+
+	_, out, err := toolShowGrants(context.Background(), &mcp.CallToolRequest{}, ShowGrantsInput{Database: "shop_eu"})
+	if err != nil {
+		t.Fatalf("toolShowGrants failed: %v", err)
+	}
+	if out.User != "app@%" || len(out.Grants) != 2 || !reflect.DeepEqual(out.Roles, []string{"reader@%"}) {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if out.Grants[1].Database != "shop%" {
+		t.Errorf("expected wildcard database grant, got %+v", out.Grants[1])
+	}
+	raw, _ := json.Marshal(out)
+	if strings.Contains(string(raw), "6BB4837E") {
+		t.Error("password hash leaked into output")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Health severities, ordered from best to worst.
+const (
+	severityOK       = "ok"
+	severityUnknown  = "unknown"
+	severityWarning  = "warning"
+	severityCritical = "critical"
+)
+
+var severityRank = map[string]int{
+	severityOK:       0,
+	severityUnknown:  1,
+	severityWarning:  2,
+	severityCritical: 3,
+}
+
+// healthStatusVars are the global status counters health_report evaluates.
+var healthStatusVars = []string{
+	"Uptime", "Threads_connected", "Threads_running", "Max_used_connections",
+	"Innodb_buffer_pool_read_requests", "Innodb_buffer_pool_reads",
+	"Created_tmp_tables", "Created_tmp_disk_tables",
+	"Slow_queries", "Questions",
+}
+
+// fetchGlobalStatus reads the named global status counters, preferring
+// performance_schema.global_status and falling back to SHOW GLOBAL STATUS.
+// Keys in the returned map are lowercased.
+func fetchGlobalStatus(ctx context.Context, db *sql.DB, names []string) (map[string]int64, error) {
+	ph := strings.Repeat("?,", len(names))
+	ph = ph[:len(ph)-1]
+	args := make([]interface{}, len(names))
+	for i := range names {
+		args[i] = names[i]
+	}
+	rows, err := db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status WHERE VARIABLE_NAME IN (%s)`, ph),
+		args...)
+	if err != nil {
+		rows, err = db.QueryContext(ctx,
+			fmt.Sprintf(`SHOW GLOBAL STATUS WHERE Variable_name IN (%s)`, ph), args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer rows.Close()
+
+	out := make(map[string]int64, len(names))
+	for rows.Next() {
+		var n, v string
+		if err := rows.Scan(&n, &v); err != nil {
+			continue
+		}
+		iv, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			continue
+		}
+		out[strings.ToLower(n)] = iv
+	}
+	return out, rows.Err()
+}
+
+// healthCheck builds a HealthCheck with a measured value.
+func healthCheck(name, severity string, value float64, unit, msg string) HealthCheck {
+	v := value
+	return HealthCheck{Name: name, Severity: severity, Value: &v, Unit: unit, Message: msg}
+}
+
+// severityAtLeast returns critical/warning/ok for value compared against ascending thresholds.
+func severityAtLeast(value, warn, crit float64) string {
+	switch {
+	case value >= crit:
+		return severityCritical
+	case value >= warn:
+		return severityWarning
+	default:
+		return severityOK
+	}
+}
+
+func toolHealthReport(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input HealthReportInput,
+) (*mcp.CallToolResult, HealthReportOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	topWaits := input.TopWaits
+	if topWaits <= 0 {
+		topWaits = 5
+	}
+	if topWaits > 20 {
+		topWaits = 20
+	}
+
+	out := HealthReportOutput{Checks: []HealthCheck{}}
+
+	if err := getDB().QueryRowContext(ctx, "SELECT VERSION()").Scan(&out.Version); err != nil {
+		return nil, HealthReportOutput{}, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	st, err := fetchGlobalStatus(ctx, getDB(), healthStatusVars)
+	if err != nil {
+		return nil, HealthReportOutput{}, fmt.Errorf("failed to get server status: %w", err)
+	}
+	out.UptimeSeconds = st["uptime"]
+
+	// Uptime: a very recent restart makes every counter-based ratio below unreliable.
+	if out.UptimeSeconds < 600 {
+		out.Checks = append(out.Checks, healthCheck("uptime", severityWarning, float64(out.UptimeSeconds), "seconds",
+			"server restarted less than 10 minutes ago; counter-based ratios are not yet representative"))
+	} else {
+		out.Checks = append(out.Checks, healthCheck("uptime", severityOK, float64(out.UptimeSeconds), "seconds", "server has been up long enough for stable counters"))
+	}
+
+	// Buffer pool hit ratio.
+	reqs, reads := st["innodb_buffer_pool_read_requests"], st["innodb_buffer_pool_reads"]
+	if reqs > 0 {
+		hit := 100.0 * (1.0 - float64(reads)/float64(reqs))
+		if hit < 0 {
+			hit = 0
+		}
+		sev := severityOK
+		switch {
+		case hit < 95:
+			sev = severityCritical
+		case hit < 99:
+			sev = severityWarning
+		}
+		out.Checks = append(out.Checks, healthCheck("buffer_pool_hit_ratio", sev, hit, "percent",
+			fmt.Sprintf("%.2f%% of InnoDB page reads served from the buffer pool", hit)))
+	} else {
+		out.Checks = append(out.Checks, HealthCheck{Name: "buffer_pool_hit_ratio", Severity: severityUnknown, Message: "no InnoDB buffer pool read requests recorded"})
+	}
+
+	// Connection usage against max_connections.
+	var maxConnName string
+	var maxConn int64
+	err = getDB().QueryRowContext(ctx, "SHOW VARIABLES LIKE 'max_connections'").Scan(&maxConnName, &maxConn)
+	if err == nil && maxConn > 0 {
+		used := 100.0 * float64(st["threads_connected"]) / float64(maxConn)
+		peak := 100.0 * float64(st["max_used_connections"]) / float64(maxConn)
+		out.Checks = append(out.Checks, healthCheck("connections_usage", severityAtLeast(used, 75, 90), used, "percent",
+			fmt.Sprintf("%d of %d connections in use (peak %d, %.0f%%); %d running",
+				st["threads_connected"], maxConn, st["max_used_connections"], peak, st["threads_running"])))
+	} else {
+		out.Checks = append(out.Checks, HealthCheck{Name: "connections_usage", Severity: severityUnknown, Message: "max_connections unavailable"})
+	}
+
+	// Temporary tables spilled to disk.
+	if tmp := st["created_tmp_tables"]; tmp > 0 {
+		pct := 100.0 * float64(st["created_tmp_disk_tables"]) / float64(tmp)
+		out.Checks = append(out.Checks, healthCheck("tmp_disk_tables", severityAtLeast(pct, 25, 50), pct, "percent",
+			fmt.Sprintf("%.1f%% of internal temporary tables were created on disk", pct)))
+	} else {
+		out.Checks = append(out.Checks, healthCheck("tmp_disk_tables", severityOK, 0, "percent", "no internal temporary tables created"))
+	}
+
+	// Slow query rate relative to total statements.
+	if q := st["questions"]; q > 0 {
+		pct := 100.0 * float64(st["slow_queries"]) / float64(q)
+		msg := fmt.Sprintf("%d slow queries out of %d statements (%.2f%%)", st["slow_queries"], q, pct)
+		if out.UptimeSeconds > 0 {
+			msg += fmt.Sprintf(", %.2f per minute", float64(st["slow_queries"])*60/float64(out.UptimeSeconds))
+		}
+		out.Checks = append(out.Checks, healthCheck("slow_query_rate", severityAtLeast(pct, 1, 5), pct, "percent", msg))
+	} else {
+		out.Checks = append(out.Checks, HealthCheck{Name: "slow_query_rate", Severity: severityUnknown, Message: "no statements recorded"})
+	}
+
+	out.Checks = append(out.Checks, replicationLagCheck(ctx, &out))
+
+	waits, err := topWaitEvents(ctx, topWaits)
+	if err != nil {
+		out.Notes = append(out.Notes, fmt.Sprintf("top wait events unavailable (performance_schema): %v", err))
+	} else {
+		out.TopWaitEvents = waits
+	}
+
+	out.Status = severityOK
+	for _, c := range out.Checks {
+		if severityRank[c.Severity] > severityRank[out.Status] {
+			out.Status = c.Severity
+		}
+	}
+
+	return nil, out, nil
+}
+
+// replicationLagCheck inspects SHOW REPLICA STATUS (SHOW SLAVE STATUS on older servers).
+// A server that is not a replica reports ok.
+func replicationLagCheck(ctx context.Context, out *HealthReportOutput) HealthCheck {
+	lag, isReplica, err := readReplicaLag(ctx, getDB())
+	if err != nil && !isReplica {
+		out.Notes = append(out.Notes, fmt.Sprintf("replication status unavailable (need REPLICATION CLIENT): %v", err))
+		return HealthCheck{Name: "replication_lag", Severity: severityUnknown, Message: "replication status unavailable"}
+	}
+	if err != nil {
+		return HealthCheck{Name: "replication_lag", Severity: severityUnknown, Message: err.Error()}
+	}
+	if !isReplica {
+		return HealthCheck{Name: "replication_lag", Severity: severityOK, Message: "not configured as a replica"}
+	}
+	if !lag.Valid || lag.String == "" {
+		return HealthCheck{Name: "replication_lag", Severity: severityCritical, Message: "replica is configured but replication threads are not running (lag is NULL)"}
+	}
+	secs, err := strconv.ParseFloat(lag.String, 64)
+	if err != nil {
+		return HealthCheck{Name: "replication_lag", Severity: severityUnknown, Message: fmt.Sprintf("unexpected lag value %q", lag.String)}
+	}
+	return healthCheck("replication_lag", severityAtLeast(secs, 30, 300), secs, "seconds",
+		fmt.Sprintf("replica is %.0f seconds behind its source", secs))
+}
+
+// readReplicaLag returns Seconds_Behind_Source (Seconds_Behind_Master) from
+// SHOW REPLICA STATUS, falling back to SHOW SLAVE STATUS. isReplica is false
+// when db is not configured as a replica; a NULL lag means the replication
+// threads are not running.
+func readReplicaLag(ctx context.Context, db *sql.DB) (lag sql.NullString, isReplica bool, err error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	if err != nil {
+		rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS")
+		if err != nil {
+			return lag, false, err
+		}
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil || !rows.Next() {
+		return lag, false, nil
+	}
+	raw := make([]sql.NullString, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range raw {
+		ptrs[i] = &raw[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return lag, true, fmt.Errorf("could not read replica status: %w", err)
+	}
+	for i, c := range cols {
+		switch strings.ToLower(c) {
+		case "seconds_behind_source", "seconds_behind_master":
+			lag = raw[i]
+		}
+	}
+	return lag, true, nil
+}
+
+// topWaitEvents returns the non-idle wait events with the highest total wait time.
+func topWaitEvents(ctx context.Context, limit int) ([]WaitEventSummary, error) {
+	rows, err := getDB().QueryContext(ctx, `
+		SELECT EVENT_NAME, COUNT_STAR, SUM_TIMER_WAIT
+		FROM performance_schema.events_waits_summary_global_by_event_name
+		WHERE EVENT_NAME <> 'idle' AND COUNT_STAR > 0
+		ORDER BY SUM_TIMER_WAIT DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []WaitEventSummary{}
+	for rows.Next() {
+		var name string
+		var count int64
+		var sumPicos float64
+		if err := rows.Scan(&name, &count, &sumPicos); err != nil {
+			continue
+		}
+		// Timer columns are in picoseconds.
+		out = append(out, WaitEventSummary{Event: name, Count: count, TotalWaitMs: sumPicos / 1e9})
+	}
+	return out, rows.Err()
+}
+
+func toolMetricsHistory(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input MetricsHistoryInput,
+) (*mcp.CallToolResult, MetricsHistoryOutput, error) {
+	if globalMetricsSampler == nil {
+		return nil, MetricsHistoryOutput{}, fmt.Errorf("metrics sampler is not enabled (set MYSQL_MCP_METRICS_SAMPLE_SECONDS)")
+	}
+	window := input.WindowMinutes
+	if window <= 0 {
+		window = 15
+	}
+
+	_, conn := connManager.GetActive()
+	samples := globalMetricsSampler.Since(time.Now().Add(-time.Duration(window)*time.Minute), conn)
+
+	out := MetricsHistoryOutput{
+		Connection:      conn,
+		IntervalSeconds: int(globalMetricsSampler.interval.Seconds()),
+		SampleCount:     len(samples),
+		Metrics:         []MetricSeriesSummary{},
+	}
+	if len(samples) < 2 {
+		out.Note = "fewer than two samples in the window; wait for the sampler to collect more data"
+	}
+	if len(samples) == 0 {
+		return nil, out, nil
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	out.WindowStart = first.Time.Format(time.RFC3339)
+	out.WindowEnd = last.Time.Format(time.RFC3339)
+	elapsed := last.Time.Sub(first.Time).Seconds()
+
+	for _, name := range sampledCounters {
+		key := strings.ToLower(name)
+		m := MetricSeriesSummary{Name: name, Kind: "counter", Start: first.Values[key], End: last.Values[key]}
+		for i := 1; i < len(samples); i++ {
+			prev, cur := samples[i-1].Values[key], samples[i].Values[key]
+			if cur < prev {
+				// Counter restarted (server restart): count from zero.
+				m.Reset = true
+				m.Delta += cur
+			} else {
+				m.Delta += cur - prev
+			}
+		}
+		if elapsed > 0 {
+			m.RatePerSec = float64(m.Delta) / elapsed
+		}
+		out.Metrics = append(out.Metrics, m)
+	}
+	for _, name := range sampledGauges {
+		key := strings.ToLower(name)
+		m := MetricSeriesSummary{Name: name, Kind: "gauge", Start: first.Values[key], End: last.Values[key]}
+		m.Min, m.Max = first.Values[key], first.Values[key]
+		var sum int64
+		for _, s := range samples {
+			v := s.Values[key]
+			if v < m.Min {
+				m.Min = v
+			}
+			if v > m.Max {
+				m.Max = v
+			}
+			sum += v
+		}
+		m.Avg = float64(sum) / float64(len(samples))
+		out.Metrics = append(out.Metrics, m)
+	}
+
+	if input.IncludeSamples {
+		for _, s := range samples {
+			out.Samples = append(out.Samples, MetricsHistorySample{Time: s.Time.Format(time.RFC3339), Values: s.Values})
+		}
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,173 @@
+// pkg/mysqlmcp/tools_health_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func healthStatusRows(values map[string]string) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"})
+	for _, name := range healthStatusVars {
+		if v, ok := values[name]; ok {
+			rows.AddRow(name, v)
+		}
+	}
+	return rows
+}
+
+func findHealthCheck(t *testing.T, out HealthReportOutput, name string) HealthCheck {
+	t.Helper()
+	for _, c := range out.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("check %q not found in %+v", name, out.Checks)
+	return HealthCheck{}
+}
+
+func TestToolHealthReportHealthyPrimary(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT VERSION\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("8.4.0"))
+	mock.ExpectQuery("FROM performance_schema.global_status").
+		WillReturnRows(healthStatusRows(map[string]string{
+			"Uptime":                           "86400",
+			"Threads_connected":                "10",
+			"Threads_running":                  "2",
+			"Max_used_connections":             "20",
+			"Innodb_buffer_pool_read_requests": "1000000",
+			"Innodb_buffer_pool_reads":         "100",
+			"Created_tmp_tables":               "100",
+			"Created_tmp_disk_tables":          "5",
+			"Slow_queries":                     "1",
+			"Questions":                        "10000",
+		}))
+	mock.ExpectQuery("SHOW VARIABLES LIKE 'max_connections'").
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("max_connections", "151"))
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Seconds_Behind_Source"}))
+	mock.ExpectQuery("FROM performance_schema.events_waits_summary_global_by_event_name").
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"EVENT_NAME", "COUNT_STAR", "SUM_TIMER_WAIT"}).
+			AddRow("wait/io/file/innodb/innodb_data_file", 500, 2.5e12))
+
+	_, out, err := toolHealthReport(context.Background(), &mcp.CallToolRequest{}, HealthReportInput{})
+	if err != nil {
+		t.Fatalf("toolHealthReport failed: %v", err)
+	}
+	if out.Status != severityOK {
+		t.Errorf("expected overall ok, got %q: %+v", out.Status, out.Checks)
+	}
+	if out.UptimeSeconds != 86400 {
+		t.Errorf("expected uptime 86400, got %d", out.UptimeSeconds)
+	}
+	if c := findHealthCheck(t, out, "replication_lag"); c.Severity != severityOK {
+		t.Errorf("expected non-replica to be ok, got %+v", c)
+	}
+	if len(out.TopWaitEvents) != 1 || out.TopWaitEvents[0].TotalWaitMs != 2500 {
+		t.Errorf("unexpected wait events: %+v", out.TopWaitEvents)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolHealthReportSeverities(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT VERSION\\(\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("8.0.36"))
+	mock.ExpectQuery("FROM performance_schema.global_status").
+		WillReturnError(fmt.Errorf("performance_schema disabled"))
+	mock.ExpectQuery("SHOW GLOBAL STATUS WHERE").
+		WillReturnRows(healthStatusRows(map[string]string{
+			"Uptime":                           "3600",
+			"Threads_connected":                "95",
+			"Max_used_connections":             "100",
+			"Innodb_buffer_pool_read_requests": "1000",
+			"Innodb_buffer_pool_reads":         "20",
+			"Created_tmp_tables":               "10",
+			"Created_tmp_disk_tables":          "3",
+			"Slow_queries":                     "0",
+			"Questions":                        "100",
+		}))
+	mock.ExpectQuery("SHOW VARIABLES LIKE 'max_connections'").
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("max_connections", "100"))
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnError(fmt.Errorf("syntax error"))
+	mock.ExpectQuery("SHOW SLAVE STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Slave_IO_State", "Seconds_Behind_Master"}).AddRow("Waiting", "120"))
+	mock.ExpectQuery("FROM performance_schema.events_waits_summary_global_by_event_name").
+		WillReturnError(fmt.Errorf("performance_schema disabled"))
+
+	_, out, err := toolHealthReport(context.Background(), &mcp.CallToolRequest{}, HealthReportInput{TopWaits: 3})
+	if err != nil {
+		t.Fatalf("toolHealthReport failed: %v", err)
+	}
+	if out.Status != severityCritical {
+		t.Errorf("expected overall critical, got %q", out.Status)
+	}
+	want := map[string]string{
+		"buffer_pool_hit_ratio": severityWarning,
+		"connections_usage":     severityCritical,
+		"tmp_disk_tables":       severityWarning,
+		"slow_query_rate":       severityOK,
+		"replication_lag":       severityWarning,
+	}
+	for name, sev := range want {
+		if c := findHealthCheck(t, out, name); c.Severity != sev {
+			t.Errorf("%s: expected %s, got %+v", name, sev, c)
+		}
+	}
+	if len(out.Notes) != 1 {
+		t.Errorf("expected a note for missing wait events, got %v", out.Notes)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolHealthReportStoppedReplica(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SHOW REPLICA STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"Replica_IO_State", "Seconds_Behind_Source"}).AddRow("", nil))
+
+	out := HealthReportOutput{}
+	c := replicationLagCheck(context.Background(), &out)
+	if c.Severity != severityCritical {
+		t.Errorf("expected critical for NULL lag, got %+v", c)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPHealthReportInvalidTopWaits(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/health-report?top_waits=abc", nil)
+	w := httptest.NewRecorder()
+
+	httpHealthReport(w, req)
+
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Result().StatusCode)
+	}
+}
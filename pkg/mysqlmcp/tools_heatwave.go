@@ -0,0 +1,283 @@
+// pkg/mysqlmcp/tools_heatwave.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// heatWaveStatusList is the SQL list of the status variables the HeatWave
+// (RAPID) plugin sets; they do not exist on servers without it.
+const heatWaveStatusList = `'rapid_plugin_bootstrapped', 'rapid_cluster_status', 'rapid_cluster_ready_number'`
+
+// heatwave_ml_predict caps.
+const (
+	heatWaveDefaultPredict = 10
+	heatWaveMaxPredict     = 100
+)
+
+// heatWaveFromStatus builds the HeatWave summary from lower-cased status
+// variables, or returns nil when the server has no HeatWave plugin.
+func heatWaveFromStatus(status map[string]string) *HeatWaveInfo {
+	if !strings.EqualFold(status["rapid_plugin_bootstrapped"], "YES") && status["rapid_cluster_status"] == "" {
+		return nil
+	}
+	hw := &HeatWaveInfo{ClusterStatus: status["rapid_cluster_status"]}
+	hw.ReadyNodes, _ = strconv.Atoi(status["rapid_cluster_ready_number"])
+	return hw
+}
+
+// requireHeatWave returns the HeatWave status of the active connection, or
+// an error when it has no HeatWave engine. The HeatWave tools are registered
+// with the extended tools and gated here, per connection.
+func requireHeatWave(ctx context.Context) (*HeatWaveInfo, error) {
+	if getServerType() == ServerTypeMariaDB {
+		return nil, fmt.Errorf("HeatWave is not available on MariaDB")
+	}
+	rows, err := getDB().QueryContext(ctx,
+		`SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status WHERE VARIABLE_NAME IN (`+heatWaveStatusList+`)`)
+	if err != nil {
+		rows, err = getDB().QueryContext(ctx, `SHOW GLOBAL STATUS WHERE Variable_name IN (`+heatWaveStatusList+`)`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read HeatWave status: %w", err)
+		}
+	}
+	defer rows.Close()
+
+	status := map[string]string{}
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			continue
+		}
+		status[strings.ToLower(name)] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read HeatWave status: %w", err)
+	}
+	hw := heatWaveFromStatus(status)
+	if hw == nil {
+		return nil, fmt.Errorf("HeatWave is not available on this connection (no RAPID secondary engine; see server_info)")
+	}
+	return hw, nil
+}
+
+func toolHeatWaveStatus(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input HeatWaveStatusInput,
+) (*mcp.CallToolResult, HeatWaveStatusOutput, error) {
+	database := strings.TrimSpace(input.Database)
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, HeatWaveStatusOutput{}, err
+		}
+	}
+	sqlText := strings.TrimSpace(input.SQL)
+	if sqlText != "" {
+		if !strings.HasPrefix(strings.ToUpper(sqlText), "SELECT") {
+			return nil, HeatWaveStatusOutput{}, fmt.Errorf("only SELECT statements can be explained")
+		}
+		if accessControlEnabled() && database == "" {
+			return nil, HeatWaveStatusOutput{}, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+		}
+		if err := requireReferencedSchemasInQuery(sqlText); err != nil {
+			return nil, HeatWaveStatusOutput{}, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	hw, err := requireHeatWave(ctx)
+	if err != nil {
+		return nil, HeatWaveStatusOutput{}, err
+	}
+	out := HeatWaveStatusOutput{ClusterStatus: hw.ClusterStatus, ReadyNodes: hw.ReadyNodes, Tables: []HeatWaveTable{}}
+	if !strings.EqualFold(hw.ClusterStatus, "ON") {
+		out.Notes = append(out.Notes, "The HeatWave cluster is not ON; queries run on InnoDB until it is started.")
+	}
+	_ = getDB().QueryRowContext(ctx, "SELECT @@use_secondary_engine").Scan(&out.UseSecondaryEngine)
+
+	tables, err := heatWaveTables(ctx, database)
+	if err != nil {
+		return nil, HeatWaveStatusOutput{}, err
+	}
+	out.Tables = tables
+
+	if sqlText != "" {
+		plan, err := runExplain(ctx, database, sqlText)
+		if err != nil {
+			return nil, HeatWaveStatusOutput{}, err
+		}
+		offloaded := false
+		for _, row := range plan {
+			if extra, ok := row["Extra"].(string); ok && strings.Contains(strings.ToLower(extra), "secondary engine") {
+				offloaded = true
+			}
+		}
+		out.Plan = plan
+		out.QueryOffloaded = &offloaded
+		if !offloaded {
+			out.Notes = append(out.Notes, "The query is not offloaded: check that every table it reads is loaded (load_status AVAIL_RPDGSTABSTATE), that use_secondary_engine is ON, and that its cost exceeds secondary_engine_cost_threshold.")
+		}
+	}
+	return nil, out, nil
+}
+
+// heatWaveTables lists the tables defined with SECONDARY_ENGINE=RAPID, with
+// their load status from performance_schema when the server exposes it.
+func heatWaveTables(ctx context.Context, database string) ([]HeatWaveTable, error) {
+	where := "t.CREATE_OPTIONS LIKE '%SECONDARY_ENGINE=%RAPID%'"
+	var args []interface{}
+	if database != "" {
+		where += " AND t.TABLE_SCHEMA = ?"
+		args = append(args, database)
+	}
+	rows, err := getDB().QueryContext(ctx, `
+		SELECT t.TABLE_SCHEMA, t.TABLE_NAME, r.LOAD_STATUS, r.LOAD_PROGRESS, r.NROWS, r.QUERY_COUNT
+		FROM information_schema.TABLES t
+		LEFT JOIN performance_schema.rpd_table_id i ON i.SCHEMA_NAME = t.TABLE_SCHEMA AND i.TABLE_NAME = t.TABLE_NAME
+		LEFT JOIN performance_schema.rpd_tables r ON r.ID = i.ID
+		WHERE `+where+`
+		ORDER BY t.TABLE_SCHEMA, t.TABLE_NAME`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list HeatWave tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []HeatWaveTable{}
+	for rows.Next() {
+		var t HeatWaveTable
+		var status sql.NullString
+		var progress sql.NullFloat64
+		var nrows, queries sql.NullInt64
+		if err := rows.Scan(&t.Database, &t.Table, &status, &progress, &nrows, &queries); err != nil {
+			return nil, fmt.Errorf("failed to read HeatWave tables: %w", err)
+		}
+		if !databaseAllowed(t.Database) {
+			continue
+		}
+		t.LoadStatus, t.LoadProgress, t.Rows, t.QueryCount = status.String, progress.Float64, nrows.Int64, queries.Int64
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+func toolHeatWaveMLPredict(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input HeatWaveMLPredictInput,
+) (*mcp.CallToolResult, HeatWaveMLPredictOutput, error) {
+	if strings.TrimSpace(input.ModelHandle) == "" {
+		return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("model_handle is required")
+	}
+	fromTable := input.Table != ""
+	if fromTable == (len(input.Rows) > 0) {
+		return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("set either rows or database, table and columns")
+	}
+	if len(input.Rows) > heatWaveMaxPredict {
+		return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("at most %d rows can be scored per call, got %d", heatWaveMaxPredict, len(input.Rows))
+	}
+
+	var query string
+	var argSets [][]interface{}
+	if fromTable {
+		if input.Database == "" || len(input.Columns) == 0 {
+			return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("database and columns are required with table")
+		}
+		if err := requireAllowedDatabase(input.Database); err != nil {
+			return nil, HeatWaveMLPredictOutput{}, err
+		}
+		if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+			return nil, HeatWaveMLPredictOutput{}, err
+		}
+		dbName, err := util.QuoteIdent(input.Database)
+		if err != nil {
+			return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("invalid database name: %w", err)
+		}
+		tableName, err := util.QuoteIdent(input.Table)
+		if err != nil {
+			return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("invalid table name: %w", err)
+		}
+		// JSON_OBJECT(?, `col`, ...): each feature keyed by its column name.
+		pairs := make([]string, len(input.Columns))
+		args := make([]interface{}, 0, len(input.Columns)+1)
+		for i, col := range input.Columns {
+			quoted, err := util.QuoteIdent(col)
+			if err != nil {
+				return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("invalid column name %q: %w", col, err)
+			}
+			pairs[i] = "?, " + quoted
+			args = append(args, col)
+		}
+		args = append(args, input.ModelHandle)
+
+		limit := input.Limit
+		if limit <= 0 {
+			limit = heatWaveDefaultPredict
+		}
+		if limit > heatWaveMaxPredict {
+			limit = heatWaveMaxPredict
+		}
+		query = fmt.Sprintf("SELECT sys.ML_PREDICT_ROW(JSON_OBJECT(%s), ?) FROM %s.%s", strings.Join(pairs, ", "), dbName, tableName)
+		if input.Where != "" {
+			if err := util.ValidateWhereClause(input.Where); err != nil {
+				return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("invalid where clause: %w", err)
+			}
+			query += " WHERE " + input.Where
+		}
+		query += fmt.Sprintf(" LIMIT %d", limit)
+		argSets = [][]interface{}{args}
+	} else {
+		query = "SELECT sys.ML_PREDICT_ROW(CAST(? AS JSON), ?)"
+		for i, row := range input.Rows {
+			b, err := json.Marshal(row)
+			if err != nil {
+				return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("rows[%d]: %w", i, err)
+			}
+			argSets = append(argSets, []interface{}{string(b), input.ModelHandle})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	if _, err := requireHeatWave(ctx); err != nil {
+		return nil, HeatWaveMLPredictOutput{}, err
+	}
+
+	out := HeatWaveMLPredictOutput{Predictions: []interface{}{}}
+	for _, args := range argSets {
+		rows, err := getDB().QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("ML_PREDICT_ROW failed (is the model loaded with sys.ML_MODEL_LOAD?): %w", err)
+		}
+		for rows.Next() {
+			var raw sql.RawBytes
+			if err := rows.Scan(&raw); err != nil {
+				rows.Close()
+				return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("failed to read prediction: %w", err)
+			}
+			var prediction interface{}
+			if err := json.Unmarshal(raw, &prediction); err != nil {
+				prediction = string(raw)
+			}
+			out.Predictions = append(out.Predictions, prediction)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, HeatWaveMLPredictOutput{}, fmt.Errorf("failed to read predictions: %w", err)
+		}
+	}
+	out.Count = len(out.Predictions)
+	return nil, out, nil
+}
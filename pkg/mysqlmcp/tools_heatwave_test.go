@@ -0,0 +1,112 @@
+// pkg/mysqlmcp/tools_heatwave_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func expectHeatWaveStatus(mock sqlmock.Sqlmock, rows *sqlmock.Rows) {
+	mock.ExpectQuery("SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status WHERE VARIABLE_NAME IN \\('rapid_plugin_bootstrapped'").
+		WillReturnRows(rows)
+}
+
+func TestHeatWaveFromStatus(t *testing.T) {
+	if hw := heatWaveFromStatus(map[string]string{"uptime": "10"}); hw != nil {
+		t.Errorf("expected no HeatWave without RAPID status, got %+v", hw)
+	}
+	hw := heatWaveFromStatus(map[string]string{"rapid_plugin_bootstrapped": "YES", "rapid_cluster_status": "ON", "rapid_cluster_ready_number": "2"})
+	if hw == nil || hw.ClusterStatus != "ON" || hw.ReadyNodes != 2 {
+		t.Errorf("unexpected HeatWave info: %+v", hw)
+	}
+}
+
+func TestToolHeatWaveStatus(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	expectHeatWaveStatus(mock, sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).
+		AddRow("rapid_plugin_bootstrapped", "YES").
+		AddRow("rapid_cluster_status", "ON").
+		AddRow("rapid_cluster_ready_number", "1"))
+	mock.ExpectQuery("SELECT @@use_secondary_engine").
+		WillReturnRows(sqlmock.NewRows([]string{"@@use_secondary_engine"}).AddRow("ON"))
+	mock.ExpectQuery("FROM information_schema.TABLES t.*rpd_tables.*ORDER BY").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME", "LOAD_STATUS", "LOAD_PROGRESS", "NROWS", "QUERY_COUNT"}).
+			AddRow("sales", "orders", "AVAIL_RPDGSTABSTATE", 100.0, 5000, 12).
+			AddRow("sales", "returns", nil, nil, nil, nil))
+	mock.ExpectQuery("EXPLAIN SELECT COUNT").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "table", "Extra"}).
+			AddRow(1, "orders", "Using secondary engine RAPID"))
+
+	_, out, err := toolHeatWaveStatus(context.Background(), &mcp.CallToolRequest{}, HeatWaveStatusInput{
+		SQL: "SELECT COUNT(*) FROM sales.orders",
+	})
+	if err != nil {
+		t.Fatalf("heatwave_status: %v", err)
+	}
+	if out.ClusterStatus != "ON" || out.ReadyNodes != 1 || out.UseSecondaryEngine != "ON" {
+		t.Errorf("unexpected cluster info: %+v", out)
+	}
+	if len(out.Tables) != 2 || out.Tables[0].LoadStatus != "AVAIL_RPDGSTABSTATE" || out.Tables[0].Rows != 5000 || out.Tables[1].LoadStatus != "" {
+		t.Errorf("unexpected tables: %+v", out.Tables)
+	}
+	if out.QueryOffloaded == nil || !*out.QueryOffloaded {
+		t.Errorf("expected the query to be offloaded, got %v", out.QueryOffloaded)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolHeatWaveRequiresEngine(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	expectHeatWaveStatus(mock, sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}))
+	_, _, err := toolHeatWaveStatus(context.Background(), &mcp.CallToolRequest{}, HeatWaveStatusInput{})
+	if err == nil || !strings.Contains(err.Error(), "HeatWave is not available") {
+		t.Errorf("expected a HeatWave availability error, got %v", err)
+	}
+}
+
+func TestToolHeatWaveMLPredict(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	expectHeatWaveStatus(mock, sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).
+		AddRow("rapid_plugin_bootstrapped", "YES"))
+	mock.ExpectQuery("SELECT sys.ML_PREDICT_ROW\\(JSON_OBJECT\\(\\?, `age`, \\?, `plan`\\), \\?\\) FROM `crm`.`customers` WHERE churned IS NULL LIMIT 2").
+		WithArgs("age", "plan", "churn_model").
+		WillReturnRows(sqlmock.NewRows([]string{"p"}).
+			AddRow(`{"age": 41, "plan": "pro", "Prediction": 1}`).
+			AddRow(`{"age": 23, "plan": "free", "Prediction": 0}`))
+
+	_, out, err := toolHeatWaveMLPredict(context.Background(), &mcp.CallToolRequest{}, HeatWaveMLPredictInput{
+		ModelHandle: "churn_model", Database: "crm", Table: "customers",
+		Columns: []string{"age", "plan"}, Where: "churned IS NULL", Limit: 2,
+	})
+	if err != nil {
+		t.Fatalf("heatwave_ml_predict: %v", err)
+	}
+	if out.Count != 2 {
+		t.Fatalf("expected 2 predictions, got %+v", out)
+	}
+	if p, ok := out.Predictions[0].(map[string]interface{}); !ok || p["Prediction"] != float64(1) {
+		t.Errorf("unexpected prediction: %#v", out.Predictions[0])
+	}
+
+	_, _, err = toolHeatWaveMLPredict(context.Background(), &mcp.CallToolRequest{}, HeatWaveMLPredictInput{
+		ModelHandle: "churn_model", Table: "customers", Rows: []map[string]interface{}{{"age": 30}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "either rows or") {
+		t.Errorf("expected rows/table conflict, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+// pkg/mysqlmcp/tools_optimizer_trace.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultOptimizerTraceBytes = 1 << 20
+	maxOptimizerTraceBytes     = 16 << 20
+)
+
+func toolOptimizerTrace(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input OptimizerTraceInput,
+) (*mcp.CallToolResult, OptimizerTraceOutput, error) {
+	sqlText := strings.TrimSpace(input.SQL)
+	if sqlText == "" {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("sql is required")
+	}
+	if !strings.HasPrefix(strings.ToUpper(sqlText), "SELECT") {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("only SELECT statements can be traced")
+	}
+	if proxyCompat() {
+		return nil, OptimizerTraceOutput{}, proxySessionError("optimizer_trace", "use explain_query for the chosen plan")
+	}
+
+	database := strings.TrimSpace(input.Database)
+	if accessControlEnabled() && database == "" {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+	}
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, OptimizerTraceOutput{}, err
+		}
+	}
+	if err := requireReferencedSchemasInQuery(sqlText); err != nil {
+		return nil, OptimizerTraceOutput{}, err
+	}
+
+	maxBytes := input.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultOptimizerTraceBytes
+	}
+	if maxBytes > maxOptimizerTraceBytes {
+		maxBytes = maxOptimizerTraceBytes
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	// The trace is per session, so everything runs on one pooled connection
+	// and tracing is switched off again before it is returned to the pool.
+	db := getDB()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	restoreDatabase, err := useDatabase(ctx, db, conn, database)
+	if err != nil {
+		return nil, OptimizerTraceOutput{}, err
+	}
+	defer restoreDatabase()
+
+	restoreSettings, err := useSessionSettings(ctx, conn)
+	if err != nil {
+		return nil, OptimizerTraceOutput{}, err
+	}
+	defer restoreSettings()
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION optimizer_trace = 'enabled=on', optimizer_trace_max_mem_size = %d", maxBytes)); err != nil {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("failed to enable optimizer trace (MySQL 5.6+ / MariaDB 10.4+ required): %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SET SESSION optimizer_trace = 'enabled=off'")
+	}()
+
+	rows, err := conn.QueryContext(ctx, "EXPLAIN "+sqlText)
+	if err != nil {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("EXPLAIN failed: %w", err)
+	}
+	out := OptimizerTraceOutput{Plan: readPlan(rows)}
+	rows.Close()
+
+	var trace sql.NullString
+	var missing int64
+	var insufficient bool
+	err = conn.QueryRowContext(ctx, `SELECT TRACE, MISSING_BYTES_BEYOND_MAX_MEM_SIZE, INSUFFICIENT_PRIVILEGES
+		FROM information_schema.OPTIMIZER_TRACE`).Scan(&trace, &missing, &insufficient)
+	if err == sql.ErrNoRows {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("no optimizer trace was recorded")
+	}
+	if err != nil {
+		return nil, OptimizerTraceOutput{}, fmt.Errorf("failed to read optimizer trace: %w", err)
+	}
+
+	out.MissingBytes = missing
+	out.Truncated = missing > 0
+	out.InsufficientPrivileges = insufficient
+	if insufficient {
+		out.Notes = append(out.Notes, "The trace is empty because the user lacks privileges on a referenced view or routine (SHOW VIEW / SELECT required).")
+	}
+	// A truncated trace is not valid JSON; return it as text in that case.
+	if trace.Valid && trace.String != "" {
+		var parsed interface{}
+		if !out.Truncated && json.Unmarshal([]byte(trace.String), &parsed) == nil {
+			out.Trace = parsed
+		} else {
+			out.TraceText = trace.String
+		}
+	}
+	if out.Truncated {
+		out.Notes = append(out.Notes, fmt.Sprintf("The trace exceeded max_bytes (%d) by %d bytes and was truncated; raise max_bytes to see all of it.", maxBytes, missing))
+	}
+
+	return nil, out, nil
+}
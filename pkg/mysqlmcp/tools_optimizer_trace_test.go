@@ -0,0 +1,87 @@
+// pkg/mysqlmcp/tools_optimizer_trace_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func expectOptimizerTrace(mock sqlmock.Sqlmock, trace string, missing int64) {
+	mock.ExpectExec("SET SESSION optimizer_trace = 'enabled=on', optimizer_trace_max_mem_size = 1048576").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("EXPLAIN SELECT \\* FROM users WHERE id = 1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "table", "type"}).AddRow(1, "users", "const"))
+	mock.ExpectQuery("FROM information_schema.OPTIMIZER_TRACE").
+		WillReturnRows(sqlmock.NewRows([]string{"TRACE", "MISSING_BYTES_BEYOND_MAX_MEM_SIZE", "INSUFFICIENT_PRIVILEGES"}).
+			AddRow(trace, missing, 0))
+	mock.ExpectExec("SET SESSION optimizer_trace = 'enabled=off'").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestToolOptimizerTrace(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+	expectOptimizerTrace(mock, `{"steps": [{"join_preparation": {"select#": 1}}]}`, 0)
+	expectRestoreDatabase(mock)
+
+	_, out, err := toolOptimizerTrace(context.Background(), &mcp.CallToolRequest{}, OptimizerTraceInput{
+		SQL:      "SELECT * FROM users WHERE id = 1",
+		Database: "app",
+	})
+	if err != nil {
+		t.Fatalf("toolOptimizerTrace failed: %v", err)
+	}
+	trace, ok := out.Trace.(map[string]interface{})
+	if !ok || trace["steps"] == nil {
+		t.Fatalf("expected parsed trace, got %#v", out.Trace)
+	}
+	if out.Truncated || out.TraceText != "" || len(out.Plan) != 1 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolOptimizerTraceTruncated(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	expectOptimizerTrace(mock, `{"steps": [{"join_prep`, 4096)
+
+	_, out, err := toolOptimizerTrace(context.Background(), &mcp.CallToolRequest{}, OptimizerTraceInput{
+		SQL: "SELECT * FROM users WHERE id = 1",
+	})
+	if err != nil {
+		t.Fatalf("toolOptimizerTrace failed: %v", err)
+	}
+	if !out.Truncated || out.MissingBytes != 4096 || out.Trace != nil || out.TraceText == "" || len(out.Notes) != 1 {
+		t.Errorf("expected truncated raw trace, got %+v", out)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolOptimizerTraceInvalidInput(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, in := range []OptimizerTraceInput{{}, {SQL: "UPDATE users SET name = 'x'"}} {
+		if _, _, err := toolOptimizerTrace(ctx, &mcp.CallToolRequest{}, in); err == nil {
+			t.Errorf("expected error for %+v", in)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// pkg/mysqlmcp/tools_partitions.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func toolCheckPartitionPruning(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input CheckPartitionPruningInput,
+) (*mcp.CallToolResult, CheckPartitionPruningOutput, error) {
+	sqlText := strings.TrimSpace(input.SQL)
+	if sqlText == "" {
+		return nil, CheckPartitionPruningOutput{}, fmt.Errorf("sql is required")
+	}
+	if !strings.HasPrefix(strings.ToUpper(sqlText), "SELECT") {
+		return nil, CheckPartitionPruningOutput{}, fmt.Errorf("only SELECT statements can be explained")
+	}
+
+	database := strings.TrimSpace(input.Database)
+	if accessControlEnabled() && database == "" {
+		return nil, CheckPartitionPruningOutput{}, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set")
+	}
+	if database != "" {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, CheckPartitionPruningOutput{}, err
+		}
+	}
+	if err := requireReferencedSchemasInQuery(sqlText); err != nil {
+		return nil, CheckPartitionPruningOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	plan, err := runExplain(ctx, database, sqlText)
+	if err != nil {
+		return nil, CheckPartitionPruningOutput{}, err
+	}
+
+	out := CheckPartitionPruningOutput{Tables: []PartitionPruningTable{}, Plan: plan}
+	aliases := util.TableAliases(sqlText)
+	for _, row := range plan {
+		scanned, ok := row["partitions"].(string)
+		if !ok || scanned == "" {
+			continue
+		}
+		ref := fmt.Sprintf("%v", row["table"])
+		schema, table := database, ref
+		if real, ok := aliases[strings.ToLower(ref)]; ok {
+			table = real
+		}
+		if i := strings.LastIndex(table, "."); i >= 0 {
+			schema, table = table[:i], table[i+1:]
+		}
+		if schema == "" {
+			out.Notes = append(out.Notes, fmt.Sprintf("Table '%s': set database to compare against information_schema.PARTITIONS.", ref))
+			continue
+		}
+		if err := requireAllowedDatabase(schema); err != nil {
+			return nil, CheckPartitionPruningOutput{}, err
+		}
+
+		entry, err := loadPartitionLayout(ctx, schema, table)
+		if err != nil {
+			return nil, CheckPartitionPruningOutput{}, err
+		}
+		entry.Alias = ref
+		entry.ScannedPartitions = strings.Split(scanned, ",")
+		entry.Scanned = len(entry.ScannedPartitions)
+		entry.Pruned = entry.Total > 0 && entry.Scanned < entry.Total
+		if !entry.Pruned {
+			out.Notes = append(out.Notes, fmt.Sprintf(
+				"Table '%s': all %d partitions are scanned — filter on the partitioning expression (%s) so MySQL can prune.",
+				entry.Table, entry.Total, entry.Expression,
+			))
+		}
+		out.Tables = append(out.Tables, entry)
+	}
+
+	if len(out.Tables) == 0 && len(out.Notes) == 0 {
+		out.Notes = append(out.Notes, "The plan does not access any partitioned table.")
+	}
+	out.Pruned = len(out.Tables) > 0
+	for _, t := range out.Tables {
+		out.Pruned = out.Pruned && t.Pruned
+	}
+
+	return nil, out, nil
+}
+
+// loadPartitionLayout counts the leaf partitions (subpartitions when the table
+// is subpartitioned, as EXPLAIN reports them) of schema.table.
+func loadPartitionLayout(ctx context.Context, schema, table string) (PartitionPruningTable, error) {
+	entry := PartitionPruningTable{Table: schema + "." + table}
+	rows, err := getDB().QueryContext(ctx, `SELECT PARTITION_METHOD, PARTITION_EXPRESSION, SUBPARTITION_METHOD
+		FROM information_schema.PARTITIONS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL`, schema, table)
+	if err != nil {
+		return entry, fmt.Errorf("failed to read partitions of %s: %w", entry.Table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var method, expr, subMethod sql.NullString
+		if err := rows.Scan(&method, &expr, &subMethod); err != nil {
+			return entry, fmt.Errorf("scan failed: %w", err)
+		}
+		entry.Total++
+		entry.Method = method.String
+		entry.Expression = expr.String
+		entry.Subpartitioned = subMethod.Valid && subMethod.String != ""
+	}
+	if err := rows.Err(); err != nil {
+		return entry, fmt.Errorf("partitions iteration: %w", err)
+	}
+	return entry, nil
+}
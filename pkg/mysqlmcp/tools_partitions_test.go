@@ -0,0 +1,138 @@
+// pkg/mysqlmcp/tools_partitions_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func explainColumns() []string {
+	return []string{"id", "select_type", "table", "partitions", "type", "possible_keys", "key", "key_len", "ref", "rows", "filtered", "Extra"}
+}
+
+func partitionRows(n int) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"PARTITION_METHOD", "PARTITION_EXPRESSION", "SUBPARTITION_METHOD"})
+	for i := 0; i < n; i++ {
+		rows.AddRow("RANGE", "year(`created_at`)", nil)
+	}
+	return rows
+}
+
+func TestToolCheckPartitionPruning(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	query := "SELECT * FROM events e JOIN archive.events_old o ON o.id = e.id JOIN users u ON u.id = e.user_id WHERE e.created_at >= '2024-01-01'"
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("EXPLAIN SELECT").
+		WillReturnRows(sqlmock.NewRows(explainColumns()).
+			AddRow(1, "SIMPLE", "e", "p2024,p2025", "range", "idx_created", "idx_created", 5, nil, 10, 100.0, "Using where").
+			AddRow(1, "SIMPLE", "o", "p0,p1,p2", "eq_ref", "PRIMARY", "PRIMARY", 4, "app.e.id", 1, 100.0, "").
+			AddRow(1, "SIMPLE", "u", nil, "eq_ref", "PRIMARY", "PRIMARY", 4, "app.e.user_id", 1, 100.0, ""))
+	expectRestoreDatabase(mock)
+	mock.ExpectQuery("FROM information_schema.PARTITIONS").
+		WithArgs("app", "events").
+		WillReturnRows(partitionRows(4))
+	mock.ExpectQuery("FROM information_schema.PARTITIONS").
+		WithArgs("archive", "events_old").
+		WillReturnRows(partitionRows(3))
+
+	_, out, err := toolCheckPartitionPruning(context.Background(), &mcp.CallToolRequest{}, CheckPartitionPruningInput{
+		SQL:      query,
+		Database: "app",
+	})
+	if err != nil {
+		t.Fatalf("toolCheckPartitionPruning failed: %v", err)
+	}
+	if len(out.Tables) != 2 || out.Pruned {
+		t.Fatalf("expected 2 tables and overall not pruned, got %+v", out)
+	}
+	events := out.Tables[0]
+	if events.Table != "app.events" || events.Alias != "e" || !events.Pruned || events.Scanned != 2 || events.Total != 4 {
+		t.Errorf("unexpected events entry: %+v", events)
+	}
+	if events.Method != "RANGE" || events.Expression != "year(`created_at`)" {
+		t.Errorf("unexpected layout: %+v", events)
+	}
+	old := out.Tables[1]
+	if old.Table != "archive.events_old" || old.Pruned {
+		t.Errorf("unexpected archive entry: %+v", old)
+	}
+	if len(out.Notes) != 1 || !strings.Contains(out.Notes[0], "archive.events_old") {
+		t.Errorf("expected a note for the unpruned table, got %v", out.Notes)
+	}
+	if len(out.Plan) != 3 {
+		t.Errorf("expected plan rows to be returned, got %d", len(out.Plan))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolCheckPartitionPruningNoPartitionedTables(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	connManager.serverTypes["mock"] = ServerTypeMariaDB
+
+	// MariaDB only reports partitions with EXPLAIN PARTITIONS.
+	mock.ExpectQuery("EXPLAIN PARTITIONS SELECT \\* FROM users").
+		WillReturnRows(sqlmock.NewRows(explainColumns()).
+			AddRow(1, "SIMPLE", "users", nil, "ALL", nil, nil, nil, nil, 100, 100.0, ""))
+
+	_, out, err := toolCheckPartitionPruning(context.Background(), &mcp.CallToolRequest{}, CheckPartitionPruningInput{
+		SQL: "SELECT * FROM users",
+	})
+	if err != nil {
+		t.Fatalf("toolCheckPartitionPruning failed: %v", err)
+	}
+	if out.Pruned || len(out.Tables) != 0 || len(out.Notes) != 1 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolCheckPartitionPruningInvalidInput(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, in := range []CheckPartitionPruningInput{{}, {SQL: "DELETE FROM events"}} {
+		if _, _, err := toolCheckPartitionPruning(ctx, &mcp.CallToolRequest{}, in); err == nil {
+			t.Errorf("expected error for %+v", in)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolExplainQueryIncludesPartitions(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	// Servers that omit the column still get a partitions key.
+	mock.ExpectQuery("EXPLAIN SELECT \\* FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "select_type", "table", "type"}).
+			AddRow(1, "SIMPLE", "users", "ALL"))
+
+	_, out, err := toolExplainQuery(context.Background(), &mcp.CallToolRequest{}, ExplainQueryInput{SQL: "SELECT * FROM users"})
+	if err != nil {
+		t.Fatalf("toolExplainQuery failed: %v", err)
+	}
+	if v, ok := out.Plan[0]["partitions"]; !ok || v != nil {
+		t.Errorf("expected nil partitions key, got %v (present=%v)", v, ok)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+// pkg/mysqlmcp/tools_pii.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// pii_scan defaults and caps.
+const (
+	piiDefaultSample = 1000
+	piiMaxSample     = 10000
+	piiMaxColumns    = 100 // text columns whose values are sampled
+	piiValuePrefix   = 128 // characters of each value read
+)
+
+// PII types reported by pii_scan.
+const (
+	piiEmail      = "email"
+	piiPhone      = "phone"
+	piiCreditCard = "credit_card"
+	piiNationalID = "national_id"
+	piiName       = "person_name"
+	piiAddress    = "address"
+	piiBirthDate  = "birth_date"
+	piiIPAddress  = "ip_address"
+)
+
+// piiTextTypes are the data types whose values pii_scan samples.
+var piiTextTypes = map[string]bool{
+	"char": true, "varchar": true, "tinytext": true, "text": true, "mediumtext": true, "longtext": true,
+}
+
+var (
+	piiEmailRe = regexp.MustCompile(`^[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}$`)
+	// Phone numbers need a leading + or a separator, so bare numeric ids do not match.
+	piiPhoneRe = regexp.MustCompile(`^(\+[\d\s().\-]{7,20}|\(?\d{2,4}\)?[\s.\-]\d{2,4}[\s.\-]\d{2,5}([\s.\-]\d{1,5})?)$`)
+	piiCardRe  = regexp.MustCompile(`^\d(?:[ \-]?\d){12,18}$`)
+	piiSSNRe   = regexp.MustCompile(`^(?:00[1-9]|0[1-9]\d|[1-578]\d{2}|6[0-57-9]\d|66[0-57-9])-(?:0[1-9]|[1-9]\d)-(?:000[1-9]|00[1-9]\d|0[1-9]\d{2}|[1-9]\d{3})$`)
+	piiNINORe  = regexp.MustCompile(`^[A-CEGHJ-PR-TW-Z]{2}\s?\d{2}\s?\d{2}\s?\d{2}\s?[A-D]$`)
+	// Dates and times share the shape of phone numbers.
+	piiDateRe = regexp.MustCompile(`^\d{4}[\-./]\d{1,2}[\-./]\d{1,2}|^\d{1,2}[\-./]\d{1,2}[\-./]\d{4}|^\d{1,2}:\d{2}`)
+)
+
+// piiNameHints map column name fragments to the PII they suggest.
+var piiNameHints = []struct {
+	fragment, piiType string
+}{
+	{"email", piiEmail}, {"e_mail", piiEmail},
+	{"phone", piiPhone}, {"mobile", piiPhone}, {"msisdn", piiPhone},
+	{"card_number", piiCreditCard}, {"cardnumber", piiCreditCard}, {"credit_card", piiCreditCard}, {"pan", piiCreditCard},
+	{"ssn", piiNationalID}, {"social_security", piiNationalID}, {"national_id", piiNationalID},
+	{"passport", piiNationalID}, {"tax_id", piiNationalID}, {"nino", piiNationalID},
+	{"first_name", piiName}, {"last_name", piiName}, {"full_name", piiName}, {"surname", piiName},
+	{"ip_address", piiIPAddress}, {"ip", piiIPAddress},
+	{"address", piiAddress}, {"street", piiAddress}, {"postcode", piiAddress}, {"zip", piiAddress},
+	{"birth", piiBirthDate}, {"dob", piiBirthDate},
+}
+
+// piiValueType returns the PII type a value looks like, or "".
+func piiValueType(v string) string {
+	v = strings.TrimSpace(v)
+	switch {
+	case v == "":
+		return ""
+	case piiEmailRe.MatchString(v):
+		return piiEmail
+	case piiSSNRe.MatchString(v) || piiNINORe.MatchString(strings.ToUpper(v)):
+		return piiNationalID
+	case piiCardRe.MatchString(v) && luhnValid(v):
+		return piiCreditCard
+	case piiPhoneRe.MatchString(v) && !piiDateRe.MatchString(v):
+		if n := countDigits(v); n >= 7 && n <= 15 {
+			return piiPhone
+		}
+	}
+	return ""
+}
+
+// piiNameType returns the PII type a column name suggests, or "".
+func piiNameType(column string) string {
+	lower := strings.ToLower(column)
+	parts := strings.FieldsFunc(lower, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	for _, h := range piiNameHints {
+		// Short hints must be whole name parts: "pan" should not match "company".
+		if len(h.fragment) <= 3 {
+			for _, p := range parts {
+				if p == h.fragment {
+					return h.piiType
+				}
+			}
+			continue
+		}
+		if strings.Contains(lower, h.fragment) {
+			return h.piiType
+		}
+	}
+	return ""
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum used by
+// payment card numbers.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// piiConfidence grades a detection: matching values in most sampled rows is
+// high, in a fair share (or a matching name backed by some values) medium,
+// and a name alone low.
+func piiConfidence(nameMatch bool, ratio float64) string {
+	switch {
+	case ratio >= 0.8 || (nameMatch && ratio >= 0.3):
+		return "high"
+	case ratio >= 0.3 || (nameMatch && ratio > 0):
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// toolPIIScan samples the first sample_size rows of a table and flags
+// columns that look like PII by their values or their names. Sampled values
+// are never returned.
+func toolPIIScan(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input PIIScanInput,
+) (*mcp.CallToolResult, PIIScanOutput, error) {
+	if input.Database == "" || input.Table == "" {
+		return nil, PIIScanOutput{}, fmt.Errorf("database and table are required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, PIIScanOutput{}, err
+	}
+	if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+		return nil, PIIScanOutput{}, err
+	}
+	dbName, err := util.QuoteIdent(input.Database)
+	if err != nil {
+		return nil, PIIScanOutput{}, fmt.Errorf("invalid database name: %w", err)
+	}
+	tableName, err := util.QuoteIdent(input.Table)
+	if err != nil {
+		return nil, PIIScanOutput{}, fmt.Errorf("invalid table name: %w", err)
+	}
+	sample := input.SampleSize
+	if sample <= 0 {
+		sample = piiDefaultSample
+	}
+	if sample > piiMaxSample {
+		sample = piiMaxSample
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	rows, err := getDB().QueryContext(ctx, `SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION`, input.Database, input.Table)
+	if err != nil {
+		return nil, PIIScanOutput{}, fmt.Errorf("column lookup failed: %w", err)
+	}
+	type column struct{ name, dataType string }
+	var columns, text []column
+	textTotal := 0
+	for rows.Next() {
+		var c column
+		if err := rows.Scan(&c.name, &c.dataType); err != nil {
+			rows.Close()
+			return nil, PIIScanOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		c.dataType = strings.ToLower(c.dataType)
+		columns = append(columns, c)
+		if piiTextTypes[c.dataType] {
+			if textTotal++; len(text) < piiMaxColumns {
+				text = append(text, c)
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, PIIScanOutput{}, err
+	}
+	if len(columns) == 0 {
+		return nil, PIIScanOutput{}, fmt.Errorf("table not found: %s.%s", input.Database, input.Table)
+	}
+
+	out := PIIScanOutput{Database: input.Database, Table: input.Table, Columns: []PIIColumn{}}
+	nonNull := make([]int, len(text))
+	matches := make([]map[string]int, len(text))
+	if len(text) > 0 {
+		exprs := make([]string, len(text))
+		for i, c := range text {
+			q, err := util.QuoteIdent(c.name)
+			if err != nil {
+				return nil, PIIScanOutput{}, fmt.Errorf("invalid column name: %w", err)
+			}
+			exprs[i] = fmt.Sprintf("LEFT(%s, %d)", q, piiValuePrefix)
+			matches[i] = map[string]int{}
+		}
+		rows, err := getDB().QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s.%s LIMIT %d",
+			strings.Join(exprs, ", "), dbName, tableName, sample))
+		if err != nil {
+			return nil, PIIScanOutput{}, fmt.Errorf("sample query failed: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			values, err := scanAndNormalizeRow(rows, len(text))
+			if err != nil {
+				return nil, PIIScanOutput{}, err
+			}
+			out.SampledRows++
+			for i, v := range values {
+				if v == nil {
+					continue
+				}
+				nonNull[i]++
+				if t := piiValueType(fmt.Sprint(v)); t != "" {
+					matches[i][t]++
+				}
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, PIIScanOutput{}, err
+		}
+	}
+	if len(text) < textTotal {
+		out.Notes = append(out.Notes, fmt.Sprintf("Only the first %d text columns were sampled.", piiMaxColumns))
+	}
+
+	sampledAt := map[string]int{}
+	for i, c := range text {
+		sampledAt[c.name] = i
+	}
+	for _, c := range columns {
+		nameType := piiNameType(c.name)
+		var valueType string
+		var matched, sampled int
+		if i, ok := sampledAt[c.name]; ok {
+			sampled = nonNull[i]
+			for t, n := range matches[i] {
+				if n > matched || (n == matched && t < valueType) {
+					valueType, matched = t, n
+				}
+			}
+		}
+		var ratio float64
+		if sampled > 0 {
+			ratio = float64(matched) / float64(sampled)
+		}
+		// A few stray matches in a free-text column are not enough on their own.
+		if valueType != "" && ratio < 0.3 && nameType != valueType {
+			valueType, matched, ratio = "", 0, 0
+		}
+		if valueType == "" && nameType == "" {
+			continue
+		}
+		col := PIIColumn{
+			Column:        c.name,
+			DataType:      c.dataType,
+			Type:          valueType,
+			NameMatch:     nameType != "",
+			MatchedValues: matched,
+			SampledValues: sampled,
+			MatchRatio:    ratio,
+		}
+		if col.Type == "" {
+			col.Type = nameType
+		}
+		col.Confidence = piiConfidence(nameType == col.Type && nameType != "", ratio)
+		out.Columns = append(out.Columns, col)
+	}
+	if out.SampledRows >= int64(sample) {
+		out.Notes = append(out.Notes, fmt.Sprintf("Only the first %d rows were sampled; PII in later rows is not detected.", sample))
+	}
+	return nil, out, nil
+}
+
+// piiResultColumns returns the columns of a result that match the configured
+// PII patterns (MYSQL_MCP_PII_COLUMNS) and are neither masked nor pseudonymized.
+func piiResultColumns(cols []string) []string {
+	if cfg == nil || len(cfg.PIIColumns) == 0 {
+		return nil
+	}
+	hidden := maskedColumns(cols, append(append([]string{}, cfg.MaskColumns...), cfg.PseudonymizeColumns...))
+	matched := maskedColumns(cols, cfg.PIIColumns)
+	var pii []string
+	for i, col := range cols {
+		if matched[i] && !hidden[i] {
+			pii = append(pii, col)
+		}
+	}
+	return pii
+}
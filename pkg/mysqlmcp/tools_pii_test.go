@@ -0,0 +1,120 @@
+// pkg/mysqlmcp/tools_pii_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPIIValueType(t *testing.T) {
+	tests := map[string]string{
+		"jane.doe@example.com": piiEmail,
+		"+44 20 7946 0958":     piiPhone,
+		"(415) 555-2671":       piiPhone,
+		"4111 1111 1111 1111":  piiCreditCard,
+		"4111111111111112":     "", // fails the Luhn check
+		"123-45-6789":          piiNationalID,
+		"15/01/2024":           "",
+		"AB 12 34 56 C":        piiNationalID,
+		"1234567":              "", // a bare number is not a phone
+		"2024-01-15":           "",
+		"hello world":          "",
+	}
+	for v, want := range tests {
+		if got := piiValueType(v); got != want {
+			t.Errorf("piiValueType(%q) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestPIINameType(t *testing.T) {
+	tests := map[string]string{
+		"email_address": piiEmail,
+		"MobilePhone":   piiPhone,
+		"pan":           piiCreditCard,
+		"company":       "",
+		"first_name":    piiName,
+		"date_of_birth": piiBirthDate,
+		"client_ip":     piiIPAddress,
+		"zip":           piiAddress,
+		"zipper_size":   "",
+		"order_total":   "",
+	}
+	for col, want := range tests {
+		if got := piiNameType(col); got != want {
+			t.Errorf("piiNameType(%q) = %q, want %q", col, got, want)
+		}
+	}
+}
+
+func TestToolPIIScan(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS").
+		WithArgs("shop", "customers").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE"}).
+			AddRow("id", "BIGINT").
+			AddRow("contact", "VARCHAR").
+			AddRow("notes", "TEXT").
+			AddRow("last_name", "VARCHAR").
+			AddRow("phone", "VARCHAR"))
+	mock.ExpectQuery("SELECT LEFT\\(`contact`, 128\\), LEFT\\(`notes`, 128\\), LEFT\\(`last_name`, 128\\), LEFT\\(`phone`, 128\\) FROM `shop`.`customers` LIMIT 3").
+		WillReturnRows(sqlmock.NewRows([]string{"a", "b", "c", "d"}).
+			AddRow("a@example.com", "call me", "Doe", "n/a").
+			AddRow("b@example.com", "mail a@example.com", "Roe", "+1 415 555 2671").
+			AddRow("c@example.com", nil, "Poe", nil))
+
+	_, out, err := toolPIIScan(context.Background(), &mcp.CallToolRequest{}, PIIScanInput{Database: "shop", Table: "customers", SampleSize: 3})
+	if err != nil {
+		t.Fatalf("pii_scan: %v", err)
+	}
+	if out.SampledRows != 3 || len(out.Notes) != 1 {
+		t.Errorf("unexpected sample info: %d rows, notes %v", out.SampledRows, out.Notes)
+	}
+	got := map[string]PIIColumn{}
+	for _, c := range out.Columns {
+		got[c.Column] = c
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected contact, last_name and phone, got %+v", out.Columns)
+	}
+	if c := got["contact"]; c.Type != piiEmail || c.Confidence != "high" || c.MatchRatio != 1 || c.NameMatch {
+		t.Errorf("unexpected contact detection: %+v", c)
+	}
+	if c := got["last_name"]; c.Type != piiName || c.Confidence != "low" || !c.NameMatch {
+		t.Errorf("unexpected last_name detection: %+v", c)
+	}
+	if c := got["phone"]; c.Type != piiPhone || c.Confidence != "high" || c.MatchedValues != 1 || c.SampledValues != 2 {
+		t.Errorf("unexpected phone detection: %+v", c)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolRunQueryFlagsPIIColumns(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg := cfg
+	defer func() { cfg = oldCfg }()
+	cfg = &config.Config{PIIColumns: []string{"email", "phone", "ssn"}, MaskColumns: []string{"ssn"}}
+
+	mock.ExpectQuery("SELECT id, email, phone, ssn FROM users").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "email", "phone", "ssn"}).AddRow(1, "a@example.com", "555-0100", "123-45-6789"))
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id, email, phone, ssn FROM users"})
+	if err != nil {
+		t.Fatalf("run_query: %v", err)
+	}
+	if strings.Join(out.PIIColumns, ",") != "email,phone" {
+		t.Errorf("expected email and phone flagged (ssn is masked), got %v", out.PIIColumns)
+	}
+	if !strings.Contains(out.Warning, "likely PII in columns: email, phone") {
+		t.Errorf("expected a PII warning, got %q", out.Warning)
+	}
+}
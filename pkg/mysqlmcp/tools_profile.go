@@ -0,0 +1,203 @@
+// pkg/mysqlmcp/tools_profile.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// profile_column defaults and caps.
+const (
+	profileDefaultSample = 100000
+	profileMaxSample     = 1000000
+	profileDefaultTopK   = 10
+	profileMaxTopK       = 100
+	profileMaxMonths     = 600
+)
+
+// profileTemporalTypes get a per-month distribution.
+var profileTemporalTypes = map[string]bool{"date": true, "datetime": true, "timestamp": true}
+
+// profileUnorderedTypes have no meaningful MIN/MAX or grouping.
+var profileUnorderedTypes = map[string]bool{
+	"json": true, "geometry": true, "point": true, "linestring": true, "polygon": true,
+	"multipoint": true, "multilinestring": true, "multipolygon": true, "geometrycollection": true,
+	"tinyblob": true, "blob": true, "mediumblob": true, "longblob": true, "vector": true,
+}
+
+// toolProfileColumn summarizes one column in at most five queries: row/null
+// counts and min/max over the table, distinct count and top values over a
+// sample of rows, and a per-month histogram for temporal columns.
+func toolProfileColumn(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ProfileColumnInput,
+) (*mcp.CallToolResult, ProfileColumnOutput, error) {
+	if input.Database == "" || input.Table == "" || input.Column == "" {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("database, table and column are required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, ProfileColumnOutput{}, err
+	}
+	if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+		return nil, ProfileColumnOutput{}, err
+	}
+	dbName, err := util.QuoteIdent(input.Database)
+	if err != nil {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("invalid database name: %w", err)
+	}
+	tableName, err := util.QuoteIdent(input.Table)
+	if err != nil {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("invalid table name: %w", err)
+	}
+	colName, err := util.QuoteIdent(input.Column)
+	if err != nil {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("invalid column name: %w", err)
+	}
+
+	sample := input.SampleSize
+	if sample <= 0 {
+		sample = profileDefaultSample
+	}
+	if sample > profileMaxSample {
+		sample = profileMaxSample
+	}
+	topK := input.TopK
+	if topK <= 0 {
+		topK = profileDefaultTopK
+	}
+	if topK > profileMaxTopK {
+		topK = profileMaxTopK
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	out := ProfileColumnOutput{
+		Database:  input.Database,
+		Table:     input.Table,
+		Column:    input.Column,
+		TopValues: []ProfileValueCount{},
+	}
+	var dataType string
+	err = getDB().QueryRowContext(ctx, `SELECT DATA_TYPE FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?`,
+		input.Database, input.Table, input.Column).Scan(&dataType)
+	if err == sql.ErrNoRows {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("column not found: %s.%s.%s", input.Database, input.Table, input.Column)
+	}
+	if err != nil {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("column lookup failed: %w", err)
+	}
+	out.DataType = strings.ToLower(dataType)
+	ordered := !profileUnorderedTypes[out.DataType]
+	// Values of masked and pseudonymized columns stay hidden: only counts are
+	// reported.
+	masked := withheldColumn(input.Column)
+	from := dbName + "." + tableName
+
+	// Counts and range over the whole table.
+	if ordered && !masked {
+		var minV, maxV interface{}
+		err = getDB().QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*), COUNT(%s), MIN(%s), MAX(%s) FROM %s",
+			colName, colName, colName, from)).Scan(&out.RowCount, &out.NonNullCount, &minV, &maxV)
+		out.Min = util.NormalizeValue(minV)
+		out.Max = util.NormalizeValue(maxV)
+	} else {
+		err = getDB().QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*), COUNT(%s) FROM %s",
+			colName, from)).Scan(&out.RowCount, &out.NonNullCount)
+		if masked {
+			out.Notes = append(out.Notes, "The column is masked or pseudonymized (MYSQL_MCP_MASK_COLUMNS, MYSQL_MCP_PSEUDONYMIZE_COLUMNS); min, max, top values and the monthly distribution are omitted.")
+		} else {
+			out.Notes = append(out.Notes, fmt.Sprintf("%s columns are not ordered; min, max and top values are omitted.", out.DataType))
+		}
+	}
+	if err != nil {
+		return nil, ProfileColumnOutput{}, fmt.Errorf("profile query failed: %w", err)
+	}
+	out.NullCount = out.RowCount - out.NonNullCount
+	if out.RowCount > 0 {
+		out.NullFraction = float64(out.NullCount) / float64(out.RowCount)
+	}
+
+	// Distinct count over the first sample rows; exact when the sample covers
+	// the table.
+	sampled := fmt.Sprintf("SELECT %s AS v FROM %s LIMIT %d", colName, from, sample)
+	if ordered {
+		var sampleNonNull int64
+		err = getDB().QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*), COUNT(v), COUNT(DISTINCT v) FROM (%s) s", sampled)).
+			Scan(&out.SampleRows, &sampleNonNull, &out.DistinctCount)
+		if err != nil {
+			return nil, ProfileColumnOutput{}, fmt.Errorf("distinct query failed: %w", err)
+		}
+		out.DistinctApprox = out.SampleRows < out.RowCount
+		if sampleNonNull > 0 {
+			out.DistinctRatio = float64(out.DistinctCount) / float64(sampleNonNull)
+		}
+	}
+	if ordered && !masked {
+
+		rows, err := getDB().QueryContext(ctx, fmt.Sprintf(
+			"SELECT v, COUNT(*) AS n FROM (%s) s WHERE v IS NOT NULL GROUP BY v ORDER BY n DESC, v LIMIT %d", sampled, topK))
+		if err != nil {
+			return nil, ProfileColumnOutput{}, fmt.Errorf("top values query failed: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var v interface{}
+			var n int64
+			if err := rows.Scan(&v, &n); err != nil {
+				return nil, ProfileColumnOutput{}, fmt.Errorf("scan failed: %w", err)
+			}
+			out.TopValues = append(out.TopValues, ProfileValueCount{Value: util.NormalizeValue(v), Count: n})
+		}
+		if err := rows.Err(); err != nil {
+			return nil, ProfileColumnOutput{}, err
+		}
+	}
+
+	if profileTemporalTypes[out.DataType] && !masked {
+		months, truncated, err := profileMonths(ctx, colName, from)
+		if err != nil {
+			return nil, ProfileColumnOutput{}, err
+		}
+		out.Months = months
+		out.MonthsTruncated = truncated
+	}
+
+	return nil, out, nil
+}
+
+// profileMonths counts non-NULL values per calendar month over the table.
+func profileMonths(ctx context.Context, colName, from string) ([]ProfileMonthCount, bool, error) {
+	rows, err := getDB().QueryContext(ctx, fmt.Sprintf(
+		"SELECT DATE_FORMAT(%s, '%%Y-%%m') AS month, COUNT(*) FROM %s WHERE %s IS NOT NULL GROUP BY month ORDER BY month LIMIT %d",
+		colName, from, colName, profileMaxMonths+1))
+	if err != nil {
+		return nil, false, fmt.Errorf("monthly distribution query failed: %w", err)
+	}
+	defer rows.Close()
+
+	months := []ProfileMonthCount{}
+	truncated := false
+	for rows.Next() {
+		var m ProfileMonthCount
+		if err := rows.Scan(&m.Month, &m.Count); err != nil {
+			return nil, false, fmt.Errorf("scan failed: %w", err)
+		}
+		if len(months) == profileMaxMonths {
+			truncated = true
+			break
+		}
+		months = append(months, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return months, truncated, nil
+}
@@ -0,0 +1,128 @@
+// pkg/mysqlmcp/tools_profile_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolProfileColumnTemporal(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT DATA_TYPE FROM information_schema.COLUMNS").
+		WithArgs("shop", "orders", "created_at").
+		WillReturnRows(sqlmock.NewRows([]string{"DATA_TYPE"}).AddRow("DATETIME"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(`created_at`\\), MIN\\(`created_at`\\), MAX\\(`created_at`\\) FROM `shop`.`orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"c", "nn", "min", "max"}).
+			AddRow(200, 150, "2024-01-03 10:00:00", "2024-03-28 17:30:00"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(v\\), COUNT\\(DISTINCT v\\) FROM \\(SELECT `created_at` AS v FROM `shop`.`orders` LIMIT 50\\) s").
+		WillReturnRows(sqlmock.NewRows([]string{"c", "nn", "d"}).AddRow(50, 40, 38))
+	mock.ExpectQuery("SELECT v, COUNT\\(\\*\\) AS n FROM \\(SELECT `created_at` AS v FROM `shop`.`orders` LIMIT 50\\) s WHERE v IS NOT NULL GROUP BY v ORDER BY n DESC, v LIMIT 2").
+		WillReturnRows(sqlmock.NewRows([]string{"v", "n"}).
+			AddRow("2024-02-01 00:00:00", 2).
+			AddRow("2024-01-03 10:00:00", 1))
+	mock.ExpectQuery("SELECT DATE_FORMAT\\(`created_at`, '%Y-%m'\\) AS month, COUNT\\(\\*\\) FROM `shop`.`orders` WHERE `created_at` IS NOT NULL GROUP BY month ORDER BY month LIMIT 601").
+		WillReturnRows(sqlmock.NewRows([]string{"month", "n"}).
+			AddRow("2024-01", 60).
+			AddRow("2024-02", 50).
+			AddRow("2024-03", 40))
+
+	_, out, err := toolProfileColumn(context.Background(), &mcp.CallToolRequest{}, ProfileColumnInput{
+		Database: "shop", Table: "orders", Column: "created_at", TopK: 2, SampleSize: 50,
+	})
+	if err != nil {
+		t.Fatalf("toolProfileColumn failed: %v", err)
+	}
+	if out.DataType != "datetime" || out.RowCount != 200 || out.NullCount != 50 || out.NullFraction != 0.25 {
+		t.Errorf("unexpected counts: %+v", out)
+	}
+	if out.Min != "2024-01-03 10:00:00" || out.Max != "2024-03-28 17:30:00" {
+		t.Errorf("unexpected range: %v - %v", out.Min, out.Max)
+	}
+	if out.DistinctCount != 38 || !out.DistinctApprox || out.DistinctRatio != 0.95 {
+		t.Errorf("unexpected distinct stats: %+v", out)
+	}
+	if len(out.TopValues) != 2 || out.TopValues[0].Count != 2 {
+		t.Errorf("unexpected top values: %+v", out.TopValues)
+	}
+	if len(out.Months) != 3 || out.Months[0].Month != "2024-01" || out.MonthsTruncated {
+		t.Errorf("unexpected months: %+v", out.Months)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolProfileColumnUnordered(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT DATA_TYPE FROM information_schema.COLUMNS").
+		WillReturnRows(sqlmock.NewRows([]string{"DATA_TYPE"}).AddRow("json"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(`attrs`\\) FROM `shop`.`orders`").
+		WillReturnRows(sqlmock.NewRows([]string{"c", "nn"}).AddRow(10, 10))
+
+	_, out, err := toolProfileColumn(context.Background(), &mcp.CallToolRequest{}, ProfileColumnInput{Database: "shop", Table: "orders", Column: "attrs"})
+	if err != nil {
+		t.Fatalf("toolProfileColumn failed: %v", err)
+	}
+	if out.NullCount != 0 || out.Min != nil || len(out.TopValues) != 0 || len(out.Notes) != 1 {
+		t.Errorf("unexpected profile: %+v", out)
+	}
+
+	mock.ExpectQuery("SELECT DATA_TYPE FROM information_schema.COLUMNS").
+		WillReturnRows(sqlmock.NewRows([]string{"DATA_TYPE"}))
+	if _, _, err := toolProfileColumn(context.Background(), &mcp.CallToolRequest{}, ProfileColumnInput{Database: "shop", Table: "orders", Column: "nope"}); err == nil {
+		t.Error("expected error for a missing column")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolProfileColumnWithheld(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	oldCfg := cfg
+	cfg = &config.Config{MaskColumns: []string{"ssn"}, PseudonymizeColumns: []string{"email"}}
+	defer func() { cfg = oldCfg }()
+
+	mock.ExpectQuery("SELECT DATA_TYPE FROM information_schema.COLUMNS").
+		WillReturnRows(sqlmock.NewRows([]string{"DATA_TYPE"}).AddRow("varchar"))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(`email`\\) FROM `shop`.`users`$").
+		WillReturnRows(sqlmock.NewRows([]string{"c", "nn"}).AddRow(10, 8))
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\), COUNT\\(v\\), COUNT\\(DISTINCT v\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"c", "nn", "d"}).AddRow(10, 8, 8))
+
+	_, out, err := toolProfileColumn(context.Background(), &mcp.CallToolRequest{}, ProfileColumnInput{Database: "shop", Table: "users", Column: "email"})
+	if err != nil {
+		t.Fatalf("toolProfileColumn failed: %v", err)
+	}
+	if out.NullCount != 2 || out.DistinctCount != 8 || out.Min != nil || out.Max != nil || len(out.TopValues) != 0 || len(out.Notes) != 1 {
+		t.Errorf("expected counts only for a pseudonymized column, got %+v", out)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPProfileColumnInvalidParams(t *testing.T) {
+	_, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profile?database=shop&table=orders&column=id&top_k=x", nil)
+	w := httptest.NewRecorder()
+	httpProfileColumn(w, req)
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Result().StatusCode)
+	}
+}
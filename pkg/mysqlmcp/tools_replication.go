@@ -0,0 +1,185 @@
+// pkg/mysqlmcp/tools_replication.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// binlogVariables are the global variables binlog_status reports. Names that
+// do not exist on the server (MySQL vs MariaDB GTID variables) are skipped.
+var binlogVariables = []string{
+	"log_bin", "log_bin_basename", "binlog_format", "binlog_row_image", "sync_binlog", "max_binlog_size",
+	"binlog_expire_logs_seconds", "binlog_expire_logs_auto_purge", "expire_logs_days",
+	"gtid_mode", "enforce_gtid_consistency", "gtid_executed", "gtid_purged",
+	"gtid_binlog_pos", "gtid_current_pos", "gtid_strict_mode", "server_id", "server_uuid",
+}
+
+const defaultBinlogFiles = 50
+
+func toolBinlogStatus(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input BinlogStatusInput,
+) (*mcp.CallToolResult, BinlogStatusOutput, error) {
+	limit := input.MaxFiles
+	if limit <= 0 {
+		limit = defaultBinlogFiles
+	}
+	if limit > maxRows {
+		limit = maxRows
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	vars, err := fetchGlobalVariables(ctx, binlogVariables)
+	if err != nil {
+		return nil, BinlogStatusOutput{}, fmt.Errorf("failed to read binlog variables: %w", err)
+	}
+
+	out := BinlogStatusOutput{
+		Enabled:      strings.EqualFold(vars["log_bin"], "ON") || vars["log_bin"] == "1",
+		Format:       vars["binlog_format"],
+		RowImage:     vars["binlog_row_image"],
+		GTIDMode:     vars["gtid_mode"],
+		GTIDExecuted: vars["gtid_executed"],
+		GTIDPurged:   vars["gtid_purged"],
+		Variables:    vars,
+		Files:        []BinaryLogFile{},
+	}
+	// MariaDB tracks GTIDs with its own domain-server-sequence positions.
+	if out.GTIDExecuted == "" {
+		out.GTIDExecuted = vars["gtid_binlog_pos"]
+	}
+	if secs, err := strconv.ParseInt(vars["binlog_expire_logs_seconds"], 10, 64); err == nil && secs > 0 {
+		out.RetentionSeconds = secs
+	} else if days, err := strconv.ParseFloat(vars["expire_logs_days"], 64); err == nil && days > 0 {
+		out.RetentionSeconds = int64(days * 86400)
+	}
+
+	if !out.Enabled {
+		out.Notes = append(out.Notes, "binary logging is disabled (log_bin=OFF)")
+		return nil, out, nil
+	}
+
+	if err := readBinaryLogs(ctx, &out, limit); err != nil {
+		out.Notes = append(out.Notes, fmt.Sprintf("binary log list unavailable (need REPLICATION CLIENT): %v", err))
+	}
+	if err := readCurrentBinlogPosition(ctx, &out); err != nil {
+		out.Notes = append(out.Notes, fmt.Sprintf("current binlog position unavailable: %v", err))
+	}
+
+	return nil, out, nil
+}
+
+// fetchGlobalVariables returns the named global variables that exist on the server.
+func fetchGlobalVariables(ctx context.Context, names []string) (map[string]string, error) {
+	rows, err := getDB().QueryContext(ctx,
+		"SHOW GLOBAL VARIABLES WHERE Variable_name IN ("+placeholders(len(names))+")", stringsToArgs(names)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vars := make(map[string]string, len(names))
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, err
+		}
+		vars[strings.ToLower(name)] = value.String
+	}
+	return vars, rows.Err()
+}
+
+// readBinaryLogs fills the newest limit files from SHOW BINARY LOGS plus totals
+// across all files.
+func readBinaryLogs(ctx context.Context, out *BinlogStatusOutput, limit int) error {
+	rows, err := getDB().QueryContext(ctx, "SHOW BINARY LOGS")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	var files []BinaryLogFile
+	for rows.Next() {
+		raw := make([]sql.NullString, len(cols))
+		ptrs := make([]interface{}, len(cols))
+		for i := range raw {
+			ptrs[i] = &raw[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		var f BinaryLogFile
+		for i, c := range cols {
+			switch strings.ToLower(c) {
+			case "log_name":
+				f.Name = raw[i].String
+			case "file_size":
+				f.SizeBytes, _ = strconv.ParseInt(raw[i].String, 10, 64)
+			case "encrypted":
+				f.Encrypted = strings.EqualFold(raw[i].String, "Yes")
+			}
+		}
+		out.TotalSizeBytes += f.SizeBytes
+		files = append(files, f)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	out.FileCount = len(files)
+	if len(files) > limit {
+		files = files[len(files)-limit:]
+		out.Truncated = true
+	}
+	out.Files = files
+	return nil
+}
+
+// readCurrentBinlogPosition reads the file/position being written, using
+// SHOW BINARY LOG STATUS (MySQL 8.4+) with a SHOW MASTER STATUS fallback.
+func readCurrentBinlogPosition(ctx context.Context, out *BinlogStatusOutput) error {
+	rows, err := getDB().QueryContext(ctx, "SHOW BINARY LOG STATUS")
+	if err != nil {
+		rows, err = getDB().QueryContext(ctx, "SHOW MASTER STATUS")
+		if err != nil {
+			return err
+		}
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil || !rows.Next() {
+		return err
+	}
+	raw := make([]sql.NullString, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range raw {
+		ptrs[i] = &raw[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	for i, c := range cols {
+		switch strings.ToLower(c) {
+		case "file":
+			out.CurrentFile = raw[i].String
+		case "position":
+			out.CurrentPosition, _ = strconv.ParseInt(raw[i].String, 10, 64)
+		}
+	}
+	return nil
+}
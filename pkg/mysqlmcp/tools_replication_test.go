@@ -0,0 +1,98 @@
+// pkg/mysqlmcp/tools_replication_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolBinlogStatus(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SHOW GLOBAL VARIABLES WHERE Variable_name IN").
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("log_bin", "ON").
+			AddRow("binlog_format", "ROW").
+			AddRow("binlog_row_image", "FULL").
+			AddRow("binlog_expire_logs_seconds", "604800").
+			AddRow("gtid_mode", "ON").
+			AddRow("gtid_executed", "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-77").
+			AddRow("gtid_purged", ""))
+	mock.ExpectQuery("SHOW BINARY LOGS").
+		WillReturnRows(sqlmock.NewRows([]string{"Log_name", "File_size", "Encrypted"}).
+			AddRow("binlog.000001", 1000, "No").
+			AddRow("binlog.000002", 2000, "No").
+			AddRow("binlog.000003", 500, "Yes"))
+	mock.ExpectQuery("SHOW BINARY LOG STATUS").WillReturnError(errors.New("syntax error"))
+	mock.ExpectQuery("SHOW MASTER STATUS").
+		WillReturnRows(sqlmock.NewRows([]string{"File", "Position", "Binlog_Do_DB", "Binlog_Ignore_DB", "Executed_Gtid_Set"}).
+			AddRow("binlog.000003", 500, "", "", "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-77"))
+
+	_, out, err := toolBinlogStatus(context.Background(), &mcp.CallToolRequest{}, BinlogStatusInput{MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("toolBinlogStatus failed: %v", err)
+	}
+	if !out.Enabled || out.Format != "ROW" || out.GTIDMode != "ON" || out.RetentionSeconds != 604800 {
+		t.Errorf("unexpected variables: %+v", out)
+	}
+	if out.FileCount != 3 || out.TotalSizeBytes != 3500 || !out.Truncated || len(out.Files) != 2 {
+		t.Errorf("unexpected file summary: %+v", out)
+	}
+	if out.Files[0].Name != "binlog.000002" || !out.Files[1].Encrypted {
+		t.Errorf("expected newest files, got %+v", out.Files)
+	}
+	if out.CurrentFile != "binlog.000003" || out.CurrentPosition != 500 {
+		t.Errorf("unexpected position %s:%d", out.CurrentFile, out.CurrentPosition)
+	}
+	if len(out.Notes) != 0 {
+		t.Errorf("unexpected notes: %v", out.Notes)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolBinlogStatusDisabledMariaDB(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SHOW GLOBAL VARIABLES WHERE Variable_name IN").
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("log_bin", "OFF").
+			AddRow("expire_logs_days", "2.000000").
+			AddRow("gtid_binlog_pos", "0-1-42"))
+
+	_, out, err := toolBinlogStatus(context.Background(), &mcp.CallToolRequest{}, BinlogStatusInput{})
+	if err != nil {
+		t.Fatalf("toolBinlogStatus failed: %v", err)
+	}
+	if out.Enabled || out.GTIDExecuted != "0-1-42" || out.RetentionSeconds != 172800 || len(out.Notes) != 1 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPBinlogStatusInvalidMaxFiles(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/binlog?max_files=x", nil)
+	w := httptest.NewRecorder()
+
+	httpBinlogStatus(w, req)
+
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Result().StatusCode)
+	}
+}
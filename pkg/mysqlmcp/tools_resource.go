@@ -0,0 +1,175 @@
+// pkg/mysqlmcp/tools_resource.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resource_usage answers capacity questions ("what is using the memory?",
+// "are sorts and temporary tables going to disk?") without leaving the agent
+// to decode raw SHOW STATUS keys: the largest performance_schema memory
+// consumers, the temporary table, sort and join counters with their meaning
+// and rate since startup, and checks that flag the spill ratios against
+// fixed thresholds the way health_report does.
+
+const (
+	defaultTopMemory = 10
+	maxTopMemory     = 50
+)
+
+// resourceCounters are the status counters resource_usage reports, in order,
+// with what each one counts.
+var resourceCounters = []struct{ name, meaning string }{
+	{"Created_tmp_tables", "internal temporary tables created (GROUP BY, DISTINCT, UNION, derived tables)"},
+	{"Created_tmp_disk_tables", "internal temporary tables that did not fit in memory (tmp_table_size / max_heap_table_size, or TempTable limits) and went to disk"},
+	{"Created_tmp_files", "temporary files created, e.g. by sorts that spilled past sort_buffer_size"},
+	{"Sort_merge_passes", "merge passes sorts needed because their rows did not fit in sort_buffer_size"},
+	{"Sort_range", "sorts done using ranges"},
+	{"Sort_scan", "sorts done by scanning the table"},
+	{"Sort_rows", "rows sorted"},
+	{"Select_full_join", "joins that read a table without a usable index, relying on the join buffer"},
+	{"Select_full_range_join", "joins that used a range search on a reference table"},
+	{"Select_range_check", "joins without keys that check key usage after each row"},
+	{"Select_scan", "joins that did a full scan of the first table"},
+}
+
+func toolResourceUsage(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ResourceUsageInput,
+) (*mcp.CallToolResult, ResourceUsageOutput, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	top := input.TopMemory
+	if top <= 0 {
+		top = defaultTopMemory
+	}
+	if top > maxTopMemory {
+		top = maxTopMemory
+	}
+
+	names := []string{"Uptime"}
+	for _, c := range resourceCounters {
+		names = append(names, c.name)
+	}
+	st, err := fetchGlobalStatus(ctx, getDB(), names)
+	if err != nil {
+		return nil, ResourceUsageOutput{}, fmt.Errorf("failed to get server status: %w", err)
+	}
+
+	out := ResourceUsageOutput{UptimeSeconds: st["uptime"], Counters: []ResourceCounter{}, Checks: []HealthCheck{}}
+	for _, c := range resourceCounters {
+		v, ok := st[strings.ToLower(c.name)]
+		if !ok {
+			continue
+		}
+		rc := ResourceCounter{Name: c.name, Value: v, Meaning: c.meaning}
+		if out.UptimeSeconds > 0 {
+			rc.PerSecond = float64(v) / float64(out.UptimeSeconds)
+		}
+		out.Counters = append(out.Counters, rc)
+	}
+	out.Checks = append(out.Checks, resourceChecks(st, out.UptimeSeconds)...)
+	if out.UptimeSeconds < 600 {
+		out.Notes = append(out.Notes, "The server restarted less than 10 minutes ago; rates and ratios are not yet representative.")
+	}
+	out.Notes = append(out.Notes, "Counters and rates cover the time since startup; metrics_history shows recent rates when the sampler is on.")
+
+	total, consumers, err := memoryConsumers(ctx, getDB(), top)
+	if err != nil {
+		out.Notes = append(out.Notes, fmt.Sprintf("memory instrumentation unavailable (needs performance_schema and SELECT on it): %v", err))
+	} else {
+		out.MemoryTotalBytes = total
+		out.TopMemory = consumers
+		if len(consumers) == 0 {
+			out.Notes = append(out.Notes, "No memory is instrumented; enable the memory/% instruments in performance_schema.setup_instruments.")
+		}
+	}
+
+	out.Status = severityOK
+	for _, c := range out.Checks {
+		if severityRank[c.Severity] > severityRank[out.Status] {
+			out.Status = c.Severity
+		}
+	}
+	return nil, out, nil
+}
+
+// resourceChecks flags the spill ratios of st. Each message names the
+// thresholds it was held against.
+func resourceChecks(st map[string]int64, uptime int64) []HealthCheck {
+	var checks []HealthCheck
+
+	if tmp := st["created_tmp_tables"]; tmp > 0 {
+		pct := 100.0 * float64(st["created_tmp_disk_tables"]) / float64(tmp)
+		checks = append(checks, healthCheck("tmp_disk_tables", severityAtLeast(pct, 25, 50), pct, "percent",
+			fmt.Sprintf("%.1f%% of %d internal temporary tables went to disk (warning at 25%%, critical at 50%%)", pct, tmp)))
+	} else {
+		checks = append(checks, healthCheck("tmp_disk_tables", severityOK, 0, "percent", "no internal temporary tables created"))
+	}
+
+	if sorts := st["sort_scan"] + st["sort_range"]; sorts > 0 {
+		pct := 100.0 * float64(st["sort_merge_passes"]) / float64(sorts)
+		checks = append(checks, healthCheck("sort_merge_passes", severityAtLeast(pct, 10, 25), pct, "percent",
+			fmt.Sprintf("%d merge passes for %d sorts (%.1f%%): sorts spilling past sort_buffer_size (warning at 10%%, critical at 25%%)",
+				st["sort_merge_passes"], sorts, pct)))
+	} else {
+		checks = append(checks, HealthCheck{Name: "sort_merge_passes", Severity: severityUnknown, Message: "no sorts recorded"})
+	}
+
+	if uptime > 0 {
+		perMin := float64(st["select_full_join"]) * 60 / float64(uptime)
+		checks = append(checks, healthCheck("full_joins", severityAtLeast(perMin, 1, 10), perMin, "per_minute",
+			fmt.Sprintf("%.2f joins per minute without a usable index, each reading through the join buffer (warning at 1, critical at 10)", perMin)))
+	} else {
+		checks = append(checks, HealthCheck{Name: "full_joins", Severity: severityUnknown, Message: "uptime unavailable"})
+	}
+	return checks
+}
+
+// memoryConsumers returns the total instrumented memory and the top event
+// names by current allocation, from the sys schema when it is installed and
+// from performance_schema otherwise.
+func memoryConsumers(ctx context.Context, db *sql.DB, limit int) (*int64, []MemoryConsumer, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT event_name, current_count, current_alloc, high_alloc
+		FROM sys.x$memory_global_by_current_bytes
+		LIMIT ?`, limit)
+	if err != nil {
+		rows, err = db.QueryContext(ctx, `
+			SELECT EVENT_NAME, CURRENT_COUNT_USED, CURRENT_NUMBER_OF_BYTES_USED, HIGH_NUMBER_OF_BYTES_USED
+			FROM performance_schema.memory_summary_global_by_event_name
+			WHERE CURRENT_NUMBER_OF_BYTES_USED > 0
+			ORDER BY CURRENT_NUMBER_OF_BYTES_USED DESC
+			LIMIT ?`, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	defer rows.Close()
+
+	consumers := []MemoryConsumer{}
+	for rows.Next() {
+		var m MemoryConsumer
+		if err := rows.Scan(&m.Event, &m.CurrentCount, &m.CurrentBytes, &m.HighBytes); err != nil {
+			return nil, nil, err
+		}
+		consumers = append(consumers, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var total sql.NullInt64
+	if err := db.QueryRowContext(ctx,
+		`SELECT SUM(CURRENT_NUMBER_OF_BYTES_USED) FROM performance_schema.memory_summary_global_by_event_name`).Scan(&total); err != nil || !total.Valid {
+		return nil, consumers, nil
+	}
+	return &total.Int64, consumers, nil
+}
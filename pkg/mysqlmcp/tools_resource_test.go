@@ -0,0 +1,73 @@
+// pkg/mysqlmcp/tools_resource_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolResourceUsage(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM performance_schema.global_status").
+		WillReturnRows(sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).
+			AddRow("Uptime", "3600").
+			AddRow("Created_tmp_tables", "1000").
+			AddRow("Created_tmp_disk_tables", "600").
+			AddRow("Sort_merge_passes", "5").
+			AddRow("Sort_scan", "80").
+			AddRow("Sort_range", "20").
+			AddRow("Select_full_join", "120"))
+	mock.ExpectQuery("FROM sys.x\\$memory_global_by_current_bytes").WithArgs(3).
+		WillReturnError(errors.New("Table 'sys.x$memory_global_by_current_bytes' doesn't exist"))
+	mock.ExpectQuery("FROM performance_schema.memory_summary_global_by_event_name\\s+WHERE").WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"EVENT_NAME", "CURRENT_COUNT_USED", "CURRENT_NUMBER_OF_BYTES_USED", "HIGH_NUMBER_OF_BYTES_USED"}).
+			AddRow("memory/innodb/buf_buf_pool", 1, 137428992, 137428992).
+			AddRow("memory/sql/TABLE", 300, 2097152, 4194304))
+	mock.ExpectQuery("SELECT SUM\\(CURRENT_NUMBER_OF_BYTES_USED\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(150000000))
+
+	_, out, err := toolResourceUsage(context.Background(), &mcp.CallToolRequest{}, ResourceUsageInput{TopMemory: 3})
+	if err != nil {
+		t.Fatalf("resource_usage: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+	if out.Status != severityCritical || out.UptimeSeconds != 3600 {
+		t.Errorf("unexpected status: %+v", out)
+	}
+	severities := map[string]string{}
+	for _, c := range out.Checks {
+		severities[c.Name] = c.Severity
+	}
+	if severities["tmp_disk_tables"] != severityCritical || severities["sort_merge_passes"] != severityOK || severities["full_joins"] != severityWarning {
+		t.Errorf("unexpected checks: %+v", out.Checks)
+	}
+	if len(out.Counters) != 6 || out.Counters[0].Name != "Created_tmp_tables" || out.Counters[0].Meaning == "" {
+		t.Errorf("unexpected counters: %+v", out.Counters)
+	}
+	if len(out.TopMemory) != 2 || out.TopMemory[0].CurrentBytes != 137428992 || out.MemoryTotalBytes == nil || *out.MemoryTotalBytes != 150000000 {
+		t.Errorf("unexpected memory: %+v %v", out.TopMemory, out.MemoryTotalBytes)
+	}
+
+	// Without performance_schema the counters are still reported.
+	mock.ExpectQuery("FROM performance_schema.global_status").WillReturnError(errors.New("performance_schema disabled"))
+	mock.ExpectQuery("SHOW GLOBAL STATUS WHERE").
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("Uptime", "86400"))
+	mock.ExpectQuery("FROM sys.x\\$memory_global_by_current_bytes").WillReturnError(errors.New("denied"))
+	mock.ExpectQuery("FROM performance_schema.memory_summary_global_by_event_name").WillReturnError(errors.New("denied"))
+	_, out, err = toolResourceUsage(context.Background(), &mcp.CallToolRequest{}, ResourceUsageInput{})
+	if err != nil {
+		t.Fatalf("resource_usage: %v", err)
+	}
+	if out.TopMemory != nil || !strings.Contains(strings.Join(out.Notes, " "), "memory instrumentation unavailable") {
+		t.Errorf("expected a memory note: %+v", out)
+	}
+}
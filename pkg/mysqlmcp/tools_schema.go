@@ -0,0 +1,621 @@
+// pkg/mysqlmcp/tools_schema.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Text renderings supported by schema_graph.
+const (
+	graphFormatJSON    = "json"
+	graphFormatDOT     = "dot"
+	graphFormatMermaid = "mermaid"
+)
+
+func toolSchemaGraph(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SchemaGraphInput,
+) (*mcp.CallToolResult, SchemaGraphOutput, error) {
+	if input.Database == "" {
+		return nil, SchemaGraphOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, SchemaGraphOutput{}, err
+	}
+	format := strings.ToLower(strings.TrimSpace(input.Format))
+	switch format {
+	case "":
+		format = graphFormatJSON
+	case graphFormatJSON, graphFormatDOT, graphFormatMermaid:
+	default:
+		return nil, SchemaGraphOutput{}, fmt.Errorf("format must be one of: json, dot, mermaid")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	edges, truncated, err := loadForeignKeyEdges(ctx, input.Database, nil, maxRows)
+	if err != nil {
+		return nil, SchemaGraphOutput{}, err
+	}
+
+	out := SchemaGraphOutput{
+		Database:  input.Database,
+		Nodes:     []SchemaGraphNode{},
+		Edges:     []SchemaGraphEdge{},
+		Format:    format,
+		Truncated: truncated,
+	}
+	nodes := map[string]*SchemaGraphNode{}
+	addNode := func(name string, external bool) *SchemaGraphNode {
+		n, ok := nodes[name]
+		if !ok {
+			n = &SchemaGraphNode{Name: name, External: external}
+			nodes[name] = n
+		}
+		return n
+	}
+	for _, e := range edges {
+		out.Edges = append(out.Edges, e.SchemaGraphEdge)
+		addNode(e.From, false).References++
+		addNode(e.To, e.external).ReferencedBy++
+	}
+
+	if input.IncludeIsolated {
+		trows, err := getDB().QueryContext(ctx,
+			"SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'",
+			input.Database)
+		if err != nil {
+			return nil, SchemaGraphOutput{}, fmt.Errorf("failed to list tables: %w", err)
+		}
+		defer trows.Close()
+		for trows.Next() {
+			var name string
+			if err := trows.Scan(&name); err == nil {
+				addNode(name, false)
+			}
+		}
+		if err := trows.Err(); err != nil {
+			return nil, SchemaGraphOutput{}, err
+		}
+	}
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.Nodes = append(out.Nodes, *nodes[name])
+	}
+
+	switch format {
+	case graphFormatDOT:
+		out.Text = renderSchemaGraphDOT(out)
+	case graphFormatMermaid:
+		out.Text = renderSchemaGraphMermaid(out)
+	}
+	return nil, out, nil
+}
+
+// foreignKeyEdge is a SchemaGraphEdge plus whether the parent table is in another database.
+type foreignKeyEdge struct {
+	SchemaGraphEdge
+	external bool
+}
+
+// loadForeignKeyEdges reads the foreign keys declared in database, one edge per
+// constraint with composite keys grouped in key order. When tables is non-empty
+// only constraints on those child tables are returned. At most limit edges are
+// returned; the bool result reports whether more were available.
+func loadForeignKeyEdges(ctx context.Context, database string, tables []string, limit int) ([]foreignKeyEdge, bool, error) {
+	query := `SELECT
+		kcu.CONSTRAINT_NAME, kcu.TABLE_NAME, kcu.COLUMN_NAME,
+		kcu.REFERENCED_TABLE_SCHEMA, kcu.REFERENCED_TABLE_NAME, kcu.REFERENCED_COLUMN_NAME,
+		rc.UPDATE_RULE, rc.DELETE_RULE
+		FROM information_schema.KEY_COLUMN_USAGE kcu
+		LEFT JOIN information_schema.REFERENTIAL_CONSTRAINTS rc
+		  ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+		 AND rc.TABLE_NAME = kcu.TABLE_NAME
+		 AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+		WHERE kcu.CONSTRAINT_SCHEMA = ? AND kcu.REFERENCED_TABLE_NAME IS NOT NULL`
+	args := []interface{}{database}
+	if len(tables) > 0 {
+		query += " AND kcu.TABLE_NAME IN (" + placeholders(len(tables)) + ")"
+		args = append(args, stringsToArgs(tables)...)
+	}
+	// ORDINAL_POSITION keeps composite keys in order.
+	query += " ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION"
+
+	rows, err := getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("foreign key query failed: %w", err)
+	}
+	defer rows.Close()
+
+	edges := []foreignKeyEdge{}
+	for rows.Next() {
+		var constraint, table, column, refSchema, refTable, refColumn string
+		var onUpdate, onDelete sql.NullString
+		if err := rows.Scan(&constraint, &table, &column, &refSchema, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			continue
+		}
+		last := len(edges) - 1
+		if last < 0 || edges[last].Constraint != constraint || edges[last].From != table {
+			if len(edges) >= limit {
+				return edges, true, nil
+			}
+			e := foreignKeyEdge{SchemaGraphEdge: SchemaGraphEdge{
+				Constraint: constraint,
+				From:       table,
+				To:         refTable,
+				OnUpdate:   onUpdate.String,
+				OnDelete:   onDelete.String,
+			}}
+			if !strings.EqualFold(refSchema, database) {
+				e.To, e.external = refSchema+"."+refTable, true
+			}
+			edges = append(edges, e)
+			last++
+		}
+		edges[last].Columns = append(edges[last].Columns, column)
+		edges[last].ReferencedColumns = append(edges[last].ReferencedColumns, refColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	return edges, false, nil
+}
+
+// placeholders returns n comma-separated "?" markers.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// schemaGraphEdgeLabel describes an edge as "constraint (col, ...)".
+func schemaGraphEdgeLabel(e SchemaGraphEdge) string {
+	return e.Constraint + " (" + strings.Join(e.Columns, ", ") + ")"
+}
+
+func renderSchemaGraphDOT(g SchemaGraphOutput) string {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", quote(g.Database))
+	b.WriteString("  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		if n.External {
+			fmt.Fprintf(&b, "  %s [style=dashed];\n", quote(n.Name))
+		} else {
+			fmt.Fprintf(&b, "  %s;\n", quote(n.Name))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", quote(e.From), quote(e.To), quote(schemaGraphEdgeLabel(e)))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// renderSchemaGraphMermaid emits a flowchart; node IDs are positional so that
+// table names never need to be valid Mermaid identifiers.
+func renderSchemaGraphMermaid(g SchemaGraphOutput) string {
+	escape := strings.NewReplacer(`"`, "#quot;").Replace
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for i, n := range g.Nodes {
+		ids[n.Name] = fmt.Sprintf("t%d", i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n.Name], escape(n.Name))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", ids[e.From], escape(schemaGraphEdgeLabel(e)), ids[e.To])
+	}
+	return b.String()
+}
+
+const (
+	defaultDataDictionaryPage = 10
+	maxDataDictionaryPage     = 50
+)
+
+func toolGenerateDataDictionary(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input GenerateDataDictionaryInput,
+) (*mcp.CallToolResult, GenerateDataDictionaryOutput, error) {
+	if input.Database == "" {
+		return nil, GenerateDataDictionaryOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, GenerateDataDictionaryOutput{}, err
+	}
+	if input.Offset < 0 {
+		return nil, GenerateDataDictionaryOutput{}, fmt.Errorf("offset must be >= 0")
+	}
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultDataDictionaryPage
+	}
+	if limit > maxDataDictionaryPage {
+		limit = maxDataDictionaryPage
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	// Fetch one extra row to detect whether another page exists.
+	query := `SELECT TABLE_NAME, ENGINE, TABLE_ROWS,
+		ROUND(DATA_LENGTH / 1024 / 1024, 2), ROUND(INDEX_LENGTH / 1024 / 1024, 2), TABLE_COMMENT
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'`
+	args := []interface{}{input.Database}
+	if input.Pattern != "" {
+		query += " AND TABLE_NAME LIKE ?"
+		args = append(args, input.Pattern)
+	}
+	query += " ORDER BY TABLE_NAME LIMIT ? OFFSET ?"
+	args = append(args, limit+1, input.Offset)
+
+	rows, err := getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, GenerateDataDictionaryOutput{}, fmt.Errorf("table query failed: %w", err)
+	}
+	defer rows.Close()
+
+	out := GenerateDataDictionaryOutput{Database: input.Database, Tables: []DataDictionaryTable{}}
+	for rows.Next() {
+		var t DataDictionaryTable
+		var engine, comment sql.NullString
+		var tableRows sql.NullInt64
+		var dataMB, indexMB sql.NullFloat64
+		if err := rows.Scan(&t.Name, &engine, &tableRows, &dataMB, &indexMB, &comment); err != nil {
+			continue
+		}
+		if len(out.Tables) == limit {
+			out.HasMore = true
+			break
+		}
+		t.Engine = engine.String
+		t.Comment = comment.String
+		t.RowEstimate = tableRows.Int64
+		t.DataMB = dataMB.Float64
+		t.IndexMB = indexMB.Float64
+		t.Columns = []ColumnInfo{}
+		t.Indexes = []IndexInfo{}
+		t.ForeignKeys = []SchemaGraphEdge{}
+		out.Tables = append(out.Tables, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, GenerateDataDictionaryOutput{}, err
+	}
+	if out.HasMore {
+		next := input.Offset + limit
+		out.NextOffset = &next
+	}
+	if len(out.Tables) == 0 {
+		return nil, out, nil
+	}
+
+	names := make([]string, len(out.Tables))
+	byName := make(map[string]*DataDictionaryTable, len(out.Tables))
+	for i := range out.Tables {
+		names[i] = out.Tables[i].Name
+		byName[names[i]] = &out.Tables[i]
+	}
+	inArgs := append([]interface{}{input.Database}, stringsToArgs(names)...)
+	inClause := "TABLE_SCHEMA = ? AND TABLE_NAME IN (" + placeholders(len(names)) + ")"
+
+	crows, err := getDB().QueryContext(ctx, `SELECT TABLE_NAME,
+		COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY,
+		COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, COLLATION_NAME, GENERATION_EXPRESSION
+		FROM information_schema.COLUMNS
+		WHERE `+inClause+`
+		ORDER BY TABLE_NAME, ORDINAL_POSITION`, inArgs...)
+	if err != nil {
+		return nil, GenerateDataDictionaryOutput{}, fmt.Errorf("column query failed: %w", err)
+	}
+	defer crows.Close()
+	for crows.Next() {
+		var table string
+		var name, colType, nullable, key, dataDefault, extra, comment, collation, generation sql.NullString
+		if err := crows.Scan(&table, &name, &colType, &nullable, &key, &dataDefault, &extra, &comment, &collation, &generation); err != nil {
+			continue
+		}
+		if t, ok := byName[table]; ok {
+			col := ColumnInfo{
+				Name:      name.String,
+				Type:      colType.String,
+				Null:      nullable.String,
+				Key:       key.String,
+				Default:   dataDefault.String,
+				Extra:     extra.String,
+				Comment:   comment.String,
+				Collation: collation.String,
+			}
+			setGeneration(&col, generation)
+			t.Columns = append(t.Columns, col)
+		}
+	}
+	if err := crows.Err(); err != nil {
+		return nil, GenerateDataDictionaryOutput{}, err
+	}
+
+	irows, err := getDB().QueryContext(ctx, `SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, COLUMN_NAME, INDEX_TYPE
+		FROM information_schema.STATISTICS
+		WHERE `+inClause+`
+		ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`, inArgs...)
+	if err != nil {
+		return nil, GenerateDataDictionaryOutput{}, fmt.Errorf("index query failed: %w", err)
+	}
+	defer irows.Close()
+	for irows.Next() {
+		var table, index, indexType string
+		var column sql.NullString // NULL for functional key parts
+		var nonUnique int
+		if err := irows.Scan(&table, &index, &nonUnique, &column, &indexType); err != nil {
+			continue
+		}
+		t, ok := byName[table]
+		if !ok {
+			continue
+		}
+		col := column.String
+		if !column.Valid {
+			col = "(expression)"
+		}
+		if n := len(t.Indexes); n > 0 && t.Indexes[n-1].Name == index {
+			t.Indexes[n-1].Columns += ", " + col
+			continue
+		}
+		t.Indexes = append(t.Indexes, IndexInfo{Name: index, Columns: col, NonUnique: nonUnique == 1, Type: indexType})
+	}
+	if err := irows.Err(); err != nil {
+		return nil, GenerateDataDictionaryOutput{}, err
+	}
+
+	edges, _, err := loadForeignKeyEdges(ctx, input.Database, names, maxRows)
+	if err != nil {
+		return nil, GenerateDataDictionaryOutput{}, err
+	}
+	for _, e := range edges {
+		if t, ok := byName[e.From]; ok {
+			t.ForeignKeys = append(t.ForeignKeys, e.SchemaGraphEdge)
+		}
+	}
+
+	return nil, out, nil
+}
+
+// errUnknownTable is ER_UNKNOWN_TABLE, returned by servers that predate
+// information_schema.CHECK_CONSTRAINTS (MySQL < 8.0.16).
+const errUnknownTable = 1109
+
+func toolTableConstraints(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input TableConstraintsInput,
+) (*mcp.CallToolResult, TableConstraintsOutput, error) {
+	if input.Database == "" {
+		return nil, TableConstraintsOutput{}, fmt.Errorf("database is required")
+	}
+	if input.Table == "" {
+		return nil, TableConstraintsOutput{}, fmt.Errorf("table is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, TableConstraintsOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	rows, err := getDB().QueryContext(ctx, `SELECT CONSTRAINT_NAME, CONSTRAINT_TYPE
+		FROM information_schema.TABLE_CONSTRAINTS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY CONSTRAINT_TYPE, CONSTRAINT_NAME`, input.Database, input.Table)
+	if err != nil {
+		return nil, TableConstraintsOutput{}, fmt.Errorf("constraint query failed: %w", err)
+	}
+	defer rows.Close()
+
+	out := TableConstraintsOutput{Constraints: []TableConstraintInfo{}}
+	byName := map[string]*TableConstraintInfo{}
+	for rows.Next() {
+		var c TableConstraintInfo
+		if err := rows.Scan(&c.Name, &c.Type); err != nil {
+			return nil, TableConstraintsOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		out.Constraints = append(out.Constraints, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, TableConstraintsOutput{}, err
+	}
+	hasCheck := false
+	for i := range out.Constraints {
+		c := &out.Constraints[i]
+		byName[c.Name] = c
+		hasCheck = hasCheck || c.Type == "CHECK"
+	}
+
+	krows, err := getDB().QueryContext(ctx, `SELECT CONSTRAINT_NAME, COLUMN_NAME,
+		REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`, input.Database, input.Table)
+	if err != nil {
+		return nil, TableConstraintsOutput{}, fmt.Errorf("key column query failed: %w", err)
+	}
+	defer krows.Close()
+	for krows.Next() {
+		var name, column string
+		var refSchema, refTable, refColumn sql.NullString
+		if err := krows.Scan(&name, &column, &refSchema, &refTable, &refColumn); err != nil {
+			return nil, TableConstraintsOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		c, ok := byName[name]
+		if !ok {
+			continue
+		}
+		c.Columns = append(c.Columns, column)
+		if refTable.Valid {
+			c.ReferencedTable = refTable.String
+			if refSchema.String != input.Database {
+				c.ReferencedTable = refSchema.String + "." + refTable.String
+			}
+			c.ReferencedColumns = append(c.ReferencedColumns, refColumn.String)
+		}
+	}
+	if err := krows.Err(); err != nil {
+		return nil, TableConstraintsOutput{}, err
+	}
+
+	// CHECK_CONSTRAINTS has no TABLE_NAME on MySQL; match by constraint name,
+	// which is unique per schema.
+	if hasCheck {
+		crows, err := getDB().QueryContext(ctx, `SELECT CONSTRAINT_NAME, CHECK_CLAUSE
+			FROM information_schema.CHECK_CONSTRAINTS
+			WHERE CONSTRAINT_SCHEMA = ?`, input.Database)
+		if err != nil {
+			var mysqlErr *mysql.MySQLError
+			if !errors.As(err, &mysqlErr) || mysqlErr.Number != errUnknownTable {
+				return nil, TableConstraintsOutput{}, fmt.Errorf("check constraint query failed: %w", err)
+			}
+			out.Notes = append(out.Notes, "information_schema.CHECK_CONSTRAINTS is not available on this server; check clauses are omitted.")
+		} else {
+			defer crows.Close()
+			for crows.Next() {
+				var name, clause string
+				if err := crows.Scan(&name, &clause); err != nil {
+					return nil, TableConstraintsOutput{}, fmt.Errorf("scan failed: %w", err)
+				}
+				if c, ok := byName[name]; ok && c.Type == "CHECK" {
+					c.CheckClause = clause
+				}
+			}
+			if err := crows.Err(); err != nil {
+				return nil, TableConstraintsOutput{}, err
+			}
+		}
+	}
+
+	if len(out.Constraints) == 0 {
+		exists, err := tableExists(ctx, input.Database, input.Table)
+		if err != nil {
+			return nil, TableConstraintsOutput{}, err
+		}
+		if !exists {
+			return nil, TableConstraintsOutput{}, fmt.Errorf("table not found: %s.%s", input.Database, input.Table)
+		}
+	}
+
+	return nil, out, nil
+}
+
+func toolFindColumns(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input FindColumnsInput,
+) (*mcp.CallToolResult, FindColumnsOutput, error) {
+	if input.Pattern == "" && input.DataType == "" {
+		return nil, FindColumnsOutput{}, fmt.Errorf("pattern or data_type is required")
+	}
+	if input.Database != "" {
+		if err := requireAllowedDatabase(input.Database); err != nil {
+			return nil, FindColumnsOutput{}, err
+		}
+	}
+	limit := input.Limit
+	if limit <= 0 || limit > maxRows {
+		limit = maxRows
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	query := `SELECT TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, DATA_TYPE, COLUMN_TYPE,
+		IS_NULLABLE, COLUMN_KEY, COLUMN_COMMENT
+		FROM information_schema.COLUMNS
+		WHERE 1 = 1`
+	var args []interface{}
+	if input.Pattern != "" {
+		query += " AND COLUMN_NAME LIKE ?"
+		args = append(args, input.Pattern)
+	}
+	if input.DataType != "" {
+		query += " AND DATA_TYPE LIKE ?"
+		args = append(args, input.DataType)
+	}
+
+	if input.Database != "" {
+		query += " AND TABLE_SCHEMA = ?"
+		args = append(args, input.Database)
+	} else {
+		if accessControlEnabled() {
+			filter, filterArgs := allowedSchemaFilter("TABLE_SCHEMA")
+			query += " AND " + filter
+			args = append(args, filterArgs...)
+		}
+		if !input.IncludeSystem {
+			query += " AND TABLE_SCHEMA NOT IN ('information_schema', 'performance_schema', 'mysql', 'sys')"
+		}
+		if len(input.ExcludeDatabases) > 0 {
+			query += " AND TABLE_SCHEMA NOT IN (" + placeholders(len(input.ExcludeDatabases)) + ")"
+			args = append(args, stringsToArgs(input.ExcludeDatabases)...)
+		}
+	}
+
+	// Fetch one extra row to detect truncation.
+	query += " ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION LIMIT ?"
+	args = append(args, limit+1)
+
+	rows, err := getDB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, FindColumnsOutput{}, fmt.Errorf("column search failed: %w", err)
+	}
+	defer rows.Close()
+
+	out := FindColumnsOutput{Columns: []ColumnMatch{}}
+	for rows.Next() {
+		var m ColumnMatch
+		var nullable string
+		var key, comment sql.NullString
+		if err := rows.Scan(&m.Database, &m.Table, &m.Column, &m.DataType, &m.ColumnType, &nullable, &key, &comment); err != nil {
+			return nil, FindColumnsOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		if len(out.Columns) == limit {
+			out.Truncated = true
+			break
+		}
+		m.Nullable = nullable == "YES"
+		m.Key = key.String
+		m.Comment = comment.String
+		out.Columns = append(out.Columns, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FindColumnsOutput{}, fmt.Errorf("column search rows iteration: %w", err)
+	}
+	out.Count = len(out.Columns)
+
+	return nil, out, nil
+}
+
+func stringsToArgs(values []string) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
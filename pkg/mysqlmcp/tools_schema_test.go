@@ -0,0 +1,394 @@
+// pkg/mysqlmcp/tools_schema_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func schemaGraphRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"CONSTRAINT_NAME", "TABLE_NAME", "COLUMN_NAME",
+		"REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME",
+		"UPDATE_RULE", "DELETE_RULE",
+	}).
+		AddRow("fk_items_order", "order_items", "order_id", "shop", "orders", "id", "RESTRICT", "CASCADE").
+		AddRow("fk_items_sku", "order_items", "sku", "shop", "products", "sku", "RESTRICT", "RESTRICT").
+		AddRow("fk_items_sku", "order_items", "variant", "shop", "products", "variant", "RESTRICT", "RESTRICT").
+		AddRow("fk_orders_customer", "orders", "customer_id", "crm", "customers", "id", "NO ACTION", "NO ACTION")
+}
+
+func TestToolSchemaGraphSuccess(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").
+		WithArgs("shop").
+		WillReturnRows(schemaGraphRows())
+	mock.ExpectQuery("FROM information_schema.TABLES").
+		WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME"}).
+			AddRow("orders").AddRow("order_items").AddRow("products").AddRow("settings"))
+
+	_, out, err := toolSchemaGraph(context.Background(), &mcp.CallToolRequest{}, SchemaGraphInput{
+		Database:        "shop",
+		IncludeIsolated: true,
+	})
+	if err != nil {
+		t.Fatalf("toolSchemaGraph failed: %v", err)
+	}
+
+	if len(out.Edges) != 3 {
+		t.Fatalf("expected 3 edges, got %+v", out.Edges)
+	}
+	sku := out.Edges[1]
+	if sku.Constraint != "fk_items_sku" || strings.Join(sku.Columns, ",") != "sku,variant" ||
+		strings.Join(sku.ReferencedColumns, ",") != "sku,variant" {
+		t.Errorf("composite key not grouped: %+v", sku)
+	}
+	if out.Edges[2].To != "crm.customers" {
+		t.Errorf("expected cross-database reference to be qualified, got %q", out.Edges[2].To)
+	}
+
+	byName := map[string]SchemaGraphNode{}
+	for _, n := range out.Nodes {
+		byName[n.Name] = n
+	}
+	if len(byName) != 5 {
+		t.Errorf("expected 5 nodes, got %+v", out.Nodes)
+	}
+	if n := byName["order_items"]; n.References != 2 || n.ReferencedBy != 0 {
+		t.Errorf("unexpected order_items node: %+v", n)
+	}
+	if n := byName["orders"]; n.References != 1 || n.ReferencedBy != 1 {
+		t.Errorf("unexpected orders node: %+v", n)
+	}
+	if n := byName["crm.customers"]; !n.External {
+		t.Errorf("expected crm.customers to be external: %+v", n)
+	}
+	if _, ok := byName["settings"]; !ok {
+		t.Error("expected isolated table settings to be included")
+	}
+	if out.Text != "" {
+		t.Errorf("expected no text for json format, got %q", out.Text)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolSchemaGraphRenderings(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").WillReturnRows(schemaGraphRows())
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").WillReturnRows(schemaGraphRows())
+
+	_, dot, err := toolSchemaGraph(context.Background(), &mcp.CallToolRequest{}, SchemaGraphInput{Database: "shop", Format: "DOT"})
+	if err != nil {
+		t.Fatalf("toolSchemaGraph(dot) failed: %v", err)
+	}
+	if !strings.HasPrefix(dot.Text, `digraph "shop" {`) ||
+		!strings.Contains(dot.Text, `"order_items" -> "products" [label="fk_items_sku (sku, variant)"];`) ||
+		!strings.Contains(dot.Text, `"crm.customers" [style=dashed];`) {
+		t.Errorf("unexpected DOT output:\n%s", dot.Text)
+	}
+
+	_, mermaid, err := toolSchemaGraph(context.Background(), &mcp.CallToolRequest{}, SchemaGraphInput{Database: "shop", Format: "mermaid"})
+	if err != nil {
+		t.Fatalf("toolSchemaGraph(mermaid) failed: %v", err)
+	}
+	// Nodes are sorted: crm.customers=t0, order_items=t1, orders=t2, products=t3.
+	if !strings.HasPrefix(mermaid.Text, "graph LR\n") ||
+		!strings.Contains(mermaid.Text, `t1 -->|"fk_items_order (order_id)"| t2`) {
+		t.Errorf("unexpected Mermaid output:\n%s", mermaid.Text)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolSchemaGraphInvalidInput(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	if _, _, err := toolSchemaGraph(context.Background(), &mcp.CallToolRequest{}, SchemaGraphInput{}); err == nil || err.Error() != "database is required" {
+		t.Errorf("expected database is required, got %v", err)
+	}
+	if _, _, err := toolSchemaGraph(context.Background(), &mcp.CallToolRequest{}, SchemaGraphInput{Database: "shop", Format: "svg"}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestHTTPSchemaGraphInvalidFormat(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/schema-graph?database=shop&format=svg", nil)
+	w := httptest.NewRecorder()
+
+	httpSchemaGraph(w, req)
+
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Result().StatusCode)
+	}
+}
+
+func TestToolGenerateDataDictionaryPage(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.TABLES").
+		WithArgs("shop", 3, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "data_mb", "index_mb", "TABLE_COMMENT"}).
+			AddRow("order_items", "InnoDB", 5000, 1.5, 0.5, "").
+			AddRow("orders", "InnoDB", 1200, 0.25, 0.1, "Customer orders").
+			AddRow("products", "InnoDB", 80, 0.02, 0.02, ""))
+	mock.ExpectQuery("FROM information_schema.COLUMNS").
+		WithArgs("shop", "order_items", "orders").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+			AddRow("order_items", "id", "bigint", "NO", "PRI", nil, "auto_increment", "", nil, nil).
+			AddRow("order_items", "order_id", "bigint", "NO", "MUL", nil, "", "", nil, nil).
+			AddRow("orders", "id", "bigint", "NO", "PRI", nil, "auto_increment", "", nil, nil).
+			AddRow("orders", "status", "varchar(20)", "NO", "", "new", "", "order state", "utf8mb4_0900_ai_ci", nil))
+	mock.ExpectQuery("FROM information_schema.STATISTICS").
+		WithArgs("shop", "order_items", "orders").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "INDEX_NAME", "NON_UNIQUE", "COLUMN_NAME", "INDEX_TYPE"}).
+			AddRow("order_items", "PRIMARY", 0, "id", "BTREE").
+			AddRow("order_items", "idx_order", 1, "order_id", "BTREE").
+			AddRow("order_items", "idx_order", 1, "id", "BTREE").
+			AddRow("orders", "PRIMARY", 0, "id", "BTREE"))
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").
+		WithArgs("shop", "order_items", "orders").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"CONSTRAINT_NAME", "TABLE_NAME", "COLUMN_NAME",
+			"REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME",
+			"UPDATE_RULE", "DELETE_RULE",
+		}).AddRow("fk_items_order", "order_items", "order_id", "shop", "orders", "id", "RESTRICT", "CASCADE"))
+
+	_, out, err := toolGenerateDataDictionary(context.Background(), &mcp.CallToolRequest{}, GenerateDataDictionaryInput{
+		Database: "shop",
+		Limit:    2,
+	})
+	if err != nil {
+		t.Fatalf("toolGenerateDataDictionary failed: %v", err)
+	}
+
+	if len(out.Tables) != 2 || !out.HasMore || out.NextOffset == nil || *out.NextOffset != 2 {
+		t.Fatalf("unexpected pagination: tables=%d has_more=%v next=%v", len(out.Tables), out.HasMore, out.NextOffset)
+	}
+	items, orders := out.Tables[0], out.Tables[1]
+	if items.RowEstimate != 5000 || len(items.Columns) != 2 || len(items.ForeignKeys) != 1 {
+		t.Errorf("unexpected order_items entry: %+v", items)
+	}
+	if len(items.Indexes) != 2 || items.Indexes[1].Columns != "order_id, id" || !items.Indexes[1].NonUnique {
+		t.Errorf("unexpected order_items indexes: %+v", items.Indexes)
+	}
+	if orders.Comment != "Customer orders" || orders.Columns[1].Default != "new" || len(orders.ForeignKeys) != 0 {
+		t.Errorf("unexpected orders entry: %+v", orders)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolGenerateDataDictionaryEmptyPage(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.TABLES").
+		WithArgs("shop", "audit%", defaultDataDictionaryPage+1, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "data_mb", "index_mb", "TABLE_COMMENT"}))
+
+	_, out, err := toolGenerateDataDictionary(context.Background(), &mcp.CallToolRequest{}, GenerateDataDictionaryInput{
+		Database: "shop",
+		Pattern:  "audit%",
+		Offset:   40,
+	})
+	if err != nil {
+		t.Fatalf("toolGenerateDataDictionary failed: %v", err)
+	}
+	if len(out.Tables) != 0 || out.HasMore {
+		t.Errorf("expected empty last page, got %+v", out)
+	}
+
+	if _, _, err := toolGenerateDataDictionary(context.Background(), &mcp.CallToolRequest{}, GenerateDataDictionaryInput{}); err == nil {
+		t.Error("expected error for missing database")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func findColumnsRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"TABLE_SCHEMA", "TABLE_NAME", "COLUMN_NAME", "DATA_TYPE", "COLUMN_TYPE",
+		"IS_NULLABLE", "COLUMN_KEY", "COLUMN_COMMENT",
+	})
+}
+
+func TestToolFindColumnsAcrossDatabases(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`(?s)FROM information_schema\.COLUMNS.*COLUMN_NAME LIKE \?.*TABLE_SCHEMA NOT IN \('information_schema', 'performance_schema', 'mysql', 'sys'\) AND TABLE_SCHEMA NOT IN \(\?\).*ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION LIMIT \?`).
+		WithArgs("%email%", "archive", 3).
+		WillReturnRows(findColumnsRows().
+			AddRow("crm", "customers", "email", "varchar", "varchar(255)", "NO", "UNI", "login").
+			AddRow("shop", "orders", "billing_email", "varchar", "varchar(255)", "YES", "", nil).
+			AddRow("shop", "users", "email", "varchar", "varchar(320)", "NO", "", ""))
+
+	_, out, err := toolFindColumns(context.Background(), &mcp.CallToolRequest{}, FindColumnsInput{
+		Pattern:          "%email%",
+		ExcludeDatabases: []string{"archive"},
+		Limit:            2,
+	})
+	if err != nil {
+		t.Fatalf("toolFindColumns failed: %v", err)
+	}
+	if out.Count != 2 || !out.Truncated {
+		t.Fatalf("expected 2 truncated results, got %+v", out)
+	}
+	first := out.Columns[0]
+	if first.Database != "crm" || first.Key != "UNI" || first.Nullable || first.Comment != "login" {
+		t.Errorf("unexpected first match: %+v", first)
+	}
+	if !out.Columns[1].Nullable {
+		t.Errorf("expected nullable column: %+v", out.Columns[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolFindColumnsByTypeWithAllowlist(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	t.Cleanup(func() { initAccessControl(nil) })
+	initAccessControl([]string{"Shop"})
+
+	mock.ExpectQuery(`(?s)DATA_TYPE LIKE \? AND LOWER\(TABLE_SCHEMA\) IN \(\?\)`).
+		WithArgs("json", "shop", 1001).
+		WillReturnRows(findColumnsRows().
+			AddRow("shop", "orders", "meta", "json", "json", "YES", "", ""))
+
+	_, out, err := toolFindColumns(context.Background(), &mcp.CallToolRequest{}, FindColumnsInput{DataType: "json"})
+	if err != nil {
+		t.Fatalf("toolFindColumns failed: %v", err)
+	}
+	if out.Count != 1 || out.Truncated {
+		t.Errorf("unexpected result: %+v", out)
+	}
+
+	if _, _, err := toolFindColumns(context.Background(), &mcp.CallToolRequest{}, FindColumnsInput{Pattern: "%", Database: "crm"}); err == nil {
+		t.Error("expected allowlist error for crm")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolFindColumnsRequiresFilter(t *testing.T) {
+	_, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	if _, _, err := toolFindColumns(context.Background(), &mcp.CallToolRequest{}, FindColumnsInput{Database: "shop"}); err == nil {
+		t.Error("expected error without pattern or data_type")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/columns?database=shop", nil)
+	w := httptest.NewRecorder()
+	httpFindColumns(w, req)
+	if w.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Result().StatusCode)
+	}
+}
+
+func TestToolTableConstraints(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.TABLE_CONSTRAINTS").
+		WithArgs("shop", "order_items").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "CONSTRAINT_TYPE"}).
+			AddRow("order_items_chk_1", "CHECK").
+			AddRow("fk_order", "FOREIGN KEY").
+			AddRow("PRIMARY", "PRIMARY KEY"))
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").
+		WithArgs("shop", "order_items").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"}).
+			AddRow("PRIMARY", "order_id", nil, nil, nil).
+			AddRow("PRIMARY", "line", nil, nil, nil).
+			AddRow("fk_order", "order_id", "shop", "orders", "id"))
+	mock.ExpectQuery("FROM information_schema.CHECK_CONSTRAINTS").
+		WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "CHECK_CLAUSE"}).
+			AddRow("orders_chk_1", "(`total` >= 0)").
+			AddRow("order_items_chk_1", "(`qty` > 0)"))
+
+	_, out, err := toolTableConstraints(context.Background(), &mcp.CallToolRequest{}, TableConstraintsInput{Database: "shop", Table: "order_items"})
+	if err != nil {
+		t.Fatalf("toolTableConstraints failed: %v", err)
+	}
+	if len(out.Constraints) != 3 {
+		t.Fatalf("expected 3 constraints, got %+v", out.Constraints)
+	}
+	if c := out.Constraints[0]; c.CheckClause != "(`qty` > 0)" || len(c.Columns) != 0 {
+		t.Errorf("unexpected check constraint: %+v", c)
+	}
+	if c := out.Constraints[1]; c.ReferencedTable != "orders" || len(c.ReferencedColumns) != 1 || c.ReferencedColumns[0] != "id" {
+		t.Errorf("unexpected foreign key: %+v", c)
+	}
+	if c := out.Constraints[2]; strings.Join(c.Columns, ",") != "order_id,line" {
+		t.Errorf("unexpected primary key columns: %+v", c)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolTableConstraintsWithoutCheckSupport(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("FROM information_schema.TABLE_CONSTRAINTS").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "CONSTRAINT_TYPE"}).AddRow("c1", "CHECK"))
+	mock.ExpectQuery("FROM information_schema.KEY_COLUMN_USAGE").
+		WillReturnRows(sqlmock.NewRows([]string{"CONSTRAINT_NAME", "COLUMN_NAME", "REFERENCED_TABLE_SCHEMA", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME"}))
+	mock.ExpectQuery("FROM information_schema.CHECK_CONSTRAINTS").
+		WillReturnError(&mysql.MySQLError{Number: errUnknownTable, Message: "Unknown table 'CHECK_CONSTRAINTS' in information_schema"})
+
+	_, out, err := toolTableConstraints(context.Background(), &mcp.CallToolRequest{}, TableConstraintsInput{Database: "shop", Table: "t"})
+	if err != nil {
+		t.Fatalf("expected missing CHECK_CONSTRAINTS to be tolerated: %v", err)
+	}
+	if len(out.Constraints) != 1 || len(out.Notes) != 1 {
+		t.Errorf("unexpected result: %+v", out)
+	}
+
+	if _, _, err := toolTableConstraints(context.Background(), &mcp.CallToolRequest{}, TableConstraintsInput{Database: "shop"}); err == nil {
+		t.Error("expected error without table")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
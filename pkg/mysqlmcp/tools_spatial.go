@@ -0,0 +1,123 @@
+// pkg/mysqlmcp/tools_spatial.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// spatialDataTypes are the information_schema.COLUMNS.DATA_TYPE values of spatial columns.
+const spatialDataTypes = `('geometry','point','linestring','polygon','multipoint','multilinestring','multipolygon','geometrycollection','geomcollection')`
+
+func toolSpatialInfo(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SpatialInfoInput,
+) (*mcp.CallToolResult, SpatialInfoOutput, error) {
+	if input.Database == "" {
+		return nil, SpatialInfoOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, SpatialInfoOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	tableFilter := ""
+	args := []interface{}{input.Database}
+	if input.Table != "" {
+		tableFilter = " AND TABLE_NAME = ?"
+		args = append(args, input.Table)
+	}
+
+	out := SpatialInfoOutput{Columns: []SpatialColumnInfo{}, Indexes: []SpatialIndexInfo{}}
+
+	// SRS_ID exists in MySQL 8.0+ only; older servers and MariaDB fall back
+	// to the columns without SRIDs.
+	hasSRID := getServerType() != ServerTypeMariaDB
+	var rows *sql.Rows
+	var err error
+	if hasSRID {
+		rows, err = getDB().QueryContext(ctx, `SELECT c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE, c.IS_NULLABLE, c.SRS_ID, s.SRS_NAME
+			FROM information_schema.COLUMNS c
+			LEFT JOIN information_schema.ST_SPATIAL_REFERENCE_SYSTEMS s ON s.SRS_ID = c.SRS_ID
+			WHERE c.TABLE_SCHEMA = ? AND c.DATA_TYPE IN `+spatialDataTypes+tableFilter+`
+			ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION`, args...)
+		if err != nil {
+			hasSRID = false
+		}
+	}
+	if !hasSRID {
+		out.Notes = append(out.Notes, "this server does not record column SRIDs in information_schema (MySQL 8.0+ only)")
+		rows, err = getDB().QueryContext(ctx, `SELECT c.TABLE_NAME, c.COLUMN_NAME, c.DATA_TYPE, c.IS_NULLABLE
+			FROM information_schema.COLUMNS c
+			WHERE c.TABLE_SCHEMA = ? AND c.DATA_TYPE IN `+spatialDataTypes+tableFilter+`
+			ORDER BY c.TABLE_NAME, c.ORDINAL_POSITION`, args...)
+	}
+	if err != nil {
+		return nil, SpatialInfoOutput{}, fmt.Errorf("spatial column query failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c SpatialColumnInfo
+		var nullable string
+		var srid sql.NullInt64
+		var srsName sql.NullString
+		dest := []interface{}{&c.Table, &c.Column, &c.Type, &nullable}
+		if hasSRID {
+			dest = append(dest, &srid, &srsName)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, SpatialInfoOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		c.Nullable = nullable == "YES"
+		if srid.Valid {
+			v := int(srid.Int64)
+			c.SRID = &v
+			c.SRSName = srsName.String
+		}
+		out.Columns = append(out.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, SpatialInfoOutput{}, err
+	}
+
+	irows, err := getDB().QueryContext(ctx, `SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND INDEX_TYPE = 'SPATIAL'`+tableFilter+`
+		ORDER BY TABLE_NAME, INDEX_NAME`, args...)
+	if err != nil {
+		return nil, SpatialInfoOutput{}, fmt.Errorf("spatial index query failed: %w", err)
+	}
+	defer irows.Close()
+	for irows.Next() {
+		var idx SpatialIndexInfo
+		if err := irows.Scan(&idx.Table, &idx.Name, &idx.Column); err != nil {
+			return nil, SpatialInfoOutput{}, fmt.Errorf("scan failed: %w", err)
+		}
+		out.Indexes = append(out.Indexes, idx)
+	}
+	if err := irows.Err(); err != nil {
+		return nil, SpatialInfoOutput{}, err
+	}
+
+	for _, idx := range out.Indexes {
+		for i := range out.Columns {
+			c := &out.Columns[i]
+			if c.Table != idx.Table || c.Column != idx.Column {
+				continue
+			}
+			c.SpatialIndex = idx.Name
+			if hasSRID && c.SRID == nil {
+				out.Notes = append(out.Notes, fmt.Sprintf(
+					"index %s on %s.%s is ignored by the optimizer: the column has no SRID attribute (add one with ALTER TABLE ... MODIFY %s %s SRID n)",
+					idx.Name, c.Table, c.Column, c.Column, c.Type))
+			}
+		}
+	}
+	return nil, out, nil
+}
@@ -0,0 +1,243 @@
+// pkg/mysqlmcp/tools_suggest.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// suggest_identifiers completes database, table and column names from the
+// schema catalog: names starting with the prefix first, then names containing
+// it, then names within a few edits of it. The last group lets an agent
+// repair a misspelled name in generated SQL with one cheap call instead of a
+// failed query and a describe_table round trip.
+
+const (
+	defaultSuggestLimit = 20
+	maxSuggestLimit     = 100
+
+	identifierKindDatabase = "database"
+	identifierKindTable    = "table"
+	identifierKindColumn   = "column"
+
+	matchExact    = "exact"
+	matchPrefix   = "prefix"
+	matchContains = "contains"
+	matchFuzzy    = "fuzzy"
+)
+
+var matchRank = map[string]int{matchExact: 0, matchPrefix: 1, matchContains: 2, matchFuzzy: 3}
+
+func toolSuggestIdentifiers(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input SuggestIdentifiersInput,
+) (*mcp.CallToolResult, SuggestIdentifiersOutput, error) {
+	database, table := strings.TrimSpace(input.Database), strings.TrimSpace(input.Table)
+	prefix := strings.TrimSpace(input.Prefix)
+
+	// A dotted prefix carries its own context: shop.ord, shop.orders.cu.
+	var out SuggestIdentifiersOutput
+	if parts := strings.Split(prefix, "."); len(parts) > 1 && table == "" {
+		qualifiers := parts[:len(parts)-1]
+		if database == "" {
+			database, qualifiers = qualifiers[0], qualifiers[1:]
+		}
+		if len(qualifiers) > 0 {
+			table = qualifiers[0]
+		}
+		if len(qualifiers) > 1 {
+			return nil, SuggestIdentifiersOutput{}, fmt.Errorf("prefix %q has too many parts", input.Prefix)
+		}
+		prefix = parts[len(parts)-1]
+		out.Notes = append(out.Notes, "The prefix was read as "+strings.Join(nonEmpty(database, table, prefix), "."))
+	}
+	database, table, prefix = strings.Trim(database, "`"), strings.Trim(table, "`"), strings.Trim(prefix, "`")
+
+	kind := strings.ToLower(strings.TrimSpace(input.Kind))
+	switch {
+	case kind == "" && table != "":
+		kind = identifierKindColumn
+	case kind == "" && database != "":
+		kind = identifierKindTable
+	case kind == "":
+		kind = identifierKindDatabase
+	case kind != identifierKindDatabase && kind != identifierKindTable && kind != identifierKindColumn:
+		return nil, SuggestIdentifiersOutput{}, fmt.Errorf("kind must be database, table or column, got %q", input.Kind)
+	}
+	if kind != identifierKindDatabase && database == "" {
+		return nil, SuggestIdentifiersOutput{}, fmt.Errorf("database is required to suggest %s names", kind)
+	}
+	if database != "" && kind != identifierKindDatabase {
+		if err := requireAllowedDatabase(database); err != nil {
+			return nil, SuggestIdentifiersOutput{}, err
+		}
+	}
+
+	limit := input.Limit
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	db := getDB()
+	var candidates []IdentifierSuggestion
+	var fetched time.Time
+	if kind == identifierKindDatabase {
+		names, at, err := identifierCatalog.databases(ctx, db)
+		if err != nil {
+			return nil, SuggestIdentifiersOutput{}, err
+		}
+		fetched = at
+		for _, name := range names {
+			candidates = append(candidates, IdentifierSuggestion{Kind: kind, Name: name, Qualified: name})
+		}
+	} else {
+		tables, at, err := identifierCatalog.tables(ctx, db, database)
+		if err != nil {
+			return nil, SuggestIdentifiersOutput{}, err
+		}
+		fetched = at
+		if len(tables) == 0 {
+			out.Notes = append(out.Notes, fmt.Sprintf("Database %s has no tables visible to this account, or does not exist.", database))
+		}
+		if kind == identifierKindColumn && table != "" {
+			t, ok := catalogTableNamed(tables, table)
+			if !ok {
+				// Suggest the table the caller probably meant instead.
+				out.Notes = append(out.Notes, fmt.Sprintf("Table %s.%s not found; suggesting tables like it.", database, table))
+				kind, prefix, table = identifierKindTable, table, ""
+			} else {
+				table = t.name
+				tables = []catalogTable{t}
+			}
+		}
+		for _, t := range tables {
+			if kind == identifierKindTable {
+				candidates = append(candidates, IdentifierSuggestion{Kind: kind, Name: t.name, Database: database, Qualified: database + "." + t.name})
+				continue
+			}
+			for _, c := range t.columns {
+				candidates = append(candidates, IdentifierSuggestion{
+					Kind: kind, Name: c, Database: database, Table: t.name, Qualified: database + "." + t.name + "." + c,
+				})
+			}
+		}
+	}
+
+	out.Prefix, out.Database, out.Table, out.Kind = prefix, database, table, kind
+	out.Suggestions = rankIdentifiers(prefix, candidates)
+	if len(out.Suggestions) > limit {
+		out.Suggestions, out.Truncated = out.Suggestions[:limit], true
+	}
+	out.CatalogAge = time.Since(fetched).Round(time.Second).Seconds()
+	return nil, out, nil
+}
+
+// rankIdentifiers keeps the candidates matching prefix, best first: by match
+// kind, edit distance, then name. An empty prefix matches every name.
+func rankIdentifiers(prefix string, candidates []IdentifierSuggestion) []IdentifierSuggestion {
+	matches := []IdentifierSuggestion{}
+	for _, c := range candidates {
+		match, distance, ok := matchIdentifier(prefix, c.Name)
+		if !ok {
+			continue
+		}
+		c.Match, c.Distance = match, distance
+		matches = append(matches, c)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if matchRank[a.Match] != matchRank[b.Match] {
+			return matchRank[a.Match] < matchRank[b.Match]
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if len(a.Name) != len(b.Name) {
+			return len(a.Name) < len(b.Name)
+		}
+		return strings.ToLower(a.Qualified) < strings.ToLower(b.Qualified)
+	})
+	return matches
+}
+
+// matchIdentifier tells how name matches prefix, ignoring case. A fuzzy
+// match compares prefix with the whole name and with its beginning, so a
+// typo in a partly typed name still finds it.
+func matchIdentifier(prefix, name string) (string, int, bool) {
+	p, n := strings.ToLower(prefix), strings.ToLower(name)
+	switch {
+	case p == n:
+		return matchExact, 0, true
+	case strings.HasPrefix(n, p):
+		return matchPrefix, 0, true
+	case strings.Contains(n, p):
+		return matchContains, 0, true
+	}
+	maxDistance := maxTypoDistance(p)
+	if maxDistance == 0 {
+		return "", 0, false
+	}
+	d := util.IdentifierDistance(p, n)
+	if r := []rune(n); len(r) > len([]rune(p)) {
+		d = min(d, util.IdentifierDistance(p, string(r[:len([]rune(p))])))
+	}
+	if d > maxDistance {
+		return "", 0, false
+	}
+	return matchFuzzy, d, true
+}
+
+// maxTypoDistance is the edit distance accepted as a typo of name: none for
+// names too short to tell a typo from another word, more for longer ones.
+func maxTypoDistance(name string) int {
+	switch n := len([]rune(name)); {
+	case n < 3:
+		return 0
+	case n <= 5:
+		return 1
+	case n <= 10:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// catalogTableNamed finds table among tables, preferring the exact spelling.
+func catalogTableNamed(tables []catalogTable, table string) (catalogTable, bool) {
+	var folded *catalogTable
+	for i := range tables {
+		if tables[i].name == table {
+			return tables[i], true
+		}
+		if folded == nil && strings.EqualFold(tables[i].name, table) {
+			folded = &tables[i]
+		}
+	}
+	if folded != nil {
+		return *folded, true
+	}
+	return catalogTable{}, false
+}
+
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+// pkg/mysqlmcp/tools_suggest_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMatchIdentifier(t *testing.T) {
+	tests := []struct {
+		prefix, name string
+		match        string
+		ok           bool
+	}{
+		{"orders", "Orders", matchExact, true},
+		{"ord", "orders", matchPrefix, true},
+		{"", "orders", matchPrefix, true},
+		{"item", "order_items", matchContains, true},
+		{"ordres", "orders", matchFuzzy, true},
+		{"custmer", "customer_id", matchFuzzy, true},
+		{"ab", "ba", "", false},
+		{"invoices", "orders", "", false},
+	}
+	for _, tt := range tests {
+		match, _, ok := matchIdentifier(tt.prefix, tt.name)
+		if ok != tt.ok || match != tt.match {
+			t.Errorf("matchIdentifier(%q, %q) = %q %v, want %q %v", tt.prefix, tt.name, match, ok, tt.match, tt.ok)
+		}
+	}
+}
+
+func TestToolSuggestIdentifiers(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	identifierCatalog = &schemaCatalog{entries: map[string]catalogEntry{}}
+	ctx := context.Background()
+
+	mock.ExpectQuery("FROM information_schema.COLUMNS").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME"}).
+			AddRow("customers", "id").AddRow("customers", "name").
+			AddRow("order_items", "order_id").AddRow("order_items", "qty").
+			AddRow("orders", "id").AddRow("orders", "customer_id").AddRow("orders", "created_at"))
+
+	_, out, err := toolSuggestIdentifiers(ctx, &mcp.CallToolRequest{}, SuggestIdentifiersInput{Prefix: "shop.ord"})
+	if err != nil {
+		t.Fatalf("suggest_identifiers: %v", err)
+	}
+	if out.Kind != identifierKindTable || out.Database != "shop" || len(out.Suggestions) != 2 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if s := out.Suggestions[0]; s.Name != "orders" || s.Match != matchPrefix || s.Qualified != "shop.orders" {
+		t.Errorf("expected orders first, got %+v", s)
+	}
+
+	// The catalog is cached: no second query.
+	_, out, err = toolSuggestIdentifiers(ctx, &mcp.CallToolRequest{}, SuggestIdentifiersInput{Database: "shop", Table: "orders", Prefix: "custmer"})
+	if err != nil {
+		t.Fatalf("suggest_identifiers: %v", err)
+	}
+	if len(out.Suggestions) != 1 || out.Suggestions[0].Name != "customer_id" || out.Suggestions[0].Match != matchFuzzy {
+		t.Errorf("expected a fuzzy customer_id, got %+v", out.Suggestions)
+	}
+
+	// An unknown table turns into table suggestions.
+	_, out, err = toolSuggestIdentifiers(ctx, &mcp.CallToolRequest{}, SuggestIdentifiersInput{Prefix: "shop.ordres.id"})
+	if err != nil {
+		t.Fatalf("suggest_identifiers: %v", err)
+	}
+	if out.Kind != identifierKindTable || len(out.Suggestions) == 0 || out.Suggestions[0].Name != "orders" || len(out.Notes) != 2 {
+		t.Errorf("expected orders as the table meant, got %+v", out)
+	}
+
+	_, out, err = toolSuggestIdentifiers(ctx, &mcp.CallToolRequest{}, SuggestIdentifiersInput{Database: "shop", Kind: "column", Prefix: "id", Limit: 1})
+	if err != nil {
+		t.Fatalf("suggest_identifiers: %v", err)
+	}
+	if len(out.Suggestions) != 1 || !out.Truncated || out.Suggestions[0].Qualified != "shop.customers.id" {
+		t.Errorf("unexpected column suggestions: %+v", out)
+	}
+
+	if _, _, err := toolSuggestIdentifiers(ctx, &mcp.CallToolRequest{}, SuggestIdentifiersInput{Kind: "column", Prefix: "id"}); err == nil {
+		t.Error("expected column suggestions without a database to fail")
+	}
+	if _, _, err := toolSuggestIdentifiers(ctx, &mcp.CallToolRequest{}, SuggestIdentifiersInput{Kind: "index"}); err == nil {
+		t.Error("expected an unknown kind to fail")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
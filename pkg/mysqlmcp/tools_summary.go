@@ -0,0 +1,280 @@
+// pkg/mysqlmcp/tools_summary.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Summary table detection for list_summary_tables: rollups declared in
+// summary_tables, plus tables whose names carry a rollup marker (agg_,
+// _summary, mv_, ...) or a time grain (_daily, _by_month, ...).
+const (
+	summaryDetectionConfig = "config"
+	summaryDetectionName   = "name"
+
+	// summaryFreshnessLimit caps the MAX() queries run by one call.
+	summaryFreshnessLimit = 20
+)
+
+var (
+	summaryMarkers = map[string]bool{
+		"agg": true, "aggr": true, "aggregate": true, "aggregates": true,
+		"summary": true, "summaries": true, "rollup": true, "rollups": true,
+		"mv": true, "mat": true, "materialized": true, "cube": true,
+	}
+	summaryGrains = map[string]string{
+		"hourly": "hourly", "hour": "hourly",
+		"daily": "daily", "day": "daily",
+		"weekly": "weekly", "week": "weekly",
+		"monthly": "monthly", "month": "monthly",
+		"quarterly": "quarterly", "quarter": "quarterly",
+		"yearly": "yearly", "annual": "yearly", "year": "yearly",
+	}
+	// summaryFreshnessColumns are tried in order when summary_tables does
+	// not name a freshness_column.
+	summaryFreshnessColumns = []string{"updated_at", "refreshed_at", "last_updated", "last_refreshed", "modified_at", "loaded_at", "created_at"}
+)
+
+// classifySummaryTable reports whether name looks like a rollup table, its
+// time grain when the name has one, and the name with the markers removed,
+// which is usually the fact table it was built from.
+func classifySummaryTable(name string) (grain, base string, ok bool) {
+	tokens := strings.Split(strings.ToLower(name), "_")
+	var rest []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case summaryMarkers[tok]:
+			ok = true
+		case summaryGrains[tok] != "" && (strings.HasSuffix(tok, "ly") || tok == "annual"):
+			grain, ok = summaryGrains[tok], true
+		case (tok == "by" || tok == "per") && i+1 < len(tokens) && summaryGrains[tokens[i+1]] != "":
+			grain, ok = summaryGrains[tokens[i+1]], true
+			i++
+		case tok != "":
+			rest = append(rest, tok)
+		}
+	}
+	if !ok || len(rest) == 0 {
+		return "", "", false
+	}
+	return grain, strings.Join(rest, "_"), true
+}
+
+// summarySource finds the table base names among tables, trying plural and
+// singular spellings.
+func summarySource(base string, tables map[string]string) string {
+	for _, candidate := range []string{base, base + "s", base + "es", strings.TrimSuffix(base, "s")} {
+		if name, ok := tables[candidate]; ok {
+			return name
+		}
+	}
+	return ""
+}
+
+type summaryTableStats struct {
+	rows, size int64
+	updateTime string
+}
+
+func toolListSummaryTables(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ListSummaryTablesInput,
+) (*mcp.CallToolResult, ListSummaryTablesOutput, error) {
+	if input.Database == "" {
+		return nil, ListSummaryTablesOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, ListSummaryTablesOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	rows, err := getDB().QueryContext(ctx, `
+		SELECT TABLE_NAME, TABLE_ROWS, DATA_LENGTH + INDEX_LENGTH, UPDATE_TIME
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'
+		ORDER BY TABLE_NAME`, input.Database)
+	if err != nil {
+		return nil, ListSummaryTablesOutput{}, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	tables := map[string]string{} // lower-case name -> name
+	stats := map[string]summaryTableStats{}
+	for rows.Next() {
+		var name string
+		var tableRows, size sql.NullInt64
+		var updated sql.NullString
+		if err := rows.Scan(&name, &tableRows, &size, &updated); err != nil {
+			return nil, ListSummaryTablesOutput{}, fmt.Errorf("failed to read tables: %w", err)
+		}
+		names = append(names, name)
+		tables[strings.ToLower(name)] = name
+		stats[name] = summaryTableStats{rows: tableRows.Int64, size: size.Int64, updateTime: updated.String}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, ListSummaryTablesOutput{}, fmt.Errorf("failed to read tables: %w", err)
+	}
+
+	found := map[string]*SummaryTable{}
+	if cfg != nil {
+		for _, st := range cfg.SummaryTables {
+			db, table, _ := strings.Cut(st.Table, ".")
+			if !strings.EqualFold(db, input.Database) {
+				continue
+			}
+			name, ok := tables[strings.ToLower(table)]
+			if !ok {
+				continue
+			}
+			source := st.Source
+			if sdb, stable, ok := strings.Cut(source, "."); ok && strings.EqualFold(sdb, input.Database) {
+				source = stable
+			}
+			found[name] = &SummaryTable{
+				Table:           name,
+				SourceTable:     source,
+				Grain:           st.Grain,
+				Detection:       summaryDetectionConfig,
+				Description:     st.Description,
+				FreshnessColumn: st.FreshnessColumn,
+			}
+		}
+	}
+	for _, name := range names {
+		if _, ok := found[name]; ok {
+			continue
+		}
+		if _, _, _, ghost := classifyGhostTable(name); ghost {
+			continue
+		}
+		grain, base, ok := classifySummaryTable(name)
+		if !ok {
+			continue
+		}
+		source := summarySource(base, tables)
+		if strings.EqualFold(source, name) {
+			source = ""
+		}
+		found[name] = &SummaryTable{Table: name, SourceTable: source, Grain: grain, Detection: summaryDetectionName}
+	}
+
+	out := ListSummaryTablesOutput{Database: input.Database, Tables: []SummaryTable{}}
+	for _, name := range names {
+		st, ok := found[name]
+		if !ok || (input.Table != "" && !strings.EqualFold(st.SourceTable, input.Table)) {
+			continue
+		}
+		s := stats[name]
+		st.Rows, st.SizeBytes, st.UpdateTime = s.rows, s.size, s.updateTime
+		if src, ok := stats[st.SourceTable]; ok {
+			st.SourceRows = src.rows
+		}
+		out.Tables = append(out.Tables, *st)
+	}
+	out.Count = len(out.Tables)
+
+	if err := summaryFreshness(ctx, input.Database, out.Tables); err != nil {
+		return nil, ListSummaryTablesOutput{}, err
+	}
+	if out.Count > summaryFreshnessLimit {
+		out.Notes = append(out.Notes, fmt.Sprintf("Freshness was checked for the first %d tables only.", summaryFreshnessLimit))
+	}
+	if out.Count > 0 {
+		out.Notes = append(out.Notes, "Prefer a summary table over its source when its grain and freshness answer the question; its rows are aggregates, not individual events. Tables found by name only are guesses: check with describe_table first.")
+	}
+	return nil, out, nil
+}
+
+// summaryFreshness fills in the freshness column, MAX() and age of the first
+// summaryFreshnessLimit tables. A failing MAX() leaves the table without
+// freshness rather than failing the call.
+func summaryFreshness(ctx context.Context, database string, tables []SummaryTable) error {
+	if len(tables) > summaryFreshnessLimit {
+		tables = tables[:summaryFreshnessLimit]
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	wanted := append([]string(nil), summaryFreshnessColumns...)
+	for _, st := range tables {
+		if c := strings.ToLower(st.FreshnessColumn); c != "" && !slices.Contains(wanted, c) {
+			wanted = append(wanted, c)
+		}
+	}
+	args := []interface{}{database}
+	for _, c := range wanted {
+		args = append(args, c)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(wanted)), ", ")
+	rows, err := getDB().QueryContext(ctx, `
+		SELECT TABLE_NAME, COLUMN_NAME
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ? AND LOWER(COLUMN_NAME) IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to read columns: %w", err)
+	}
+	columns := map[string]map[string]string{} // table -> lower-case column -> column
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read columns: %w", err)
+		}
+		if columns[table] == nil {
+			columns[table] = map[string]string{}
+		}
+		columns[table][strings.ToLower(column)] = column
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	for i := range tables {
+		st := &tables[i]
+		candidates := summaryFreshnessColumns
+		if st.FreshnessColumn != "" {
+			candidates = []string{st.FreshnessColumn}
+		}
+		st.FreshnessColumn = ""
+		for _, c := range candidates {
+			if name, ok := columns[st.Table][strings.ToLower(c)]; ok {
+				st.FreshnessColumn = name
+				break
+			}
+		}
+		if st.FreshnessColumn == "" {
+			continue
+		}
+		qdb, err1 := util.QuoteIdent(database)
+		qtable, err2 := util.QuoteIdent(st.Table)
+		column, err3 := util.QuoteIdent(st.FreshnessColumn)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		var last sql.NullString
+		var age sql.NullInt64
+		q := fmt.Sprintf("SELECT MAX(%s), TIMESTAMPDIFF(SECOND, MAX(%s), NOW()) FROM %s.%s", column, column, qdb, qtable)
+		if err := getDB().QueryRowContext(ctx, q).Scan(&last, &age); err != nil {
+			continue
+		}
+		st.LastUpdated = last.String
+		if age.Valid {
+			st.AgeSeconds = &age.Int64
+		}
+	}
+	return nil
+}
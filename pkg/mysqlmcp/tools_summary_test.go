@@ -0,0 +1,83 @@
+// pkg/mysqlmcp/tools_summary_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestClassifySummaryTable(t *testing.T) {
+	tests := []struct {
+		name, grain, base string
+		ok                bool
+	}{
+		{"orders_daily", "daily", "orders", true},
+		{"agg_sales_monthly", "monthly", "sales", true},
+		{"sales_by_day", "daily", "sales", true},
+		{"daily_order_summary", "daily", "order", true},
+		{"mv_revenue", "", "revenue", true},
+		{"pageviews_per_hour_rollup", "hourly", "pageviews", true},
+		{"orders", "", "", false},
+		{"day_parts", "", "", false},
+		{"summary", "", "", false},
+	}
+	for _, tc := range tests {
+		grain, base, ok := classifySummaryTable(tc.name)
+		if ok != tc.ok || grain != tc.grain || base != tc.base {
+			t.Errorf("classifySummaryTable(%q) = %q, %q, %v; want %q, %q, %v", tc.name, grain, base, ok, tc.grain, tc.base, tc.ok)
+		}
+	}
+}
+
+func TestToolListSummaryTables(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	oldCfg := cfg
+	cfg = &config.Config{SummaryTables: []config.SummaryTable{
+		{Table: "shop.store_revenue", Source: "shop.orders", Grain: "daily", FreshnessColumn: "refreshed_at"},
+	}}
+	defer func() { cfg = oldCfg }()
+
+	mock.ExpectQuery("FROM information_schema.TABLES").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "TABLE_ROWS", "SIZE", "UPDATE_TIME"}).
+			AddRow("_orders_daily_gho", 10, 16384, nil).
+			AddRow("order_summary", 30, 16384, nil).
+			AddRow("orders", 5000000, 268435456, "2026-10-15 09:00:00").
+			AddRow("orders_daily", 900, 65536, nil).
+			AddRow("store_revenue", 1200, 65536, nil))
+	mock.ExpectQuery("FROM information_schema.COLUMNS").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME"}).
+			AddRow("orders_daily", "updated_at").
+			AddRow("store_revenue", "refreshed_at").
+			AddRow("store_revenue", "updated_at"))
+	mock.ExpectQuery("SELECT MAX\\(`updated_at`\\), TIMESTAMPDIFF\\(SECOND, MAX\\(`updated_at`\\), NOW\\(\\)\\) FROM `shop`.`orders_daily`").
+		WillReturnRows(sqlmock.NewRows([]string{"max", "age"}).AddRow("2026-10-15 00:05:00", 3600))
+	mock.ExpectQuery("SELECT MAX\\(`refreshed_at`\\).* FROM `shop`.`store_revenue`").
+		WillReturnRows(sqlmock.NewRows([]string{"max", "age"}).AddRow("2026-10-14 00:00:00", 90000))
+
+	_, out, err := toolListSummaryTables(context.Background(), &mcp.CallToolRequest{}, ListSummaryTablesInput{Database: "shop", Table: "orders"})
+	if err != nil {
+		t.Fatalf("list_summary_tables: %v", err)
+	}
+	// order_summary has no freshness column and is still listed.
+	if out.Count != 3 || len(out.Notes) != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if s := out.Tables[0]; s.Table != "order_summary" || s.SourceTable != "orders" || s.LastUpdated != "" {
+		t.Errorf("unexpected order_summary: %+v", s)
+	}
+	if s := out.Tables[1]; s.Grain != "daily" || s.Detection != summaryDetectionName || s.SourceRows != 5000000 ||
+		s.FreshnessColumn != "updated_at" || s.AgeSeconds == nil || *s.AgeSeconds != 3600 {
+		t.Errorf("unexpected orders_daily: %+v", s)
+	}
+	if s := out.Tables[2]; s.Detection != summaryDetectionConfig || s.SourceTable != "orders" || s.FreshnessColumn != "refreshed_at" {
+		t.Errorf("unexpected store_revenue: %+v", s)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
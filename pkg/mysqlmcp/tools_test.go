@@ -1,16 +1,22 @@
-// cmd/mysql-mcp-server/tools_test.go
-package main
+// pkg/mysqlmcp/tools_test.go
+package mysqlmcp
 
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/askdba/mysql-mcp-server/internal/config"
 	"github.com/askdba/mysql-mcp-server/internal/dbretry"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/go-sql-driver/mysql"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -28,6 +34,16 @@ func setupMockDB(t *testing.T) (sqlmock.Sqlmock, func()) {
 	return result.mock, result.cleanup
 }
 
+// mockDSN names a default database so calls scoped to another database switch
+// back with USE `mockdb` rather than discarding the only sqlmock connection.
+const mockDSN = "mock@tcp(mock:3306)/mockdb"
+
+// expectRestoreDatabase expects the switch back to mockDSN's database that
+// ends every call scoped to another database.
+func expectRestoreDatabase(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("USE `mockdb`").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
 // setupMockDBFull returns the full mock result including the mock DB for tests
 // that need to add it to additional connection managers.
 func setupMockDBFull(t *testing.T) mockDBResult {
@@ -46,7 +62,7 @@ func setupMockDBFull(t *testing.T) mockDBResult {
 	// Set up mock connection manager with mock DB
 	cm := NewConnectionManager()
 	cm.connections["mock"] = mockDB
-	cm.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: "mock://test"}
+	cm.configs["mock"] = config.ConnectionConfig{Name: "mock", DSN: mockDSN}
 	cm.activeConn = "mock"
 	connManager = cm
 
@@ -61,6 +77,7 @@ func setupMockDBFull(t *testing.T) mockDBResult {
 		queryTimeout = oldQueryTimeout
 		pingTimeout = oldPingTimeout
 		dbRetryCfg = oldDBRetryCfg
+		preparedStmts.forget(mockDB)
 		mockDB.Close()
 	}
 
@@ -102,6 +119,34 @@ func TestToolListDatabases(t *testing.T) {
 	}
 }
 
+func TestToolListDatabasesPaging(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT SCHEMA_NAME FROM information_schema.SCHEMATA WHERE SCHEMA_NAME LIKE \\? ESCAPE '!' ORDER BY SCHEMA_NAME").
+		WithArgs("tenant!_%").
+		WillReturnRows(sqlmock.NewRows([]string{"schema_name"}).
+			AddRow("tenant_1").AddRow("tenant_2").AddRow("tenant_3").AddRow("tenant_4").AddRow("tenant_5"))
+
+	_, out, err := toolListDatabases(context.Background(), &mcp.CallToolRequest{}, ListDatabasesInput{Prefix: "tenant_", Offset: 2, Limit: 2})
+	if err != nil {
+		t.Fatalf("toolListDatabases failed: %v", err)
+	}
+	if out.Total != 5 || !out.HasMore || out.NextOffset == nil || *out.NextOffset != 4 {
+		t.Errorf("unexpected page: %+v", out)
+	}
+	if len(out.Databases) != 2 || out.Databases[0].Name != "tenant_3" || out.Databases[1].Name != "tenant_4" {
+		t.Errorf("unexpected databases: %+v", out.Databases)
+	}
+
+	if _, _, err := toolListDatabases(context.Background(), &mcp.CallToolRequest{}, ListDatabasesInput{Offset: -1}); err == nil {
+		t.Error("expected error for a negative offset")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestToolListTablesSuccess(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -113,7 +158,7 @@ func TestToolListTablesSuccess(t *testing.T) {
 		AddRow("products", "MyISAM", 50, "Products table")
 
 	mock.ExpectQuery(`(?s)SELECT\s+TABLE_NAME\s*,\s*ENGINE\s*,\s*TABLE_ROWS\s*,\s*TABLE_COMMENT\s+FROM\s+information_schema\.TABLES\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+ORDER\s+BY\s+TABLE_NAME`).
-		WithArgs("testdb").
+		WithArgs("testdb", 1001, 0).
 		WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -152,7 +197,7 @@ func TestToolListTablesMissingDatabase(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "TABLE_COMMENT"})
 	mock.ExpectQuery(`(?s)SELECT\s+TABLE_NAME\s*,\s*ENGINE\s*,\s*TABLE_ROWS\s*,\s*TABLE_COMMENT\s+FROM\s+information_schema\.TABLES\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+ORDER\s+BY\s+TABLE_NAME`).
-		WithArgs("missingdb").
+		WithArgs("missingdb", 1001, 0).
 		WillReturnRows(rows)
 
 	schemaRows := sqlmock.NewRows([]string{"1"})
@@ -182,7 +227,7 @@ func TestToolListTablesEmptySchemaReturnsEmpty(t *testing.T) {
 
 	rows := sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "TABLE_COMMENT"})
 	mock.ExpectQuery(`(?s)SELECT\s+TABLE_NAME\s*,\s*ENGINE\s*,\s*TABLE_ROWS\s*,\s*TABLE_COMMENT\s+FROM\s+information_schema\.TABLES\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+ORDER\s+BY\s+TABLE_NAME`).
-		WithArgs("emptydb").
+		WithArgs("emptydb", 1001, 0).
 		WillReturnRows(rows)
 
 	schemaRows := sqlmock.NewRows([]string{"1"}).AddRow(1)
@@ -211,7 +256,7 @@ func TestToolListTablesNullMetadata(t *testing.T) {
 	rows := sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "TABLE_COMMENT"}).
 		AddRow("audit_log", nil, nil, nil)
 	mock.ExpectQuery(`(?s)SELECT\s+TABLE_NAME\s*,\s*ENGINE\s*,\s*TABLE_ROWS\s*,\s*TABLE_COMMENT\s+FROM\s+information_schema\.TABLES\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+ORDER\s+BY\s+TABLE_NAME`).
-		WithArgs("testdb").
+		WithArgs("testdb", 1001, 0).
 		WillReturnRows(rows)
 
 	ctx := context.Background()
@@ -237,6 +282,59 @@ func TestToolListTablesNullMetadata(t *testing.T) {
 	}
 }
 
+func TestToolListTablesPatternMetadataAndPaging(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"TABLE_NAME", "ENGINE", "TABLE_ROWS", "TABLE_COMMENT",
+		"TABLE_TYPE", "CREATE_TIME", "UPDATE_TIME", "DATA_MB", "INDEX_MB"}).
+		AddRow("order_items", "InnoDB", 10, "", "BASE TABLE", "2024-01-01 00:00:00", nil, 1.5, 0.25).
+		AddRow("orders", "InnoDB", 5, "", "BASE TABLE", "2024-01-01 00:00:00", "2024-02-01 00:00:00", 0.02, 0.02).
+		AddRow("orders_view", nil, nil, "VIEW", "VIEW", nil, nil, nil, nil)
+	mock.ExpectQuery(`(?s)SELECT\s+TABLE_NAME\s*,\s*ENGINE\s*,\s*TABLE_ROWS\s*,\s*TABLE_COMMENT\s*,\s*TABLE_TYPE\s*,\s*CREATE_TIME\s*,\s*UPDATE_TIME.*WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s+LIKE\s+\?\s+ORDER\s+BY\s+TABLE_NAME\s+LIMIT\s+\?\s+OFFSET\s+\?`).
+		WithArgs("testdb", "order%", 3, 2).
+		WillReturnRows(rows)
+
+	ctx := context.Background()
+	_, output, err := toolListTables(ctx, &mcp.CallToolRequest{}, ListTablesInput{
+		Database:        "testdb",
+		Pattern:         "order%",
+		IncludeMetadata: true,
+		Offset:          2,
+		Limit:           2,
+	})
+	if err != nil {
+		t.Fatalf("toolListTables failed: %v", err)
+	}
+	if len(output.Tables) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(output.Tables))
+	}
+	if !output.HasMore || output.NextOffset == nil || *output.NextOffset != 4 {
+		t.Errorf("expected has_more with next_offset 4, got %v %v", output.HasMore, output.NextOffset)
+	}
+	first := output.Tables[0]
+	if first.Type != "BASE TABLE" || first.CreatedAt == "" || first.UpdatedAt != "" {
+		t.Errorf("unexpected metadata: %+v", first)
+	}
+	if first.DataMB == nil || *first.DataMB != 1.5 || first.IndexMB == nil || *first.IndexMB != 0.25 {
+		t.Errorf("unexpected sizes: %v %v", first.DataMB, first.IndexMB)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolListTablesNegativeOffset(t *testing.T) {
+	_, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	_, _, err := toolListTables(context.Background(), &mcp.CallToolRequest{}, ListTablesInput{Database: "testdb", Offset: -1})
+	if err == nil {
+		t.Fatal("expected error for negative offset")
+	}
+}
+
 func TestToolListTablesEmptyDatabase(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -260,13 +358,14 @@ func TestToolDescribeTableSuccess(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
-	// New query fetches 8 columns: COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, COLLATION_NAME
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"}).
-		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil).
-		AddRow("name", "varchar(255)", "YES", "UNI", nil, "", "User name", "utf8mb4_unicode_ci").
-		AddRow("email", "varchar(255)", "YES", "", nil, "", "", "utf8mb4_unicode_ci")
+	// New query fetches 9 columns: COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, COLLATION_NAME, GENERATION_EXPRESSION
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil, nil).
+		AddRow("name", "varchar(255)", "YES", "UNI", nil, "", "User name", "utf8mb4_unicode_ci", nil).
+		AddRow("email", "varchar(255)", "YES", "", nil, "", "", "utf8mb4_unicode_ci", nil).
+		AddRow("email_domain", "varchar(255)", "YES", "", nil, "VIRTUAL GENERATED", "", "utf8mb4_unicode_ci", "substring_index(`email`,_utf8mb4'@',-(1))")
 
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("testdb", "users").
 		WillReturnRows(rows)
 
@@ -280,8 +379,8 @@ func TestToolDescribeTableSuccess(t *testing.T) {
 		t.Fatalf("toolDescribeTable failed: %v", err)
 	}
 
-	if len(output.Columns) != 3 {
-		t.Errorf("expected 3 columns, got %d", len(output.Columns))
+	if len(output.Columns) != 4 {
+		t.Fatalf("expected 4 columns, got %d", len(output.Columns))
 	}
 
 	// Check first column
@@ -294,6 +393,12 @@ func TestToolDescribeTableSuccess(t *testing.T) {
 	if output.Columns[0].Key != "PRI" {
 		t.Errorf("expected key 'PRI', got '%s'", output.Columns[0].Key)
 	}
+	if output.Columns[0].IsGenerated || output.Columns[0].GenerationExpression != "" {
+		t.Errorf("expected 'id' not to be generated, got %+v", output.Columns[0])
+	}
+	if gen := output.Columns[3]; !gen.IsGenerated || !strings.HasPrefix(gen.GenerationExpression, "substring_index") {
+		t.Errorf("expected generated column 'email_domain', got %+v", gen)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("unfulfilled expectations: %v", err)
@@ -304,8 +409,8 @@ func TestToolDescribeTableNonExistentTable(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"})
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"})
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("testdb", "missing").
 		WillReturnRows(rows)
 
@@ -340,8 +445,8 @@ func TestToolDescribeTableNoColumnsFound(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"})
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"})
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("testdb", "empty_table").
 		WillReturnRows(rows)
 
@@ -371,8 +476,8 @@ func TestToolDescribeTableDatabaseNotFound(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"})
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"})
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("missingdb", "users").
 		WillReturnRows(rows)
 
@@ -408,12 +513,12 @@ func TestToolDescribeTableWithNullCollation(t *testing.T) {
 	defer cleanup()
 
 	// MySQL 8.4+ returns NULL for Collation on non-string columns
-	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME"}).
-		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil).
-		AddRow("created_at", "timestamp", "YES", "", nil, "", "", nil).
-		AddRow("name", "varchar(255)", "NO", "", nil, "", "User name", "utf8mb4_unicode_ci")
+	rows := sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT", "COLLATION_NAME", "GENERATION_EXPRESSION"}).
+		AddRow("id", "int", "NO", "PRI", nil, "auto_increment", "", nil, nil).
+		AddRow("created_at", "timestamp", "YES", "", nil, "", "", nil, nil).
+		AddRow("name", "varchar(255)", "NO", "", nil, "", "User name", "utf8mb4_unicode_ci", nil)
 
-	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
+	mock.ExpectQuery(`(?s)SELECT\s+COLUMN_NAME\s*,\s*COLUMN_TYPE\s*,\s*IS_NULLABLE\s*,\s*COLUMN_KEY\s*,\s*COLUMN_DEFAULT\s*,\s*EXTRA\s*,\s*COLUMN_COMMENT\s*,\s*COLLATION_NAME\s*,\s*GENERATION_EXPRESSION\s+FROM\s+information_schema\.COLUMNS\s+WHERE\s+TABLE_SCHEMA\s*=\s*\?\s+AND\s+TABLE_NAME\s*=\s*\?\s+ORDER\s+BY\s+ORDINAL_POSITION`).
 		WithArgs("testdb", "users").
 		WillReturnRows(rows)
 
@@ -520,6 +625,150 @@ func TestToolRunQuerySelectSuccess(t *testing.T) {
 	}
 }
 
+func TestToolRunQueryMaxExecutionTimeHint(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	oldCfg := cfg
+	defer func() { cfg = oldCfg }()
+	run := func() {
+		t.Helper()
+		if _, _, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t"}); err != nil {
+			t.Fatalf("run_query: %v", err)
+		}
+	}
+
+	// MySQL gets the hint, bounded by the 30s query timeout.
+	connManager.serverTypes["mock"] = ServerTypeMySQL
+	mock.ExpectQuery(`^SELECT /\*\+ MAX_EXECUTION_TIME\((29\d{3}|30000)\) \*/ id FROM t LIMIT \d+$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	run()
+
+	// MariaDB and a disabled hint run the SQL as written.
+	connManager.serverTypes["mock"] = ServerTypeMariaDB
+	mock.ExpectQuery(`^SELECT id FROM t LIMIT \d+$`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	run()
+	connManager.serverTypes["mock"] = ServerTypeMySQL
+	cfg = &config.Config{InjectLimit: true}
+	mock.ExpectQuery(`^SELECT id FROM t LIMIT \d+$`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	run()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolRunQueryReportsRetries(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	dbRetryCfg = dbretry.Config{MaxRetries: 2, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+
+	mock.ExpectQuery("SELECT id FROM orders").
+		WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+	mock.ExpectQuery("SELECT id FROM orders").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, out, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM orders"})
+	if err != nil {
+		t.Fatalf("toolRunQuery failed: %v", err)
+	}
+	if out.Retries != 1 || out.RetryReason != "deadlock (1213)" || len(out.Rows) != 1 {
+		t.Errorf("unexpected retry metadata: retries=%d reason=%q rows=%d", out.Retries, out.RetryReason, len(out.Rows))
+	}
+
+	mock.ExpectQuery("SELECT id FROM orders").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	_, out, err = toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM orders"})
+	if err != nil || out.Retries != 0 || out.RetryReason != "" {
+		t.Errorf("expected no retry metadata, got %d %q (%v)", out.Retries, out.RetryReason, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolRunQueryAuditIncludesDigest(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	oldAudit := auditLogger
+	var err error
+	auditLogger, err = NewAuditLogger(logPath)
+	if err != nil {
+		t.Fatalf("NewAuditLogger failed: %v", err)
+	}
+	defer func() {
+		auditLogger.Close()
+		auditLogger = oldAudit
+	}()
+
+	mock.ExpectQuery("SELECT id FROM users WHERE id = 7").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	if _, _, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{
+		SQL: "SELECT id FROM users WHERE id = 7",
+	}); err != nil {
+		t.Fatalf("toolRunQuery failed: %v", err)
+	}
+	auditLogger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	var entry AuditEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if want := util.QueryDigest("SELECT id FROM users WHERE id = 99"); entry.QueryDigest != want {
+		t.Errorf("expected digest %s, got %q", want, entry.QueryDigest)
+	}
+}
+
+func TestToolRunQueryAuditRedactsLiterals(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	oldSettings := currentLogSettings.Load()
+	defer currentLogSettings.Store(oldSettings)
+	if err := configureLogging(&config.Config{LogRedactSQL: true}); err != nil {
+		t.Fatalf("configureLogging failed: %v", err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+	oldAudit := auditLogger
+	var err error
+	auditLogger, err = NewAuditLogger(logPath)
+	if err != nil {
+		t.Fatalf("NewAuditLogger failed: %v", err)
+	}
+	defer func() {
+		auditLogger.Close()
+		auditLogger = oldAudit
+	}()
+
+	sqlText := "SELECT id FROM users WHERE email = 'alice@example.com'"
+	mock.ExpectQuery("SELECT id FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	if _, _, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: sqlText}); err != nil {
+		t.Fatalf("toolRunQuery failed: %v", err)
+	}
+	auditLogger.Close()
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	var entry AuditEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("failed to parse audit entry: %v", err)
+	}
+	if strings.Contains(entry.Query, "alice") || !strings.Contains(entry.Query, "email = ?") {
+		t.Errorf("expected a redacted query, got %q", entry.Query)
+	}
+	if entry.QueryDigest != util.QueryDigest(sqlText) {
+		t.Errorf("expected the digest of the original query, got %q", entry.QueryDigest)
+	}
+}
+
 func TestToolRunQueryEmptySQL(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
@@ -607,6 +856,7 @@ func TestToolRunQueryWithDatabase(t *testing.T) {
 		AddRow(1, "Alice")
 	mock.ExpectExec("USE `testdb`").WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectQuery("SELECT \\* FROM users").WillReturnRows(rows)
+	expectRestoreDatabase(mock)
 
 	ctx := context.Background()
 	_, output, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{
@@ -742,6 +992,37 @@ func TestToolListConnectionsSuccess(t *testing.T) {
 	_ = result.mock
 }
 
+func TestToolPoolStats(t *testing.T) {
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+
+	cm := NewConnectionManager()
+	cm.connections["replica"] = result.mockDB
+	cm.connections["primary"] = result.mockDB
+	cm.activeConn = "replica"
+	connManager = cm
+	result.mockDB.SetMaxOpenConns(7)
+
+	_, output, err := toolPoolStats(context.Background(), &mcp.CallToolRequest{}, PoolStatsInput{})
+	if err != nil {
+		t.Fatalf("toolPoolStats failed: %v", err)
+	}
+	if len(output.Pools) != 2 || output.Active != "replica" {
+		t.Fatalf("unexpected output: %+v", output)
+	}
+	if output.Pools[0].Name != "primary" || output.Pools[0].Active || !output.Pools[1].Active {
+		t.Errorf("expected pools sorted by name with active flag, got %+v", output.Pools)
+	}
+	if output.Pools[0].MaxOpen != 7 {
+		t.Errorf("expected max_open 7, got %d", output.Pools[0].MaxOpen)
+	}
+
+	connManager = nil
+	if _, _, err := toolPoolStats(context.Background(), &mcp.CallToolRequest{}, PoolStatsInput{}); err == nil {
+		t.Error("expected error when connManager is nil")
+	}
+}
+
 func TestToolUseConnectionNoManager(t *testing.T) {
 	oldConnManager := connManager
 	defer func() { connManager = oldConnManager }()
@@ -839,6 +1120,33 @@ func TestToolUseConnectionNotFound(t *testing.T) {
 
 	_ = result.mock
 }
+func TestToolServerInfoPercona(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("SELECT VERSION\\(\\)").WillReturnRows(sqlmock.NewRows([]string{"VERSION()"}).AddRow("8.0.36-28"))
+	mock.ExpectQuery("FROM performance_schema.global_variables").WillReturnRows(
+		sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).
+			AddRow("version_comment", "Percona Server (GPL), Release 28").
+			AddRow("thread_handling", "pool-of-threads").
+			AddRow("audit_log_policy", "ALL"))
+	mock.ExpectQuery("FROM performance_schema.global_status").WillReturnRows(
+		sqlmock.NewRows([]string{"VARIABLE_NAME", "VARIABLE_VALUE"}).AddRow("Uptime", "60"))
+	mock.ExpectQuery("SELECT CURRENT_USER\\(\\)").WillReturnRows(
+		sqlmock.NewRows([]string{"CURRENT_USER()", "DATABASE()"}).AddRow("root@localhost", ""))
+
+	_, out, err := toolServerInfo(context.Background(), &mcp.CallToolRequest{}, ServerInfoInput{})
+	if err != nil {
+		t.Fatalf("server_info: %v", err)
+	}
+	if out.Percona == nil || !out.Percona.ThreadPool || !out.Percona.AuditLog {
+		t.Errorf("expected Percona thread pool and audit log, got %+v", out.Percona)
+	}
+	if out.HeatWave != nil {
+		t.Errorf("expected no HeatWave info, got %+v", out.HeatWave)
+	}
+}
+
 func TestToolServerInfoFallback(t *testing.T) {
 	result := setupMockDBFull(t)
 	defer result.cleanup()
@@ -1093,6 +1401,67 @@ func TestToolRunQueryLimitNotInjectedWhenPresent(t *testing.T) {
 	}
 }
 
+func TestToolRunQueryLimitInjectionDisabled(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	oldCfg, oldMaxRows := cfg, maxRows
+	cfg = &config.Config{InjectLimit: false}
+	maxRows = 2
+	defer func() { cfg, maxRows = oldCfg, oldMaxRows }()
+
+	rows := sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3)
+	// Without injection the SQL runs as written and the cap is enforced client-side.
+	mock.ExpectQuery("^SELECT id FROM t$").WillReturnRows(rows)
+
+	_, output, err := toolRunQuery(context.Background(), &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(output.Rows) != 2 || !output.Truncated {
+		t.Errorf("expected 2 rows and Truncated=true, got %d rows truncated=%v", len(output.Rows), output.Truncated)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolRunQueryPerDatabaseRowLimit(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	oldCfg := cfg
+	cfg = &config.Config{InjectLimit: true, DatabaseMaxRows: map[string]int{"Analytics": 5}}
+	defer func() { cfg = oldCfg }()
+
+	mock.ExpectExec("USE `analytics`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t LIMIT 5").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectRestoreDatabase(mock)
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t LIMIT 3").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectRestoreDatabase(mock)
+	mock.ExpectExec("USE `shop`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id FROM t LIMIT 1000").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expectRestoreDatabase(mock)
+
+	ctx := context.Background()
+	// The per-database cap replaces maxRows (1000) for that database...
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t", Database: "analytics"}); err != nil {
+		t.Fatalf("analytics query failed: %v", err)
+	}
+	// ...while max_rows can still lower the cap elsewhere.
+	three := 3
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t", Database: "shop", MaxRows: &three}); err != nil {
+		t.Fatalf("shop query failed: %v", err)
+	}
+	if _, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM t", Database: "shop"}); err != nil {
+		t.Fatalf("shop query failed: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestToolRunQueryOffsetPaginationHasMore(t *testing.T) {
 	mock, cleanup := setupMockDB(t)
 	defer cleanup()
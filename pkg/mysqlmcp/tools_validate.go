@@ -0,0 +1,234 @@
+// pkg/mysqlmcp/tools_validate.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Stages reported by validate_query when a query would be rejected.
+const (
+	validateStageInput      = "input"
+	validateStageValidation = "validation"
+	validateStageAccess     = "access"
+	validateStageExplain    = "explain"
+)
+
+// toolValidateQuery runs run_query's checks and an EXPLAIN without executing
+// the statement. Rejections are reported in the output (valid=false) rather
+// than as tool errors so agents can read the reason and retry.
+func toolValidateQuery(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ValidateQueryInput,
+) (*mcp.CallToolResult, ValidateQueryOutput, error) {
+	sqlText := strings.TrimSpace(input.SQL)
+	database := strings.TrimSpace(input.Database)
+	out := ValidateQueryOutput{Tables: []string{}}
+
+	reject := func(stage string, err error) (*mcp.CallToolResult, ValidateQueryOutput, error) {
+		out.Valid = false
+		out.Stage = stage
+		out.Error = err.Error()
+		return nil, out, nil
+	}
+
+	if sqlText == "" {
+		return reject(validateStageInput, fmt.Errorf("sql is required"))
+	}
+	if accessControlEnabled() {
+		if database == "" {
+			return reject(validateStageAccess, fmt.Errorf("database is required when MYSQL_MCP_ALLOWED_DATABASES is set"))
+		}
+		if err := requireAllowedDatabase(database); err != nil {
+			return reject(validateStageAccess, err)
+		}
+	}
+
+	if err := validateSQL(sqlText); err != nil {
+		return reject(validateStageValidation, fmt.Errorf("query validation failed: %w", err))
+	}
+	if err := requireReferencedSchemasInQuery(sqlText); err != nil {
+		return reject(validateStageAccess, err)
+	}
+	execSQL, err := applyRowPolicies(sqlText, database)
+	if err != nil {
+		return reject(validateStageAccess, err)
+	}
+
+	normalized := util.NormalizeQuery(sqlText)
+	out.StatementType = normalized.StatementType
+	out.Tables = append(out.Tables, normalized.Tables...)
+	out.RowCap = defaultRowLimit(database)
+	out.FinalSQL = execSQL
+	if cfg == nil || cfg.InjectLimit {
+		out.FinalSQL = util.InjectLimit(execSQL, out.RowCap)
+	}
+	if util.HasSelectStar(sqlText) {
+		out.Warnings = append(out.Warnings, "SELECT * returns every column; list the columns you need to reduce output size.")
+	}
+	if err := requireConfirmation(false); err != nil {
+		out.Notes = append(out.Notes, "run_query will need confirm=true: "+err.Error())
+	}
+
+	if !explainable(sqlText) {
+		out.Valid = true
+		out.Notes = append(out.Notes, "Only SELECT statements are explained; no plan or cost for this statement type.")
+		return nil, out, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	plan, err := runExplain(ctx, database, execSQL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ValidateQueryOutput{}, err
+		}
+		return reject(validateStageExplain, err)
+	}
+	out.Valid = true
+	estimated, examined, _ := planRowEstimates(plan)
+	out.EstimatedRows = &estimated
+	out.RowsExamined = &examined
+	out.Recommendation = rowRecommendation(estimated, out.RowCap)
+	out.Warnings = append(out.Warnings, analyzeExplainPlan(plan)...)
+
+	if getServerType() != ServerTypeMariaDB {
+		if cost, ok := explainQueryCost(ctx, database, execSQL); ok {
+			out.QueryCost = &cost
+		}
+	}
+	if out.QueryCost == nil {
+		out.Notes = append(out.Notes, "Optimizer cost is unavailable on this server; use estimated_rows and rows_examined.")
+	}
+	return nil, out, nil
+}
+
+// explainable reports whether sqlText is a SELECT (or a parenthesized/UNION
+// SELECT, or a WITH query) that can be prefixed with EXPLAIN.
+func explainable(sqlText string) bool {
+	s := strings.TrimLeft(sqlText, "( \t\r\n")
+	return (len(s) >= 6 && strings.EqualFold(s[:6], "SELECT")) ||
+		(len(s) > 4 && strings.EqualFold(s[:4], "WITH") && strings.TrimSpace(s[4:5]) == "")
+}
+
+// explainQueryCost returns query_block.cost_info.query_cost from
+// EXPLAIN FORMAT=JSON (MySQL 5.7+).
+func explainQueryCost(ctx context.Context, database, sqlText string) (float64, bool) {
+	plan, err := runExplainStatement(ctx, database, "EXPLAIN FORMAT=JSON", sqlText)
+	if err != nil || len(plan) == 0 {
+		return 0, false
+	}
+	var doc string
+	for _, v := range plan[0] {
+		if s, ok := v.(string); ok && strings.HasPrefix(strings.TrimSpace(s), "{") {
+			doc = s
+			break
+		}
+	}
+	var parsed struct {
+		QueryBlock struct {
+			CostInfo struct {
+				QueryCost string `json:"query_cost"`
+			} `json:"cost_info"`
+		} `json:"query_block"`
+	}
+	if doc == "" || json.Unmarshal([]byte(doc), &parsed) != nil {
+		return 0, false
+	}
+	cost, err := strconv.ParseFloat(parsed.QueryBlock.CostInfo.QueryCost, 64)
+	if err != nil {
+		return 0, false
+	}
+	return cost, true
+}
+
+// validationHints tells an agent how to get past each rule of explain_validation.
+var validationHints = map[string]string{
+	"empty":                "Pass the query in sql.",
+	"single_statement":     "Send one statement per call, without ';' between statements.",
+	"syntax":               "Fix the SQL syntax; only SELECT (with WITH, UNION, INTERSECT, EXCEPT and window functions), SHOW, DESCRIBE and EXPLAIN are understood.",
+	"statement_type":       "Only read-only statements run: SELECT, WITH ... SELECT, SHOW, DESCRIBE and EXPLAIN.",
+	"allowed_prefix":       "Start the query with SELECT, WITH, SHOW, DESCRIBE or EXPLAIN.",
+	"dangerous_function":   "Remove SLEEP, BENCHMARK and the GET_LOCK family of functions.",
+	"system_schema":        "Use list_databases, list_tables, describe_table or the extended introspection tools instead of querying mysql, information_schema, performance_schema or sys.",
+	"comment":              "Remove SQL comments (--, /* */).",
+	"file_access":          "Files cannot be read or written; drop LOAD_FILE, LOAD DATA and INTO OUTFILE/DUMPFILE and return the rows instead.",
+	"data_modifying_cte":   "CTE bodies and the statement after WITH must be SELECTs.",
+	"database_required":    "Pass database; it is required when MYSQL_MCP_ALLOWED_DATABASES is set.",
+	"database_not_allowed": "Use a database returned by list_databases.",
+	"schema_not_allowed":   "Qualify tables only with databases returned by list_databases.",
+	"show_databases":       "Use the list_databases tool.",
+}
+
+// toolExplainValidation reports every validation and access rule a query
+// breaks, without connecting to MySQL.
+func toolExplainValidation(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ExplainValidationInput,
+) (*mcp.CallToolResult, ExplainValidationOutput, error) {
+	sqlText := strings.TrimSpace(input.SQL)
+	database := strings.TrimSpace(input.Database)
+	out := ExplainValidationOutput{Triggered: []ValidationFinding{}}
+	add := func(f ValidationFinding) {
+		f.Hint = validationHints[f.Rule]
+		out.Triggered = append(out.Triggered, f)
+	}
+
+	for _, v := range util.ExplainValidation(sqlText) {
+		add(ValidationFinding{Layer: v.Layer, Rule: v.Rule, Message: v.Message, Pattern: v.Pattern})
+	}
+
+	if accessControlEnabled() {
+		if database == "" {
+			add(ValidationFinding{Layer: validateStageAccess, Rule: "database_required",
+				Message: "database is required when MYSQL_MCP_ALLOWED_DATABASES is set"})
+		} else if err := requireAllowedDatabase(database); err != nil {
+			add(ValidationFinding{Layer: validateStageAccess, Rule: "database_not_allowed", Message: err.Error()})
+		}
+		if sqlText != "" && util.ShowEnumeratesAllSchemasInQuery(sqlText) {
+			add(ValidationFinding{Layer: validateStageAccess, Rule: "show_databases",
+				Message: "SHOW DATABASES is not allowed when MYSQL_MCP_ALLOWED_DATABASES is set"})
+		}
+		if refs, err := util.ReferencedSchemaQualifiers(sqlText); err == nil {
+			names := make([]string, 0, len(refs))
+			for name := range refs {
+				if !databaseAllowed(name) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				add(ValidationFinding{Layer: validateStageAccess, Rule: "schema_not_allowed",
+					Message: fmt.Sprintf("query references database %q which is not in MYSQL_MCP_ALLOWED_DATABASES", name)})
+			}
+		}
+	}
+
+	out.Allowed = len(out.Triggered) == 0
+	if err := requireConfirmation(false); err != nil {
+		out.Notes = append(out.Notes, "run_query will need confirm=true: "+err.Error())
+	}
+	return nil, out, nil
+}
+
+// validateSQL runs the parser and regex validators on sqlText. At debug level
+// the validator component logs every statement checked and the outcome.
+func validateSQL(sqlText string) error {
+	err := util.ValidateSQLCombined(sqlText)
+	if err != nil {
+		validatorLog.DebugSQL("query rejected", sqlText, map[string]interface{}{"error": err.Error()})
+	} else {
+		validatorLog.DebugSQL("query accepted", sqlText, nil)
+	}
+	return err
+}
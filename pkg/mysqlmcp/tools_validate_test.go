@@ -0,0 +1,172 @@
+// pkg/mysqlmcp/tools_validate_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolValidateQuery(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("EXPLAIN SELECT").
+		WillReturnRows(sqlmock.NewRows(explainColumns()).
+			AddRow(1, "SIMPLE", "o", nil, "ALL", nil, nil, nil, nil, 5000, 10.0, "Using where"))
+	expectRestoreDatabase(mock)
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("EXPLAIN FORMAT=JSON SELECT").
+		WillReturnRows(sqlmock.NewRows([]string{"EXPLAIN"}).
+			AddRow(`{"query_block": {"select_id": 1, "cost_info": {"query_cost": "512.75"}}}`))
+	expectRestoreDatabase(mock)
+
+	_, out, err := toolValidateQuery(context.Background(), &mcp.CallToolRequest{}, ValidateQueryInput{
+		SQL:      "SELECT * FROM orders o WHERE o.total > 100",
+		Database: "app",
+	})
+	if err != nil {
+		t.Fatalf("toolValidateQuery failed: %v", err)
+	}
+	if !out.Valid || out.StatementType != "select" || len(out.Tables) != 1 || out.Tables[0] != "orders" {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if out.FinalSQL != "SELECT * FROM orders o WHERE o.total > 100 LIMIT 1000" {
+		t.Errorf("unexpected final_sql: %s", out.FinalSQL)
+	}
+	if out.EstimatedRows == nil || *out.EstimatedRows != 500 || out.Recommendation != "run" {
+		t.Errorf("unexpected estimate: %v %q", out.EstimatedRows, out.Recommendation)
+	}
+	if out.QueryCost == nil || *out.QueryCost != 512.75 {
+		t.Errorf("unexpected query_cost: %v", out.QueryCost)
+	}
+	if len(out.Warnings) < 2 {
+		t.Errorf("expected SELECT * and full scan warnings, got %v", out.Warnings)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolValidateQueryRejections(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	mock.ExpectQuery("EXPLAIN SELECT").WillReturnError(errors.New("Table 'app.nope' doesn't exist"))
+
+	tests := []struct {
+		sql   string
+		stage string
+	}{
+		{"", validateStageInput},
+		{"DELETE FROM orders", validateStageValidation},
+		{"SELECT 1; DROP TABLE orders", validateStageValidation},
+		{"WITH t AS (DELETE FROM orders) SELECT * FROM t", validateStageValidation},
+		{"SELECT id FROM nope", validateStageExplain},
+	}
+	for _, tt := range tests {
+		_, out, err := toolValidateQuery(context.Background(), &mcp.CallToolRequest{}, ValidateQueryInput{SQL: tt.sql})
+		if err != nil {
+			t.Fatalf("%q: unexpected tool error: %v", tt.sql, err)
+		}
+		if out.Valid || out.Stage != tt.stage || out.Error == "" {
+			t.Errorf("%q: expected rejection at %s, got %+v", tt.sql, tt.stage, out)
+		}
+	}
+
+	_, out, _ := toolValidateQuery(context.Background(), &mcp.CallToolRequest{}, ValidateQueryInput{SQL: "SHOW TABLES"})
+	if !out.Valid || len(out.Notes) == 0 || out.EstimatedRows != nil {
+		t.Errorf("expected SHOW to validate without a plan: %+v", out)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestExplainable(t *testing.T) {
+	for sql, want := range map[string]bool{
+		"SELECT 1":                              true,
+		"(SELECT 1) UNION (SELECT 2)":           true,
+		"WITH t AS (SELECT 1) SELECT * FROM t":  true,
+		"with\nt as (select 1) select * from t": true,
+		"WITHOUT":                               false,
+		"SHOW TABLES":                           false,
+	} {
+		if got := explainable(sql); got != want {
+			t.Errorf("explainable(%q) = %v, want %v", sql, got, want)
+		}
+	}
+}
+
+func TestToolExplainValidation(t *testing.T) {
+	_, out, err := toolExplainValidation(context.Background(), &mcp.CallToolRequest{}, ExplainValidationInput{SQL: "SELECT id FROM orders"})
+	if err != nil {
+		t.Fatalf("toolExplainValidation failed: %v", err)
+	}
+	if !out.Allowed || len(out.Triggered) != 0 {
+		t.Errorf("expected a clean SELECT to pass: %+v", out)
+	}
+
+	_, out, _ = toolExplainValidation(context.Background(), &mcp.CallToolRequest{}, ExplainValidationInput{
+		SQL: "SELECT SLEEP(1) FROM mysql.user; DROP TABLE t",
+	})
+	if out.Allowed {
+		t.Fatal("expected the query to be rejected")
+	}
+	rules := map[string]ValidationFinding{}
+	for _, f := range out.Triggered {
+		rules[f.Layer+"/"+f.Rule] = f
+	}
+	for _, want := range []string{"multi_statement/single_statement", "parser/dangerous_function", "parser/statement_type", "regex/dangerous_function", "regex/system_schema"} {
+		f, ok := rules[want]
+		if !ok {
+			t.Errorf("expected %s among %+v", want, out.Triggered)
+			continue
+		}
+		if f.Hint == "" {
+			t.Errorf("%s has no hint", want)
+		}
+	}
+	if f := rules["regex/system_schema"]; f.Pattern == "" {
+		t.Errorf("regex finding should carry its pattern: %+v", f)
+	}
+}
+
+func TestToolExplainValidationAccess(t *testing.T) {
+	t.Cleanup(func() { initAccessControl(nil) })
+	initAccessControl([]string{"app"})
+
+	_, out, _ := toolExplainValidation(context.Background(), &mcp.CallToolRequest{}, ExplainValidationInput{
+		SQL: "SELECT * FROM billing.invoices JOIN hr.people",
+	})
+	var rules []string
+	for _, f := range out.Triggered {
+		if f.Layer == validateStageAccess {
+			rules = append(rules, f.Rule+":"+f.Message)
+		}
+	}
+	want := []string{
+		"database_required:database is required when MYSQL_MCP_ALLOWED_DATABASES is set",
+		`schema_not_allowed:query references database "billing" which is not in MYSQL_MCP_ALLOWED_DATABASES`,
+		`schema_not_allowed:query references database "hr" which is not in MYSQL_MCP_ALLOWED_DATABASES`,
+	}
+	if strings.Join(rules, "\n") != strings.Join(want, "\n") {
+		t.Errorf("access findings = %q, want %q", rules, want)
+	}
+
+	_, out, _ = toolExplainValidation(context.Background(), &mcp.CallToolRequest{}, ExplainValidationInput{SQL: "SHOW DATABASES", Database: "other"})
+	got := map[string]bool{}
+	for _, f := range out.Triggered {
+		got[f.Rule] = true
+	}
+	if !got["database_not_allowed"] || !got["show_databases"] || out.Allowed {
+		t.Errorf("expected database_not_allowed and show_databases: %+v", out)
+	}
+}
@@ -0,0 +1,279 @@
+// pkg/mysqlmcp/tools_vector_write.go
+package mysqlmcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// vectorWriteMaxRows caps the rows vector_insert writes and the keys
+// vector_delete matches in one call.
+const vectorWriteMaxRows = 1000
+
+// vectorColumnDimensions returns the dimensions of a VECTOR column, read from
+// information_schema so a write is checked against the table as it is now.
+func vectorColumnDimensions(ctx context.Context, database, table, column string) (int, error) {
+	const q = `SELECT COLUMN_TYPE FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_NAME = ?`
+	var colType string
+	err := getDB().QueryRowContext(ctx, q, database, table, column).Scan(&colType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("column %s.%s.%s not found", database, table, column)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read column type: %w", err)
+	}
+	m := vectorDimensionsRegex.FindStringSubmatch(strings.ToLower(colType))
+	if m == nil {
+		return 0, fmt.Errorf("column %s.%s.%s is %s, not a VECTOR column", database, table, column, colType)
+	}
+	return strconv.Atoi(m[1])
+}
+
+// vectorLiteral formats an embedding for STRING_TO_VECTOR. Values are written
+// at float32 precision, which is what a VECTOR column stores.
+func vectorLiteral(vec []float64) (string, error) {
+	parts := make([]string, len(vec))
+	for i, v := range vec {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("element %d is not a finite number", i)
+		}
+		parts[i] = strconv.FormatFloat(v, 'g', -1, 32)
+	}
+	return "[" + strings.Join(parts, ",") + "]", nil
+}
+
+// vectorWriteArg converts a JSON value to a statement argument: objects and
+// arrays are sent as JSON text (for JSON columns), scalars as they are.
+func vectorWriteArg(v interface{}) (interface{}, error) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+	return v, nil
+}
+
+// auditVectorWrite records a vector_insert or vector_delete statement.
+func auditVectorWrite(ctx context.Context, tool, database, query string, timer *QueryTimer, rows int64, err error) {
+	if err != nil {
+		timer.LogError(err, query, nil, nil)
+	} else {
+		timer.LogSuccess(int(rows), query, nil, nil)
+	}
+	if auditLogger == nil {
+		return
+	}
+	entry := &AuditEntry{
+		Tool:        tool,
+		Database:    database,
+		Query:       loggedSQL(query, 500),
+		QueryDigest: util.QueryDigest(query),
+		DurationMs:  timer.ElapsedMs(),
+		RowCount:    int(rows),
+		Success:     err == nil,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	auditLogger.LogContext(ctx, entry)
+}
+
+func toolVectorInsert(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input VectorInsertInput,
+) (*mcp.CallToolResult, VectorInsertOutput, error) {
+	if input.Database == "" || input.Table == "" || input.Column == "" {
+		return nil, VectorInsertOutput{}, fmt.Errorf("database, table, and column are required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, VectorInsertOutput{}, err
+	}
+	if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+		return nil, VectorInsertOutput{}, err
+	}
+	if len(input.Rows) == 0 {
+		return nil, VectorInsertOutput{}, fmt.Errorf("rows is required")
+	}
+	if len(input.Rows) > vectorWriteMaxRows {
+		return nil, VectorInsertOutput{}, fmt.Errorf("at most %d rows can be inserted per call, got %d", vectorWriteMaxRows, len(input.Rows))
+	}
+
+	dbName, err := util.QuoteIdent(input.Database)
+	if err != nil {
+		return nil, VectorInsertOutput{}, fmt.Errorf("invalid database name: %w", err)
+	}
+	tableName, err := util.QuoteIdent(input.Table)
+	if err != nil {
+		return nil, VectorInsertOutput{}, fmt.Errorf("invalid table name: %w", err)
+	}
+	colName, err := util.QuoteIdent(input.Column)
+	if err != nil {
+		return nil, VectorInsertOutput{}, fmt.Errorf("invalid column name: %w", err)
+	}
+
+	// The first row decides the columns; every other row must set the same ones.
+	names := make([]string, 0, len(input.Rows[0].Values))
+	for name := range input.Rows[0].Values {
+		if strings.EqualFold(name, input.Column) {
+			return nil, VectorInsertOutput{}, fmt.Errorf("values must not set the vector column %s; use vector", input.Column)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	quoted := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		q, err := util.QuoteIdent(name)
+		if err != nil {
+			return nil, VectorInsertOutput{}, fmt.Errorf("invalid column name %q: %w", name, err)
+		}
+		quoted = append(quoted, q)
+	}
+	quoted = append(quoted, colName)
+
+	if err := requireConfirmation(input.Confirm); err != nil {
+		return nil, VectorInsertOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	dims, err := vectorColumnDimensions(ctx, input.Database, input.Table, input.Column)
+	if err != nil {
+		return nil, VectorInsertOutput{}, err
+	}
+
+	placeholder := "(" + strings.Repeat("?, ", len(names)) + "STRING_TO_VECTOR(?))"
+	tuples := make([]string, len(input.Rows))
+	args := make([]interface{}, 0, len(input.Rows)*(len(names)+1))
+	for i, row := range input.Rows {
+		if len(row.Vector) != dims {
+			return nil, VectorInsertOutput{}, fmt.Errorf("row %d: vector has %d dimensions, column %s is vector(%d)", i, len(row.Vector), input.Column, dims)
+		}
+		if len(row.Values) != len(names) {
+			return nil, VectorInsertOutput{}, fmt.Errorf("row %d: every row must set the same columns (%s)", i, strings.Join(names, ", "))
+		}
+		for _, name := range names {
+			v, ok := row.Values[name]
+			if !ok {
+				return nil, VectorInsertOutput{}, fmt.Errorf("row %d: missing value for column %s", i, name)
+			}
+			arg, err := vectorWriteArg(v)
+			if err != nil {
+				return nil, VectorInsertOutput{}, fmt.Errorf("row %d: column %s: %w", i, name, err)
+			}
+			args = append(args, arg)
+		}
+		vec, err := vectorLiteral(row.Vector)
+		if err != nil {
+			return nil, VectorInsertOutput{}, fmt.Errorf("row %d: %w", i, err)
+		}
+		args = append(args, vec)
+		tuples[i] = placeholder
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s.%s (%s) VALUES %s", dbName, tableName, strings.Join(quoted, ", "), strings.Join(tuples, ", "))
+	if input.Upsert {
+		updates := make([]string, len(quoted))
+		for i, q := range quoted {
+			updates[i] = fmt.Sprintf("%s = _new.%s", q, q)
+		}
+		query += " AS _new ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	}
+
+	// Writes always go to the primary, never to a replica.
+	timer := NewQueryTimer(ctx, "vector_insert")
+	res, err := getDB().ExecContext(ctx, query, args...)
+	if err != nil {
+		err = fmt.Errorf("vector insert failed: %w", err)
+		auditVectorWrite(ctx, "vector_insert", input.Database, query, timer, 0, err)
+		return nil, VectorInsertOutput{}, err
+	}
+	out := VectorInsertOutput{Dimensions: dims}
+	out.RowsAffected, _ = res.RowsAffected()
+	out.LastInsertID, _ = res.LastInsertId()
+	auditVectorWrite(ctx, "vector_insert", input.Database, query, timer, out.RowsAffected, nil)
+	return nil, out, nil
+}
+
+func toolVectorDelete(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input VectorDeleteInput,
+) (*mcp.CallToolResult, VectorDeleteOutput, error) {
+	if input.Database == "" || input.Table == "" || input.Column == "" || input.KeyColumn == "" {
+		return nil, VectorDeleteOutput{}, fmt.Errorf("database, table, column, and key_column are required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, VectorDeleteOutput{}, err
+	}
+	if err := requireNoRowPolicy(input.Database, input.Table); err != nil {
+		return nil, VectorDeleteOutput{}, err
+	}
+	if len(input.Keys) == 0 {
+		return nil, VectorDeleteOutput{}, fmt.Errorf("keys is required")
+	}
+	if len(input.Keys) > vectorWriteMaxRows {
+		return nil, VectorDeleteOutput{}, fmt.Errorf("at most %d keys can be deleted per call, got %d", vectorWriteMaxRows, len(input.Keys))
+	}
+
+	dbName, err := util.QuoteIdent(input.Database)
+	if err != nil {
+		return nil, VectorDeleteOutput{}, fmt.Errorf("invalid database name: %w", err)
+	}
+	tableName, err := util.QuoteIdent(input.Table)
+	if err != nil {
+		return nil, VectorDeleteOutput{}, fmt.Errorf("invalid table name: %w", err)
+	}
+	keyName, err := util.QuoteIdent(input.KeyColumn)
+	if err != nil {
+		return nil, VectorDeleteOutput{}, fmt.Errorf("invalid key column name: %w", err)
+	}
+	for i, k := range input.Keys {
+		switch k.(type) {
+		case string, float64, bool:
+		default:
+			return nil, VectorDeleteOutput{}, fmt.Errorf("keys[%d]: keys must be strings or numbers", i)
+		}
+	}
+
+	if err := requireConfirmation(input.Confirm); err != nil {
+		return nil, VectorDeleteOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	// Only tables holding embeddings can be written through the vector tools.
+	if _, err := vectorColumnDimensions(ctx, input.Database, input.Table, input.Column); err != nil {
+		return nil, VectorDeleteOutput{}, err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s.%s WHERE %s IN (%s)", dbName, tableName, keyName,
+		strings.TrimSuffix(strings.Repeat("?, ", len(input.Keys)), ", "))
+
+	timer := NewQueryTimer(ctx, "vector_delete")
+	res, err := getDB().ExecContext(ctx, query, input.Keys...)
+	if err != nil {
+		err = fmt.Errorf("vector delete failed: %w", err)
+		auditVectorWrite(ctx, "vector_delete", input.Database, query, timer, 0, err)
+		return nil, VectorDeleteOutput{}, err
+	}
+	var out VectorDeleteOutput
+	out.RowsAffected, _ = res.RowsAffected()
+	auditVectorWrite(ctx, "vector_delete", input.Database, query, timer, out.RowsAffected, nil)
+	return nil, out, nil
+}
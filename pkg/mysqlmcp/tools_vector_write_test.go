@@ -0,0 +1,121 @@
+// pkg/mysqlmcp/tools_vector_write_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func expectVectorColumn(mock sqlmock.Sqlmock, colType string) {
+	mock.ExpectQuery("SELECT COLUMN_TYPE FROM information_schema.COLUMNS").
+		WithArgs("rag", "docs", "embedding").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_TYPE"}).AddRow(colType))
+}
+
+func TestVectorLiteral(t *testing.T) {
+	got, err := vectorLiteral([]float64{0.5, -1, 0.0000012})
+	if err != nil || got != "[0.5,-1,1.2e-06]" {
+		t.Errorf("vectorLiteral = %q, %v", got, err)
+	}
+	if _, err := vectorLiteral([]float64{1, math.Inf(-1)}); err == nil {
+		t.Error("expected an error for an infinite element")
+	}
+}
+
+func TestToolVectorInsert(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	expectVectorColumn(mock, "vector(3)")
+	mock.ExpectExec("INSERT INTO `rag`.`docs` \\(`body`, `id`, `embedding`\\) VALUES \\(\\?, \\?, STRING_TO_VECTOR\\(\\?\\)\\), \\(\\?, \\?, STRING_TO_VECTOR\\(\\?\\)\\) AS _new ON DUPLICATE KEY UPDATE `body` = _new.`body`, `id` = _new.`id`, `embedding` = _new.`embedding`").
+		WithArgs("first", float64(1), "[0.1,0.2,0.3]", `{"lang":"en"}`, float64(2), "[1,0,0]").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	_, out, err := toolVectorInsert(context.Background(), &mcp.CallToolRequest{}, VectorInsertInput{
+		Database: "rag", Table: "docs", Column: "embedding", Upsert: true,
+		Rows: []VectorInsertRow{
+			{Vector: []float64{0.1, 0.2, 0.3}, Values: map[string]interface{}{"id": float64(1), "body": "first"}},
+			{Vector: []float64{1, 0, 0}, Values: map[string]interface{}{"id": float64(2), "body": map[string]interface{}{"lang": "en"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("vector_insert: %v", err)
+	}
+	if out.RowsAffected != 2 || out.Dimensions != 3 {
+		t.Errorf("unexpected output: %+v", out)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestToolVectorInsertRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		colType string // "" = no column lookup expected
+		rows    []VectorInsertRow
+		want    string
+	}{
+		{"dimension mismatch", "vector(3)", []VectorInsertRow{{Vector: []float64{1, 2}}}, "has 2 dimensions, column embedding is vector(3)"},
+		{"not a vector column", "varchar(255)", []VectorInsertRow{{Vector: []float64{1}}}, "not a VECTOR column"},
+		{"uneven columns", "vector(1)", []VectorInsertRow{
+			{Vector: []float64{1}, Values: map[string]interface{}{"id": 1}},
+			{Vector: []float64{1}, Values: map[string]interface{}{"doc_id": 1}},
+		}, "missing value for column id"},
+		{"vector in values", "", []VectorInsertRow{{Vector: []float64{1}, Values: map[string]interface{}{"Embedding": "x"}}}, "must not set the vector column"},
+		{"no rows", "", nil, "rows is required"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock, cleanup := setupMockDB(t)
+			defer cleanup()
+			if tc.colType != "" {
+				expectVectorColumn(mock, tc.colType)
+			}
+			_, _, err := toolVectorInsert(context.Background(), &mcp.CallToolRequest{}, VectorInsertInput{
+				Database: "rag", Table: "docs", Column: "embedding", Rows: tc.rows,
+			})
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected error containing %q, got %v", tc.want, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestToolVectorDelete(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	expectVectorColumn(mock, "vector(768)")
+	mock.ExpectExec("DELETE FROM `rag`.`docs` WHERE `id` IN \\(\\?, \\?\\)").
+		WithArgs(float64(7), "doc-8").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	_, out, err := toolVectorDelete(context.Background(), &mcp.CallToolRequest{}, VectorDeleteInput{
+		Database: "rag", Table: "docs", Column: "embedding", KeyColumn: "id", Keys: []interface{}{float64(7), "doc-8"},
+	})
+	if err != nil {
+		t.Fatalf("vector_delete: %v", err)
+	}
+	if out.RowsAffected != 1 {
+		t.Errorf("expected 1 row deleted, got %d", out.RowsAffected)
+	}
+
+	_, _, err = toolVectorDelete(context.Background(), &mcp.CallToolRequest{}, VectorDeleteInput{
+		Database: "rag", Table: "docs", Column: "embedding", KeyColumn: "id", Keys: []interface{}{[]interface{}{1}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "strings or numbers") {
+		t.Errorf("expected a key type error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// pkg/mysqlmcp/tools_view_lineage.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// view_lineage parses the definitions MySQL keeps in information_schema.VIEWS
+// and traces every view column back to the table columns it is read from, so
+// an analyst can see what a view is built on before trusting its numbers.
+// Columns computed by an expression or aggregate are marked derived and carry
+// the expression. Sources that are views themselves are flagged, since their
+// own lineage is one more call away. Definitions using syntax the parser does
+// not know (window functions, CTEs, JSON_TABLE) are reported with an error
+// instead of a partial answer.
+
+// viewLineageLimit caps the views one call traces.
+const viewLineageLimit = 50
+
+const queryViewDefinitions = `
+	SELECT TABLE_NAME, VIEW_DEFINITION
+	FROM information_schema.VIEWS
+	WHERE TABLE_SCHEMA = ?
+	ORDER BY TABLE_NAME`
+
+func toolViewLineage(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input ViewLineageInput,
+) (*mcp.CallToolResult, ViewLineageOutput, error) {
+	if input.Database == "" {
+		return nil, ViewLineageOutput{}, fmt.Errorf("database is required")
+	}
+	if err := requireAllowedDatabase(input.Database); err != nil {
+		return nil, ViewLineageOutput{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	rows, err := getDB().QueryContext(ctx, queryViewDefinitions, input.Database)
+	if err != nil {
+		return nil, ViewLineageOutput{}, fmt.Errorf("failed to read view definitions: %w", err)
+	}
+	type viewDefinition struct{ name, definition string }
+	var defs []viewDefinition
+	views := make(map[string]bool)
+	for rows.Next() {
+		var d viewDefinition
+		if err := rows.Scan(&d.name, &d.definition); err != nil {
+			rows.Close()
+			return nil, ViewLineageOutput{}, fmt.Errorf("failed to read view definitions: %w", err)
+		}
+		views[strings.ToLower(d.name)] = true
+		if input.View == "" || strings.EqualFold(d.name, input.View) {
+			defs = append(defs, d)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, ViewLineageOutput{}, fmt.Errorf("failed to read view definitions: %w", err)
+	}
+	if input.View != "" && len(defs) == 0 {
+		return nil, ViewLineageOutput{}, fmt.Errorf("view %s.%s not found", input.Database, input.View)
+	}
+
+	out := ViewLineageOutput{Database: input.Database, Views: []ViewLineage{}}
+	if len(defs) > viewLineageLimit {
+		out.Notes = append(out.Notes, fmt.Sprintf("Only the first %d of %d views are traced; pass view to trace another one.", viewLineageLimit, len(defs)))
+		defs = defs[:viewLineageLimit]
+	}
+	var hidden bool
+	for _, d := range defs {
+		if strings.TrimSpace(d.definition) == "" {
+			hidden = true
+			out.Views = append(out.Views, ViewLineage{View: d.name, Error: "definition not visible to this account"})
+			continue
+		}
+		out.Views = append(out.Views, traceViewLineage(input.Database, d.name, d.definition, views))
+	}
+	if hidden {
+		out.Notes = append(out.Notes, "MySQL only shows a view's definition to its definer and to accounts with the SHOW VIEW privilege.")
+	}
+	return nil, out, nil
+}
+
+// traceViewLineage traces one view of database. views holds the lowercased
+// names of the views of database.
+func traceViewLineage(database, name, definition string, views map[string]bool) ViewLineage {
+	v := ViewLineage{View: name}
+	lineage, err := util.SelectLineage(definition)
+	if err != nil {
+		v.Error = "could not trace the definition: " + err.Error()
+		return v
+	}
+	for _, t := range lineage.Tables {
+		if t.Database == "" {
+			t.Database = database
+		}
+		v.BaseTables = append(v.BaseTables, t.String())
+	}
+	for _, c := range lineage.Columns {
+		col := ViewColumnLineage{Column: c.Name, Derived: c.Derived, Sources: []LineageSource{}}
+		if c.Derived {
+			col.Expression = c.Expression
+		}
+		for _, s := range c.Sources {
+			src := LineageSource{Database: s.Database, Table: s.Table, Column: s.Column}
+			if src.Table != "" && src.Database == "" {
+				src.Database = database
+			}
+			src.View = src.Table != "" && strings.EqualFold(src.Database, database) && views[strings.ToLower(src.Table)]
+			col.Sources = append(col.Sources, src)
+		}
+		v.Columns = append(v.Columns, col)
+	}
+	return v
+}
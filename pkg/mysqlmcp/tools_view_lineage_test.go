@@ -0,0 +1,66 @@
+// pkg/mysqlmcp/tools_view_lineage_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolViewLineage(t *testing.T) {
+	mock, cleanup := setupExtendedMockDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	definitions := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"TABLE_NAME", "VIEW_DEFINITION"}).
+			AddRow("big_orders", "select `shop`.`order_totals`.`order_id` AS `order_id` from `shop`.`order_totals` where (`shop`.`order_totals`.`total` > 100)").
+			AddRow("order_totals", "select `o`.`id` AS `order_id`,sum((`i`.`qty` * `i`.`price`)) AS `total` "+
+				"from (`shop`.`orders` `o` join `shop`.`order_items` `i` on((`i`.`order_id` = `o`.`id`))) group by `o`.`id`").
+			AddRow("ranked", "select `shop`.`orders`.`id` AS `id`,rank() OVER (ORDER BY `shop`.`orders`.`total` )  AS `r` from `shop`.`orders`").
+			AddRow("secret", "")
+	}
+	mock.ExpectQuery("FROM information_schema.VIEWS").WithArgs("shop").WillReturnRows(definitions())
+
+	_, out, err := toolViewLineage(ctx, &mcp.CallToolRequest{}, ViewLineageInput{Database: "shop"})
+	if err != nil {
+		t.Fatalf("view_lineage: %v", err)
+	}
+	if len(out.Views) != 4 || len(out.Notes) != 1 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	big := out.Views[0]
+	if len(big.Columns) != 1 || !big.Columns[0].Sources[0].View || big.BaseTables[0] != "shop.order_totals" {
+		t.Errorf("expected big_orders to read the order_totals view: %+v", big)
+	}
+	totals := out.Views[1]
+	if totals.Error != "" || len(totals.Columns) != 2 {
+		t.Fatalf("unexpected order_totals: %+v", totals)
+	}
+	if c := totals.Columns[0]; c.Column != "order_id" || c.Derived || c.Sources[0] != (LineageSource{Database: "shop", Table: "orders", Column: "id"}) {
+		t.Errorf("unexpected order_id: %+v", c)
+	}
+	if c := totals.Columns[1]; !c.Derived || c.Expression == "" || len(c.Sources) != 2 || c.Sources[1].Column != "price" {
+		t.Errorf("unexpected total: %+v", c)
+	}
+	if got := strings.Join(totals.BaseTables, ","); got != "shop.order_items,shop.orders" {
+		t.Errorf("unexpected base tables %s", got)
+	}
+	if !strings.Contains(out.Views[2].Error, "could not trace") {
+		t.Errorf("expected the window function view to fail: %+v", out.Views[2])
+	}
+	if out.Views[3].Error == "" {
+		t.Errorf("expected the hidden definition to be reported: %+v", out.Views[3])
+	}
+
+	mock.ExpectQuery("FROM information_schema.VIEWS").WithArgs("shop").WillReturnRows(definitions())
+	if _, _, err := toolViewLineage(ctx, &mcp.CallToolRequest{}, ViewLineageInput{Database: "shop", View: "missing"}); err == nil {
+		t.Error("expected an error for an unknown view")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
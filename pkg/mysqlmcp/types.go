@@ -0,0 +1,1816 @@
+// pkg/mysqlmcp/types.go
+package mysqlmcp
+
+// ===== Tool input / output types =====
+
+type ListDatabasesInput struct {
+	Prefix string `json:"prefix,omitempty" jsonschema:"only databases whose name starts with this text (e.g. tenant_42)"`
+	Offset int    `json:"offset,omitempty" jsonschema:"zero-based database offset for pagination"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"databases per page (default and max: the server row limit)"`
+}
+
+type DatabaseInfo struct {
+	Name string `json:"name" jsonschema:"database name"`
+}
+
+type ListDatabasesOutput struct {
+	Databases  []DatabaseInfo `json:"databases" jsonschema:"list of accessible databases"`
+	HasMore    bool           `json:"has_more,omitempty" jsonschema:"true when more databases remain"`
+	NextOffset *int           `json:"next_offset,omitempty" jsonschema:"pass as offset to retrieve the next page when has_more is true"`
+	Total      int            `json:"total" jsonschema:"number of matching databases across all pages"`
+}
+
+type ListTablesInput struct {
+	Database        string `json:"database" validate:"required,ident" jsonschema:"database name to list tables from"`
+	Pattern         string `json:"pattern,omitempty" jsonschema:"optional LIKE pattern to filter table names (e.g. order%)"`
+	Prefix          string `json:"prefix,omitempty" jsonschema:"only tables whose name starts with this text; unlike pattern, % and _ match themselves"`
+	IncludeMetadata bool   `json:"include_metadata,omitempty" jsonschema:"when true, also return table type, create/update time and data/index size"`
+	Offset          int    `json:"offset,omitempty" jsonschema:"zero-based table offset for pagination"`
+	Limit           int    `json:"limit,omitempty" jsonschema:"tables per page (default and max: the server row limit)"`
+
+	Sort         string   `json:"sort,omitempty" jsonschema:"field to sort by (name, engine, type, comment, created_at, updated_at, rows, data_mb, index_mb); prefix with - for descending; default name"`
+	Filter       []string `json:"filter,omitempty" jsonschema:"conditions as field:op:value with op eq, ne, gt, gte, lt, lte or like (e.g. engine:eq:InnoDB, rows:gt:1000); all must match"`
+	IncludeTotal bool     `json:"include_total,omitempty" jsonschema:"when true, also return the number of matching tables in total"`
+}
+
+type TableInfo struct {
+	Name      string   `json:"name" jsonschema:"table name"`
+	Engine    string   `json:"engine,omitempty" jsonschema:"storage engine (e.g. InnoDB, MyISAM)"`
+	Rows      *int64   `json:"rows,omitempty" jsonschema:"estimated number of rows"`
+	Comment   string   `json:"comment,omitempty" jsonschema:"table comment"`
+	Type      string   `json:"type,omitempty" jsonschema:"BASE TABLE, VIEW or SYSTEM VIEW (include_metadata only)"`
+	CreatedAt string   `json:"created_at,omitempty" jsonschema:"creation time (include_metadata only)"`
+	UpdatedAt string   `json:"updated_at,omitempty" jsonschema:"last data change when tracked by the engine (include_metadata only)"`
+	DataMB    *float64 `json:"data_mb,omitempty" jsonschema:"data size in megabytes (include_metadata only)"`
+	IndexMB   *float64 `json:"index_mb,omitempty" jsonschema:"index size in megabytes (include_metadata only)"`
+}
+
+type ListTablesOutput struct {
+	Tables     []TableInfo `json:"tables" jsonschema:"list of tables in the database"`
+	HasMore    bool        `json:"has_more,omitempty" jsonschema:"true when more tables remain"`
+	NextOffset *int        `json:"next_offset,omitempty" jsonschema:"pass as offset to retrieve the next page when has_more is true"`
+	Total      *int        `json:"total,omitempty" jsonschema:"number of matching tables across all pages (include_total only)"`
+}
+
+type DescribeTableInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table" validate:"required,ident" jsonschema:"table name"`
+}
+
+type ColumnInfo struct {
+	Name      string `json:"name" jsonschema:"column name"`
+	Type      string `json:"type" jsonschema:"column type"`
+	Null      string `json:"null" jsonschema:"YES if nullable, NO otherwise"`
+	Key       string `json:"key" jsonschema:"key information (PRI, MUL, etc.)"`
+	Default   string `json:"default" jsonschema:"default value, if any"`
+	Extra     string `json:"extra" jsonschema:"extra metadata (auto_increment, etc.)"`
+	Comment   string `json:"comment" jsonschema:"column comment, if any"`
+	Collation string `json:"collation" jsonschema:"column collation, if any"`
+
+	GenerationExpression string `json:"generation_expression,omitempty" jsonschema:"expression of a generated (virtual or stored) column"`
+	IsGenerated          bool   `json:"is_generated,omitempty" jsonschema:"true for generated columns"`
+}
+
+type DescribeTableOutput struct {
+	Columns []ColumnInfo `json:"columns" jsonschema:"detailed column information"`
+}
+
+type RunQueryInput struct {
+	SQL      string `json:"sql" validate:"required" jsonschema:"SQL query to execute; must start with SELECT, SHOW, DESCRIBE, or EXPLAIN. Apply MySQL optimization guidelines before execution."`
+	MaxRows  *int   `json:"max_rows,omitempty" jsonschema:"optional row limit overriding the default max rows"`
+	Offset   *int   `json:"offset,omitempty" jsonschema:"optional zero-based row offset for SELECT/UNION pagination; do not add LIMIT to the SQL when using this"`
+	Database string `json:"database,omitempty" validate:"ident" jsonschema:"optional database name to USE before running the query"`
+	Confirm  bool   `json:"confirm,omitempty" jsonschema:"set to true to run on a connection whose environment or tags require confirmation (see list_connections requires_confirm)"`
+
+	BinaryOutput string `json:"binary_output,omitempty" jsonschema:"how BLOB/BINARY/VARBINARY cells are returned: hex (preview), base64, length, skip (omit the columns) or raw; defaults to the server setting"`
+	TimeZone     string `json:"time_zone,omitempty" jsonschema:"session time_zone to read TIMESTAMP values in, e.g. +00:00 or Europe/Berlin; defaults to the server setting"`
+	Format       string `json:"format,omitempty" jsonschema:"json (default) or table: the text content is a mysql client-style ASCII table instead of JSON; structured content is unchanged"`
+	Dedupe       bool   `json:"dedupe,omitempty" jsonschema:"remove returned rows that are exact copies of an earlier row and report how many were removed in duplicate_rows"`
+}
+
+type RunCrossDatabaseQueryInput struct {
+	Tables  []string `json:"tables" validate:"required" jsonschema:"every table the query reads, as database.table, from at least two databases"`
+	SQL     string   `json:"sql" validate:"required" jsonschema:"SELECT joining the tables; bare table names are qualified with their database from tables"`
+	MaxRows *int     `json:"max_rows,omitempty" jsonschema:"optional row limit lower than the default"`
+	Confirm bool     `json:"confirm,omitempty" jsonschema:"set to true on a connection whose environment or tags require confirmation"`
+}
+
+type RunCrossDatabaseQueryOutput struct {
+	SQL       string      `json:"sql" jsonschema:"the query as run, with every table qualified"`
+	Qualified []string    `json:"qualified,omitempty" jsonschema:"tables whose bare names were qualified"`
+	Result    QueryResult `json:"result" jsonschema:"query result, as returned by run_query"`
+}
+
+type ValidateQueryInput struct {
+	SQL      string `json:"sql" jsonschema:"query to check exactly as it would be passed to run_query (not executed)"`
+	Database string `json:"database,omitempty" jsonschema:"database the query would run in"`
+}
+
+type ValidateQueryOutput struct {
+	Valid          bool     `json:"valid" jsonschema:"true when run_query would accept the query and EXPLAIN succeeded"`
+	Stage          string   `json:"stage,omitempty" jsonschema:"where a rejected query failed: input, validation, access, or explain"`
+	Error          string   `json:"error,omitempty" jsonschema:"why the query would be rejected"`
+	StatementType  string   `json:"statement_type,omitempty" jsonschema:"SELECT, UNION, SHOW, ..."`
+	Tables         []string `json:"tables" jsonschema:"tables the query reads (schema-qualified when written so)"`
+	FinalSQL       string   `json:"final_sql,omitempty" jsonschema:"SQL run_query would execute after row policies and LIMIT injection"`
+	RowCap         int      `json:"row_cap,omitempty" jsonschema:"row cap run_query would apply"`
+	EstimatedRows  *int64   `json:"estimated_rows,omitempty" jsonschema:"optimizer estimate of rows returned"`
+	RowsExamined   *int64   `json:"rows_examined,omitempty" jsonschema:"optimizer estimate of rows examined"`
+	QueryCost      *float64 `json:"query_cost,omitempty" jsonschema:"optimizer cost from EXPLAIN FORMAT=JSON (MySQL only)"`
+	Recommendation string   `json:"recommendation,omitempty" jsonschema:"run, paginate, refine, or unknown, comparing the estimate with the row cap"`
+	Warnings       []string `json:"warnings,omitempty" jsonschema:"plan and style issues worth fixing before running"`
+	Notes          []string `json:"notes,omitempty" jsonschema:"limitations of this check"`
+}
+
+type ExplainValidationInput struct {
+	SQL      string `json:"sql" jsonschema:"query to check exactly as it would be passed to run_query (not executed)"`
+	Database string `json:"database,omitempty" jsonschema:"database the query would run in; needed for the allowlist checks"`
+}
+
+// ValidationFinding is one validation or access rule a query breaks.
+type ValidationFinding struct {
+	Layer   string `json:"layer" jsonschema:"input, multi_statement, parser, regex (defense-in-depth patterns) or access (database allowlist)"`
+	Rule    string `json:"rule" jsonschema:"rule id, e.g. syntax, statement_type, dangerous_function, system_schema, comment, file_access, allowed_prefix, data_modifying_cte, database_not_allowed"`
+	Message string `json:"message" jsonschema:"the rejection message"`
+	Pattern string `json:"pattern,omitempty" jsonschema:"regular expression that matched (regex layer)"`
+	Hint    string `json:"hint,omitempty" jsonschema:"how to rewrite the query so the rule passes"`
+}
+
+type ExplainValidationOutput struct {
+	Allowed   bool                `json:"allowed" jsonschema:"true when run_query's validation and access checks accept the query"`
+	Triggered []ValidationFinding `json:"triggered" jsonschema:"every rule the query breaks, in check order"`
+	Notes     []string            `json:"notes,omitempty" jsonschema:"requirements that do not reject the query, such as confirm=true"`
+}
+
+type QueryResult struct {
+	Columns        []string        `json:"columns" jsonschema:"column names"`
+	Rows           [][]interface{} `json:"rows" jsonschema:"rows of values"`
+	Truncated      bool            `json:"truncated,omitempty" jsonschema:"true if more rows existed beyond the row or byte limit (not set when the result size exactly equals the row limit)"`
+	HasMore        bool            `json:"has_more,omitempty" jsonschema:"true when offset pagination indicates another page may exist"`
+	NextOffset     *int            `json:"next_offset,omitempty" jsonschema:"pass as offset to retrieve the next page when has_more is true"`
+	Warning        string          `json:"warning,omitempty" jsonschema:"performance or usage warning, if any"`
+	SizeBytes      int64           `json:"size_bytes,omitempty" jsonschema:"approximate size of the returned cell data in bytes"`
+	TruncatedCells int             `json:"truncated_cells,omitempty" jsonschema:"cells shortened to fit the result byte limit; each ends with a [truncated N bytes] marker"`
+	SkippedColumns []string        `json:"skipped_columns,omitempty" jsonschema:"binary columns left out by binary_output=skip"`
+	CellHandles    []CellHandle    `json:"cell_handles,omitempty" jsonschema:"handles for the truncated cells; pass one to fetch_cell to read the full value"`
+	Connection     string          `json:"connection,omitempty" jsonschema:"connection that answered, when it has an environment label"`
+	Environment    string          `json:"environment,omitempty" jsonschema:"environment label of that connection (prod, staging, ...)"`
+	Retries        int             `json:"retries,omitempty" jsonschema:"times the query was retried after a transient error (deadlock, lock wait timeout, dropped connection)"`
+	RetryReason    string          `json:"retry_reason,omitempty" jsonschema:"the transient error behind the last retry"`
+	PIIColumns     []string        `json:"pii_columns,omitempty" jsonschema:"returned columns matching the configured PII patterns, not masked or pseudonymized"`
+	DuplicateRows  int             `json:"duplicate_rows,omitempty" jsonschema:"rows removed by dedupe as exact copies of an earlier returned row"`
+
+	TimeZone        string           `json:"time_zone,omitempty" jsonschema:"session time_zone the result was read in (SYSTEM means the server's zone), set when it has temporal columns"`
+	UTCOffset       string           `json:"utc_offset,omitempty" jsonschema:"current offset of time_zone from UTC, e.g. +02:00"`
+	TemporalColumns []TemporalColumn `json:"temporal_columns,omitempty" jsonschema:"TIMESTAMP, DATETIME, DATE and TIME columns and how their values relate to time_zone"`
+
+	ColumnTypes  []ColumnType `json:"column_types,omitempty" jsonschema:"MySQL type, nullability and precision of each returned column, in column order"`
+	SourceTables []string     `json:"source_tables,omitempty" jsonschema:"tables the query reads according to the SQL parser (schema-qualified when written so); omitted when it could not parse the query"`
+
+	DuplicateSuppressed bool   `json:"duplicate_suppressed,omitempty" jsonschema:"true when the same query already ran in this session moments ago: this is that earlier result and MySQL was not queried again"`
+	DuplicateRepeats    int    `json:"duplicate_repeats,omitempty" jsonschema:"how many times the query has been repeated and answered from the earlier result"`
+	CachedAt            string `json:"cached_at,omitempty" jsonschema:"when the returned result was read from MySQL (RFC 3339), set for suppressed duplicates"`
+}
+
+// ColumnType describes one result column as reported by the driver.
+type ColumnType struct {
+	Name      string `json:"name" jsonschema:"column name"`
+	Type      string `json:"type" jsonschema:"MySQL type, e.g. VARCHAR, DECIMAL, UNSIGNED BIGINT, DATETIME"`
+	Kind      string `json:"kind,omitempty" jsonschema:"integer, decimal, float, bit, temporal, json, spatial, binary or string"`
+	Nullable  *bool  `json:"nullable,omitempty" jsonschema:"whether the column may hold NULL"`
+	Charset   string `json:"charset,omitempty" jsonschema:"binary for byte strings; omitted for text, whose character set the driver does not report"`
+	Precision *int64 `json:"precision,omitempty" jsonschema:"total digits of a DECIMAL, or fractional second digits of a temporal column"`
+	Scale     *int64 `json:"scale,omitempty" jsonschema:"digits after the decimal point of a DECIMAL or FLOAT, or fractional second digits of a temporal column"`
+}
+
+// TemporalColumn describes the zone of one date or time column in a result.
+type TemporalColumn struct {
+	Column string `json:"column" jsonschema:"column name"`
+	Type   string `json:"type" jsonschema:"TIMESTAMP, DATETIME, DATE or TIME"`
+	Zone   string `json:"zone" jsonschema:"session (TIMESTAMP: converted from UTC to time_zone) or as_stored (returned as written; the zone is whatever the application used)"`
+}
+
+// CellHandle points at one cell that was cut to fit the result byte limit.
+type CellHandle struct {
+	Row       int    `json:"row" jsonschema:"index of the row in rows"`
+	Column    string `json:"column" jsonschema:"column name"`
+	FullBytes int    `json:"full_bytes" jsonschema:"size of the untruncated value in bytes"`
+	Handle    string `json:"handle" jsonschema:"opaque handle for fetch_cell"`
+}
+
+// FetchCellInput is the input for fetch_cell.
+type FetchCellInput struct {
+	Handle   string `json:"handle" jsonschema:"cell handle from a cell_handles entry of run_query or run_saved_query"`
+	Offset   int    `json:"offset,omitempty" jsonschema:"byte offset to start reading at (default 0)"`
+	Length   int    `json:"length,omitempty" jsonschema:"maximum bytes to return (default 1 MiB, capped by the result byte limit)"`
+	Encoding string `json:"encoding,omitempty" jsonschema:"auto (default: text when valid UTF-8, otherwise base64) or base64"`
+}
+
+// FetchCellOutput is the output for fetch_cell.
+type FetchCellOutput struct {
+	Column     string `json:"column" jsonschema:"column name"`
+	Row        int    `json:"row" jsonschema:"index of the row in the original result"`
+	Null       bool   `json:"null,omitempty" jsonschema:"true when the value is now NULL"`
+	TotalBytes int    `json:"total_bytes" jsonschema:"size of the full value in bytes"`
+	Offset     int    `json:"offset" jsonschema:"byte offset of data"`
+	Length     int    `json:"length" jsonschema:"number of bytes in data"`
+	Encoding   string `json:"encoding" jsonschema:"text or base64"`
+	Data       string `json:"data" jsonschema:"the requested byte range of the value"`
+	HasMore    bool   `json:"has_more,omitempty" jsonschema:"true when bytes remain after this range"`
+	NextOffset *int   `json:"next_offset,omitempty" jsonschema:"pass as offset to read the next range"`
+}
+
+// ===== Saved Query Types =====
+
+type SavedQueryParamInfo struct {
+	Name        string      `json:"name" jsonschema:"parameter name, referenced in the SQL as :name"`
+	Type        string      `json:"type,omitempty" jsonschema:"string (default), int, number, bool, date (YYYY-MM-DD), or identifier (reports only)"`
+	Description string      `json:"description,omitempty" jsonschema:"what the parameter means"`
+	Required    bool        `json:"required,omitempty" jsonschema:"true when the caller must supply a value"`
+	Default     interface{} `json:"default,omitempty" jsonschema:"value used when the parameter is omitted"`
+}
+
+type SavedQueryInfo struct {
+	Name        string                `json:"name" jsonschema:"saved query name"`
+	Description string                `json:"description,omitempty" jsonschema:"what the query returns"`
+	Database    string                `json:"database,omitempty" jsonschema:"database the query runs in"`
+	SQL         string                `json:"sql" jsonschema:"query text with :name placeholders"`
+	Params      []SavedQueryParamInfo `json:"params" jsonschema:"parameters accepted by run_saved_query"`
+	Source      string                `json:"source" jsonschema:"config (reviewed, from the config file), runtime (registered with save_query) or bookmark (shared over /api/bookmarks)"`
+}
+
+type ListSavedQueriesInput struct{}
+
+type ListSavedQueriesOutput struct {
+	Queries []SavedQueryInfo `json:"queries" jsonschema:"saved queries sorted by name"`
+}
+
+type RunSavedQueryInput struct {
+	Name     string                 `json:"name" jsonschema:"saved query name (see list_saved_queries)"`
+	Params   map[string]interface{} `json:"params,omitempty" jsonschema:"parameter values by name; values are bound, never interpolated"`
+	Database string                 `json:"database,omitempty" jsonschema:"database to run in when the saved query does not pin one"`
+	MaxRows  *int                   `json:"max_rows,omitempty" jsonschema:"optional row limit lower than the default"`
+
+	BinaryOutput string `json:"binary_output,omitempty" jsonschema:"how BLOB/BINARY/VARBINARY cells are returned: hex (preview), base64, length, skip (omit the columns) or raw; defaults to the server setting"`
+	TimeZone     string `json:"time_zone,omitempty" jsonschema:"session time_zone to read TIMESTAMP values in, e.g. +00:00 or Europe/Berlin; defaults to the server setting"`
+}
+
+type SaveQueryInput struct {
+	Name        string                `json:"name" jsonschema:"query name (letters, digits, _ . -; max 64 characters)"`
+	Description string                `json:"description,omitempty" jsonschema:"what the query returns"`
+	SQL         string                `json:"sql" jsonschema:"read-only SELECT with :name placeholders for parameters"`
+	Database    string                `json:"database,omitempty" jsonschema:"optional database to pin the query to"`
+	Params      []SavedQueryParamInfo `json:"params,omitempty" jsonschema:"parameter definitions; undeclared placeholders become required strings"`
+}
+
+type SaveQueryOutput struct {
+	Query    SavedQueryInfo `json:"query" jsonschema:"the stored query"`
+	Replaced bool           `json:"replaced,omitempty" jsonschema:"true when an earlier runtime query with the same name was replaced"`
+}
+
+type CheckSavedQueriesInput struct {
+	Names          []string `json:"names,omitempty" jsonschema:"saved queries to check; defaults to all"`
+	Database       string   `json:"database,omitempty" jsonschema:"database for saved queries that do not pin one"`
+	UpdateBaseline bool     `json:"update_baseline,omitempty" jsonschema:"after comparing, store the current plans as the new baselines, accepting any changes"`
+}
+
+type PlanStep struct {
+	Table  string `json:"table" jsonschema:"table or alias from EXPLAIN"`
+	Access string `json:"access" jsonschema:"EXPLAIN access type (const, eq_ref, ref, range, index, ALL, ...)"`
+	Key    string `json:"key,omitempty" jsonschema:"index used"`
+	Rows   int64  `json:"rows,omitempty" jsonschema:"estimated rows examined"`
+}
+
+type PlanChange struct {
+	Table  string `json:"table" jsonschema:"table or alias the change is on"`
+	Kind   string `json:"kind" jsonschema:"new_full_scan, access_regression, access_improvement or index_changed"`
+	Before string `json:"before,omitempty" jsonschema:"baseline access type or index"`
+	After  string `json:"after,omitempty" jsonschema:"current access type or index"`
+}
+
+type SavedQueryPlanCheck struct {
+	Name       string       `json:"name" jsonschema:"saved query name"`
+	Database   string       `json:"database,omitempty" jsonschema:"database the query was explained in"`
+	Status     string       `json:"status" jsonschema:"baseline_recorded, unchanged, changed (no regression), regressed or error"`
+	Changes    []PlanChange `json:"changes,omitempty" jsonschema:"differences from the baseline plan"`
+	Plan       []PlanStep   `json:"plan,omitempty" jsonschema:"current plan"`
+	BaselineAt string       `json:"baseline_at,omitempty" jsonschema:"when the baseline compared against was recorded (RFC 3339)"`
+	Error      string       `json:"error,omitempty" jsonschema:"why the query could not be explained"`
+}
+
+type CheckSavedQueriesOutput struct {
+	Connection string                `json:"connection" jsonschema:"connection the plans were taken on"`
+	Checked    int                   `json:"checked" jsonschema:"saved queries explained"`
+	Regressed  int                   `json:"regressed" jsonschema:"saved queries whose plan got worse"`
+	Queries    []SavedQueryPlanCheck `json:"queries" jsonschema:"one entry per saved query, sorted by name"`
+	Persisted  bool                  `json:"persisted" jsonschema:"true when baselines are kept in MYSQL_MCP_PLAN_BASELINE_FILE, false when only in memory"`
+}
+
+// ===== Query Job Types =====
+
+// QueryJobInput is the body of POST /api/jobs: the body of /api/query and,
+// for exports, where to upload the result.
+type QueryJobInput struct {
+	RunQueryInput
+	Export *JobExportInput `json:"export,omitempty"`
+}
+
+// JobExportInput sends a job's rows to object storage instead of the job store.
+type JobExportInput struct {
+	Target string `json:"target"`           // export_targets name
+	Format string `json:"format,omitempty"` // ndjson (default) or csv
+}
+
+// QueryJobExport describes the object an export job uploaded.
+type QueryJobExport struct {
+	Target       string `json:"target"`
+	Format       string `json:"format"`
+	Object       string `json:"object"` // object name in the bucket
+	RowCount     int    `json:"row_count"`
+	Truncated    bool   `json:"truncated,omitempty"` // MYSQL_HTTP_STREAM_MAX_ROWS was reached
+	Bytes        int64  `json:"bytes,omitempty"`
+	URL          string `json:"url,omitempty"`            // signed download URL, once uploaded
+	URLExpiresAt string `json:"url_expires_at,omitempty"` // RFC 3339
+}
+
+// QueryJobInfo describes an async query job of /api/jobs.
+type QueryJobInfo struct {
+	ID          string          `json:"id"`
+	Status      string          `json:"status"` // running, succeeded, failed, canceled or expired
+	SQL         string          `json:"sql"`
+	Database    string          `json:"database,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	FinishedAt  string          `json:"finished_at,omitempty"`
+	DurationMs  int64           `json:"duration_ms,omitempty"`
+	ExpiresAt   string          `json:"expires_at,omitempty"` // when the finished job is discarded
+	Error       string          `json:"error,omitempty"`
+	RowCount    *int            `json:"row_count,omitempty"`
+	ResultBytes int             `json:"result_bytes,omitempty"`
+	Result      *QueryResult    `json:"result,omitempty"` // GET /api/jobs/{id} of a succeeded job
+	Export      *QueryJobExport `json:"export,omitempty"`
+}
+
+type ListQueryJobsOutput struct {
+	Jobs []QueryJobInfo `json:"jobs"`
+}
+
+// ===== Bookmark Types =====
+
+type BookmarkInfo struct {
+	SavedQueryInfo
+	CreatedBy string `json:"created_by,omitempty" jsonschema:"API key (masked), MCP client or address that saved the bookmark"`
+	CreatedAt string `json:"created_at" jsonschema:"when the bookmark was saved (RFC 3339)"`
+}
+
+type ListBookmarksInput struct{}
+
+type ListBookmarksOutput struct {
+	Bookmarks []BookmarkInfo `json:"bookmarks" jsonschema:"bookmarks sorted by name"`
+}
+
+type SaveBookmarkOutput struct {
+	Bookmark BookmarkInfo `json:"bookmark" jsonschema:"the stored bookmark"`
+	Replaced bool         `json:"replaced,omitempty" jsonschema:"true when an earlier bookmark or runtime query with the same name was replaced"`
+}
+
+type DeleteBookmarkInput struct {
+	Name string `json:"name" validate:"required" jsonschema:"bookmark name"`
+}
+
+type DeleteBookmarkOutput struct {
+	Name    string `json:"name" jsonschema:"bookmark name"`
+	Deleted bool   `json:"deleted" jsonschema:"true when the bookmark was removed"`
+}
+
+// ===== Report Types =====
+
+type ReportInfo struct {
+	Name        string                `json:"name" jsonschema:"report name"`
+	Description string                `json:"description,omitempty" jsonschema:"what the report summarizes"`
+	Database    string                `json:"database,omitempty" jsonschema:"database the report runs in"`
+	Variables   []SavedQueryParamInfo `json:"variables" jsonschema:"variables accepted by run_report"`
+	Sections    []string              `json:"sections" jsonschema:"section names in execution order"`
+}
+
+type ListReportsInput struct{}
+
+type ListReportsOutput struct {
+	Reports []ReportInfo `json:"reports" jsonschema:"reports sorted by name"`
+}
+
+type RunReportInput struct {
+	Name      string                 `json:"name" jsonschema:"report name (see list_reports)"`
+	Variables map[string]interface{} `json:"variables,omitempty" jsonschema:"variable values by name; validated against each variable's type"`
+	Database  string                 `json:"database,omitempty" jsonschema:"database to run in when the report does not pin one"`
+	MaxRows   *int                   `json:"max_rows,omitempty" jsonschema:"optional per-section row limit lower than the default"`
+	Snapshot  bool                   `json:"snapshot,omitempty" jsonschema:"run every section in one read-only transaction WITH CONSISTENT SNAPSHOT, so all sections see the same point in time (sections are not retried)"`
+}
+
+type ReportSectionResult struct {
+	Name        string          `json:"name" jsonschema:"section name"`
+	Columns     []string        `json:"columns" jsonschema:"column names"`
+	Rows        [][]interface{} `json:"rows" jsonschema:"result rows"`
+	Truncated   bool            `json:"truncated,omitempty" jsonschema:"true when the row limit cut the result"`
+	Error       string          `json:"error,omitempty" jsonschema:"why the section failed; other sections still run"`
+	Retries     int             `json:"retries,omitempty" jsonschema:"times the section query was retried after a transient error"`
+	RetryReason string          `json:"retry_reason,omitempty" jsonschema:"the transient error behind the last retry"`
+}
+
+type RunReportOutput struct {
+	Name        string                 `json:"name" jsonschema:"report name"`
+	Description string                 `json:"description,omitempty" jsonschema:"what the report summarizes"`
+	Variables   map[string]interface{} `json:"variables" jsonschema:"resolved variable values, including defaults"`
+	Sections    []ReportSectionResult  `json:"sections" jsonschema:"results in section order"`
+	Snapshot    bool                   `json:"snapshot,omitempty" jsonschema:"true when all sections read one consistent snapshot"`
+}
+
+type ScheduledResultsInput struct {
+	Name        string `json:"name,omitempty" jsonschema:"schedule name; omit to list every schedule and its status"`
+	ChangesOnly bool   `json:"changes_only,omitempty" jsonschema:"return only the rows added and removed since the previous run, not the full latest result"`
+}
+
+type ScheduleInfo struct {
+	Name            string `json:"name" jsonschema:"schedule name"`
+	SavedQuery      string `json:"saved_query" jsonschema:"saved query the schedule runs"`
+	Cron            string `json:"cron" jsonschema:"cron expression"`
+	Database        string `json:"database,omitempty" jsonschema:"database the query runs in"`
+	NextRun         string `json:"next_run,omitempty" jsonschema:"when the next run starts (RFC 3339, UTC)"`
+	LastRun         string `json:"last_run,omitempty" jsonschema:"when the latest run started (RFC 3339, UTC)"`
+	LastDurationMs  int64  `json:"last_duration_ms,omitempty" jsonschema:"how long the latest run took"`
+	LastError       string `json:"last_error,omitempty" jsonschema:"why the latest run failed"`
+	Runs            int    `json:"runs" jsonschema:"completed runs since the server started"`
+	SkippedOverlaps int    `json:"skipped_overlaps,omitempty" jsonschema:"runs skipped because the previous one was still going"`
+	Running         bool   `json:"running,omitempty" jsonschema:"true while a run is in progress"`
+}
+
+type ScheduledChanges struct {
+	Since          string          `json:"since" jsonschema:"start of the previous run the latest result is compared with (RFC 3339, UTC)"`
+	Added          [][]interface{} `json:"added,omitempty" jsonschema:"rows in the latest result but not the previous one"`
+	Removed        [][]interface{} `json:"removed,omitempty" jsonschema:"rows in the previous result but not the latest one"`
+	Unchanged      int             `json:"unchanged" jsonschema:"rows present in both results"`
+	ColumnsChanged bool            `json:"columns_changed,omitempty" jsonschema:"true when the columns differ, in which case every row counts as added or removed"`
+}
+
+type ScheduledResultsOutput struct {
+	Schedules []ScheduleInfo    `json:"schedules,omitempty" jsonschema:"every schedule sorted by name, when no name was given"`
+	Schedule  *ScheduleInfo     `json:"schedule,omitempty" jsonschema:"status of the named schedule"`
+	Result    *QueryResult      `json:"result,omitempty" jsonschema:"latest successful result; omitted before the first run or with changes_only"`
+	Changes   *ScheduledChanges `json:"changes,omitempty" jsonschema:"rows added and removed since the previous run, once two runs succeeded"`
+}
+
+type SchemaChangesInput struct {
+	Database string `json:"database,omitempty" jsonschema:"only changes of this watched database"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"max changes to return, newest first (default 20)"`
+}
+
+// SchemaChange is the difference between two snapshots of a watched database.
+type SchemaChange struct {
+	Time           string               `json:"time" jsonschema:"when the change was seen (RFC 3339, UTC)"`
+	Connection     string               `json:"connection" jsonschema:"connection the database was read on"`
+	Database       string               `json:"database" jsonschema:"watched database"`
+	TablesAdded    []string             `json:"tables_added,omitempty" jsonschema:"tables and views created"`
+	TablesDropped  []string             `json:"tables_dropped,omitempty" jsonschema:"tables and views dropped or renamed away"`
+	ColumnsAdded   []SchemaColumnChange `json:"columns_added,omitempty" jsonschema:"columns added to existing tables"`
+	ColumnsDropped []SchemaColumnChange `json:"columns_dropped,omitempty" jsonschema:"columns dropped from existing tables"`
+	ColumnsChanged []SchemaColumnChange `json:"columns_changed,omitempty" jsonschema:"columns whose type, nullability or default changed"`
+	Fingerprint    string               `json:"fingerprint" jsonschema:"SHA-256 of the new column metadata"`
+}
+
+// SchemaColumnChange is one added, dropped or redefined column.
+type SchemaColumnChange struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Before string `json:"before,omitempty" jsonschema:"previous definition, e.g. varchar(64) NOT NULL"`
+	After  string `json:"after,omitempty" jsonschema:"new definition"`
+}
+
+type SchemaChangesOutput struct {
+	Databases       []string       `json:"databases" jsonschema:"watched databases"`
+	IntervalSeconds int            `json:"interval_seconds" jsonschema:"seconds between snapshots"`
+	LastCheck       string         `json:"last_check,omitempty" jsonschema:"when the databases were last read (RFC 3339, UTC)"`
+	LastError       string         `json:"last_error,omitempty" jsonschema:"why the last check failed"`
+	Changes         []SchemaChange `json:"changes" jsonschema:"changes since the server started, newest first"`
+}
+
+type PingInput struct{}
+
+type PingOutput struct {
+	Success   bool   `json:"success" jsonschema:"true if the database is reachable"`
+	LatencyMs int64  `json:"latency_ms" jsonschema:"round-trip latency in milliseconds"`
+	Message   string `json:"message" jsonschema:"status message"`
+}
+
+// ReadinessReport is returned by GET /ready and --healthcheck.
+type ReadinessReport struct {
+	Ready       bool                  `json:"ready"`
+	Started     bool                  `json:"started"`
+	Subsystems  map[string]string     `json:"subsystems"`
+	Connections []ConnectionReadiness `json:"connections"`
+}
+
+type ConnectionReadiness struct {
+	Name      string `json:"name"`
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ServerInfoInput struct {
+	Detailed bool `json:"detailed,omitempty" jsonschema:"when true, include health metrics (threads_running, slow_queries, buffer pool hit rate, ping latency)"`
+}
+
+// ServerHealthSnapshot is returned when server_info is called with detailed=true.
+type ServerHealthSnapshot struct {
+	PingLatencyMs          int64    `json:"ping_latency_ms,omitempty" jsonschema:"round-trip ping in milliseconds"`
+	ThreadsRunning         int      `json:"threads_running,omitempty" jsonschema:"Threads_running status"`
+	SlowQueries            int64    `json:"slow_queries,omitempty" jsonschema:"Slow_queries counter since startup"`
+	Questions              int64    `json:"questions,omitempty" jsonschema:"Questions counter since startup"`
+	BufferPoolHitPercent   *float64 `json:"buffer_pool_hit_percent,omitempty" jsonschema:"InnoDB buffer pool hit ratio 0-100 when available"`
+	BufferPoolReadRequests *int64   `json:"buffer_pool_read_requests,omitempty"`
+	BufferPoolReads        *int64   `json:"buffer_pool_reads,omitempty"`
+}
+
+// ServerTokenSnapshot summarizes token metrics when MYSQL_MCP_TOKEN_TRACKING=1.
+type ServerTokenSnapshot struct {
+	ToolCalls         int `json:"tool_calls,omitempty"`
+	TotalInputTokens  int `json:"total_input_tokens,omitempty"`
+	TotalOutputTokens int `json:"total_output_tokens,omitempty"`
+	TotalTokens       int `json:"total_tokens,omitempty"`
+	MetricsUptimeSec  int `json:"metrics_uptime_seconds,omitempty"`
+}
+
+type PerconaInfo struct {
+	ThreadPool     bool   `json:"thread_pool" jsonschema:"true when thread_handling is pool-of-threads"`
+	ThreadHandling string `json:"thread_handling,omitempty" jsonschema:"thread_handling variable"`
+	AuditLog       bool   `json:"audit_log" jsonschema:"true when the audit_log plugin is loaded"`
+}
+
+type ServerInfoOutput struct {
+	Version          string                `json:"version" jsonschema:"MySQL server version"`
+	ServerEngine     string                `json:"server_engine" jsonschema:"Server engine (mysql or mariadb)"`
+	VersionComment   string                `json:"version_comment" jsonschema:"MySQL version comment (e.g., MySQL Community Server)"`
+	Uptime           int64                 `json:"uptime_seconds" jsonschema:"server uptime in seconds"`
+	CurrentDatabase  string                `json:"current_database" jsonschema:"currently selected database, if any"`
+	CurrentUser      string                `json:"current_user" jsonschema:"current MySQL user"`
+	CharacterSet     string                `json:"character_set" jsonschema:"server character set"`
+	Collation        string                `json:"collation" jsonschema:"server collation"`
+	MaxConnections   int                   `json:"max_connections" jsonschema:"maximum allowed connections"`
+	ThreadsConnected int                   `json:"threads_connected" jsonschema:"current number of connected threads"`
+	HeatWave         *HeatWaveInfo         `json:"heatwave,omitempty" jsonschema:"present when the server has the HeatWave (RAPID) secondary engine"`
+	Percona          *PerconaInfo          `json:"percona,omitempty" jsonschema:"present on Percona Server: its optional features in use"`
+	Middleware       string                `json:"middleware,omitempty" jsonschema:"proxysql or vitess when the connection goes through one"`
+	ProxyCompat      bool                  `json:"proxy_compat,omitempty" jsonschema:"query tools avoid USE and session variables (MYSQL_MCP_PROXY_COMPAT)"`
+	Health           *ServerHealthSnapshot `json:"health,omitempty" jsonschema:"present when detailed=true"`
+	TokenMetrics     *ServerTokenSnapshot  `json:"token_metrics,omitempty" jsonschema:"present when token tracking is enabled"`
+
+	Capabilities *ConnectionCapabilities `json:"capabilities,omitempty" jsonschema:"what the active connection supports, probed when it opened"`
+}
+
+// ConnectionCapabilities is the capability matrix of a connection. A nil
+// capability could not be probed.
+type ConnectionCapabilities struct {
+	Flavor            string            `json:"flavor" jsonschema:"mysql, mariadb or unknown"`
+	Version           string            `json:"version,omitempty" jsonschema:"server version when probed"`
+	Middleware        string            `json:"middleware,omitempty" jsonschema:"proxysql or vitess when the connection goes through one"`
+	ProbedAt          string            `json:"probed_at" jsonschema:"when the connection was probed (RFC 3339, UTC)"`
+	PerformanceSchema *bool             `json:"performance_schema" jsonschema:"performance_schema is on and readable"`
+	ProcessPrivilege  *bool             `json:"process_privilege" jsonschema:"the account has PROCESS: InnoDB information_schema tables and other accounts' threads are visible"`
+	SlowLogTable      *bool             `json:"slow_log_table" jsonschema:"mysql.slow_log is readable"`
+	OptimizerTrace    *bool             `json:"optimizer_trace" jsonschema:"the optimizer trace is available"`
+	HeatWave          *bool             `json:"heatwave" jsonschema:"the HeatWave (RAPID) engine is present"`
+	Aurora            *bool             `json:"aurora" jsonschema:"the server is Amazon Aurora MySQL"`
+	CTEs              *bool             `json:"ctes" jsonschema:"WITH common table expressions"`
+	WindowFunctions   *bool             `json:"window_functions" jsonschema:"OVER (...) window functions"`
+	JSONTable         *bool             `json:"json_table" jsonschema:"the JSON_TABLE function"`
+	ExplainAnalyze    *bool             `json:"explain_analyze" jsonschema:"EXPLAIN ANALYZE"`
+	VectorType        *bool             `json:"vector_type" jsonschema:"the VECTOR column type"`
+	Unsupported       []UnsupportedTool `json:"unsupported_tools" jsonschema:"tools that refuse to run on this connection, with alternatives"`
+}
+
+// UnsupportedTool is a tool the connection lacks a capability for.
+type UnsupportedTool struct {
+	Tool        string `json:"tool"`
+	Reason      string `json:"reason"`
+	Alternative string `json:"alternative"`
+}
+
+// ===== Session Settings Types =====
+
+type SessionSettingsInput struct{}
+
+type SessionOverrideInfo struct {
+	SQLModeAdded    []string          `json:"sql_mode_added,omitempty" jsonschema:"modes set_session_setting added to sql_mode"`
+	SQLModeRemoved  []string          `json:"sql_mode_removed,omitempty" jsonschema:"modes set_session_setting removed from sql_mode"`
+	OptimizerSwitch map[string]string `json:"optimizer_switch,omitempty" jsonschema:"optimizer_switch flags set_session_setting turned on or off"`
+}
+
+type SessionSettingsOutput struct {
+	Connection           string              `json:"connection" jsonschema:"connection the settings apply to"`
+	SQLMode              string              `json:"sql_mode" jsonschema:"effective sql_mode of query tools"`
+	OptimizerSwitch      map[string]string   `json:"optimizer_switch" jsonschema:"effective optimizer_switch flags (on or off)"`
+	TransactionIsolation string              `json:"transaction_isolation" jsonschema:"session transaction isolation level"`
+	Overrides            SessionOverrideInfo `json:"overrides" jsonschema:"changes from set_session_setting applied on top of the server defaults"`
+}
+
+type SetSessionSettingInput struct {
+	Setting string            `json:"setting" validate:"required,oneof=sql_mode|optimizer_switch" jsonschema:"sql_mode or optimizer_switch"`
+	Add     []string          `json:"add,omitempty" jsonschema:"sql_mode: modes to add, e.g. ONLY_FULL_GROUP_BY"`
+	Remove  []string          `json:"remove,omitempty" jsonschema:"sql_mode: modes to remove"`
+	Flags   map[string]string `json:"flags,omitempty" jsonschema:"optimizer_switch: flags to turn on or off, e.g. {\"hash_join\": \"off\"}"`
+	Reset   bool              `json:"reset,omitempty" jsonschema:"drop the overrides of this setting before applying add, remove or flags"`
+}
+
+// ===== Multi-DSN Tool Types =====
+
+type ListConnectionsInput struct{}
+
+type ConnectionInfo struct {
+	Name            string        `json:"name" jsonschema:"connection name"`
+	DSN             string        `json:"dsn" jsonschema:"masked DSN (password hidden)"`
+	Description     string        `json:"description,omitempty" jsonschema:"connection description"`
+	Environment     string        `json:"environment,omitempty" jsonschema:"environment label such as prod, staging or dev"`
+	Tags            []string      `json:"tags,omitempty" jsonschema:"free-form connection labels"`
+	RequiresConfirm bool          `json:"requires_confirm,omitempty" jsonschema:"true when run_query needs confirm=true on this connection"`
+	RowPolicies     []string      `json:"row_policies,omitempty" jsonschema:"tables whose reads are filtered by a mandatory row policy predicate"`
+	Replicas        []string      `json:"replicas,omitempty" jsonschema:"replica connections that serve this connection's query tools"`
+	ReplicaRouting  string        `json:"replica_routing,omitempty" jsonschema:"how reads are spread over the replicas: round_robin or least_lag"`
+	ReplicaOf       string        `json:"replica_of,omitempty" jsonschema:"for a replica, the connection whose reads it serves"`
+	ConnectOnDemand bool          `json:"connect_on_demand,omitempty" jsonschema:"true when the connection is opened on first use rather than at startup"`
+	Pending         bool          `json:"pending,omitempty" jsonschema:"true while a connect_on_demand connection has not been used yet"`
+	Circuit         *CircuitState `json:"circuit,omitempty" jsonschema:"circuit breaker state; open means calls fail fast after repeated connection failures"`
+	Active          bool          `json:"active" jsonschema:"true if this is the active connection"`
+	AuroraRole      string        `json:"aurora_role,omitempty" jsonschema:"on Aurora, the role of the active connection's instance: writer or reader"`
+}
+
+type ListConnectionsOutput struct {
+	Connections []ConnectionInfo `json:"connections" jsonschema:"list of available connections"`
+	Active      string           `json:"active" jsonschema:"name of the currently active connection"`
+
+	VirtualConnections []VirtualConnectionInfo `json:"virtual_connections,omitempty" jsonschema:"tenant scopes defined in the config file, which only MYSQL_MCP_VIRTUAL_CONNECTION can lock the server to; only the locked one once a virtual connection is in use"`
+	VirtualConnection  string                  `json:"virtual_connection,omitempty" jsonschema:"virtual connection every call is locked to"`
+}
+
+// VirtualConnectionInfo describes a tenant scope on a shared connection.
+type VirtualConnectionInfo struct {
+	Name        string   `json:"name"`
+	Connection  string   `json:"connection,omitempty" jsonschema:"physical connection it runs on; empty for the active one"`
+	Database    string   `json:"database,omitempty" jsonschema:"default database for tools called without one"`
+	Schemas     []string `json:"schemas" jsonschema:"schema patterns it may access; % matches any characters"`
+	Description string   `json:"description,omitempty"`
+	Active      bool     `json:"active"`
+}
+
+type PoolStatsInput struct{}
+
+type PoolStats struct {
+	Name              string        `json:"name" jsonschema:"connection name"`
+	Active            bool          `json:"active" jsonschema:"true if this is the active connection"`
+	MaxOpen           int           `json:"max_open" jsonschema:"maximum open connections (0 = unlimited)"`
+	Open              int           `json:"open" jsonschema:"established connections, in use and idle"`
+	InUse             int           `json:"in_use" jsonschema:"connections currently in use"`
+	Idle              int           `json:"idle" jsonschema:"idle connections"`
+	WaitCount         int64         `json:"wait_count" jsonschema:"total times a caller waited for a free connection"`
+	WaitDurationMs    int64         `json:"wait_duration_ms" jsonschema:"total time spent waiting for a free connection"`
+	MaxIdleClosed     int64         `json:"max_idle_closed" jsonschema:"connections closed because of MYSQL_MAX_IDLE_CONNS"`
+	MaxIdleTimeClosed int64         `json:"max_idle_time_closed" jsonschema:"connections closed because of the idle timeout"`
+	MaxLifetimeClosed int64         `json:"max_lifetime_closed" jsonschema:"connections closed because of MYSQL_CONN_MAX_LIFETIME"`
+	Circuit           *CircuitState `json:"circuit,omitempty" jsonschema:"circuit breaker state of the connection"`
+}
+
+type PoolStatsOutput struct {
+	Pools       []PoolStats `json:"pools" jsonschema:"pool statistics per configured connection"`
+	Active      string      `json:"active" jsonschema:"name of the currently active connection"`
+	QueuedCalls int         `json:"queued_calls,omitempty" jsonschema:"tool calls waiting for a query slot (MYSQL_MCP_QUERY_QUEUE_DEPTH)"`
+}
+
+type UsageStatsInput struct {
+	Tool string `json:"tool,omitempty" jsonschema:"only report this tool"`
+}
+
+type ToolUsageStats struct {
+	Tool         string  `json:"tool" jsonschema:"tool name"`
+	Calls        int64   `json:"calls" jsonschema:"calls since startup"`
+	Errors       int64   `json:"errors" jsonschema:"calls that returned an error"`
+	ErrorRate    float64 `json:"error_rate" jsonschema:"errors divided by calls"`
+	P50Ms        float64 `json:"p50_ms" jsonschema:"median latency in milliseconds over the last 1000 calls"`
+	P95Ms        float64 `json:"p95_ms" jsonschema:"95th percentile latency in milliseconds over the last 1000 calls"`
+	AvgMs        float64 `json:"avg_ms" jsonschema:"mean latency in milliseconds since startup"`
+	RowsReturned int64   `json:"rows_returned" jsonschema:"result rows returned (query, saved query and report tools)"`
+	LastCallAt   string  `json:"last_call_at,omitempty" jsonschema:"time of the most recent call (RFC3339)"`
+
+	totalSeconds float64 // unrounded, for the Prometheus summary sum
+}
+
+type UsageStatsOutput struct {
+	UptimeSeconds int              `json:"uptime_seconds" jsonschema:"seconds since the server started"`
+	TotalCalls    int64            `json:"total_calls" jsonschema:"calls of all tools"`
+	TotalErrors   int64            `json:"total_errors" jsonschema:"failed calls of all tools"`
+	Tools         []ToolUsageStats `json:"tools" jsonschema:"per-tool statistics, most called first; registered tools never called have zero calls"`
+}
+
+type UseConnectionInput struct {
+	Name string `json:"name" validate:"required" jsonschema:"name of the connection or virtual connection to switch to; a virtual connection locks the session to its schemas"`
+}
+
+type UseConnectionOutput struct {
+	Success  bool   `json:"success" jsonschema:"true if switch was successful"`
+	Active   string `json:"active" jsonschema:"name of the now-active connection"`
+	Message  string `json:"message" jsonschema:"status message"`
+	Database string `json:"database,omitempty" jsonschema:"current database of the connection"`
+
+	VirtualConnection string `json:"virtual_connection,omitempty" jsonschema:"virtual connection now in use"`
+}
+
+// ===== Diagnostic / admin tools (extended, gated by config) =====
+
+type ProcessListInput struct{}
+
+type ProcessRow struct {
+	ID      int64  `json:"id" jsonschema:"connection / thread id"`
+	User    string `json:"user" jsonschema:"user@host"`
+	Host    string `json:"host" jsonschema:"client host"`
+	DB      string `json:"db,omitempty" jsonschema:"default database"`
+	Command string `json:"command" jsonschema:"thread command"`
+	Time    int    `json:"time" jsonschema:"seconds in current state"`
+	State   string `json:"state,omitempty"`
+	Info    string `json:"info,omitempty" jsonschema:"statement (truncated)"`
+}
+
+type ProcessListOutput struct {
+	Processes []ProcessRow `json:"processes" jsonschema:"active server threads"`
+	Note      string       `json:"note,omitempty" jsonschema:"privilege or compatibility note"`
+}
+
+type ListSessionsInput struct {
+	IncludeOtherUsers bool   `json:"include_other_users,omitempty" jsonschema:"include sessions of other MySQL users (default: only the connected user)"`
+	User              string `json:"user,omitempty" jsonschema:"only sessions for this MySQL user name"`
+	Command           string `json:"command,omitempty" jsonschema:"only sessions with this command (e.g. Query, Sleep)"`
+	ExcludeSleeping   bool   `json:"exclude_sleeping,omitempty" jsonschema:"omit idle sessions (command Sleep)"`
+}
+
+type SessionRow struct {
+	ID      int64  `json:"id" jsonschema:"connection / processlist id"`
+	User    string `json:"user" jsonschema:"MySQL user name"`
+	Host    string `json:"host,omitempty" jsonschema:"client host without port"`
+	DB      string `json:"db,omitempty" jsonschema:"default database"`
+	Command string `json:"command" jsonschema:"thread command"`
+	Time    int64  `json:"time" jsonschema:"seconds in current state"`
+	State   string `json:"state,omitempty"`
+	Info    string `json:"info,omitempty" jsonschema:"statement with credentials redacted (truncated)"`
+}
+
+type ListSessionsOutput struct {
+	Sessions    []SessionRow `json:"sessions" jsonschema:"client sessions (read-only view, no kill support)"`
+	Source      string       `json:"source" jsonschema:"performance_schema or processlist"`
+	CurrentUser string       `json:"current_user,omitempty" jsonschema:"connected MySQL user used for default filtering"`
+	Truncated   bool         `json:"truncated,omitempty" jsonschema:"true if the session cap was reached"`
+	Note        string       `json:"note,omitempty" jsonschema:"privilege or fallback note"`
+}
+
+type KillQueryInput struct {
+	ID int64 `json:"id" jsonschema:"connection/thread id from process_list (KILL QUERY target)"`
+}
+
+type KillQueryOutput struct {
+	OK      bool   `json:"ok" jsonschema:"true if KILL QUERY executed"`
+	Message string `json:"message" jsonschema:"result message"`
+}
+
+type ReadAuditLogInput struct {
+	Lines int `json:"lines,omitempty" jsonschema:"max lines from end of file (default 50, max 500)"`
+}
+
+type ReadAuditLogOutput struct {
+	Path      string   `json:"path" jsonschema:"audit file path"`
+	Lines     []string `json:"lines" jsonschema:"recent log lines (JSON entries)"`
+	Truncated bool     `json:"truncated,omitempty" jsonschema:"true if byte limit hit before reading full tail"`
+}
+
+type SlowQueryLogInput struct {
+	Limit int `json:"limit,omitempty" jsonschema:"max rows from mysql.slow_log (default 20, max 200)"`
+}
+
+type SlowQueryLogRow struct {
+	StartTime    string `json:"start_time,omitempty"`
+	UserHost     string `json:"user_host,omitempty"`
+	QueryTime    string `json:"query_time,omitempty"`
+	LockTime     string `json:"lock_time,omitempty"`
+	RowsSent     int64  `json:"rows_sent,omitempty"`
+	RowsExamined int64  `json:"rows_examined,omitempty"`
+	Database     string `json:"db,omitempty"`
+	LastInsertID int64  `json:"last_insert_id,omitempty"`
+	InsertID     int64  `json:"insert_id,omitempty"`
+	Query        string `json:"query,omitempty" jsonschema:"truncated SQL text"`
+}
+
+type SlowQueryLogOutput struct {
+	Mode        string            `json:"mode" jsonschema:"table_rows, file, or disabled"`
+	Message     string            `json:"message,omitempty"`
+	Settings    map[string]string `json:"settings,omitempty" jsonschema:"relevant @@variables when not table mode"`
+	Rows        []SlowQueryLogRow `json:"rows,omitempty"`
+	SlowQueries int64             `json:"slow_queries_status,omitempty" jsonschema:"Slow_queries counter"`
+}
+
+// ===== Vector Tool Types (MySQL 9.0+) =====
+
+type VectorSearchInput struct {
+	Database     string      `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table        string      `json:"table" validate:"required,ident" jsonschema:"table name containing vector column"`
+	Column       string      `json:"column" validate:"required,ident" jsonschema:"name of the vector column"`
+	Query        []float64   `json:"query,omitempty" jsonschema:"query vector for similarity search"`
+	Queries      [][]float64 `json:"queries,omitempty" validate:"max=32" jsonschema:"several query vectors searched in one call (max 32), results grouped per vector in batches; instead of query"`
+	Limit        int         `json:"limit,omitempty" jsonschema:"max results to return (default: 10)"`
+	Select       string      `json:"select,omitempty" jsonschema:"additional columns to select (comma-separated)"`
+	Where        string      `json:"where,omitempty" jsonschema:"additional WHERE conditions"`
+	DistanceFunc string      `json:"distance_func,omitempty" validate:"oneof=cosine|euclidean|l2|dot|inner_product" jsonschema:"distance function: cosine, euclidean, dot (default: cosine)"`
+	MaxDistance  *float64    `json:"max_distance,omitempty" jsonschema:"only return rows whose distance is at most this value"`
+	ReturnVector bool        `json:"return_vector,omitempty" jsonschema:"include each row's stored vector in the results"`
+}
+
+type VectorSearchResult struct {
+	Distance float64                `json:"distance" jsonschema:"distance/similarity score"`
+	Data     map[string]interface{} `json:"data" jsonschema:"row data"`
+	Vector   []float64              `json:"vector,omitempty" jsonschema:"stored vector (with return_vector)"`
+}
+
+type VectorSearchBatch struct {
+	Query   int                  `json:"query" jsonschema:"index of the vector in queries"`
+	Results []VectorSearchResult `json:"results" jsonschema:"search results ordered by similarity"`
+	Count   int                  `json:"count" jsonschema:"number of results"`
+}
+
+type VectorSearchOutput struct {
+	Results []VectorSearchResult `json:"results" jsonschema:"search results ordered by similarity (empty when queries is used)"`
+	Count   int                  `json:"count" jsonschema:"number of results (across all batches when queries is used)"`
+	Batches []VectorSearchBatch  `json:"batches,omitempty" jsonschema:"results per query vector, in the order of queries"`
+}
+
+type VectorInfoInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table,omitempty" validate:"ident" jsonschema:"table name (optional, lists all if empty)"`
+}
+
+type VectorColumnInfo struct {
+	Table      string `json:"table" jsonschema:"table name"`
+	Column     string `json:"column" jsonschema:"column name"`
+	Dimensions int    `json:"dimensions" jsonschema:"vector dimensions"`
+	IndexName  string `json:"index_name,omitempty" jsonschema:"vector index name if exists"`
+	IndexType  string `json:"index_type,omitempty" jsonschema:"vector index type"`
+}
+
+type VectorInfoOutput struct {
+	Columns       []VectorColumnInfo `json:"columns" jsonschema:"vector columns found"`
+	VectorSupport bool               `json:"vector_support" jsonschema:"true if MySQL supports VECTOR type"`
+	MySQLVersion  string             `json:"mysql_version" jsonschema:"MySQL version"`
+}
+
+type VectorInsertRow struct {
+	Vector []float64              `json:"vector" jsonschema:"embedding; its length must match the column's dimensions"`
+	Values map[string]interface{} `json:"values,omitempty" jsonschema:"other columns of the row (column name to value); every row must set the same columns"`
+}
+
+type VectorInsertInput struct {
+	Database string            `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string            `json:"table" validate:"required,ident" jsonschema:"table name containing the vector column"`
+	Column   string            `json:"column" validate:"required,ident" jsonschema:"name of the vector column"`
+	Rows     []VectorInsertRow `json:"rows" validate:"required,max=1000" jsonschema:"rows to insert (max 1000)"`
+	Upsert   bool              `json:"upsert,omitempty" jsonschema:"update the row instead when it collides with a primary or unique key"`
+	Confirm  bool              `json:"confirm,omitempty" jsonschema:"set true for connections that require confirmation"`
+}
+
+type VectorInsertOutput struct {
+	RowsAffected int64 `json:"rows_affected" jsonschema:"rows inserted (an upserted row that changed counts twice)"`
+	LastInsertID int64 `json:"last_insert_id,omitempty" jsonschema:"AUTO_INCREMENT id of the first inserted row"`
+	Dimensions   int   `json:"dimensions" jsonschema:"dimensions of the vector column"`
+}
+
+type VectorDeleteInput struct {
+	Database  string        `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table     string        `json:"table" validate:"required,ident" jsonschema:"table name containing the vector column"`
+	Column    string        `json:"column" validate:"required,ident" jsonschema:"name of the vector column (checked to be a VECTOR column)"`
+	KeyColumn string        `json:"key_column" validate:"required,ident" jsonschema:"column the keys are matched against, usually the primary key"`
+	Keys      []interface{} `json:"keys" validate:"required,max=1000" jsonschema:"key values of the rows to delete (max 1000)"`
+	Confirm   bool          `json:"confirm,omitempty" jsonschema:"set true for connections that require confirmation"`
+}
+
+type VectorDeleteOutput struct {
+	RowsAffected int64 `json:"rows_affected" jsonschema:"rows deleted"`
+}
+
+// ===== Extended Tool Types (MYSQL_MCP_EXTENDED=1) =====
+
+type ListIndexesInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table" validate:"required,ident" jsonschema:"table name"`
+}
+
+type IndexInfo struct {
+	Name      string `json:"name" jsonschema:"index name"`
+	Columns   string `json:"columns" jsonschema:"columns in the index"`
+	NonUnique bool   `json:"non_unique" jsonschema:"true if index allows duplicates"`
+	Type      string `json:"type" jsonschema:"index type (BTREE, HASH, etc.)"`
+}
+
+type ListIndexesOutput struct {
+	Indexes []IndexInfo `json:"indexes" jsonschema:"list of indexes on the table"`
+}
+
+type ShowCreateTableInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table" validate:"required,ident" jsonschema:"table name"`
+}
+
+type ShowCreateTableOutput struct {
+	CreateStatement string `json:"create_statement" jsonschema:"CREATE TABLE statement"`
+}
+
+type ExplainQueryInput struct {
+	SQL      string `json:"sql" validate:"required" jsonschema:"SELECT query to explain"`
+	Database string `json:"database,omitempty" validate:"ident" jsonschema:"optional database context"`
+	Format   string `json:"format,omitempty" jsonschema:"output format: traditional, json, tree (default: traditional)"`
+}
+
+type ExplainQueryOutput struct {
+	Plan     []map[string]interface{} `json:"plan" jsonschema:"query execution plan"`
+	Warnings []string                 `json:"warnings,omitempty" jsonschema:"actionable optimization suggestions derived from the execution plan"`
+}
+
+type CheckPartitionPruningInput struct {
+	SQL      string `json:"sql" validate:"required" jsonschema:"SELECT query to analyze"`
+	Database string `json:"database,omitempty" validate:"ident" jsonschema:"database context (needed to resolve unqualified table names)"`
+}
+
+type PartitionPruningTable struct {
+	Table             string   `json:"table" jsonschema:"schema-qualified table name"`
+	Alias             string   `json:"alias,omitempty" jsonschema:"name of the table in the plan"`
+	Method            string   `json:"method,omitempty" jsonschema:"partitioning method (RANGE, LIST, HASH, KEY, ...)"`
+	Expression        string   `json:"expression,omitempty" jsonschema:"partitioning expression"`
+	Subpartitioned    bool     `json:"subpartitioned,omitempty" jsonschema:"true when partitions are subpartitioned (counts are per subpartition)"`
+	Total             int      `json:"total_partitions" jsonschema:"number of partitions in information_schema.PARTITIONS"`
+	Scanned           int      `json:"scanned_partitions" jsonschema:"number of partitions EXPLAIN will access"`
+	ScannedPartitions []string `json:"partitions" jsonschema:"partitions EXPLAIN will access"`
+	Pruned            bool     `json:"pruned" jsonschema:"true when fewer partitions are scanned than exist"`
+}
+
+type CheckPartitionPruningOutput struct {
+	Pruned bool                     `json:"pruned" jsonschema:"true when every partitioned table in the plan is pruned"`
+	Tables []PartitionPruningTable  `json:"tables" jsonschema:"partitioned tables accessed by the plan"`
+	Notes  []string                 `json:"notes,omitempty" jsonschema:"suggestions and tables that could not be analyzed"`
+	Plan   []map[string]interface{} `json:"plan" jsonschema:"EXPLAIN output including the partitions column"`
+}
+
+type OptimizerTraceInput struct {
+	SQL      string `json:"sql" validate:"required" jsonschema:"SELECT query to trace (EXPLAIN only; the query is not executed)"`
+	Database string `json:"database,omitempty" validate:"ident" jsonschema:"optional database context"`
+	MaxBytes int    `json:"max_bytes,omitempty" jsonschema:"trace size cap in bytes (default 1 MiB, max 16 MiB)"`
+}
+
+type OptimizerTraceOutput struct {
+	Plan                   []map[string]interface{} `json:"plan" jsonschema:"EXPLAIN output for the query"`
+	Trace                  interface{}              `json:"trace,omitempty" jsonschema:"optimizer trace JSON"`
+	TraceText              string                   `json:"trace_text,omitempty" jsonschema:"raw trace text when it was truncated and is not valid JSON"`
+	Truncated              bool                     `json:"truncated,omitempty" jsonschema:"true when the trace exceeded max_bytes"`
+	MissingBytes           int64                    `json:"missing_bytes,omitempty" jsonschema:"bytes cut from the trace by the size cap"`
+	InsufficientPrivileges bool                     `json:"insufficient_privileges,omitempty" jsonschema:"true when MySQL withheld the trace for lack of privileges"`
+	Notes                  []string                 `json:"notes,omitempty" jsonschema:"truncation and privilege notes"`
+}
+
+type EstimateRowsInput struct {
+	SQL      string `json:"sql,omitempty" jsonschema:"SELECT query to estimate via EXPLAIN (not executed)"`
+	Database string `json:"database,omitempty" jsonschema:"database context; required with table"`
+	Table    string `json:"table,omitempty" jsonschema:"table whose information_schema TABLE_ROWS estimate to return"`
+}
+
+type PlanRowEstimate struct {
+	Table      string  `json:"table" jsonschema:"table name as shown in the plan"`
+	AccessType string  `json:"access_type,omitempty" jsonschema:"EXPLAIN type (ALL, range, ref, ...)"`
+	Rows       int64   `json:"rows" jsonschema:"rows the optimizer expects to examine"`
+	Filtered   float64 `json:"filtered" jsonschema:"percentage of examined rows expected to match the conditions"`
+}
+
+type EstimateRowsOutput struct {
+	EstimatedRows  *int64                   `json:"estimated_rows,omitempty" jsonschema:"optimizer estimate of rows the SELECT returns (rows x filtered across the top-level join)"`
+	RowsExamined   *int64                   `json:"rows_examined,omitempty" jsonschema:"sum of rows examined across all plan rows"`
+	PlanRows       []PlanRowEstimate        `json:"plan_rows,omitempty" jsonschema:"per-table estimates from EXPLAIN"`
+	TableRows      *int64                   `json:"table_rows,omitempty" jsonschema:"information_schema.TABLES.TABLE_ROWS (approximate for InnoDB)"`
+	Recommendation string                   `json:"recommendation" jsonschema:"run, paginate, refine, or unknown, comparing the estimate with the row cap"`
+	RowCap         int                      `json:"row_cap" jsonschema:"row cap run_query would apply"`
+	Notes          []string                 `json:"notes,omitempty" jsonschema:"accuracy caveats"`
+	Plan           []map[string]interface{} `json:"plan,omitempty" jsonschema:"raw EXPLAIN output"`
+}
+
+type NormalizeQueryInput struct {
+	SQL string `json:"sql" validate:"required" jsonschema:"SQL statement to normalize (not executed)"`
+}
+
+type NormalizeQueryOutput struct {
+	Fingerprint   string   `json:"fingerprint" jsonschema:"statement with literals replaced by ? and IN lists collapsed"`
+	Digest        string   `json:"digest" jsonschema:"SHA-256 of the fingerprint; equal for queries that differ only in literals"`
+	StatementType string   `json:"statement_type" jsonschema:"select, union, show, explain, or other"`
+	Tables        []string `json:"tables" jsonschema:"referenced tables"`
+	Columns       []string `json:"columns" jsonschema:"referenced columns (aliases resolved to table names)"`
+	Parsed        bool     `json:"parsed" jsonschema:"false when only a lexical fingerprint was possible (tables/columns empty)"`
+}
+
+type ListViewsInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+}
+
+type ViewInfo struct {
+	Name        string `json:"name" jsonschema:"view name"`
+	Definer     string `json:"definer" jsonschema:"view definer"`
+	Security    string `json:"security" jsonschema:"security type (DEFINER or INVOKER)"`
+	IsUpdatable string `json:"is_updatable" jsonschema:"YES if view is updatable"`
+}
+
+type ListViewsOutput struct {
+	Views []ViewInfo `json:"views" jsonschema:"list of views in the database"`
+}
+
+type ViewLineageInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database holding the views"`
+	View     string `json:"view,omitempty" validate:"ident" jsonschema:"optional view: only trace this one"`
+}
+
+type LineageSource struct {
+	Database string `json:"database,omitempty" jsonschema:"database of the source table"`
+	Table    string `json:"table,omitempty" jsonschema:"table the value is read from; empty when an unqualified column could come from more than one table"`
+	Column   string `json:"column" jsonschema:"source column, or * for all columns of the table"`
+	View     bool   `json:"view,omitempty" jsonschema:"true when the source is itself a view; trace it with another view_lineage call"`
+}
+
+type ViewColumnLineage struct {
+	Column     string          `json:"column" jsonschema:"view column"`
+	Sources    []LineageSource `json:"sources" jsonschema:"columns the value derives from"`
+	Derived    bool            `json:"derived,omitempty" jsonschema:"true when computed by an expression or aggregate rather than copied from one column"`
+	Expression string          `json:"expression,omitempty" jsonschema:"expression computing a derived column"`
+}
+
+type ViewLineage struct {
+	View       string              `json:"view" jsonschema:"view name"`
+	BaseTables []string            `json:"base_tables,omitempty" jsonschema:"tables and views the definition reads, as database.table"`
+	Columns    []ViewColumnLineage `json:"columns,omitempty" jsonschema:"lineage of each view column, in view order"`
+	Error      string              `json:"error,omitempty" jsonschema:"why the definition could not be traced"`
+}
+
+type ViewLineageOutput struct {
+	Database string        `json:"database" jsonschema:"database name"`
+	Views    []ViewLineage `json:"views" jsonschema:"views sorted by name"`
+	Notes    []string      `json:"notes,omitempty" jsonschema:"limits and privilege caveats"`
+}
+
+type ListTriggersInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+}
+
+type TriggerInfo struct {
+	Name      string `json:"name" jsonschema:"trigger name"`
+	Event     string `json:"event" jsonschema:"triggering event (INSERT, UPDATE, DELETE)"`
+	Table     string `json:"table" jsonschema:"table the trigger is on"`
+	Timing    string `json:"timing" jsonschema:"BEFORE or AFTER"`
+	Statement string `json:"statement" jsonschema:"trigger body (truncated)"`
+}
+
+type ListTriggersOutput struct {
+	Triggers []TriggerInfo `json:"triggers" jsonschema:"list of triggers"`
+}
+
+type ListProceduresInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+}
+
+type ProcedureInfo struct {
+	Name      string `json:"name" jsonschema:"procedure name"`
+	Definer   string `json:"definer" jsonschema:"procedure definer"`
+	Created   string `json:"created" jsonschema:"creation timestamp"`
+	Modified  string `json:"modified" jsonschema:"last modified timestamp"`
+	ParamList string `json:"parameters" jsonschema:"parameter list"`
+}
+
+type ListProceduresOutput struct {
+	Procedures []ProcedureInfo `json:"procedures" jsonschema:"list of stored procedures"`
+}
+
+type ListFunctionsInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+}
+
+type FunctionInfo struct {
+	Name    string `json:"name" jsonschema:"function name"`
+	Definer string `json:"definer" jsonschema:"function definer"`
+	Returns string `json:"returns" jsonschema:"return type"`
+	Created string `json:"created" jsonschema:"creation timestamp"`
+}
+
+type ListFunctionsOutput struct {
+	Functions []FunctionInfo `json:"functions" jsonschema:"list of stored functions"`
+}
+
+type ListPartitionsInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table" validate:"required,ident" jsonschema:"table name"`
+}
+
+type PartitionInfo struct {
+	Name        string `json:"name" jsonschema:"partition name"`
+	Method      string `json:"method" jsonschema:"partitioning method"`
+	Expression  string `json:"expression" jsonschema:"partitioning expression"`
+	Description string `json:"description" jsonschema:"partition description/value"`
+	TableRows   int64  `json:"table_rows" jsonschema:"approximate row count"`
+	DataLength  int64  `json:"data_length" jsonschema:"data size in bytes"`
+}
+
+type ListPartitionsOutput struct {
+	Partitions []PartitionInfo `json:"partitions" jsonschema:"list of partitions"`
+}
+
+type DatabaseSizeInput struct {
+	Database string `json:"database,omitempty" jsonschema:"database name (optional, all databases if empty)"`
+}
+
+type DatabaseSizeInfo struct {
+	Name    string  `json:"name" jsonschema:"database name"`
+	SizeMB  float64 `json:"size_mb" jsonschema:"total size in megabytes"`
+	DataMB  float64 `json:"data_mb" jsonschema:"data size in megabytes"`
+	IndexMB float64 `json:"index_mb" jsonschema:"index size in megabytes"`
+	Tables  int     `json:"tables" jsonschema:"number of tables"`
+}
+
+type DatabaseSizeOutput struct {
+	Databases []DatabaseSizeInfo `json:"databases" jsonschema:"database size information"`
+}
+
+type TableSizeInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table,omitempty" validate:"ident" jsonschema:"table name (optional, all tables if empty)"`
+}
+
+type TableSizeInfo struct {
+	Name    string  `json:"name" jsonschema:"table name"`
+	Rows    int64   `json:"rows" jsonschema:"approximate row count"`
+	DataMB  float64 `json:"data_mb" jsonschema:"data size in megabytes"`
+	IndexMB float64 `json:"index_mb" jsonschema:"index size in megabytes"`
+	TotalMB float64 `json:"total_mb" jsonschema:"total size in megabytes"`
+	Engine  string  `json:"engine" jsonschema:"storage engine"`
+}
+
+type TableSizeOutput struct {
+	Tables []TableSizeInfo `json:"tables" jsonschema:"table size information"`
+}
+
+type ForeignKeysInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table,omitempty" validate:"ident" jsonschema:"table name (optional)"`
+}
+
+type ForeignKeyInfo struct {
+	Name             string `json:"name" jsonschema:"constraint name"`
+	Table            string `json:"table" jsonschema:"table name"`
+	Column           string `json:"column" jsonschema:"column name"`
+	ReferencedTable  string `json:"referenced_table" jsonschema:"referenced table"`
+	ReferencedColumn string `json:"referenced_column" jsonschema:"referenced column"`
+	OnUpdate         string `json:"on_update" jsonschema:"ON UPDATE action"`
+	OnDelete         string `json:"on_delete" jsonschema:"ON DELETE action"`
+}
+
+type ForeignKeysOutput struct {
+	ForeignKeys []ForeignKeyInfo `json:"foreign_keys" jsonschema:"list of foreign key constraints"`
+}
+
+type TableConstraintsInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table" validate:"required,ident" jsonschema:"table name"`
+}
+
+type TableConstraintInfo struct {
+	Name              string   `json:"name" jsonschema:"constraint name"`
+	Type              string   `json:"type" jsonschema:"PRIMARY KEY, UNIQUE, FOREIGN KEY or CHECK"`
+	Columns           []string `json:"columns,omitempty" jsonschema:"key columns in order (not set for CHECK)"`
+	ReferencedTable   string   `json:"referenced_table,omitempty" jsonschema:"referenced table for foreign keys (schema-qualified when in another database)"`
+	ReferencedColumns []string `json:"referenced_columns,omitempty" jsonschema:"referenced columns for foreign keys"`
+	CheckClause       string   `json:"check_clause,omitempty" jsonschema:"expression of a CHECK constraint"`
+}
+
+type TableConstraintsOutput struct {
+	Constraints []TableConstraintInfo `json:"constraints" jsonschema:"constraints declared on the table"`
+	Notes       []string              `json:"notes,omitempty" jsonschema:"server limitations affecting the result"`
+}
+
+type SpatialInfoInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string `json:"table,omitempty" jsonschema:"table name (optional)"`
+}
+
+type SpatialColumnInfo struct {
+	Table        string `json:"table" jsonschema:"table name"`
+	Column       string `json:"column" jsonschema:"column name"`
+	Type         string `json:"type" jsonschema:"spatial type (geometry, point, polygon, ...)"`
+	Nullable     bool   `json:"nullable" jsonschema:"whether the column allows NULL"`
+	SRID         *int   `json:"srid,omitempty" jsonschema:"SRID attribute of the column (MySQL 8.0+); absent when the column accepts any SRID"`
+	SRSName      string `json:"srs_name,omitempty" jsonschema:"name of the spatial reference system"`
+	SpatialIndex string `json:"spatial_index,omitempty" jsonschema:"name of the SPATIAL index on the column, if any"`
+}
+
+type SpatialIndexInfo struct {
+	Table  string `json:"table" jsonschema:"table name"`
+	Name   string `json:"name" jsonschema:"index name"`
+	Column string `json:"column" jsonschema:"indexed column"`
+}
+
+type SpatialInfoOutput struct {
+	Columns []SpatialColumnInfo `json:"columns" jsonschema:"spatial columns"`
+	Indexes []SpatialIndexInfo  `json:"indexes" jsonschema:"SPATIAL indexes"`
+	Notes   []string            `json:"notes,omitempty" jsonschema:"server limitations and index usability warnings"`
+}
+
+type SchemaGraphInput struct {
+	Database        string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Format          string `json:"format,omitempty" jsonschema:"optional text rendering: json (default, nodes/edges only), dot or mermaid"`
+	IncludeIsolated bool   `json:"include_isolated,omitempty" jsonschema:"also include tables that have no foreign key relationships"`
+}
+
+type SchemaGraphNode struct {
+	Name         string `json:"name" jsonschema:"table name (schema-qualified when outside the requested database)"`
+	References   int    `json:"references" jsonschema:"number of outgoing foreign keys"`
+	ReferencedBy int    `json:"referenced_by" jsonschema:"number of incoming foreign keys"`
+	External     bool   `json:"external,omitempty" jsonschema:"true when the table lives in another database"`
+}
+
+type SchemaGraphEdge struct {
+	Constraint        string   `json:"constraint" jsonschema:"foreign key constraint name"`
+	From              string   `json:"from" jsonschema:"referencing (child) table"`
+	To                string   `json:"to" jsonschema:"referenced (parent) table"`
+	Columns           []string `json:"columns" jsonschema:"referencing columns in key order"`
+	ReferencedColumns []string `json:"referenced_columns" jsonschema:"referenced columns in key order"`
+	OnUpdate          string   `json:"on_update,omitempty" jsonschema:"ON UPDATE action"`
+	OnDelete          string   `json:"on_delete,omitempty" jsonschema:"ON DELETE action"`
+}
+
+type SchemaGraphOutput struct {
+	Database  string            `json:"database" jsonschema:"database the graph was built for"`
+	Nodes     []SchemaGraphNode `json:"nodes" jsonschema:"tables in the graph"`
+	Edges     []SchemaGraphEdge `json:"edges" jsonschema:"foreign key relationships (child -> parent)"`
+	Format    string            `json:"format" jsonschema:"rendering used for text"`
+	Text      string            `json:"text,omitempty" jsonschema:"DOT or Mermaid rendering when requested"`
+	Truncated bool              `json:"truncated,omitempty" jsonschema:"true if edges were capped at the row limit"`
+}
+
+type GenerateDataDictionaryInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Pattern  string `json:"pattern,omitempty" jsonschema:"optional LIKE pattern to filter table names"`
+	Offset   int    `json:"offset,omitempty" jsonschema:"zero-based table offset for pagination"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"tables per page (default 10, max 50)"`
+}
+
+type DataDictionaryTable struct {
+	Name        string            `json:"name" jsonschema:"table name"`
+	Engine      string            `json:"engine,omitempty" jsonschema:"storage engine"`
+	Comment     string            `json:"comment,omitempty" jsonschema:"table comment"`
+	RowEstimate int64             `json:"row_estimate" jsonschema:"approximate row count from information_schema"`
+	DataMB      float64           `json:"data_mb" jsonschema:"data size in megabytes"`
+	IndexMB     float64           `json:"index_mb" jsonschema:"index size in megabytes"`
+	Columns     []ColumnInfo      `json:"columns" jsonschema:"columns in ordinal order"`
+	Indexes     []IndexInfo       `json:"indexes" jsonschema:"indexes on the table"`
+	ForeignKeys []SchemaGraphEdge `json:"foreign_keys" jsonschema:"foreign keys declared on the table"`
+}
+
+type GenerateDataDictionaryOutput struct {
+	Database   string                `json:"database" jsonschema:"database name"`
+	Tables     []DataDictionaryTable `json:"tables" jsonschema:"documented tables for this page"`
+	HasMore    bool                  `json:"has_more,omitempty" jsonschema:"true when more tables remain"`
+	NextOffset *int                  `json:"next_offset,omitempty" jsonschema:"pass as offset to retrieve the next page when has_more is true"`
+}
+
+type ListStatusInput struct {
+	Pattern string   `json:"pattern,omitempty" jsonschema:"optional LIKE pattern to filter status variables"`
+	Offset  int      `json:"offset,omitempty" jsonschema:"zero-based offset for pagination"`
+	Limit   int      `json:"limit,omitempty" jsonschema:"variables per page (default and max: the server row limit)"`
+	Sort    string   `json:"sort,omitempty" jsonschema:"name or value, prefixed with - for descending; values sort numerically when numeric; default name"`
+	Filter  []string `json:"filter,omitempty" jsonschema:"conditions as field:op:value on name or value with op eq, ne, gt, gte, lt, lte or like (e.g. value:gt:100); all must match"`
+}
+
+type StatusVariable struct {
+	Name  string `json:"name" jsonschema:"variable name"`
+	Value string `json:"value" jsonschema:"variable value"`
+}
+
+type ListStatusOutput struct {
+	Variables  []StatusVariable `json:"variables" jsonschema:"server status variables"`
+	Total      int              `json:"total" jsonschema:"number of matching variables across all pages"`
+	HasMore    bool             `json:"has_more,omitempty" jsonschema:"true when more variables remain"`
+	NextOffset *int             `json:"next_offset,omitempty" jsonschema:"pass as offset to retrieve the next page when has_more is true"`
+}
+
+type ListVariablesInput struct {
+	Pattern string   `json:"pattern,omitempty" jsonschema:"optional LIKE pattern to filter variables"`
+	Offset  int      `json:"offset,omitempty" jsonschema:"zero-based offset for pagination"`
+	Limit   int      `json:"limit,omitempty" jsonschema:"variables per page (default and max: the server row limit)"`
+	Sort    string   `json:"sort,omitempty" jsonschema:"name or value, prefixed with - for descending; values sort numerically when numeric; default name"`
+	Filter  []string `json:"filter,omitempty" jsonschema:"conditions as field:op:value on name or value with op eq, ne, gt, gte, lt, lte or like (e.g. value:eq:ON); all must match"`
+}
+
+type ServerVariable struct {
+	Name  string `json:"name" jsonschema:"variable name"`
+	Value string `json:"value" jsonschema:"variable value"`
+}
+
+type ListVariablesOutput struct {
+	Variables  []ServerVariable `json:"variables" jsonschema:"server configuration variables"`
+	Total      int              `json:"total" jsonschema:"number of matching variables across all pages"`
+	HasMore    bool             `json:"has_more,omitempty" jsonschema:"true when more variables remain"`
+	NextOffset *int             `json:"next_offset,omitempty" jsonschema:"pass as offset to retrieve the next page when has_more is true"`
+}
+
+type SearchSchemaInput struct {
+	Pattern  string `json:"pattern" validate:"required" jsonschema:"search pattern for table or column names (uses SQL LIKE syntax, e.g., %user%)"`
+	Database string `json:"database,omitempty" validate:"ident" jsonschema:"optional database name to restrict search"`
+}
+
+type SchemaMatch struct {
+	Database string `json:"database" jsonschema:"database name"`
+	Table    string `json:"table" jsonschema:"table name"`
+	Column   string `json:"column,omitempty" jsonschema:"column name (if matched)"`
+	Type     string `json:"type" jsonschema:"match type: TABLE or COLUMN"`
+}
+
+type SearchSchemaOutput struct {
+	Matches []SchemaMatch `json:"matches" jsonschema:"list of schema matches"`
+}
+
+type FindColumnsInput struct {
+	Pattern          string   `json:"pattern,omitempty" jsonschema:"LIKE pattern for column names (e.g. %email%); pattern or data_type is required"`
+	DataType         string   `json:"data_type,omitempty" jsonschema:"optional LIKE pattern for the column data type (e.g. varchar, %int, json)"`
+	Database         string   `json:"database,omitempty" jsonschema:"optional database to search; all accessible databases when empty"`
+	ExcludeDatabases []string `json:"exclude_databases,omitempty" jsonschema:"databases to skip when searching across databases"`
+	IncludeSystem    bool     `json:"include_system,omitempty" jsonschema:"include mysql, sys, information_schema and performance_schema (default false)"`
+	Limit            int      `json:"limit,omitempty" jsonschema:"maximum columns to return (default and max: the server row limit)"`
+}
+
+type ColumnMatch struct {
+	Database   string `json:"database" jsonschema:"database name"`
+	Table      string `json:"table" jsonschema:"table name"`
+	Column     string `json:"column" jsonschema:"column name"`
+	DataType   string `json:"data_type" jsonschema:"data type (e.g. varchar)"`
+	ColumnType string `json:"column_type" jsonschema:"full column type (e.g. varchar(255))"`
+	Nullable   bool   `json:"nullable" jsonschema:"whether the column allows NULL"`
+	Key        string `json:"key,omitempty" jsonschema:"key type (PRI, UNI, MUL)"`
+	Comment    string `json:"comment,omitempty" jsonschema:"column comment"`
+}
+
+type FindColumnsOutput struct {
+	Columns   []ColumnMatch `json:"columns" jsonschema:"matching columns ordered by database, table and position"`
+	Count     int           `json:"count" jsonschema:"number of columns returned"`
+	Truncated bool          `json:"truncated,omitempty" jsonschema:"true when more columns matched than limit"`
+}
+
+type SuggestIdentifiersInput struct {
+	Prefix   string `json:"prefix,omitempty" jsonschema:"start of the name typed so far, or a misspelled name; may carry its context as database.table.prefix"`
+	Database string `json:"database,omitempty" validate:"ident" jsonschema:"database to suggest tables or columns of; databases are suggested when empty"`
+	Table    string `json:"table,omitempty" validate:"ident" jsonschema:"table to suggest columns of"`
+	Kind     string `json:"kind,omitempty" jsonschema:"database, table or column; defaults to columns when table is set, tables when database is set, databases otherwise"`
+	Limit    int    `json:"limit,omitempty" jsonschema:"maximum suggestions (default 20, max 100)"`
+}
+
+type IdentifierSuggestion struct {
+	Kind      string `json:"kind" jsonschema:"database, table or column"`
+	Name      string `json:"name" jsonschema:"name as the catalog spells it"`
+	Database  string `json:"database,omitempty" jsonschema:"database of a table or column"`
+	Table     string `json:"table,omitempty" jsonschema:"table of a column"`
+	Qualified string `json:"qualified" jsonschema:"database.table.column form of the name"`
+	Match     string `json:"match" jsonschema:"exact, prefix, contains or fuzzy (a likely typo)"`
+	Distance  int    `json:"distance,omitempty" jsonschema:"edit distance from prefix for fuzzy matches"`
+}
+
+type SuggestIdentifiersOutput struct {
+	Prefix      string                 `json:"prefix" jsonschema:"prefix matched, without its context"`
+	Database    string                 `json:"database,omitempty" jsonschema:"database searched"`
+	Table       string                 `json:"table,omitempty" jsonschema:"table searched"`
+	Kind        string                 `json:"kind" jsonschema:"kind of names suggested"`
+	Suggestions []IdentifierSuggestion `json:"suggestions" jsonschema:"matches, best first: exact, then prefix, contains and fuzzy matches"`
+	Truncated   bool                   `json:"truncated,omitempty" jsonschema:"true when more names matched than limit"`
+	CatalogAge  float64                `json:"catalog_age_seconds" jsonschema:"age of the cached names in seconds; names are reloaded after a minute"`
+	Notes       []string               `json:"notes,omitempty" jsonschema:"how the request was interpreted"`
+}
+
+type FulltextSearchInput struct {
+	Database string   `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table    string   `json:"table" validate:"required,ident" jsonschema:"table with a FULLTEXT index"`
+	Columns  []string `json:"columns,omitempty" jsonschema:"columns of the FULLTEXT index to match (optional when the table has a single FULLTEXT index)"`
+	Query    string   `json:"query" validate:"required" jsonschema:"search text (bound as a parameter)"`
+	Mode     string   `json:"mode,omitempty" validate:"oneof=natural|boolean|query_expansion" jsonschema:"natural (default), boolean or query_expansion"`
+	Select   string   `json:"select,omitempty" jsonschema:"columns to return (comma-separated, default *)"`
+	Where    string   `json:"where,omitempty" jsonschema:"additional WHERE conditions"`
+	Limit    int      `json:"limit,omitempty" jsonschema:"max results to return (default: 10)"`
+}
+
+type FulltextSearchResult struct {
+	Score float64                `json:"score" jsonschema:"relevance score (higher is more relevant)"`
+	Data  map[string]interface{} `json:"data" jsonschema:"row data"`
+}
+
+type FulltextSearchOutput struct {
+	Index   string                 `json:"index" jsonschema:"FULLTEXT index used"`
+	Columns []string               `json:"columns" jsonschema:"indexed columns matched"`
+	Mode    string                 `json:"mode" jsonschema:"search mode"`
+	Results []FulltextSearchResult `json:"results" jsonschema:"matching rows ordered by relevance"`
+	Count   int                    `json:"count" jsonschema:"number of results"`
+}
+
+type ProfileColumnInput struct {
+	Database   string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table      string `json:"table" validate:"required,ident" jsonschema:"table name"`
+	Column     string `json:"column" validate:"required,ident" jsonschema:"column to profile"`
+	TopK       int    `json:"top_k,omitempty" jsonschema:"number of most frequent values to return (default 10, max 100)"`
+	SampleSize int    `json:"sample_size,omitempty" jsonschema:"rows sampled for distinct count and top values (default 100000, max 1000000)"`
+}
+
+type ProfileValueCount struct {
+	Value interface{} `json:"value" jsonschema:"column value"`
+	Count int64       `json:"count" jsonschema:"occurrences in the sample"`
+}
+
+type ProfileMonthCount struct {
+	Month string `json:"month" jsonschema:"calendar month (YYYY-MM)"`
+	Count int64  `json:"count" jsonschema:"non-NULL values in the month"`
+}
+
+type ProfileColumnOutput struct {
+	Database        string              `json:"database" jsonschema:"database name"`
+	Table           string              `json:"table" jsonschema:"table name"`
+	Column          string              `json:"column" jsonschema:"column name"`
+	DataType        string              `json:"data_type" jsonschema:"column data type"`
+	RowCount        int64               `json:"row_count" jsonschema:"rows in the table"`
+	NonNullCount    int64               `json:"non_null_count" jsonschema:"rows where the column is not NULL"`
+	NullCount       int64               `json:"null_count" jsonschema:"rows where the column is NULL"`
+	NullFraction    float64             `json:"null_fraction" jsonschema:"null_count / row_count"`
+	Min             interface{}         `json:"min,omitempty" jsonschema:"smallest value"`
+	Max             interface{}         `json:"max,omitempty" jsonschema:"largest value"`
+	SampleRows      int64               `json:"sample_rows,omitempty" jsonschema:"rows read for distinct count and top values"`
+	DistinctCount   int64               `json:"distinct_count,omitempty" jsonschema:"distinct non-NULL values in the sample"`
+	DistinctApprox  bool                `json:"distinct_approx,omitempty" jsonschema:"true when the sample did not cover the whole table, making distinct_count a lower bound"`
+	DistinctRatio   float64             `json:"distinct_ratio,omitempty" jsonschema:"distinct values per non-NULL sampled row (1.0 means unique in the sample)"`
+	TopValues       []ProfileValueCount `json:"top_values" jsonschema:"most frequent non-NULL values in the sample"`
+	Months          []ProfileMonthCount `json:"months,omitempty" jsonschema:"per-month distribution for date/datetime/timestamp columns"`
+	MonthsTruncated bool                `json:"months_truncated,omitempty" jsonschema:"true when the distribution was cut at 600 months"`
+	Notes           []string            `json:"notes,omitempty" jsonschema:"limitations that apply to this column type"`
+}
+
+type PIIScanInput struct {
+	Database   string `json:"database" validate:"required,ident" jsonschema:"database name"`
+	Table      string `json:"table" validate:"required,ident" jsonschema:"table name"`
+	SampleSize int    `json:"sample_size,omitempty" jsonschema:"rows sampled (default 1000, max 10000)"`
+}
+
+type PIIColumn struct {
+	Column        string  `json:"column" jsonschema:"column name"`
+	DataType      string  `json:"data_type" jsonschema:"column data type"`
+	Type          string  `json:"type" jsonschema:"likely PII: email, phone, credit_card, national_id, person_name, address, birth_date or ip_address"`
+	Confidence    string  `json:"confidence" jsonschema:"high, medium or low (column name only)"`
+	NameMatch     bool    `json:"name_match,omitempty" jsonschema:"true when the column name suggests this PII type"`
+	MatchedValues int     `json:"matched_values,omitempty" jsonschema:"sampled values that look like this PII type"`
+	SampledValues int     `json:"sampled_values,omitempty" jsonschema:"non-NULL values sampled from the column"`
+	MatchRatio    float64 `json:"match_ratio,omitempty" jsonschema:"matched_values / sampled_values"`
+}
+
+type PIIScanOutput struct {
+	Database    string      `json:"database" jsonschema:"database name"`
+	Table       string      `json:"table" jsonschema:"table name"`
+	SampledRows int64       `json:"sampled_rows" jsonschema:"rows read from the table"`
+	Columns     []PIIColumn `json:"columns" jsonschema:"columns flagged as likely PII; sampled values are never returned"`
+	Notes       []string    `json:"notes,omitempty" jsonschema:"limitations of this scan"`
+}
+
+type ListGhostTablesInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database to check"`
+}
+
+type GhostTable struct {
+	Table          string `json:"table" jsonschema:"table name"`
+	Tool           string `json:"tool" jsonschema:"tool that likely created it: gh-ost, pt-online-schema-change or lhm"`
+	Role           string `json:"role" jsonschema:"ghost copy, new copy, changelog or old table"`
+	OriginalTable  string `json:"original_table" jsonschema:"table the migration was copying"`
+	OriginalExists bool   `json:"original_exists" jsonschema:"true when the original table still exists"`
+	Rows           int64  `json:"rows" jsonschema:"estimated rows (information_schema.TABLES)"`
+	SizeBytes      int64  `json:"size_bytes" jsonschema:"data plus index size in bytes"`
+	CreateTime     string `json:"create_time,omitempty" jsonschema:"when the table was created"`
+}
+
+type GhostTrigger struct {
+	Trigger string `json:"trigger" jsonschema:"trigger name"`
+	Table   string `json:"table" jsonschema:"table the trigger is defined on"`
+	Tool    string `json:"tool" jsonschema:"tool that likely created it"`
+}
+
+type ListGhostTablesOutput struct {
+	Database string         `json:"database" jsonschema:"database name"`
+	Tables   []GhostTable   `json:"tables" jsonschema:"leftover online schema change tables"`
+	Triggers []GhostTrigger `json:"triggers" jsonschema:"leftover pt-online-schema-change and LHM triggers"`
+	Count    int            `json:"count" jsonschema:"number of tables found"`
+	Notes    []string       `json:"notes,omitempty" jsonschema:"advice before cleaning up"`
+}
+
+type ListSummaryTablesInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database to check"`
+	Table    string `json:"table,omitempty" validate:"ident" jsonschema:"optional fact table: only list summaries built from it"`
+}
+
+type SummaryTable struct {
+	Table           string `json:"table" jsonschema:"summary table name"`
+	SourceTable     string `json:"source_table,omitempty" jsonschema:"fact table it summarizes (database.table when in another database), when known"`
+	Grain           string `json:"grain,omitempty" jsonschema:"time grain, e.g. hourly, daily, monthly"`
+	Detection       string `json:"detection" jsonschema:"config (declared in summary_tables) or name (guessed from the table name)"`
+	Description     string `json:"description,omitempty" jsonschema:"description from summary_tables"`
+	Rows            int64  `json:"rows" jsonschema:"estimated rows (information_schema.TABLES)"`
+	SourceRows      int64  `json:"source_rows,omitempty" jsonschema:"estimated rows of the source table"`
+	SizeBytes       int64  `json:"size_bytes" jsonschema:"data plus index size in bytes"`
+	FreshnessColumn string `json:"freshness_column,omitempty" jsonschema:"column whose MAX() gives the last refresh"`
+	LastUpdated     string `json:"last_updated,omitempty" jsonschema:"MAX(freshness_column)"`
+	AgeSeconds      *int64 `json:"age_seconds,omitempty" jsonschema:"seconds between last_updated and the server's NOW()"`
+	UpdateTime      string `json:"update_time,omitempty" jsonschema:"last write recorded in information_schema.TABLES (may be empty or reset by a restart)"`
+}
+
+type ListSummaryTablesOutput struct {
+	Database string         `json:"database" jsonschema:"database name"`
+	Tables   []SummaryTable `json:"tables" jsonschema:"rollup and materialized tables, with the fact tables they replace"`
+	Count    int            `json:"count" jsonschema:"number of tables found"`
+	Notes    []string       `json:"notes,omitempty" jsonschema:"how to use the results"`
+}
+
+type DataFreshnessInput struct {
+	Database string `json:"database" validate:"required,ident" jsonschema:"database to check"`
+	Table    string `json:"table,omitempty" validate:"ident" jsonschema:"optional table: only report this one"`
+	Refresh  bool   `json:"refresh,omitempty" jsonschema:"ignore cached answers and query again"`
+}
+
+type TableFreshness struct {
+	Table       string `json:"table" jsonschema:"table name"`
+	Source      string `json:"source" jsonschema:"column (MAX of the configured timestamp column), update_time (information_schema.TABLES) or unknown"`
+	Column      string `json:"column,omitempty" jsonschema:"timestamp column read when source is column"`
+	LastUpdated string `json:"last_updated,omitempty" jsonschema:"newest timestamp found"`
+	AgeSeconds  *int64 `json:"age_seconds,omitempty" jsonschema:"seconds between last_updated and the server's NOW()"`
+	CheckedAt   string `json:"checked_at" jsonschema:"when the value was read (RFC 3339, UTC)"`
+	Cached      bool   `json:"cached,omitempty" jsonschema:"true when the answer came from the cache; pass refresh=true to re-read"`
+	Error       string `json:"error,omitempty" jsonschema:"why the configured column could not be read, when the answer fell back to update_time"`
+}
+
+type DataFreshnessOutput struct {
+	Database string           `json:"database" jsonschema:"database name"`
+	Tables   []TableFreshness `json:"tables" jsonschema:"tables sorted by name"`
+	Notes    []string         `json:"notes,omitempty" jsonschema:"caveats about the values"`
+}
+
+type TableCountsInput struct {
+	Database string   `json:"database" validate:"required,ident" jsonschema:"database holding the tables"`
+	Tables   []string `json:"tables" validate:"required" jsonschema:"tables to count (at most 50)"`
+	Mode     string   `json:"mode,omitempty" jsonschema:"estimate (default: information_schema TABLE_ROWS, fast and approximate) or exact (COUNT(*) of every table in one consistent snapshot)"`
+}
+
+type TableCount struct {
+	Table string `json:"table" jsonschema:"table name"`
+	Rows  *int64 `json:"rows,omitempty" jsonschema:"row count, or the TABLE_ROWS estimate in estimate mode"`
+	Error string `json:"error,omitempty" jsonschema:"why the table could not be counted"`
+}
+
+type TableCountsOutput struct {
+	Database  string       `json:"database" jsonschema:"database name"`
+	Mode      string       `json:"mode" jsonschema:"estimate or exact"`
+	Snapshot  bool         `json:"snapshot,omitempty" jsonschema:"true when the counts were read in one consistent snapshot"`
+	Tables    []TableCount `json:"tables" jsonschema:"counts in the order requested"`
+	TotalRows int64        `json:"total_rows" jsonschema:"sum of the counted rows"`
+	Notes     []string     `json:"notes,omitempty" jsonschema:"accuracy caveats"`
+}
+
+type HeatWaveInfo struct {
+	ClusterStatus string `json:"cluster_status" jsonschema:"rapid_cluster_status: ON when the HeatWave cluster is up"`
+	ReadyNodes    int    `json:"ready_nodes,omitempty" jsonschema:"HeatWave nodes ready to run queries"`
+}
+
+type HeatWaveStatusInput struct {
+	Database string `json:"database,omitempty" jsonschema:"only list tables of this database"`
+	SQL      string `json:"sql,omitempty" jsonschema:"SELECT to check for offload to HeatWave (EXPLAIN only; the query is not executed)"`
+}
+
+type HeatWaveTable struct {
+	Database     string  `json:"database" jsonschema:"database name"`
+	Table        string  `json:"table" jsonschema:"table name"`
+	LoadStatus   string  `json:"load_status,omitempty" jsonschema:"performance_schema.rpd_tables LOAD_STATUS, e.g. AVAIL_RPDGSTABSTATE; empty when not loaded"`
+	LoadProgress float64 `json:"load_progress,omitempty" jsonschema:"load progress in percent"`
+	Rows         int64   `json:"rows,omitempty" jsonschema:"rows loaded into HeatWave"`
+	QueryCount   int64   `json:"query_count,omitempty" jsonschema:"queries offloaded to this table"`
+}
+
+type HeatWaveStatusOutput struct {
+	ClusterStatus      string                   `json:"cluster_status" jsonschema:"rapid_cluster_status: ON when the HeatWave cluster is up"`
+	ReadyNodes         int                      `json:"ready_nodes,omitempty" jsonschema:"HeatWave nodes ready to run queries"`
+	UseSecondaryEngine string                   `json:"use_secondary_engine" jsonschema:"session use_secondary_engine: ON, OFF or FORCED"`
+	Tables             []HeatWaveTable          `json:"tables" jsonschema:"tables defined with SECONDARY_ENGINE=RAPID and their load status"`
+	QueryOffloaded     *bool                    `json:"query_offloaded,omitempty" jsonschema:"with sql: true when EXPLAIN shows the query using the secondary engine"`
+	Plan               []map[string]interface{} `json:"plan,omitempty" jsonschema:"with sql: the EXPLAIN output"`
+	Notes              []string                 `json:"notes,omitempty" jsonschema:"hints, e.g. why a query is not offloaded"`
+}
+
+type HeatWaveMLPredictInput struct {
+	ModelHandle string                   `json:"model_handle" validate:"required" jsonschema:"handle of a trained HeatWave AutoML model (see ML_SCHEMA_<user>.MODEL_CATALOG)"`
+	Rows        []map[string]interface{} `json:"rows,omitempty" validate:"max=100" jsonschema:"feature rows to score (max 100); instead of database/table"`
+	Database    string                   `json:"database,omitempty" validate:"ident" jsonschema:"database of the table to score"`
+	Table       string                   `json:"table,omitempty" validate:"ident" jsonschema:"table to score rows from"`
+	Columns     []string                 `json:"columns,omitempty" jsonschema:"feature columns read from the table"`
+	Where       string                   `json:"where,omitempty" jsonschema:"optional WHERE conditions selecting the rows to score"`
+	Limit       int                      `json:"limit,omitempty" jsonschema:"rows scored from the table (default 10, max 100)"`
+}
+
+type HeatWaveMLPredictOutput struct {
+	Predictions []interface{} `json:"predictions" jsonschema:"ML_PREDICT_ROW output per row: the features plus Prediction and ml_results"`
+	Count       int           `json:"count" jsonschema:"number of rows scored"`
+}
+
+type AuroraTopologyInput struct{}
+
+type AuroraInstance struct {
+	ServerID     string   `json:"server_id" jsonschema:"DB instance identifier"`
+	Role         string   `json:"role" jsonschema:"writer or reader"`
+	Endpoint     string   `json:"endpoint,omitempty" jsonschema:"instance endpoint, derived from the connection's RDS host name"`
+	Current      bool     `json:"current,omitempty" jsonschema:"true for the instance the active connection is on"`
+	ReplicaLagMs *float64 `json:"replica_lag_ms,omitempty" jsonschema:"reader: milliseconds behind the writer"`
+	CPU          *float64 `json:"cpu_percent,omitempty" jsonschema:"CPU use in percent"`
+	LastUpdate   string   `json:"last_update,omitempty" jsonschema:"when the instance last reported its status"`
+}
+
+type AuroraTopologyOutput struct {
+	AuroraVersion   string           `json:"aurora_version" jsonschema:"@@aurora_version of the instance the active connection is on"`
+	Connection      string           `json:"connection" jsonschema:"active connection name"`
+	Role            string           `json:"role" jsonschema:"role of the active connection's instance: writer or reader"`
+	ServerID        string           `json:"server_id" jsonschema:"instance the active connection is on"`
+	Writer          string           `json:"writer,omitempty" jsonschema:"server_id of the writer"`
+	ClusterEndpoint string           `json:"cluster_endpoint,omitempty" jsonschema:"writer (cluster) endpoint, when the connection uses a cluster endpoint"`
+	ReaderEndpoint  string           `json:"reader_endpoint,omitempty" jsonschema:"reader (cluster-ro) endpoint, when the connection uses a cluster endpoint"`
+	Instances       []AuroraInstance `json:"instances" jsonschema:"writer first, then readers by server_id"`
+	MaxReplicaLagMs float64          `json:"max_replica_lag_ms" jsonschema:"largest replica lag among the readers"`
+	Notes           []string         `json:"notes,omitempty"`
+}
+
+type SchemaDiffInput struct {
+	SourceDatabase string `json:"source_database" validate:"required,ident" jsonschema:"source database name"`
+	TargetDatabase string `json:"target_database" validate:"required,ident" jsonschema:"target database name"`
+}
+
+type DiffResult struct {
+	Table   string `json:"table" jsonschema:"table name"`
+	Status  string `json:"status" jsonschema:"diff status (MISSING, EXTRA, CHANGED)"`
+	Details string `json:"details,omitempty" jsonschema:"details about the difference"`
+}
+
+type SchemaDiffOutput struct {
+	SourceDatabase string       `json:"source_database" jsonschema:"source database name"`
+	TargetDatabase string       `json:"target_database" jsonschema:"target database name"`
+	Diffs          []DiffResult `json:"diffs" jsonschema:"list of differences between schemas"`
+}
+
+// ===== Health Report Types =====
+
+type HealthReportInput struct {
+	TopWaits int `json:"top_waits,omitempty" jsonschema:"number of top wait events to include (default 5, max 20)"`
+}
+
+// HealthCheck is one evaluated metric with a severity flag.
+type HealthCheck struct {
+	Name     string   `json:"name" jsonschema:"check name (e.g. buffer_pool_hit_ratio)"`
+	Severity string   `json:"severity" jsonschema:"ok, warning, critical, or unknown"`
+	Value    *float64 `json:"value,omitempty" jsonschema:"measured value (omitted when unavailable)"`
+	Unit     string   `json:"unit,omitempty" jsonschema:"unit of value (percent, seconds, per_minute)"`
+	Message  string   `json:"message" jsonschema:"human-readable interpretation"`
+}
+
+type ResourceUsageInput struct {
+	TopMemory int `json:"top_memory,omitempty" jsonschema:"number of top memory consumers to list (default 10, max 50)"`
+}
+
+type MemoryConsumer struct {
+	Event        string `json:"event" jsonschema:"performance_schema memory instrument, e.g. memory/innodb/buf_buf_pool"`
+	CurrentBytes int64  `json:"current_bytes" jsonschema:"bytes allocated now"`
+	HighBytes    int64  `json:"high_bytes" jsonschema:"most bytes allocated at once since startup"`
+	CurrentCount int64  `json:"current_count" jsonschema:"allocations currently held"`
+}
+
+type ResourceCounter struct {
+	Name      string  `json:"name" jsonschema:"global status variable"`
+	Value     int64   `json:"value" jsonschema:"value since startup"`
+	PerSecond float64 `json:"per_second" jsonschema:"average rate since startup"`
+	Meaning   string  `json:"meaning" jsonschema:"what the counter counts"`
+}
+
+type ResourceUsageOutput struct {
+	Status           string            `json:"status" jsonschema:"worst severity across checks: ok, unknown, warning or critical"`
+	UptimeSeconds    int64             `json:"uptime_seconds" jsonschema:"seconds since the server started"`
+	Checks           []HealthCheck     `json:"checks" jsonschema:"temporary table, sort and join spill checks with severity flags"`
+	Counters         []ResourceCounter `json:"counters" jsonschema:"temporary table, sort and join counters with their meaning and rate"`
+	MemoryTotalBytes *int64            `json:"memory_total_bytes,omitempty" jsonschema:"memory currently allocated by all instrumented events"`
+	TopMemory        []MemoryConsumer  `json:"top_memory,omitempty" jsonschema:"largest memory consumers by current allocation"`
+	Notes            []string          `json:"notes,omitempty" jsonschema:"missing privileges and caveats"`
+}
+
+type WaitEventSummary struct {
+	Event       string  `json:"event" jsonschema:"performance_schema wait event name"`
+	Count       int64   `json:"count" jsonschema:"number of waits since startup"`
+	TotalWaitMs float64 `json:"total_wait_ms" jsonschema:"total wait time in milliseconds"`
+}
+
+type HealthReportOutput struct {
+	Status        string             `json:"status" jsonschema:"overall status: worst severity across checks"`
+	Version       string             `json:"version,omitempty" jsonschema:"server version"`
+	UptimeSeconds int64              `json:"uptime_seconds" jsonschema:"server uptime in seconds"`
+	Checks        []HealthCheck      `json:"checks" jsonschema:"individual health checks with severity flags"`
+	TopWaitEvents []WaitEventSummary `json:"top_wait_events,omitempty" jsonschema:"top wait events by total wait time (performance_schema)"`
+	Notes         []string           `json:"notes,omitempty" jsonschema:"metrics that could not be collected and why"`
+}
+
+// ===== Grants Types =====
+
+type ShowGrantsInput struct {
+	Database string `json:"database,omitempty" jsonschema:"only return grants that apply to this database (global grants included)"`
+}
+
+type GrantEntry struct {
+	Level       string              `json:"level" jsonschema:"global, database, table, column, routine or proxy"`
+	Database    string              `json:"database,omitempty" jsonschema:"database (may contain % or _ wildcards)"`
+	Object      string              `json:"object,omitempty" jsonschema:"table or routine name"`
+	ObjectType  string              `json:"object_type,omitempty" jsonschema:"function or procedure for routine grants"`
+	Privileges  []string            `json:"privileges" jsonschema:"granted privileges"`
+	Columns     map[string][]string `json:"columns,omitempty" jsonschema:"column-level privileges: privilege -> columns"`
+	GrantOption bool                `json:"grant_option,omitempty" jsonschema:"true when WITH GRANT OPTION"`
+	Revoke      bool                `json:"revoke,omitempty" jsonschema:"true for partial revokes (privileges removed from a broader grant)"`
+}
+
+type ShowGrantsOutput struct {
+	User   string       `json:"user" jsonschema:"current account (CURRENT_USER())"`
+	Grants []GrantEntry `json:"grants" jsonschema:"privileges of the current account per object"`
+	Roles  []string     `json:"roles,omitempty" jsonschema:"roles granted to the current account"`
+	Notes  []string     `json:"notes,omitempty" jsonschema:"parsing notes"`
+}
+
+// ===== Binlog Status Types =====
+
+type BinlogStatusInput struct {
+	MaxFiles int `json:"max_files,omitempty" jsonschema:"newest binary log files to list (default 50)"`
+}
+
+type BinaryLogFile struct {
+	Name      string `json:"name" jsonschema:"binary log file name"`
+	SizeBytes int64  `json:"size_bytes" jsonschema:"file size in bytes"`
+	Encrypted bool   `json:"encrypted,omitempty" jsonschema:"true when the file is encrypted"`
+}
+
+type BinlogStatusOutput struct {
+	Enabled          bool              `json:"enabled" jsonschema:"whether binary logging is on (log_bin)"`
+	Format           string            `json:"format,omitempty" jsonschema:"binlog_format (ROW, STATEMENT, MIXED)"`
+	RowImage         string            `json:"row_image,omitempty" jsonschema:"binlog_row_image (FULL, MINIMAL, NOBLOB)"`
+	GTIDMode         string            `json:"gtid_mode,omitempty" jsonschema:"gtid_mode (MySQL)"`
+	GTIDExecuted     string            `json:"gtid_executed,omitempty" jsonschema:"executed GTID set (MySQL gtid_executed, MariaDB gtid_binlog_pos)"`
+	GTIDPurged       string            `json:"gtid_purged,omitempty" jsonschema:"GTIDs no longer in the binary logs (MySQL)"`
+	RetentionSeconds int64             `json:"retention_seconds,omitempty" jsonschema:"automatic purge age (binlog_expire_logs_seconds or expire_logs_days)"`
+	CurrentFile      string            `json:"current_file,omitempty" jsonschema:"binary log currently being written"`
+	CurrentPosition  int64             `json:"current_position,omitempty" jsonschema:"write position in current_file"`
+	FileCount        int               `json:"file_count" jsonschema:"number of binary log files"`
+	TotalSizeBytes   int64             `json:"total_size_bytes" jsonschema:"combined size of all binary log files"`
+	Files            []BinaryLogFile   `json:"files" jsonschema:"newest binary log files, oldest first"`
+	Truncated        bool              `json:"truncated,omitempty" jsonschema:"true when older files were omitted (see max_files)"`
+	Variables        map[string]string `json:"variables" jsonschema:"binlog, GTID and retention related global variables"`
+	Notes            []string          `json:"notes,omitempty" jsonschema:"sections that could not be read"`
+}
+
+// ===== Metrics History Types =====
+
+type MetricsHistoryInput struct {
+	WindowMinutes  int  `json:"window_minutes,omitempty" jsonschema:"look-back window in minutes (default 15)"`
+	IncludeSamples bool `json:"include_samples,omitempty" jsonschema:"include the raw samples in the window"`
+}
+
+// MetricSeriesSummary describes one status value over the window.
+type MetricSeriesSummary struct {
+	Name       string  `json:"name" jsonschema:"status variable name"`
+	Kind       string  `json:"kind" jsonschema:"counter (cumulative) or gauge (point-in-time)"`
+	Start      int64   `json:"start" jsonschema:"value at the first sample in the window"`
+	End        int64   `json:"end" jsonschema:"value at the last sample in the window"`
+	Delta      int64   `json:"delta,omitempty" jsonschema:"counter increase over the window"`
+	RatePerSec float64 `json:"rate_per_sec,omitempty" jsonschema:"counter increase per second (e.g. Questions -> QPS)"`
+	Min        int64   `json:"min,omitempty" jsonschema:"gauge minimum over the window"`
+	Max        int64   `json:"max,omitempty" jsonschema:"gauge maximum over the window"`
+	Avg        float64 `json:"avg,omitempty" jsonschema:"gauge average over the window"`
+	Reset      bool    `json:"reset,omitempty" jsonschema:"true if the counter went backwards (server restart) inside the window"`
+}
+
+type MetricsHistorySample struct {
+	Time   string           `json:"time" jsonschema:"sample time (RFC3339)"`
+	Values map[string]int64 `json:"values" jsonschema:"status values keyed by lowercased name"`
+}
+
+type MetricsHistoryOutput struct {
+	Connection      string                 `json:"connection" jsonschema:"connection the samples were taken from"`
+	IntervalSeconds int                    `json:"interval_seconds" jsonschema:"sampling interval"`
+	WindowStart     string                 `json:"window_start,omitempty" jsonschema:"time of the first sample used (RFC3339)"`
+	WindowEnd       string                 `json:"window_end,omitempty" jsonschema:"time of the last sample used (RFC3339)"`
+	SampleCount     int                    `json:"sample_count" jsonschema:"number of samples in the window"`
+	Metrics         []MetricSeriesSummary  `json:"metrics" jsonschema:"per-metric deltas, rates and ranges"`
+	Samples         []MetricsHistorySample `json:"samples,omitempty" jsonschema:"raw samples when include_samples=true"`
+	Note            string                 `json:"note,omitempty"`
+}
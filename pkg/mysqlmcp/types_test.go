@@ -1,5 +1,5 @@
-// cmd/mysql-mcp-server/types_test.go
-package main
+// pkg/mysqlmcp/types_test.go
+package mysqlmcp
 
 import (
 	"encoding/json"
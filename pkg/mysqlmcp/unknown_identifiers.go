@@ -0,0 +1,156 @@
+// pkg/mysqlmcp/unknown_identifiers.go
+package mysqlmcp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/util"
+	"github.com/go-sql-driver/mysql"
+)
+
+// When run_query fails because a table or column does not exist, a second
+// pass looks the name up in the schema catalog and adds the closest names
+// to the error, so an agent can fix a typo in generated SQL without a
+// describe_table round trip. Columns are looked for in the tables the query
+// reads, or only in the table of the alias the column was qualified with.
+
+const (
+	errBadField = 1054 // ER_BAD_FIELD_ERROR
+
+	// maxUnknownIdentifierSuggestions caps the names added to one error.
+	maxUnknownIdentifierSuggestions = 5
+	// unknownIdentifierTimeout bounds the catalog lookup, which runs after
+	// the query and must not hold up its error for long.
+	unknownIdentifierTimeout = 2 * time.Second
+)
+
+var unknownColumnRe = regexp.MustCompile(`^Unknown column '([^']*)' in`)
+
+// UnknownIdentifierError is a MySQL unknown table or column error with the
+// catalog names closest to the one the query used.
+type UnknownIdentifierError struct {
+	Kind        string // identifierKindTable or identifierKindColumn
+	Name        string // as the query wrote it, qualifiers included
+	Suggestions []IdentifierSuggestion
+	Err         error
+}
+
+func (e *UnknownIdentifierError) Error() string {
+	names := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		names[i] = s.Qualified
+	}
+	return e.Err.Error() + " (did you mean " + strings.Join(names, ", ") + "?)"
+}
+
+func (e *UnknownIdentifierError) Unwrap() error { return e.Err }
+
+// suggestUnknownIdentifiers returns err as an UnknownIdentifierError when it
+// is an unknown table or column error of sqlText, run against database, and
+// the catalog has names close to the missing one. Any other error, or one
+// without close names, is returned unchanged.
+func suggestUnknownIdentifiers(ctx context.Context, err error, sqlText, database string) error {
+	var mysqlErr *mysql.MySQLError
+	if err == nil || !errors.As(err, &mysqlErr) || (mysqlErr.Number != errNoSuchTable && mysqlErr.Number != errBadField) {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), unknownIdentifierTimeout)
+	defer cancel()
+
+	var kind, name string
+	var candidates []IdentifierSuggestion
+	if m := noSuchTableRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+		kind, name = identifierKindTable, m[2]
+		for _, t := range catalogTablesOf(ctx, m[1]) {
+			candidates = append(candidates, IdentifierSuggestion{Kind: kind, Name: t.name, Database: m[1], Qualified: m[1] + "." + t.name})
+		}
+	} else if m := unknownColumnRe.FindStringSubmatch(mysqlErr.Message); m != nil {
+		kind, name = identifierKindColumn, m[1]
+		candidates = columnCandidates(ctx, m[1], sqlText, database)
+	}
+	if kind == "" {
+		return err
+	}
+
+	column := name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		column = name[i+1:]
+	}
+	suggestions := rankIdentifiers(column, candidates)
+	if len(suggestions) == 0 {
+		return err
+	}
+	if len(suggestions) > maxUnknownIdentifierSuggestions {
+		suggestions = suggestions[:maxUnknownIdentifierSuggestions]
+	}
+	return &UnknownIdentifierError{Kind: kind, Name: name, Suggestions: suggestions, Err: err}
+}
+
+// columnCandidates lists the columns an unknown column name may have meant:
+// those of the table its qualifier refers to, or of every table the query
+// reads when it has none or the qualifier is not one of them.
+func columnCandidates(ctx context.Context, name, sqlText, database string) []IdentifierSuggestion {
+	aliases := util.TableAliases(sqlText)
+	tables := make([]string, 0, len(aliases))
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		if table, ok := aliases[strings.ToLower(name[:i])]; ok {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		seen := map[string]bool{}
+		for _, table := range aliases {
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+
+	var candidates []IdentifierSuggestion
+	for _, written := range tables {
+		schema, table := database, written
+		if i := strings.LastIndex(written, "."); i >= 0 {
+			schema, table = written[:i], written[i+1:]
+		} else if schema == "" {
+			database = currentDatabase(ctx)
+			schema = database
+		}
+		t, ok := catalogTableNamed(catalogTablesOf(ctx, schema), table)
+		if !ok {
+			continue
+		}
+		for _, c := range t.columns {
+			candidates = append(candidates, IdentifierSuggestion{
+				Kind: identifierKindColumn, Name: c, Database: schema, Table: t.name, Qualified: schema + "." + t.name + "." + c,
+			})
+		}
+	}
+	return candidates
+}
+
+// catalogTablesOf returns the cached tables of database, or none when the
+// caller may not use it or the catalog cannot be read.
+func catalogTablesOf(ctx context.Context, database string) []catalogTable {
+	if database == "" || !databaseAllowed(database) {
+		return nil
+	}
+	tables, _, err := identifierCatalog.tables(ctx, getDB(), database)
+	if err != nil {
+		return nil
+	}
+	return tables
+}
+
+// currentDatabase returns the default database of the connection, or "".
+func currentDatabase(ctx context.Context) string {
+	var name *string
+	if err := getDB().QueryRowContext(ctx, "SELECT DATABASE()").Scan(&name); err != nil || name == nil {
+		return ""
+	}
+	return *name
+}
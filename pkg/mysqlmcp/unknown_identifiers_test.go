@@ -0,0 +1,78 @@
+// pkg/mysqlmcp/unknown_identifiers_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRunQueryUnknownIdentifierSuggestions(t *testing.T) {
+	mock, cleanup := setupMockDB(t)
+	defer cleanup()
+	identifierCatalog = &schemaCatalog{entries: map[string]catalogEntry{}}
+	ctx := context.Background()
+
+	mock.ExpectQuery("SELECT o.custmer_id FROM shop.orders").
+		WillReturnError(&mysql.MySQLError{Number: errBadField, Message: "Unknown column 'o.custmer_id' in 'field list'"})
+	mock.ExpectQuery("FROM information_schema.COLUMNS").WithArgs("shop").
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "COLUMN_NAME"}).
+			AddRow("customers", "id").AddRow("customers", "name").
+			AddRow("orders", "id").AddRow("orders", "customer_id").AddRow("orders", "created_at"))
+
+	_, _, err := toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT o.custmer_id FROM shop.orders o"})
+	var unknown *UnknownIdentifierError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected an UnknownIdentifierError, got %v", err)
+	}
+	if unknown.Kind != identifierKindColumn || unknown.Name != "o.custmer_id" || len(unknown.Suggestions) != 1 ||
+		unknown.Suggestions[0].Qualified != "shop.orders.customer_id" {
+		t.Errorf("unexpected suggestions: %+v", unknown)
+	}
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || !strings.Contains(err.Error(), "did you mean shop.orders.customer_id?") {
+		t.Errorf("expected the MySQL error with a hint, got %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	writeToolError(rec, err)
+	var resp struct {
+		Details struct {
+			Kind        string                 `json:"kind"`
+			Suggestions []IdentifierSuggestion `json:"suggestions"`
+		} `json:"details"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusBadRequest || resp.Details.Kind != identifierKindColumn || len(resp.Details.Suggestions) != 1 {
+		t.Errorf("expected 400 with suggestions, got %d %+v", rec.Code, resp)
+	}
+
+	// The catalog is cached: an unknown table needs no second lookup.
+	mock.ExpectQuery("SELECT id FROM shop.ordres").
+		WillReturnError(&mysql.MySQLError{Number: errNoSuchTable, Message: "Table 'shop.ordres' doesn't exist"})
+	_, _, err = toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT id FROM shop.ordres"})
+	if !errors.As(err, &unknown) || unknown.Kind != identifierKindTable || unknown.Suggestions[0].Qualified != "shop.orders" {
+		t.Errorf("expected shop.orders as the table meant, got %v", err)
+	}
+
+	// Nothing close: the error is left alone.
+	mock.ExpectQuery("SELECT zzz FROM shop.orders").
+		WillReturnError(&mysql.MySQLError{Number: errBadField, Message: "Unknown column 'zzz' in 'field list'"})
+	_, _, err = toolRunQuery(ctx, &mcp.CallToolRequest{}, RunQueryInput{SQL: "SELECT zzz FROM shop.orders"})
+	if err == nil || errors.As(err, &unknown) {
+		t.Errorf("expected a plain error, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,219 @@
+// pkg/mysqlmcp/usage_stats.go
+package mysqlmcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// usageLatencySamples is how many recent call latencies each tool keeps for
+// its percentiles.
+const usageLatencySamples = 1000
+
+// toolUsage holds the counters of one tool.
+type toolUsage struct {
+	calls     int64
+	errors    int64
+	rows      int64
+	totalTime time.Duration
+	latencies []time.Duration // ring of the last usageLatencySamples calls
+	next      int
+	lastCall  time.Time
+}
+
+// usageRegistry counts tool calls since startup. It backs the usage_stats
+// tool, /api/stats and the Prometheus /metrics endpoint.
+type usageRegistry struct {
+	mu      sync.Mutex
+	start   time.Time
+	samples int
+	tools   map[string]*toolUsage
+}
+
+// globalUsage is the process-wide registry, fed by dispatchTool.
+var globalUsage = newUsageRegistry(usageLatencySamples)
+
+func newUsageRegistry(samples int) *usageRegistry {
+	return &usageRegistry{start: time.Now(), samples: samples, tools: map[string]*toolUsage{}}
+}
+
+// register lists a tool with zero calls, so enabled but unused tools show up.
+func (u *usageRegistry) register(tool string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.usage(tool)
+}
+
+func (u *usageRegistry) usage(tool string) *toolUsage {
+	t, ok := u.tools[tool]
+	if !ok {
+		t = &toolUsage{}
+		u.tools[tool] = t
+	}
+	return t
+}
+
+// record counts one call of tool. It is safe for concurrent use.
+func (u *usageRegistry) record(tool string, d time.Duration, rows int, failed bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	t := u.usage(tool)
+	t.calls++
+	if failed {
+		t.errors++
+	}
+	t.rows += int64(rows)
+	t.totalTime += d
+	t.lastCall = time.Now()
+	if len(t.latencies) < u.samples {
+		t.latencies = append(t.latencies, d)
+	} else {
+		t.latencies[t.next] = d
+		t.next = (t.next + 1) % u.samples
+	}
+}
+
+// snapshot returns the stats of every tool, most called first.
+func (u *usageRegistry) snapshot() UsageStatsOutput {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	out := UsageStatsOutput{
+		UptimeSeconds: int(time.Since(u.start).Seconds()),
+		Tools:         make([]ToolUsageStats, 0, len(u.tools)),
+	}
+	for name, t := range u.tools {
+		s := ToolUsageStats{Tool: name, Calls: t.calls, Errors: t.errors, RowsReturned: t.rows, totalSeconds: t.totalTime.Seconds()}
+		if t.calls > 0 {
+			s.ErrorRate = roundFloat(float64(t.errors)/float64(t.calls), 4)
+			s.AvgMs = durationMs(t.totalTime / time.Duration(t.calls))
+			sorted := make([]time.Duration, len(t.latencies))
+			copy(sorted, t.latencies)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			s.P50Ms = durationMs(latencyPercentile(sorted, 0.50))
+			s.P95Ms = durationMs(latencyPercentile(sorted, 0.95))
+			s.LastCallAt = t.lastCall.UTC().Format(time.RFC3339)
+		}
+		out.TotalCalls += t.calls
+		out.TotalErrors += t.errors
+		out.Tools = append(out.Tools, s)
+	}
+	sort.Slice(out.Tools, func(i, j int) bool {
+		if out.Tools[i].Calls != out.Tools[j].Calls {
+			return out.Tools[i].Calls > out.Tools[j].Calls
+		}
+		return out.Tools[i].Tool < out.Tools[j].Tool
+	})
+	return out
+}
+
+// latencyPercentile returns the nearest-rank percentile p of sorted.
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p*float64(len(sorted))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i]
+}
+
+func durationMs(d time.Duration) float64 {
+	return roundFloat(float64(d)/float64(time.Millisecond), 3)
+}
+
+// resultRowCounter is implemented by tool outputs that return result rows.
+type resultRowCounter interface {
+	resultRows() int
+}
+
+func (r QueryResult) resultRows() int { return len(r.Rows) }
+
+func (r RunCrossDatabaseQueryOutput) resultRows() int { return len(r.Result.Rows) }
+
+func (s streamSummaryLine) resultRows() int { return s.RowCount }
+
+func (r RunReportOutput) resultRows() int {
+	n := 0
+	for _, s := range r.Sections {
+		n += len(s.Rows)
+	}
+	return n
+}
+
+// resultRows returns the rows in a tool output, or 0 for outputs without rows.
+func resultRows(out interface{}) int {
+	if c, ok := out.(resultRowCounter); ok {
+		return c.resultRows()
+	}
+	return 0
+}
+
+// writePrometheus writes the registry in the Prometheus text exposition format.
+func (u *usageRegistry) writePrometheus(w io.Writer) {
+	stats := u.snapshot()
+	fmt.Fprintf(w, "# HELP mysql_mcp_uptime_seconds Seconds since the server started.\n")
+	fmt.Fprintf(w, "# TYPE mysql_mcp_uptime_seconds gauge\n")
+	fmt.Fprintf(w, "mysql_mcp_uptime_seconds %d\n", stats.UptimeSeconds)
+
+	counters := []struct {
+		name, help string
+		value      func(ToolUsageStats) int64
+	}{
+		{"mysql_mcp_tool_calls_total", "Tool calls since startup.", func(s ToolUsageStats) int64 { return s.Calls }},
+		{"mysql_mcp_tool_errors_total", "Tool calls that returned an error.", func(s ToolUsageStats) int64 { return s.Errors }},
+		{"mysql_mcp_tool_rows_returned_total", "Result rows returned by tool calls.", func(s ToolUsageStats) int64 { return s.RowsReturned }},
+	}
+	for _, c := range counters {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+		for _, s := range stats.Tools {
+			fmt.Fprintf(w, "%s{tool=%q} %d\n", c.name, s.Tool, c.value(s))
+		}
+	}
+
+	fmt.Fprintf(w, "# HELP mysql_mcp_tool_duration_seconds Tool call latency; quantiles over the last %d calls.\n", u.samples)
+	fmt.Fprintf(w, "# TYPE mysql_mcp_tool_duration_seconds summary\n")
+	for _, s := range stats.Tools {
+		fmt.Fprintf(w, "mysql_mcp_tool_duration_seconds{tool=%q,quantile=\"0.5\"} %g\n", s.Tool, s.P50Ms/1000)
+		fmt.Fprintf(w, "mysql_mcp_tool_duration_seconds{tool=%q,quantile=\"0.95\"} %g\n", s.Tool, s.P95Ms/1000)
+		fmt.Fprintf(w, "mysql_mcp_tool_duration_seconds_sum{tool=%q} %g\n", s.Tool, s.totalSeconds)
+		fmt.Fprintf(w, "mysql_mcp_tool_duration_seconds_count{tool=%q} %d\n", s.Tool, s.Calls)
+	}
+}
+
+func toolUsageStats(
+	ctx context.Context,
+	req *mcp.CallToolRequest,
+	input UsageStatsInput,
+) (*mcp.CallToolResult, UsageStatsOutput, error) {
+	out := globalUsage.snapshot()
+	if input.Tool != "" {
+		tools := out.Tools[:0]
+		for _, s := range out.Tools {
+			if s.Tool == input.Tool {
+				tools = append(tools, s)
+			}
+		}
+		if len(tools) == 0 {
+			return nil, UsageStatsOutput{}, fmt.Errorf("unknown tool %q", input.Tool)
+		}
+		out.Tools = tools
+	}
+	return nil, out, nil
+}
+
+// httpMetricsPrometheus handles GET /metrics in the Prometheus text format.
+func httpMetricsPrometheus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	globalUsage.writePrometheus(w)
+}
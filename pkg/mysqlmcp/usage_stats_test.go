@@ -0,0 +1,128 @@
+// pkg/mysqlmcp/usage_stats_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useUsageRegistry swaps globalUsage for a fresh registry for the test.
+func useUsageRegistry(t *testing.T, samples int) *usageRegistry {
+	t.Helper()
+	old := globalUsage
+	globalUsage = newUsageRegistry(samples)
+	t.Cleanup(func() { globalUsage = old })
+	return globalUsage
+}
+
+func TestUsageRegistrySnapshot(t *testing.T) {
+	u := newUsageRegistry(10)
+	u.register("list_views")
+	for i := 1; i <= 20; i++ {
+		u.record("run_query", time.Duration(i)*time.Millisecond, 3, i%4 == 0)
+	}
+
+	stats := u.snapshot()
+	if stats.TotalCalls != 20 || stats.TotalErrors != 5 || len(stats.Tools) != 2 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	rq := stats.Tools[0]
+	if rq.Tool != "run_query" || rq.Calls != 20 || rq.Errors != 5 || rq.ErrorRate != 0.25 || rq.RowsReturned != 60 {
+		t.Errorf("unexpected run_query stats: %+v", rq)
+	}
+	// Percentiles cover the last 10 calls (11..20ms); the mean covers all 20.
+	if rq.P50Ms != 15 || rq.P95Ms != 20 || rq.AvgMs != 10.5 {
+		t.Errorf("unexpected latencies: p50=%v p95=%v avg=%v", rq.P50Ms, rq.P95Ms, rq.AvgMs)
+	}
+	if rq.LastCallAt == "" {
+		t.Error("expected last_call_at")
+	}
+	if lv := stats.Tools[1]; lv.Tool != "list_views" || lv.Calls != 0 || lv.LastCallAt != "" {
+		t.Errorf("expected a registered tool with no calls, got %+v", lv)
+	}
+}
+
+func TestDispatchToolRecordsUsage(t *testing.T) {
+	u := useUsageRegistry(t, 10)
+
+	ok := dispatchTool("run_query", func(ctx context.Context, req *mcp.CallToolRequest, in struct{}) (*mcp.CallToolResult, QueryResult, error) {
+		return nil, QueryResult{Rows: [][]interface{}{{1}, {2}}}, nil
+	})
+	failing := dispatchTool("describe_table", func(ctx context.Context, req *mcp.CallToolRequest, in struct{}) (*mcp.CallToolResult, struct{}, error) {
+		return nil, struct{}{}, errors.New("no such table")
+	})
+	_, _, _ = ok(context.Background(), nil, struct{}{})
+	_, _, _ = ok(context.Background(), nil, struct{}{})
+	_, _, _ = failing(context.Background(), nil, struct{}{})
+
+	_, out, err := toolUsageStats(context.Background(), nil, UsageStatsInput{Tool: "run_query"})
+	if err != nil {
+		t.Fatalf("usage_stats failed: %v", err)
+	}
+	if len(out.Tools) != 1 || out.Tools[0].Calls != 2 || out.Tools[0].RowsReturned != 4 || out.Tools[0].Errors != 0 {
+		t.Errorf("unexpected run_query stats: %+v", out.Tools)
+	}
+	if out.TotalCalls != 3 || out.TotalErrors != 1 {
+		t.Errorf("unexpected totals: %+v", out)
+	}
+	if _, _, err := toolUsageStats(context.Background(), nil, UsageStatsInput{Tool: "nope"}); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+	if u.snapshot().TotalCalls != 3 {
+		t.Error("expected usage_stats itself not to be counted when called directly")
+	}
+}
+
+func TestHTTPUsageStatsAndPrometheus(t *testing.T) {
+	_, cleanup := setupHTTPTest(t)
+	defer cleanup()
+	u := useUsageRegistry(t, 10)
+	u.record("list_tables", 2*time.Millisecond, 0, false)
+	u.record("list_tables", 4*time.Millisecond, 0, true)
+	// Averages round to whole microseconds; the summary sum must not.
+	u.record("describe_table", time.Nanosecond, 0, false)
+	u.record("describe_table", 3*time.Nanosecond, 0, false)
+
+	w := httptest.NewRecorder()
+	httpUsageStats(w, httptest.NewRequest(http.MethodGet, "/api/stats?tool=list_tables", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data UsageStatsOutput `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Data.Tools) != 1 || resp.Data.Tools[0].Calls != 2 || resp.Data.Tools[0].ErrorRate != 0.5 {
+		t.Errorf("unexpected stats: %+v", resp.Data.Tools)
+	}
+
+	w = httptest.NewRecorder()
+	httpMetricsPrometheus(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE mysql_mcp_tool_calls_total counter",
+		`mysql_mcp_tool_calls_total{tool="list_tables"} 2`,
+		`mysql_mcp_tool_errors_total{tool="list_tables"} 1`,
+		`mysql_mcp_tool_duration_seconds{tool="list_tables",quantile="0.95"} 0.004`,
+		`mysql_mcp_tool_duration_seconds_count{tool="list_tables"} 2`,
+		`mysql_mcp_tool_duration_seconds_sum{tool="list_tables"} 0.006`,
+		`mysql_mcp_tool_duration_seconds_sum{tool="describe_table"} 4e-09`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, body)
+		}
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
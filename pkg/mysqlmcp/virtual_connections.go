@@ -0,0 +1,196 @@
+// pkg/mysqlmcp/virtual_connections.go
+package mysqlmcp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// A virtual connection (virtual_connections in the config file) scopes the
+// server to one tenant on a shared connection: it names the physical
+// connection, a default database and the schema patterns the tenant may
+// access, e.g. tenant_1234_%. Once one is in use, picked by
+// security.virtual_connection (MYSQL_MCP_VIRTUAL_CONNECTION) at startup, every
+// schema check goes through its patterns on top of MYSQL_MCP_ALLOWED_DATABASES,
+// and the server stays locked to it until restart. The lock and the active
+// connection are shared by every client of the process, so use_connection
+// cannot take it at runtime: one caller would lock out all the others, and a
+// per-caller lock would still leave them on the tenant's connection.
+
+var (
+	virtualConnections map[string]config.VirtualConnection
+
+	activeVirtual atomic.Pointer[config.VirtualConnection]
+	virtualMu     sync.Mutex // serializes enterVirtualConnection
+)
+
+func initVirtualConnections(list []config.VirtualConnection) {
+	virtualConnections = make(map[string]config.VirtualConnection, len(list))
+	for _, vc := range list {
+		virtualConnections[vc.Name] = vc
+	}
+	activeVirtual.Store(nil)
+}
+
+// activeVirtualConnection returns the virtual connection the server is locked
+// to, or nil.
+func activeVirtualConnection() *config.VirtualConnection {
+	return activeVirtual.Load()
+}
+
+func lookupVirtualConnection(name string) (config.VirtualConnection, bool) {
+	vc, ok := virtualConnections[name]
+	return vc, ok
+}
+
+// enterVirtualConnection switches to the physical connection of the named
+// virtual connection and locks the server to its schemas. It runs at startup
+// only. Entering the virtual connection already in use is a no-op; any other
+// is refused.
+func enterVirtualConnection(name string) (config.VirtualConnection, error) {
+	virtualMu.Lock()
+	defer virtualMu.Unlock()
+	if cur := activeVirtualConnection(); cur != nil {
+		if cur.Name == name {
+			return *cur, nil
+		}
+		return config.VirtualConnection{}, fmt.Errorf("locked to virtual connection %q", cur.Name)
+	}
+	vc, ok := lookupVirtualConnection(name)
+	if !ok {
+		return config.VirtualConnection{}, fmt.Errorf("unknown virtual connection: %s", name)
+	}
+	// Scope first, so no call runs on the tenant's connection unscoped.
+	activeVirtual.Store(&vc)
+	if vc.Connection != "" {
+		if err := connManager.SetActive(vc.Connection); err != nil {
+			activeVirtual.Store(nil)
+			return config.VirtualConnection{}, err
+		}
+	}
+	return vc, nil
+}
+
+// requireConnectionSwitch refuses a switch to a physical connection while a
+// virtual connection is in use.
+func requireConnectionSwitch(name string) error {
+	if vc := activeVirtualConnection(); vc != nil && name != vc.Name {
+		return fmt.Errorf("locked to virtual connection %q; use_connection %q is not allowed", vc.Name, name)
+	}
+	return nil
+}
+
+func virtualSchemaAllowed(vc *config.VirtualConnection, schema string) bool {
+	schema = strings.TrimSpace(schema)
+	for _, p := range vc.Schemas {
+		if config.MatchSchemaPattern(p, schema) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaPatternLike turns a schema pattern into a lowercase LIKE pattern for
+// ESCAPE '!': % stays a wildcard, _ and ! match themselves.
+func schemaPatternLike(pattern string) string {
+	r := strings.NewReplacer("!", "!!", "_", "!_")
+	return r.Replace(strings.ToLower(pattern))
+}
+
+// applyVirtualDatabase fills an empty "database" field of input with the
+// default database of the active virtual connection, for tools that cannot
+// run without one: those validating it as required and those taking SQL.
+// Optional database filters, such as search_schema's, stay empty and cover
+// every schema of the virtual connection.
+func applyVirtualDatabase(input interface{}) {
+	vc := activeVirtualConnection()
+	if vc == nil || vc.Database == "" {
+		return
+	}
+	rv := reflect.ValueOf(input)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	rv = rv.Elem()
+	var database reflect.Value
+	required, takesSQL := false, false
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		switch strings.Split(f.Tag.Get("json"), ",")[0] {
+		case "database":
+			if rv.Field(i).Kind() == reflect.String {
+				database = rv.Field(i)
+				required = strings.Contains(f.Tag.Get("validate"), "required")
+			}
+		case "sql":
+			takesSQL = true
+		}
+	}
+	if database.IsValid() && strings.TrimSpace(database.String()) == "" && (required || takesSQL) {
+		database.SetString(vc.Database)
+	}
+}
+
+func virtualConnectionInfo(vc config.VirtualConnection, active bool) VirtualConnectionInfo {
+	return VirtualConnectionInfo{
+		Name:        vc.Name,
+		Connection:  vc.Connection,
+		Database:    vc.Database,
+		Schemas:     vc.Schemas,
+		Description: vc.Description,
+		Active:      active,
+	}
+}
+
+// useVirtualConnection is use_connection for a virtual connection name. Only
+// the virtual connection the server was locked to at startup is accepted, as
+// a no-op.
+func useVirtualConnection(name string) (*mcp.CallToolResult, UseConnectionOutput, error) {
+	vc := activeVirtualConnection()
+	if vc == nil {
+		return nil, UseConnectionOutput{}, fmt.Errorf("virtual connection %q can only be entered at startup (MYSQL_MCP_VIRTUAL_CONNECTION / security.virtual_connection), since the lock applies to every client of this server", name)
+	}
+	if vc.Name != name {
+		return nil, UseConnectionOutput{}, fmt.Errorf("locked to virtual connection %q", vc.Name)
+	}
+	_, active := connManager.GetActive()
+	return nil, UseConnectionOutput{
+		Success:           true,
+		Active:            active,
+		Message:           fmt.Sprintf("Already on virtual connection '%s'; this server is limited to schemas %s", vc.Name, strings.Join(vc.Schemas, ", ")),
+		Database:          vc.Database,
+		VirtualConnection: vc.Name,
+	}, nil
+}
+
+// checkVirtualConnections verifies what the config file cannot: connections
+// may come from the environment, and so may the lock.
+func checkVirtualConnections(c *config.Config) error {
+	conns := make(map[string]bool, len(c.Connections))
+	for _, conn := range c.Connections {
+		conns[conn.Name] = true
+	}
+	for _, vc := range c.VirtualConnections {
+		if conns[vc.Name] {
+			return fmt.Errorf("virtual connection '%s' has the name of a connection", vc.Name)
+		}
+		if vc.Connection != "" && !conns[vc.Connection] {
+			return fmt.Errorf("virtual connection '%s' references unknown connection '%s'", vc.Name, vc.Connection)
+		}
+	}
+	if c.VirtualConnection != "" {
+		if _, ok := virtualConnections[c.VirtualConnection]; !ok {
+			return fmt.Errorf("MYSQL_MCP_VIRTUAL_CONNECTION / security.virtual_connection '%s' is not a defined virtual connection", c.VirtualConnection)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// pkg/mysqlmcp/virtual_connections_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var testTenant = config.VirtualConnection{
+	Name:       "tenant_1234",
+	Connection: "mock",
+	Database:   "tenant_1234_app",
+	Schemas:    []string{"tenant_1234_%"},
+}
+
+func TestSchemaPatternLike(t *testing.T) {
+	if got := schemaPatternLike("Tenant_1234_%"); got != "tenant!_1234!_%" {
+		t.Errorf("schemaPatternLike = %q", got)
+	}
+	if got := schemaPatternLike("a!b"); got != "a!!b" {
+		t.Errorf("schemaPatternLike = %q", got)
+	}
+}
+
+func TestVirtualConnectionLock(t *testing.T) {
+	result := setupMockDBFull(t)
+	defer result.cleanup()
+	t.Cleanup(func() { initVirtualConnections(nil) })
+	initVirtualConnections([]config.VirtualConnection{testTenant, {Name: "tenant_5678", Schemas: []string{"tenant_5678_%"}}})
+	ctx := context.Background()
+
+	_, list, err := toolListConnections(ctx, &mcp.CallToolRequest{}, ListConnectionsInput{})
+	if err != nil || len(list.VirtualConnections) != 2 || list.VirtualConnection != "" {
+		t.Fatalf("unexpected list before lock: %+v, %v", list, err)
+	}
+
+	// Any client could lock every other out, so use_connection cannot enter one.
+	if _, _, err := toolUseConnection(ctx, &mcp.CallToolRequest{}, UseConnectionInput{Name: "tenant_1234"}); err == nil ||
+		!strings.Contains(err.Error(), "only be entered at startup") || activeVirtualConnection() != nil {
+		t.Fatalf("expected use_connection to refuse a virtual connection, got %v", err)
+	}
+	if _, err := enterVirtualConnection("tenant_1234"); err != nil {
+		t.Fatalf("startup lock failed: %v", err)
+	}
+	if !databaseAllowed("TENANT_1234_orders") || databaseAllowed("tenant_5678_orders") || databaseAllowed("tenant_12345") {
+		t.Error("schema patterns not applied")
+	}
+	if err := requireAllowedDatabase("tenant_5678_app"); err == nil || !strings.Contains(err.Error(), "outside virtual connection") {
+		t.Errorf("expected outside error, got %v", err)
+	}
+	if err := requireReferencedSchemasInQuery("SELECT * FROM tenant_5678_app.users"); err == nil {
+		t.Error("expected cross-tenant reference to be rejected")
+	}
+
+	// Locked: the same name is a no-op, anything else is refused.
+	_, out, err := toolUseConnection(ctx, &mcp.CallToolRequest{}, UseConnectionInput{Name: "tenant_1234"})
+	if err != nil || !out.Success || out.Active != "mock" || out.VirtualConnection != "tenant_1234" || out.Database != "tenant_1234_app" {
+		t.Errorf("re-entering the locked virtual connection: %+v, %v", out, err)
+	}
+	for _, name := range []string{"tenant_5678", "mock"} {
+		if _, _, err := toolUseConnection(ctx, &mcp.CallToolRequest{}, UseConnectionInput{Name: name}); err == nil || !strings.Contains(err.Error(), "locked") {
+			t.Errorf("use_connection %s: expected lock error, got %v", name, err)
+		}
+	}
+
+	_, list, _ = toolListConnections(ctx, &mcp.CallToolRequest{}, ListConnectionsInput{})
+	if list.VirtualConnection != "tenant_1234" || len(list.VirtualConnections) != 1 || !list.VirtualConnections[0].Active {
+		t.Errorf("unexpected list after lock: %+v", list.VirtualConnections)
+	}
+
+	result.mock.ExpectQuery("SELECT SCHEMA_NAME FROM information_schema.SCHEMATA").
+		WillReturnRows(sqlmock.NewRows([]string{"SCHEMA_NAME"}).
+			AddRow("shared").AddRow("tenant_1234_app").AddRow("tenant_1234_logs").AddRow("tenant_5678_app"))
+	_, dbs, err := toolListDatabases(ctx, &mcp.CallToolRequest{}, ListDatabasesInput{})
+	if err != nil {
+		t.Fatalf("list_databases failed: %v", err)
+	}
+	if len(dbs.Databases) != 2 || dbs.Databases[0].Name != "tenant_1234_app" || dbs.Databases[1].Name != "tenant_1234_logs" {
+		t.Errorf("unexpected databases: %+v", dbs.Databases)
+	}
+	if err := result.mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAllowedSchemaFilter(t *testing.T) {
+	t.Cleanup(func() {
+		initAccessControl(nil)
+		initVirtualConnections(nil)
+	})
+	initAccessControl([]string{"tenant_1234_app", "shared"})
+	initVirtualConnections(nil)
+	activeVirtual.Store(&testTenant)
+
+	filter, args := allowedSchemaFilter("TABLE_SCHEMA")
+	if filter != "LOWER(TABLE_SCHEMA) IN (?,?) AND (LOWER(TABLE_SCHEMA) LIKE ? ESCAPE '!')" {
+		t.Errorf("filter = %q", filter)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"shared", "tenant_1234_app", "tenant!_1234!_%"}) {
+		t.Errorf("args = %v", args)
+	}
+	if databaseAllowed("shared") {
+		t.Error("allowlisted schema outside the virtual connection should be refused")
+	}
+}
+
+func TestApplyVirtualDatabase(t *testing.T) {
+	t.Cleanup(func() { initVirtualConnections(nil) })
+	initVirtualConnections(nil)
+
+	q := RunQueryInput{SQL: "SELECT 1"}
+	applyVirtualDatabase(&q)
+	if q.Database != "" {
+		t.Errorf("filled without a virtual connection: %q", q.Database)
+	}
+
+	activeVirtual.Store(&testTenant)
+	applyVirtualDatabase(&q)
+	tables := ListTablesInput{}
+	applyVirtualDatabase(&tables)
+	search := SearchSchemaInput{Pattern: "%user%"}
+	applyVirtualDatabase(&search)
+	explicit := ListTablesInput{Database: "tenant_1234_logs"}
+	applyVirtualDatabase(&explicit)
+	if q.Database != "tenant_1234_app" || tables.Database != "tenant_1234_app" {
+		t.Errorf("default database not applied: run_query %q, list_tables %q", q.Database, tables.Database)
+	}
+	if search.Database != "" || explicit.Database != "tenant_1234_logs" {
+		t.Errorf("optional or explicit database changed: search %q, explicit %q", search.Database, explicit.Database)
+	}
+}
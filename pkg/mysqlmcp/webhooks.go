@@ -0,0 +1,256 @@
+// pkg/mysqlmcp/webhooks.go
+package mysqlmcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// Webhooks POST a JSON event to each configured URL when the validator blocks
+// a query, a query tool call runs longer than webhooks.slow_query_seconds or
+// a connection turns unhealthy (its circuit opens or keepalive pings fail),
+// so operators get Slack or pager alerts without scraping logs. Delivery runs
+// in the background with retries; a full queue drops events rather than slow
+// down tool calls.
+
+const (
+	webhookQueueSize = 256
+	webhookAttempts  = 3
+	webhookTimeout   = 10 * time.Second
+	// webhookUnhealthyRepeat is how long connection_unhealthy stays quiet for
+	// a connection after it was sent, so a dead server does not page every
+	// keepalive interval.
+	webhookUnhealthyRepeat = 5 * time.Minute
+)
+
+// WebhookEvent is the JSON body POSTed to webhooks. Text is a one-line
+// summary, which Slack incoming webhooks show as the message.
+type WebhookEvent struct {
+	Event      string `json:"event"`
+	Timestamp  string `json:"timestamp"`
+	Text       string `json:"text"`
+	RequestID  string `json:"request_id,omitempty"`
+	Tool       string `json:"tool,omitempty"`
+	Connection string `json:"connection,omitempty"`
+	Database   string `json:"database,omitempty"`
+	Query      string `json:"query,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	Schema *SchemaChange `json:"schema,omitempty"` // schema_changed only
+}
+
+type webhookNotifier struct {
+	hooks   []config.Webhook
+	client  *http.Client
+	backoff time.Duration // wait before the first retry; doubled for each further one
+	queue   chan WebhookEvent
+
+	mu            sync.Mutex
+	lastUnhealthy map[string]time.Time // connection -> last connection_unhealthy sent
+}
+
+// webhooks is nil unless webhooks are configured.
+var webhooks *webhookNotifier
+
+func initWebhooks(c *config.Config) {
+	if len(c.Webhooks) == 0 {
+		webhooks = nil
+		return
+	}
+	webhooks = &webhookNotifier{
+		hooks:         c.Webhooks,
+		client:        &http.Client{Timeout: webhookTimeout},
+		backoff:       time.Second,
+		queue:         make(chan WebhookEvent, webhookQueueSize),
+		lastUnhealthy: map[string]time.Time{},
+	}
+}
+
+// run delivers queued events until ctx is canceled.
+func (n *webhookNotifier) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-n.queue:
+			n.deliver(ctx, ev)
+		}
+	}
+}
+
+// notify queues ev for delivery without waiting.
+func (n *webhookNotifier) notify(ev WebhookEvent) {
+	ev.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	select {
+	case n.queue <- ev:
+	default:
+		serverLog.Warn("webhook queue full; event dropped", map[string]interface{}{"event": ev.Event})
+	}
+}
+
+// deliver POSTs ev to every webhook subscribed to its event.
+func (n *webhookNotifier) deliver(ctx context.Context, ev WebhookEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	for _, h := range n.hooks {
+		if len(h.Events) > 0 && !slices.Contains(h.Events, ev.Event) {
+			continue
+		}
+		if err := n.post(ctx, h, ev.Event, body); err != nil {
+			serverLog.Warn("webhook delivery failed", map[string]interface{}{
+				"event": ev.Event,
+				"url":   config.MaskWebhookURL(h.URL),
+				"error": err.Error(),
+			})
+		}
+	}
+}
+
+// post sends body to h, retrying network errors, 429 and 5xx responses with
+// exponential backoff.
+func (n *webhookNotifier) post(ctx context.Context, h config.Webhook, event string, body []byte) error {
+	wait := n.backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		if retry, err = n.postOnce(ctx, h, event, body); err == nil || !retry || attempt == webhookAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+func (n *webhookNotifier) postOnce(ctx context.Context, h config.Webhook, event string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mysql-mcp-server/"+Version)
+	req.Header.Set("X-MCP-Event", event)
+	if h.Secret != "" {
+		req.Header.Set("X-MCP-Signature", "sha256="+webhookSignature(h.Secret, body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook answered %s", resp.Status)
+}
+
+// webhookSignature is the hex HMAC-SHA256 of body keyed with secret, sent as
+// X-MCP-Signature: sha256=<signature>.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// notifyToolWebhooks sends query_blocked when err is a validator rejection
+// and query_slow when a query tool call took longer than the threshold.
+func notifyToolWebhooks(ctx context.Context, tool string, input any, elapsed time.Duration, err error) {
+	n := webhooks
+	if n == nil {
+		return
+	}
+	ev := WebhookEvent{
+		Tool:       tool,
+		RequestID:  requestIDFrom(ctx),
+		Connection: activeConnectionName(),
+		Database:   inputString(input, "database"),
+		Query:      loggedSQL(inputString(input, "sql"), 500),
+	}
+	switch {
+	case isValidationError(err):
+		ev.Event, ev.Error = config.WebhookEventQueryBlocked, err.Error()
+		ev.Text = fmt.Sprintf("mysql-mcp-server: %s query blocked by the SQL validator on %s: %s", tool, ev.Connection, err)
+	case cfg != nil && cfg.WebhookSlowQuery > 0 && elapsed > cfg.WebhookSlowQuery && toolQueryWeight(tool) > 0:
+		ev.Event, ev.DurationMs = config.WebhookEventQuerySlow, elapsed.Milliseconds()
+		ev.Text = fmt.Sprintf("mysql-mcp-server: %s ran for %s on %s", tool, elapsed.Round(time.Millisecond), ev.Connection)
+		if err != nil {
+			ev.Error = err.Error()
+		}
+	default:
+		return
+	}
+	n.notify(ev)
+}
+
+// notifyConnectionUnhealthy sends connection_unhealthy for conn, at most once
+// per webhookUnhealthyRepeat.
+func notifyConnectionUnhealthy(conn, reason string) {
+	n := webhooks
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	now := time.Now()
+	if last, ok := n.lastUnhealthy[conn]; ok && now.Sub(last) < webhookUnhealthyRepeat {
+		n.mu.Unlock()
+		return
+	}
+	n.lastUnhealthy[conn] = now
+	n.mu.Unlock()
+	n.notify(WebhookEvent{
+		Event:      config.WebhookEventConnectionUnhealthy,
+		Connection: conn,
+		Error:      reason,
+		Text:       fmt.Sprintf("mysql-mcp-server: connection %s is unhealthy: %s", conn, reason),
+	})
+}
+
+// isValidationError reports whether err is a rejection by the SQL validator.
+func isValidationError(err error) bool {
+	var parserErr *util.ParserValidationError
+	var regexErr *util.SQLValidationError
+	return errors.As(err, &parserErr) || errors.As(err, &regexErr)
+}
+
+// inputString returns the string field of a tool input struct whose JSON
+// name is name, or "".
+func inputString(input any, name string) string {
+	rv := reflect.ValueOf(input)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		if f.IsExported() && strings.Split(f.Tag.Get("json"), ",")[0] == name && rv.Field(i).Kind() == reflect.String {
+			return rv.Field(i).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+// pkg/mysqlmcp/webhooks_test.go
+package mysqlmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/askdba/mysql-mcp-server/internal/config"
+	"github.com/askdba/mysql-mcp-server/internal/util"
+)
+
+// withWebhooks installs a notifier for hooks whose queue the test drains.
+func withWebhooks(t *testing.T, hooks ...config.Webhook) *webhookNotifier {
+	old := webhooks
+	initWebhooks(&config.Config{Webhooks: hooks})
+	webhooks.backoff = time.Millisecond
+	t.Cleanup(func() { webhooks = old })
+	return webhooks
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	var got []*http.Request
+	var bodies [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		got, bodies = append(got, r), append(bodies, body)
+	}))
+	defer srv.Close()
+
+	n := withWebhooks(t,
+		config.Webhook{URL: srv.URL + "/alerts", Secret: "s3cret"},
+		config.Webhook{URL: srv.URL + "/slow", Events: []string{config.WebhookEventQuerySlow}},
+	)
+	n.deliver(context.Background(), WebhookEvent{Event: config.WebhookEventQueryBlocked, Tool: "run_query", Text: "blocked"})
+
+	if calls != 2 || len(got) != 1 {
+		t.Fatalf("expected one retried delivery, got %d calls and %d deliveries", calls, len(got))
+	}
+	if got[0].URL.Path != "/alerts" || got[0].Header.Get("X-MCP-Event") != config.WebhookEventQueryBlocked {
+		t.Errorf("unexpected request: %s %v", got[0].URL.Path, got[0].Header)
+	}
+	if sig := got[0].Header.Get("X-MCP-Signature"); sig != "sha256="+webhookSignature("s3cret", bodies[0]) {
+		t.Errorf("unexpected signature %q", sig)
+	}
+	var ev WebhookEvent
+	if err := json.Unmarshal(bodies[0], &ev); err != nil || ev.Tool != "run_query" || ev.Text != "blocked" {
+		t.Errorf("unexpected body %s: %v", bodies[0], err)
+	}
+}
+
+func TestNotifyToolWebhooks(t *testing.T) {
+	n := withWebhooks(t, config.Webhook{URL: "https://hooks.example.com/x"})
+	oldCfg := cfg
+	cfg = &config.Config{WebhookSlowQuery: time.Second}
+	defer func() { cfg = oldCfg }()
+	ctx := context.Background()
+
+	input := RunQueryInput{SQL: "DROP TABLE users", Database: "app"}
+	err := fmt.Errorf("query validation failed: %w", util.ValidateSQLCombined(input.SQL))
+	notifyToolWebhooks(ctx, "run_query", &input, time.Millisecond, err)
+	notifyToolWebhooks(ctx, "run_query", &input, 2*time.Second, nil)
+	notifyToolWebhooks(ctx, "list_connections", &ListConnectionsInput{}, 2*time.Second, nil)
+	notifyToolWebhooks(ctx, "run_query", &input, time.Millisecond, fmt.Errorf("unknown column"))
+
+	if len(n.queue) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(n.queue))
+	}
+	if ev := <-n.queue; ev.Event != config.WebhookEventQueryBlocked || ev.Query != "DROP TABLE users" || ev.Database != "app" || ev.Error == "" {
+		t.Errorf("unexpected blocked event: %+v", ev)
+	}
+	if ev := <-n.queue; ev.Event != config.WebhookEventQuerySlow || ev.DurationMs != 2000 {
+		t.Errorf("unexpected slow event: %+v", ev)
+	}
+
+	notifyConnectionUnhealthy("prod", "circuit opened")
+	notifyConnectionUnhealthy("prod", "keepalive failed")
+	if len(n.queue) != 1 {
+		t.Errorf("expected repeated connection_unhealthy to be suppressed, got %d events", len(n.queue))
+	}
+}